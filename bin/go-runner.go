@@ -12,15 +12,23 @@ import (
 	// _ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 	// _ "k8s.io/client-go/plugin/pkg/client/auth/openstack"
 
+	azureInstanceStop "github.com/litmuschaos/litmus-go/experiments/azure/azure-instance-stop/experiment"
+	cassandraNodeDecommission "github.com/litmuschaos/litmus-go/experiments/cassandra/node-decommission/experiment"
 	cassandraPodDelete "github.com/litmuschaos/litmus-go/experiments/cassandra/pod-delete/experiment"
+	gcpVMInstanceStop "github.com/litmuschaos/litmus-go/experiments/gcp/gcp-vm-instance-stop/experiment"
 	containerKill "github.com/litmuschaos/litmus-go/experiments/generic/container-kill/experiment"
 	diskFill "github.com/litmuschaos/litmus-go/experiments/generic/disk-fill/experiment"
 	kubeletServiceKill "github.com/litmuschaos/litmus-go/experiments/generic/kubelet-service-kill/experiment"
 	nodeCPUHog "github.com/litmuschaos/litmus-go/experiments/generic/node-cpu-hog/experiment"
+	nodeDiskFill "github.com/litmuschaos/litmus-go/experiments/generic/node-disk-fill/experiment"
 	nodeDrain "github.com/litmuschaos/litmus-go/experiments/generic/node-drain/experiment"
 	nodeIOStress "github.com/litmuschaos/litmus-go/experiments/generic/node-io-stress/experiment"
 	nodeMemoryHog "github.com/litmuschaos/litmus-go/experiments/generic/node-memory-hog/experiment"
+	nodeNetworkLatency "github.com/litmuschaos/litmus-go/experiments/generic/node-network-latency/experiment"
+	nodeNetworkLoss "github.com/litmuschaos/litmus-go/experiments/generic/node-network-loss/experiment"
+	nodeNetworkPartition "github.com/litmuschaos/litmus-go/experiments/generic/node-network-partition/experiment"
 	nodeRestart "github.com/litmuschaos/litmus-go/experiments/generic/node-restart/experiment"
+	nodeServiceKill "github.com/litmuschaos/litmus-go/experiments/generic/node-service-kill/experiment"
 	nodeTaint "github.com/litmuschaos/litmus-go/experiments/generic/node-taint/experiment"
 	podAutoscaler "github.com/litmuschaos/litmus-go/experiments/generic/pod-autoscaler/experiment"
 	podCPUHog "github.com/litmuschaos/litmus-go/experiments/generic/pod-cpu-hog/experiment"
@@ -32,9 +40,13 @@ import (
 	podNetworkDuplication "github.com/litmuschaos/litmus-go/experiments/generic/pod-network-duplication/experiment"
 	podNetworkLatency "github.com/litmuschaos/litmus-go/experiments/generic/pod-network-latency/experiment"
 	podNetworkLoss "github.com/litmuschaos/litmus-go/experiments/generic/pod-network-loss/experiment"
+	podTimeChaos "github.com/litmuschaos/litmus-go/experiments/generic/pod-time-chaos/experiment"
 	kafkaBrokerPodFailure "github.com/litmuschaos/litmus-go/experiments/kafka/kafka-broker-pod-failure/experiment"
+	azOutage "github.com/litmuschaos/litmus-go/experiments/kube-aws/az-outage/experiment"
+	ebsLossByTag "github.com/litmuschaos/litmus-go/experiments/kube-aws/ebs-loss-by-tag/experiment"
 	ebsLoss "github.com/litmuschaos/litmus-go/experiments/kube-aws/ebs-loss/experiment"
 	ec2TerminateByID "github.com/litmuschaos/litmus-go/experiments/kube-aws/ec2-terminate-by-id/experiment"
+	ec2TerminateByNode "github.com/litmuschaos/litmus-go/experiments/kube-aws/ec2-terminate-by-node/experiment"
 	ec2TerminateByTag "github.com/litmuschaos/litmus-go/experiments/kube-aws/ec2-terminate-by-tag/experiment"
 
 	"github.com/litmuschaos/litmus-go/pkg/clients"
@@ -78,12 +90,22 @@ func main() {
 		kubeletServiceKill.KubeletServiceKill(clients)
 	case "node-cpu-hog":
 		nodeCPUHog.NodeCPUHog(clients)
+	case "node-disk-fill":
+		nodeDiskFill.NodeDiskFill(clients)
 	case "node-drain":
 		nodeDrain.NodeDrain(clients)
 	case "node-io-stress":
 		nodeIOStress.NodeIOStress(clients)
 	case "node-memory-hog":
 		nodeMemoryHog.NodeMemoryHog(clients)
+	case "node-network-latency":
+		nodeNetworkLatency.NodeNetworkLatency(clients)
+	case "node-network-loss":
+		nodeNetworkLoss.NodeNetworkLoss(clients)
+	case "node-network-partition":
+		nodeNetworkPartition.NodeNetworkPartition(clients)
+	case "node-service-kill":
+		nodeServiceKill.NodeServiceKill(clients)
 	case "node-taint":
 		nodeTaint.NodeTaint(clients)
 	case "pod-autoscaler":
@@ -106,16 +128,30 @@ func main() {
 		podNetworkLoss.PodNetworkLoss(clients)
 	case "cassandra-pod-delete":
 		cassandraPodDelete.CasssandraPodDelete(clients)
+	case "cassandra-node-decommission":
+		cassandraNodeDecommission.CassandraNodeDecommission(clients)
 	case "ec2-terminate-by-id":
 		ec2TerminateByID.EC2TerminateByID(clients)
 	case "ec2-terminate-by-tag":
 		ec2TerminateByTag.EC2TerminateByTag(clients)
+	case "ec2-terminate-by-node":
+		ec2TerminateByNode.EC2TerminateByNode(clients)
 	case "ebs-loss":
 		ebsLoss.EBSLoss(clients)
+	case "ebs-loss-by-tag":
+		ebsLossByTag.EBSLossByTag(clients)
+	case "az-outage":
+		azOutage.AZOutage(clients)
+	case "gcp-vm-instance-stop":
+		gcpVMInstanceStop.VMInstanceStop(clients)
+	case "azure-instance-stop":
+		azureInstanceStop.AzureInstanceStop(clients)
 	case "node-restart":
 		nodeRestart.NodeRestart(clients)
 	case "pod-dns-chaos":
 		podDNSChaos.PodDNSExperiment(clients)
+	case "pod-time-chaos":
+		podTimeChaos.PodTimeChaos(clients)
 	default:
 		log.Errorf("Unsupported -name %v, please provide the correct value of -name args", *experimentName)
 		return
@@ -0,0 +1,454 @@
+package lib
+
+import (
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	awslib "github.com/litmuschaos/litmus-go/pkg/cloud/aws"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/kube-aws/az-outage/types"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/probe"
+	"github.com/litmuschaos/litmus-go/pkg/result"
+	"github.com/litmuschaos/litmus-go/pkg/status"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/pkg/errors"
+)
+
+var abort chan os.Signal
+
+// isolation contains the original network state of the isolated zone, used to restore it after the chaos
+// it is guarded by the mutex, as the abortWatcher restores it while the chaos is being injected
+type isolation struct {
+	sync.Mutex
+	// interfaces are the isolated network interfaces along with their original security groups
+	interfaces []cloud.NetworkInterface
+	// associations are the current associations of the isolated subnets along with their original network acl
+	associations []cloud.NetworkACLAssociation
+}
+
+// PrepareAZOutage contains the prepration and injection steps for the experiment
+func PrepareAZOutage(experimentsDetails *experimentTypes.ExperimentDetails, targets []awslib.TargetNode, clients clients.ClientSets, provider cloud.NetworkProvider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	// abort channel is used to transmit signal notifications.
+	abort = make(chan os.Signal, 1)
+	// Catch and relay certain signal(s) to abort channel.
+	signal.Notify(abort, os.Interrupt, syscall.SIGTERM)
+
+	//Waiting for the ramp time before chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time before injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+
+	if len(targets) == 0 {
+		return errors.Errorf("no target instance found in the %v zone", experimentsDetails.Zone)
+	}
+
+	if experimentsDetails.EngineName != "" {
+		msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on " + experimentsDetails.Zone + " zone"
+		types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+		events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+	}
+
+	var err error
+	switch strings.ToLower(experimentsDetails.ChaosMode) {
+	case "stop":
+		err = InjectInstanceStop(experimentsDetails, targets, clients, provider, resultDetails, eventsDetails, chaosDetails)
+	case "security-group":
+		err = InjectSecurityGroupIsolation(experimentsDetails, targets, clients, provider, resultDetails, eventsDetails, chaosDetails)
+	case "network-acl":
+		err = InjectNetworkACLIsolation(experimentsDetails, targets, clients, provider, resultDetails, eventsDetails, chaosDetails)
+	default:
+		return errors.Errorf("%v chaos mode is not supported, provide stop, security-group or network-acl", experimentsDetails.ChaosMode)
+	}
+	if err != nil {
+		return err
+	}
+
+	//Waiting for the ramp time after chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time after injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+	return nil
+}
+
+// InjectInstanceStop stops all the target instances of the zone for the chaos duration and starts them back
+// the instances of a managed nodegroup get terminated, so they are replaced instead of started back
+func InjectInstanceStop(experimentsDetails *experimentTypes.ExperimentDetails, targets []awslib.TargetNode, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	// watching for the abort signal and revert the chaos
+	go abortWatcher(experimentsDetails, func() error { return startInstances(experimentsDetails, targets, provider) }, clients, resultDetails, chaosDetails, eventsDetails)
+
+	if err := stopInstances(experimentsDetails, targets, clients, provider); err != nil {
+		if restoreErr := startInstances(experimentsDetails, targets, provider); restoreErr != nil {
+			log.Errorf("unable to start the instances, err: %v", restoreErr)
+		}
+		return err
+	}
+
+	if err := waitForChaosDuration(experimentsDetails, clients, resultDetails, eventsDetails, chaosDetails); err != nil {
+		if restoreErr := startInstances(experimentsDetails, targets, provider); restoreErr != nil {
+			log.Errorf("unable to start the instances, err: %v", restoreErr)
+		}
+		return err
+	}
+
+	return startInstances(experimentsDetails, targets, provider)
+}
+
+// InjectSecurityGroupIsolation swaps the security groups of all the network interfaces of the target instances
+// with the blackhole security group for the chaos duration and restores the original security groups
+func InjectSecurityGroupIsolation(experimentsDetails *experimentTypes.ExperimentDetails, targets []awslib.TargetNode, clients clients.ClientSets, provider cloud.NetworkProvider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	if experimentsDetails.BlackholeSecurityGroupID == "" {
+		return errors.Errorf("please provide the BLACKHOLE_SECURITY_GROUP_ID for the security-group chaos mode")
+	}
+
+	interfaces, err := getNetworkInterfaces(targets, provider)
+	if err != nil {
+		return err
+	}
+
+	state := &isolation{}
+	// watching for the abort signal and revert the chaos
+	go abortWatcher(experimentsDetails, func() error { return restoreSecurityGroups(state, provider) }, clients, resultDetails, chaosDetails, eventsDetails)
+
+	for _, networkInterface := range interfaces {
+		log.Infof("[Chaos]: Isolating the network interface '%v' with the blackhole security group", networkInterface.ID)
+		// the interface is recorded under the same lock, so the abortWatcher restores every isolated interface
+		state.Lock()
+		err := provider.SetSecurityGroups(networkInterface.ID, []string{experimentsDetails.BlackholeSecurityGroupID})
+		if err == nil {
+			state.interfaces = append(state.interfaces, networkInterface)
+		}
+		state.Unlock()
+		if err != nil {
+			if restoreErr := restoreSecurityGroups(state, provider); restoreErr != nil {
+				log.Errorf("unable to restore the security groups, err: %v", restoreErr)
+			}
+			return errors.Errorf("unable to isolate the %v network interface, err: %v", networkInterface.ID, err)
+		}
+	}
+
+	if err := waitForChaosDuration(experimentsDetails, clients, resultDetails, eventsDetails, chaosDetails); err != nil {
+		if restoreErr := restoreSecurityGroups(state, provider); restoreErr != nil {
+			log.Errorf("unable to restore the security groups, err: %v", restoreErr)
+		}
+		return err
+	}
+
+	return restoreSecurityGroups(state, provider)
+}
+
+// InjectNetworkACLIsolation associates the subnets of the target instances with the blackhole network acl
+// for the chaos duration and restores the original network acls
+func InjectNetworkACLIsolation(experimentsDetails *experimentTypes.ExperimentDetails, targets []awslib.TargetNode, clients clients.ClientSets, provider cloud.NetworkProvider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	if experimentsDetails.BlackholeNetworkACLID == "" {
+		return errors.Errorf("please provide the BLACKHOLE_NETWORK_ACL_ID for the network-acl chaos mode")
+	}
+
+	interfaces, err := getNetworkInterfaces(targets, provider)
+	if err != nil {
+		return err
+	}
+	var subnets []string
+	seen := map[string]bool{}
+	for _, networkInterface := range interfaces {
+		if !seen[networkInterface.SubnetID] {
+			seen[networkInterface.SubnetID] = true
+			subnets = append(subnets, networkInterface.SubnetID)
+		}
+	}
+	log.Infof("[Info]: The subnets under chaos are: %v", subnets)
+
+	state := &isolation{}
+	// watching for the abort signal and revert the chaos
+	go abortWatcher(experimentsDetails, func() error { return restoreNetworkACLs(state, provider) }, clients, resultDetails, chaosDetails, eventsDetails)
+
+	for _, subnetID := range subnets {
+		association, err := provider.GetNetworkACLAssociation(subnetID)
+		if err != nil {
+			if restoreErr := restoreNetworkACLs(state, provider); restoreErr != nil {
+				log.Errorf("unable to restore the network acls, err: %v", restoreErr)
+			}
+			return errors.Errorf("unable to get the network acl of %v subnet, err: %v", subnetID, err)
+		}
+
+		log.Infof("[Chaos]: Isolating the subnet '%v' with the blackhole network acl", subnetID)
+		// the association is recorded under the same lock, so the abortWatcher restores every isolated subnet
+		state.Lock()
+		newAssociationID, err := provider.ReplaceNetworkACLAssociation(association.ID, experimentsDetails.BlackholeNetworkACLID)
+		if err == nil {
+			association.ID = newAssociationID
+			state.associations = append(state.associations, association)
+		}
+		state.Unlock()
+		if err != nil {
+			if restoreErr := restoreNetworkACLs(state, provider); restoreErr != nil {
+				log.Errorf("unable to restore the network acls, err: %v", restoreErr)
+			}
+			return errors.Errorf("unable to isolate the %v subnet, err: %v", subnetID, err)
+		}
+	}
+
+	if err := waitForChaosDuration(experimentsDetails, clients, resultDetails, eventsDetails, chaosDetails); err != nil {
+		if restoreErr := restoreNetworkACLs(state, provider); restoreErr != nil {
+			log.Errorf("unable to restore the network acls, err: %v", restoreErr)
+		}
+		return err
+	}
+
+	return restoreNetworkACLs(state, provider)
+}
+
+// waitForChaosDuration runs the probes and waits for the chaos duration
+func waitForChaosDuration(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	// run the probes during chaos
+	if len(resultDetails.ProbeDetails) != 0 {
+		if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+			return err
+		}
+	}
+
+	log.Infof("[Chaos]: Waiting for %vs", experimentsDetails.ChaosDuration)
+	common.WaitForDuration(experimentsDetails.ChaosDuration)
+	log.Info("[Chaos]: Stopping the experiment")
+	return nil
+}
+
+// stopInstances stops all the target instances and waits for them and their nodes to go down
+func stopInstances(experimentsDetails *experimentTypes.ExperimentDetails, targets []awslib.TargetNode, clients clients.ClientSets, provider cloud.Provider) error {
+
+	//PowerOff the instances
+	for _, target := range targets {
+		log.Infof("[Chaos]: Stoping the EC2 instance '%v'", target.InstanceID)
+		if err := provider.StopInstance(target.InstanceID); err != nil {
+			return errors.Errorf("ec2 instance failed to stop, err: %v", err)
+		}
+	}
+
+	for _, target := range targets {
+		//Wait for ec2 instance to completely stop
+		log.Infof("[Wait]: Wait for EC2 instance '%v' to come in stopped state", target.InstanceID)
+		if err := cloud.WaitForInstanceDown(provider, experimentsDetails.Timeout, experimentsDetails.Delay, experimentsDetails.ManagedNodegroup, target.InstanceID); err != nil {
+			return errors.Errorf("unable to stop the ec2 instance, err: %v", err)
+		}
+
+		// the node object of a terminated managed nodegroup instance is removed, so its status is not checked
+		if target.NodeName != "" && experimentsDetails.ManagedNodegroup != "enable" {
+			log.Infof("[Wait]: Wait for %v node to get in NotReady state", target.NodeName)
+			if err := status.CheckNodeNotReadyState(target.NodeName, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
+				return errors.Errorf("%v node is not in NotReady state, err: %v", target.NodeName, err)
+			}
+		}
+	}
+	return nil
+}
+
+// startInstances starts back all the target instances and waits for them and their nodes to be ready
+func startInstances(experimentsDetails *experimentTypes.ExperimentDetails, targets []awslib.TargetNode, provider cloud.Provider) error {
+
+	// the terminated instances of a managed nodegroup can't be started, they are replaced by the nodegroup
+	if experimentsDetails.ManagedNodegroup == "enable" {
+		log.Info("[Chaos]: All the target instances of the managed nodegroup are terminated")
+		return nil
+	}
+
+	for _, target := range targets {
+		log.Infof("[Chaos]: Starting back the EC2 instance '%v'", target.InstanceID)
+		if err := provider.StartInstance(target.InstanceID); err != nil {
+			return errors.Errorf("ec2 instance failed to start, err: %v", err)
+		}
+	}
+
+	for _, target := range targets {
+		//Wait for ec2 instance to come in running state
+		log.Infof("[Wait]: Wait for EC2 instance '%v' to get in running state", target.InstanceID)
+		if err := cloud.WaitForInstanceUp(provider, experimentsDetails.Timeout, experimentsDetails.Delay, target.InstanceID); err != nil {
+			return errors.Errorf("unable to start the ec2 instance, err: %v", err)
+		}
+	}
+	return nil
+}
+
+// getNetworkInterfaces returns the network interfaces of all the target instances along with their current security groups
+func getNetworkInterfaces(targets []awslib.TargetNode, provider cloud.Provider) ([]cloud.NetworkInterface, error) {
+
+	var instanceIDs []string
+	for _, target := range targets {
+		instanceIDs = append(instanceIDs, target.InstanceID)
+	}
+	instances, err := provider.DescribeInstances(instanceIDs...)
+	if err != nil {
+		return nil, errors.Errorf("fail to get the target instances, err: %v", err)
+	}
+
+	var interfaces []cloud.NetworkInterface
+	for _, instance := range instances {
+		if len(instance.NetworkInterfaces) == 0 {
+			return nil, errors.Errorf("no network interface found for the ec2 instance '%v'", instance.ID)
+		}
+		interfaces = append(interfaces, instance.NetworkInterfaces...)
+	}
+	return interfaces, nil
+}
+
+// restoreSecurityGroups restores the original security groups of the isolated network interfaces
+// it tries all the interfaces and returns the combined error of the failed ones
+func restoreSecurityGroups(state *isolation, provider cloud.NetworkProvider) error {
+
+	state.Lock()
+	defer state.Unlock()
+
+	var failed []string
+	for _, networkInterface := range state.interfaces {
+		log.Infof("[Chaos]: Restoring the security groups of network interface '%v'", networkInterface.ID)
+		if err := provider.SetSecurityGroups(networkInterface.ID, networkInterface.SecurityGroups); err != nil {
+			failed = append(failed, networkInterface.ID+": "+err.Error())
+		}
+	}
+	if len(failed) != 0 {
+		return errors.Errorf("unable to restore the security groups of the network interfaces, %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// restoreNetworkACLs restores the original network acls of the isolated subnets
+// it tries all the subnets and returns the combined error of the failed ones
+func restoreNetworkACLs(state *isolation, provider cloud.NetworkProvider) error {
+
+	state.Lock()
+	defer state.Unlock()
+
+	var failed []string
+	for i, association := range state.associations {
+		log.Infof("[Chaos]: Restoring the network acl '%v' of subnet '%v'", association.NetworkACLID, association.SubnetID)
+		newAssociationID, err := provider.ReplaceNetworkACLAssociation(association.ID, association.NetworkACLID)
+		if err != nil {
+			failed = append(failed, association.SubnetID+": "+err.Error())
+			continue
+		}
+		// the association id changes on every replacement, it is tracked to keep the restore repeatable
+		state.associations[i].ID = newAssociationID
+	}
+	if len(failed) != 0 {
+		return errors.Errorf("unable to restore the network acls of the subnets, %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// InstanceStatusCheckByZone is used to check the instance status of all the target instances of the zone
+func InstanceStatusCheckByZone(targets []awslib.TargetNode, provider cloud.Provider) error {
+
+	log.Infof("[Info]: The instances under chaos(IUC) are: %v", targets)
+	for _, target := range targets {
+		instanceState, err := cloud.GetInstanceStatus(provider, target.InstanceID)
+		if err != nil {
+			return err
+		}
+		if instanceState != cloud.InstanceRunning {
+			return errors.Errorf("failed to get the ec2 instance '%v' status as running", target.InstanceID)
+		}
+	}
+	return nil
+}
+
+// CheckRunnerZone verifies that the experiment pod is not scheduled in the target zone
+// the isolation of the zone cuts the experiment pod off from the aws and kube apis, so it can't revert the chaos
+func CheckRunnerZone(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) error {
+
+	runnerZone, err := awslib.GetPodZone(experimentsDetails.ChaosPodName, experimentsDetails.ChaosNamespace, clients)
+	if err != nil {
+		return err
+	}
+	if runnerZone == "" {
+		return errors.Errorf("unable to find the zone of the %v experiment pod, the node has no topology label", experimentsDetails.ChaosPodName)
+	}
+	if runnerZone == experimentsDetails.Zone {
+		return errors.Errorf("the experiment pod %v is running in the target zone %v, please schedule it outside the zone using a nodeSelector", experimentsDetails.ChaosPodName, experimentsDetails.Zone)
+	}
+	log.Infof("[Info]: The experiment pod is running in the %v zone", runnerZone)
+	return nil
+}
+
+// GetClusterNodeTargets returns the target instances which are cluster nodes
+func GetClusterNodeTargets(targets []awslib.TargetNode) []awslib.TargetNode {
+
+	var nodes []awslib.TargetNode
+	for _, target := range targets {
+		if target.NodeName != "" {
+			nodes = append(nodes, target)
+		}
+	}
+	return nodes
+}
+
+// PostChaosZoneStatusCheck verifies that the nodes of the zone re-registered as Ready
+// or their replacement nodes joined the cluster, if the instances belong to a managed nodegroup
+func PostChaosZoneStatusCheck(experimentsDetails *experimentTypes.ExperimentDetails, targets []awslib.TargetNode, clients clients.ClientSets) error {
+
+	targetNodes := GetClusterNodeTargets(targets)
+	if len(targetNodes) == 0 {
+		return nil
+	}
+
+	if experimentsDetails.ManagedNodegroup == "enable" && strings.ToLower(experimentsDetails.ChaosMode) == "stop" {
+		return awslib.WaitForReplacementNodes(targetNodes, common.NodeSelector{NodeLabel: experimentsDetails.NodeLabel}, experimentsDetails.ActiveNodes, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
+	}
+
+	var nodeNames []string
+	for _, target := range targetNodes {
+		nodeNames = append(nodeNames, target.NodeName)
+	}
+	return status.CheckNodeStatus(strings.Join(nodeNames, ","), experimentsDetails.Timeout, experimentsDetails.Delay, clients)
+}
+
+// abortWatcher continuosly watch for the abort signals and restores the zone using the given revert function
+func abortWatcher(experimentsDetails *experimentTypes.ExperimentDetails, revert func() error, clients clients.ClientSets, resultDetails *types.ResultDetails, chaosDetails *types.ChaosDetails, eventsDetails *types.EventDetails) {
+
+	for {
+		select {
+		case <-abort:
+			log.Info("[Chaos]: Killing process started because of terminated signal received")
+			log.Info("Chaos Revert Started")
+			// retry thrice for the chaos revert
+			retry := 3
+			for retry > 0 {
+				err := revert()
+				if err == nil {
+					break
+				}
+				log.Errorf("Unable to restore the %v zone, err: %v", experimentsDetails.Zone, err)
+				retry--
+				time.Sleep(1 * time.Second)
+			}
+			log.Info("Chaos Revert Completed")
+
+			// updating the chaosresult after stopped
+			failStep := "Chaos injection stopped!"
+			types.SetResultAfterCompletion(resultDetails, "Stopped", "Stopped", failStep)
+			result.ChaosResult(chaosDetails, clients, resultDetails, "EOT")
+
+			// generating summary event in chaosengine
+			msg := experimentsDetails.ExperimentName + " experiment has been aborted"
+			types.SetEngineEventAttributes(eventsDetails, types.Summary, msg, "Warning", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+
+			// generating summary event in chaosresult
+			types.SetResultEventAttributes(eventsDetails, types.StoppedVerdict, msg, "Warning", resultDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosResult")
+			os.Exit(1)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package lib
+
+import (
+	"strings"
+
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/azure/azure-instance-stop/types"
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/pkg/errors"
+)
+
+// instanceKind is the kind of the target instances used in the logs and events
+const instanceKind = "azure"
+
+// PrepareAzureInstanceStop contains the prepration and injection steps for the experiment
+// it runs the instance stop chaos shared by the cloud vm experiments
+func PrepareAzureInstanceStop(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, provider cloud.InstanceProvider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	details := cloud.InstanceStopDetails{
+		ExperimentName:       experimentsDetails.ExperimentName,
+		EngineName:           experimentsDetails.EngineName,
+		RampTime:             experimentsDetails.RampTime,
+		ChaosDuration:        experimentsDetails.ChaosDuration,
+		ChaosInterval:        experimentsDetails.ChaosInterval,
+		Timeout:              experimentsDetails.Timeout,
+		Delay:                experimentsDetails.Delay,
+		Sequence:             experimentsDetails.Sequence,
+		InstanceAffectedPerc: experimentsDetails.InstanceAffectedPerc,
+		InstanceKind:         instanceKind,
+	}
+	return cloud.PrepareInstanceStop(details, experimentsDetails.TargetInstanceIDList, clients, provider, resultDetails, eventsDetails, chaosDetails)
+}
+
+// GetTargetInstances returns the ids of the target instances
+// the instances are selected by the tag if provided, otherwise by the names and resource group
+// the names are in scaleSetName_instanceID format, if the scale set is enabled
+func GetTargetInstances(experimentsDetails *experimentTypes.ExperimentDetails, provider cloud.InstanceProvider) ([]string, error) {
+
+	if experimentsDetails.InstanceTag != "" {
+		instanceIDList, err := provider.ListByTag(experimentsDetails.InstanceTag)
+		if err != nil {
+			return nil, err
+		}
+		if len(instanceIDList) == 0 {
+			return nil, errors.Errorf("no azure instance found with %v tag", experimentsDetails.InstanceTag)
+		}
+		return instanceIDList, nil
+	}
+
+	if experimentsDetails.AzureInstanceNames == "" || experimentsDetails.ResourceGroup == "" {
+		return nil, errors.Errorf("please provide either the azure instance names along with the resource group or the instance tag")
+	}
+
+	var instanceIDList []string
+	for _, name := range strings.Split(experimentsDetails.AzureInstanceNames, ",") {
+		name = strings.TrimSpace(name)
+		if experimentsDetails.ScaleSet == "enable" {
+			index := strings.LastIndex(name, "_")
+			if index <= 0 || index == len(name)-1 {
+				return nil, errors.Errorf("fail to parse the scale set instance %v, provide it in scaleSetName_instanceID format", name)
+			}
+			name = name[:index] + "/" + name[index+1:]
+		}
+		instanceIDList = append(instanceIDList, experimentsDetails.ResourceGroup+"/"+name)
+	}
+	return instanceIDList, nil
+}
+
+// InstanceStatusCheck is used to check the instance status of all the instance under chaos
+// it stores the target instances in the experiment details
+func InstanceStatusCheck(experimentsDetails *experimentTypes.ExperimentDetails, provider cloud.InstanceProvider) error {
+
+	instanceIDList, err := GetTargetInstances(experimentsDetails, provider)
+	if err != nil {
+		return err
+	}
+	if err := cloud.InstanceStatusCheck(instanceKind, instanceIDList, provider); err != nil {
+		return err
+	}
+	experimentsDetails.TargetInstanceIDList = instanceIDList
+	return nil
+}
@@ -0,0 +1,394 @@
+package lib
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/cassandra"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/cassandra/pod-delete/types"
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/probe"
+	"github.com/litmuschaos/litmus-go/pkg/result"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	litmusexec "github.com/litmuschaos/litmus-go/pkg/utils/exec"
+	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var inject, abort chan os.Signal
+
+// the states of the target node, it is shared with the abort watcher, hence accessed atomically
+// the data volumes of the node are deleted only once it left the ring, as it streams its data to the other replicas till then
+const (
+	nodeActive int32 = iota
+	// nodeLeaving is set once the decommission is requested, till the node is removed from the peers of the other nodes
+	nodeLeaving
+	// nodeLeft is set once the node left the ring, till it is recovered
+	nodeLeft
+)
+
+var nodeState int32
+
+//PrepareNodeDecommission contains the prepration steps before chaos injection
+func PrepareNodeDecommission(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	// inject channel is used to transmit signal notifications.
+	inject = make(chan os.Signal, 1)
+	// Catch and relay certain signal(s) to inject channel.
+	signal.Notify(inject, os.Interrupt, syscall.SIGTERM)
+
+	// abort channel is used to transmit signal notifications.
+	abort = make(chan os.Signal, 1)
+	// Catch and relay certain signal(s) to abort channel.
+	signal.Notify(abort, os.Interrupt, syscall.SIGTERM)
+
+	//Waiting for the ramp time before chaos injection
+	if experimentsDetails.ChaoslibDetail.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time before injecting chaos", experimentsDetails.ChaoslibDetail.RampTime)
+		common.WaitForDuration(experimentsDetails.ChaoslibDetail.RampTime)
+	}
+
+	// select the target pod, only a single node is decommissioned at a time
+	targetPod, err := getTargetPod(experimentsDetails, clients, chaosDetails)
+	if err != nil {
+		return err
+	}
+	node, err := cassandra.GetRingNode(experimentsDetails, targetPod)
+	if err != nil {
+		return err
+	}
+	log.Infof("[Info]: Target cassandra node is %v with %v host id", targetPod.Name, node.HostID)
+
+	if experimentsDetails.ChaoslibDetail.EngineName != "" {
+		msg := "Injecting " + experimentsDetails.ChaoslibDetail.ExperimentName + " chaos on " + targetPod.Name + " pod"
+		types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+		events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+	}
+
+	// run the probes during chaos
+	if len(resultDetails.ProbeDetails) != 0 {
+		if err = probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+			return err
+		}
+	}
+
+	// watching for the abort signal and recover the node
+	go abortWatcher(experimentsDetails, targetPod, node.HostID, clients, resultDetails, chaosDetails, eventsDetails)
+
+	select {
+	case <-inject:
+		// stopping the chaos execution, if abort signal recieved
+		os.Exit(0)
+	default:
+		atomic.StoreInt32(&nodeState, nodeLeaving)
+		// the decommission may continue in the node, even if the request failed, ex: upon the exec timeout
+		if err := decommissionNode(experimentsDetails, targetPod, clients); err != nil {
+			chaosDetails.PendingReverts = append(chaosDetails.PendingReverts, pendingRecovery(targetPod, node.HostID))
+			return err
+		}
+	}
+
+	// wait for the node to leave the ring
+	if err := waitForNodeLeave(experimentsDetails, targetPod, node.HostID, clients); err != nil {
+		chaosDetails.PendingReverts = append(chaosDetails.PendingReverts, pendingRecovery(targetPod, node.HostID))
+		return err
+	}
+	atomic.StoreInt32(&nodeState, nodeLeft)
+
+	log.Infof("[Chaos]: Waiting for %vs", experimentsDetails.ChaoslibDetail.ChaosDuration)
+	common.WaitForDuration(experimentsDetails.ChaoslibDetail.ChaosDuration)
+
+	log.Info("[Chaos]: Stopping the experiment")
+	if err := recoverNode(experimentsDetails, targetPod, clients); err != nil {
+		chaosDetails.PendingReverts = append(chaosDetails.PendingReverts, pendingRecovery(targetPod, node.HostID))
+		return err
+	}
+	atomic.StoreInt32(&nodeState, nodeActive)
+	if err := waitForNodeRecovery(experimentsDetails, targetPod, clients); err != nil {
+		return err
+	}
+
+	//Waiting for the ramp time after chaos injection
+	if experimentsDetails.ChaoslibDetail.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time after injecting chaos", experimentsDetails.ChaoslibDetail.RampTime)
+		common.WaitForDuration(experimentsDetails.ChaoslibDetail.RampTime)
+	}
+	return nil
+}
+
+// pendingRecovery describes the recovery of the target node, which is left to be done manually
+func pendingRecovery(target apiv1.Pod, hostID string) string {
+	return "cassandra node: " + target.Name + ", host id: " + hostID + ", recover it once nodetool status doesn't list the host id, by deleting its pvcs and pod"
+}
+
+// getTargetPod returns the target cassandra pod, the first one is selected if multiple pods are targeted
+func getTargetPod(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, chaosDetails *types.ChaosDetails) (apiv1.Pod, error) {
+
+	if experimentsDetails.ChaoslibDetail.TargetPods == "" && chaosDetails.AppDetail.Label == "" {
+		return apiv1.Pod{}, errors.Errorf("Please provide one of the appLabel or TARGET_PODS")
+	}
+	targetPodList, err := common.GetPodList(experimentsDetails.ChaoslibDetail.TargetPods, experimentsDetails.ChaoslibDetail.PodsAffectedPerc, clients, chaosDetails)
+	if err != nil {
+		return apiv1.Pod{}, err
+	}
+	if len(targetPodList.Items) > 1 {
+		log.Warnf("[Info]: %v pods are targeted, only the %v pod is decommissioned", len(targetPodList.Items), targetPodList.Items[0].Name)
+	}
+	return targetPodList.Items[0], nil
+}
+
+// decommissionNode decommission the cassandra node, which streams its data to the remaining replicas and leaves the ring
+// the management api is used if its port is provided, otherwise the nodetool is executed inside the target container
+func decommissionNode(experimentsDetails *experimentTypes.ExperimentDetails, pod apiv1.Pod, clients clients.ClientSets) error {
+
+	log.Infof("[Chaos]: Decommissioning the %v cassandra node", pod.Name)
+	if experimentsDetails.ManagementAPIPort != 0 {
+		URL := "http://" + net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(experimentsDetails.ManagementAPIPort)) + "/api/v0/ops/node/decommission?force=true"
+		client := &http.Client{Timeout: time.Duration(experimentsDetails.ChaoslibDetail.Timeout) * time.Second}
+		response, err := client.Post(URL, "application/json", nil)
+		if err != nil {
+			return errors.Errorf("Unable to decommission the %v cassandra node, err: %v", pod.Name, err)
+		}
+		defer response.Body.Close()
+		if response.StatusCode < 200 || response.StatusCode > 299 {
+			return errors.Errorf("Unable to decommission the %v cassandra node, the management api responded with %v status", pod.Name, response.Status)
+		}
+		return nil
+	}
+
+	containerName := experimentsDetails.ChaoslibDetail.TargetContainer
+	if containerName == "" {
+		containerName = "cassandra"
+	}
+	execCommandDetails := litmusexec.PodDetails{}
+	litmusexec.SetExecCommandAttributes(&execCommandDetails, pod.Name, containerName, experimentsDetails.ChaoslibDetail.AppNS)
+	if _, err := litmusexec.Exec(&execCommandDetails, clients, []string{"nodetool", "decommission"}); err != nil {
+		return errors.Errorf("Unable to decommission the %v cassandra node, err: %v", pod.Name, err)
+	}
+	return nil
+}
+
+// waitForNodeLeave waits till the decommissioned node is removed from the peers of all the remaining nodes
+func waitForNodeLeave(experimentsDetails *experimentTypes.ExperimentDetails, target apiv1.Pod, hostID string, clients clients.ClientSets) error {
+
+	log.Infof("[Wait]: Waiting for the %v cassandra node to leave the ring", target.Name)
+	return retry.
+		Times(uint(experimentsDetails.ChaoslibDetail.Timeout / experimentsDetails.ChaoslibDetail.Delay)).
+		Wait(time.Duration(experimentsDetails.ChaoslibDetail.Delay) * time.Second).
+		Try(func(attempt uint) error {
+			podList, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaoslibDetail.AppNS).List(v1.ListOptions{LabelSelector: experimentsDetails.ChaoslibDetail.AppLabel})
+			if err != nil {
+				return errors.Errorf("Failed to get the application pods, err: %v", err)
+			}
+			for _, pod := range podList.Items {
+				if pod.Name == target.Name {
+					continue
+				}
+				node, err := cassandra.GetRingNode(experimentsDetails, pod)
+				if err != nil {
+					return err
+				}
+				for _, peer := range node.Peers {
+					if peer == hostID {
+						return errors.Errorf("%v cassandra node still has the %v node as its peer", pod.Name, target.Name)
+					}
+				}
+			}
+			log.Infof("[Info]: The %v cassandra node left the ring", target.Name)
+			return nil
+		})
+}
+
+// recoverNode brings the decommissioned node back to the ring, as per the CASSANDRA_DECOMMISSION_RECOVERY
+// rebootstrap: the data volumes and the pod are deleted, so that the statefulset recreates the node and it bootstraps afresh
+// it waits for the data volumes to be deleted, but not for the node to rejoin the ring, so that it can be used upon the abort
+// none: the node is left decommissioned
+func recoverNode(experimentsDetails *experimentTypes.ExperimentDetails, target apiv1.Pod, clients clients.ClientSets) error {
+
+	switch experimentsDetails.DecommissionRecovery {
+	case "none":
+		log.Warnf("[Recovery]: The %v cassandra node is left decommissioned, as the recovery is disabled", target.Name)
+		return nil
+	case "rebootstrap":
+	default:
+		return errors.Errorf("%v decommission recovery is not supported, supported values: rebootstrap, none", experimentsDetails.DecommissionRecovery)
+	}
+
+	// the decommissioned node refuses to rejoin the ring with its old data
+	isStatefulSet := false
+	for _, owner := range target.OwnerReferences {
+		if owner.Kind == "StatefulSet" {
+			isStatefulSet = true
+		}
+	}
+	if !isStatefulSet {
+		return errors.Errorf("%v cassandra pod is not part of a statefulset, it can't be recovered", target.Name)
+	}
+
+	claims := getClaimNames(target)
+	log.Infof("[Recovery]: Deleting the %v data volumes and the %v cassandra pod", claims, target.Name)
+	for _, claim := range claims {
+		// the claims are protected while in use, they are removed once the pod is deleted
+		if err := clients.KubeClient.CoreV1().PersistentVolumeClaims(target.Namespace).Delete(claim, &v1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Errorf("Unable to delete the %v pvc, err: %v", claim, err)
+		}
+	}
+	if err := clients.KubeClient.CoreV1().Pods(target.Namespace).Delete(target.Name, &v1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return errors.Errorf("Unable to delete the %v pod, err: %v", target.Name, err)
+	}
+	if err := waitForClaimsDeletion(experimentsDetails, target.Namespace, claims, clients); err != nil {
+		return err
+	}
+	return deletePendingPod(target, clients)
+}
+
+// getClaimNames returns the names of the persistent volume claims of the pod
+func getClaimNames(pod apiv1.Pod) []string {
+	claims := []string{}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			claims = append(claims, volume.PersistentVolumeClaim.ClaimName)
+		}
+	}
+	return claims
+}
+
+// waitForClaimsDeletion waits till the given persistent volume claims are removed
+func waitForClaimsDeletion(experimentsDetails *experimentTypes.ExperimentDetails, namespace string, claims []string, clients clients.ClientSets) error {
+
+	log.Infof("[Recovery]: Waiting for the %v data volumes to be deleted", claims)
+	return retry.
+		Times(uint(experimentsDetails.ChaoslibDetail.Timeout / experimentsDetails.ChaoslibDetail.Delay)).
+		Wait(time.Duration(experimentsDetails.ChaoslibDetail.Delay) * time.Second).
+		Try(func(attempt uint) error {
+			for _, claim := range claims {
+				_, err := clients.KubeClient.CoreV1().PersistentVolumeClaims(namespace).Get(claim, v1.GetOptions{})
+				if err == nil {
+					return errors.Errorf("%v pvc is not deleted yet", claim)
+				}
+				if !k8serrors.IsNotFound(err) {
+					return errors.Errorf("Unable to get the %v pvc, err: %v", claim, err)
+				}
+			}
+			return nil
+		})
+}
+
+// deletePendingPod deletes the recreated pod, if it is pending on the deleted claims
+// the statefulset may recreate the pod while its old claims are still terminating, such pod is never scheduled
+// as the statefulset creates the missing claims only along with the pod
+func deletePendingPod(target apiv1.Pod, clients clients.ClientSets) error {
+
+	pod, err := clients.KubeClient.CoreV1().Pods(target.Namespace).Get(target.Name, v1.GetOptions{})
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Errorf("Unable to get the %v pod, err: %v", target.Name, err)
+	}
+	if pod.UID == target.UID || pod.Status.Phase != apiv1.PodPending {
+		return nil
+	}
+	for _, claim := range getClaimNames(*pod) {
+		_, err := clients.KubeClient.CoreV1().PersistentVolumeClaims(pod.Namespace).Get(claim, v1.GetOptions{})
+		if err == nil {
+			continue
+		}
+		if !k8serrors.IsNotFound(err) {
+			return errors.Errorf("Unable to get the %v pvc, err: %v", claim, err)
+		}
+		log.Infof("[Recovery]: Deleting the %v cassandra pod again, as it is pending on the missing %v pvc", pod.Name, claim)
+		if err := clients.KubeClient.CoreV1().Pods(pod.Namespace).Delete(pod.Name, &v1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			return errors.Errorf("Unable to delete the %v pod, err: %v", pod.Name, err)
+		}
+		return nil
+	}
+	return nil
+}
+
+// waitForNodeRecovery waits for the statefulset to recreate the target pod and for its node to rejoin the ring
+func waitForNodeRecovery(experimentsDetails *experimentTypes.ExperimentDetails, target apiv1.Pod, clients clients.ClientSets) error {
+
+	if experimentsDetails.DecommissionRecovery == "none" {
+		return nil
+	}
+
+	log.Infof("[Recovery]: Waiting for the %v cassandra pod to be recreated", target.Name)
+	err := retry.
+		Times(uint(experimentsDetails.ChaoslibDetail.Timeout / experimentsDetails.ChaoslibDetail.Delay)).
+		Wait(time.Duration(experimentsDetails.ChaoslibDetail.Delay) * time.Second).
+		Try(func(attempt uint) error {
+			pod, err := clients.KubeClient.CoreV1().Pods(target.Namespace).Get(target.Name, v1.GetOptions{})
+			if err != nil {
+				return errors.Errorf("Unable to get the %v pod, err: %v", target.Name, err)
+			}
+			if pod.UID == target.UID {
+				return errors.Errorf("%v pod is not deleted yet", target.Name)
+			}
+			for _, condition := range pod.Status.Conditions {
+				if condition.Type == apiv1.PodReady && condition.Status == apiv1.ConditionTrue {
+					return nil
+				}
+			}
+			return errors.Errorf("%v pod is not ready yet", target.Name)
+		})
+	if err != nil {
+		return err
+	}
+	return cassandra.RingStatusCheck(experimentsDetails, clients)
+}
+
+// abortWatcher continuosly watch for the abort signals
+func abortWatcher(experimentsDetails *experimentTypes.ExperimentDetails, target apiv1.Pod, hostID string, clients clients.ClientSets, resultDetails *types.ResultDetails, chaosDetails *types.ChaosDetails, eventsDetails *types.EventDetails) {
+
+	for {
+		select {
+		case <-abort:
+			log.Info("[Chaos]: Killing process started because of terminated signal received")
+			// the decommission can't be cancelled once requested, the node is recovered instead once it left the ring
+			// the node which is still leaving keeps its data volumes, its recovery is reported in the chaosresult
+			var pendingReverts []string
+			switch atomic.LoadInt32(&nodeState) {
+			case nodeLeaving:
+				log.Warnf("[Recovery]: The %v cassandra node is still leaving the ring, it is left to be recovered manually", target.Name)
+				pendingReverts = append(pendingReverts, pendingRecovery(target, hostID))
+			case nodeLeft:
+				log.Info("Chaos Revert Started")
+				if err := recoverNode(experimentsDetails, target, clients); err != nil {
+					log.Errorf("Unable to recover the %v cassandra node, err: %v", target.Name, err)
+					pendingReverts = append(pendingReverts, pendingRecovery(target, hostID))
+				} else {
+					atomic.StoreInt32(&nodeState, nodeActive)
+				}
+				log.Info("Chaos Revert Completed")
+			}
+
+			// updating the chaosresult after stopped
+			failStep := result.WithPendingReverts("Chaos injection stopped!", pendingReverts, resultDetails)
+			types.SetResultAfterCompletion(resultDetails, "Stopped", "Stopped", failStep)
+			result.ChaosResult(chaosDetails, clients, resultDetails, "EOT")
+
+			// generating summary event in chaosengine
+			msg := experimentsDetails.ChaoslibDetail.ExperimentName + " experiment has been aborted"
+			types.SetEngineEventAttributes(eventsDetails, types.Summary, msg, "Warning", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+
+			// generating summary event in chaosresult
+			types.SetResultEventAttributes(eventsDetails, types.StoppedVerdict, msg, "Warning", resultDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosResult")
+			os.Exit(1)
+		}
+	}
+}
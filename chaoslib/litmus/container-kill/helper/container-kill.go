@@ -12,6 +12,7 @@ import (
 	"github.com/litmuschaos/litmus-go/pkg/events"
 	experimentEnv "github.com/litmuschaos/litmus-go/pkg/generic/container-kill/environment"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/container-kill/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/litmuschaos/litmus-go/pkg/types"
 	"github.com/openebs/maya/pkg/util/retry"
@@ -42,6 +43,7 @@ func main() {
 
 	err := KillContainer(&experimentsDetails, clients, &eventsDetails, &chaosDetails)
 	if err != nil {
+		helper.WriteTerminationMessage(err)
 		log.Fatalf("helper pod failed, err: %v", err)
 	}
 
@@ -95,6 +97,7 @@ func KillContainer(experimentsDetails *experimentTypes.ExperimentDetails, client
 		default:
 			return errors.Errorf("%v container runtime not supported", experimentsDetails.ContainerRuntime)
 		}
+		helper.RecordInjected("killed " + experimentsDetails.TargetContainer + " container of " + experimentsDetails.TargetPods + " pod with " + experimentsDetails.Signal + " signal")
 
 		//Waiting for the chaos interval after chaos injection
 		if experimentsDetails.ChaosInterval != 0 {
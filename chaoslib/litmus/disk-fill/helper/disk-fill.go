@@ -14,6 +14,7 @@ import (
 	"github.com/litmuschaos/litmus-go/pkg/events"
 	experimentEnv "github.com/litmuschaos/litmus-go/pkg/generic/disk-fill/environment"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/disk-fill/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/litmuschaos/litmus-go/pkg/result"
 	"github.com/litmuschaos/litmus-go/pkg/types"
@@ -65,6 +66,7 @@ func main() {
 
 	err := DiskFill(&experimentsDetails, clients, &eventsDetails, &chaosDetails, &resultDetails)
 	if err != nil {
+		helper.WriteTerminationMessage(err)
 		log.Fatalf("helper pod failed, err: %v", err)
 	}
 }
@@ -133,6 +135,7 @@ func DiskFill(experimentsDetails *experimentTypes.ExperimentDetails, clients cli
 			log.Error(string(out))
 			return err
 		}
+		helper.RecordInjected("filled " + strconv.Itoa(sizeTobeFilled) + "KB of ephemeral storage of " + experimentsDetails.TargetContainer + " container of " + experimentsDetails.TargetPods + " pod")
 
 		log.Infof("[Chaos]: Waiting for %vs", experimentsDetails.ChaosDuration)
 
@@ -158,7 +161,7 @@ func fillDisk(containerID string, sizeTobeFilled int) error {
 	select {
 	case <-inject:
 		// stopping the chaos execution, if abort signal recieved
-		os.Exit(1)
+		helper.ExitAborted()
 	default:
 		// Creating files to fill the required ephemeral storage size of block size of 4K
 		log.Infof("[Fill]: Filling ephemeral storage, size: %vKB", sizeTobeFilled)
@@ -273,6 +276,7 @@ func Remedy(experimentsDetails *experimentTypes.ExperimentDetails, clients clien
 			return err
 		}
 	}
+	helper.RecordReverted("removed the files filling the ephemeral storage of " + experimentsDetails.TargetPods + " pod")
 	return nil
 }
 
@@ -318,7 +322,7 @@ func abortWatcher(experimentsDetails *experimentTypes.ExperimentDetails, clients
 				time.Sleep(1 * time.Second)
 			}
 			log.Info("Chaos Revert Completed")
-			os.Exit(1)
+			helper.ExitAborted()
 		}
 	}
 }
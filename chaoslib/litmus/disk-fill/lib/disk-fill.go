@@ -5,9 +5,9 @@ import (
 
 	clients "github.com/litmuschaos/litmus-go/pkg/clients"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/disk-fill/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/litmuschaos/litmus-go/pkg/probe"
-	"github.com/litmuschaos/litmus-go/pkg/status"
 	"github.com/litmuschaos/litmus-go/pkg/types"
 	"github.com/litmuschaos/litmus-go/pkg/utils/common"
 	"github.com/litmuschaos/litmus-go/pkg/utils/exec"
@@ -99,7 +99,7 @@ func PrepareDiskFill(experimentsDetails *experimentTypes.ExperimentDetails, clie
 // InjectChaosInSerialMode fill the ephemeral storage of all target application serially (one by one)
 func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetails, targetPodList apiv1.PodList, clients clients.ClientSets, chaosDetails *types.ChaosDetails, execCommandDetails exec.PodDetails, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails) error {
 
-	labelSuffix := common.GetRunID()
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
 
 	// run the probes during chaos
 	if len(resultDetails.ProbeDetails) != 0 {
@@ -110,36 +110,14 @@ func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetai
 
 	// creating the helper pod to perform disk-fill chaos
 	for _, pod := range targetPodList.Items {
-		runID := common.GetRunID()
-		err := CreateHelperPod(experimentsDetails, clients, pod.Name, pod.Spec.NodeName, runID, labelSuffix)
-		if err != nil {
-			return errors.Errorf("Unable to create the helper pod, err: %v", err)
-		}
-
-		appLabel := "name=" + experimentsDetails.ExperimentName + "-" + runID
-
-		//checking the status of the helper pods, wait till the pod comes to running state else fail the experiment
-		log.Info("[Status]: Checking the status of the helper pods")
-		err = status.CheckApplicationStatus(experimentsDetails.ChaosNamespace, appLabel, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
-		if err != nil {
-			common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+runID, appLabel, chaosDetails, clients)
-			return errors.Errorf("helper pods are not in running state, err: %v", err)
+		if err := CreateHelperPod(experimentsDetails, manager, pod.Name, pod.Spec.NodeName); err != nil {
+			return err
 		}
 
-		// Wait till the completion of the helper pod
+		// wait till the completion of the helper pod and delete it afterwards
 		// set an upper limit for the waiting time
-		log.Info("[Wait]: waiting till the completion of the helper pod")
-		podStatus, err := status.WaitForCompletion(experimentsDetails.ChaosNamespace, appLabel, clients, experimentsDetails.ChaosDuration+60, experimentsDetails.ExperimentName)
-		if err != nil || podStatus == "Failed" {
-			common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+runID, appLabel, chaosDetails, clients)
-			return errors.Errorf("helper pod failed due to, err: %v", err)
-		}
-
-		//Deleting all the helper pod for disk-fill chaos
-		log.Info("[Cleanup]: Deleting the helper pod")
-		err = common.DeletePod(experimentsDetails.ExperimentName+"-"+runID, appLabel, experimentsDetails.ChaosNamespace, chaosDetails.Timeout, chaosDetails.Delay, clients)
-		if err != nil {
-			return errors.Errorf("Unable to delete the helper pod, %v", err)
+		if err := manager.Run(experimentsDetails.ChaosDuration + 60); err != nil {
+			return err
 		}
 	}
 
@@ -150,7 +128,7 @@ func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetai
 // InjectChaosInParallelMode fill the ephemeral storage of of all target application in parallel mode (all at once)
 func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDetails, targetPodList apiv1.PodList, clients clients.ClientSets, chaosDetails *types.ChaosDetails, execCommandDetails exec.PodDetails, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails) error {
 
-	labelSuffix := common.GetRunID()
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
 
 	// run the probes during chaos
 	if len(resultDetails.ProbeDetails) != 0 {
@@ -161,41 +139,14 @@ func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDet
 
 	// creating the helper pod to perform disk-fill chaos
 	for _, pod := range targetPodList.Items {
-		runID := common.GetRunID()
-		err := CreateHelperPod(experimentsDetails, clients, pod.Name, pod.Spec.NodeName, runID, labelSuffix)
-		if err != nil {
-			return errors.Errorf("Unable to create the helper pod, err: %v", err)
+		if err := CreateHelperPod(experimentsDetails, manager, pod.Name, pod.Spec.NodeName); err != nil {
+			return err
 		}
 	}
 
-	appLabel := "app=" + experimentsDetails.ExperimentName + "-helper-" + labelSuffix
-
-	//checking the status of the helper pods, wait till the pod comes to running state else fail the experiment
-	log.Info("[Status]: Checking the status of the helper pods")
-	err := status.CheckApplicationStatus(experimentsDetails.ChaosNamespace, appLabel, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
-	if err != nil {
-		common.DeleteAllHelperPodBasedOnJobCleanupPolicy(appLabel, chaosDetails, clients)
-		return errors.Errorf("helper pods are not in running state, err: %v", err)
-	}
-
-	// Wait till the completion of the helper pod
+	// wait till the completion of the helper pods and delete them afterwards
 	// set an upper limit for the waiting time
-	log.Info("[Wait]: waiting till the completion of the helper pod")
-	podStatus, err := status.WaitForCompletion(experimentsDetails.ChaosNamespace, appLabel, clients, experimentsDetails.ChaosDuration+60, experimentsDetails.ExperimentName)
-	if err != nil || podStatus == "Failed" {
-		common.DeleteAllHelperPodBasedOnJobCleanupPolicy(appLabel, chaosDetails, clients)
-		return errors.Errorf("helper pod failed due to, err: %v", err)
-	}
-
-	//Deleting all the helper pod for disk-fill chaos
-	log.Info("[Cleanup]: Deleting all the helper pod")
-	err = common.DeleteAllPod(appLabel, experimentsDetails.ChaosNamespace, chaosDetails.Timeout, chaosDetails.Delay, clients)
-	if err != nil {
-		return errors.Errorf("Unable to delete the helper pod, %v", err)
-	}
-
-	return nil
-
+	return manager.Run(experimentsDetails.ChaosDuration + 60)
 }
 
 // GetServiceAccount find the serviceAccountName for the helper pod
@@ -219,75 +170,39 @@ func GetTargetContainer(experimentsDetails *experimentTypes.ExperimentDetails, a
 	return pod.Spec.Containers[0].Name, nil
 }
 
+// GetHelperManager returns the manager of the helper pods
+func GetHelperManager(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, chaosDetails *types.ChaosDetails) *helper.Manager {
+	return helper.NewManager(helper.Meta{
+		ExperimentName:  experimentsDetails.ExperimentName,
+		Namespace:       experimentsDetails.ChaosNamespace,
+		ChaosUID:        experimentsDetails.ChaosUID,
+		Image:           experimentsDetails.LIBImage,
+		ImagePullPolicy: experimentsDetails.LIBImagePullPolicy,
+	}, clients, chaosDetails)
+}
+
 // CreateHelperPod derive the attributes for helper pod and create the helper pod
-func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, appName, appNodeName, runID, labelSuffix string) error {
+func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, manager *helper.Manager, appName, appNodeName string) error {
 
 	mountPropagationMode := apiv1.MountPropagationHostToContainer
-	terminationGracePeriodSeconds := int64(experimentsDetails.TerminationGracePeriodSeconds)
-
-	helperPod := &apiv1.Pod{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      experimentsDetails.ExperimentName + "-" + runID,
-			Namespace: experimentsDetails.ChaosNamespace,
-			Labels: map[string]string{
-				"app":                       experimentsDetails.ExperimentName + "-helper-" + labelSuffix,
-				"name":                      experimentsDetails.ExperimentName + "-" + runID,
-				"chaosUID":                  string(experimentsDetails.ChaosUID),
-				"app.kubernetes.io/part-of": "litmus",
-			},
-			Annotations: experimentsDetails.Annotations,
-		},
-		Spec: apiv1.PodSpec{
-			RestartPolicy:                 apiv1.RestartPolicyNever,
-			ImagePullSecrets:              experimentsDetails.ImagePullSecrets,
-			NodeName:                      appNodeName,
-			ServiceAccountName:            experimentsDetails.ChaosServiceAccount,
-			TerminationGracePeriodSeconds: &terminationGracePeriodSeconds,
-			Volumes: []apiv1.Volume{
-				{
-					Name: "udev",
-					VolumeSource: apiv1.VolumeSource{
-						HostPath: &apiv1.HostPathVolumeSource{
-							Path: experimentsDetails.ContainerPath,
-						},
-					},
-				},
-			},
-			Containers: []apiv1.Container{
-				{
-					Name:            experimentsDetails.ExperimentName,
-					Image:           experimentsDetails.LIBImage,
-					ImagePullPolicy: apiv1.PullPolicy(experimentsDetails.LIBImagePullPolicy),
-					Command: []string{
-						"/bin/bash",
-					},
-					Args: []string{
-						"-c",
-						"./helper/disk-fill",
-					},
-					Resources: experimentsDetails.Resources,
-					Env:       GetPodEnv(experimentsDetails, appName),
-					VolumeMounts: []apiv1.VolumeMount{
-						{
-							Name:             "udev",
-							MountPath:        "/diskfill",
-							MountPropagation: &mountPropagationMode,
-						},
-					},
-				},
-			},
-		},
-	}
-
-	_, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaosNamespace).Create(helperPod)
-	return err
+	return manager.Create(manager.NewPod(
+		helper.WithNodeName(appNodeName),
+		helper.WithServiceAccount(experimentsDetails.ChaosServiceAccount),
+		helper.WithTerminationGracePeriod(experimentsDetails.TerminationGracePeriodSeconds),
+		helper.WithAnnotations(experimentsDetails.Annotations),
+		helper.WithImagePullSecrets(experimentsDetails.ImagePullSecrets),
+		helper.WithResources(experimentsDetails.Resources),
+		helper.WithCommand([]string{"/bin/bash"}, "-c", "./helper/disk-fill"),
+		helper.WithEnv(GetPodEnv(experimentsDetails, appName)),
+		helper.WithPodNameEnv(),
+		helper.WithHostPath("udev", experimentsDetails.ContainerPath, "/diskfill", &mountPropagationMode),
+	))
 }
 
 // GetPodEnv derive all the env required for the helper pod
-func GetPodEnv(experimentsDetails *experimentTypes.ExperimentDetails, podName string) []apiv1.EnvVar {
+func GetPodEnv(experimentsDetails *experimentTypes.ExperimentDetails, podName string) map[string]string {
 
-	var envVar []apiv1.EnvVar
-	ENVList := map[string]string{
+	return map[string]string{
 		"APP_NS":                      experimentsDetails.AppNS,
 		"APP_POD":                     podName,
 		"APP_CONTAINER":               experimentsDetails.TargetContainer,
@@ -299,29 +214,4 @@ func GetPodEnv(experimentsDetails *experimentTypes.ExperimentDetails, podName st
 		"FILL_PERCENTAGE":             strconv.Itoa(experimentsDetails.FillPercentage),
 		"EPHEMERAL_STORAGE_MEBIBYTES": strconv.Itoa(experimentsDetails.EphemeralStorageMebibytes),
 	}
-	for key, value := range ENVList {
-		var perEnv apiv1.EnvVar
-		perEnv.Name = key
-		perEnv.Value = value
-		envVar = append(envVar, perEnv)
-	}
-	// Getting experiment pod name from downward API
-	experimentPodName := GetValueFromDownwardAPI("v1", "metadata.name")
-	var downwardEnv apiv1.EnvVar
-	downwardEnv.Name = "POD_NAME"
-	downwardEnv.ValueFrom = &experimentPodName
-	envVar = append(envVar, downwardEnv)
-
-	return envVar
-}
-
-// GetValueFromDownwardAPI returns the value from downwardApi
-func GetValueFromDownwardAPI(apiVersion string, fieldPath string) apiv1.EnvVarSource {
-	downwardENV := apiv1.EnvVarSource{
-		FieldRef: &apiv1.ObjectFieldSelector{
-			APIVersion: apiVersion,
-			FieldPath:  fieldPath,
-		},
-	}
-	return downwardENV
 }
@@ -1,79 +1,94 @@
 package lib
 
 import (
+	"math/rand"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/ec2"
 	clients "github.com/litmuschaos/litmus-go/pkg/clients"
-	ebs "github.com/litmuschaos/litmus-go/pkg/cloud/aws"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	"github.com/litmuschaos/litmus-go/pkg/events"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/kube-aws/ebs-loss/types"
 	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/math"
 	"github.com/litmuschaos/litmus-go/pkg/probe"
+	"github.com/litmuschaos/litmus-go/pkg/result"
 	"github.com/litmuschaos/litmus-go/pkg/types"
 	"github.com/litmuschaos/litmus-go/pkg/utils/common"
-	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
-//InjectEBSLoss contains the chaos injection steps for ebs loss
-func InjectEBSLoss(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+var abort chan os.Signal
 
-	var err error
-	//Waiting for the ramp time before chaos injection
-	if experimentsDetails.RampTime != 0 {
-		log.Infof("[Ramp]: Waiting for the %vs ramp time before injecting chaos", experimentsDetails.RampTime)
-		common.WaitForDuration(experimentsDetails.RampTime)
+// TargetVolume contains the volume under chaos along with its original attachment
+type TargetVolume struct {
+	VolumeID   string
+	InstanceID string
+	Device     string
+}
+
+//PrepareEBSLossByID contains the prepration and injection steps for the ebs volumes provided by ids
+func PrepareEBSLossByID(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	volumeIDList := GetVolumeListByID(experimentsDetails.EBSVolumeID)
+	if len(volumeIDList) == 0 {
+		return errors.Errorf("no volume id found to detach")
 	}
+	return PrepareEBSLoss(experimentsDetails, volumeIDList, clients, provider, resultDetails, eventsDetails, chaosDetails)
+}
 
-	//Detaching the ebs volume from the instance
-	log.Info("[Chaos]: Detaching the EBS volume from the instance")
-	err = EBSVolumeDetach(experimentsDetails)
+//PrepareEBSLossByTag contains the prepration and injection steps for the ebs volumes filtered by tag
+func PrepareEBSLossByTag(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	volumeIDList, err := GetVolumeListByTag(experimentsDetails.VolumeTag, provider)
 	if err != nil {
-		return errors.Errorf("ebs detachment failed, err: %v", err)
+		return err
 	}
+	volumeIDList = CalculateVolumeAffPerc(experimentsDetails.VolumeAffectedPerc, volumeIDList)
+	log.Infof("[Chaos]:Number of volumes targeted: %v", len(volumeIDList))
 
-	//Wait for ebs volume detachment
-	log.Info("[Wait]: Wait for EBS volume detachment")
-	if err = WaitForVolumeDetachment(experimentsDetails); err != nil {
-		return errors.Errorf("unable to detach the ebs volume to the ec2 instance, err: %v", err)
-	}
+	// the targeted volumes are verified once again post chaos
+	experimentsDetails.TargetVolumeIDList = volumeIDList
 
-	// run the probes during chaos
-	if len(resultDetails.ProbeDetails) != 0 {
-		if err = probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
-			return err
-		}
-	}
+	return PrepareEBSLoss(experimentsDetails, volumeIDList, clients, provider, resultDetails, eventsDetails, chaosDetails)
+}
 
-	//Wait for chaos duration
-	log.Infof("[Wait]: Waiting for the chaos duration of %vs", experimentsDetails.ChaosDuration)
-	time.Sleep(time.Duration(experimentsDetails.ChaosDuration) * time.Second)
+//PrepareEBSLoss contains the prepration and injection steps for the given ebs volumes
+func PrepareEBSLoss(experimentsDetails *experimentTypes.ExperimentDetails, volumeIDList []string, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
-	//Getting the EBS volume attachment status
-	EBSStatus, err := ebs.GetEBSStatus(experimentsDetails)
+	// abort channel is used to transmit signal notifications.
+	abort = make(chan os.Signal, 1)
+	// Catch and relay certain signal(s) to abort channel.
+	signal.Notify(abort, os.Interrupt, syscall.SIGTERM)
+
+	var err error
+	//Waiting for the ramp time before chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time before injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+
+	// record the original attachment of the volumes, it is used to reattach them
+	targetVolumes, err := GetTargetVolumes(volumeIDList, provider)
 	if err != nil {
-		return errors.Errorf("failed to get the ebs status, err: %v", err)
+		return err
 	}
 
-	if EBSStatus != "attached" {
-		//Attaching the ebs volume from the instance
-		log.Info("[Chaos]: Attaching the EBS volume from the instance")
-		err = EBSVolumeAttach(experimentsDetails)
-		if err != nil {
-			return errors.Errorf("ebs attachment failed, err: %v", err)
-		}
+	// watching for the abort signal and reattach the volumes
+	go abortWatcher(experimentsDetails, targetVolumes, provider, clients, resultDetails, chaosDetails, eventsDetails)
 
-		//Wait for ebs volume attachment
-		log.Info("[Wait]: Wait for EBS volume attachment")
-		if err = WaitForVolumeAttachment(experimentsDetails); err != nil {
-			return errors.Errorf("unable to attach the ebs volume to the ec2 instance, err: %v", err)
+	if strings.ToLower(experimentsDetails.Sequence) == "serial" {
+		if err = InjectChaosInSerialMode(experimentsDetails, targetVolumes, clients, provider, resultDetails, eventsDetails, chaosDetails); err != nil {
+			return err
 		}
 	} else {
-		log.Info("[Skip]: The EBS volume is already attached")
+		if err = InjectChaosInParallelMode(experimentsDetails, targetVolumes, clients, provider, resultDetails, eventsDetails, chaosDetails); err != nil {
+			return err
+		}
 	}
 
 	//Waiting for the ramp time after chaos injection
@@ -84,132 +99,339 @@ func InjectEBSLoss(experimentsDetails *experimentTypes.ExperimentDetails, client
 	return nil
 }
 
-// EBSVolumeDetach will detach the ebs vol from ec2 node
-func EBSVolumeDetach(experimentsDetails *experimentTypes.ExperimentDetails) error {
+//InjectChaosInSerialMode will detach and reattach the ebs volumes in serial mode that is one after other
+//if the chaos interval is not provided, every volume is detached once for the whole chaos duration
+func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetails, targetVolumes []TargetVolume, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
-	// Load session from shared config
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-		Config:            aws.Config{Region: aws.String(experimentsDetails.Region)},
-	}))
+	//ChaosStartTimeStamp contains the start timestamp, when the chaos injection begin
+	ChaosStartTimeStamp := time.Now().Unix()
+	chaosInterval := getChaosInterval(experimentsDetails)
 
-	// Create new EC2 client
-	ec2Svc := ec2.New(sess)
+loop:
+	for {
 
-	input := &ec2.DetachVolumeInput{
-		VolumeId: aws.String(experimentsDetails.EBSVolumeID),
-	}
+		log.Infof("Target volumes list, %v", targetVolumes)
 
-	result, err := ec2Svc.DetachVolume(input)
-	if err != nil {
-		if aerr, ok := err.(awserr.Error); ok {
-			switch aerr.Code() {
-			default:
-				return errors.Errorf(aerr.Error())
+		if experimentsDetails.EngineName != "" {
+			msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on ebs volume"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		for _, volume := range targetVolumes {
+
+			if err := detachVolumes(experimentsDetails, []TargetVolume{volume}, clients, provider, resultDetails, eventsDetails, chaosDetails); err != nil {
+				return revertOnError(experimentsDetails, []TargetVolume{volume}, provider, err)
+			}
+
+			//Wait for chaos interval
+			log.Infof("[Wait]: Waiting for chaos interval of %vs before attaching the volume", chaosInterval)
+			common.WaitForDuration(chaosInterval)
+
+			if err := reattachVolume(experimentsDetails, volume, provider); err != nil {
+				return err
+			}
+
+			//ChaosCurrentTimeStamp contains the current timestamp
+			ChaosCurrentTimeStamp := time.Now().Unix()
+
+			//ChaosDiffTimeStamp contains the difference of current timestamp and start timestamp
+			//It will helpful to track the total chaos duration
+			chaosDiffTimeStamp := ChaosCurrentTimeStamp - ChaosStartTimeStamp
+
+			if experimentsDetails.ChaosInterval != 0 && int(chaosDiffTimeStamp) >= experimentsDetails.ChaosDuration {
+				log.Infof("[Chaos]: Time is up for experiment: %v", experimentsDetails.ExperimentName)
+				break loop
 			}
-		} else {
-			return errors.Errorf(err.Error())
+		}
+
+		// the volumes are detached only once, if the chaos interval is not provided
+		if experimentsDetails.ChaosInterval == 0 {
+			break loop
 		}
 	}
 
-	log.InfoWithValues("Detaching ebs having:", logrus.Fields{
-		"VolumeId":   *result.VolumeId,
-		"State":      *result.State,
-		"Device":     *result.Device,
-		"InstanceId": *result.InstanceId,
-	})
+	return nil
+}
+
+//InjectChaosInParallelMode will detach and reattach the ebs volumes in parallel mode that is all at once
+//if the chaos interval is not provided, the volumes are detached once for the whole chaos duration
+func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDetails, targetVolumes []TargetVolume, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	//ChaosStartTimeStamp contains the start timestamp, when the chaos injection begin
+	ChaosStartTimeStamp := time.Now().Unix()
+	chaosInterval := getChaosInterval(experimentsDetails)
+
+loop:
+	for {
+
+		log.Infof("Target volumes list, %v", targetVolumes)
+
+		if experimentsDetails.EngineName != "" {
+			msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on ebs volume"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		if err := detachVolumes(experimentsDetails, targetVolumes, clients, provider, resultDetails, eventsDetails, chaosDetails); err != nil {
+			return revertOnError(experimentsDetails, targetVolumes, provider, err)
+		}
+
+		//Wait for chaos interval
+		log.Infof("[Wait]: Waiting for chaos interval of %vs before attaching the volumes", chaosInterval)
+		common.WaitForDuration(chaosInterval)
+
+		if err := reattachVolumes(experimentsDetails, targetVolumes, provider); err != nil {
+			return err
+		}
+
+		//ChaosCurrentTimeStamp contains the current timestamp
+		ChaosCurrentTimeStamp := time.Now().Unix()
+
+		//ChaosDiffTimeStamp contains the difference of current timestamp and start timestamp
+		//It will helpful to track the total chaos duration
+		chaosDiffTimeStamp := ChaosCurrentTimeStamp - ChaosStartTimeStamp
+
+		if experimentsDetails.ChaosInterval == 0 || int(chaosDiffTimeStamp) >= experimentsDetails.ChaosDuration {
+			log.Infof("[Chaos]: Time is up for experiment: %v", experimentsDetails.ExperimentName)
+			break loop
+		}
+	}
 
 	return nil
 }
 
-// EBSVolumeAttach will detach the ebs vol from ec2 node
-func EBSVolumeAttach(experimentsDetails *experimentTypes.ExperimentDetails) error {
+// getChaosInterval returns the duration for which the volumes stay detached in every iteration
+// the volumes stay detached for the whole chaos duration, if the chaos interval is not provided
+func getChaosInterval(experimentsDetails *experimentTypes.ExperimentDetails) int {
+	if experimentsDetails.ChaosInterval == 0 {
+		return experimentsDetails.ChaosDuration
+	}
+	return experimentsDetails.ChaosInterval
+}
 
-	// Load session from shared config
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-		Config:            aws.Config{Region: aws.String(experimentsDetails.Region)},
-	}))
+// detachVolumes detaches the given volumes, waits for their detachment and runs the probes during chaos
+func detachVolumes(experimentsDetails *experimentTypes.ExperimentDetails, targetVolumes []TargetVolume, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
-	// Create new EC2 client
-	ec2Svc := ec2.New(sess)
+	//Detaching the ebs volumes from the instances
+	for _, volume := range targetVolumes {
+		log.Infof("[Chaos]: Detaching the EBS volume '%v' from the instance", volume.VolumeID)
+		if err := provider.DetachVolume(volume.VolumeID); err != nil {
+			return errors.Errorf("ebs detachment failed, err: %v", err)
+		}
+	}
 
-	//Attaching the ebs volume after chaos
-	input := &ec2.AttachVolumeInput{
-		Device:     aws.String(experimentsDetails.DeviceName),
-		InstanceId: aws.String(experimentsDetails.Ec2InstanceID),
-		VolumeId:   aws.String(experimentsDetails.EBSVolumeID),
+	for _, volume := range targetVolumes {
+		//Wait for ebs volume detachment
+		log.Infof("[Wait]: Wait for EBS volume '%v' detachment", volume.VolumeID)
+		if err := cloud.WaitForVolumeState(provider, experimentsDetails.Timeout, experimentsDetails.Delay, volume.VolumeID, volume.InstanceID, cloud.VolumeDetached); err != nil {
+			return errors.Errorf("unable to detach the ebs volume from the ec2 instance, err: %v", err)
+		}
 	}
 
-	result, err := ec2Svc.AttachVolume(input)
+	// run the probes during chaos
+	if len(resultDetails.ProbeDetails) != 0 {
+		if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revertOnError reattaches the given volumes after a failure in the chaos injection and returns the failure
+func revertOnError(experimentsDetails *experimentTypes.ExperimentDetails, targetVolumes []TargetVolume, provider cloud.Provider, err error) error {
+	if revertErr := reattachVolumes(experimentsDetails, targetVolumes, provider); revertErr != nil {
+		log.Errorf("unable to reattach the ebs volumes, err: %v", revertErr)
+	}
+	return err
+}
+
+// reattachVolumes attaches all the given volumes back to their original instances
+// it tries all the volumes and returns the combined error of the failed ones
+func reattachVolumes(experimentsDetails *experimentTypes.ExperimentDetails, targetVolumes []TargetVolume, provider cloud.Provider) error {
+
+	var failed []string
+	for _, volume := range targetVolumes {
+		if err := reattachVolume(experimentsDetails, volume, provider); err != nil {
+			failed = append(failed, volume.VolumeID+": "+err.Error())
+		}
+	}
+	if len(failed) != 0 {
+		return errors.Errorf("unable to reattach the ebs volumes, %v", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+// reattachVolume attaches the volume back to its original instance and device, if it is not already attached
+func reattachVolume(experimentsDetails *experimentTypes.ExperimentDetails, volume TargetVolume, provider cloud.Provider) error {
+
+	//Getting the EBS volume attachment status
+	EBSStatus, err := cloud.GetVolumeStatus(provider, volume.VolumeID, volume.InstanceID)
 	if err != nil {
-		if aerr, ok := err.(awserr.Error); ok {
-			switch aerr.Code() {
-			default:
-				return errors.Errorf(aerr.Error())
-			}
-		} else {
-			return errors.Errorf(err.Error())
+		return errors.Errorf("failed to get the ebs status, err: %v", err)
+	}
+
+	if EBSStatus == cloud.VolumeAttached {
+		log.Infof("[Skip]: The EBS volume '%v' is already attached", volume.VolumeID)
+		return nil
+	}
+
+	// the volume can't be attached till its detachment is completed, ex: once the detachment timed out
+	if EBSStatus == cloud.VolumeDetaching {
+		log.Infof("[Wait]: Wait for EBS volume '%v' detachment, before attaching it back", volume.VolumeID)
+		if err = cloud.WaitForVolumeState(provider, experimentsDetails.Timeout, experimentsDetails.Delay, volume.VolumeID, volume.InstanceID, cloud.VolumeDetached); err != nil {
+			return errors.Errorf("unable to detach the ebs volume before attaching it back, err: %v", err)
 		}
 	}
 
-	log.InfoWithValues("Attaching ebs having:", logrus.Fields{
-		"VolumeId":   *result.VolumeId,
-		"State":      *result.State,
-		"Device":     *result.Device,
-		"InstanceId": *result.InstanceId,
-	})
+	//Attaching the ebs volume to the instance
+	log.Info("[Chaos]: Attaching the EBS volume back to the instance")
+	if err = provider.AttachVolume(volume.VolumeID, volume.InstanceID, volume.Device); err != nil {
+		return errors.Errorf("ebs attachment failed, err: %v", err)
+	}
+
+	//Wait for ebs volume attachment
+	log.Infof("[Wait]: Wait for EBS volume '%v' attachment", volume.VolumeID)
+	if err = cloud.WaitForVolumeState(provider, experimentsDetails.Timeout, experimentsDetails.Delay, volume.VolumeID, volume.InstanceID, cloud.VolumeAttached); err != nil {
+		return errors.Errorf("unable to attach the ebs volume to the ec2 instance, err: %v", err)
+	}
 	return nil
 }
 
-// WaitForVolumeDetachment will wait the ebs volume to completely detach
-func WaitForVolumeDetachment(experimentsDetails *experimentTypes.ExperimentDetails) error {
+//GetTargetVolumes records the instance and device to which the given volumes are attached
+func GetTargetVolumes(volumeIDList []string, provider cloud.Provider) ([]TargetVolume, error) {
 
-	log.Info("[Status]: Checking ebs volume status for detachment")
-	err := retry.
-		Times(uint(experimentsDetails.Timeout / experimentsDetails.Delay)).
-		Wait(time.Duration(experimentsDetails.Delay) * time.Second).
-		Try(func(attempt uint) error {
+	volumes, err := provider.DescribeVolumes(volumeIDList...)
+	if err != nil {
+		return nil, errors.Errorf("failed to describe the ebs volumes, err: %v", err)
+	}
 
-			instanceState, err := ebs.GetEBSStatus(experimentsDetails)
-			if err != nil {
-				return errors.Errorf("failed to get the instance status")
-			}
-			if instanceState != "detached" {
-				log.Infof("The instance state is %v", instanceState)
-				return errors.Errorf("instance is not yet in detached state")
-			}
-			log.Infof("The instance state is %v", instanceState)
-			return nil
+	var targetVolumes []TargetVolume
+	for _, volume := range volumes {
+		if len(volume.Attachments) == 0 || volume.Attachments[0].State != cloud.VolumeAttached {
+			return nil, errors.Errorf("the ebs volume '%v' is not attached to any ec2 instance", volume.ID)
+		}
+		targetVolume := TargetVolume{
+			VolumeID:   volume.ID,
+			InstanceID: volume.Attachments[0].InstanceID,
+			Device:     volume.Attachments[0].Device,
+		}
+		log.InfoWithValues("[Info]: The original attachment of the target volume is:", logrus.Fields{
+			"VolumeId":   targetVolume.VolumeID,
+			"InstanceId": targetVolume.InstanceID,
+			"Device":     targetVolume.Device,
 		})
+		targetVolumes = append(targetVolumes, targetVolume)
+	}
+	if len(targetVolumes) != len(volumeIDList) {
+		return nil, errors.Errorf("unable to find all the ebs volumes, found: %v, expected: %v", len(targetVolumes), len(volumeIDList))
+	}
+	return targetVolumes, nil
+}
+
+//GetVolumeListByID returns the list of volume ids provided as comma separated string
+func GetVolumeListByID(volumeIDs string) []string {
+
+	var volumeIDList []string
+	for _, id := range strings.Split(volumeIDs, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			volumeIDList = append(volumeIDList, id)
+		}
+	}
+	return volumeIDList
+}
+
+//GetVolumeListByTag returns the list of attached volumes having the given tag
+func GetVolumeListByTag(volumeTag string, provider cloud.Provider) ([]string, error) {
+
+	volumeIDList, err := provider.ListVolumesByTag(volumeTag)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	if len(volumeIDList) == 0 {
+		return nil, errors.Errorf("no ebs volume found with the tag %v", volumeTag)
+	}
+
+	volumes, err := provider.DescribeVolumes(volumeIDList...)
+	if err != nil {
+		return nil, errors.Errorf("failed to describe the ebs volumes, err: %v", err)
+	}
+
+	var attachedVolumeIDList []string
+	for _, volume := range volumes {
+		if len(volume.Attachments) != 0 && volume.Attachments[0].State == cloud.VolumeAttached {
+			attachedVolumeIDList = append(attachedVolumeIDList, volume.ID)
+		}
+	}
+	if len(attachedVolumeIDList) == 0 {
+		return nil, errors.Errorf("no attached ebs volume found with the tag %v", volumeTag)
+	}
+	return attachedVolumeIDList, nil
 }
 
-// WaitForVolumeAttachment will wait for the ebs volume to get attached on ec2 instance
-func WaitForVolumeAttachment(experimentsDetails *experimentTypes.ExperimentDetails) error {
+//CalculateVolumeAffPerc will calculate the target volume ids according to the volume affected percentage provided.
+func CalculateVolumeAffPerc(volumeAffPerc int, volumeList []string) []string {
 
-	log.Info("[Status]: Checking ebs volume status for attachment")
-	err := retry.
-		Times(uint(experimentsDetails.Timeout / experimentsDetails.Delay)).
-		Wait(time.Duration(experimentsDetails.Delay) * time.Second).
-		Try(func(attempt uint) error {
+	var newVolumeList []string
+	newVolumeListLength := math.Maximum(1, math.Adjustment(volumeAffPerc, len(volumeList)))
+	rand.Seed(time.Now().UnixNano())
 
-			instanceState, err := ebs.GetEBSStatus(experimentsDetails)
-			if err != nil {
-				return errors.Errorf("failed to get the instance status")
-			}
-			if instanceState != "attached" {
-				log.Infof("The instance state is %v", instanceState)
-				return errors.Errorf("instance is not yet in attached state")
+	// it will generate the random volumeList
+	// it starts from the random index and choose requirement no of volumeID next to that index in a circular way.
+	index := rand.Intn(len(volumeList))
+	for i := 0; i < newVolumeListLength; i++ {
+		newVolumeList = append(newVolumeList, volumeList[index])
+		index = (index + 1) % len(volumeList)
+	}
+	return newVolumeList
+}
+
+//EBSStateCheck verifies that all the given volumes are attached to an ec2 instance
+func EBSStateCheck(volumeIDList []string, provider cloud.Provider) error {
+
+	if len(volumeIDList) == 0 {
+		return errors.Errorf("no volume id found to verify")
+	}
+	log.Infof("[Info]: The volumes under chaos(VUC) are: %v", volumeIDList)
+	_, err := GetTargetVolumes(volumeIDList, provider)
+	return err
+}
+
+// abortWatcher continuosly watch for the abort signals and reattaches the target volumes
+func abortWatcher(experimentsDetails *experimentTypes.ExperimentDetails, targetVolumes []TargetVolume, provider cloud.Provider, clients clients.ClientSets, resultDetails *types.ResultDetails, chaosDetails *types.ChaosDetails, eventsDetails *types.EventDetails) {
+
+	for {
+		select {
+		case <-abort:
+			log.Info("[Chaos]: Killing process started because of terminated signal received")
+			log.Info("Chaos Revert Started")
+			// retry thrice for the chaos revert
+			retry := 3
+			for retry > 0 {
+				err := reattachVolumes(experimentsDetails, targetVolumes, provider)
+				if err == nil {
+					break
+				}
+				log.Errorf("Unable to reattach the ebs volumes, err: %v", err)
+				retry--
+				time.Sleep(1 * time.Second)
 			}
-			log.Infof("The instance state is %v", instanceState)
-			return nil
-		})
-	if err != nil {
-		return err
+			log.Info("Chaos Revert Completed")
+
+			// updating the chaosresult after stopped
+			failStep := "Chaos injection stopped!"
+			types.SetResultAfterCompletion(resultDetails, "Stopped", "Stopped", failStep)
+			result.ChaosResult(chaosDetails, clients, resultDetails, "EOT")
+
+			// generating summary event in chaosengine
+			msg := experimentsDetails.ExperimentName + " experiment has been aborted"
+			types.SetEngineEventAttributes(eventsDetails, types.Summary, msg, "Warning", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+
+			// generating summary event in chaosresult
+			types.SetResultEventAttributes(eventsDetails, types.StoppedVerdict, msg, "Warning", resultDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosResult")
+			os.Exit(1)
+		}
 	}
-	return nil
 }
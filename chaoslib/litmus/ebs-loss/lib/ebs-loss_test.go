@@ -0,0 +1,249 @@
+package lib
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/litmuschaos/litmus-go/internal/testutil"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	cloudfake "github.com/litmuschaos/litmus-go/pkg/cloud/fake"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/kube-aws/ebs-loss/types"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/pkg/errors"
+)
+
+// newTestDetails returns the experiment & chaos details targeting the given volumes
+// the volumes are detached once, as the chaos duration and the chaos interval are zero
+func newTestDetails(volumeIDs string) (*experimentTypes.ExperimentDetails, *types.ChaosDetails) {
+
+	chaosDetails := testutil.ChaosDetails("ebs-loss")
+	experimentsDetails := &experimentTypes.ExperimentDetails{
+		ExperimentName:     chaosDetails.ExperimentName,
+		ChaosNamespace:     chaosDetails.ChaosNamespace,
+		Timeout:            chaosDetails.Timeout,
+		Delay:              chaosDetails.Delay,
+		EBSVolumeID:        volumeIDs,
+		VolumeTag:          "chaos:true",
+		VolumeAffectedPerc: 100,
+		Sequence:           "parallel",
+	}
+	return experimentsDetails, chaosDetails
+}
+
+// newTestProvider returns the fake provider with the given volumes, tagged as the chaos targets
+// every volume is attached to its own instance, at the /dev/sdb device
+func newTestProvider(ids ...string) *cloudfake.Provider {
+
+	provider := cloudfake.NewProvider()
+	for _, id := range ids {
+		provider.AddInstance(cloud.Instance{ID: "i-" + id, State: cloud.InstanceRunning})
+		provider.AddVolume(cloud.Volume{
+			ID:          id,
+			State:       "in-use",
+			Attachments: []cloud.VolumeAttachment{{InstanceID: "i-" + id, Device: "/dev/sdb", State: cloud.VolumeAttached}},
+			Tags:        map[string]string{"chaos": "true"},
+		})
+	}
+	return provider
+}
+
+// assertAttached verifies that the given volumes are attached back to their original instance & device
+func assertAttached(t *testing.T, provider *cloudfake.Provider, ids ...string) {
+
+	volumes, err := provider.DescribeVolumes(ids...)
+	if err != nil {
+		t.Fatalf("unable to describe the volumes, err: %v", err)
+	}
+	for _, volume := range volumes {
+		want := []cloud.VolumeAttachment{{InstanceID: "i-" + volume.ID, Device: "/dev/sdb", State: cloud.VolumeAttached}}
+		if !reflect.DeepEqual(volume.Attachments, want) {
+			t.Errorf("got %v attachments of %v volume, want %v", volume.Attachments, volume.ID, want)
+		}
+	}
+}
+
+// operations returns the detach & attach calls of the provider, in order
+func operations(provider *cloudfake.Provider) []string {
+
+	var calls []string
+	for _, call := range provider.Calls {
+		if strings.HasPrefix(call, "DetachVolume") || strings.HasPrefix(call, "AttachVolume") {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func TestPrepareEBSLossByID(t *testing.T) {
+
+	tests := []struct {
+		sequence  string
+		wantCalls []string
+		testName  string
+	}{
+		{
+			sequence:  "serial",
+			wantCalls: []string{"DetachVolume(vol-1)", "AttachVolume(vol-1,i-vol-1,/dev/sdb)", "DetachVolume(vol-2)", "AttachVolume(vol-2,i-vol-2,/dev/sdb)"},
+			testName:  "serial",
+		},
+		{
+			sequence:  "parallel",
+			wantCalls: []string{"DetachVolume(vol-1)", "DetachVolume(vol-2)", "AttachVolume(vol-1,i-vol-1,/dev/sdb)", "AttachVolume(vol-2,i-vol-2,/dev/sdb)"},
+			testName:  "parallel",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			clients, server := testutil.NewTestClientSets(t)
+			defer server.Close()
+			provider := newTestProvider("vol-1", "vol-2")
+
+			experimentsDetails, chaosDetails := newTestDetails("vol-1, vol-2")
+			experimentsDetails.Sequence = tt.sequence
+			if err := EBSStateCheck(GetVolumeListByID(experimentsDetails.EBSVolumeID), provider); err != nil {
+				t.Fatalf("EBSStateCheck failed, err: %v", err)
+			}
+			if err := PrepareEBSLossByID(experimentsDetails, clients, provider, &types.ResultDetails{}, &types.EventDetails{}, chaosDetails); err != nil {
+				t.Fatalf("PrepareEBSLossByID failed, err: %v", err)
+			}
+			if got := operations(provider); !reflect.DeepEqual(got, tt.wantCalls) {
+				t.Errorf("got %v calls, want %v", got, tt.wantCalls)
+			}
+			assertAttached(t, provider, "vol-1", "vol-2")
+		})
+	}
+}
+
+func TestPrepareEBSLossByTag(t *testing.T) {
+
+	clients, server := testutil.NewTestClientSets(t)
+	defer server.Close()
+	provider := newTestProvider("vol-1", "vol-2")
+	// the detached volumes are not targeted by tag
+	provider.AddVolume(cloud.Volume{ID: "vol-3", State: "available", Tags: map[string]string{"chaos": "true"}})
+
+	experimentsDetails, chaosDetails := newTestDetails("")
+	if err := PrepareEBSLossByTag(experimentsDetails, clients, provider, &types.ResultDetails{}, &types.EventDetails{}, chaosDetails); err != nil {
+		t.Fatalf("PrepareEBSLossByTag failed, err: %v", err)
+	}
+	targets := append([]string{}, experimentsDetails.TargetVolumeIDList...)
+	sort.Strings(targets)
+	if want := []string{"vol-1", "vol-2"}; !reflect.DeepEqual(targets, want) {
+		t.Errorf("got %v target volumes, want %v", targets, want)
+	}
+	if err := EBSStateCheck(experimentsDetails.TargetVolumeIDList, provider); err != nil {
+		t.Errorf("EBSStateCheck failed post chaos, err: %v", err)
+	}
+	assertAttached(t, provider, "vol-1", "vol-2")
+}
+
+func TestPrepareEBSLossFailure(t *testing.T) {
+
+	// the volume stays detaching for more than the timeout, which allows one attempt more than timeout/delay
+	var slowDetach []string
+	for i := 0; i <= testutil.Timeout/testutil.Delay+1; i++ {
+		slowDetach = append(slowDetach, cloud.VolumeDetaching)
+	}
+	slowDetach = append(slowDetach, cloud.VolumeDetached)
+
+	tests := []struct {
+		sequence          string
+		detachTransitions []string
+		attachTransitions []string
+		errors            map[string]error
+		// wantAttached is true, if the volumes are expected to be reattached after the failure
+		wantAttached bool
+		testName     string
+	}{
+		{
+			sequence:     "parallel",
+			errors:       map[string]error{"DetachVolume": errors.Errorf("UnauthorizedOperation")},
+			wantAttached: true,
+			testName:     "volume fails to detach",
+		},
+		{
+			// the volume is reattached once its detachment is completed, after the detachment timed out
+			sequence:          "serial",
+			detachTransitions: slowDetach,
+			wantAttached:      true,
+			testName:          "volume is slow to detach",
+		},
+		{
+			sequence:          "parallel",
+			attachTransitions: []string{"attaching"},
+			testName:          "volume never attaches",
+		},
+		{
+			sequence: "parallel",
+			errors:   map[string]error{"AttachVolume": errors.Errorf("VolumeInUse")},
+			testName: "volume fails to attach",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			clients, server := testutil.NewTestClientSets(t)
+			defer server.Close()
+			provider := newTestProvider("vol-1")
+			if tt.detachTransitions != nil {
+				provider.DetachTransitions = tt.detachTransitions
+			}
+			if tt.attachTransitions != nil {
+				provider.AttachTransitions = tt.attachTransitions
+			}
+			for operation, err := range tt.errors {
+				provider.Errors[operation] = err
+			}
+
+			experimentsDetails, chaosDetails := newTestDetails("vol-1")
+			experimentsDetails.Sequence = tt.sequence
+			if err := PrepareEBSLossByID(experimentsDetails, clients, provider, &types.ResultDetails{}, &types.EventDetails{}, chaosDetails); err == nil {
+				t.Fatal("expected PrepareEBSLossByID to fail")
+			}
+			if tt.wantAttached {
+				assertAttached(t, provider, "vol-1")
+			}
+		})
+	}
+}
+
+func TestGetTargetVolumes(t *testing.T) {
+
+	provider := newTestProvider("vol-1")
+	provider.AddVolume(cloud.Volume{ID: "vol-2", State: "available"})
+
+	tests := []struct {
+		volumeIDs []string
+		want      []TargetVolume
+		wantErr   bool
+		testName  string
+	}{
+		{
+			volumeIDs: []string{"vol-1"},
+			want:      []TargetVolume{{VolumeID: "vol-1", InstanceID: "i-vol-1", Device: "/dev/sdb"}},
+			testName:  "attached volume",
+		},
+		{
+			volumeIDs: []string{"vol-1", "vol-2"},
+			wantErr:   true,
+			testName:  "detached volume",
+		},
+		{
+			volumeIDs: []string{"vol-3"},
+			wantErr:   true,
+			testName:  "unknown volume",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			got, err := GetTargetVolumes(tt.volumeIDs, provider)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got %v error, want error: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v target volumes, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"time"
 
 	clients "github.com/litmuschaos/litmus-go/pkg/clients"
-	awslib "github.com/litmuschaos/litmus-go/pkg/cloud/aws"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
 	"github.com/litmuschaos/litmus-go/pkg/events"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/kube-aws/ec2-terminate-by-id/types"
 	"github.com/litmuschaos/litmus-go/pkg/log"
@@ -16,7 +16,7 @@ import (
 )
 
 //PrepareEC2TerminateByID contains the prepration and injection steps for the experiment
-func PrepareEC2TerminateByID(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+func PrepareEC2TerminateByID(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
 	var err error
 	//Waiting for the ramp time before chaos injection
@@ -32,11 +32,11 @@ func PrepareEC2TerminateByID(experimentsDetails *experimentTypes.ExperimentDetai
 	}
 
 	if strings.ToLower(experimentsDetails.Sequence) == "serial" {
-		if err = InjectChaosInSerialMode(experimentsDetails, instanceIDList, clients, resultDetails, eventsDetails, chaosDetails); err != nil {
+		if err = InjectChaosInSerialMode(experimentsDetails, instanceIDList, clients, provider, resultDetails, eventsDetails, chaosDetails); err != nil {
 			return err
 		}
 	} else {
-		if err = InjectChaosInParallelMode(experimentsDetails, instanceIDList, clients, resultDetails, eventsDetails, chaosDetails); err != nil {
+		if err = InjectChaosInParallelMode(experimentsDetails, instanceIDList, clients, provider, resultDetails, eventsDetails, chaosDetails); err != nil {
 			return err
 		}
 	}
@@ -49,7 +49,7 @@ func PrepareEC2TerminateByID(experimentsDetails *experimentTypes.ExperimentDetai
 }
 
 //InjectChaosInSerialMode will inject the ec2 instance termination in serial mode that is one after other
-func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetails, instanceIDList []string, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetails, instanceIDList []string, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
 	//ChaosStartTimeStamp contains the start timestamp, when the chaos injection begin
 	ChaosStartTimeStamp := time.Now().Unix()
@@ -70,14 +70,14 @@ loop:
 
 			//Stoping the EC2 instance
 			log.Info("[Chaos]: Stoping the desired EC2 instance")
-			err := awslib.EC2Stop(id, experimentsDetails.Region)
+			err := provider.StopInstance(id)
 			if err != nil {
 				return errors.Errorf("ec2 instance failed to stop, err: %v", err)
 			}
 
 			//Wait for ec2 instance to completely stop
 			log.Infof("[Wait]: Wait for EC2 instance '%v' to come in stopped state", id)
-			if err := awslib.WaitForEC2Down(experimentsDetails.Timeout, experimentsDetails.Delay, experimentsDetails.ManagedNodegroup, experimentsDetails.Region, id); err != nil {
+			if err := cloud.WaitForInstanceDown(provider, experimentsDetails.Timeout, experimentsDetails.Delay, experimentsDetails.ManagedNodegroup, id); err != nil {
 				return errors.Errorf("unable to stop the ec2 instance, err: %v", err)
 			}
 
@@ -95,14 +95,14 @@ loop:
 			//Starting the EC2 instance
 			if experimentsDetails.ManagedNodegroup != "enable" {
 				log.Info("[Chaos]: Starting back the EC2 instance")
-				err = awslib.EC2Start(id, experimentsDetails.Region)
+				err = provider.StartInstance(id)
 				if err != nil {
 					return errors.Errorf("ec2 instance failed to start, err: %v", err)
 				}
 
 				//Wait for ec2 instance to come in running state
 				log.Infof("[Wait]: Wait for EC2 instance '%v' to get in running state", id)
-				if err := awslib.WaitForEC2Up(experimentsDetails.Timeout, experimentsDetails.Delay, experimentsDetails.ManagedNodegroup, experimentsDetails.Region, id); err != nil {
+				if err := cloud.WaitForInstanceUp(provider, experimentsDetails.Timeout, experimentsDetails.Delay, id); err != nil {
 					return errors.Errorf("unable to start the ec2 instance, err: %v", err)
 				}
 			}
@@ -126,7 +126,7 @@ loop:
 }
 
 // InjectChaosInParallelMode will inject the ec2 instance termination in parallel mode that is all at once
-func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDetails, instanceIDList []string, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDetails, instanceIDList []string, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
 	//ChaosStartTimeStamp contains the start timestamp, when the chaos injection begin
 	ChaosStartTimeStamp := time.Now().Unix()
@@ -146,7 +146,7 @@ loop:
 		for _, id := range instanceIDList {
 			//Stoping the EC2 instance
 			log.Info("[Chaos]: Stoping the desired EC2 instance")
-			err := awslib.EC2Stop(id, experimentsDetails.Region)
+			err := provider.StopInstance(id)
 			if err != nil {
 				return errors.Errorf("ec2 instance failed to stop, err: %v", err)
 			}
@@ -155,7 +155,7 @@ loop:
 		for _, id := range instanceIDList {
 			//Wait for ec2 instance to completely stop
 			log.Infof("[Wait]: Wait for EC2 instance '%v' to come in stopped state", id)
-			if err := awslib.WaitForEC2Down(experimentsDetails.Timeout, experimentsDetails.Delay, experimentsDetails.ManagedNodegroup, experimentsDetails.Region, id); err != nil {
+			if err := cloud.WaitForInstanceDown(provider, experimentsDetails.Timeout, experimentsDetails.Delay, experimentsDetails.ManagedNodegroup, id); err != nil {
 				return errors.Errorf("unable to stop the ec2 instance, err: %v", err)
 			}
 		}
@@ -176,7 +176,7 @@ loop:
 
 			for _, id := range instanceIDList {
 				log.Info("[Chaos]: Starting back the EC2 instance")
-				err := awslib.EC2Start(id, experimentsDetails.Region)
+				err := provider.StartInstance(id)
 				if err != nil {
 					return errors.Errorf("ec2 instance failed to start, err: %v", err)
 				}
@@ -186,7 +186,7 @@ loop:
 				//Wait for ec2 instance to come in running state
 				log.Infof("[Wait]: Wait for EC2 instance '%v' to get in running state", id)
 				experimentsDetails.Ec2InstanceID = id
-				if err := awslib.WaitForEC2Up(experimentsDetails.Timeout, experimentsDetails.Delay, experimentsDetails.ManagedNodegroup, experimentsDetails.Region, id); err != nil {
+				if err := cloud.WaitForInstanceUp(provider, experimentsDetails.Timeout, experimentsDetails.Delay, id); err != nil {
 					return errors.Errorf("unable to start the ec2 instance, err: %v", err)
 				}
 			}
@@ -209,7 +209,7 @@ loop:
 }
 
 //InstanceStatusCheckByID is used to check the instance status of all the instance under chaos.
-func InstanceStatusCheckByID(experimentsDetails *experimentTypes.ExperimentDetails) error {
+func InstanceStatusCheckByID(experimentsDetails *experimentTypes.ExperimentDetails, provider cloud.Provider) error {
 
 	instanceIDList := strings.Split(experimentsDetails.Ec2InstanceID, ",")
 	if len(instanceIDList) == 0 {
@@ -217,11 +217,11 @@ func InstanceStatusCheckByID(experimentsDetails *experimentTypes.ExperimentDetai
 	}
 	log.Infof("[Info]: The instances under chaos(IUC) are: %v", instanceIDList)
 	for _, id := range instanceIDList {
-		instanceState, err := awslib.GetEC2InstanceStatus(id, experimentsDetails.Region)
+		instanceState, err := cloud.GetInstanceStatus(provider, id)
 		if err != nil {
 			return err
 		}
-		if instanceState != "running" {
+		if instanceState != cloud.InstanceRunning {
 			return errors.Errorf("failed to get the ec2 instance '%v' status as running", id)
 		}
 	}
@@ -0,0 +1,196 @@
+package lib
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/litmuschaos/litmus-go/internal/testutil"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	cloudfake "github.com/litmuschaos/litmus-go/pkg/cloud/fake"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/kube-aws/ec2-terminate-by-id/types"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/pkg/errors"
+)
+
+// newTestDetails returns the experiment & chaos details targeting the given instances
+// the instances are stopped once, as the chaos duration and the chaos interval are zero
+func newTestDetails(instanceIDs string) (*experimentTypes.ExperimentDetails, *types.ChaosDetails) {
+
+	chaosDetails := testutil.ChaosDetails("ec2-terminate-by-id")
+	experimentsDetails := &experimentTypes.ExperimentDetails{
+		ExperimentName: chaosDetails.ExperimentName,
+		ChaosNamespace: chaosDetails.ChaosNamespace,
+		Timeout:        chaosDetails.Timeout,
+		Delay:          chaosDetails.Delay,
+		Ec2InstanceID:  instanceIDs,
+		Sequence:       "parallel",
+	}
+	return experimentsDetails, chaosDetails
+}
+
+// newTestProvider returns the fake provider with the given running instances
+func newTestProvider(ids ...string) *cloudfake.Provider {
+
+	provider := cloudfake.NewProvider()
+	for _, id := range ids {
+		provider.AddInstance(cloud.Instance{ID: id, State: cloud.InstanceRunning})
+	}
+	return provider
+}
+
+// operations returns the stop & start calls of the provider in order
+func operations(provider *cloudfake.Provider) []string {
+
+	var calls []string
+	for _, call := range provider.Calls {
+		if call != "DescribeInstances(i-1)" && call != "DescribeInstances(i-2)" {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+func TestPrepareEC2TerminateByID(t *testing.T) {
+
+	tests := []struct {
+		sequence         string
+		managedNodegroup string
+		stopTransitions  []string
+		wantCalls        []string
+		wantState        string
+		testName         string
+	}{
+		{
+			// the serial mode checks the chaos duration after every instance, hence only the first instance is targeted
+			sequence:  "serial",
+			wantCalls: []string{"StopInstance(i-1)", "StartInstance(i-1)"},
+			wantState: cloud.InstanceRunning,
+			testName:  "serial",
+		},
+		{
+			sequence:  "parallel",
+			wantCalls: []string{"StopInstance(i-1)", "StopInstance(i-2)", "StartInstance(i-1)", "StartInstance(i-2)"},
+			wantState: cloud.InstanceRunning,
+			testName:  "parallel",
+		},
+		{
+			sequence:         "parallel",
+			managedNodegroup: "enable",
+			stopTransitions:  []string{"shutting-down", cloud.InstanceTerminated},
+			wantCalls:        []string{"StopInstance(i-1)", "StopInstance(i-2)"},
+			wantState:        cloud.InstanceTerminated,
+			testName:         "managed nodegroup",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			clients, server := testutil.NewTestClientSets(t)
+			defer server.Close()
+			provider := newTestProvider("i-1", "i-2")
+			if tt.stopTransitions != nil {
+				provider.StopTransitions = tt.stopTransitions
+			}
+
+			experimentsDetails, chaosDetails := newTestDetails("i-1,i-2")
+			experimentsDetails.Sequence = tt.sequence
+			experimentsDetails.ManagedNodegroup = tt.managedNodegroup
+			if err := InstanceStatusCheckByID(experimentsDetails, provider); err != nil {
+				t.Fatalf("InstanceStatusCheckByID failed, err: %v", err)
+			}
+			if err := PrepareEC2TerminateByID(experimentsDetails, clients, provider, &types.ResultDetails{}, &types.EventDetails{}, chaosDetails); err != nil {
+				t.Fatalf("PrepareEC2TerminateByID failed, err: %v", err)
+			}
+			if got := operations(provider); !reflect.DeepEqual(got, tt.wantCalls) {
+				t.Errorf("got %v calls, want %v", got, tt.wantCalls)
+			}
+			for _, id := range []string{"i-1", "i-2"} {
+				if state, err := cloud.GetInstanceStatus(provider, id); err != nil || state != tt.wantState {
+					t.Errorf("got %v state of %v instance, err: %v, want %v", state, id, err, tt.wantState)
+				}
+			}
+		})
+	}
+}
+
+func TestPrepareEC2TerminateByIDFailure(t *testing.T) {
+
+	tests := []struct {
+		stopTransitions  []string
+		startTransitions []string
+		errors           map[string]error
+		testName         string
+	}{
+		{
+			errors:   map[string]error{"StopInstance": errors.Errorf("UnauthorizedOperation")},
+			testName: "instance fails to stop",
+		},
+		{
+			stopTransitions: []string{"stopping"},
+			testName:        "instance never stops",
+		},
+		{
+			startTransitions: []string{"pending"},
+			testName:         "instance never starts",
+		},
+		{
+			errors:   map[string]error{"DescribeInstances": errors.Errorf("RequestLimitExceeded")},
+			testName: "instance status is unknown",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			clients, server := testutil.NewTestClientSets(t)
+			defer server.Close()
+			provider := newTestProvider("i-1")
+			if tt.stopTransitions != nil {
+				provider.StopTransitions = tt.stopTransitions
+			}
+			if tt.startTransitions != nil {
+				provider.StartTransitions = tt.startTransitions
+			}
+			for operation, err := range tt.errors {
+				provider.Errors[operation] = err
+			}
+
+			experimentsDetails, chaosDetails := newTestDetails("i-1")
+			if err := PrepareEC2TerminateByID(experimentsDetails, clients, provider, &types.ResultDetails{}, &types.EventDetails{}, chaosDetails); err == nil {
+				t.Fatal("expected PrepareEC2TerminateByID to fail")
+			}
+		})
+	}
+}
+
+func TestInstanceStatusCheckByID(t *testing.T) {
+
+	provider := newTestProvider("i-1")
+	provider.AddInstance(cloud.Instance{ID: "i-2", State: cloud.InstanceStopped})
+
+	tests := []struct {
+		instanceIDs string
+		wantErr     bool
+		testName    string
+	}{
+		{
+			instanceIDs: "i-1",
+			testName:    "running instance",
+		},
+		{
+			instanceIDs: "i-1,i-2",
+			wantErr:     true,
+			testName:    "stopped instance",
+		},
+		{
+			instanceIDs: "i-3",
+			wantErr:     true,
+			testName:    "unknown instance",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			experimentsDetails, _ := newTestDetails(tt.instanceIDs)
+			if err := InstanceStatusCheckByID(experimentsDetails, provider); (err != nil) != tt.wantErr {
+				t.Errorf("got %v error, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package lib
+
+import (
+	"strings"
+	"time"
+
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	awslib "github.com/litmuschaos/litmus-go/pkg/cloud/aws"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/kube-aws/ec2-terminate-by-node/types"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/probe"
+	"github.com/litmuschaos/litmus-go/pkg/status"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/pkg/errors"
+)
+
+//PrepareEC2TerminateByNode contains the prepration and injection steps for the experiment
+func PrepareEC2TerminateByNode(experimentsDetails *experimentTypes.ExperimentDetails, targetNodes []awslib.TargetNode, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	var err error
+	//Waiting for the ramp time before chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time before injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+
+	if len(targetNodes) == 0 {
+		return errors.Errorf("no target node found to terminate")
+	}
+
+	if strings.ToLower(experimentsDetails.Sequence) == "serial" {
+		if err = InjectChaosInSerialMode(experimentsDetails, targetNodes, clients, provider, resultDetails, eventsDetails, chaosDetails); err != nil {
+			return err
+		}
+	} else {
+		if err = InjectChaosInParallelMode(experimentsDetails, targetNodes, clients, provider, resultDetails, eventsDetails, chaosDetails); err != nil {
+			return err
+		}
+	}
+	//Waiting for the ramp time after chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time after injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+	return nil
+}
+
+//InjectChaosInSerialMode will inject the ec2 instance termination of the target nodes in serial mode that is one after other
+func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetails, targetNodes []awslib.TargetNode, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	//ChaosStartTimeStamp contains the start timestamp, when the chaos injection begin
+	ChaosStartTimeStamp := time.Now().Unix()
+
+loop:
+	for {
+
+		log.Infof("Target node list, %v", targetNodes)
+
+		if experimentsDetails.EngineName != "" {
+			msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on ec2 instance"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		//PowerOff the instance
+		for _, target := range targetNodes {
+
+			if err := stopInstance(target, provider); err != nil {
+				return err
+			}
+			if err := waitForNodeDown(experimentsDetails, target, clients, provider); err != nil {
+				return err
+			}
+
+			// run the probes during chaos
+			if len(resultDetails.ProbeDetails) != 0 {
+				if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+					return err
+				}
+			}
+
+			//Wait for chaos interval
+			log.Infof("[Wait]: Waiting for chaos interval of %vs before starting the instance", experimentsDetails.ChaosInterval)
+			time.Sleep(time.Duration(experimentsDetails.ChaosInterval) * time.Second)
+
+			//Starting the EC2 instance
+			if experimentsDetails.ManagedNodegroup != "enable" {
+				if err := startInstance(target, provider); err != nil {
+					return err
+				}
+				if err := waitForNodeUp(experimentsDetails, target, clients, provider); err != nil {
+					return err
+				}
+			}
+		}
+
+		// the instances of a managed nodegroup are terminated and replaced, so they can't be targeted again
+		if experimentsDetails.ManagedNodegroup == "enable" {
+			log.Info("[Chaos]: All the target instances of the managed nodegroup are terminated")
+			break loop
+		}
+
+		//ChaosCurrentTimeStamp contains the current timestamp
+		ChaosCurrentTimeStamp := time.Now().Unix()
+
+		//ChaosDiffTimeStamp contains the difference of current timestamp and start timestamp
+		//It will helpful to track the total chaos duration
+		chaosDiffTimeStamp := ChaosCurrentTimeStamp - ChaosStartTimeStamp
+
+		if int(chaosDiffTimeStamp) >= experimentsDetails.ChaosDuration {
+			log.Infof("[Chaos]: Time is up for experiment: %v", experimentsDetails.ExperimentName)
+			break loop
+		}
+	}
+
+	return nil
+}
+
+// InjectChaosInParallelMode will inject the ec2 instance termination of the target nodes in parallel mode that is all at once
+func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDetails, targetNodes []awslib.TargetNode, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	//ChaosStartTimeStamp contains the start timestamp, when the chaos injection begin
+	ChaosStartTimeStamp := time.Now().Unix()
+
+loop:
+	for {
+
+		log.Infof("Target node list, %v", targetNodes)
+
+		if experimentsDetails.EngineName != "" {
+			msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on ec2 instance"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		//PowerOff the instance
+		for _, target := range targetNodes {
+			if err := stopInstance(target, provider); err != nil {
+				return err
+			}
+		}
+
+		for _, target := range targetNodes {
+			if err := waitForNodeDown(experimentsDetails, target, clients, provider); err != nil {
+				return err
+			}
+		}
+
+		// run the probes during chaos
+		if len(resultDetails.ProbeDetails) != 0 {
+			if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+				return err
+			}
+		}
+
+		//Wait for chaos interval
+		log.Infof("[Wait]: Waiting for chaos interval of %vs before starting the instance", experimentsDetails.ChaosInterval)
+		time.Sleep(time.Duration(experimentsDetails.ChaosInterval) * time.Second)
+
+		// the instances of a managed nodegroup are terminated and replaced, so they can't be targeted again
+		if experimentsDetails.ManagedNodegroup == "enable" {
+			log.Info("[Chaos]: All the target instances of the managed nodegroup are terminated")
+			break loop
+		}
+
+		//Starting the EC2 instance
+		for _, target := range targetNodes {
+			if err := startInstance(target, provider); err != nil {
+				return err
+			}
+		}
+
+		for _, target := range targetNodes {
+			if err := waitForNodeUp(experimentsDetails, target, clients, provider); err != nil {
+				return err
+			}
+		}
+
+		//ChaosCurrentTimeStamp contains the current timestamp
+		ChaosCurrentTimeStamp := time.Now().Unix()
+
+		//ChaosDiffTimeStamp contains the difference of current timestamp and start timestamp
+		//It will helpful to track the total chaos duration
+		chaosDiffTimeStamp := ChaosCurrentTimeStamp - ChaosStartTimeStamp
+
+		if int(chaosDiffTimeStamp) >= experimentsDetails.ChaosDuration {
+			log.Infof("[Chaos]: Time is up for experiment: %v", experimentsDetails.ExperimentName)
+			break loop
+		}
+	}
+
+	return nil
+}
+
+// stopInstance stops the ec2 instance of the target node
+func stopInstance(target awslib.TargetNode, provider cloud.Provider) error {
+
+	log.Infof("[Chaos]: Stoping the EC2 instance '%v' of %v node", target.InstanceID, target.NodeName)
+	if err := provider.StopInstance(target.InstanceID); err != nil {
+		return errors.Errorf("ec2 instance failed to stop, err: %v", err)
+	}
+	return nil
+}
+
+// waitForNodeDown waits for the ec2 instance to stop and the node to get in NotReady state
+func waitForNodeDown(experimentsDetails *experimentTypes.ExperimentDetails, target awslib.TargetNode, clients clients.ClientSets, provider cloud.Provider) error {
+
+	//Wait for ec2 instance to completely stop
+	log.Infof("[Wait]: Wait for EC2 instance '%v' to come in stopped state", target.InstanceID)
+	if err := cloud.WaitForInstanceDown(provider, experimentsDetails.Timeout, experimentsDetails.Delay, experimentsDetails.ManagedNodegroup, target.InstanceID); err != nil {
+		return errors.Errorf("unable to stop the ec2 instance, err: %v", err)
+	}
+
+	// the node object of a terminated managed nodegroup instance is removed, so its status is not checked
+	if experimentsDetails.ManagedNodegroup != "enable" {
+		log.Infof("[Wait]: Wait for %v node to get in NotReady state", target.NodeName)
+		if err := status.CheckNodeNotReadyState(target.NodeName, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
+			return errors.Errorf("%v node is not in NotReady state, err: %v", target.NodeName, err)
+		}
+	}
+	return nil
+}
+
+// startInstance starts the ec2 instance of the target node
+func startInstance(target awslib.TargetNode, provider cloud.Provider) error {
+
+	log.Infof("[Chaos]: Starting back the EC2 instance '%v' of %v node", target.InstanceID, target.NodeName)
+	if err := provider.StartInstance(target.InstanceID); err != nil {
+		return errors.Errorf("ec2 instance failed to start, err: %v", err)
+	}
+	return nil
+}
+
+// waitForNodeUp waits for the ec2 instance to start and the same node to re-register as Ready
+func waitForNodeUp(experimentsDetails *experimentTypes.ExperimentDetails, target awslib.TargetNode, clients clients.ClientSets, provider cloud.Provider) error {
+
+	//Wait for ec2 instance to come in running state
+	log.Infof("[Wait]: Wait for EC2 instance '%v' to get in running state", target.InstanceID)
+	if err := cloud.WaitForInstanceUp(provider, experimentsDetails.Timeout, experimentsDetails.Delay, target.InstanceID); err != nil {
+		return errors.Errorf("unable to start the ec2 instance, err: %v", err)
+	}
+
+	log.Infof("[Wait]: Wait for %v node to get in Ready state", target.NodeName)
+	if err := status.CheckNodeStatus(target.NodeName, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
+		return errors.Errorf("%v node is not in Ready state, err: %v", target.NodeName, err)
+	}
+	return nil
+}
+
+//GetNodeSelector builds the node selector from the experiment details
+// the nodegroup is matched through the eks nodegroup label of the nodes
+func GetNodeSelector(experimentsDetails *experimentTypes.ExperimentDetails) common.NodeSelector {
+
+	var nodeLabels []string
+	if experimentsDetails.NodeLabel != "" {
+		nodeLabels = append(nodeLabels, experimentsDetails.NodeLabel)
+	}
+	if experimentsDetails.Nodegroup != "" {
+		nodeLabels = append(nodeLabels, awslib.NodegroupLabel+"="+experimentsDetails.Nodegroup)
+	}
+	return common.NodeSelector{
+		NodeLabel:         strings.Join(nodeLabels, ","),
+		NodesAffectedPerc: experimentsDetails.NodesAffectedPerc,
+		Zone:              experimentsDetails.NodeZone,
+		AppNS:             experimentsDetails.AppNS,
+		AppLabel:          experimentsDetails.AppLabel,
+		AppNodes:          experimentsDetails.TargetAppNodes == "enable",
+	}
+}
+
+//InstanceStatusCheckByNode is used to check the instance status of all the target nodes
+func InstanceStatusCheckByNode(targetNodes []awslib.TargetNode, provider cloud.Provider) error {
+
+	log.Infof("[Info]: The nodes under chaos(NUC) are: %v", targetNodes)
+	for _, target := range targetNodes {
+		instanceState, err := cloud.GetInstanceStatus(provider, target.InstanceID)
+		if err != nil {
+			return err
+		}
+		if instanceState != cloud.InstanceRunning {
+			return errors.Errorf("failed to get the ec2 instance '%v' of %v node status as running", target.InstanceID, target.NodeName)
+		}
+	}
+	return nil
+}
+
+//PostChaosTargetNodeStatusCheck verifies that the target nodes re-registered as Ready
+//or their replacement nodes joined the cluster, if the instances belong to a managed nodegroup
+func PostChaosTargetNodeStatusCheck(experimentsDetails *experimentTypes.ExperimentDetails, targetNodes []awslib.TargetNode, clients clients.ClientSets) error {
+
+	if experimentsDetails.ManagedNodegroup == "enable" {
+		return awslib.WaitForReplacementNodes(targetNodes, GetNodeSelector(experimentsDetails), experimentsDetails.ActiveNodes, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
+	}
+
+	var nodeNames []string
+	for _, target := range targetNodes {
+		nodeNames = append(nodeNames, target.NodeName)
+	}
+	return status.CheckNodeStatus(strings.Join(nodeNames, ","), experimentsDetails.Timeout, experimentsDetails.Delay, clients)
+}
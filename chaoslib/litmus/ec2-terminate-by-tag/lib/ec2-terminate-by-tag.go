@@ -6,7 +6,7 @@ import (
 	"time"
 
 	clients "github.com/litmuschaos/litmus-go/pkg/clients"
-	awslib "github.com/litmuschaos/litmus-go/pkg/cloud/aws"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
 	"github.com/litmuschaos/litmus-go/pkg/events"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/kube-aws/ec2-terminate-by-tag/types"
 	"github.com/litmuschaos/litmus-go/pkg/log"
@@ -18,7 +18,7 @@ import (
 )
 
 //PrepareEC2TerminateByTag contains the prepration and injection steps for the experiment
-func PrepareEC2TerminateByTag(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+func PrepareEC2TerminateByTag(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
 	var err error
 	//Waiting for the ramp time before chaos injection
@@ -27,7 +27,7 @@ func PrepareEC2TerminateByTag(experimentsDetails *experimentTypes.ExperimentDeta
 		common.WaitForDuration(experimentsDetails.RampTime)
 	}
 
-	instanceIDList, err := awslib.GetInstanceList(experimentsDetails.InstanceTag, experimentsDetails.Region)
+	instanceIDList, err := provider.ListByTag(experimentsDetails.InstanceTag)
 	if err != nil {
 		return err
 	}
@@ -38,11 +38,11 @@ func PrepareEC2TerminateByTag(experimentsDetails *experimentTypes.ExperimentDeta
 	log.Infof("[Chaos]:Number of Instance targeted: %v", len(instanceIDList))
 
 	if strings.ToLower(experimentsDetails.Sequence) == "serial" {
-		if err = InjectChaosInSerialMode(experimentsDetails, instanceIDList, clients, resultDetails, eventsDetails, chaosDetails); err != nil {
+		if err = InjectChaosInSerialMode(experimentsDetails, instanceIDList, clients, provider, resultDetails, eventsDetails, chaosDetails); err != nil {
 			return err
 		}
 	} else {
-		if err = InjectChaosInParallelMode(experimentsDetails, instanceIDList, clients, resultDetails, eventsDetails, chaosDetails); err != nil {
+		if err = InjectChaosInParallelMode(experimentsDetails, instanceIDList, clients, provider, resultDetails, eventsDetails, chaosDetails); err != nil {
 			return err
 		}
 	}
@@ -55,7 +55,7 @@ func PrepareEC2TerminateByTag(experimentsDetails *experimentTypes.ExperimentDeta
 }
 
 //InjectChaosInSerialMode will inject the ce2 instance termination in serial mode that is one after other
-func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetails, instanceIDList []string, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetails, instanceIDList []string, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
 	//ChaosStartTimeStamp contains the start timestamp, when the chaos injection begin
 	ChaosStartTimeStamp := time.Now().Unix()
@@ -76,14 +76,14 @@ loop:
 
 			//Stoping the EC2 instance
 			log.Info("[Chaos]: Stoping the desired EC2 instance")
-			err := awslib.EC2Stop(id, experimentsDetails.Region)
+			err := provider.StopInstance(id)
 			if err != nil {
 				return errors.Errorf("ec2 instance failed to stop, err: %v", err)
 			}
 
 			//Wait for ec2 instance to completely stop
 			log.Infof("[Wait]: Wait for EC2 instance '%v' to come in stopped state", id)
-			if err := awslib.WaitForEC2Down(experimentsDetails.Timeout, experimentsDetails.Delay, experimentsDetails.ManagedNodegroup, experimentsDetails.Region, id); err != nil {
+			if err := cloud.WaitForInstanceDown(provider, experimentsDetails.Timeout, experimentsDetails.Delay, experimentsDetails.ManagedNodegroup, id); err != nil {
 				return errors.Errorf("unable to stop the ec2 instance, err: %v", err)
 			}
 
@@ -101,14 +101,14 @@ loop:
 			//Starting the EC2 instance
 			if experimentsDetails.ManagedNodegroup != "enable" {
 				log.Info("[Chaos]: Starting back the EC2 instance")
-				err = awslib.EC2Start(id, experimentsDetails.Region)
+				err = provider.StartInstance(id)
 				if err != nil {
 					return errors.Errorf("ec2 instance failed to start, err: %v", err)
 				}
 
 				//Wait for ec2 instance to come in running state
 				log.Infof("[Wait]: Wait for EC2 instance '%v' to get in running state", id)
-				if err := awslib.WaitForEC2Up(experimentsDetails.Timeout, experimentsDetails.Delay, experimentsDetails.ManagedNodegroup, experimentsDetails.Region, id); err != nil {
+				if err := cloud.WaitForInstanceUp(provider, experimentsDetails.Timeout, experimentsDetails.Delay, id); err != nil {
 					return errors.Errorf("unable to start the ec2 instance, err: %v", err)
 				}
 			}
@@ -132,7 +132,7 @@ loop:
 }
 
 // InjectChaosInParallelMode will inject the ce2 instance termination in parallel mode that is all at once
-func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDetails, instanceIDList []string, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDetails, instanceIDList []string, clients clients.ClientSets, provider cloud.Provider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
 	//ChaosStartTimeStamp contains the start timestamp, when the chaos injection begin
 	ChaosStartTimeStamp := time.Now().Unix()
@@ -152,7 +152,7 @@ loop:
 		for _, id := range instanceIDList {
 			//Stoping the EC2 instance
 			log.Info("[Chaos]: Stoping the desired EC2 instance")
-			err := awslib.EC2Stop(id, experimentsDetails.Region)
+			err := provider.StopInstance(id)
 			if err != nil {
 				return errors.Errorf("ec2 instance failed to stop, err: %v", err)
 			}
@@ -161,7 +161,7 @@ loop:
 		for _, id := range instanceIDList {
 			//Wait for ec2 instance to completely stop
 			log.Infof("[Wait]: Wait for EC2 instance '%v' to come in stopped state", id)
-			if err := awslib.WaitForEC2Down(experimentsDetails.Timeout, experimentsDetails.Delay, experimentsDetails.ManagedNodegroup, experimentsDetails.Region, id); err != nil {
+			if err := cloud.WaitForInstanceDown(provider, experimentsDetails.Timeout, experimentsDetails.Delay, experimentsDetails.ManagedNodegroup, id); err != nil {
 				return errors.Errorf("unable to stop the ec2 instance, err: %v", err)
 			}
 		}
@@ -182,7 +182,7 @@ loop:
 
 			for _, id := range instanceIDList {
 				log.Info("[Chaos]: Starting back the EC2 instance")
-				err := awslib.EC2Start(id, experimentsDetails.Region)
+				err := provider.StartInstance(id)
 				if err != nil {
 					return errors.Errorf("ec2 instance failed to start, err: %v", err)
 				}
@@ -191,7 +191,7 @@ loop:
 			for _, id := range instanceIDList {
 				//Wait for ec2 instance to come in running state
 				log.Infof("[Wait]: Wait for EC2 instance '%v' to get in running state", id)
-				if err := awslib.WaitForEC2Up(experimentsDetails.Timeout, experimentsDetails.Delay, experimentsDetails.ManagedNodegroup, experimentsDetails.Region, id); err != nil {
+				if err := cloud.WaitForInstanceUp(provider, experimentsDetails.Timeout, experimentsDetails.Delay, id); err != nil {
 					return errors.Errorf("unable to start the ec2 instance, err: %v", err)
 				}
 			}
@@ -231,19 +231,19 @@ func CalculateInstanceAffPerc(InstanceAffPerc int, instanceList []string) []stri
 }
 
 //InstanceStatusCheckByTag is used to check the instance status of all the instance under chaos.
-func InstanceStatusCheckByTag(instanceTag, region string) error {
+func InstanceStatusCheckByTag(instanceTag string, provider cloud.Provider) error {
 
-	instanceIDList, err := awslib.GetInstanceList(instanceTag, region)
+	instanceIDList, err := provider.ListByTag(instanceTag)
 	if err != nil {
 		return err
 	}
 	log.Infof("[Info]: The instances under chaos(IUC) are: %v", instanceIDList)
 	for _, id := range instanceIDList {
-		instanceState, err := awslib.GetEC2InstanceStatus(id, region)
+		instanceState, err := cloud.GetInstanceStatus(provider, id)
 		if err != nil {
 			return err
 		}
-		if instanceState != "running" {
+		if instanceState != cloud.InstanceRunning {
 			return errors.Errorf("failed to get the ec2 instance '%v' status as running", id)
 		}
 	}
@@ -0,0 +1,232 @@
+package lib
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/litmuschaos/litmus-go/internal/testutil"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	cloudfake "github.com/litmuschaos/litmus-go/pkg/cloud/fake"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/kube-aws/ec2-terminate-by-tag/types"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/pkg/errors"
+)
+
+// newTestDetails returns the experiment & chaos details targeting all the instances with the chaos=true tag
+// the instances are stopped once, as the chaos duration and the chaos interval are zero
+func newTestDetails() (*experimentTypes.ExperimentDetails, *types.ChaosDetails) {
+
+	chaosDetails := testutil.ChaosDetails("ec2-terminate-by-tag")
+	experimentsDetails := &experimentTypes.ExperimentDetails{
+		ExperimentName:       chaosDetails.ExperimentName,
+		ChaosNamespace:       chaosDetails.ChaosNamespace,
+		Timeout:              chaosDetails.Timeout,
+		Delay:                chaosDetails.Delay,
+		InstanceTag:          "chaos:true",
+		InstanceAffectedPerc: 100,
+		Sequence:             "parallel",
+	}
+	return experimentsDetails, chaosDetails
+}
+
+// newTestProvider returns the fake provider with the given running instances, tagged as the chaos targets
+// and a running instance which is not tagged
+func newTestProvider(ids ...string) *cloudfake.Provider {
+
+	provider := cloudfake.NewProvider()
+	for _, id := range ids {
+		provider.AddInstance(cloud.Instance{ID: id, State: cloud.InstanceRunning, Tags: map[string]string{"chaos": "true"}})
+	}
+	provider.AddInstance(cloud.Instance{ID: "i-untagged", State: cloud.InstanceRunning})
+	return provider
+}
+
+// targets returns the instances stopped & started by the chaos, along with the instances stopped only
+func targets(provider *cloudfake.Provider) ([]string, []string) {
+
+	calls := map[string]int{}
+	for _, call := range provider.Calls {
+		calls[call]++
+	}
+	var restarted, stopped []string
+	for _, id := range []string{"i-1", "i-2", "i-untagged"} {
+		switch {
+		case calls["StopInstance("+id+")"] == 1 && calls["StartInstance("+id+")"] == 1:
+			restarted = append(restarted, id)
+		case calls["StopInstance("+id+")"] == 1:
+			stopped = append(stopped, id)
+		}
+	}
+	return restarted, stopped
+}
+
+func TestPrepareEC2TerminateByTag(t *testing.T) {
+
+	tests := []struct {
+		sequence         string
+		affectedPerc     int
+		managedNodegroup string
+		wantRestarted    int
+		wantStopped      int
+		testName         string
+	}{
+		{
+			sequence:      "parallel",
+			affectedPerc:  100,
+			wantRestarted: 2,
+			testName:      "parallel",
+		},
+		{
+			// the serial mode checks the chaos duration after every instance, hence only the first instance is targeted
+			sequence:      "serial",
+			affectedPerc:  100,
+			wantRestarted: 1,
+			testName:      "serial",
+		},
+		{
+			sequence:      "parallel",
+			affectedPerc:  50,
+			wantRestarted: 1,
+			testName:      "instance affected percentage",
+		},
+		{
+			sequence:         "parallel",
+			affectedPerc:     100,
+			managedNodegroup: "enable",
+			wantStopped:      2,
+			testName:         "managed nodegroup",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			clients, server := testutil.NewTestClientSets(t)
+			defer server.Close()
+			provider := newTestProvider("i-1", "i-2")
+			if tt.managedNodegroup == "enable" {
+				provider.StopTransitions = []string{"shutting-down", cloud.InstanceTerminated}
+			}
+
+			experimentsDetails, chaosDetails := newTestDetails()
+			experimentsDetails.Sequence = tt.sequence
+			experimentsDetails.InstanceAffectedPerc = tt.affectedPerc
+			experimentsDetails.ManagedNodegroup = tt.managedNodegroup
+			if err := InstanceStatusCheckByTag(experimentsDetails.InstanceTag, provider); err != nil {
+				t.Fatalf("InstanceStatusCheckByTag failed, err: %v", err)
+			}
+			if err := PrepareEC2TerminateByTag(experimentsDetails, clients, provider, &types.ResultDetails{}, &types.EventDetails{}, chaosDetails); err != nil {
+				t.Fatalf("PrepareEC2TerminateByTag failed, err: %v", err)
+			}
+
+			restarted, stopped := targets(provider)
+			if len(restarted) != tt.wantRestarted || len(stopped) != tt.wantStopped {
+				t.Fatalf("got %v restarted & %v stopped instances, want %v & %v, calls: %v", restarted, stopped, tt.wantRestarted, tt.wantStopped, provider.Calls)
+			}
+			for _, id := range append(restarted, stopped...) {
+				if id == "i-untagged" {
+					t.Errorf("expected the untagged instance not to be targeted, calls: %v", provider.Calls)
+				}
+			}
+			if tt.managedNodegroup != "enable" {
+				if err := InstanceStatusCheckByTag(experimentsDetails.InstanceTag, provider); err != nil {
+					t.Errorf("expected the instances to be running post chaos, err: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestPrepareEC2TerminateByTagFailure(t *testing.T) {
+
+	tests := []struct {
+		instanceTag     string
+		stopTransitions []string
+		errors          map[string]error
+		testName        string
+	}{
+		{
+			instanceTag: "chaos:false",
+			testName:    "no instance with the tag",
+		},
+		{
+			instanceTag: "chaos",
+			testName:    "invalid tag",
+		},
+		{
+			instanceTag: "chaos:true",
+			errors:      map[string]error{"StartInstance": errors.Errorf("InsufficientInstanceCapacity")},
+			testName:    "instance fails to start",
+		},
+		{
+			instanceTag:     "chaos:true",
+			stopTransitions: []string{"stopping"},
+			testName:        "instance never stops",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			clients, server := testutil.NewTestClientSets(t)
+			defer server.Close()
+			provider := newTestProvider("i-1")
+			if tt.stopTransitions != nil {
+				provider.StopTransitions = tt.stopTransitions
+			}
+			for operation, err := range tt.errors {
+				provider.Errors[operation] = err
+			}
+
+			experimentsDetails, chaosDetails := newTestDetails()
+			experimentsDetails.InstanceTag = tt.instanceTag
+			if err := PrepareEC2TerminateByTag(experimentsDetails, clients, provider, &types.ResultDetails{}, &types.EventDetails{}, chaosDetails); err == nil {
+				t.Fatal("expected PrepareEC2TerminateByTag to fail")
+			}
+		})
+	}
+}
+
+func TestCalculateInstanceAffPerc(t *testing.T) {
+
+	instanceList := []string{"i-1", "i-2", "i-3", "i-4"}
+	tests := []struct {
+		affectedPerc int
+		want         int
+		testName     string
+	}{
+		{
+			affectedPerc: 0,
+			want:         1,
+			testName:     "at least one instance",
+		},
+		{
+			affectedPerc: 50,
+			want:         2,
+			testName:     "half of the instances",
+		},
+		{
+			affectedPerc: 100,
+			want:         4,
+			testName:     "all the instances",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			got := CalculateInstanceAffPerc(tt.affectedPerc, instanceList)
+			if len(got) != tt.want {
+				t.Fatalf("got %v instances, want %v", got, tt.want)
+			}
+			unique := map[string]bool{}
+			for _, id := range got {
+				unique[id] = true
+			}
+			if len(unique) != len(got) {
+				t.Errorf("got %v instances, want distinct instances", got)
+			}
+			if tt.want == len(instanceList) {
+				sort.Strings(got)
+				if !reflect.DeepEqual(got, instanceList) {
+					t.Errorf("got %v instances, want %v", got, instanceList)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package lib
+
+import (
+	"strings"
+
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/gcp/gcp-vm-instance-stop/types"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/pkg/errors"
+)
+
+// instanceKind is the kind of the target instances used in the logs and events
+const instanceKind = "vm"
+
+// PrepareVMInstanceStop contains the prepration and injection steps for the experiment
+// it runs the instance stop chaos shared by the cloud vm experiments
+func PrepareVMInstanceStop(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, provider cloud.InstanceProvider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	details := cloud.InstanceStopDetails{
+		ExperimentName:       experimentsDetails.ExperimentName,
+		EngineName:           experimentsDetails.EngineName,
+		RampTime:             experimentsDetails.RampTime,
+		ChaosDuration:        experimentsDetails.ChaosDuration,
+		ChaosInterval:        experimentsDetails.ChaosInterval,
+		Timeout:              experimentsDetails.Timeout,
+		Delay:                experimentsDetails.Delay,
+		Sequence:             experimentsDetails.Sequence,
+		InstanceAffectedPerc: experimentsDetails.InstanceAffectedPerc,
+		InstanceKind:         instanceKind,
+		// the stopped instances of a managed instance group are started back by the group itself
+		SkipStart: experimentsDetails.ManagedInstanceGroup == "enable",
+	}
+	return cloud.PrepareInstanceStop(details, experimentsDetails.TargetInstanceIDList, clients, provider, resultDetails, eventsDetails, chaosDetails)
+}
+
+// GetTargetInstances returns the ids of the target instances in zone/name format
+// the instances are selected by the label if provided, otherwise by the names and zones
+// a single zone is applied to all the instance names
+func GetTargetInstances(experimentsDetails *experimentTypes.ExperimentDetails, provider cloud.InstanceProvider) ([]string, error) {
+
+	if experimentsDetails.InstanceLabel != "" {
+		instanceIDList, err := provider.ListByTag(experimentsDetails.InstanceLabel)
+		if err != nil {
+			return nil, err
+		}
+		if len(instanceIDList) == 0 {
+			return nil, errors.Errorf("no vm instance found with %v label", experimentsDetails.InstanceLabel)
+		}
+		return instanceIDList, nil
+	}
+
+	if experimentsDetails.VMInstanceNames == "" {
+		return nil, errors.Errorf("please provide either the vm instance names or the instance label")
+	}
+	names := strings.Split(experimentsDetails.VMInstanceNames, ",")
+	zones := strings.Split(experimentsDetails.InstanceZones, ",")
+	if len(zones) != 1 && len(zones) != len(names) {
+		return nil, errors.Errorf("the number of instance zones should be one or equal to the number of vm instance names")
+	}
+
+	var instanceIDList []string
+	for i, name := range names {
+		zone := zones[0]
+		if len(zones) != 1 {
+			zone = zones[i]
+		}
+		if strings.TrimSpace(zone) == "" {
+			return nil, errors.Errorf("please provide the zone of %v vm instance", name)
+		}
+		instanceIDList = append(instanceIDList, strings.TrimSpace(zone)+"/"+strings.TrimSpace(name))
+	}
+	return instanceIDList, nil
+}
+
+// InstanceStatusCheck is used to check the instance status of all the instance under chaos
+// it stores the target instances in the experiment details
+func InstanceStatusCheck(experimentsDetails *experimentTypes.ExperimentDetails, provider cloud.InstanceProvider) error {
+
+	instanceIDList, err := GetTargetInstances(experimentsDetails, provider)
+	if err != nil {
+		return err
+	}
+	if err := cloud.InstanceStatusCheck(instanceKind, instanceIDList, provider); err != nil {
+		return err
+	}
+	experimentsDetails.TargetInstanceIDList = instanceIDList
+	return nil
+}
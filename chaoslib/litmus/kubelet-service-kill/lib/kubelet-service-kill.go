@@ -6,6 +6,7 @@ import (
 	clients "github.com/litmuschaos/litmus-go/pkg/clients"
 	"github.com/litmuschaos/litmus-go/pkg/events"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/kubelet-service-kill/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/litmuschaos/litmus-go/pkg/probe"
 	"github.com/litmuschaos/litmus-go/pkg/status"
@@ -13,8 +14,6 @@ import (
 	"github.com/litmuschaos/litmus-go/pkg/utils/common"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	apiv1 "k8s.io/api/core/v1"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // PrepareKubeletKill contains prepration steps before chaos injection
@@ -23,18 +22,17 @@ func PrepareKubeletKill(experimentsDetails *experimentTypes.ExperimentDetails, c
 	var err error
 	if experimentsDetails.TargetNode == "" {
 		//Select node for kubelet-service-kill
-		experimentsDetails.TargetNode, err = common.GetNodeName(experimentsDetails.AppNS, experimentsDetails.AppLabel, clients)
+		targetNodeList, err := common.GetTargetNodes(common.GetNodeSelector(experimentsDetails.NodeTargetDetails, experimentsDetails.AppNS, experimentsDetails.AppLabel), clients)
 		if err != nil {
 			return err
 		}
+		experimentsDetails.TargetNode = targetNodeList[0]
 	}
 
 	log.InfoWithValues("[Info]: Details of node under chaos injection", logrus.Fields{
 		"NodeName": experimentsDetails.TargetNode,
 	})
 
-	experimentsDetails.RunID = common.GetRunID()
-
 	//Waiting for the ramp time before chaos injection
 	if experimentsDetails.RampTime != 0 {
 		log.Infof("[Ramp]: Waiting for the %vs ramp time before injecting chaos", experimentsDetails.RampTime)
@@ -65,26 +63,24 @@ func PrepareKubeletKill(experimentsDetails *experimentTypes.ExperimentDetails, c
 		}
 	}
 
-	// Creating the helper pod to perform node memory hog
-	err = CreateHelperPod(experimentsDetails, clients, experimentsDetails.TargetNode)
-	if err != nil {
-		return errors.Errorf("Unable to create the helper pod, err: %v", err)
-	}
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
 
-	appLabel := "name=" + experimentsDetails.ExperimentName + "-" + experimentsDetails.RunID
+	// Creating the helper pod to perform kubelet service kill
+	if err = CreateHelperPod(experimentsDetails, manager, experimentsDetails.TargetNode); err != nil {
+		return err
+	}
 
 	//Checking the status of helper pod
-	log.Info("[Status]: Checking the status of the helper pod")
-	err = status.CheckApplicationStatus(experimentsDetails.ChaosNamespace, appLabel, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
-	if err != nil {
-		common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, chaosDetails, clients)
-		return errors.Errorf("helper pod is not in running state, err: %v", err)
+	if err = manager.WaitForRunning(); err != nil {
+		manager.Cleanup()
+		return err
 	}
+	manager.StreamLogs()
 
 	// run the probes during chaos
 	if len(resultDetails.ProbeDetails) != 0 {
 		if err = probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
-			common.DeleteAllHelperPodBasedOnJobCleanupPolicy(appLabel, chaosDetails, clients)
+			manager.Cleanup()
 			return err
 		}
 	}
@@ -93,32 +89,27 @@ func PrepareKubeletKill(experimentsDetails *experimentTypes.ExperimentDetails, c
 	log.Info("[Status]: Check for the node to be in NotReady state")
 	err = status.CheckNodeNotReadyState(experimentsDetails.TargetNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
 	if err != nil {
-		common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, chaosDetails, clients)
+		manager.Cleanup()
 		return errors.Errorf("application node is not in NotReady state, err: %v", err)
 	}
 
 	// Wait till the completion of helper pod
 	log.Infof("[Wait]: Waiting for %vs till the completion of the helper pod", experimentsDetails.ChaosDuration+30)
 
-	podStatus, err := status.WaitForCompletion(experimentsDetails.ChaosNamespace, appLabel, clients, experimentsDetails.ChaosDuration+30, experimentsDetails.ExperimentName)
-	if err != nil || podStatus == "Failed" {
-		common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, chaosDetails, clients)
-		return errors.Errorf("helper pod failed, err: %v", err)
+	if err = manager.WaitForCompletion(experimentsDetails.ChaosDuration + 30); err != nil {
+		manager.Cleanup()
+		return err
 	}
 
 	// Checking the status of target nodes
 	log.Info("[Status]: Getting the status of target nodes")
-	err = status.CheckNodeStatus(experimentsDetails.TargetNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
-	if err != nil {
-		common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, chaosDetails, clients)
+	if err = status.CheckNodeStatus(experimentsDetails.TargetNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
 		log.Warnf("Target nodes are not in the ready state, you may need to manually recover the node, err: %v", err)
 	}
 
 	//Deleting the helper pod
-	log.Info("[Cleanup]: Deleting the helper pod")
-	err = common.DeletePod(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, experimentsDetails.ChaosNamespace, chaosDetails.Timeout, chaosDetails.Delay, clients)
-	if err != nil {
-		return errors.Errorf("Unable to delete the helper pod, err: %v", err)
+	if err = manager.Delete(); err != nil {
+		return err
 	}
 
 	//Waiting for the ramp time after chaos injection
@@ -129,76 +120,30 @@ func PrepareKubeletKill(experimentsDetails *experimentTypes.ExperimentDetails, c
 	return nil
 }
 
-// CreateHelperPod derive the attributes for helper pod and create the helper pod
-func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, appNodeName string) error {
-
-	privileged := true
-	helperPod := &apiv1.Pod{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      experimentsDetails.ExperimentName + "-" + experimentsDetails.RunID,
-			Namespace: experimentsDetails.ChaosNamespace,
-			Labels: map[string]string{
-				"app":                       experimentsDetails.ExperimentName,
-				"name":                      experimentsDetails.ExperimentName + "-" + experimentsDetails.RunID,
-				"chaosUID":                  string(experimentsDetails.ChaosUID),
-				"app.kubernetes.io/part-of": "litmus",
-			},
-			Annotations: experimentsDetails.Annotations,
-		},
-		Spec: apiv1.PodSpec{
-			RestartPolicy:    apiv1.RestartPolicyNever,
-			ImagePullSecrets: experimentsDetails.ImagePullSecrets,
-			NodeName:         appNodeName,
-			Volumes: []apiv1.Volume{
-				{
-					Name: "bus",
-					VolumeSource: apiv1.VolumeSource{
-						HostPath: &apiv1.HostPathVolumeSource{
-							Path: "/var/run",
-						},
-					},
-				},
-				{
-					Name: "root",
-					VolumeSource: apiv1.VolumeSource{
-						HostPath: &apiv1.HostPathVolumeSource{
-							Path: "/",
-						},
-					},
-				},
-			},
-			Containers: []apiv1.Container{
-				{
-					Name:            experimentsDetails.ExperimentName,
-					Image:           experimentsDetails.LIBImage,
-					ImagePullPolicy: apiv1.PullPolicy(experimentsDetails.LIBImagePullPolicy),
-					Command: []string{
-						"/bin/bash",
-					},
-					Args: []string{
-						"-c",
-						"sleep 10 && systemctl stop kubelet && sleep " + strconv.Itoa(experimentsDetails.ChaosDuration) + " && systemctl start kubelet",
-					},
-					Resources: experimentsDetails.Resources,
-					VolumeMounts: []apiv1.VolumeMount{
-						{
-							Name:      "bus",
-							MountPath: "/var/run",
-						},
-						{
-							Name:      "root",
-							MountPath: "/node",
-						},
-					},
-					SecurityContext: &apiv1.SecurityContext{
-						Privileged: &privileged,
-					},
-					TTY: true,
-				},
-			},
-		},
-	}
+// GetHelperManager returns the manager of the helper pods
+func GetHelperManager(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, chaosDetails *types.ChaosDetails) *helper.Manager {
+	return helper.NewManager(helper.Meta{
+		ExperimentName:  experimentsDetails.ExperimentName,
+		Namespace:       experimentsDetails.ChaosNamespace,
+		ChaosUID:        experimentsDetails.ChaosUID,
+		Image:           experimentsDetails.LIBImage,
+		ImagePullPolicy: experimentsDetails.LIBImagePullPolicy,
+	}, clients, chaosDetails)
+}
 
-	_, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaosNamespace).Create(helperPod)
-	return err
+// CreateHelperPod derive the attributes for helper pod and create the helper pod
+func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, manager *helper.Manager, appNodeName string) error {
+
+	return manager.Create(manager.NewPod(
+		helper.WithNodeName(appNodeName),
+		helper.WithAnnotations(experimentsDetails.Annotations),
+		helper.WithImagePullSecrets(experimentsDetails.ImagePullSecrets),
+		helper.WithResources(experimentsDetails.Resources),
+		helper.WithCommand([]string{"/bin/bash"}, "-c", "sleep 10 && systemctl stop kubelet && sleep "+strconv.Itoa(experimentsDetails.ChaosDuration)+" && systemctl start kubelet"),
+		helper.WithHostPath("bus", "/var/run", "/var/run", nil),
+		helper.WithHostPath("root", "/", "/node", nil),
+		// systemctl manages the kubelet of the node via the host dbus, which is allowed only to the privileged containers
+		helper.WithPrivileged(),
+	))
 }
+
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
@@ -15,12 +14,11 @@ import (
 	"github.com/litmuschaos/litmus-go/pkg/events"
 	experimentEnv "github.com/litmuschaos/litmus-go/pkg/generic/network-chaos/environment"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/network-chaos/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/litmuschaos/litmus-go/pkg/result"
 	"github.com/litmuschaos/litmus-go/pkg/types"
 	"github.com/litmuschaos/litmus-go/pkg/utils/common"
-	"github.com/pkg/errors"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clientTypes "k8s.io/apimachinery/pkg/types"
 )
 
@@ -70,6 +68,7 @@ func main() {
 
 	err := PreparePodNetworkChaos(&experimentsDetails, clients, &eventsDetails, &chaosDetails, &resultDetails)
 	if err != nil {
+		helper.WriteTerminationMessage(err)
 		log.Fatalf("helper pod failed, err: %v", err)
 	}
 
@@ -78,30 +77,38 @@ func main() {
 //PreparePodNetworkChaos contains the prepration steps before chaos injection
 func PreparePodNetworkChaos(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails, resultDetails *types.ResultDetails) error {
 
-	containerID, err := GetContainerID(experimentsDetails, clients)
-	if err != nil {
-		return err
-	}
-	// extract out the pid of the target container
-	targetPID, err := GetPID(experimentsDetails, containerID)
-	if err != nil {
-		return err
+	// the helper runs with the host pid namespace, so pid 1 refers to the init process of the node
+	// and its network namespace is the host network namespace
+	targetPID := 1
+	target := "node"
+	if experimentsDetails.NetworkNamespace != "host" {
+		containerID, err := common.GetContainerID(experimentsDetails.AppNS, experimentsDetails.TargetPods, experimentsDetails.TargetContainer, experimentsDetails.ContainerRuntime, experimentsDetails.SocketPath, clients)
+		if err != nil {
+			return err
+		}
+		// extract out the pid of the target container
+		targetPID, err = common.GetPID(experimentsDetails.ContainerRuntime, containerID, experimentsDetails.SocketPath)
+		if err != nil {
+			return err
+		}
+		target = "application pod"
 	}
 
 	// record the event inside chaosengine
 	if experimentsDetails.EngineName != "" {
-		msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on application pod"
+		msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on " + target
 		types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
 		events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
 	}
 
 	// watching for the abort signal and revert the chaos
-	go abortWatcher(targetPID)
+	go abortWatcher(targetPID, experimentsDetails.NetworkInterface)
 
 	// injecting network chaos inside target container
 	if err = InjectChaos(experimentsDetails, targetPID); err != nil {
 		return err
 	}
+	helper.RecordInjected("netem " + os.Getenv("NETEM_COMMAND") + " on " + experimentsDetails.NetworkInterface + " interface of " + target)
 
 	log.Infof("[Chaos]: Waiting for %vs", experimentsDetails.ChaosDuration)
 
@@ -110,177 +117,13 @@ func PreparePodNetworkChaos(experimentsDetails *experimentTypes.ExperimentDetail
 	log.Info("[Chaos]: Stopping the experiment")
 
 	// cleaning the netem process after chaos injection
-	if err = Killnetem(targetPID); err != nil {
+	if err = Killnetem(targetPID, experimentsDetails.NetworkInterface); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-//GetContainerID extract out the container id of the target container
-func GetContainerID(experimentDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (string, error) {
-
-	var containerID string
-	switch experimentDetails.ContainerRuntime {
-	case "docker":
-		host := "unix://" + experimentDetails.SocketPath
-		// deriving the container id of the pause container
-		cmd := "sudo docker --host " + host + " ps | grep k8s_POD_" + experimentDetails.TargetPods + "_" + experimentDetails.AppNS + " | awk '{print $1}'"
-		out, err := exec.Command("/bin/sh", "-c", cmd).CombinedOutput()
-		if err != nil {
-			log.Error(fmt.Sprintf("[docker]: Failed to run docker ps command: %s", string(out)))
-			return "", err
-		}
-		containerID = strings.TrimSpace(string(out))
-	case "containerd", "crio":
-		pod, err := clients.KubeClient.CoreV1().Pods(experimentDetails.AppNS).Get(experimentDetails.TargetPods, v1.GetOptions{})
-		if err != nil {
-			return "", err
-		}
-		// filtering out the container id from the details of containers inside containerStatuses of the given pod
-		// container id is present in the form of <runtime>://<container-id>
-		for _, container := range pod.Status.ContainerStatuses {
-			if container.Name == experimentDetails.TargetContainer {
-				containerID = strings.Split(container.ContainerID, "//")[1]
-				break
-			}
-		}
-	default:
-		return "", errors.Errorf("%v container runtime not suported", experimentDetails.ContainerRuntime)
-	}
-	log.Infof("containerid: %v", containerID)
-
-	return containerID, nil
-}
-
-//GetPID extract out the PID of the target container
-func GetPID(experimentDetails *experimentTypes.ExperimentDetails, containerID string) (int, error) {
-	var PID int
-
-	switch experimentDetails.ContainerRuntime {
-	case "docker":
-		host := "unix://" + experimentDetails.SocketPath
-		// deriving pid from the inspect out of target container
-		out, err := exec.Command("sudo", "docker", "--host", host, "inspect", containerID).CombinedOutput()
-		if err != nil {
-			log.Error(fmt.Sprintf("[docker]: Failed to run docker inspect: %s", string(out)))
-			return 0, err
-		}
-		// parsing data from the json output of inspect command
-		PID, err = parsePIDFromJSON(out, experimentDetails.ContainerRuntime)
-		if err != nil {
-			log.Error(fmt.Sprintf("[docker]: Failed to parse json from docker inspect output: %s", string(out)))
-			return 0, err
-		}
-	case "containerd", "crio":
-		// deriving pid from the inspect out of target container
-		endpoint := "unix://" + experimentDetails.SocketPath
-		out, err := exec.Command("sudo", "crictl", "-i", endpoint, "-r", endpoint, "inspect", containerID).CombinedOutput()
-		if err != nil {
-			log.Error(fmt.Sprintf("[cri]: Failed to run crictl: %s", string(out)))
-			return 0, err
-		}
-		// parsing data from the json output of inspect command
-		PID, err = parsePIDFromJSON(out, experimentDetails.ContainerRuntime)
-		if err != nil {
-			log.Errorf(fmt.Sprintf("[cri]: Failed to parse json from crictl output: %s", string(out)))
-			return 0, err
-		}
-	default:
-		return 0, errors.Errorf("%v container runtime not suported", experimentDetails.ContainerRuntime)
-	}
-
-	log.Info(fmt.Sprintf("[cri]: Container ID=%s has process PID=%d", containerID, PID))
-
-	return PID, nil
-}
-
-// CrictlInspectResponse JSON representation of crictl inspect command output
-// in crio, pid is present inside pid attribute of inspect output
-// in containerd, pid is present inside `info.pid` of inspect output
-type CrictlInspectResponse struct {
-	Info InfoDetails `json:"info"`
-}
-
-// InfoDetails JSON representation of crictl inspect command output
-type InfoDetails struct {
-	RuntimeSpec RuntimeDetails `json:"runtimeSpec"`
-	PID         int            `json:"pid"`
-}
-
-// RuntimeDetails contains runtime details
-type RuntimeDetails struct {
-	Linux LinuxAttributes `json:"linux"`
-}
-
-// LinuxAttributes contains all the linux attributes
-type LinuxAttributes struct {
-	Namespaces []Namespace `json:"namespaces"`
-}
-
-// Namespace contains linux namespace details
-type Namespace struct {
-	Type string `json:"type"`
-	Path string `json:"path"`
-}
-
-// DockerInspectResponse JSON representation of docker inspect command output
-type DockerInspectResponse struct {
-	State StateDetails `json:"state"`
-}
-
-// StateDetails JSON representation of docker inspect command output
-type StateDetails struct {
-	PID int `json:"pid"`
-}
-
-//parsePIDFromJSON extract the pid from the json output
-func parsePIDFromJSON(j []byte, runtime string) (int, error) {
-	var pid int
-	// namespaces are present inside `info.runtimeSpec.linux.namespaces` of inspect output
-	// linux namespace of type network contains pid, in the form of `/proc/<pid>/ns/net`
-	switch runtime {
-	case "docker":
-		// in docker, pid is present inside state.pid attribute of inspect output
-		var resp []DockerInspectResponse
-		if err := json.Unmarshal(j, &resp); err != nil {
-			return 0, err
-		}
-		pid = resp[0].State.PID
-	case "containerd":
-		var resp CrictlInspectResponse
-		if err := json.Unmarshal(j, &resp); err != nil {
-			return 0, err
-		}
-		for _, namespace := range resp.Info.RuntimeSpec.Linux.Namespaces {
-			if namespace.Type == "network" {
-				value := strings.Split(namespace.Path, "/")[2]
-				pid, _ = strconv.Atoi(value)
-			}
-		}
-	case "crio":
-		var info InfoDetails
-		if err := json.Unmarshal(j, &info); err != nil {
-			return 0, err
-		}
-		pid = info.PID
-		if pid == 0 {
-			var resp CrictlInspectResponse
-			if err := json.Unmarshal(j, &resp); err != nil {
-				return 0, err
-			}
-			pid = resp.Info.PID
-		}
-	default:
-		return 0, errors.Errorf("[cri]: No supported container runtime, runtime: %v", runtime)
-	}
-	if pid == 0 {
-		return 0, errors.Errorf("[cri]: No running target container found, pid: %d", pid)
-	}
-
-	return pid, nil
-}
-
 // InjectChaos inject the network chaos in target container
 // it is using nsenter command to enter into network namespace of target container
 // and execute the netem command inside it.
@@ -288,13 +131,35 @@ func InjectChaos(experimentDetails *experimentTypes.ExperimentDetails, pid int)
 
 	netemCommands := os.Getenv("NETEM_COMMAND")
 	destinationIPs := os.Getenv("DESTINATION_IPS")
+	excludedIPs := os.Getenv("EXCLUDED_IPS")
 
 	select {
 	case <-inject:
 		// stopping the chaos execution, if abort signal recieved
-		os.Exit(1)
+		helper.ExitAborted()
 	default:
-		if destinationIPs == "" {
+		if destinationIPs == "" && excludedIPs != "" {
+
+			// Create a priority-based queue with 4 bands and send all the traffic through the 1:4 class
+			// the excluded ips are redirected to the 1:1 class, which doesn't have netem
+			priority := fmt.Sprintf("sudo nsenter -t %v -n tc qdisc replace dev %v root handle 1: prio bands 4 priomap 3 3 3 3 3 3 3 3 3 3 3 3 3 3 3 3", pid, experimentDetails.NetworkInterface)
+			cmd := exec.Command("/bin/bash", "-c", priority)
+			out, err := cmd.CombinedOutput()
+			log.Info(cmd.String())
+			if err != nil {
+				log.Error(string(out))
+				return err
+			}
+
+			traffic := fmt.Sprintf("sudo nsenter -t %v -n tc qdisc replace dev %v parent 1:4 netem %v", pid, experimentDetails.NetworkInterface, netemCommands)
+			cmd = exec.Command("/bin/bash", "-c", traffic)
+			out, err = cmd.CombinedOutput()
+			log.Info(cmd.String())
+			if err != nil {
+				log.Error(string(out))
+				return err
+			}
+		} else if destinationIPs == "" {
 			tc := fmt.Sprintf("sudo nsenter -t %d -n tc qdisc replace dev %s root netem %v", pid, experimentDetails.NetworkInterface, netemCommands)
 			cmd := exec.Command("/bin/bash", "-c", tc)
 			out, err := cmd.CombinedOutput()
@@ -360,14 +225,41 @@ func InjectChaos(experimentDetails *experimentTypes.ExperimentDetails, pid int)
 				}
 			}
 		}
+
+		if excludedIPs != "" {
+			if err := excludeIPs(pid, experimentDetails.NetworkInterface, excludedIPs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// excludeIPs redirect the traffic for the excluded ips through the 1:1 class, bypassing the netem
+// the filters are added with a higher priority than the destination ips filters
+func excludeIPs(pid int, networkInterface, excludedIPs string) error {
+
+	for _, ip := range strings.Split(excludedIPs, ",") {
+		// It allows ipv4 addresses only
+		if ip == "" || strings.Contains(ip, ":") {
+			continue
+		}
+		tc := fmt.Sprintf("sudo nsenter -t %v -n tc filter add dev %v protocol ip parent 1:0 prio 1 u32 match ip dst %v flowid 1:1", pid, networkInterface, ip)
+		cmd := exec.Command("/bin/bash", "-c", tc)
+		out, err := cmd.CombinedOutput()
+		log.Info(cmd.String())
+		if err != nil {
+			log.Error(string(out))
+			return err
+		}
 	}
 	return nil
 }
 
 // Killnetem kill the netem process for all the target containers
-func Killnetem(PID int) error {
+func Killnetem(PID int, networkInterface string) error {
 
-	tc := fmt.Sprintf("sudo nsenter -t %d -n tc qdisc delete dev eth0 root", PID)
+	tc := fmt.Sprintf("sudo nsenter -t %d -n tc qdisc delete dev %s root", PID, networkInterface)
 	cmd := exec.Command("/bin/bash", "-c", tc)
 	out, err := cmd.CombinedOutput()
 	log.Info(cmd.String())
@@ -381,6 +273,7 @@ func Killnetem(PID int) error {
 		}
 		return err
 	}
+	helper.RecordReverted("netem on " + networkInterface + " interface of " + strconv.Itoa(PID) + " pid")
 
 	return nil
 }
@@ -401,6 +294,8 @@ func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
 	experimentDetails.NetworkInterface = Getenv("NETWORK_INTERFACE", "eth0")
 	experimentDetails.SocketPath = Getenv("SOCKET_PATH", "")
 	experimentDetails.DestinationIPs = Getenv("DESTINATION_IPS", "")
+	experimentDetails.ExcludedIPs = Getenv("EXCLUDED_IPS", "")
+	experimentDetails.NetworkNamespace = Getenv("NETWORK_NAMESPACE", "pod")
 }
 
 // Getenv fetch the env and set the default value, if any
@@ -413,7 +308,7 @@ func Getenv(key string, defaultValue string) string {
 }
 
 // abortWatcher continuosly watch for the abort signals
-func abortWatcher(targetPID int) {
+func abortWatcher(targetPID int, networkInterface string) {
 
 	for {
 		select {
@@ -423,14 +318,14 @@ func abortWatcher(targetPID int) {
 			// retry thrice for the chaos revert
 			retry := 3
 			for retry > 0 {
-				if err = Killnetem(targetPID); err != nil {
+				if err = Killnetem(targetPID, networkInterface); err != nil {
 					log.Errorf("unable to kill netem process, err :%v", err)
 				}
 				retry--
 				time.Sleep(1 * time.Second)
 			}
 			log.Info("Chaos Revert Completed")
-			os.Exit(1)
+			helper.ExitAborted()
 		}
 	}
 }
@@ -7,9 +7,9 @@ import (
 
 	clients "github.com/litmuschaos/litmus-go/pkg/clients"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/network-chaos/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/litmuschaos/litmus-go/pkg/probe"
-	"github.com/litmuschaos/litmus-go/pkg/status"
 	"github.com/litmuschaos/litmus-go/pkg/types"
 	"github.com/litmuschaos/litmus-go/pkg/utils/common"
 	"github.com/pkg/errors"
@@ -99,7 +99,7 @@ func PrepareAndInjectChaos(experimentsDetails *experimentTypes.ExperimentDetails
 // InjectChaosInSerialMode inject the network chaos in all target application serially (one by one)
 func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetails, targetPodList apiv1.PodList, clients clients.ClientSets, chaosDetails *types.ChaosDetails, args string, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails) error {
 
-	labelSuffix := common.GetRunID()
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
 
 	// run the probes during chaos
 	if len(resultDetails.ProbeDetails) != 0 {
@@ -116,36 +116,14 @@ func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetai
 			"NodeName":      pod.Spec.NodeName,
 			"ContainerName": experimentsDetails.TargetContainer,
 		})
-		runID := common.GetRunID()
-		err = CreateHelperPod(experimentsDetails, clients, pod.Name, pod.Spec.NodeName, runID, args, labelSuffix)
-		if err != nil {
-			return errors.Errorf("Unable to create the helper pod, err: %v", err)
-		}
-
-		appLabel := "name=" + experimentsDetails.ExperimentName + "-" + runID
-
-		//checking the status of the helper pods, wait till the pod comes to running state else fail the experiment
-		log.Info("[Status]: Checking the status of the helper pods")
-		err = status.CheckApplicationStatus(experimentsDetails.ChaosNamespace, appLabel, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
-		if err != nil {
-			common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+runID, appLabel, chaosDetails, clients)
-			return errors.Errorf("helper pods are not in running state, err: %v", err)
+		if err = CreateHelperPod(experimentsDetails, manager, pod.Name, pod.Spec.NodeName, args); err != nil {
+			return err
 		}
 
-		// Wait till the completion of the helper pod
+		// wait till the completion of the helper pod and delete it afterwards
 		// set an upper limit for the waiting time
-		log.Info("[Wait]: waiting till the completion of the helper pod")
-		podStatus, err := status.WaitForCompletion(experimentsDetails.ChaosNamespace, appLabel, clients, experimentsDetails.ChaosDuration+60, experimentsDetails.ExperimentName)
-		if err != nil || podStatus == "Failed" {
-			common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+runID, appLabel, chaosDetails, clients)
-			return errors.Errorf("helper pod failed due to, err: %v", err)
-		}
-
-		//Deleting all the helper pod for container-kill chaos
-		log.Info("[Cleanup]: Deleting the the helper pod")
-		err = common.DeletePod(experimentsDetails.ExperimentName+"-"+runID, appLabel, experimentsDetails.ChaosNamespace, chaosDetails.Timeout, chaosDetails.Delay, clients)
-		if err != nil {
-			return errors.Errorf("Unable to delete the helper pods, err: %v", err)
+		if err = manager.Run(experimentsDetails.ChaosDuration + 60); err != nil {
+			return err
 		}
 	}
 
@@ -155,7 +133,7 @@ func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetai
 // InjectChaosInParallelMode inject the network chaos in all target application in parallel mode (all at once)
 func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDetails, targetPodList apiv1.PodList, clients clients.ClientSets, chaosDetails *types.ChaosDetails, args string, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails) error {
 
-	labelSuffix := common.GetRunID()
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
 
 	// run the probes during chaos
 	if len(resultDetails.ProbeDetails) != 0 {
@@ -172,40 +150,14 @@ func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDet
 			"NodeName":      pod.Spec.NodeName,
 			"ContainerName": experimentsDetails.TargetContainer,
 		})
-		runID := common.GetRunID()
-		err = CreateHelperPod(experimentsDetails, clients, pod.Name, pod.Spec.NodeName, runID, args, labelSuffix)
-		if err != nil {
-			return errors.Errorf("Unable to create the helper pod, err: %v", err)
+		if err = CreateHelperPod(experimentsDetails, manager, pod.Name, pod.Spec.NodeName, args); err != nil {
+			return err
 		}
 	}
 
-	appLabel := "app=" + experimentsDetails.ExperimentName + "-helper-" + labelSuffix
-
-	//checking the status of the helper pods, wait till the pod comes to running state else fail the experiment
-	log.Info("[Status]: Checking the status of the helper pods")
-	err = status.CheckApplicationStatus(experimentsDetails.ChaosNamespace, appLabel, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
-	if err != nil {
-		common.DeleteAllHelperPodBasedOnJobCleanupPolicy(appLabel, chaosDetails, clients)
-		return errors.Errorf("helper pods are not in running state, err: %v", err)
-	}
-
-	// Wait till the completion of the helper pod
+	// wait till the completion of the helper pods and delete them afterwards
 	// set an upper limit for the waiting time
-	log.Info("[Wait]: waiting till the completion of the helper pod")
-	podStatus, err := status.WaitForCompletion(experimentsDetails.ChaosNamespace, appLabel, clients, experimentsDetails.ChaosDuration+60, experimentsDetails.ExperimentName)
-	if err != nil || podStatus == "Failed" {
-		common.DeleteAllHelperPodBasedOnJobCleanupPolicy(appLabel, chaosDetails, clients)
-		return errors.Errorf("helper pod failed due to, err: %v", err)
-	}
-
-	//Deleting all the helper pod for container-kill chaos
-	log.Info("[Cleanup]: Deleting all the helper pod")
-	err = common.DeleteAllPod(appLabel, experimentsDetails.ChaosNamespace, chaosDetails.Timeout, chaosDetails.Delay, clients)
-	if err != nil {
-		return errors.Errorf("Unable to delete the helper pods, err: %v", err)
-	}
-
-	return nil
+	return manager.Run(experimentsDetails.ChaosDuration + 60)
 }
 
 // GetServiceAccount find the serviceAccountName for the helper pod
@@ -229,86 +181,46 @@ func GetTargetContainer(experimentsDetails *experimentTypes.ExperimentDetails, a
 	return pod.Spec.Containers[0].Name, nil
 }
 
+// GetHelperManager returns the manager of the helper pods
+func GetHelperManager(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, chaosDetails *types.ChaosDetails) *helper.Manager {
+	return helper.NewManager(helper.Meta{
+		ExperimentName:  experimentsDetails.ExperimentName,
+		Namespace:       experimentsDetails.ChaosNamespace,
+		ChaosUID:        experimentsDetails.ChaosUID,
+		Image:           experimentsDetails.LIBImage,
+		ImagePullPolicy: experimentsDetails.LIBImagePullPolicy,
+	}, clients, chaosDetails)
+}
+
 // CreateHelperPod derive the attributes for helper pod and create the helper pod
-func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, podName, nodeName, runID, args, labelSuffix string) error {
-
-	privilegedEnable := true
-	terminationGracePeriodSeconds := int64(experimentsDetails.TerminationGracePeriodSeconds)
-
-	helperPod := &apiv1.Pod{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      experimentsDetails.ExperimentName + "-" + runID,
-			Namespace: experimentsDetails.ChaosNamespace,
-			Labels: map[string]string{
-				"app":                       experimentsDetails.ExperimentName + "-helper-" + labelSuffix,
-				"name":                      experimentsDetails.ExperimentName + "-" + runID,
-				"chaosUID":                  string(experimentsDetails.ChaosUID),
-				"app.kubernetes.io/part-of": "litmus",
-			},
-			Annotations: experimentsDetails.Annotations,
-		},
-		Spec: apiv1.PodSpec{
-			HostPID:                       true,
-			TerminationGracePeriodSeconds: &terminationGracePeriodSeconds,
-			ImagePullSecrets:              experimentsDetails.ImagePullSecrets,
-			ServiceAccountName:            experimentsDetails.ChaosServiceAccount,
-			RestartPolicy:                 apiv1.RestartPolicyNever,
-			NodeName:                      nodeName,
-			Volumes: []apiv1.Volume{
-				{
-					Name: "cri-socket",
-					VolumeSource: apiv1.VolumeSource{
-						HostPath: &apiv1.HostPathVolumeSource{
-							Path: experimentsDetails.SocketPath,
-						},
-					},
-				},
-			},
-
-			Containers: []apiv1.Container{
-				{
-					Name:            experimentsDetails.ExperimentName,
-					Image:           experimentsDetails.LIBImage,
-					ImagePullPolicy: apiv1.PullPolicy(experimentsDetails.LIBImagePullPolicy),
-					Command: []string{
-						"/bin/bash",
-					},
-					Args: []string{
-						"-c",
-						"./helper/network-chaos",
-					},
-					Resources: experimentsDetails.Resources,
-					Env:       GetPodEnv(experimentsDetails, podName, args),
-					VolumeMounts: []apiv1.VolumeMount{
-						{
-							Name:      "cri-socket",
-							MountPath: experimentsDetails.SocketPath,
-						},
-					},
-					SecurityContext: &apiv1.SecurityContext{
-						Privileged: &privilegedEnable,
-						Capabilities: &apiv1.Capabilities{
-							Add: []apiv1.Capability{
-								"NET_ADMIN",
-								"SYS_ADMIN",
-							},
-						},
-					},
-				},
-			},
-		},
+func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, manager *helper.Manager, podName, nodeName, args string) error {
+
+	opts := []helper.Option{
+		helper.WithNodeName(nodeName),
+		helper.WithHostPID(),
+		helper.WithServiceAccount(experimentsDetails.ChaosServiceAccount),
+		helper.WithTerminationGracePeriod(experimentsDetails.TerminationGracePeriodSeconds),
+		helper.WithAnnotations(experimentsDetails.Annotations),
+		helper.WithImagePullSecrets(experimentsDetails.ImagePullSecrets),
+		helper.WithResources(experimentsDetails.Resources),
+		helper.WithCommand([]string{"/bin/bash"}, "-c", "./helper/network-chaos"),
+		helper.WithEnv(GetPodEnv(experimentsDetails, podName, args)),
+		helper.WithPodNameEnv(),
+		helper.WithSocketMount(experimentsDetails.SocketPath),
+		// nsenter into the network namespace of the target container needs SYS_ADMIN & SYS_PTRACE, tc needs NET_ADMIN
+		helper.WithCapabilities("NET_ADMIN", "SYS_ADMIN", "SYS_PTRACE"),
 	}
-
-	_, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaosNamespace).Create(helperPod)
-	return err
-
+	// the crio socket is labeled by selinux, it is accessible only from the privileged containers
+	if experimentsDetails.ContainerRuntime == "crio" {
+		opts = append(opts, helper.WithPrivileged())
+	}
+	return manager.Create(manager.NewPod(opts...))
 }
 
 // GetPodEnv derive all the env required for the helper pod
-func GetPodEnv(experimentsDetails *experimentTypes.ExperimentDetails, podName, args string) []apiv1.EnvVar {
+func GetPodEnv(experimentsDetails *experimentTypes.ExperimentDetails, podName, args string) map[string]string {
 
-	var envVar []apiv1.EnvVar
-	ENVList := map[string]string{
+	return map[string]string{
 		"APP_NS":               experimentsDetails.AppNS,
 		"APP_POD":              podName,
 		"APP_CONTAINER":        experimentsDetails.TargetContainer,
@@ -323,31 +235,6 @@ func GetPodEnv(experimentsDetails *experimentTypes.ExperimentDetails, podName, a
 		"SOCKET_PATH":          experimentsDetails.SocketPath,
 		"DESTINATION_IPS":      experimentsDetails.DestinationIPs,
 	}
-	for key, value := range ENVList {
-		var perEnv apiv1.EnvVar
-		perEnv.Name = key
-		perEnv.Value = value
-		envVar = append(envVar, perEnv)
-	}
-	// Getting experiment pod name from downward API
-	experimentPodName := GetValueFromDownwardAPI("v1", "metadata.name")
-	var downwardEnv apiv1.EnvVar
-	downwardEnv.Name = "POD_NAME"
-	downwardEnv.ValueFrom = &experimentPodName
-	envVar = append(envVar, downwardEnv)
-
-	return envVar
-}
-
-// GetValueFromDownwardAPI returns the value from downwardApi
-func GetValueFromDownwardAPI(apiVersion string, fieldPath string) apiv1.EnvVarSource {
-	downwardENV := apiv1.EnvVarSource{
-		FieldRef: &apiv1.ObjectFieldSelector{
-			APIVersion: apiVersion,
-			FieldPath:  fieldPath,
-		},
-	}
-	return downwardENV
 }
 
 // GetTargetIps return the comma separated target ips
@@ -6,6 +6,7 @@ import (
 	clients "github.com/litmuschaos/litmus-go/pkg/clients"
 	"github.com/litmuschaos/litmus-go/pkg/events"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/node-cpu-hog/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/litmuschaos/litmus-go/pkg/probe"
 	"github.com/litmuschaos/litmus-go/pkg/status"
@@ -13,7 +14,6 @@ import (
 	"github.com/litmuschaos/litmus-go/pkg/utils/common"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	apiv1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -29,7 +29,7 @@ func PrepareNodeCPUHog(experimentsDetails *experimentTypes.ExperimentDetails, cl
 	}
 
 	//Select node for node-cpu-hog
-	targetNodeList, err := common.GetNodeList(experimentsDetails.TargetNodes, experimentsDetails.NodesAffectedPerc, clients)
+	targetNodeList, err := common.GetTargetNodes(common.GetNodeSelector(experimentsDetails.NodeTargetDetails, experimentsDetails.AppNS, experimentsDetails.AppLabel), clients)
 	if err != nil {
 		return err
 	}
@@ -54,6 +54,13 @@ func PrepareNodeCPUHog(experimentsDetails *experimentTypes.ExperimentDetails, cl
 		if err != nil {
 			return errors.Errorf("Unable to get imagePullSecrets, err: %v", err)
 		}
+		// Get the serviceAccountName, the closed loop helper reads the usage of the target node
+		if experimentsDetails.TargetCPUUtilisation != 0 {
+			experimentsDetails.ChaosServiceAccount, err = GetServiceAccount(experimentsDetails, clients)
+			if err != nil {
+				return errors.Errorf("Unable to get the serviceAccountName, err: %v", err)
+			}
+		}
 	}
 
 	if experimentsDetails.Sequence == "serial" {
@@ -78,7 +85,7 @@ func PrepareNodeCPUHog(experimentsDetails *experimentTypes.ExperimentDetails, cl
 func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetails, targetNodeList []string, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
 	nodeCPUCores := experimentsDetails.NodeCPUcores
-	labelSuffix := common.GetRunID()
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
 
 	// run the probes during chaos
 	if len(resultDetails.ProbeDetails) != 0 {
@@ -87,6 +94,9 @@ func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetai
 		}
 	}
 
+	// utilisation of the target nodes recorded during the chaos, for the closed loop stress
+	samples := map[string][]common.UtilisationSample{}
+
 	for _, appNode := range targetNodeList {
 
 		if experimentsDetails.EngineName != "" {
@@ -108,46 +118,48 @@ func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetai
 			"NodeCPUcores": experimentsDetails.NodeCPUcores,
 		})
 
-		experimentsDetails.RunID = common.GetRunID()
-
 		// Creating the helper pod to perform node cpu hog
-		err = CreateHelperPod(experimentsDetails, appNode, clients, labelSuffix)
-		if err != nil {
-			return errors.Errorf("Unable to create the helper pod, err: %v", err)
+		if err = CreateHelperPod(experimentsDetails, manager, appNode); err != nil {
+			return err
 		}
 
-		appLabel := "name=" + experimentsDetails.ExperimentName + "-" + experimentsDetails.RunID
-
 		//Checking the status of helper pod
-		log.Info("[Status]: Checking the status of the helper pod")
-		err = status.CheckApplicationStatus(experimentsDetails.ChaosNamespace, appLabel, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
-		if err != nil {
-			common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, chaosDetails, clients)
-			return errors.Errorf("helper pod is not in running state, err: %v", err)
+		if err = manager.WaitForRunning(); err != nil {
+			manager.Cleanup()
+			return err
 		}
+		manager.StreamLogs()
 
 		// Wait till the completion of helper pod
 		log.Infof("[Wait]: Waiting for %vs till the completion of the helper pod", experimentsDetails.ChaosDuration+30)
 
-		podStatus, err := status.WaitForCompletion(experimentsDetails.ChaosNamespace, appLabel, clients, experimentsDetails.ChaosDuration+30, experimentsDetails.ExperimentName)
-		if err != nil || podStatus == "Failed" {
-			common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, chaosDetails, clients)
-			return errors.Errorf("helper pod failed due to, err: %v", err)
+		// record the utilisation of the target node during the chaos, for the closed loop stress
+		var recorder *common.UtilisationRecorder
+		if experimentsDetails.TargetCPUUtilisation != 0 {
+			recorder = common.StartUtilisationRecorder([]string{appNode}, "cpu", experimentsDetails.MetricsSource, experimentsDetails.ControlInterval, clients)
+		}
+
+		err = manager.WaitForCompletion(experimentsDetails.ChaosDuration + 30)
+		if recorder != nil {
+			for node, nodeSamples := range recorder.Stop() {
+				samples[node] = nodeSamples
+			}
+			common.RecordUtilisationInResult(resultDetails, samples, "cpu", experimentsDetails.TargetCPUUtilisation)
+		}
+		if err != nil {
+			manager.Cleanup()
+			return err
 		}
 
 		// Checking the status of target nodes
 		log.Info("[Status]: Getting the status of target nodes")
-		err = status.CheckNodeStatus(appNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
-		if err != nil {
-			common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, chaosDetails, clients)
+		if err = status.CheckNodeStatus(appNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
 			log.Warnf("Target nodes are not in the ready state, you may need to manually recover the node, err: %v", err)
 		}
 
 		//Deleting the helper pod
-		log.Info("[Cleanup]: Deleting the helper pod")
-		err = common.DeletePod(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, experimentsDetails.ChaosNamespace, chaosDetails.Timeout, chaosDetails.Delay, clients)
-		if err != nil {
-			return errors.Errorf("Unable to delete the helper pod, err: %v", err)
+		if err = manager.Delete(); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -157,7 +169,7 @@ func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetai
 func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDetails, targetNodeList []string, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 	nodeCPUCores := experimentsDetails.NodeCPUcores
 
-	labelSuffix := common.GetRunID()
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
 
 	// run the probes during chaos
 	if len(resultDetails.ProbeDetails) != 0 {
@@ -187,53 +199,48 @@ func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDet
 			"NodeCPUcores": experimentsDetails.NodeCPUcores,
 		})
 
-		experimentsDetails.RunID = common.GetRunID()
-
 		// Creating the helper pod to perform node cpu hog
-		err = CreateHelperPod(experimentsDetails, appNode, clients, labelSuffix)
-		if err != nil {
-			return errors.Errorf("Unable to create the helper pod, err: %v", err)
+		if err = CreateHelperPod(experimentsDetails, manager, appNode); err != nil {
+			return err
 		}
 	}
 
-	appLabel := "app=" + experimentsDetails.ExperimentName + "-helper-" + labelSuffix
-
 	//Checking the status of helper pod
-	log.Info("[Status]: Checking the status of the helper pods")
-	err = status.CheckApplicationStatus(experimentsDetails.ChaosNamespace, appLabel, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
-	if err != nil {
-		common.DeleteAllHelperPodBasedOnJobCleanupPolicy(appLabel, chaosDetails, clients)
-		return errors.Errorf("helper pod is not in running state, err: %v", err)
+	if err = manager.WaitForRunning(); err != nil {
+		manager.Cleanup()
+		return err
 	}
+	manager.StreamLogs()
 
 	// Wait till the completion of helper pod
 	log.Infof("[Wait]: Waiting for %vs till the completion of the helper pod", experimentsDetails.ChaosDuration+30)
 
-	podStatus, err := status.WaitForCompletion(experimentsDetails.ChaosNamespace, appLabel, clients, experimentsDetails.ChaosDuration+30, experimentsDetails.ExperimentName)
-	if err != nil || podStatus == "Failed" {
-		common.DeleteAllHelperPodBasedOnJobCleanupPolicy(appLabel, chaosDetails, clients)
-		return errors.Errorf("helper pod failed due to, err: %v", err)
+	// record the utilisation of the target nodes during the chaos, for the closed loop stress
+	var recorder *common.UtilisationRecorder
+	if experimentsDetails.TargetCPUUtilisation != 0 {
+		recorder = common.StartUtilisationRecorder(targetNodeList, "cpu", experimentsDetails.MetricsSource, experimentsDetails.ControlInterval, clients)
+	}
+
+	err = manager.WaitForCompletion(experimentsDetails.ChaosDuration + 30)
+	if recorder != nil {
+		common.RecordUtilisationInResult(resultDetails, recorder.Stop(), "cpu", experimentsDetails.TargetCPUUtilisation)
+	}
+	if err != nil {
+		manager.Cleanup()
+		return err
 	}
 
 	for _, appNode := range targetNodeList {
 
 		// Checking the status of application node
 		log.Info("[Status]: Getting the status of application node")
-		err = status.CheckNodeStatus(appNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
-		if err != nil {
-			common.DeleteAllHelperPodBasedOnJobCleanupPolicy(appLabel, chaosDetails, clients)
+		if err = status.CheckNodeStatus(appNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
 			log.Warn("Application node is not in the ready state, you may need to manually recover the node")
 		}
 	}
 
 	//Deleting the helper pod
-	log.Info("[Cleanup]: Deleting the helper pod")
-	err = common.DeleteAllPod(appLabel, experimentsDetails.ChaosNamespace, chaosDetails.Timeout, chaosDetails.Delay, clients)
-	if err != nil {
-		return errors.Errorf("Unable to delete the helper pod, err: %v", err)
-	}
-
-	return nil
+	return manager.Delete()
 }
 
 //SetCPUCapacity fetch the node cpu capacity
@@ -250,45 +257,65 @@ func SetCPUCapacity(experimentsDetails *experimentTypes.ExperimentDetails, appNo
 	return nil
 }
 
+// GetHelperManager returns the manager of the helper pods
+func GetHelperManager(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, chaosDetails *types.ChaosDetails) *helper.Manager {
+	return helper.NewManager(helper.Meta{
+		ExperimentName:  experimentsDetails.ExperimentName,
+		Namespace:       experimentsDetails.ChaosNamespace,
+		ChaosUID:        experimentsDetails.ChaosUID,
+		Image:           experimentsDetails.LIBImage,
+		ImagePullPolicy: experimentsDetails.LIBImagePullPolicy,
+	}, clients, chaosDetails)
+}
+
 // CreateHelperPod derive the attributes for helper pod and create the helper pod
-func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, appNode string, clients clients.ClientSets, labelSuffix string) error {
-
-	helperPod := &apiv1.Pod{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      experimentsDetails.ExperimentName + "-" + experimentsDetails.RunID,
-			Namespace: experimentsDetails.ChaosNamespace,
-			Labels: map[string]string{
-				"app":                       experimentsDetails.ExperimentName + "-helper-" + labelSuffix,
-				"name":                      experimentsDetails.ExperimentName + "-" + experimentsDetails.RunID,
-				"chaosUID":                  string(experimentsDetails.ChaosUID),
-				"app.kubernetes.io/part-of": "litmus",
-			},
-			Annotations: experimentsDetails.Annotations,
-		},
-		Spec: apiv1.PodSpec{
-			RestartPolicy:    apiv1.RestartPolicyNever,
-			ImagePullSecrets: experimentsDetails.ImagePullSecrets,
-			NodeName:         appNode,
-			Containers: []apiv1.Container{
-				{
-					Name:            experimentsDetails.ExperimentName,
-					Image:           experimentsDetails.LIBImage,
-					ImagePullPolicy: apiv1.PullPolicy(experimentsDetails.LIBImagePullPolicy),
-					Command: []string{
-						"stress-ng",
-					},
-					Args: []string{
-						"--cpu",
-						strconv.Itoa(experimentsDetails.NodeCPUcores),
-						"--timeout",
-						strconv.Itoa(experimentsDetails.ChaosDuration),
-					},
-					Resources: experimentsDetails.Resources,
-				},
-			},
-		},
+func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, manager *helper.Manager, appNode string) error {
+
+	opts := []helper.Option{
+		helper.WithNodeName(appNode),
+		helper.WithAnnotations(experimentsDetails.Annotations),
+		helper.WithImagePullSecrets(experimentsDetails.ImagePullSecrets),
+		helper.WithResources(experimentsDetails.Resources),
+	}
+
+	// the closed loop helper adjusts the stress, so that the node sits at the target utilisation
+	if experimentsDetails.TargetCPUUtilisation != 0 {
+		opts = append(opts,
+			helper.WithServiceAccount(experimentsDetails.ChaosServiceAccount),
+			helper.WithCommand([]string{"/bin/bash"}, "-c", "./helper/node-stress"),
+			helper.WithEnv(GetPodEnv(experimentsDetails, appNode)),
+			helper.WithPodNameEnv(),
+		)
+	} else {
+		opts = append(opts, helper.WithCommand([]string{"stress-ng"}, "--cpu", strconv.Itoa(experimentsDetails.NodeCPUcores), "--timeout", strconv.Itoa(experimentsDetails.ChaosDuration)))
+	}
+	return manager.Create(manager.NewPod(opts...))
+}
+
+// GetPodEnv derive all the env required for the closed loop helper pod
+func GetPodEnv(experimentsDetails *experimentTypes.ExperimentDetails, appNode string) map[string]string {
+
+	return map[string]string{
+		"STRESS_TYPE":          "cpu",
+		"TARGET_UTILISATION":   strconv.Itoa(experimentsDetails.TargetCPUUtilisation),
+		"CONTROL_INTERVAL":     strconv.Itoa(experimentsDetails.ControlInterval),
+		"METRICS_SOURCE":       experimentsDetails.MetricsSource,
+		"TOTAL_CHAOS_DURATION": strconv.Itoa(experimentsDetails.ChaosDuration),
+		"CHAOS_NAMESPACE":      experimentsDetails.ChaosNamespace,
+		"CHAOS_ENGINE":         experimentsDetails.EngineName,
+		"CHAOS_UID":            string(experimentsDetails.ChaosUID),
+		"EXPERIMENT_NAME":      experimentsDetails.ExperimentName,
+		"INSTANCE_ID":          experimentsDetails.InstanceID,
+		"NODE_NAME":            appNode,
 	}
+}
 
-	_, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaosNamespace).Create(helperPod)
-	return err
+// GetServiceAccount find the serviceAccountName for the helper pod
+func GetServiceAccount(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (string, error) {
+	pod, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaosNamespace).Get(experimentsDetails.ChaosPodName, v1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	return pod.Spec.ServiceAccountName, nil
 }
+
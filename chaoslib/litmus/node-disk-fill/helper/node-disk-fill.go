@@ -0,0 +1,334 @@
+package main
+
+import (
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	experimentEnv "github.com/litmuschaos/litmus-go/pkg/generic/node-disk-fill/environment"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/node-disk-fill/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/result"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	clientTypes "k8s.io/apimachinery/pkg/types"
+)
+
+// mountPath is the path inside the helper pod, where the FILL_PATH of the node is mounted
+const mountPath = "/node-disk-fill"
+
+// chunkSize is the size of the writes, if the filesystem doesn't support the fallocate
+const chunkSize = 4 * 1024 * 1024
+
+// filesPerDirectory is the number of the files created inside a directory in the inode mode
+const filesPerDirectory = 10000
+
+// filler keeps the fill and the revert mutually exclusive
+// so that no files are created once the revert is started
+type filler struct {
+	mu      sync.Mutex
+	stopped bool
+	dir     string
+}
+
+var inject, abort chan os.Signal
+
+func main() {
+
+	experimentsDetails := experimentTypes.ExperimentDetails{}
+	clients := clients.ClientSets{}
+	eventsDetails := types.EventDetails{}
+	chaosDetails := types.ChaosDetails{}
+	resultDetails := types.ResultDetails{}
+
+	// inject channel is used to transmit signal notifications.
+	inject = make(chan os.Signal, 1)
+	// Catch and relay certain signal(s) to inject channel.
+	signal.Notify(inject, os.Interrupt, syscall.SIGTERM)
+
+	// abort channel is used to transmit signal notifications.
+	abort = make(chan os.Signal, 1)
+	// Catch and relay certain signal(s) to abort channel.
+	signal.Notify(abort, os.Interrupt, syscall.SIGTERM)
+
+	//Getting kubeConfig and Generate ClientSets
+	if err := clients.GenerateClientSetFromKubeConfig(); err != nil {
+		log.Fatalf("Unable to Get the kubeconfig, err: %v", err)
+	}
+
+	//Fetching all the ENV passed in the helper pod
+	log.Info("[PreReq]: Getting the ENV variables")
+	GetENV(&experimentsDetails)
+
+	// Intialise the chaos attributes
+	experimentEnv.InitialiseChaosVariables(&chaosDetails, &experimentsDetails)
+
+	// Intialise Chaos Result Parameters
+	types.SetResultAttributes(&resultDetails, chaosDetails)
+
+	// Set the chaos result uid
+	result.SetResultUID(&resultDetails, clients, &chaosDetails)
+
+	if err := NodeDiskFill(&experimentsDetails, clients, &eventsDetails, &chaosDetails); err != nil {
+		helper.WriteTerminationMessage(err)
+		log.Fatalf("helper pod failed, err: %v", err)
+	}
+}
+
+//NodeDiskFill fills the space or the inodes of the node filesystem for the chaos duration and removes the files afterwards
+func NodeDiskFill(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	if experimentsDetails.FillDirectory == "" || filepath.Base(experimentsDetails.FillDirectory) != experimentsDetails.FillDirectory {
+		return errors.Errorf("%v is not a valid fill directory", experimentsDetails.FillDirectory)
+	}
+	fill := &filler{
+		dir: filepath.Join(mountPath, experimentsDetails.FillDirectory),
+	}
+
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(mountPath, &stat); err != nil {
+		return errors.Errorf("unable to get the filesystem stats of %v, err: %v", experimentsDetails.FillPath, err)
+	}
+
+	log.InfoWithValues("[Info]: The filesystem details are as follows", logrus.Fields{
+		"FillPath":       experimentsDetails.FillPath,
+		"FillMode":       experimentsDetails.FillMode,
+		"CapacityBytes":  stat.Blocks * uint64(stat.Bsize),
+		"AvailableBytes": stat.Bavail * uint64(stat.Bsize),
+		"TotalInodes":    stat.Files,
+		"FreeInodes":     stat.Ffree,
+	})
+
+	// record the event inside chaosengine
+	if experimentsDetails.EngineName != "" {
+		msg := "Filling the " + experimentsDetails.FillMode + " of " + experimentsDetails.FillPath + " path of the node"
+		types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+		events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+	}
+
+	// watching for the abort signal and revert the chaos
+	go abortWatcher(fill)
+
+	select {
+	case <-inject:
+		// stopping the chaos execution, if abort signal recieved
+		helper.ExitAborted()
+	default:
+		if err := os.MkdirAll(fill.dir, 0700); err != nil {
+			return errors.Errorf("unable to create the %v directory, err: %v", fill.dir, err)
+		}
+		switch experimentsDetails.FillMode {
+		case "space":
+			if err := fill.fillSpace(getBytesToBeFilled(experimentsDetails, stat)); err != nil {
+				fill.remove()
+				return err
+			}
+		case "inode":
+			if err := fill.fillInodes(getInodesToBeFilled(experimentsDetails, stat)); err != nil {
+				fill.remove()
+				return err
+			}
+		default:
+			fill.remove()
+			return errors.Errorf("%v fill mode not supported, supported modes are space and inode", experimentsDetails.FillMode)
+		}
+		helper.RecordInjected("filled the " + experimentsDetails.FillMode + " of " + experimentsDetails.FillPath + " path in " + fill.dir + " directory")
+	}
+
+	log.Infof("[Chaos]: Waiting for %vs", experimentsDetails.ChaosDuration)
+	common.WaitForDuration(experimentsDetails.ChaosDuration)
+
+	log.Info("[Chaos]: Stopping the experiment")
+	return fill.remove()
+}
+
+// getBytesToBeFilled derive the bytes to be written, from the FILL_MEBIBYTES or from the FILL_PERCENTAGE of the capacity
+func getBytesToBeFilled(experimentsDetails *experimentTypes.ExperimentDetails, stat syscall.Statfs_t) int64 {
+
+	available := int64(stat.Bavail) * int64(stat.Bsize)
+	if experimentsDetails.FillMebibytes != 0 {
+		size := int64(experimentsDetails.FillMebibytes) * 1024 * 1024
+		if size > available {
+			log.Warnf("The fill size %v bytes is more than the available space %v bytes, filling upto the available space", size, available)
+			return available
+		}
+		return size
+	}
+
+	// the blocks reserved for the root user are counted as used, as the kubelet computes the usage in the same way
+	capacity := int64(stat.Blocks) * int64(stat.Bsize)
+	used := capacity - available
+	return int64(float64(capacity)*float64(experimentsDetails.FillPercentage)/100) - used
+}
+
+// getInodesToBeFilled derive the number of files to be created, from the FILL_PERCENTAGE of the total inodes
+func getInodesToBeFilled(experimentsDetails *experimentTypes.ExperimentDetails, stat syscall.Statfs_t) int64 {
+	used := int64(stat.Files) - int64(stat.Ffree)
+	return int64(float64(stat.Files)*float64(experimentsDetails.FillPercentage)/100) - used
+}
+
+// fillSpace allocates the given bytes inside the fill directory
+// it falls back to write the zeros, if the filesystem doesn't support the fallocate
+func (f *filler) fillSpace(size int64) error {
+
+	if size <= 0 {
+		log.Warn("[Fill]: The filesystem is already filled upto the required size, skipping the fill")
+		return nil
+	}
+	log.Infof("[Fill]: Filling %v bytes", size)
+
+	f.mu.Lock()
+	if f.stopped {
+		f.mu.Unlock()
+		return nil
+	}
+	file, err := os.OpenFile(filepath.Join(f.dir, "fill"), os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		f.mu.Unlock()
+		return errors.Errorf("unable to create the fill file, err: %v", err)
+	}
+	defer file.Close()
+
+	err = syscall.Fallocate(int(file.Fd()), 0, 0, size)
+	f.mu.Unlock()
+	if err == nil {
+		return nil
+	} else if err != syscall.EOPNOTSUPP {
+		return errors.Errorf("unable to allocate the fill file, err: %v", err)
+	}
+
+	// the lock is taken for every chunk, so that the revert is not blocked till the whole size is written
+	buf := make([]byte, chunkSize)
+	for written := int64(0); written < size; {
+		n := int64(chunkSize)
+		if size-written < n {
+			n = size - written
+		}
+		f.mu.Lock()
+		if f.stopped {
+			f.mu.Unlock()
+			return nil
+		}
+		_, err := file.Write(buf[:n])
+		f.mu.Unlock()
+		if err != nil {
+			return errors.Errorf("unable to write the fill file, err: %v", err)
+		}
+		written += n
+	}
+	return file.Sync()
+}
+
+// fillInodes creates the given number of empty files inside the fill directory
+// the files are spread across the sub-directories, to keep the directories small
+func (f *filler) fillInodes(count int64) error {
+
+	if count <= 0 {
+		log.Warn("[Fill]: The filesystem is already filled upto the required inodes, skipping the fill")
+		return nil
+	}
+	log.Infof("[Fill]: Creating %v files", count)
+
+	for created := int64(0); created < count; {
+		f.mu.Lock()
+		if f.stopped {
+			f.mu.Unlock()
+			return nil
+		}
+		dir := filepath.Join(f.dir, strconv.FormatInt(created/filesPerDirectory, 10))
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			f.mu.Unlock()
+			return errors.Errorf("unable to create the %v directory, err: %v", dir, err)
+		}
+		for i := 0; i < filesPerDirectory && created < count; i++ {
+			file, err := os.OpenFile(filepath.Join(dir, strconv.Itoa(i)), os.O_CREATE|os.O_WRONLY, 0600)
+			if err != nil {
+				f.mu.Unlock()
+				if errors.Is(err, syscall.ENOSPC) {
+					log.Warnf("[Fill]: No inodes left after creating %v files", created)
+					return nil
+				}
+				return errors.Errorf("unable to create the fill file, err: %v", err)
+			}
+			file.Close()
+			created++
+		}
+		f.mu.Unlock()
+	}
+	return nil
+}
+
+// remove stops the fill and removes the fill directory along with all the files
+func (f *filler) remove() error {
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.stopped = true
+	log.Infof("[Cleanup]: Removing the %v directory", f.dir)
+	if err := os.RemoveAll(f.dir); err != nil {
+		return errors.Errorf("unable to remove the fill directory, err: %v", err)
+	}
+	helper.RecordReverted("removed the " + f.dir + " directory")
+	return nil
+}
+
+//GetENV fetches all the env variables from the runner pod
+func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
+	experimentDetails.ExperimentName = Getenv("EXPERIMENT_NAME", "")
+	experimentDetails.InstanceID = Getenv("INSTANCE_ID", "")
+	experimentDetails.ChaosDuration, _ = strconv.Atoi(Getenv("TOTAL_CHAOS_DURATION", "120"))
+	experimentDetails.ChaosNamespace = Getenv("CHAOS_NAMESPACE", "litmus")
+	experimentDetails.EngineName = Getenv("CHAOS_ENGINE", "")
+	experimentDetails.ChaosUID = clientTypes.UID(Getenv("CHAOS_UID", ""))
+	experimentDetails.ChaosPodName = Getenv("POD_NAME", "")
+	experimentDetails.FillPath = Getenv("FILL_PATH", "")
+	experimentDetails.FillMode = Getenv("FILL_MODE", "space")
+	experimentDetails.FillPercentage, _ = strconv.Atoi(Getenv("FILL_PERCENTAGE", "95"))
+	experimentDetails.FillMebibytes, _ = strconv.Atoi(Getenv("FILL_MEBIBYTES", "0"))
+	experimentDetails.FillDirectory = Getenv("FILL_DIRECTORY", "")
+}
+
+// Getenv fetch the env and set the default value, if any
+func Getenv(key string, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		value = defaultValue
+	}
+	return value
+}
+
+// abortWatcher continuosly watch for the abort signals
+// the pod receives the terminated signal on the abort and also on the eviction of the helper pod
+func abortWatcher(fill *filler) {
+
+	for {
+		select {
+		case <-abort:
+			log.Info("[Chaos]: Killing process started because of terminated signal received")
+			log.Info("Chaos Revert Started")
+			// retry thrice for the chaos revert
+			retry := 3
+			for retry > 0 {
+				if err := fill.remove(); err != nil {
+					log.Errorf("Unable to remove the fill directory, err: %v", err)
+					retry--
+					time.Sleep(1 * time.Second)
+					continue
+				}
+				break
+			}
+			log.Info("Chaos Revert Completed")
+			helper.ExitAborted()
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package lib
+
+import (
+	"strconv"
+	"strings"
+
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/node-disk-fill/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/probe"
+	"github.com/litmuschaos/litmus-go/pkg/status"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var err error
+
+// defaultEvictionThreshold is the used percentage of the node filesystem at which the kubelet reports the DiskPressure by default
+// it corresponds to the nodefs.available<10% and nodefs.inodesFree<5% hard eviction thresholds
+var defaultEvictionThreshold = map[string]int{
+	"space": 90,
+	"inode": 95,
+}
+
+// PrepareNodeDiskFill contains prepration steps before chaos injection
+func PrepareNodeDiskFill(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	if experimentsDetails.FillMode != "space" && experimentsDetails.FillMode != "inode" {
+		return errors.Errorf("%v fill mode not supported, supported modes are space and inode", experimentsDetails.FillMode)
+	}
+	if experimentsDetails.FillMode == "inode" && experimentsDetails.FillMebibytes != 0 {
+		log.Warn("FILL_MEBIBYTES is not applicable for the inode fill mode, using the FILL_PERCENTAGE of the inodes")
+	}
+	if experimentsDetails.VerifyDiskPressure && experimentsDetails.FillMebibytes == 0 && experimentsDetails.FillPercentage <= defaultEvictionThreshold[experimentsDetails.FillMode] {
+		log.Warnf("FILL_PERCENTAGE %v%% does not exceed the default kubelet eviction threshold of %v%% for the %v fill mode, the node may not report the DiskPressure", experimentsDetails.FillPercentage, defaultEvictionThreshold[experimentsDetails.FillMode], experimentsDetails.FillMode)
+	}
+
+	//Select the target nodes for the chaos
+	targetNodeList, err := common.GetTargetNodes(common.GetNodeSelector(experimentsDetails.NodeTargetDetails, experimentsDetails.AppNS, experimentsDetails.AppLabel), clients)
+	if err != nil {
+		return err
+	}
+	log.InfoWithValues("[Info]: Details of Nodes under chaos injection", logrus.Fields{
+		"No. Of Nodes": len(targetNodeList),
+		"Node Names":   targetNodeList,
+		"Fill Path":    experimentsDetails.FillPath,
+		"Fill Mode":    experimentsDetails.FillMode,
+	})
+
+	//Waiting for the ramp time before chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time before injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+
+	// Getting the serviceAccountName, need permission inside helper pod to create the events
+	if experimentsDetails.ChaosServiceAccount == "" {
+		experimentsDetails.ChaosServiceAccount, err = GetServiceAccount(experimentsDetails, clients)
+		if err != nil {
+			return errors.Errorf("Unable to get the serviceAccountName, err: %v", err)
+		}
+	}
+
+	if experimentsDetails.EngineName != "" {
+		// Get Chaos Pod Annotation
+		experimentsDetails.Annotations, err = common.GetChaosPodAnnotation(experimentsDetails.ChaosPodName, experimentsDetails.ChaosNamespace, clients)
+		if err != nil {
+			return errors.Errorf("unable to get annotations, err: %v", err)
+		}
+		// Get Resource Requirements
+		experimentsDetails.Resources, err = common.GetChaosPodResourceRequirements(experimentsDetails.ChaosPodName, experimentsDetails.ExperimentName, experimentsDetails.ChaosNamespace, clients)
+		if err != nil {
+			return errors.Errorf("Unable to get resource requirements, err: %v", err)
+		}
+		// Get ImagePullSecrets
+		experimentsDetails.ImagePullSecrets, err = common.GetImagePullSecrets(experimentsDetails.ChaosPodName, experimentsDetails.ChaosNamespace, clients)
+		if err != nil {
+			return errors.Errorf("Unable to get imagePullSecrets, err: %v", err)
+		}
+	}
+
+	if experimentsDetails.Sequence == "serial" {
+		if err = InjectChaosInSerialMode(experimentsDetails, targetNodeList, clients, resultDetails, eventsDetails, chaosDetails); err != nil {
+			return err
+		}
+	} else {
+		if err = InjectChaosInParallelMode(experimentsDetails, targetNodeList, clients, resultDetails, eventsDetails, chaosDetails); err != nil {
+			return err
+		}
+	}
+
+	//Waiting for the ramp time after chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time after injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+	return nil
+}
+
+// InjectChaosInSerialMode fill the disk of all the target nodes serially (one by one)
+func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetails, targetNodeList []string, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
+
+	// run the probes during chaos
+	if len(resultDetails.ProbeDetails) != 0 {
+		if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+			return err
+		}
+	}
+
+	for _, appNode := range targetNodeList {
+
+		if experimentsDetails.EngineName != "" {
+			msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on " + appNode + " node"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		// the pods evicted before the chaos, which are excluded while verifying the eviction
+		preChaosEvictedPods, err := GetEvictedPods(appNode, clients)
+		if err != nil {
+			return err
+		}
+
+		// Creating the helper pod to fill the disk
+		if err = CreateHelperPod(experimentsDetails, manager, appNode); err != nil {
+			return err
+		}
+
+		//checking the status of the helper pods, wait till the pod comes to running state else fail the experiment
+		if err := manager.WaitForRunning(); err != nil {
+			manager.Cleanup()
+			return err
+		}
+		manager.StreamLogs()
+
+		if err = verifyDiskPressure(experimentsDetails, appNode, apiv1.ConditionTrue, clients); err != nil {
+			manager.Cleanup()
+			return err
+		}
+
+		// Wait till the completion of helper pod
+		log.Infof("[Wait]: Waiting for %vs till the completion of the helper pod", experimentsDetails.ChaosDuration+experimentsDetails.Timeout)
+		if err := manager.WaitForCompletion(experimentsDetails.ChaosDuration + experimentsDetails.Timeout); err != nil {
+			manager.Cleanup()
+			return err
+		}
+
+		// Checking the recovery of the target nodes from the disk pressure
+		if err = verifyDiskPressure(experimentsDetails, appNode, apiv1.ConditionFalse, clients); err != nil {
+			manager.Cleanup()
+			return err
+		}
+
+		if err = verifyEviction(experimentsDetails, appNode, preChaosEvictedPods, clients, eventsDetails, chaosDetails); err != nil {
+			manager.Cleanup()
+			return err
+		}
+
+		//Deleting the helper pod
+		if err := manager.Delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InjectChaosInParallelMode fill the disk of all the target nodes in parallel mode (all at once)
+func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDetails, targetNodeList []string, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
+
+	// run the probes during chaos
+	if len(resultDetails.ProbeDetails) != 0 {
+		if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+			return err
+		}
+	}
+
+	preChaosEvictedPods := map[string]map[string]bool{}
+	for _, appNode := range targetNodeList {
+
+		if experimentsDetails.EngineName != "" {
+			msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on " + appNode + " node"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		// the pods evicted before the chaos, which are excluded while verifying the eviction
+		preChaosEvictedPods[appNode], err = GetEvictedPods(appNode, clients)
+		if err != nil {
+			return err
+		}
+
+		// Creating the helper pod to fill the disk
+		if err = CreateHelperPod(experimentsDetails, manager, appNode); err != nil {
+			return err
+		}
+	}
+
+	//checking the status of the helper pods, wait till the pod comes to running state else fail the experiment
+	if err := manager.WaitForRunning(); err != nil {
+		manager.Cleanup()
+		return err
+	}
+	manager.StreamLogs()
+
+	for _, appNode := range targetNodeList {
+		if err = verifyDiskPressure(experimentsDetails, appNode, apiv1.ConditionTrue, clients); err != nil {
+			manager.Cleanup()
+			return err
+		}
+	}
+
+	// Wait till the completion of helper pod
+	log.Infof("[Wait]: Waiting for %vs till the completion of the helper pod", experimentsDetails.ChaosDuration+experimentsDetails.Timeout)
+	if err := manager.WaitForCompletion(experimentsDetails.ChaosDuration + experimentsDetails.Timeout); err != nil {
+		manager.Cleanup()
+		return err
+	}
+
+	for _, appNode := range targetNodeList {
+		// Checking the recovery of the target nodes from the disk pressure
+		if err = verifyDiskPressure(experimentsDetails, appNode, apiv1.ConditionFalse, clients); err != nil {
+			manager.Cleanup()
+			return err
+		}
+		if err = verifyEviction(experimentsDetails, appNode, preChaosEvictedPods[appNode], clients, eventsDetails, chaosDetails); err != nil {
+			manager.Cleanup()
+			return err
+		}
+	}
+
+	//Deleting the helper pod
+	return manager.Delete()
+}
+
+// verifyDiskPressure waits for the DiskPressure condition of the node to be in the expected status
+// it is True once the kubelet observes the filled disk and False once the disk is reclaimed after the chaos
+func verifyDiskPressure(experimentsDetails *experimentTypes.ExperimentDetails, appNode string, expectedStatus apiv1.ConditionStatus, clients clients.ClientSets) error {
+
+	if !experimentsDetails.VerifyDiskPressure {
+		return nil
+	}
+
+	log.Infof("[Status]: Check for the DiskPressure condition of %v node to be %v", appNode, expectedStatus)
+	if err := status.CheckNodeCondition(appNode, apiv1.NodeDiskPressure, expectedStatus, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
+		if expectedStatus == apiv1.ConditionTrue {
+			return errors.Errorf("%v node is not under disk pressure, err: %v", appNode, err)
+		}
+		return errors.Errorf("%v node is not recovered from the disk pressure, you may need to manually cleanup the %v path, err: %v", appNode, experimentsDetails.FillPath, err)
+	}
+	return nil
+}
+
+// verifyEviction logs the pods evicted by the kubelet during the chaos and records it in the chaosengine events
+// it fails if no pod is evicted and the VERIFY_EVICTION is enabled
+func verifyEviction(experimentsDetails *experimentTypes.ExperimentDetails, appNode string, preChaosEvictedPods map[string]bool, clients clients.ClientSets, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	evictedPods, err := GetEvictedPods(appNode, clients)
+	if err != nil {
+		return err
+	}
+	var newlyEvictedPods []string
+	for pod := range evictedPods {
+		if !preChaosEvictedPods[pod] {
+			newlyEvictedPods = append(newlyEvictedPods, pod)
+		}
+	}
+
+	log.InfoWithValues("[Info]: The pods evicted during the chaos are as follows", logrus.Fields{
+		"NodeName":    appNode,
+		"EvictedPods": newlyEvictedPods,
+	})
+
+	if experimentsDetails.EngineName != "" {
+		msg := strconv.Itoa(len(newlyEvictedPods)) + " pods are evicted from " + appNode + " node during the chaos"
+		if len(newlyEvictedPods) != 0 {
+			msg += ": " + strings.Join(newlyEvictedPods, ",")
+		}
+		types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+		events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+	}
+
+	if experimentsDetails.VerifyEviction && len(newlyEvictedPods) == 0 {
+		return errors.Errorf("no pod is evicted from %v node during the chaos", appNode)
+	}
+	return nil
+}
+
+// GetEvictedPods returns the evicted pods of the node, in the <namespace>/<name> format
+func GetEvictedPods(appNode string, clients clients.ClientSets) (map[string]bool, error) {
+
+	pods, err := clients.KubeClient.CoreV1().Pods("").List(v1.ListOptions{FieldSelector: "spec.nodeName=" + appNode})
+	if err != nil {
+		return nil, errors.Errorf("Unable to list the pods of %v node, err: %v", appNode, err)
+	}
+	evictedPods := map[string]bool{}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == apiv1.PodFailed && pod.Status.Reason == "Evicted" {
+			evictedPods[pod.Namespace+"/"+pod.Name] = true
+		}
+	}
+	return evictedPods, nil
+}
+
+// GetHelperManager returns the manager of the helper pods
+func GetHelperManager(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, chaosDetails *types.ChaosDetails) *helper.Manager {
+	return helper.NewManager(helper.Meta{
+		ExperimentName:  experimentsDetails.ExperimentName,
+		Namespace:       experimentsDetails.ChaosNamespace,
+		ChaosUID:        experimentsDetails.ChaosUID,
+		Image:           experimentsDetails.LIBImage,
+		ImagePullPolicy: experimentsDetails.LIBImagePullPolicy,
+	}, clients, chaosDetails)
+}
+
+// CreateHelperPod derive the attributes for helper pod and create the helper pod
+func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, manager *helper.Manager, appNode string) error {
+
+	hostPathType := apiv1.HostPathDirectory
+	helperPod := manager.NewPod(
+		helper.WithNodeName(appNode),
+		helper.WithTerminationGracePeriod(experimentsDetails.TerminationGracePeriodSeconds),
+		helper.WithServiceAccount(experimentsDetails.ChaosServiceAccount),
+		helper.WithAnnotations(experimentsDetails.Annotations),
+		helper.WithImagePullSecrets(experimentsDetails.ImagePullSecrets),
+		helper.WithResources(experimentsDetails.Resources),
+		helper.WithCommand([]string{"/bin/bash"}, "-c", "./helper/node-disk-fill"),
+		helper.WithEnv(GetPodEnv(experimentsDetails)),
+		helper.WithPodNameEnv(),
+		helper.WithVolume(apiv1.Volume{
+			Name: "fill-path",
+			VolumeSource: apiv1.VolumeSource{
+				HostPath: &apiv1.HostPathVolumeSource{
+					Path: experimentsDetails.FillPath,
+					Type: &hostPathType,
+				},
+			},
+		}, "/node-disk-fill", nil),
+		// the helper runs as root, as the host paths are owned by the root user
+		helper.WithRunAsUser(0),
+	)
+	// the fill directory is unique for every helper, so that the concurrent helpers do not reclaim each others files
+	helper.WithEnv(map[string]string{"FILL_DIRECTORY": "litmus-" + helperPod.Name})(helperPod)
+
+	return manager.Create(helperPod)
+}
+
+// GetPodEnv derive all the env required for the helper pod
+func GetPodEnv(experimentsDetails *experimentTypes.ExperimentDetails) map[string]string {
+
+	return map[string]string{
+		"FILL_PATH":            experimentsDetails.FillPath,
+		"FILL_MODE":            experimentsDetails.FillMode,
+		"FILL_PERCENTAGE":      strconv.Itoa(experimentsDetails.FillPercentage),
+		"FILL_MEBIBYTES":       strconv.Itoa(experimentsDetails.FillMebibytes),
+		"TOTAL_CHAOS_DURATION": strconv.Itoa(experimentsDetails.ChaosDuration),
+		"CHAOS_NAMESPACE":      experimentsDetails.ChaosNamespace,
+		"CHAOS_ENGINE":         experimentsDetails.EngineName,
+		"CHAOS_UID":            string(experimentsDetails.ChaosUID),
+		"EXPERIMENT_NAME":      experimentsDetails.ExperimentName,
+		"INSTANCE_ID":          experimentsDetails.InstanceID,
+	}
+}
+
+// GetServiceAccount find the serviceAccountName for the helper pod
+func GetServiceAccount(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (string, error) {
+	pod, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaosNamespace).Get(experimentsDetails.ChaosPodName, v1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	return pod.Spec.ServiceAccountName, nil
+}
+
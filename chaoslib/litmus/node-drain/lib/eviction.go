@@ -0,0 +1,192 @@
+package lib
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// mirrorPodAnnotation is set by the kubelet on the mirror pods of the static pods
+const mirrorPodAnnotation = "kubernetes.io/config.mirror"
+
+// originalCordonState contains the unschedulable state of the target nodes before the chaos
+// it is guarded by the cordonStateLock, as the abortWatcher reads it while the nodes are being cordoned
+var (
+	originalCordonState = map[string]bool{}
+	cordonStateLock     sync.Mutex
+)
+
+// cordonNode record the cordon state of the node and mark it as unschedulable
+func cordonNode(nodeName string, clients clients.ClientSets) error {
+
+	node, err := clients.KubeClient.CoreV1().Nodes().Get(nodeName, v1.GetOptions{})
+	if err != nil {
+		return errors.Errorf("Unable to get the %v node, err: %v", nodeName, err)
+	}
+	cordonStateLock.Lock()
+	if _, ok := originalCordonState[nodeName]; !ok {
+		originalCordonState[nodeName] = node.Spec.Unschedulable
+	}
+	cordonStateLock.Unlock()
+	if node.Spec.Unschedulable {
+		log.Infof("[Info]: The %v node is already cordoned", nodeName)
+		return nil
+	}
+	if err := setNodeUnschedulable(nodeName, true, clients); err != nil {
+		return errors.Errorf("Unable to cordon the %v node, err: %v", nodeName, err)
+	}
+	return nil
+}
+
+// getOriginalCordonState returns the unschedulable state of the node before the chaos, if it is recorded
+func getOriginalCordonState(nodeName string) (bool, bool) {
+	cordonStateLock.Lock()
+	defer cordonStateLock.Unlock()
+	unschedulable, ok := originalCordonState[nodeName]
+	return unschedulable, ok
+}
+
+// setNodeUnschedulable patch the unschedulable field of the node and wait till it is reflected
+func setNodeUnschedulable(nodeName string, unschedulable bool, clients clients.ClientSets) error {
+
+	patch := fmt.Sprintf(`{"spec":{"unschedulable":%v}}`, unschedulable)
+	if _, err := clients.KubeClient.CoreV1().Nodes().Patch(nodeName, k8stypes.StrategicMergePatchType, []byte(patch)); err != nil {
+		return err
+	}
+
+	return retry.
+		Times(90).
+		Wait(1 * time.Second).
+		Try(func(attempt uint) error {
+			nodeSpec, err := clients.KubeClient.CoreV1().Nodes().Get(nodeName, v1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			if nodeSpec.Spec.Unschedulable != unschedulable {
+				return errors.Errorf("%v node is not in the desired schedulable state", nodeName)
+			}
+			return nil
+		})
+}
+
+// evictPods evict all the pods of the node through the eviction api, except the daemonset and mirror pods
+// the evictions blocked by the pod disruption budgets are retried till the timeout, all the blocked pods are
+// retried in every round, so that a pod which stays blocked doesn't delay the eviction of the other pods
+// it returns the pods which are not evicted within the timeout
+func evictPods(nodeName string, timeout, delay int, clients clients.ClientSets) ([]string, error) {
+
+	podList, err := clients.KubeClient.CoreV1().Pods("").List(v1.ListOptions{FieldSelector: "spec.nodeName=" + nodeName})
+	if err != nil {
+		return nil, errors.Errorf("Unable to list the pods of %v node, err: %v", nodeName, err)
+	}
+
+	var pendingPods []apiv1.Pod
+	for _, pod := range podList.Items {
+		if isDaemonSetPod(pod) || isMirrorPod(pod) {
+			log.Infof("[Info]: Skipping the eviction of %v/%v pod", pod.Namespace, pod.Name)
+			continue
+		}
+		pendingPods = append(pendingPods, pod)
+	}
+
+	deadline := time.Now().Add(time.Duration(timeout) * time.Second)
+	var evictedPods []apiv1.Pod
+	var failedPods []string
+	for len(pendingPods) != 0 {
+		var blockedPods []apiv1.Pod
+		for _, pod := range pendingPods {
+			err := evictPod(pod, clients)
+			switch {
+			case err == nil:
+				evictedPods = append(evictedPods, pod)
+			case k8serrors.IsTooManyRequests(err):
+				blockedPods = append(blockedPods, pod)
+			default:
+				log.Warnf("Unable to evict the %v/%v pod, err: %v", pod.Namespace, pod.Name, err)
+				failedPods = append(failedPods, pod.Namespace+"/"+pod.Name)
+			}
+		}
+		if len(blockedPods) != 0 && time.Now().After(deadline) {
+			for _, pod := range blockedPods {
+				log.Warnf("Unable to evict the %v/%v pod, the eviction is blocked by the pod disruption budget", pod.Namespace, pod.Name)
+				failedPods = append(failedPods, pod.Namespace+"/"+pod.Name)
+			}
+			break
+		}
+		if len(blockedPods) != 0 {
+			log.Infof("[Wait]: The eviction of %v pods is blocked by the pod disruption budget, retrying", len(blockedPods))
+			time.Sleep(time.Duration(delay) * time.Second)
+		}
+		pendingPods = blockedPods
+	}
+
+	// wait for the evicted pods to terminate, honouring their termination grace period
+	for _, pod := range evictedPods {
+		if err := waitForPodDeletion(pod, deadline, delay, clients); err != nil {
+			log.Warnf("The %v/%v pod is not terminated, err: %v", pod.Namespace, pod.Name, err)
+			failedPods = append(failedPods, pod.Namespace+"/"+pod.Name)
+		}
+	}
+	return failedPods, nil
+}
+
+// evictPod create the eviction for the pod, a pod which is already deleted is considered as evicted
+func evictPod(pod apiv1.Pod, clients clients.ClientSets) error {
+
+	eviction := &policyv1beta1.Eviction{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      pod.Name,
+			Namespace: pod.Namespace,
+		},
+		DeleteOptions: &v1.DeleteOptions{
+			GracePeriodSeconds: pod.Spec.TerminationGracePeriodSeconds,
+		},
+	}
+
+	err := clients.KubeClient.PolicyV1beta1().Evictions(pod.Namespace).Evict(eviction)
+	if k8serrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// waitForPodDeletion wait till the pod is deleted or replaced by a new pod with the same name
+func waitForPodDeletion(pod apiv1.Pod, deadline time.Time, delay int, clients clients.ClientSets) error {
+
+	for {
+		currentPod, err := clients.KubeClient.CoreV1().Pods(pod.Namespace).Get(pod.Name, v1.GetOptions{})
+		if k8serrors.IsNotFound(err) || (err == nil && currentPod.UID != pod.UID) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return errors.Errorf("pod is not deleted within the timeout")
+		}
+		time.Sleep(time.Duration(delay) * time.Second)
+	}
+}
+
+// isDaemonSetPod check whether the pod is controlled by a daemonset
+func isDaemonSetPod(pod apiv1.Pod) bool {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Kind == "DaemonSet" && owner.Controller != nil && *owner.Controller {
+			return true
+		}
+	}
+	return false
+}
+
+// isMirrorPod check whether the pod is the mirror pod of a static pod
+func isMirrorPod(pod apiv1.Pod) bool {
+	_, ok := pod.Annotations[mirrorPodAnnotation]
+	return ok
+}
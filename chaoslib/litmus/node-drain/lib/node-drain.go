@@ -1,11 +1,11 @@
 package lib
 
 import (
-	"bytes"
+	"encoding/json"
+	"fmt"
 	"os"
-	"os/exec"
 	"os/signal"
-	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -18,9 +18,7 @@ import (
 	"github.com/litmuschaos/litmus-go/pkg/status"
 	"github.com/litmuschaos/litmus-go/pkg/types"
 	"github.com/litmuschaos/litmus-go/pkg/utils/common"
-	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
 	"github.com/pkg/errors"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 var err error
@@ -45,16 +43,15 @@ func PrepareNodeDrain(experimentsDetails *experimentTypes.ExperimentDetails, cli
 		common.WaitForDuration(experimentsDetails.RampTime)
 	}
 
-	if experimentsDetails.TargetNode == "" {
-		//Select node for kubelet-service-kill
-		experimentsDetails.TargetNode, err = common.GetNodeName(experimentsDetails.AppNS, experimentsDetails.AppLabel, clients)
-		if err != nil {
-			return err
-		}
+	//Select the target nodes for the node drain
+	experimentsDetails.TargetNodeList, err = common.GetTargetNodes(common.GetNodeSelector(experimentsDetails.NodeTargetDetails, experimentsDetails.AppNS, experimentsDetails.AppLabel), clients)
+	if err != nil {
+		return err
 	}
+	log.Infof("[Info]: Target nodes list, %v", experimentsDetails.TargetNodeList)
 
 	if experimentsDetails.EngineName != "" {
-		msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on " + experimentsDetails.TargetNode + " node"
+		msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on " + strings.Join(experimentsDetails.TargetNodeList, ",") + " node"
 		types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
 		events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
 	}
@@ -69,9 +66,18 @@ func PrepareNodeDrain(experimentsDetails *experimentTypes.ExperimentDetails, cli
 	// watching for the abort signal and revert the chaos
 	go abortWatcher(experimentsDetails, clients, resultDetails, chaosDetails, eventsDetails)
 
-	// Drain the application node
-	err := DrainNode(experimentsDetails, clients)
-	if err != nil {
+	// restore the cordon state, if the experiment fails after the nodes are (partially) cordoned
+	uncordoned := false
+	defer func() {
+		if !uncordoned {
+			if uncordonErr := UncordonNode(experimentsDetails, clients); uncordonErr != nil {
+				log.Errorf("Unable to restore the cordon state of the nodes, err: %v", uncordonErr)
+			}
+		}
+	}()
+
+	// Drain the target nodes
+	if err := DrainNode(experimentsDetails, clients, resultDetails, eventsDetails, chaosDetails); err != nil {
 		return err
 	}
 
@@ -97,14 +103,15 @@ func PrepareNodeDrain(experimentsDetails *experimentTypes.ExperimentDetails, cli
 
 	log.Info("[Chaos]: Stopping the experiment")
 
-	// Uncordon the application node
+	// Restore the cordon state of the target nodes
+	uncordoned = true
 	if err := UncordonNode(experimentsDetails, clients); err != nil {
 		return err
 	}
 
 	// Checking the status of target nodes
 	log.Info("[Status]: Getting the status of target nodes")
-	err = status.CheckNodeStatus(experimentsDetails.TargetNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
+	err = status.CheckNodeStatus(strings.Join(experimentsDetails.TargetNodeList, ","), experimentsDetails.Timeout, experimentsDetails.Delay, clients)
 	if err != nil {
 		log.Warnf("Target nodes are not in the ready state, you may need to manually recover the node, err: %v", err)
 	}
@@ -117,69 +124,86 @@ func PrepareNodeDrain(experimentsDetails *experimentTypes.ExperimentDetails, cli
 	return nil
 }
 
-// DrainNode drain the application node
-func DrainNode(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) error {
+// DrainNode cordon all the target nodes and evict the pods running on them
+// all the nodes are cordoned upfront so that the evicted pods are not rescheduled on the other target nodes
+// the pods which can't be evicted within the chaos duration are recorded in the chaosresult and the drain continues
+func DrainNode(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
 	select {
 	case <-inject:
 		// stopping the chaos execution, if abort signal recieved
 		os.Exit(0)
 	default:
-		log.Infof("[Inject]: Draining the %v node", experimentsDetails.TargetNode)
-
-		command := exec.Command("kubectl", "drain", experimentsDetails.TargetNode, "--ignore-daemonsets", "--delete-local-data", "--force", "--timeout", strconv.Itoa(experimentsDetails.ChaosDuration)+"s")
-		var out, stderr bytes.Buffer
-		command.Stdout = &out
-		command.Stderr = &stderr
-		if err := command.Run(); err != nil {
-			log.Infof("Error String: %v", stderr.String())
-			return errors.Errorf("Unable to drain the %v node, err: %v", experimentsDetails.TargetNode, err)
+		for _, nodeName := range experimentsDetails.TargetNodeList {
+			log.Infof("[Inject]: Cordon the %v node", nodeName)
+			if err := cordonNode(nodeName, clients); err != nil {
+				return err
+			}
 		}
 
-		return retry.
-			Times(90).
-			Wait(1 * time.Second).
-			Try(func(attempt uint) error {
-				nodeSpec, err := clients.KubeClient.CoreV1().Nodes().Get(experimentsDetails.TargetNode, v1.GetOptions{})
-				if err != nil {
-					return err
-				}
-				if !nodeSpec.Spec.Unschedulable {
-					return errors.Errorf("%v node is not in unschedulable state", experimentsDetails.TargetNode)
-				}
-				return nil
-			})
+		var failedPods []string
+		for _, nodeName := range experimentsDetails.TargetNodeList {
+			log.Infof("[Inject]: Draining the %v node", nodeName)
+			pods, err := evictPods(nodeName, experimentsDetails.ChaosDuration, experimentsDetails.Delay, clients)
+			if err != nil {
+				return err
+			}
+			failedPods = append(failedPods, pods...)
+		}
+		if len(failedPods) != 0 {
+			recordUnevictedPods(experimentsDetails, failedPods, clients, resultDetails, eventsDetails, chaosDetails)
+		}
 	}
 	return nil
 }
 
-// UncordonNode uncordon the application node
-func UncordonNode(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) error {
+// recordUnevictedPods records the pods which are not evicted from the target nodes in the chaosresult
+// and generates a warning event in the chaosengine, the drain itself is not failed for them
+func recordUnevictedPods(experimentsDetails *experimentTypes.ExperimentDetails, failedPods []string, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) {
 
-	log.Infof("[Recover]: Uncordon the %v node", experimentsDetails.TargetNode)
+	log.Warnf("Unable to evict the %v pods within %vs, continuing the drain", strings.Join(failedPods, ","), experimentsDetails.ChaosDuration)
 
-	command := exec.Command("kubectl", "uncordon", experimentsDetails.TargetNode)
-	var out, stderr bytes.Buffer
-	command.Stdout = &out
-	command.Stderr = &stderr
-	if err := command.Run(); err != nil {
-		log.Infof("Error String: %v", stderr.String())
-		return errors.Errorf("Unable to uncordon the %v node, err: %v", experimentsDetails.TargetNode, err)
+	if resultDetails.Annotations == nil {
+		resultDetails.Annotations = map[string]string{}
+	}
+	if value, err := json.Marshal(failedPods); err != nil {
+		log.Warnf("Unable to record the unevicted pods in the chaosresult, err: %v", err)
+	} else {
+		resultDetails.Annotations["litmuschaos.io/node-drain-unevicted-pods"] = string(value)
 	}
 
-	return retry.
-		Times(90).
-		Wait(1 * time.Second).
-		Try(func(attempt uint) error {
-			nodeSpec, err := clients.KubeClient.CoreV1().Nodes().Get(experimentsDetails.TargetNode, v1.GetOptions{})
-			if err != nil {
-				return err
-			}
-			if nodeSpec.Spec.Unschedulable {
-				return errors.Errorf("%v node is in unschedulable state", experimentsDetails.TargetNode)
-			}
-			return nil
-		})
+	if experimentsDetails.EngineName != "" {
+		msg := "Unable to evict the " + strings.Join(failedPods, ",") + " pods from the " + strings.Join(experimentsDetails.TargetNodeList, ",") + " node"
+		types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Warning", chaosDetails)
+		events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+	}
+}
+
+// UncordonNode restore the cordon state of the target nodes
+// the nodes which were already cordoned before the chaos are kept unschedulable
+// a node which fails to uncordon doesn't stop the uncordon of the other nodes
+func UncordonNode(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) error {
+
+	var failed []string
+	for _, nodeName := range experimentsDetails.TargetNodeList {
+		unschedulable, ok := getOriginalCordonState(nodeName)
+		if !ok {
+			continue
+		}
+		if unschedulable {
+			log.Infof("[Recover]: The %v node was cordoned before the chaos, skipping the uncordon", nodeName)
+			continue
+		}
+
+		log.Infof("[Recover]: Uncordon the %v node", nodeName)
+		if err := setNodeUnschedulable(nodeName, false, clients); err != nil {
+			failed = append(failed, fmt.Sprintf("%v node, err: %v", nodeName, err))
+		}
+	}
+	if len(failed) != 0 {
+		return errors.Errorf("Unable to uncordon the %v", strings.Join(failed, "; "))
+	}
+	return nil
 }
 
 // abortWatcher continuosly watch for the abort signals
@@ -194,7 +218,7 @@ func abortWatcher(experimentsDetails *experimentTypes.ExperimentDetails, clients
 			retry := 3
 			for retry > 0 {
 				if err := UncordonNode(experimentsDetails, clients); err != nil {
-					log.Errorf("Unable to uncordon the node, err: %v", err)
+					log.Errorf("Unable to uncordon the nodes, err: %v", err)
 				}
 				retry--
 				time.Sleep(1 * time.Second)
@@ -218,3 +242,4 @@ func abortWatcher(experimentsDetails *experimentTypes.ExperimentDetails, clients
 		}
 	}
 }
+
@@ -6,6 +6,7 @@ import (
 	clients "github.com/litmuschaos/litmus-go/pkg/clients"
 	"github.com/litmuschaos/litmus-go/pkg/events"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/node-memory-hog/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/litmuschaos/litmus-go/pkg/probe"
 	"github.com/litmuschaos/litmus-go/pkg/status"
@@ -13,7 +14,6 @@ import (
 	"github.com/litmuschaos/litmus-go/pkg/utils/common"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	apiv1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -27,7 +27,7 @@ func PrepareNodeMemoryHog(experimentsDetails *experimentTypes.ExperimentDetails,
 	}
 
 	//Select node for node-memory-hog
-	targetNodeList, err := common.GetNodeList(experimentsDetails.TargetNodes, experimentsDetails.NodesAffectedPerc, clients)
+	targetNodeList, err := common.GetTargetNodes(common.GetNodeSelector(experimentsDetails.NodeTargetDetails, experimentsDetails.AppNS, experimentsDetails.AppLabel), clients)
 	if err != nil {
 		return err
 	}
@@ -52,6 +52,13 @@ func PrepareNodeMemoryHog(experimentsDetails *experimentTypes.ExperimentDetails,
 		if err != nil {
 			return errors.Errorf("Unable to get imagePullSecrets, err: %v", err)
 		}
+		// Get the serviceAccountName, the closed loop helper reads the usage of the target node
+		if experimentsDetails.TargetMemoryUtilisation != 0 {
+			experimentsDetails.ChaosServiceAccount, err = GetServiceAccount(experimentsDetails, clients)
+			if err != nil {
+				return errors.Errorf("Unable to get the serviceAccountName, err: %v", err)
+			}
+		}
 	}
 
 	if experimentsDetails.Sequence == "serial" {
@@ -75,7 +82,7 @@ func PrepareNodeMemoryHog(experimentsDetails *experimentTypes.ExperimentDetails,
 // InjectChaosInSerialMode stress the memory of all the target nodes serially (one by one)
 func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetails, targetNodeList []string, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
-	labelSuffix := common.GetRunID()
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
 
 	// run the probes during chaos
 	if len(resultDetails.ProbeDetails) != 0 {
@@ -84,6 +91,9 @@ func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetai
 		}
 	}
 
+	// utilisation of the target nodes recorded during the chaos, for the closed loop stress
+	samples := map[string][]common.UtilisationSample{}
+
 	for _, appNode := range targetNodeList {
 
 		if experimentsDetails.EngineName != "" {
@@ -98,8 +108,6 @@ func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetai
 			"Memory Consumption Mebibytes":  experimentsDetails.MemoryConsumptionMebibytes,
 		})
 
-		experimentsDetails.RunID = common.GetRunID()
-
 		//Getting node memory details
 		memoryCapacity, memoryAllocatable, err := GetNodeMemoryDetails(appNode, clients)
 		if err != nil {
@@ -113,45 +121,47 @@ func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetai
 		}
 
 		// Creating the helper pod to perform node memory hog
-		err = CreateHelperPod(experimentsDetails, appNode, clients, labelSuffix, MemoryConsumption)
-		if err != nil {
-			return errors.Errorf("Unable to create the helper pod, err: %v", err)
+		if err = CreateHelperPod(experimentsDetails, manager, appNode, MemoryConsumption); err != nil {
+			return err
 		}
 
-		appLabel := "name=" + experimentsDetails.ExperimentName + "-" + experimentsDetails.RunID
-
 		//Checking the status of helper pod
-		log.Info("[Status]: Checking the status of the helper pod")
-		err = status.CheckApplicationStatus(experimentsDetails.ChaosNamespace, appLabel, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
-		if err != nil {
-			common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, chaosDetails, clients)
-			return errors.Errorf("helper pod is not in running state, err: %v", err)
+		if err = manager.WaitForRunning(); err != nil {
+			manager.Cleanup()
+			return err
 		}
+		manager.StreamLogs()
 
 		// Wait till the completion of helper pod
 		log.Infof("[Wait]: Waiting for %vs till the completion of the helper pod", experimentsDetails.ChaosDuration+30)
 
-		podStatus, err := status.WaitForCompletion(experimentsDetails.ChaosNamespace, appLabel, clients, experimentsDetails.ChaosDuration+30, experimentsDetails.ExperimentName)
+		// record the utilisation of the target node during the chaos, for the closed loop stress
+		var recorder *common.UtilisationRecorder
+		if experimentsDetails.TargetMemoryUtilisation != 0 {
+			recorder = common.StartUtilisationRecorder([]string{appNode}, "memory", experimentsDetails.MetricsSource, experimentsDetails.ControlInterval, clients)
+		}
+
+		err = manager.WaitForCompletion(experimentsDetails.ChaosDuration + 30)
+		if recorder != nil {
+			for node, nodeSamples := range recorder.Stop() {
+				samples[node] = nodeSamples
+			}
+			common.RecordUtilisationInResult(resultDetails, samples, "memory", experimentsDetails.TargetMemoryUtilisation)
+		}
 		if err != nil {
-			common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, chaosDetails, clients)
-			return errors.Errorf("helper pod failed due to, err: %v", err)
-		} else if podStatus == "Failed" {
-			return errors.Errorf("helper pod status is %v", podStatus)
+			manager.Cleanup()
+			return err
 		}
 
 		// Checking the status of target nodes
 		log.Info("[Status]: Getting the status of target nodes")
-		err = status.CheckNodeStatus(appNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
-		if err != nil {
-			common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, chaosDetails, clients)
+		if err = status.CheckNodeStatus(appNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
 			log.Warnf("Target nodes are not in the ready state, you may need to manually recover the node, err: %v", err)
 		}
 
 		//Deleting the helper pod
-		log.Info("[Cleanup]: Deleting the helper pod")
-		err = common.DeletePod(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, experimentsDetails.ChaosNamespace, chaosDetails.Timeout, chaosDetails.Delay, clients)
-		if err != nil {
-			return errors.Errorf("Unable to delete the helper pod, err: %v", err)
+		if err = manager.Delete(); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -160,7 +170,7 @@ func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetai
 // InjectChaosInParallelMode stress the memory all the target nodes in parallel mode (all at once)
 func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDetails, targetNodeList []string, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
-	labelSuffix := common.GetRunID()
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
 
 	// run the probes during chaos
 	if len(resultDetails.ProbeDetails) != 0 {
@@ -183,8 +193,6 @@ func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDet
 			"Memory Consumption Mebibytes":  experimentsDetails.MemoryConsumptionMebibytes,
 		})
 
-		experimentsDetails.RunID = common.GetRunID()
-
 		//Getting node memory details
 		memoryCapacity, memoryAllocatable, err := GetNodeMemoryDetails(appNode, clients)
 		if err != nil {
@@ -198,51 +206,47 @@ func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDet
 		}
 
 		// Creating the helper pod to perform node memory hog
-		err = CreateHelperPod(experimentsDetails, appNode, clients, labelSuffix, MemoryConsumption)
-		if err != nil {
-			return errors.Errorf("Unable to create the helper pod, err: %v", err)
+		if err = CreateHelperPod(experimentsDetails, manager, appNode, MemoryConsumption); err != nil {
+			return err
 		}
 	}
 
-	appLabel := "app=" + experimentsDetails.ExperimentName + "-helper-" + labelSuffix
-
 	//Checking the status of helper pod
-	log.Info("[Status]: Checking the status of the helper pod")
-	if err := status.CheckApplicationStatus(experimentsDetails.ChaosNamespace, appLabel, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
-		common.DeleteAllHelperPodBasedOnJobCleanupPolicy(appLabel, chaosDetails, clients)
-		return errors.Errorf("helper pod is not in running state, err: %v", err)
+	if err := manager.WaitForRunning(); err != nil {
+		manager.Cleanup()
+		return err
 	}
+	manager.StreamLogs()
 
 	// Wait till the completion of helper pod
 	log.Infof("[Wait]: Waiting for %vs till the completion of the helper pod", experimentsDetails.ChaosDuration+30)
 
-	podStatus, err := status.WaitForCompletion(experimentsDetails.ChaosNamespace, appLabel, clients, experimentsDetails.ChaosDuration+30, experimentsDetails.ExperimentName)
+	// record the utilisation of the target nodes during the chaos, for the closed loop stress
+	var recorder *common.UtilisationRecorder
+	if experimentsDetails.TargetMemoryUtilisation != 0 {
+		recorder = common.StartUtilisationRecorder(targetNodeList, "memory", experimentsDetails.MetricsSource, experimentsDetails.ControlInterval, clients)
+	}
+
+	err := manager.WaitForCompletion(experimentsDetails.ChaosDuration + 30)
+	if recorder != nil {
+		common.RecordUtilisationInResult(resultDetails, recorder.Stop(), "memory", experimentsDetails.TargetMemoryUtilisation)
+	}
 	if err != nil {
-		common.DeleteAllHelperPodBasedOnJobCleanupPolicy(appLabel, chaosDetails, clients)
-		return errors.Errorf("helper pod failed due to, err: %v", err)
-	} else if podStatus == "Failed" {
-		return errors.Errorf("helper pod status is %v", podStatus)
+		manager.Cleanup()
+		return err
 	}
 
 	for _, appNode := range targetNodeList {
 
 		// Checking the status of application node
 		log.Info("[Status]: Getting the status of application node")
-		err = status.CheckNodeStatus(appNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
-		if err != nil {
-			common.DeleteAllHelperPodBasedOnJobCleanupPolicy(appLabel, chaosDetails, clients)
+		if err = status.CheckNodeStatus(appNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
 			log.Warn("Application node is not in the ready state, you may need to manually recover the node")
 		}
 	}
 
 	//Deleting the helper pod
-	log.Info("[Cleanup]: Deleting the helper pod")
-	err = common.DeleteAllPod(appLabel, experimentsDetails.ChaosNamespace, chaosDetails.Timeout, chaosDetails.Delay, clients)
-	if err != nil {
-		return errors.Errorf("Unable to delete the helper pod, err: %v", err)
-	}
-
-	return nil
+	return manager.Delete()
 }
 
 // GetNodeMemoryDetails will return the total memory capacity and memory allocatable of an application node
@@ -323,47 +327,65 @@ func CalculateMemoryConsumption(experimentsDetails *experimentTypes.ExperimentDe
 	return "", errors.Errorf("please specify the memory consumption value either in percentage or mebibytes in a non-decimal format using respective envs")
 }
 
+// GetHelperManager returns the manager of the helper pods
+func GetHelperManager(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, chaosDetails *types.ChaosDetails) *helper.Manager {
+	return helper.NewManager(helper.Meta{
+		ExperimentName:  experimentsDetails.ExperimentName,
+		Namespace:       experimentsDetails.ChaosNamespace,
+		ChaosUID:        experimentsDetails.ChaosUID,
+		Image:           experimentsDetails.LIBImage,
+		ImagePullPolicy: experimentsDetails.LIBImagePullPolicy,
+	}, clients, chaosDetails)
+}
+
 // CreateHelperPod derive the attributes for helper pod and create the helper pod
-func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, appNode string, clients clients.ClientSets, labelSuffix, MemoryConsumption string) error {
-
-	helperPod := &apiv1.Pod{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      experimentsDetails.ExperimentName + "-" + experimentsDetails.RunID,
-			Namespace: experimentsDetails.ChaosNamespace,
-			Labels: map[string]string{
-				"app":                       experimentsDetails.ExperimentName + "-helper-" + labelSuffix,
-				"name":                      experimentsDetails.ExperimentName + "-" + experimentsDetails.RunID,
-				"chaosUID":                  string(experimentsDetails.ChaosUID),
-				"app.kubernetes.io/part-of": "litmus",
-			},
-			Annotations: experimentsDetails.Annotations,
-		},
-		Spec: apiv1.PodSpec{
-			RestartPolicy:    apiv1.RestartPolicyNever,
-			ImagePullSecrets: experimentsDetails.ImagePullSecrets,
-			NodeName:         appNode,
-			Containers: []apiv1.Container{
-				{
-					Name:            experimentsDetails.ExperimentName,
-					Image:           experimentsDetails.LIBImage,
-					ImagePullPolicy: apiv1.PullPolicy(experimentsDetails.LIBImagePullPolicy),
-					Command: []string{
-						"stress-ng",
-					},
-					Args: []string{
-						"--vm",
-						strconv.Itoa(experimentsDetails.NumberOfWorkers),
-						"--vm-bytes",
-						MemoryConsumption,
-						"--timeout",
-						strconv.Itoa(experimentsDetails.ChaosDuration) + "s",
-					},
-					Resources: experimentsDetails.Resources,
-				},
-			},
-		},
+func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, manager *helper.Manager, appNode, MemoryConsumption string) error {
+
+	opts := []helper.Option{
+		helper.WithNodeName(appNode),
+		helper.WithAnnotations(experimentsDetails.Annotations),
+		helper.WithImagePullSecrets(experimentsDetails.ImagePullSecrets),
+		helper.WithResources(experimentsDetails.Resources),
 	}
 
-	_, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaosNamespace).Create(helperPod)
-	return err
+	// the closed loop helper adjusts the stress, so that the node sits at the target utilisation
+	if experimentsDetails.TargetMemoryUtilisation != 0 {
+		opts = append(opts,
+			helper.WithServiceAccount(experimentsDetails.ChaosServiceAccount),
+			helper.WithCommand([]string{"/bin/bash"}, "-c", "./helper/node-stress"),
+			helper.WithEnv(GetPodEnv(experimentsDetails, appNode)),
+			helper.WithPodNameEnv(),
+		)
+	} else {
+		opts = append(opts, helper.WithCommand([]string{"stress-ng"}, "--vm", strconv.Itoa(experimentsDetails.NumberOfWorkers), "--vm-bytes", MemoryConsumption, "--timeout", strconv.Itoa(experimentsDetails.ChaosDuration)+"s"))
+	}
+	return manager.Create(manager.NewPod(opts...))
+}
+
+// GetPodEnv derive all the env required for the closed loop helper pod
+func GetPodEnv(experimentsDetails *experimentTypes.ExperimentDetails, appNode string) map[string]string {
+
+	return map[string]string{
+		"STRESS_TYPE":          "memory",
+		"TARGET_UTILISATION":   strconv.Itoa(experimentsDetails.TargetMemoryUtilisation),
+		"CONTROL_INTERVAL":     strconv.Itoa(experimentsDetails.ControlInterval),
+		"METRICS_SOURCE":       experimentsDetails.MetricsSource,
+		"TOTAL_CHAOS_DURATION": strconv.Itoa(experimentsDetails.ChaosDuration),
+		"CHAOS_NAMESPACE":      experimentsDetails.ChaosNamespace,
+		"CHAOS_ENGINE":         experimentsDetails.EngineName,
+		"CHAOS_UID":            string(experimentsDetails.ChaosUID),
+		"EXPERIMENT_NAME":      experimentsDetails.ExperimentName,
+		"INSTANCE_ID":          experimentsDetails.InstanceID,
+		"NODE_NAME":            appNode,
+	}
 }
+
+// GetServiceAccount find the serviceAccountName for the helper pod
+func GetServiceAccount(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (string, error) {
+	pod, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaosNamespace).Get(experimentsDetails.ChaosPodName, v1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	return pod.Spec.ServiceAccountName, nil
+}
+
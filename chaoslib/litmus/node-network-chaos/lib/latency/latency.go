@@ -0,0 +1,27 @@
+package latency
+
+import (
+	"strconv"
+
+	node_network_chaos "github.com/litmuschaos/litmus-go/chaoslib/litmus/node-network-chaos/lib"
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/node-network-chaos/types"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+)
+
+//NodeNetworkLatencyChaos contains the steps to prepare and inject chaos
+func NodeNetworkLatencyChaos(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	targetNodeList, err := node_network_chaos.GetTargetNodes(experimentsDetails, clients)
+	if err != nil {
+		return err
+	}
+
+	args := "delay " + strconv.Itoa(experimentsDetails.NetworkLatency) + "ms"
+	err = node_network_chaos.PrepareAndInjectChaos(experimentsDetails, clients, resultDetails, eventsDetails, chaosDetails, targetNodeList, args)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package loss
+
+import (
+	"strconv"
+
+	node_network_chaos "github.com/litmuschaos/litmus-go/chaoslib/litmus/node-network-chaos/lib"
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/node-network-chaos/types"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+)
+
+//NodeNetworkLossChaos contains the steps to prepare and inject chaos
+func NodeNetworkLossChaos(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	targetNodeList, err := node_network_chaos.GetTargetNodes(experimentsDetails, clients)
+	if err != nil {
+		return err
+	}
+
+	args := "loss " + strconv.Itoa(experimentsDetails.NetworkPacketLossPercentage)
+	err = node_network_chaos.PrepareAndInjectChaos(experimentsDetails, clients, resultDetails, eventsDetails, chaosDetails, targetNodeList, args)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,330 @@
+package lib
+
+import (
+	"strconv"
+	"strings"
+
+	network_chaos "github.com/litmuschaos/litmus-go/chaoslib/litmus/network-chaos/lib"
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/node-network-chaos/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/probe"
+	"github.com/litmuschaos/litmus-go/pkg/status"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var err error
+
+//PrepareAndInjectChaos contains the prepration & injection steps
+// the helper pods share the network-chaos helper, which applies the netem rules inside the host network namespace of the target nodes
+func PrepareAndInjectChaos(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails, targetNodeList []string, args string) error {
+
+	log.InfoWithValues("[Info]: Details of Nodes under chaos injection", logrus.Fields{
+		"No. Of Nodes": len(targetNodeList),
+		"Node Names":   targetNodeList,
+	})
+
+	//Waiting for the ramp time before chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time before injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+
+	// Getting the serviceAccountName, need permission inside helper pod to create the events
+	if experimentsDetails.ChaosServiceAccount == "" {
+		experimentsDetails.ChaosServiceAccount, err = GetServiceAccount(experimentsDetails, clients)
+		if err != nil {
+			return errors.Errorf("Unable to get the serviceAccountName, err: %v", err)
+		}
+	}
+
+	experimentsDetails.DestinationIPs, err = network_chaos.GetTargetIps(experimentsDetails.DestinationIPs, experimentsDetails.DestinationHosts)
+	if err != nil {
+		return err
+	}
+
+	experimentsDetails.ExcludedIPs, err = GetExcludedIPs(experimentsDetails, clients)
+	if err != nil {
+		return err
+	}
+
+	if experimentsDetails.EngineName != "" {
+		// Get Chaos Pod Annotation
+		experimentsDetails.Annotations, err = common.GetChaosPodAnnotation(experimentsDetails.ChaosPodName, experimentsDetails.ChaosNamespace, clients)
+		if err != nil {
+			return errors.Errorf("unable to get annotations, err: %v", err)
+		}
+		// Get Resource Requirements
+		experimentsDetails.Resources, err = common.GetChaosPodResourceRequirements(experimentsDetails.ChaosPodName, experimentsDetails.ExperimentName, experimentsDetails.ChaosNamespace, clients)
+		if err != nil {
+			return errors.Errorf("Unable to get resource requirements, err: %v", err)
+		}
+		// Get ImagePullSecrets
+		experimentsDetails.ImagePullSecrets, err = common.GetImagePullSecrets(experimentsDetails.ChaosPodName, experimentsDetails.ChaosNamespace, clients)
+		if err != nil {
+			return errors.Errorf("Unable to get imagePullSecrets, err: %v", err)
+		}
+	}
+
+	if experimentsDetails.Sequence == "serial" {
+		if err = InjectChaosInSerialMode(experimentsDetails, targetNodeList, clients, chaosDetails, args, resultDetails, eventsDetails); err != nil {
+			return err
+		}
+	} else {
+		if err = InjectChaosInParallelMode(experimentsDetails, targetNodeList, clients, chaosDetails, args, resultDetails, eventsDetails); err != nil {
+			return err
+		}
+	}
+
+	//Waiting for the ramp time after chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time after injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+	return nil
+}
+
+// InjectChaosInSerialMode inject the network chaos in all the target nodes serially (one by one)
+func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetails, targetNodeList []string, clients clients.ClientSets, chaosDetails *types.ChaosDetails, args string, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails) error {
+
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
+
+	// run the probes during chaos
+	if len(resultDetails.ProbeDetails) != 0 {
+		if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+			return err
+		}
+	}
+
+	// creating the helper pod to perform network chaos
+	for _, appNode := range targetNodeList {
+
+		if experimentsDetails.EngineName != "" {
+			msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on " + appNode + " node"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		log.InfoWithValues("[Info]: Details of node under chaos injection", logrus.Fields{
+			"NodeName":         appNode,
+			"NetworkInterface": experimentsDetails.NetworkInterface,
+		})
+		if err = CreateHelperPod(experimentsDetails, manager, appNode, args); err != nil {
+			return err
+		}
+
+		//checking the status of the helper pods, wait till the pod comes to running state else fail the experiment
+		if err := manager.WaitForRunning(); err != nil {
+			manager.Cleanup()
+			return err
+		}
+		manager.StreamLogs()
+
+		// Wait till the completion of the helper pod
+		// set an upper limit for the waiting time
+		log.Info("[Wait]: waiting till the completion of the helper pod")
+		if err := manager.WaitForCompletion(experimentsDetails.ChaosDuration + 60); err != nil {
+			manager.Cleanup()
+			return err
+		}
+
+		// Checking the status of target nodes
+		log.Info("[Status]: Getting the status of target nodes")
+		if err = status.CheckNodeStatus(appNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
+			log.Warnf("Target nodes are not in the ready state, you may need to manually recover the node, err: %v", err)
+		}
+
+		//Deleting the helper pod
+		if err := manager.Delete(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InjectChaosInParallelMode inject the network chaos in all the target nodes in parallel mode (all at once)
+func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDetails, targetNodeList []string, clients clients.ClientSets, chaosDetails *types.ChaosDetails, args string, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails) error {
+
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
+
+	// run the probes during chaos
+	if len(resultDetails.ProbeDetails) != 0 {
+		if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+			return err
+		}
+	}
+
+	// creating the helper pod to perform network chaos
+	for _, appNode := range targetNodeList {
+
+		if experimentsDetails.EngineName != "" {
+			msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on " + appNode + " node"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		log.InfoWithValues("[Info]: Details of node under chaos injection", logrus.Fields{
+			"NodeName":         appNode,
+			"NetworkInterface": experimentsDetails.NetworkInterface,
+		})
+		if err = CreateHelperPod(experimentsDetails, manager, appNode, args); err != nil {
+			return err
+		}
+	}
+
+	//checking the status of the helper pods, wait till the pod comes to running state else fail the experiment
+	if err := manager.WaitForRunning(); err != nil {
+		manager.Cleanup()
+		return err
+	}
+	manager.StreamLogs()
+
+	// Wait till the completion of the helper pod
+	// set an upper limit for the waiting time
+	log.Info("[Wait]: waiting till the completion of the helper pod")
+	if err := manager.WaitForCompletion(experimentsDetails.ChaosDuration + 60); err != nil {
+		manager.Cleanup()
+		return err
+	}
+
+	// Checking the status of target nodes
+	log.Info("[Status]: Getting the status of target nodes")
+	if err = status.CheckNodeStatus(strings.Join(targetNodeList, ","), experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
+		log.Warnf("Target nodes are not in the ready state, you may need to manually recover the node, err: %v", err)
+	}
+
+	//Deleting the helper pod
+	return manager.Delete()
+}
+
+// GetTargetNodes derive the target nodes using the node selection criteria of the experiment
+func GetTargetNodes(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) ([]string, error) {
+	return common.GetTargetNodes(common.GetNodeSelector(experimentsDetails.NodeTargetDetails, experimentsDetails.AppNS, experimentsDetails.AppLabel), clients)
+}
+
+// GetServiceAccount find the serviceAccountName for the helper pod
+func GetServiceAccount(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (string, error) {
+	pod, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaosNamespace).Get(experimentsDetails.ChaosPodName, v1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	return pod.Spec.ServiceAccountName, nil
+}
+
+// GetExcludedIPs return the comma separated ips which are excluded from the chaos
+// it appends the kubernetes service and api server endpoint ips, if the api server is protected
+// so that the kubelet and the helper pods can still reach the api server during the chaos
+func GetExcludedIPs(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (string, error) {
+
+	var excludedIPs []string
+	if experimentsDetails.ExcludedIPs != "" {
+		excludedIPs = strings.Split(experimentsDetails.ExcludedIPs, ",")
+	}
+
+	if experimentsDetails.ProtectAPIServer {
+		service, err := clients.KubeClient.CoreV1().Services("default").Get("kubernetes", v1.GetOptions{})
+		if err != nil {
+			return "", errors.Errorf("Unable to get the kubernetes service, err: %v", err)
+		}
+		excludedIPs = append(excludedIPs, service.Spec.ClusterIP)
+
+		endpoints, err := clients.KubeClient.CoreV1().Endpoints("default").Get("kubernetes", v1.GetOptions{})
+		if err != nil {
+			return "", errors.Errorf("Unable to get the kubernetes endpoints, err: %v", err)
+		}
+		for _, subset := range endpoints.Subsets {
+			for _, address := range subset.Addresses {
+				excludedIPs = append(excludedIPs, address.IP)
+			}
+		}
+	}
+
+	if len(excludedIPs) != 0 {
+		log.Infof("[Info]: The %v ips are excluded from the chaos", excludedIPs)
+	}
+	return strings.Join(excludedIPs, ","), nil
+}
+
+// GetNodeIPs return the comma separated internal ips of all the nodes except the target nodes
+func GetNodeIPs(targetNodeList []string, clients clients.ClientSets) (string, error) {
+
+	nodes, err := clients.KubeClient.CoreV1().Nodes().List(v1.ListOptions{})
+	if err != nil {
+		return "", errors.Errorf("Failed to list the nodes, err: %v", err)
+	}
+
+	targetNodes := map[string]bool{}
+	for _, node := range targetNodeList {
+		targetNodes[node] = true
+	}
+
+	var nodeIPs []string
+	for _, node := range nodes.Items {
+		if targetNodes[node.Name] {
+			continue
+		}
+		for _, address := range node.Status.Addresses {
+			if address.Type == apiv1.NodeInternalIP {
+				nodeIPs = append(nodeIPs, address.Address)
+			}
+		}
+	}
+	return strings.Join(nodeIPs, ","), nil
+}
+
+// GetHelperManager returns the manager of the helper pods
+func GetHelperManager(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, chaosDetails *types.ChaosDetails) *helper.Manager {
+	return helper.NewManager(helper.Meta{
+		ExperimentName:  experimentsDetails.ExperimentName,
+		Namespace:       experimentsDetails.ChaosNamespace,
+		ChaosUID:        experimentsDetails.ChaosUID,
+		Image:           experimentsDetails.LIBImage,
+		ImagePullPolicy: experimentsDetails.LIBImagePullPolicy,
+	}, clients, chaosDetails)
+}
+
+// CreateHelperPod derive the attributes for helper pod and create the helper pod
+func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, manager *helper.Manager, nodeName, args string) error {
+
+	return manager.Create(manager.NewPod(
+		helper.WithNodeName(nodeName),
+		helper.WithHostPID(),
+		helper.WithTerminationGracePeriod(experimentsDetails.TerminationGracePeriodSeconds),
+		helper.WithServiceAccount(experimentsDetails.ChaosServiceAccount),
+		helper.WithAnnotations(experimentsDetails.Annotations),
+		helper.WithImagePullSecrets(experimentsDetails.ImagePullSecrets),
+		helper.WithResources(experimentsDetails.Resources),
+		helper.WithCommand([]string{"/bin/bash"}, "-c", "./helper/network-chaos"),
+		helper.WithEnv(GetPodEnv(experimentsDetails, nodeName, args)),
+		helper.WithPodNameEnv(),
+		// nsenter into the network namespace of the node needs SYS_ADMIN & SYS_PTRACE, tc needs NET_ADMIN
+		helper.WithCapabilities("NET_ADMIN", "SYS_ADMIN", "SYS_PTRACE"),
+	))
+}
+
+// GetPodEnv derive all the env required for the helper pod
+func GetPodEnv(experimentsDetails *experimentTypes.ExperimentDetails, nodeName, args string) map[string]string {
+
+	return map[string]string{
+		"TOTAL_CHAOS_DURATION": strconv.Itoa(experimentsDetails.ChaosDuration),
+		"CHAOS_NAMESPACE":      experimentsDetails.ChaosNamespace,
+		"CHAOS_ENGINE":         experimentsDetails.EngineName,
+		"CHAOS_UID":            string(experimentsDetails.ChaosUID),
+		"NETEM_COMMAND":        args,
+		"NETWORK_INTERFACE":    experimentsDetails.NetworkInterface,
+		"NETWORK_NAMESPACE":    "host",
+		"EXPERIMENT_NAME":      experimentsDetails.ExperimentName,
+		"DESTINATION_IPS":      experimentsDetails.DestinationIPs,
+		"EXCLUDED_IPS":         experimentsDetails.ExcludedIPs,
+		"TARGET_NODE":          nodeName,
+	}
+}
+
@@ -0,0 +1,40 @@
+package partition
+
+import (
+	node_network_chaos "github.com/litmuschaos/litmus-go/chaoslib/litmus/node-network-chaos/lib"
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/node-network-chaos/types"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/pkg/errors"
+)
+
+//NodeNetworkPartitionChaos contains the steps to prepare and inject chaos
+// it blackholes the traffic from the target nodes to the destination ips
+// the destination defaults to the internal ips of all the other nodes, which partitions the target nodes from the rest of the cluster
+func NodeNetworkPartitionChaos(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	targetNodeList, err := node_network_chaos.GetTargetNodes(experimentsDetails, clients)
+	if err != nil {
+		return err
+	}
+
+	if experimentsDetails.DestinationIPs == "" && experimentsDetails.DestinationHosts == "" {
+		experimentsDetails.DestinationIPs, err = node_network_chaos.GetNodeIPs(targetNodeList, clients)
+		if err != nil {
+			return err
+		}
+		if experimentsDetails.DestinationIPs == "" {
+			return errors.Errorf("no other node found to partition the target nodes from")
+		}
+		log.Infof("[Info]: Partitioning the target nodes from the %v node ips", experimentsDetails.DestinationIPs)
+	}
+
+	args := "loss 100%"
+	err = node_network_chaos.PrepareAndInjectChaos(experimentsDetails, clients, resultDetails, eventsDetails, chaosDetails, targetNodeList, args)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -2,13 +2,12 @@ package lib
 
 import (
 	"fmt"
-	"math/rand"
 	"strconv"
-	"time"
 
 	clients "github.com/litmuschaos/litmus-go/pkg/clients"
 	"github.com/litmuschaos/litmus-go/pkg/events"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/node-restart/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/litmuschaos/litmus-go/pkg/probe"
 	"github.com/litmuschaos/litmus-go/pkg/status"
@@ -17,7 +16,6 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	apiv1 "k8s.io/api/core/v1"
-	k8stypes "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	schedulerapi "k8s.io/kubernetes/pkg/scheduler/api"
 )
@@ -31,8 +29,8 @@ const (
 	emptyDirMount   string = "/data"
 	emptyDirPath    string = "/data/ssh-privatekey"
 
-	privateKeySecret string = "private-key-cm-"
-	emptyDirVolume   string = "empty-dir-"
+	privateKeySecret string = "private-key-cm"
+	emptyDirVolume   string = "empty-dir"
 )
 
 // PrepareNodeRestart contains preparation steps before chaos injection
@@ -41,18 +39,25 @@ func PrepareNodeRestart(experimentsDetails *experimentTypes.ExperimentDetails, c
 	//Select the node
 	if experimentsDetails.TargetNode == "" {
 		//Select node for node-restart
-		targetNode, err := GetNode(experimentsDetails, clients)
+		targetNodeList, err := common.GetTargetNodes(common.GetNodeSelector(experimentsDetails.NodeTargetDetails, experimentsDetails.AppNS, experimentsDetails.AppLabel), clients)
 		if err != nil {
 			return err
 		}
+		experimentsDetails.TargetNode = targetNodeList[0]
+	}
 
-		experimentsDetails.TargetNode = targetNode.Spec.NodeName
-		experimentsDetails.TargetNodeIP = targetNode.Status.HostIP
+	// the node ip is required only to reach the node over ssh
+	if experimentsDetails.RebootMethod == "ssh" && experimentsDetails.TargetNodeIP == "" {
+		experimentsDetails.TargetNodeIP, err = GetInternalIP(experimentsDetails.TargetNode, clients)
+		if err != nil {
+			return err
+		}
 	}
 
 	log.InfoWithValues("[Info]: Details of application under chaos injection", logrus.Fields{
 		"Target Node":    experimentsDetails.TargetNode,
 		"Target Node IP": experimentsDetails.TargetNodeIP,
+		"Reboot Method":  experimentsDetails.RebootMethod,
 	})
 
 	// Checking the status of target node
@@ -62,8 +67,13 @@ func PrepareNodeRestart(experimentsDetails *experimentTypes.ExperimentDetails, c
 		return errors.Errorf("Target node is not in ready state, err: %v", err)
 	}
 
-	experimentsDetails.RunID = common.GetRunID()
-	appLabel := "name=" + experimentsDetails.ExperimentName + "-" + experimentsDetails.RunID
+	// recording the boot id of the target node, to verify the reboot post chaos
+	preChaosBootID, err := GetBootID(experimentsDetails.TargetNode, clients)
+	if err != nil {
+		return err
+	}
+
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
 
 	//Waiting for the ramp time before chaos injection
 	if experimentsDetails.RampTime != 0 {
@@ -77,67 +87,65 @@ func PrepareNodeRestart(experimentsDetails *experimentTypes.ExperimentDetails, c
 		events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
 	}
 
-	if experimentsDetails.EngineName != "" {
-		// Get Chaos Pod Annotation
-		experimentsDetails.Annotations, err = common.GetChaosPodAnnotation(experimentsDetails.ChaosPodName, experimentsDetails.ChaosNamespace, clients)
-		if err != nil {
-			return errors.Errorf("unable to get annotations, err: %v", err)
+	switch experimentsDetails.RebootMethod {
+	case "ssh", "nsenter", "sysrq":
+		if err := createRebootHelperPod(experimentsDetails, manager, clients); err != nil {
+			return err
 		}
-		// Get Resource Requirements
-		experimentsDetails.Resources, err = common.GetChaosPodResourceRequirements(experimentsDetails.ChaosPodName, experimentsDetails.ExperimentName, experimentsDetails.ChaosNamespace, clients)
-		if err != nil {
-			return errors.Errorf("Unable to get resource requirements, err: %v", err)
+	case "cloud":
+		if err := RebootWithCloudProvider(experimentsDetails, clients); err != nil {
+			return errors.Errorf("Unable to reboot the %v node through the cloud provider, err: %v", experimentsDetails.TargetNode, err)
 		}
-		// Get ImagePullSecrets
-		experimentsDetails.ImagePullSecrets, err = common.GetImagePullSecrets(experimentsDetails.ChaosPodName, experimentsDetails.ChaosNamespace, clients)
-		if err != nil {
-			return errors.Errorf("Unable to get imagePullSecrets, err: %v", err)
-		}
-	}
-	// Creating the helper pod to perform node restart
-	err = CreateHelperPod(experimentsDetails, clients)
-	if err != nil {
-		return errors.Errorf("Unable to create the helper pod, err: %v", err)
-	}
-
-	//Checking the status of helper pod
-	log.Info("[Status]: Checking the status of the helper pod")
-	err = CheckApplicationStatus(experimentsDetails.ChaosNamespace, appLabel, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
-	if err != nil {
-		common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, chaosDetails, clients)
-		return errors.Errorf("helper pod is not in running state, err: %v", err)
+	default:
+		return errors.Errorf("%v reboot method is not supported, supported methods are ssh, nsenter, sysrq and cloud", experimentsDetails.RebootMethod)
 	}
 
 	// run the probes during chaos
 	if len(resultDetails.ProbeDetails) != 0 {
 		if err = probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
-			common.DeleteAllHelperPodBasedOnJobCleanupPolicy(appLabel, chaosDetails, clients)
+			manager.Cleanup()
 			return err
 		}
 	}
 
-	// Wait till the completion of helper pod
-	log.Infof("[Wait]: Waiting for %vs till the completion of the helper pod", strconv.Itoa(experimentsDetails.ChaosDuration+30))
+	// Checking for the node to be in not-ready state
+	// it is best-effort, as a node rebooting faster than the node monitor grace period never turns NotReady
+	if experimentsDetails.VerifyNodeNotReady {
+		log.Infof("[Status]: Check for the %v node to be in NotReady state", experimentsDetails.TargetNode)
+		if err = status.CheckNodeNotReadyState(experimentsDetails.TargetNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
+			log.Warnf("The %v node is not observed in NotReady state, it may have rebooted within the node monitor grace period, err: %v", experimentsDetails.TargetNode, err)
+		}
+	}
+
+	// the ssh helper runs outside the target node, so it completes once the reboot command is issued
+	// the helpers running on the target node are disrupted by the reboot itself
+	if experimentsDetails.RebootMethod == "ssh" {
+		// Wait till the completion of helper pod
+		log.Infof("[Wait]: Waiting for %vs till the completion of the helper pod", strconv.Itoa(experimentsDetails.ChaosDuration+30))
 
-	podStatus, err := status.WaitForCompletion(experimentsDetails.ChaosNamespace, appLabel, clients, experimentsDetails.ChaosDuration+30, experimentsDetails.ExperimentName)
-	if err != nil || podStatus == "Failed" {
-		common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, chaosDetails, clients)
-		return errors.Errorf("helper pod failed due to, err: %v", err)
+		if err := manager.WaitForCompletion(experimentsDetails.ChaosDuration + 30); err != nil {
+			manager.Cleanup()
+			return err
+		}
 	}
 
 	// Checking the status of application node
 	log.Info("[Status]: Getting the status of application node")
-	err = status.CheckNodeStatus(experimentsDetails.TargetNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
-	if err != nil {
-		common.DeleteHelperPodBasedOnJobCleanupPolicy(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, chaosDetails, clients)
-		log.Warnf("Application node is not in the ready state, you may need to manually recover the node, err: %v", err)
+	if err = status.CheckNodeStatus(experimentsDetails.TargetNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
+		manager.Cleanup()
+		return errors.Errorf("%v node is not recovered after the chaos, you may need to manually recover the node, err: %v", experimentsDetails.TargetNode, err)
 	}
 
-	//Deleting the helper pod
-	log.Info("[Cleanup]: Deleting the helper pod")
-	err = common.DeletePod(experimentsDetails.ExperimentName+"-"+experimentsDetails.RunID, appLabel, experimentsDetails.ChaosNamespace, chaosDetails.Timeout, chaosDetails.Delay, clients)
-	if err != nil {
-		return errors.Errorf("Unable to delete the helper pod, err: %v", err)
+	if err = VerifyBootIDChanged(experimentsDetails.TargetNode, preChaosBootID, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
+		manager.Cleanup()
+		return err
+	}
+
+	if experimentsDetails.RebootMethod != "cloud" {
+		//Deleting the helper pod
+		if err = manager.Delete(); err != nil {
+			return err
+		}
 	}
 
 	//Waiting for the ramp time after chaos injection
@@ -148,118 +156,118 @@ func PrepareNodeRestart(experimentsDetails *experimentTypes.ExperimentDetails, c
 	return nil
 }
 
+// createRebootHelperPod creates the helper pod of the ssh, nsenter or sysrq reboot method and waits for it to be running
+func createRebootHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, manager *helper.Manager, clients clients.ClientSets) error {
+
+	if experimentsDetails.EngineName != "" {
+		// Get Chaos Pod Annotation
+		experimentsDetails.Annotations, err = common.GetChaosPodAnnotation(experimentsDetails.ChaosPodName, experimentsDetails.ChaosNamespace, clients)
+		if err != nil {
+			return errors.Errorf("unable to get annotations, err: %v", err)
+		}
+		// Get Resource Requirements
+		experimentsDetails.Resources, err = common.GetChaosPodResourceRequirements(experimentsDetails.ChaosPodName, experimentsDetails.ExperimentName, experimentsDetails.ChaosNamespace, clients)
+		if err != nil {
+			return errors.Errorf("Unable to get resource requirements, err: %v", err)
+		}
+		// Get ImagePullSecrets
+		experimentsDetails.ImagePullSecrets, err = common.GetImagePullSecrets(experimentsDetails.ChaosPodName, experimentsDetails.ChaosNamespace, clients)
+		if err != nil {
+			return errors.Errorf("Unable to get imagePullSecrets, err: %v", err)
+		}
+	}
+
+	// Creating the helper pod to perform node restart
+	if experimentsDetails.RebootMethod == "ssh" {
+		err = CreateHelperPod(experimentsDetails, manager)
+	} else {
+		err = CreateHostRebootHelperPod(experimentsDetails, manager)
+	}
+	if err != nil {
+		return err
+	}
+
+	//Checking the status of helper pod
+	if err = manager.WaitForRunning(); err != nil {
+		manager.Cleanup()
+		return err
+	}
+	manager.StreamLogs()
+	return nil
+}
+
+// GetHelperManager returns the manager of the helper pods
+func GetHelperManager(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, chaosDetails *types.ChaosDetails) *helper.Manager {
+	return helper.NewManager(helper.Meta{
+		ExperimentName:  experimentsDetails.ExperimentName,
+		Namespace:       experimentsDetails.ChaosNamespace,
+		ChaosUID:        experimentsDetails.ChaosUID,
+		Image:           experimentsDetails.LIBImage,
+		ImagePullPolicy: experimentsDetails.LIBImagePullPolicy,
+	}, clients, chaosDetails)
+}
+
 // CreateHelperPod derive the attributes for helper pod and create the helper pod
-func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) error {
+// it is scheduled on any node other than the target node, which reboots the target node over the ssh
+func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, manager *helper.Manager) error {
 	// This method is attaching emptyDir along with secret volume, and copy data from secret
 	// to the emptyDir, because secret is mounted as readonly and with 777 perms and it can't be changed
 	// because of: https://github.com/kubernetes/kubernetes/issues/57923
 
-	helperPod := &apiv1.Pod{
-		ObjectMeta: v1.ObjectMeta{
-			Name:      experimentsDetails.ExperimentName + "-" + experimentsDetails.RunID,
-			Namespace: experimentsDetails.ChaosNamespace,
-			Labels: map[string]string{
-				"app":                       experimentsDetails.ExperimentName + "-" + "helper",
-				"name":                      experimentsDetails.ExperimentName + "-" + experimentsDetails.RunID,
-				"chaosUID":                  string(experimentsDetails.ChaosUID),
-				"app.kubernetes.io/part-of": "litmus",
-			},
-			Annotations: experimentsDetails.Annotations,
-		},
-		Spec: apiv1.PodSpec{
-			RestartPolicy:    apiv1.RestartPolicyNever,
-			ImagePullSecrets: experimentsDetails.ImagePullSecrets,
-			Affinity: &k8stypes.Affinity{
-				NodeAffinity: &k8stypes.NodeAffinity{
-					RequiredDuringSchedulingIgnoredDuringExecution: &k8stypes.NodeSelector{
-						NodeSelectorTerms: []k8stypes.NodeSelectorTerm{
+	affinity := &apiv1.Affinity{
+		NodeAffinity: &apiv1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &apiv1.NodeSelector{
+				NodeSelectorTerms: []apiv1.NodeSelectorTerm{
+					{
+						MatchFields: []apiv1.NodeSelectorRequirement{
 							{
-								MatchFields: []k8stypes.NodeSelectorRequirement{
-									{
-										Key:      schedulerapi.NodeFieldSelectorKeyNodeName,
-										Operator: k8stypes.NodeSelectorOpNotIn,
-										Values:   []string{experimentsDetails.TargetNode},
-									},
-								},
+								Key:      schedulerapi.NodeFieldSelectorKeyNodeName,
+								Operator: apiv1.NodeSelectorOpNotIn,
+								Values:   []string{experimentsDetails.TargetNode},
 							},
 						},
 					},
 				},
 			},
-			Containers: []apiv1.Container{
-				{
-					Name:            experimentsDetails.ExperimentName,
-					Image:           experimentsDetails.LIBImage,
-					ImagePullPolicy: apiv1.PullPolicy(experimentsDetails.LIBImagePullPolicy),
-					Command: []string{
-						"/bin/sh",
-					},
-					Args:      []string{"-c", fmt.Sprintf("cp %[1]s %[2]s && chmod 400 %[2]s && ssh -o \"StrictHostKeyChecking=no\" -o \"UserKnownHostsFile=/dev/null\" -i %[2]s %[3]s@%[4]s %[5]s", privateKeyPath, emptyDirPath, experimentsDetails.SSHUser, experimentsDetails.TargetNodeIP, experimentsDetails.RebootCommand)},
-					Resources: experimentsDetails.Resources,
-					VolumeMounts: []apiv1.VolumeMount{
-						{
-							Name:      privateKeySecret + experimentsDetails.RunID,
-							MountPath: privateKeyMount,
-						},
-						{
-							Name:      emptyDirVolume + experimentsDetails.RunID,
-							MountPath: emptyDirMount,
-						},
-					},
-				},
-			},
-			Volumes: []apiv1.Volume{
-				{
-					Name: privateKeySecret + experimentsDetails.RunID,
-					VolumeSource: apiv1.VolumeSource{
-						Secret: &apiv1.SecretVolumeSource{
-							SecretName: secretName,
-						},
-					},
-				},
-				{
-					Name: emptyDirVolume + experimentsDetails.RunID,
-					VolumeSource: apiv1.VolumeSource{
-						EmptyDir: &apiv1.EmptyDirVolumeSource{},
-					},
-				},
+		},
+	}
+	privateKeyVolume := apiv1.Volume{
+		Name: privateKeySecret,
+		VolumeSource: apiv1.VolumeSource{
+			Secret: &apiv1.SecretVolumeSource{
+				SecretName: secretName,
 			},
 		},
 	}
-
-	_, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaosNamespace).Create(helperPod)
-	return err
-}
-
-//GetNode will select a random replica of application pod and return the node spec of that application pod
-func GetNode(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (*k8stypes.Pod, error) {
-	podList, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.AppNS).List(v1.ListOptions{LabelSelector: experimentsDetails.AppLabel})
-	if err != nil || len(podList.Items) == 0 {
-		return nil, errors.Wrapf(err, "Fail to get the application pod in %v namespace, err: %v", experimentsDetails.AppNS, err)
+	emptyDir := apiv1.Volume{
+		Name: emptyDirVolume,
+		VolumeSource: apiv1.VolumeSource{
+			EmptyDir: &apiv1.EmptyDirVolumeSource{},
+		},
 	}
 
-	rand.Seed(time.Now().Unix())
-	randomIndex := rand.Intn(len(podList.Items))
-	podForNodeCandidate := podList.Items[randomIndex]
-
-	return &podForNodeCandidate, nil
+	return manager.Create(manager.NewPod(
+		helper.WithAffinity(affinity),
+		helper.WithAnnotations(experimentsDetails.Annotations),
+		helper.WithImagePullSecrets(experimentsDetails.ImagePullSecrets),
+		helper.WithResources(experimentsDetails.Resources),
+		helper.WithCommand([]string{"/bin/sh"}, "-c", fmt.Sprintf("cp %[1]s %[2]s && chmod 400 %[2]s && ssh -o \"StrictHostKeyChecking=no\" -o \"UserKnownHostsFile=/dev/null\" -i %[2]s %[3]s@%[4]s %[5]s", privateKeyPath, emptyDirPath, experimentsDetails.SSHUser, experimentsDetails.TargetNodeIP, experimentsDetails.RebootCommand)),
+		helper.WithVolume(privateKeyVolume, privateKeyMount, nil),
+		helper.WithVolume(emptyDir, emptyDirMount, nil),
+	))
 }
 
-// CheckApplicationStatus checks the status of the AUT
-func CheckApplicationStatus(appNs, appLabel string, timeout, delay int, clients clients.ClientSets) error {
-
-	// Checking whether application containers are in ready state
-	log.Info("[Status]: Checking whether application containers are in ready state")
-	err := status.CheckContainerStatus(appNs, appLabel, "", timeout, delay, clients)
+//GetInternalIP returns the internal ip of the node
+func GetInternalIP(nodeName string, clients clients.ClientSets) (string, error) {
+	node, err := clients.KubeClient.CoreV1().Nodes().Get(nodeName, v1.GetOptions{})
 	if err != nil {
-		return err
+		return "", errors.Errorf("Unable to get the %v node, err: %v", nodeName, err)
 	}
-	// Checking whether application pods are in running or completed state
-	log.Info("[Status]: Checking whether application pods are in running or completed state")
-	err = status.CheckPodStatusPhase(appNs, appLabel, timeout, delay, clients, "Running", "Completed")
-	if err != nil {
-		return err
+	for _, address := range node.Status.Addresses {
+		if address.Type == apiv1.NodeInternalIP {
+			return address.Address, nil
+		}
 	}
-
-	return nil
+	return "", errors.Errorf("Unable to find the internal ip of the %v node", nodeName)
 }
+
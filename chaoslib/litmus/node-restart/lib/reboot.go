@@ -0,0 +1,169 @@
+package lib
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	awslib "github.com/litmuschaos/litmus-go/pkg/cloud/aws"
+	"github.com/litmuschaos/litmus-go/pkg/cloud/azure"
+	"github.com/litmuschaos/litmus-go/pkg/cloud/gcp"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/node-restart/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// preRebootDelay is the wait inside the helper pod before triggering the reboot
+// it lets the runner observe the helper pod in running state before the node goes down
+const preRebootDelay = 10
+
+// sysrqRebootCommand enables the sysrq and triggers an immediate reboot, without syncing or unmounting the filesystems
+const sysrqRebootCommand = "echo 1 > /proc/sys/kernel/sysrq && echo b > /proc/sysrq-trigger"
+
+// rebootProvider is the subset of the cloud providers used to reboot the instance of the target node
+type rebootProvider interface {
+	CheckCredentials() error
+	RebootInstance(instanceID string) error
+}
+
+// CreateHostRebootHelperPod creates the privileged helper pod on the target node, which reboots the node from the host namespaces
+// it runs the REBOOT_COMMAND for the nsenter method and triggers the reboot through the sysrq for the sysrq method
+func CreateHostRebootHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, manager *helper.Manager) error {
+
+	rebootCommand := experimentsDetails.RebootCommand
+	if experimentsDetails.RebootMethod == "sysrq" {
+		rebootCommand = sysrqRebootCommand
+	}
+
+	return manager.Create(manager.NewPod(
+		helper.WithNodeName(experimentsDetails.TargetNode),
+		helper.WithHostPID(),
+		helper.WithAnnotations(experimentsDetails.Annotations),
+		helper.WithImagePullSecrets(experimentsDetails.ImagePullSecrets),
+		helper.WithResources(experimentsDetails.Resources),
+		// the delay and the reboot command are passed as the positional parameters of the script, so the command is not quoted into it
+		helper.WithCommand([]string{"/bin/sh"}, "-c", `sleep "$1" && exec sudo nsenter -t 1 -m -u -i -n -p -- sh -c "$2"`, "reboot", strconv.Itoa(preRebootDelay), rebootCommand),
+		// the reboot is triggered from the namespaces of the node, which is allowed only to the privileged containers
+		helper.WithPrivileged(),
+	))
+}
+
+// RebootWithCloudProvider reboots the instance of the target node through the cloud provider api
+// the cloud provider is derived from the provider id of the node, if the CLOUD_PROVIDER is not provided
+func RebootWithCloudProvider(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) error {
+
+	node, err := clients.KubeClient.CoreV1().Nodes().Get(experimentsDetails.TargetNode, v1.GetOptions{})
+	if err != nil {
+		return errors.Errorf("Unable to get the %v node, err: %v", experimentsDetails.TargetNode, err)
+	}
+
+	cloudProvider := experimentsDetails.CloudProvider
+	if cloudProvider == "" {
+		cloudProvider = strings.Split(node.Spec.ProviderID, ":")[0]
+	}
+
+	provider, instanceID, err := newRebootProvider(experimentsDetails, cloudProvider, node.Spec.ProviderID)
+	if err != nil {
+		return err
+	}
+	if err := provider.CheckCredentials(); err != nil {
+		return errors.Errorf("%v credentials check failed, err: %v", cloudProvider, err)
+	}
+
+	log.InfoWithValues("[Chaos]: Rebooting the node through the cloud provider", logrus.Fields{
+		"NodeName":      experimentsDetails.TargetNode,
+		"CloudProvider": cloudProvider,
+		"InstanceId":    instanceID,
+	})
+	return provider.RebootInstance(instanceID)
+}
+
+// newRebootProvider creates the cloud provider and derive the instance id of the node from its provider id
+func newRebootProvider(experimentsDetails *experimentTypes.ExperimentDetails, cloudProvider, providerID string) (rebootProvider, string, error) {
+
+	switch cloudProvider {
+	case "aws":
+		instanceID, err := awslib.GetInstanceIDFromProviderID(providerID)
+		if err != nil {
+			return nil, "", err
+		}
+		provider, err := awslib.NewProvider(awslib.Config{
+			Region:        experimentsDetails.Region,
+			AssumeRoleARN: experimentsDetails.AssumeRoleARN,
+			ExternalID:    experimentsDetails.ExternalID,
+			EndpointURL:   experimentsDetails.AWSEndpointURL,
+		})
+		if err != nil {
+			return nil, "", errors.Errorf("failed to create the aws provider, err: %v", err)
+		}
+		return provider, instanceID, nil
+	case "gce", "gcp":
+		instanceID, err := gcp.GetInstanceIDFromProviderID(providerID)
+		if err != nil {
+			return nil, "", err
+		}
+		provider, err := gcp.NewProvider(gcp.Config{
+			ProjectID:       experimentsDetails.GCPProjectID,
+			CredentialsFile: experimentsDetails.GCPCredentialsFile,
+			EndpointURL:     experimentsDetails.GCPEndpointURL,
+		})
+		if err != nil {
+			return nil, "", errors.Errorf("failed to create the gcp provider, err: %v", err)
+		}
+		return provider, instanceID, nil
+	case "azure":
+		instanceID, err := azure.GetInstanceIDFromProviderID(providerID)
+		if err != nil {
+			return nil, "", err
+		}
+		provider, err := azure.NewProvider(azure.Config{
+			SubscriptionID:     experimentsDetails.AzureSubscriptionID,
+			TenantID:           experimentsDetails.AzureTenantID,
+			ClientID:           experimentsDetails.AzureClientID,
+			ClientSecret:       experimentsDetails.AzureClientSecret,
+			FederatedTokenFile: experimentsDetails.AzureFederatedTokenFile,
+			AuthFile:           experimentsDetails.AzureAuthFile,
+			EndpointURL:        experimentsDetails.AzureEndpointURL,
+		})
+		if err != nil {
+			return nil, "", errors.Errorf("failed to create the azure provider, err: %v", err)
+		}
+		return provider, instanceID, nil
+	default:
+		return nil, "", errors.Errorf("cloud provider '%v' is not supported, supported providers are aws, gcp and azure", cloudProvider)
+	}
+}
+
+// GetBootID returns the boot id of the node, it changes on every reboot of the node
+func GetBootID(nodeName string, clients clients.ClientSets) (string, error) {
+	node, err := clients.KubeClient.CoreV1().Nodes().Get(nodeName, v1.GetOptions{})
+	if err != nil {
+		return "", errors.Errorf("Unable to get the %v node, err: %v", nodeName, err)
+	}
+	return node.Status.NodeInfo.BootID, nil
+}
+
+// VerifyBootIDChanged verifies that the node is rebooted, by comparing its boot id with the pre chaos boot id
+// the boot id is updated by the kubelet after the restart, so it is retried till the timeout
+func VerifyBootIDChanged(nodeName, preChaosBootID string, timeout, delay int, clients clients.ClientSets) error {
+
+	log.Infof("[Status]: Verify that the %v node is rebooted", nodeName)
+	return retry.
+		Times(uint(timeout / delay)).
+		Wait(time.Duration(delay) * time.Second).
+		Try(func(attempt uint) error {
+			bootID, err := GetBootID(nodeName, clients)
+			if err != nil {
+				return err
+			}
+			if bootID == preChaosBootID {
+				return errors.Errorf("the boot id of %v node is not changed, the node is not rebooted", nodeName)
+			}
+			return nil
+		})
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	experimentEnv "github.com/litmuschaos/litmus-go/pkg/generic/node-service-kill/environment"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/node-service-kill/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/result"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/pkg/errors"
+	clientTypes "k8s.io/apimachinery/pkg/types"
+)
+
+// preChaosDelay is the wait before stopping the service
+// it lets the runner observe the helper pod in running state before the node loses the kubelet
+const preChaosDelay = 10
+
+// minChaosInterval is the minimum wait between the kills of the kill method
+// it keeps the helper from spawning the host commands in a tight loop, ex: with a zero chaos interval
+const minChaosInterval = 1
+
+// serviceNameRegex matches the valid systemd unit names
+var serviceNameRegex = regexp.MustCompile(`^[a-zA-Z0-9@._-]+$`)
+
+// unitNameRegex matches the characters of the service name which are replaced in the name of the restart unit
+var unitNameRegex = regexp.MustCompile(`[^a-zA-Z0-9-]`)
+
+var inject, abort chan os.Signal
+
+func main() {
+
+	experimentsDetails := experimentTypes.ExperimentDetails{}
+	clients := clients.ClientSets{}
+	eventsDetails := types.EventDetails{}
+	chaosDetails := types.ChaosDetails{}
+	resultDetails := types.ResultDetails{}
+
+	// inject channel is used to transmit signal notifications.
+	inject = make(chan os.Signal, 1)
+	// Catch and relay certain signal(s) to inject channel.
+	signal.Notify(inject, os.Interrupt, syscall.SIGTERM)
+
+	// abort channel is used to transmit signal notifications.
+	abort = make(chan os.Signal, 1)
+	// Catch and relay certain signal(s) to abort channel.
+	signal.Notify(abort, os.Interrupt, syscall.SIGTERM)
+
+	//Getting kubeConfig and Generate ClientSets
+	if err := clients.GenerateClientSetFromKubeConfig(); err != nil {
+		log.Fatalf("Unable to Get the kubeconfig, err: %v", err)
+	}
+
+	//Fetching all the ENV passed in the helper pod
+	log.Info("[PreReq]: Getting the ENV variables")
+	GetENV(&experimentsDetails)
+
+	// Intialise the chaos attributes
+	experimentEnv.InitialiseChaosVariables(&chaosDetails, &experimentsDetails)
+
+	// Intialise Chaos Result Parameters
+	types.SetResultAttributes(&resultDetails, chaosDetails)
+
+	// Set the chaos result uid
+	result.SetResultUID(&resultDetails, clients, &chaosDetails)
+
+	if err := KillService(&experimentsDetails, clients, &eventsDetails, &chaosDetails); err != nil {
+		helper.WriteTerminationMessage(err)
+		log.Fatalf("helper pod failed, err: %v", err)
+	}
+}
+
+//KillService stops the target service of the node for the chaos duration and starts it again
+func KillService(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	if !serviceNameRegex.MatchString(experimentsDetails.ServiceName) {
+		return errors.Errorf("%v is not a valid service name", experimentsDetails.ServiceName)
+	}
+
+	// record the event inside chaosengine
+	if experimentsDetails.EngineName != "" {
+		msg := "Killing the " + experimentsDetails.ServiceName + " service of the node"
+		types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+		events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+	}
+
+	// watching for the abort signal and revert the chaos
+	go abortWatcher(experimentsDetails.ServiceName)
+
+	common.WaitForDuration(preChaosDelay)
+
+	select {
+	case <-inject:
+		// stopping the chaos execution, if abort signal recieved
+		helper.ExitAborted()
+	default:
+		// the restart is scheduled on the host before the chaos, it brings the service back even if the helper
+		// is killed along with the service, ex: when the service is the container runtime hosting the helper
+		restartUnit, err := scheduleRestart(experimentsDetails.ServiceName, experimentsDetails.ChaosDuration)
+		if err != nil {
+			return err
+		}
+		defer cancelRestart(restartUnit)
+
+		switch experimentsDetails.KillMethod {
+		case "systemctl":
+			log.Infof("[Chaos]: Stopping the %v service", experimentsDetails.ServiceName)
+			if _, err := runOnHost("systemctl", "stop", experimentsDetails.ServiceName); err != nil {
+				return errors.Errorf("unable to stop the %v service, err: %v", experimentsDetails.ServiceName, err)
+			}
+			helper.RecordInjected("stopped the " + experimentsDetails.ServiceName + " service")
+			log.Infof("[Chaos]: Waiting for %vs", experimentsDetails.ChaosDuration)
+			common.WaitForDuration(experimentsDetails.ChaosDuration)
+		case "kill":
+			if err := killMainPID(experimentsDetails); err != nil {
+				return err
+			}
+		default:
+			return errors.Errorf("%v kill method not supported, supported methods are systemctl and kill", experimentsDetails.KillMethod)
+		}
+	}
+
+	log.Info("[Chaos]: Stopping the experiment")
+	return startService(experimentsDetails.ServiceName)
+}
+
+// killMainPID sends the SIGKILL to the main pid of the service at every chaos interval till the chaos duration
+// the service manager may restart the service in between, which gets killed again in the next iteration
+func killMainPID(experimentsDetails *experimentTypes.ExperimentDetails) error {
+
+	ChaosStartTimeStamp := time.Now()
+	for int(time.Since(ChaosStartTimeStamp).Seconds()) < experimentsDetails.ChaosDuration {
+		pid, err := getMainPID(experimentsDetails.ServiceName)
+		if err != nil {
+			return err
+		}
+		if pid != 0 {
+			log.Infof("[Chaos]: Killing the %v process of the %v service", pid, experimentsDetails.ServiceName)
+			if _, err := runOnHost("kill", "-9", strconv.Itoa(pid)); err != nil {
+				return errors.Errorf("unable to kill the %v service, err: %v", experimentsDetails.ServiceName, err)
+			}
+			helper.RecordInjected("killed the " + strconv.Itoa(pid) + " process of the " + experimentsDetails.ServiceName + " service")
+		}
+		common.WaitForDuration(experimentsDetails.ChaosInterval)
+	}
+	return nil
+}
+
+// getMainPID returns the main pid of the service, it is zero if the service is not running
+func getMainPID(serviceName string) (int, error) {
+
+	out, err := runOnHost("systemctl", "show", "--property", "MainPID", serviceName)
+	if err != nil {
+		return 0, errors.Errorf("unable to get the main pid of the %v service, err: %v", serviceName, err)
+	}
+	pid, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(out), "MainPID="))
+	if err != nil {
+		return 0, errors.Errorf("unable to parse the main pid of the %v service from %v, err: %v", serviceName, out, err)
+	}
+	return pid, nil
+}
+
+// scheduleRestart schedules the start of the service on the host after the given seconds through a transient systemd timer
+// it returns the name of the transient unit, which is used to cancel the restart once the helper starts the service itself
+func scheduleRestart(serviceName string, after int) (string, error) {
+
+	unit := "litmus-restart-" + unitNameRegex.ReplaceAllString(serviceName, "-") + "-" + strconv.FormatInt(time.Now().Unix(), 10)
+	log.Infof("[Chaos]: Scheduling the start of the %v service after %vs through the %v unit", serviceName, after, unit)
+	if _, err := runOnHost("systemd-run", "--unit", unit, "--on-active", strconv.Itoa(after)+"s", "systemctl", "start", serviceName); err != nil {
+		return "", errors.Errorf("unable to schedule the start of the %v service, err: %v", serviceName, err)
+	}
+	return unit, nil
+}
+
+// cancelRestart stops the timer of the scheduled restart, the timer is already gone if it has fired
+func cancelRestart(unit string) {
+	if _, err := runOnHost("systemctl", "stop", unit+".timer"); err != nil {
+		log.Warnf("Unable to cancel the scheduled restart %v, err: %v", unit, err)
+	}
+}
+
+// startService starts the service and verifies that it is active
+func startService(serviceName string) error {
+
+	log.Infof("[Recover]: Starting the %v service", serviceName)
+	if _, err := runOnHost("systemctl", "start", serviceName); err != nil {
+		return errors.Errorf("unable to start the %v service, err: %v", serviceName, err)
+	}
+	if _, err := runOnHost("systemctl", "is-active", "--quiet", serviceName); err != nil {
+		return errors.Errorf("the %v service is not active after the start, err: %v", serviceName, err)
+	}
+	helper.RecordReverted("started the " + serviceName + " service")
+	return nil
+}
+
+// runOnHost runs the command inside the namespaces of the init process of the node
+// the helper pod runs with the host pid namespace, so pid 1 refers to the init process of the node
+func runOnHost(command ...string) (string, error) {
+
+	args := append([]string{"nsenter", "-t", "1", "-m", "-u", "-i", "-n", "-p", "--"}, command...)
+	cmd := exec.Command("sudo", args...)
+	out, err := cmd.CombinedOutput()
+	log.Info(cmd.String())
+	if err != nil {
+		log.Error(string(out))
+		return "", err
+	}
+	return string(out), nil
+}
+
+//GetENV fetches all the env variables from the runner pod
+func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
+	experimentDetails.ExperimentName = Getenv("EXPERIMENT_NAME", "")
+	experimentDetails.ServiceName = Getenv("SERVICE_NAME", "kubelet")
+	experimentDetails.KillMethod = Getenv("KILL_METHOD", "systemctl")
+	experimentDetails.ChaosDuration, _ = strconv.Atoi(Getenv("TOTAL_CHAOS_DURATION", "90"))
+	experimentDetails.ChaosInterval, _ = strconv.Atoi(Getenv("CHAOS_INTERVAL", "5"))
+	if experimentDetails.ChaosInterval < minChaosInterval {
+		log.Warnf("The %v chaos interval is invalid, using the minimum chaos interval of %vs", os.Getenv("CHAOS_INTERVAL"), minChaosInterval)
+		experimentDetails.ChaosInterval = minChaosInterval
+	}
+	experimentDetails.ChaosNamespace = Getenv("CHAOS_NAMESPACE", "litmus")
+	experimentDetails.EngineName = Getenv("CHAOS_ENGINE", "")
+	experimentDetails.ChaosUID = clientTypes.UID(Getenv("CHAOS_UID", ""))
+	experimentDetails.ChaosPodName = Getenv("POD_NAME", "")
+}
+
+// Getenv fetch the env and set the default value, if any
+func Getenv(key string, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		value = defaultValue
+	}
+	return value
+}
+
+// abortWatcher continuosly watch for the abort signals
+func abortWatcher(serviceName string) {
+
+	for {
+		select {
+		case <-abort:
+			log.Info("[Chaos]: Killing process started because of terminated signal received")
+			log.Info("Chaos Revert Started")
+			// retry thrice for the chaos revert
+			retry := 3
+			for retry > 0 {
+				if err := startService(serviceName); err != nil {
+					log.Errorf("unable to start the %v service, err: %v", serviceName, err)
+				}
+				retry--
+				time.Sleep(1 * time.Second)
+			}
+			log.Info("Chaos Revert Completed")
+			helper.ExitAborted()
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package lib
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/node-service-kill/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/probe"
+	"github.com/litmuschaos/litmus-go/pkg/status"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var err error
+
+// PrepareNodeServiceKill contains prepration steps before chaos injection
+func PrepareNodeServiceKill(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	//Select the target nodes for the chaos
+	targetNodeList, err := common.GetTargetNodes(common.GetNodeSelector(experimentsDetails.NodeTargetDetails, experimentsDetails.AppNS, experimentsDetails.AppLabel), clients)
+	if err != nil {
+		return err
+	}
+	log.InfoWithValues("[Info]: Details of Nodes under chaos injection", logrus.Fields{
+		"No. Of Nodes": len(targetNodeList),
+		"Node Names":   targetNodeList,
+		"Service":      experimentsDetails.ServiceName,
+		"Kill Method":  experimentsDetails.KillMethod,
+	})
+
+	//Waiting for the ramp time before chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time before injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+
+	// Getting the serviceAccountName, need permission inside helper pod to create the events
+	if experimentsDetails.ChaosServiceAccount == "" {
+		experimentsDetails.ChaosServiceAccount, err = GetServiceAccount(experimentsDetails, clients)
+		if err != nil {
+			return errors.Errorf("Unable to get the serviceAccountName, err: %v", err)
+		}
+	}
+
+	if experimentsDetails.EngineName != "" {
+		// Get Chaos Pod Annotation
+		experimentsDetails.Annotations, err = common.GetChaosPodAnnotation(experimentsDetails.ChaosPodName, experimentsDetails.ChaosNamespace, clients)
+		if err != nil {
+			return errors.Errorf("unable to get annotations, err: %v", err)
+		}
+		// Get Resource Requirements
+		experimentsDetails.Resources, err = common.GetChaosPodResourceRequirements(experimentsDetails.ChaosPodName, experimentsDetails.ExperimentName, experimentsDetails.ChaosNamespace, clients)
+		if err != nil {
+			return errors.Errorf("Unable to get resource requirements, err: %v", err)
+		}
+		// Get ImagePullSecrets
+		experimentsDetails.ImagePullSecrets, err = common.GetImagePullSecrets(experimentsDetails.ChaosPodName, experimentsDetails.ChaosNamespace, clients)
+		if err != nil {
+			return errors.Errorf("Unable to get imagePullSecrets, err: %v", err)
+		}
+	}
+
+	if experimentsDetails.Sequence == "serial" {
+		if err = InjectChaosInSerialMode(experimentsDetails, targetNodeList, clients, resultDetails, eventsDetails, chaosDetails); err != nil {
+			return err
+		}
+	} else {
+		if err = InjectChaosInParallelMode(experimentsDetails, targetNodeList, clients, resultDetails, eventsDetails, chaosDetails); err != nil {
+			return err
+		}
+	}
+
+	//Waiting for the ramp time after chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time after injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+	return nil
+}
+
+// InjectChaosInSerialMode kill the service of all the target nodes serially (one by one)
+func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetails, targetNodeList []string, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
+
+	// run the probes during chaos
+	if len(resultDetails.ProbeDetails) != 0 {
+		if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+			return err
+		}
+	}
+
+	for _, appNode := range targetNodeList {
+
+		if experimentsDetails.EngineName != "" {
+			msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on " + appNode + " node"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		ChaosStartTimeStamp := time.Now()
+
+		// Creating the helper pod to kill the service
+		if err = CreateHelperPod(experimentsDetails, manager, appNode); err != nil {
+			return err
+		}
+
+		//checking the status of the helper pods, wait till the pod comes to running state else fail the experiment
+		if err := manager.WaitForRunning(); err != nil {
+			manager.Cleanup()
+			return err
+		}
+		manager.StreamLogs()
+
+		notReadyAfter, err := verifyNodeNotReady(experimentsDetails, appNode, ChaosStartTimeStamp, clients)
+		if err != nil {
+			manager.Cleanup()
+			return err
+		}
+
+		// Wait till the completion of helper pod
+		log.Infof("[Wait]: Waiting for %vs till the completion of the helper pod", experimentsDetails.ChaosDuration+60)
+		if err := manager.WaitForCompletion(experimentsDetails.ChaosDuration + 60); err != nil {
+			manager.Cleanup()
+			return err
+		}
+
+		// Checking the status of target nodes
+		log.Info("[Status]: Getting the status of target nodes")
+		if err = status.CheckNodeStatus(appNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
+			manager.Cleanup()
+			return errors.Errorf("%v node is not recovered after the chaos, you may need to manually recover the node, err: %v", appNode, err)
+		}
+		recordNodeTimings(experimentsDetails, appNode, notReadyAfter, time.Since(ChaosStartTimeStamp), clients, eventsDetails, chaosDetails)
+
+		//Deleting the helper pod
+		if err := manager.Delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InjectChaosInParallelMode kill the service of all the target nodes in parallel mode (all at once)
+func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDetails, targetNodeList []string, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
+
+	// run the probes during chaos
+	if len(resultDetails.ProbeDetails) != 0 {
+		if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+			return err
+		}
+	}
+
+	ChaosStartTimeStamp := time.Now()
+	for _, appNode := range targetNodeList {
+
+		if experimentsDetails.EngineName != "" {
+			msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on " + appNode + " node"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		// Creating the helper pod to kill the service
+		if err = CreateHelperPod(experimentsDetails, manager, appNode); err != nil {
+			return err
+		}
+	}
+
+	//checking the status of the helper pods, wait till the pod comes to running state else fail the experiment
+	if err := manager.WaitForRunning(); err != nil {
+		manager.Cleanup()
+		return err
+	}
+	manager.StreamLogs()
+
+	notReadyAfter := map[string]time.Duration{}
+	for _, appNode := range targetNodeList {
+		notReadyAfter[appNode], err = verifyNodeNotReady(experimentsDetails, appNode, ChaosStartTimeStamp, clients)
+		if err != nil {
+			manager.Cleanup()
+			return err
+		}
+	}
+
+	// Wait till the completion of helper pod
+	log.Infof("[Wait]: Waiting for %vs till the completion of the helper pod", experimentsDetails.ChaosDuration+60)
+	if err := manager.WaitForCompletion(experimentsDetails.ChaosDuration + 60); err != nil {
+		manager.Cleanup()
+		return err
+	}
+
+	for _, appNode := range targetNodeList {
+		// Checking the status of target nodes
+		log.Info("[Status]: Getting the status of target nodes")
+		if err = status.CheckNodeStatus(appNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
+			manager.Cleanup()
+			return errors.Errorf("%v node is not recovered after the chaos, you may need to manually recover the node, err: %v", appNode, err)
+		}
+		recordNodeTimings(experimentsDetails, appNode, notReadyAfter[appNode], time.Since(ChaosStartTimeStamp), clients, eventsDetails, chaosDetails)
+	}
+
+	//Deleting the helper pod
+	return manager.Delete()
+}
+
+// verifyNodeNotReady waits for the node to be in NotReady state and returns the time taken since the chaos injection
+func verifyNodeNotReady(experimentsDetails *experimentTypes.ExperimentDetails, appNode string, chaosStartTimeStamp time.Time, clients clients.ClientSets) (time.Duration, error) {
+
+	if !experimentsDetails.VerifyNodeNotReady {
+		return 0, nil
+	}
+
+	// Checking for the node to be in not-ready state
+	log.Infof("[Status]: Check for the %v node to be in NotReady state", appNode)
+	if err := status.CheckNodeNotReadyState(appNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients); err != nil {
+		return 0, errors.Errorf("%v node is not in NotReady state, err: %v", appNode, err)
+	}
+	return time.Since(chaosStartTimeStamp), nil
+}
+
+// recordNodeTimings logs the time taken by the node to become NotReady and to recover and records it in the chaosengine events
+func recordNodeTimings(experimentsDetails *experimentTypes.ExperimentDetails, appNode string, notReadyAfter, recoveredAfter time.Duration, clients clients.ClientSets, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) {
+
+	log.InfoWithValues("[Info]: The node timings are as follows", logrus.Fields{
+		"NodeName":       appNode,
+		"Service":        experimentsDetails.ServiceName,
+		"NotReadyAfter":  notReadyAfter.Round(time.Second).String(),
+		"RecoveredAfter": recoveredAfter.Round(time.Second).String(),
+	})
+
+	if experimentsDetails.EngineName != "" {
+		msg := fmt.Sprintf("%v node recovered %v after the %v service kill", appNode, recoveredAfter.Round(time.Second), experimentsDetails.ServiceName)
+		if experimentsDetails.VerifyNodeNotReady {
+			msg = fmt.Sprintf("%v node became NotReady %v and recovered %v after the %v service kill", appNode, notReadyAfter.Round(time.Second), recoveredAfter.Round(time.Second), experimentsDetails.ServiceName)
+		}
+		types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+		events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+	}
+}
+
+// GetServiceAccount find the serviceAccountName for the helper pod
+func GetServiceAccount(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (string, error) {
+	pod, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaosNamespace).Get(experimentsDetails.ChaosPodName, v1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	return pod.Spec.ServiceAccountName, nil
+}
+
+// GetHelperManager returns the manager of the helper pods
+func GetHelperManager(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, chaosDetails *types.ChaosDetails) *helper.Manager {
+	return helper.NewManager(helper.Meta{
+		ExperimentName:  experimentsDetails.ExperimentName,
+		Namespace:       experimentsDetails.ChaosNamespace,
+		ChaosUID:        experimentsDetails.ChaosUID,
+		Image:           experimentsDetails.LIBImage,
+		ImagePullPolicy: experimentsDetails.LIBImagePullPolicy,
+	}, clients, chaosDetails)
+}
+
+// CreateHelperPod derive the attributes for helper pod and create the helper pod
+func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, manager *helper.Manager, appNode string) error {
+
+	return manager.Create(manager.NewPod(
+		helper.WithNodeName(appNode),
+		helper.WithHostPID(),
+		helper.WithTerminationGracePeriod(experimentsDetails.TerminationGracePeriodSeconds),
+		helper.WithServiceAccount(experimentsDetails.ChaosServiceAccount),
+		helper.WithAnnotations(experimentsDetails.Annotations),
+		helper.WithImagePullSecrets(experimentsDetails.ImagePullSecrets),
+		helper.WithResources(experimentsDetails.Resources),
+		helper.WithCommand([]string{"/bin/bash"}, "-c", "./helper/node-service-kill"),
+		helper.WithEnv(GetPodEnv(experimentsDetails, appNode)),
+		helper.WithPodNameEnv(),
+		// the helper enters all the namespaces of the node to kill its services, which is allowed only to the privileged containers
+		helper.WithPrivileged(),
+	))
+}
+
+// GetPodEnv derive all the env required for the helper pod
+func GetPodEnv(experimentsDetails *experimentTypes.ExperimentDetails, appNode string) map[string]string {
+
+	return map[string]string{
+		"SERVICE_NAME":         experimentsDetails.ServiceName,
+		"KILL_METHOD":          experimentsDetails.KillMethod,
+		"TOTAL_CHAOS_DURATION": strconv.Itoa(experimentsDetails.ChaosDuration),
+		"CHAOS_INTERVAL":       strconv.Itoa(experimentsDetails.ChaosInterval),
+		"CHAOS_NAMESPACE":      experimentsDetails.ChaosNamespace,
+		"CHAOS_ENGINE":         experimentsDetails.EngineName,
+		"CHAOS_UID":            string(experimentsDetails.ChaosUID),
+		"EXPERIMENT_NAME":      experimentsDetails.ExperimentName,
+		"TARGET_NODE":          appNode,
+	}
+}
+
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"math"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/result"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	clientTypes "k8s.io/apimachinery/pkg/types"
+)
+
+// deadband is the minimum change in percentage of the capacity, for which the stressor is restarted
+// it avoids restarting the stressor for the noise of the usage metrics
+const deadband = 2
+
+// stressDetails contains the attributes of the closed loop stress
+type stressDetails struct {
+	ExperimentName    string
+	EngineName        string
+	ChaosDuration     int
+	ChaosNamespace    string
+	ChaosPodName      string
+	ChaosUID          clientTypes.UID
+	InstanceID        string
+	NodeName          string
+	StressType        string
+	TargetUtilisation int
+	ControlInterval   int
+	MetricsSource     string
+}
+
+// stressor is the running stress-ng process along with its intensity
+// the intensity is the cpu load percentage of the workers for the cpu stress and the bytes for the memory stress
+type stressor struct {
+	mu        sync.Mutex
+	cmd       *exec.Cmd
+	workers   int
+	intensity int64
+}
+
+var inject, abort chan os.Signal
+
+func main() {
+
+	experimentsDetails := stressDetails{}
+	clients := clients.ClientSets{}
+	eventsDetails := types.EventDetails{}
+	chaosDetails := types.ChaosDetails{}
+	resultDetails := types.ResultDetails{}
+
+	// inject channel is used to transmit signal notifications.
+	inject = make(chan os.Signal, 1)
+	// Catch and relay certain signal(s) to inject channel.
+	signal.Notify(inject, os.Interrupt, syscall.SIGTERM)
+
+	// abort channel is used to transmit signal notifications.
+	abort = make(chan os.Signal, 1)
+	// Catch and relay certain signal(s) to abort channel.
+	signal.Notify(abort, os.Interrupt, syscall.SIGTERM)
+
+	//Getting kubeConfig and Generate ClientSets
+	if err := clients.GenerateClientSetFromKubeConfig(); err != nil {
+		log.Fatalf("Unable to Get the kubeconfig, err: %v", err)
+	}
+
+	//Fetching all the ENV passed in the helper pod
+	log.Info("[PreReq]: Getting the ENV variables")
+	GetENV(&experimentsDetails)
+
+	// Intialise the chaos attributes
+	chaosDetails.ChaosNamespace = experimentsDetails.ChaosNamespace
+	chaosDetails.ChaosPodName = experimentsDetails.ChaosPodName
+	chaosDetails.ChaosUID = experimentsDetails.ChaosUID
+	chaosDetails.EngineName = experimentsDetails.EngineName
+	chaosDetails.ExperimentName = experimentsDetails.ExperimentName
+	chaosDetails.InstanceID = experimentsDetails.InstanceID
+
+	// Intialise Chaos Result Parameters
+	types.SetResultAttributes(&resultDetails, chaosDetails)
+
+	// Set the chaos result uid
+	result.SetResultUID(&resultDetails, clients, &chaosDetails)
+
+	if err := StressNode(&experimentsDetails, clients, &eventsDetails, &chaosDetails); err != nil {
+		helper.WriteTerminationMessage(err)
+		log.Fatalf("helper pod failed, err: %v", err)
+	}
+}
+
+//StressNode runs the stressor on the node and adjusts its intensity at every control interval
+// so that the utilisation of the node stays around the target utilisation till the chaos duration
+func StressNode(experimentsDetails *stressDetails, clients clients.ClientSets, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	if experimentsDetails.StressType != "cpu" && experimentsDetails.StressType != "memory" {
+		return errors.Errorf("%v stress type not supported, supported types are cpu and memory", experimentsDetails.StressType)
+	}
+
+	// the baseline usage of the node, before the stressor is started
+	usage, err := common.GetNodeUsage(experimentsDetails.NodeName, experimentsDetails.MetricsSource, clients)
+	if err != nil {
+		return err
+	}
+	stress := &stressor{
+		workers: int(math.Ceil(float64(usage.CPUCapacityMillicores) / 1000)),
+	}
+	stress.intensity = nextIntensity(experimentsDetails, usage, 0)
+
+	log.InfoWithValues("[Info]: The baseline utilisation of the node", logrus.Fields{
+		"NodeName":          experimentsDetails.NodeName,
+		"StressType":        experimentsDetails.StressType,
+		"Utilisation":       usage.Percentage(experimentsDetails.StressType),
+		"TargetUtilisation": experimentsDetails.TargetUtilisation,
+	})
+
+	// record the event inside chaosengine
+	if experimentsDetails.EngineName != "" {
+		msg := "Stressing the " + experimentsDetails.StressType + " of the " + experimentsDetails.NodeName + " node to " + strconv.Itoa(experimentsDetails.TargetUtilisation) + "% utilisation"
+		types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+		events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+	}
+
+	// watching for the abort signal and revert the chaos
+	go abortWatcher(stress)
+
+	select {
+	case <-inject:
+		// stopping the chaos execution, if abort signal recieved
+		helper.ExitAborted()
+	default:
+	}
+
+	deadline := time.Now().Add(time.Duration(experimentsDetails.ChaosDuration) * time.Second)
+	if err := stress.start(experimentsDetails, int(time.Until(deadline).Seconds())); err != nil {
+		return err
+	}
+	helper.RecordInjected("stressing the " + experimentsDetails.StressType + " of the " + experimentsDetails.NodeName + " node to " + strconv.Itoa(experimentsDetails.TargetUtilisation) + "% utilisation")
+
+	for {
+		remaining := int(time.Until(deadline).Seconds())
+		if remaining <= 0 {
+			break
+		}
+		common.WaitForDuration(int(math.Min(float64(experimentsDetails.ControlInterval), float64(remaining))))
+		if time.Now().After(deadline) {
+			break
+		}
+
+		usage, err := common.GetNodeUsage(experimentsDetails.NodeName, experimentsDetails.MetricsSource, clients)
+		if err != nil {
+			log.Warnf("Unable to get the usage of %v node, retaining the current intensity, err: %v", experimentsDetails.NodeName, err)
+			continue
+		}
+		intensity := nextIntensity(experimentsDetails, usage, stress.intensity)
+		log.InfoWithValues("[Chaos]: The current utilisation of the node", logrus.Fields{
+			"Utilisation":      usage.Percentage(experimentsDetails.StressType),
+			"CurrentIntensity": stress.intensity,
+			"NextIntensity":    intensity,
+		})
+		if !exceedsDeadband(experimentsDetails, usage, stress.intensity, intensity) {
+			continue
+		}
+
+		stress.stop()
+		stress.intensity = intensity
+		if err := stress.start(experimentsDetails, int(time.Until(deadline).Seconds())); err != nil {
+			return err
+		}
+	}
+
+	log.Info("[Chaos]: Stopping the experiment")
+	stress.stop()
+	helper.RecordReverted("stopped the stressor")
+	return nil
+}
+
+// nextIntensity derive the intensity of the stressor, which moves the utilisation of the node to the target utilisation
+// the utilisation of the node contains the load of the current stressor, so the error is added to the current intensity
+func nextIntensity(experimentsDetails *stressDetails, usage common.NodeUsage, current int64) int64 {
+
+	deviation := float64(experimentsDetails.TargetUtilisation) - usage.Percentage(experimentsDetails.StressType)
+	switch experimentsDetails.StressType {
+	case "memory":
+		next := current + int64(deviation*float64(usage.MemoryCapacityBytes)/100)
+		return int64(math.Max(0, math.Min(float64(next), float64(usage.MemoryCapacityBytes))))
+	default:
+		// the number of workers is same as the number of cores, so the load percentage of the workers maps to the cpu utilisation
+		next := current + int64(deviation)
+		return int64(math.Max(0, math.Min(float64(next), 100)))
+	}
+}
+
+// exceedsDeadband checks whether the change of the intensity is large enough to restart the stressor
+func exceedsDeadband(experimentsDetails *stressDetails, usage common.NodeUsage, current, next int64) bool {
+	change := math.Abs(float64(next - current))
+	if experimentsDetails.StressType == "memory" {
+		return usage.MemoryCapacityBytes != 0 && change*100/float64(usage.MemoryCapacityBytes) >= deadband
+	}
+	return change >= deadband
+}
+
+// start starts the stress-ng process with the current intensity for the given duration
+func (s *stressor) start(experimentsDetails *stressDetails, duration int) error {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.intensity == 0 || duration <= 0 {
+		log.Info("[Chaos]: The node is already at the target utilisation, skipping the stressor")
+		return nil
+	}
+
+	var args []string
+	switch experimentsDetails.StressType {
+	case "memory":
+		args = []string{"--vm", "1", "--vm-bytes", strconv.FormatInt(s.intensity, 10), "--vm-keep"}
+	default:
+		args = []string{"--cpu", strconv.Itoa(s.workers), "--cpu-load", strconv.FormatInt(s.intensity, 10)}
+	}
+	args = append(args, "--timeout", strconv.Itoa(duration))
+
+	s.cmd = exec.Command("stress-ng", args...)
+	// the stressor runs in its own process group, so that all the workers are stopped together
+	s.cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	s.cmd.Stdout = os.Stdout
+	s.cmd.Stderr = os.Stderr
+	log.Info(s.cmd.String())
+	if err := s.cmd.Start(); err != nil {
+		return errors.Errorf("unable to start the stressor, err: %v", err)
+	}
+	return nil
+}
+
+// stop kills the process group of the running stressor, if any
+func (s *stressor) stop() {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cmd == nil || s.cmd.Process == nil {
+		return
+	}
+	if err := syscall.Kill(-s.cmd.Process.Pid, syscall.SIGKILL); err != nil {
+		log.Warnf("unable to kill the stressor, err: %v", err)
+	}
+	s.cmd.Wait()
+	s.cmd = nil
+}
+
+//GetENV fetches all the env variables from the runner pod
+func GetENV(experimentDetails *stressDetails) {
+	experimentDetails.ExperimentName = Getenv("EXPERIMENT_NAME", "")
+	experimentDetails.ChaosDuration, _ = strconv.Atoi(Getenv("TOTAL_CHAOS_DURATION", "30"))
+	experimentDetails.ChaosNamespace = Getenv("CHAOS_NAMESPACE", "litmus")
+	experimentDetails.EngineName = Getenv("CHAOS_ENGINE", "")
+	experimentDetails.ChaosUID = clientTypes.UID(Getenv("CHAOS_UID", ""))
+	experimentDetails.ChaosPodName = Getenv("POD_NAME", "")
+	experimentDetails.InstanceID = Getenv("INSTANCE_ID", "")
+	experimentDetails.NodeName = Getenv("NODE_NAME", "")
+	experimentDetails.StressType = Getenv("STRESS_TYPE", "cpu")
+	experimentDetails.TargetUtilisation, _ = strconv.Atoi(Getenv("TARGET_UTILISATION", "90"))
+	experimentDetails.ControlInterval = common.ParseControlInterval(os.Getenv("CONTROL_INTERVAL"))
+	experimentDetails.MetricsSource = Getenv("METRICS_SOURCE", "kubelet")
+}
+
+// Getenv fetch the env and set the default value, if any
+func Getenv(key string, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		value = defaultValue
+	}
+	return value
+}
+
+// abortWatcher continuosly watch for the abort signals
+func abortWatcher(stress *stressor) {
+
+	for {
+		select {
+		case <-abort:
+			log.Info("[Chaos]: Killing process started because of terminated signal received")
+			log.Info("Chaos Revert Started")
+			stress.stop()
+			helper.RecordReverted("stopped the stressor")
+			log.Info("Chaos Revert Completed")
+			helper.ExitAborted()
+		}
+	}
+}
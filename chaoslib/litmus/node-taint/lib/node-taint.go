@@ -1,10 +1,10 @@
 package lib
 
 import (
-	"fmt"
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -20,11 +20,19 @@ import (
 	"github.com/pkg/errors"
 	apiv1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	retries "k8s.io/client-go/util/retry"
 )
 
 var err error
 var inject, abort chan os.Signal
 
+// originalTaints contains the taints of the target nodes before the chaos
+// it is guarded by the taintsLock, as the abortWatcher reads it while the nodes are being tainted
+var (
+	originalTaints = map[string][]apiv1.Taint{}
+	taintsLock     sync.Mutex
+)
+
 //PrepareNodeTaint contains the prepration steps before chaos injection
 func PrepareNodeTaint(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
@@ -44,16 +52,15 @@ func PrepareNodeTaint(experimentsDetails *experimentTypes.ExperimentDetails, cli
 		common.WaitForDuration(experimentsDetails.RampTime)
 	}
 
-	if experimentsDetails.TargetNode == "" {
-		//Select node for kubelet-service-kill
-		experimentsDetails.TargetNode, err = common.GetNodeName(experimentsDetails.AppNS, experimentsDetails.AppLabel, clients)
-		if err != nil {
-			return err
-		}
+	//Select the target nodes for the node taint
+	experimentsDetails.TargetNodeList, err = common.GetTargetNodes(common.GetNodeSelector(experimentsDetails.NodeTargetDetails, experimentsDetails.AppNS, experimentsDetails.AppLabel), clients)
+	if err != nil {
+		return err
 	}
+	log.Infof("[Info]: Target nodes list, %v", experimentsDetails.TargetNodeList)
 
 	if experimentsDetails.EngineName != "" {
-		msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on " + experimentsDetails.TargetNode + " node"
+		msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on " + strings.Join(experimentsDetails.TargetNodeList, ",") + " node"
 		types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
 		events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
 	}
@@ -68,9 +75,18 @@ func PrepareNodeTaint(experimentsDetails *experimentTypes.ExperimentDetails, cli
 	// watching for the abort signal and revert the chaos
 	go abortWatcher(experimentsDetails, clients, resultDetails, chaosDetails, eventsDetails)
 
-	// taint the application node
-	err := TaintNode(experimentsDetails, clients)
-	if err != nil {
+	// restore the pre-chaos taints, if the experiment fails after the nodes are (partially) tainted
+	reverted := false
+	defer func() {
+		if !reverted {
+			if revertErr := RemoveTaintFromNode(experimentsDetails, clients); revertErr != nil {
+				log.Errorf("Unable to restore the taints of the nodes, err: %v", revertErr)
+			}
+		}
+	}()
+
+	// taint the target nodes
+	if err := TaintNode(experimentsDetails, clients); err != nil {
 		return err
 	}
 
@@ -96,14 +112,15 @@ func PrepareNodeTaint(experimentsDetails *experimentTypes.ExperimentDetails, cli
 
 	log.Info("[Chaos]: Stopping the experiment")
 
-	// remove taint from the application node
+	// restore the original taints of the target nodes
+	reverted = true
 	if err := RemoveTaintFromNode(experimentsDetails, clients); err != nil {
 		return err
 	}
 
 	// Checking the status of target nodes
 	log.Info("[Status]: Getting the status of target nodes")
-	err = status.CheckNodeStatus(experimentsDetails.TargetNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
+	err = status.CheckNodeStatus(strings.Join(experimentsDetails.TargetNodeList, ","), experimentsDetails.Timeout, experimentsDetails.Delay, clients)
 	if err != nil {
 		log.Warnf("Target nodes are not in the ready state, you may need to manually recover the node, err: %v", err)
 	}
@@ -116,27 +133,14 @@ func PrepareNodeTaint(experimentsDetails *experimentTypes.ExperimentDetails, cli
 	return nil
 }
 
-// TaintNode taint the application node
+// TaintNode add the taints to all the target nodes
+// the taints of the nodes are recorded before the update, so that the revert restores the exact pre-chaos taints
 func TaintNode(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) error {
 
-	// get the taint labels & effect
-	TaintKey, TaintValue, TaintEffect := GetTaintDetails(experimentsDetails)
-
-	log.Infof("Add %v taints to the %v node", TaintKey+"="+TaintValue+":"+TaintEffect, experimentsDetails.TargetNode)
-
-	// get the node details
-	node, err := clients.KubeClient.CoreV1().Nodes().Get(experimentsDetails.TargetNode, v1.GetOptions{})
-	if err != nil || node == nil {
-		return errors.Errorf("failed to get %v node, err: %v", experimentsDetails.TargetNode, err)
-	}
-
-	// check if the taint already exists
-	tainted := false
-	for _, taint := range node.Spec.Taints {
-		if taint.Key == TaintKey {
-			tainted = true
-			break
-		}
+	// get the taints from the TAINTS env
+	chaosTaints, err := GetTaintDetails(experimentsDetails)
+	if err != nil {
+		return err
 	}
 
 	select {
@@ -144,86 +148,133 @@ func TaintNode(experimentsDetails *experimentTypes.ExperimentDetails, clients cl
 		// stopping the chaos execution, if abort signal recieved
 		os.Exit(0)
 	default:
-		if !tainted {
-			node.Spec.Taints = append(node.Spec.Taints, apiv1.Taint{
-				Key:    TaintKey,
-				Value:  TaintValue,
-				Effect: apiv1.TaintEffect(TaintEffect),
-			})
+		for _, nodeName := range experimentsDetails.TargetNodeList {
+			log.Infof("Add %v taints to the %v node", experimentsDetails.Taints, nodeName)
 
-			updatedNodeWithTaint, err := clients.KubeClient.CoreV1().Nodes().Update(node)
-			if err != nil || updatedNodeWithTaint == nil {
-				return fmt.Errorf("failed to update %v node after adding taints, err: %v", experimentsDetails.TargetNode, err)
+			err := updateNodeTaints(nodeName, clients, func(node *apiv1.Node) []apiv1.Taint {
+				taintsLock.Lock()
+				if _, ok := originalTaints[nodeName]; !ok {
+					originalTaints[nodeName] = node.Spec.Taints
+				}
+				taintsLock.Unlock()
+				// the taints with the same key and effect are replaced by the chaos taints
+				return append(filterTaints(node.Spec.Taints, chaosTaints, false), chaosTaints...)
+			})
+			if err != nil {
+				return errors.Errorf("failed to add taints to %v node, err: %v", nodeName, err)
 			}
+			log.Infof("Successfully added taint in %v node", nodeName)
 		}
-
-		log.Infof("Successfully added taint in %v node", experimentsDetails.TargetNode)
 	}
 	return nil
 }
 
-// RemoveTaintFromNode remove the taint from the application node
+// RemoveTaintFromNode restore the original taints of the target nodes
+// only the chaos taints are reverted, the taints added by others during the chaos are preserved
 func RemoveTaintFromNode(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) error {
 
-	// Get the taint key
-	TaintLabel := strings.Split(experimentsDetails.Taints, ":")
-	TaintKey := strings.Split(TaintLabel[0], "=")[0]
-
-	// get the node details
-	node, err := clients.KubeClient.CoreV1().Nodes().Get(experimentsDetails.TargetNode, v1.GetOptions{})
-	if err != nil || node == nil {
-		return errors.Errorf("failed to get %v node, err: %v", experimentsDetails.TargetNode, err)
+	chaosTaints, err := GetTaintDetails(experimentsDetails)
+	if err != nil {
+		return err
 	}
 
-	// check if the taint already exists
-	tainted := false
-	for _, taint := range node.Spec.Taints {
-		if taint.Key == TaintKey {
-			tainted = true
-			break
+	for _, nodeName := range experimentsDetails.TargetNodeList {
+		taintsLock.Lock()
+		snapshot, ok := originalTaints[nodeName]
+		taintsLock.Unlock()
+		if !ok {
+			continue
+		}
+
+		err := updateNodeTaints(nodeName, clients, func(node *apiv1.Node) []apiv1.Taint {
+			// remove the chaos taints and add back the pre-chaos taints having the same key and effect
+			return append(filterTaints(node.Spec.Taints, chaosTaints, false), filterTaints(snapshot, chaosTaints, true)...)
+		})
+		if err != nil {
+			return errors.Errorf("failed to restore the taints of %v node, err: %v", nodeName, err)
 		}
+		log.Infof("Successfully removed taint from the %v node", nodeName)
 	}
+	return nil
+}
+
+// updateNodeTaints update the taints of the node with optimistic concurrency
+// the node is fetched again and the taints are recomputed if the update conflicts with another writer
+func updateNodeTaints(nodeName string, clients clients.ClientSets, desiredTaints func(node *apiv1.Node) []apiv1.Taint) error {
+
+	return retries.RetryOnConflict(retries.DefaultRetry, func() error {
+		node, err := clients.KubeClient.CoreV1().Nodes().Get(nodeName, v1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		node.Spec.Taints = desiredTaints(node)
+		_, err = clients.KubeClient.CoreV1().Nodes().Update(node)
+		return err
+	})
+}
+
+// filterTaints return the taints which match (or do not match) any of the chaos taints by key and effect
+func filterTaints(taints, chaosTaints []apiv1.Taint, match bool) []apiv1.Taint {
 
-	if tainted {
-		var Newtaints []apiv1.Taint
-		// remove all the taints with matching key
-		for _, taint := range node.Spec.Taints {
-			if taint.Key != TaintKey {
-				Newtaints = append(Newtaints, taint)
+	var filtered []apiv1.Taint
+	for _, taint := range taints {
+		matched := false
+		for _, chaosTaint := range chaosTaints {
+			if taint.MatchTaint(&chaosTaint) {
+				matched = true
+				break
 			}
 		}
-		node.Spec.Taints = Newtaints
-		updatedNodeWithTaint, err := clients.KubeClient.CoreV1().Nodes().Update(node)
-		if err != nil || updatedNodeWithTaint == nil {
-			return fmt.Errorf("failed to update %v node after removing taints, err: %v", experimentsDetails.TargetNode, err)
+		if matched == match {
+			filtered = append(filtered, taint)
 		}
 	}
-
-	log.Infof("Successfully removed taint from the %v node", node.Name)
-	return nil
+	return filtered
 }
 
-// GetTaintDetails return the key, value and effect for the taint
-func GetTaintDetails(experimentsDetails *experimentTypes.ExperimentDetails) (string, string, string) {
-	TaintValue := "node-taint"
-	TaintEffect := string(apiv1.TaintEffectNoExecute)
+// GetTaintDetails parse the comma separated taints in key=value:effect format
+// the value defaults to node-taint and the effect defaults to NoExecute
+func GetTaintDetails(experimentsDetails *experimentTypes.ExperimentDetails) ([]apiv1.Taint, error) {
+
+	var taints []apiv1.Taint
+	for _, taint := range strings.Split(experimentsDetails.Taints, ",") {
+		taint = strings.TrimSpace(taint)
+		if taint == "" {
+			continue
+		}
+		TaintValue := "node-taint"
+		TaintEffect := string(apiv1.TaintEffectNoExecute)
+
+		Taints := strings.Split(taint, ":")
+		TaintLabel := strings.Split(Taints[0], "=")
+		TaintKey := TaintLabel[0]
 
-	Taints := strings.Split(experimentsDetails.Taints, ":")
-	TaintLabel := strings.Split(Taints[0], "=")
-	TaintKey := TaintLabel[0]
+		// It will set the value for taint label from `TAINTS` env, if provided
+		// otherwise it will use the `node-taint` value as default value.
+		if len(TaintLabel) >= 2 {
+			TaintValue = TaintLabel[1]
+		}
+		// It will set the value for taint effect from `TAINTS` env, if provided
+		// otherwise it will use `NoExecute` value as default value.
+		if len(Taints) >= 2 {
+			TaintEffect = Taints[1]
+		}
 
-	// It will set the value for taint label from `TAINT` env, if provided
-	// otherwise it will use the `node-taint` value as default value.
-	if len(TaintLabel) >= 2 {
-		TaintValue = TaintLabel[1]
+		switch apiv1.TaintEffect(TaintEffect) {
+		case apiv1.TaintEffectNoExecute, apiv1.TaintEffectNoSchedule, apiv1.TaintEffectPreferNoSchedule:
+		default:
+			return nil, errors.Errorf("invalid effect %v for the %v taint", TaintEffect, TaintKey)
+		}
+		taints = append(taints, apiv1.Taint{
+			Key:    TaintKey,
+			Value:  TaintValue,
+			Effect: apiv1.TaintEffect(TaintEffect),
+		})
 	}
-	// It will set the value for taint effect from `TAINT` env, if provided
-	// otherwise it will use `NoExecute` value as default value.
-	if len(Taints) >= 2 {
-		TaintEffect = Taints[1]
+	if len(taints) == 0 {
+		return nil, errors.Errorf("no taints found, please provide the taints in key=value:effect format")
 	}
-
-	return TaintKey, TaintValue, TaintEffect
+	return taints, nil
 }
 
 // abortWatcher continuosly watch for the abort signals
@@ -238,7 +289,7 @@ func abortWatcher(experimentsDetails *experimentTypes.ExperimentDetails, clients
 			retry := 3
 			for retry > 0 {
 				if err := RemoveTaintFromNode(experimentsDetails, clients); err != nil {
-					log.Errorf("Unable to untaint node, err: %v", err)
+					log.Errorf("Unable to untaint the nodes, err: %v", err)
 				}
 				retry--
 				time.Sleep(1 * time.Second)
@@ -262,3 +313,4 @@ func abortWatcher(experimentsDetails *experimentTypes.ExperimentDetails, clients
 		}
 	}
 }
+
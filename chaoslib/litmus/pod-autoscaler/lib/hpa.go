@@ -0,0 +1,73 @@
+package lib
+
+import (
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/pod-autoscaler/types"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	retries "k8s.io/client-go/util/retry"
+)
+
+//PauseHPA pins the horizontal pod autoscalers of the applications to the target replica count
+//so that they don't revert the scaling during the chaos, the initial bounds are kept to resume them
+func PauseHPA(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, appsUnderTest []experimentTypes.ApplicationUnderTest, kind string) error {
+
+	hpaList, err := clients.KubeClient.AutoscalingV1().HorizontalPodAutoscalers(experimentsDetails.AppNS).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Errorf("Unable to list the horizontal pod autoscalers, err: %v", err)
+	}
+
+	for i := range appsUnderTest {
+		for _, hpa := range hpaList.Items {
+			if hpa.Spec.ScaleTargetRef.Kind != kind || hpa.Spec.ScaleTargetRef.Name != appsUnderTest[i].AppName {
+				continue
+			}
+			appsUnderTest[i].HPA = &experimentTypes.HPADetails{
+				Name:        hpa.Name,
+				MinReplicas: hpa.Spec.MinReplicas,
+				MaxReplicas: hpa.Spec.MaxReplicas,
+			}
+			// the horizontal pod autoscaler doesn't act on the applications scaled to zero
+			if experimentsDetails.Replicas == 0 {
+				log.Infof("[Info]: %v hpa of %v application is inactive for zero replicas", hpa.Name, appsUnderTest[i].AppName)
+				continue
+			}
+			if err := updateHPABounds(experimentsDetails.AppNS, hpa.Name, int32Ptr(int32(experimentsDetails.Replicas)), int32(experimentsDetails.Replicas), clients); err != nil {
+				return errors.Errorf("Unable to pause the %v hpa, err: %v", hpa.Name, err)
+			}
+			log.Infof("[Info]: Paused the %v hpa of %v application at %v replicas", hpa.Name, appsUnderTest[i].AppName, experimentsDetails.Replicas)
+		}
+	}
+	return nil
+}
+
+//ResumeHPA restores the initial replica bounds of the horizontal pod autoscalers of the applications
+func ResumeHPA(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, appsUnderTest []experimentTypes.ApplicationUnderTest) error {
+
+	for _, app := range appsUnderTest {
+		if app.HPA == nil {
+			continue
+		}
+		if err := updateHPABounds(experimentsDetails.AppNS, app.HPA.Name, app.HPA.MinReplicas, app.HPA.MaxReplicas, clients); err != nil {
+			return errors.Errorf("Unable to resume the %v hpa, err: %v", app.HPA.Name, err)
+		}
+		log.Infof("[Info]: Resumed the %v hpa of %v application", app.HPA.Name, app.AppName)
+	}
+	return nil
+}
+
+// updateHPABounds updates the replica bounds of the horizontal pod autoscaler
+func updateHPABounds(namespace, name string, minReplicas *int32, maxReplicas int32, clients clients.ClientSets) error {
+
+	return retries.RetryOnConflict(retries.DefaultRetry, func() error {
+		hpa, err := clients.KubeClient.AutoscalingV1().HorizontalPodAutoscalers(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		hpa.Spec.MinReplicas = minReplicas
+		hpa.Spec.MaxReplicas = maxReplicas
+		_, err = clients.KubeClient.AutoscalingV1().HorizontalPodAutoscalers(namespace).Update(hpa)
+		return err
+	})
+}
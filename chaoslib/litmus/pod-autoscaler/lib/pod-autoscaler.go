@@ -2,9 +2,9 @@ package lib
 
 import (
 	"math"
+	"math/rand"
 	"os"
 	"os/signal"
-	"strings"
 	"syscall"
 	"time"
 
@@ -17,17 +17,12 @@ import (
 	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
 	"github.com/sirupsen/logrus"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	appsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
-	retries "k8s.io/client-go/util/retry"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/pkg/errors"
 )
 
-var (
-	err                     error
-	appsv1DeploymentClient  appsv1.DeploymentInterface
-	appsv1StatefulsetClient appsv1.StatefulSetInterface
-)
+var err error
 
 //PreparePodAutoscaler contains the prepration steps before chaos injection
 func PreparePodAutoscaler(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
@@ -38,69 +33,43 @@ func PreparePodAutoscaler(experimentsDetails *experimentTypes.ExperimentDetails,
 		common.WaitForDuration(experimentsDetails.RampTime)
 	}
 
-	// initialise the resource clients
-	appsv1DeploymentClient = clients.KubeClient.AppsV1().Deployments(experimentsDetails.AppNS)
-	appsv1StatefulsetClient = clients.KubeClient.AppsV1().StatefulSets(experimentsDetails.AppNS)
-
-	switch strings.ToLower(experimentsDetails.AppKind) {
-	case "deployment", "deployments":
-
-		appsUnderTest, err := GetDeploymentDetails(experimentsDetails, clients)
-		if err != nil {
-			return errors.Errorf("Unable to get the name & replicaCount of the deployment, err: %v", err)
-		}
-
-		deploymentList := []string{}
-		for _, deployment := range appsUnderTest {
-			deploymentList = append(deploymentList, deployment.AppName)
-		}
-		log.InfoWithValues("[Info]: Details of Deployments under chaos injection", logrus.Fields{
-			"No. Of Deployments": len(deploymentList),
-			"Target Deployments": deploymentList,
-		})
-
-		//calling go routine which will continuously watch for the abort signal
-		go AbortPodAutoScalerChaos(appsUnderTest, experimentsDetails, clients, resultDetails, eventsDetails, chaosDetails)
-
-		err = PodAutoscalerChaosInDeployment(experimentsDetails, clients, appsUnderTest, resultDetails, eventsDetails, chaosDetails)
-		if err != nil {
-			return errors.Errorf("Unable to perform autoscaling, err: %v", err)
-		}
-
-		err = AutoscalerRecoveryInDeployment(experimentsDetails, clients, appsUnderTest)
-		if err != nil {
-			return errors.Errorf("Unable to rollback the autoscaling, err: %v", err)
-		}
-
-	case "statefulset", "statefulsets":
+	// deriving the resource of the applications, any resource exposing the scale subresource is supported
+	resource, err := GetScalableResource(experimentsDetails.AppKind, clients)
+	if err != nil {
+		return errors.Errorf("application type '%s' is not supported for the chaos, err: %v", experimentsDetails.AppKind, err)
+	}
 
-		appsUnderTest, err := GetStatefulsetDetails(experimentsDetails, clients)
-		if err != nil {
-			return errors.Errorf("Unable to get the name & replicaCount of the statefulset, err: %v", err)
-		}
+	appsUnderTest, err := GetApplicationDetails(experimentsDetails, resource, clients)
+	if err != nil {
+		return errors.Errorf("Unable to get the name & replicaCount of the %v, err: %v", resource.GVR.Resource, err)
+	}
 
-		stsList := []string{}
-		for _, sts := range appsUnderTest {
-			stsList = append(stsList, sts.AppName)
-		}
-		log.InfoWithValues("[Info]: Details of Statefulsets under chaos injection", logrus.Fields{
-			"No. Of Statefulsets": len(stsList),
-			"Target Statefulsets": stsList,
-		})
+	appList := []string{}
+	for _, app := range appsUnderTest {
+		appList = append(appList, app.AppName)
+	}
+	log.InfoWithValues("[Info]: Details of Applications under chaos injection", logrus.Fields{
+		"Kind":                resource.Kind,
+		"No. Of Applications": len(appList),
+		"Target Applications": appList,
+	})
 
-		//calling go routine which will continuously watch for the abort signal
-		go AbortPodAutoScalerChaos(appsUnderTest, experimentsDetails, clients, resultDetails, eventsDetails, chaosDetails)
+	// pausing the hpa of the applications, so that it doesn't revert the scaling
+	if err = PauseHPA(experimentsDetails, clients, appsUnderTest, resource.Kind); err != nil {
+		ResumeHPA(experimentsDetails, clients, appsUnderTest)
+		return err
+	}
 
-		if err = PodAutoscalerChaosInStatefulset(experimentsDetails, clients, appsUnderTest, resultDetails, eventsDetails, chaosDetails); err != nil {
-			return errors.Errorf("Unable to perform autoscaling, err: %v", err)
-		}
+	//calling go routine which will continuously watch for the abort signal
+	go AbortPodAutoScalerChaos(appsUnderTest, resource, experimentsDetails, clients, resultDetails, eventsDetails, chaosDetails)
 
-		if err = AutoscalerRecoveryInStatefulset(experimentsDetails, clients, appsUnderTest); err != nil {
-			return errors.Errorf("Unable to rollback the autoscaling, err: %v", err)
-		}
+	if err = PodAutoscalerChaos(experimentsDetails, resource, clients, appsUnderTest, resultDetails, eventsDetails, chaosDetails); err != nil {
+		ResumeHPA(experimentsDetails, clients, appsUnderTest)
+		return errors.Errorf("Unable to perform autoscaling, err: %v", err)
+	}
 
-	default:
-		return errors.Errorf("application type '%s' is not supported for the chaos", experimentsDetails.AppKind)
+	if err = AutoscalerRecovery(experimentsDetails, resource, clients, appsUnderTest); err != nil {
+		return errors.Errorf("Unable to rollback the autoscaling, err: %v", err)
 	}
 
 	//Waiting for the ramp time after chaos injection
@@ -111,111 +80,76 @@ func PreparePodAutoscaler(experimentsDetails *experimentTypes.ExperimentDetails,
 	return nil
 }
 
+// getSliceOfTotalApplicationsTargeted selects the random applications as per the APP_AFFECT_PERC
 func getSliceOfTotalApplicationsTargeted(appList []experimentTypes.ApplicationUnderTest, experimentsDetails *experimentTypes.ExperimentDetails) ([]experimentTypes.ApplicationUnderTest, error) {
 
 	slice := int(math.Round(float64(len(appList)*experimentsDetails.AppAffectPercentage) / float64(100)))
 	if slice < 0 || slice > len(appList) {
 		return nil, errors.Errorf("slice of applications to target out of range %d/%d", slice, len(appList))
 	}
+	rand.Seed(time.Now().UnixNano())
+	rand.Shuffle(len(appList), func(i, j int) { appList[i], appList[j] = appList[j], appList[i] })
 	return appList[:slice], nil
 }
 
-//GetDeploymentDetails is used to get the name and total number of replicas of the deployment
-func GetDeploymentDetails(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) ([]experimentTypes.ApplicationUnderTest, error) {
+//GetApplicationDetails is used to get the name and total number of replicas of the applications
+//the replica count and the pod selector are derived from the scale subresource of the applications
+func GetApplicationDetails(experimentsDetails *experimentTypes.ExperimentDetails, resource experimentTypes.ScalableResource, clients clients.ClientSets) ([]experimentTypes.ApplicationUnderTest, error) {
 
-	deploymentList, err := appsv1DeploymentClient.List(metav1.ListOptions{LabelSelector: experimentsDetails.AppLabel})
-	if err != nil || len(deploymentList.Items) == 0 {
-		return nil, errors.Errorf("Unable to find the deployments with matching labels, err: %v", err)
-	}
-	appsUnderTest := []experimentTypes.ApplicationUnderTest{}
-	for _, app := range deploymentList.Items {
-		log.Infof("[DeploymentDetails]: Found deployment name %s with replica count %d", app.Name, int(*app.Spec.Replicas))
-		appsUnderTest = append(appsUnderTest, experimentTypes.ApplicationUnderTest{AppName: app.Name, ReplicaCount: int(*app.Spec.Replicas)})
-	}
-	// Applying the APP_AFFECT_PERC variable to determine the total target deployments to scale
-	return getSliceOfTotalApplicationsTargeted(appsUnderTest, experimentsDetails)
-
-}
-
-//GetStatefulsetDetails is used to get the name and total number of replicas of the statefulsets
-func GetStatefulsetDetails(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) ([]experimentTypes.ApplicationUnderTest, error) {
-
-	statefulsetList, err := appsv1StatefulsetClient.List(metav1.ListOptions{LabelSelector: experimentsDetails.AppLabel})
-	if err != nil || len(statefulsetList.Items) == 0 {
-		return nil, errors.Errorf("Unable to find the statefulsets with matching labels, err: %v", err)
+	appList, err := clients.DynamicClient.Resource(resource.GVR).Namespace(experimentsDetails.AppNS).List(metav1.ListOptions{LabelSelector: experimentsDetails.AppLabel})
+	if err != nil || len(appList.Items) == 0 {
+		return nil, errors.Errorf("Unable to find the %v with matching labels, err: %v", resource.GVR.Resource, err)
 	}
 
 	appsUnderTest := []experimentTypes.ApplicationUnderTest{}
-	for _, app := range statefulsetList.Items {
-		log.Infof("[DeploymentDetails]: Found statefulset name %s with replica count %d", app.Name, int(*app.Spec.Replicas))
-		appsUnderTest = append(appsUnderTest, experimentTypes.ApplicationUnderTest{AppName: app.Name, ReplicaCount: int(*app.Spec.Replicas)})
+	for _, app := range appList.Items {
+		scale, err := getScale(experimentsDetails, resource, app.GetName(), clients)
+		if err != nil {
+			return nil, errors.Errorf("Unable to get the scale of %v %v, err: %v", app.GetName(), resource.Kind, err)
+		}
+		replicas, _, err := unstructured.NestedInt64(scale.Object, "spec", "replicas")
+		if err != nil {
+			return nil, errors.Errorf("Unable to get the replica count of %v %v, err: %v", app.GetName(), resource.Kind, err)
+		}
+		selector, _, err := unstructured.NestedString(scale.Object, "status", "selector")
+		if err != nil {
+			return nil, errors.Errorf("Unable to derive the pod selector of %v %v, err: %v", app.GetName(), resource.Kind, err)
+		}
+		// the ready replicas and the scheduling outcome are derived from the pods, which are selected by the pod selector
+		if selector == "" {
+			return nil, errors.Errorf("%v %v doesn't expose the pod selector in the status of its scale subresource", app.GetName(), resource.Kind)
+		}
+		log.Infof("[ApplicationDetails]: Found %v name %s with replica count %d", resource.Kind, app.GetName(), replicas)
+		appsUnderTest = append(appsUnderTest, experimentTypes.ApplicationUnderTest{AppName: app.GetName(), ReplicaCount: int(replicas), PodSelector: selector})
 	}
-	// Applying the APP_AFFECT_PERC variable to determine the total target deployments to scale
+	// Applying the APP_AFFECT_PERC variable to determine the total target applications to scale
 	return getSliceOfTotalApplicationsTargeted(appsUnderTest, experimentsDetails)
 }
 
-//PodAutoscalerChaosInDeployment scales up the replicas of deployment and verify the status
-func PodAutoscalerChaosInDeployment(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, appsUnderTest []experimentTypes.ApplicationUnderTest, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
-
-	// Scale Application
-	retryErr := retries.RetryOnConflict(retries.DefaultRetry, func() error {
-		for _, app := range appsUnderTest {
-			// Retrieve the latest version of Deployment before attempting update
-			// RetryOnConflict uses exponential backoff to avoid exhausting the apiserver
-			appUnderTest, err := appsv1DeploymentClient.Get(app.AppName, metav1.GetOptions{})
-			if err != nil {
-				return errors.Errorf("Failed to get latest version of Application Deployment, err: %v", err)
-			}
-			// modifying the replica count
-			appUnderTest.Spec.Replicas = int32Ptr(int32(experimentsDetails.Replicas))
-			log.Infof("Updating deployment %s to number of replicas %d", appUnderTest.ObjectMeta.Name, experimentsDetails.Replicas)
-			_, err = appsv1DeploymentClient.Update(appUnderTest)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
-	})
-	if retryErr != nil {
-		return errors.Errorf("Unable to scale the deployment, err: %v", retryErr)
-	}
-	log.Info("Application Started Scaling")
-
-	err = DeploymentStatusCheck(experimentsDetails, clients, appsUnderTest, resultDetails, eventsDetails, chaosDetails)
-	if err != nil {
-		return errors.Errorf("Status Check failed, err: %v", err)
-	}
+//PodAutoscalerChaos scales up the replicas of the applications and verify the status
+func PodAutoscalerChaos(experimentsDetails *experimentTypes.ExperimentDetails, resource experimentTypes.ScalableResource, clients clients.ClientSets, appsUnderTest []experimentTypes.ApplicationUnderTest, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
-	return nil
-}
-
-//PodAutoscalerChaosInStatefulset scales up the replicas of statefulset and verify the status
-func PodAutoscalerChaosInStatefulset(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, appsUnderTest []experimentTypes.ApplicationUnderTest, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+	// recording the scheduling outcome of the replicas, from the start of the scaling
+	recorder := StartScaleRecorder(experimentsDetails, clients, appsUnderTest, resource.Kind)
+	defer recorder.Stop()
 
 	// Scale Application
-	retryErr := retries.RetryOnConflict(retries.DefaultRetry, func() error {
-		for _, app := range appsUnderTest {
-			// Retrieve the latest version of Statefulset before attempting update
-			// RetryOnConflict uses exponential backoff to avoid exhausting the apiserver
-			appUnderTest, err := appsv1StatefulsetClient.Get(app.AppName, metav1.GetOptions{})
-			if err != nil {
-				return errors.Errorf("Failed to get latest version of Application Statefulset, err: %v", err)
-			}
-			// modifying the replica count
-			appUnderTest.Spec.Replicas = int32Ptr(int32(experimentsDetails.Replicas))
-			_, err = appsv1StatefulsetClient.Update(appUnderTest)
-			if err != nil {
-				return err
+	for i, app := range appsUnderTest {
+		log.Infof("Updating %v %s to number of replicas %d", resource.Kind, app.AppName, experimentsDetails.Replicas)
+		if err := scaleApplication(experimentsDetails, resource, app.AppName, experimentsDetails.Replicas, clients); err != nil {
+			// rollback the applications, which are already scaled
+			recorder.Stop()
+			if err := AutoscalerRecovery(experimentsDetails, resource, clients, appsUnderTest[:i]); err != nil {
+				log.Errorf("Unable to rollback the scaled applications, err: %v", err)
 			}
+			return errors.Errorf("Unable to scale the %v %v, err: %v", app.AppName, resource.Kind, err)
 		}
-		return nil
-	})
-	if retryErr != nil {
-		return errors.Errorf("Unable to scale the statefulset, err: %v", retryErr)
 	}
 	log.Info("Application Started Scaling")
 
-	err = StatefulsetStatusCheck(experimentsDetails, clients, appsUnderTest, resultDetails, eventsDetails, chaosDetails)
+	err = ScaleStatusCheck(experimentsDetails, resource, clients, appsUnderTest, recorder, resultDetails, eventsDetails, chaosDetails)
+	recorder.Stop()
+	RecordScaleMetricsInResult(experimentsDetails, resultDetails, recorder)
 	if err != nil {
 		return errors.Errorf("Status Check failed, err: %v", err)
 	}
@@ -223,26 +157,27 @@ func PodAutoscalerChaosInStatefulset(experimentsDetails *experimentTypes.Experim
 	return nil
 }
 
-// DeploymentStatusCheck check the status of deployment and verify the available replicas
-func DeploymentStatusCheck(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, appsUnderTest []experimentTypes.ApplicationUnderTest, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+// ScaleStatusCheck check the status of the applications and verify the ready replicas
+func ScaleStatusCheck(experimentsDetails *experimentTypes.ExperimentDetails, resource experimentTypes.ScalableResource, clients clients.ClientSets, appsUnderTest []experimentTypes.ApplicationUnderTest, recorder *ScaleRecorder, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
 
 	//Record start timestamp
 	ChaosStartTimeStamp := time.Now().Unix()
 	isFailed := false
 
 	err = retry.
-		Times(uint(experimentsDetails.ChaosDuration / experimentsDetails.Delay)).
+		Times(uint(getScaleTimeout(experimentsDetails) / experimentsDetails.Delay)).
 		Wait(time.Duration(experimentsDetails.Delay) * time.Second).
 		Try(func(attempt uint) error {
 			for _, app := range appsUnderTest {
-				deployment, err := appsv1DeploymentClient.Get(app.AppName, metav1.GetOptions{})
+				readyReplicas, err := getReadyReplicas(experimentsDetails, resource, app, clients)
 				if err != nil {
-					return errors.Errorf("Unable to find the deployment with name %v, err: %v", app.AppName, err)
+					return errors.Errorf("Unable to find the ready replicas of %v %v, err: %v", app.AppName, resource.Kind, err)
 				}
-				log.Infof("Deployment's Available Replica Count is %v", deployment.Status.AvailableReplicas)
-				if int(deployment.Status.AvailableReplicas) != experimentsDetails.Replicas {
+				log.Infof("%v %v Ready Replica Count is: %v", resource.Kind, app.AppName, readyReplicas)
+				if readyReplicas != experimentsDetails.Replicas {
 					isFailed = true
-					return errors.Errorf("Application %s is not scaled yet, err: %v", app.AppName, err)
+					logAddedNodes(experimentsDetails, recorder)
+					return errors.Errorf("Application %s is not scaled yet", app.AppName)
 				}
 			}
 			isFailed = false
@@ -250,7 +185,8 @@ func DeploymentStatusCheck(experimentsDetails *experimentTypes.ExperimentDetails
 		})
 
 	if isFailed {
-		err = AutoscalerRecoveryInDeployment(experimentsDetails, clients, appsUnderTest)
+		recorder.Stop()
+		err = AutoscalerRecovery(experimentsDetails, resource, clients, appsUnderTest)
 		if err != nil {
 			return errors.Errorf("Unable to perform autoscaling, err: %v", err)
 		}
@@ -276,83 +212,21 @@ func DeploymentStatusCheck(experimentsDetails *experimentTypes.ExperimentDetails
 	return nil
 }
 
-// StatefulsetStatusCheck check the status of statefulset and verify the available replicas
-func StatefulsetStatusCheck(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, appsUnderTest []experimentTypes.ApplicationUnderTest, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
-
-	//Record start timestamp
-	ChaosStartTimeStamp := time.Now().Unix()
-	isFailed := false
+//AutoscalerRecovery rollback the replicas of the applications to the initial values
+func AutoscalerRecovery(experimentsDetails *experimentTypes.ExperimentDetails, resource experimentTypes.ScalableResource, clients clients.ClientSets, appsUnderTest []experimentTypes.ApplicationUnderTest) error {
 
-	err = retry.
-		Times(uint(experimentsDetails.ChaosDuration / experimentsDetails.Delay)).
-		Wait(time.Duration(experimentsDetails.Delay) * time.Second).
-		Try(func(attempt uint) error {
-			for _, app := range appsUnderTest {
-				statefulset, err := appsv1StatefulsetClient.Get(app.AppName, metav1.GetOptions{})
-				if err != nil {
-					return errors.Errorf("Unable to find the statefulset with name %v, err: %v", app.AppName, err)
-				}
-				log.Infof("Statefulset's Ready Replica Count is: %v", statefulset.Status.ReadyReplicas)
-				if int(statefulset.Status.ReadyReplicas) != experimentsDetails.Replicas {
-					isFailed = true
-					return errors.Errorf("Application is not scaled yet, err: %v", err)
-				}
-			}
-			isFailed = false
-			return nil
-		})
-
-	if isFailed {
-		err = AutoscalerRecoveryInStatefulset(experimentsDetails, clients, appsUnderTest)
-		if err != nil {
-			return errors.Errorf("Unable to perform autoscaling, err: %v", err)
-		}
-		return errors.Errorf("Failed to scale the application")
-	}
-	if err != nil {
-		return err
-	}
-
-	// run the probes during chaos
-	if len(resultDetails.ProbeDetails) != 0 {
-		if err = probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
-			return err
+	// resuming the hpa once the applications are rolled back
+	defer func() {
+		if err := ResumeHPA(experimentsDetails, clients, appsUnderTest); err != nil {
+			log.Errorf("%v", err)
 		}
-	}
-
-	//ChaosCurrentTimeStamp contains the current timestamp
-	ChaosCurrentTimeStamp := time.Now().Unix()
-	if int(ChaosCurrentTimeStamp-ChaosStartTimeStamp) <= experimentsDetails.ChaosDuration {
-		log.Info("[Wait]: Waiting for completion of chaos duration")
-		time.Sleep(time.Duration(experimentsDetails.ChaosDuration-int(ChaosCurrentTimeStamp-ChaosStartTimeStamp)) * time.Second)
-	}
-
-	return nil
-}
-
-//AutoscalerRecoveryInDeployment rollback the replicas to initial values in deployment
-func AutoscalerRecoveryInDeployment(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, appsUnderTest []experimentTypes.ApplicationUnderTest) error {
+	}()
 
 	// Scale back to initial number of replicas
-	retryErr := retries.RetryOnConflict(retries.DefaultRetry, func() error {
-		// Retrieve the latest version of Deployment before attempting update
-		// RetryOnConflict uses exponential backoff to avoid exhausting the apiserver
-		for _, app := range appsUnderTest {
-			appUnderTest, err := appsv1DeploymentClient.Get(app.AppName, metav1.GetOptions{})
-			if err != nil {
-				return errors.Errorf("Failed to find the latest version of Application Deployment with name %v, err: %v", app.AppName, err)
-			}
-
-			appUnderTest.Spec.Replicas = int32Ptr(int32(app.ReplicaCount)) // modify replica count
-			_, err = appsv1DeploymentClient.Update(appUnderTest)
-			if err != nil {
-				return err
-			}
+	for _, app := range appsUnderTest {
+		if err := scaleApplication(experimentsDetails, resource, app.AppName, app.ReplicaCount, clients); err != nil {
+			return errors.Errorf("Unable to rollback the %v %v, err: %v", app.AppName, resource.Kind, err)
 		}
-		return nil
-	})
-	if retryErr != nil {
-		return errors.Errorf("Unable to rollback the deployment, err: %v", retryErr)
 	}
 	log.Info("[Info]: Application pod started rolling back")
 
@@ -361,13 +235,13 @@ func AutoscalerRecoveryInDeployment(experimentsDetails *experimentTypes.Experime
 		Wait(time.Duration(experimentsDetails.Delay) * time.Second).
 		Try(func(attempt uint) error {
 			for _, app := range appsUnderTest {
-				applicationDeploy, err := appsv1DeploymentClient.Get(app.AppName, metav1.GetOptions{})
+				readyReplicas, err := getReadyReplicas(experimentsDetails, resource, app, clients)
 				if err != nil {
-					return errors.Errorf("Unable to find the deployment with name %v, err: %v", app.AppName, err)
+					return errors.Errorf("Unable to find the ready replicas of %v %v, err: %v", app.AppName, resource.Kind, err)
 				}
-				if int(applicationDeploy.Status.AvailableReplicas) != app.ReplicaCount {
-					log.Infof("Application Available Replica Count is: %v", applicationDeploy.Status.AvailableReplicas)
-					return errors.Errorf("Unable to rollback to older replica count, err: %v", err)
+				if readyReplicas != app.ReplicaCount {
+					log.Infof("Application Ready Replica Count is: %v", readyReplicas)
+					return errors.Errorf("Unable to rollback to older replica count")
 				}
 			}
 			return nil
@@ -381,62 +255,26 @@ func AutoscalerRecoveryInDeployment(experimentsDetails *experimentTypes.Experime
 	return nil
 }
 
-//AutoscalerRecoveryInStatefulset rollback the replicas to initial values in deployment
-func AutoscalerRecoveryInStatefulset(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, appsUnderTest []experimentTypes.ApplicationUnderTest) error {
-
-	// Scale back to initial number of replicas
-	retryErr := retries.RetryOnConflict(retries.DefaultRetry, func() error {
-		for _, app := range appsUnderTest {
-			// Retrieve the latest version of Statefulset before attempting update
-			// RetryOnConflict uses exponential backoff to avoid exhausting the apiserver
-			appUnderTest, err := appsv1StatefulsetClient.Get(app.AppName, metav1.GetOptions{})
-			if err != nil {
-				return errors.Errorf("Failed to find the latest version of Statefulset with name %v, err: %v", app.AppName, err)
-			}
+func int32Ptr(i int32) *int32 { return &i }
 
-			appUnderTest.Spec.Replicas = int32Ptr(int32(app.ReplicaCount)) // modify replica count
-			_, err = appsv1StatefulsetClient.Update(appUnderTest)
-			if err != nil {
-				return err
-			}
-		}
-		return nil
-	})
-	if retryErr != nil {
-		return errors.Errorf("Unable to rollback the statefulset, err: %v", retryErr)
+// getScaleTimeout returns the time in seconds, within which the applications should be scaled
+// the nodes provisioned by the cluster autoscaler are waited for in addition to the chaos duration
+func getScaleTimeout(experimentsDetails *experimentTypes.ExperimentDetails) int {
+	if experimentsDetails.WaitForNodes {
+		return experimentsDetails.ChaosDuration + experimentsDetails.NodeProvisionTimeout
 	}
-	log.Info("[Info]: Application pod started rolling back")
-
-	err = retry.
-		Times(uint(experimentsDetails.Timeout / experimentsDetails.Delay)).
-		Wait(time.Duration(experimentsDetails.Delay) * time.Second).
-		Try(func(attempt uint) error {
-			for _, app := range appsUnderTest {
-				applicationDeploy, err := appsv1StatefulsetClient.Get(app.AppName, metav1.GetOptions{})
-
-				if err != nil {
-					return errors.Errorf("Unable to find the statefulset with name %v, err: %v", app.AppName, err)
-				}
-				if int(applicationDeploy.Status.ReadyReplicas) != app.ReplicaCount {
-					log.Infof("Application Ready Replica Count is: %v", applicationDeploy.Status.ReadyReplicas)
-					return errors.Errorf("Unable to roll back to older replica count, err: %v", err)
-				}
-			}
-			return nil
-		})
+	return experimentsDetails.ChaosDuration
+}
 
-	if err != nil {
-		return err
+// logAddedNodes logs the nodes added by the cluster autoscaler, while the applications are not scaled yet
+func logAddedNodes(experimentsDetails *experimentTypes.ExperimentDetails, recorder *ScaleRecorder) {
+	if experimentsDetails.WaitForNodes {
+		log.Infof("[Wait]: Waiting for the cluster autoscaler, %v nodes are added so far", recorder.AddedNodes())
 	}
-	log.Info("[RollBack]: Application Pod roll back to initial number of replicas")
-
-	return nil
 }
 
-func int32Ptr(i int32) *int32 { return &i }
-
 //AbortPodAutoScalerChaos go routine will continuously watch for the abort signal for the entire chaos duration and generate the required events and result
-func AbortPodAutoScalerChaos(appsUnderTest []experimentTypes.ApplicationUnderTest, experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) {
+func AbortPodAutoScalerChaos(appsUnderTest []experimentTypes.ApplicationUnderTest, resource experimentTypes.ScalableResource, experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) {
 
 	// signChan channel is used to transmit signal notifications.
 	signChan := make(chan os.Signal, 1)
@@ -455,19 +293,8 @@ func AbortPodAutoScalerChaos(appsUnderTest []experimentTypes.ApplicationUnderTes
 			// ..tests indicate we succeed with the downscale/patch call, even if the status checks take longer
 			// As such, this is a workaround, and other solutions such as usage of pre-stop hooks etc., need to be explored
 			// Other experiments have simpler "recoveries" that are more or less guaranteed to work.
-			switch strings.ToLower(experimentsDetails.AppKind) {
-			case "deployment", "deployments":
-				if err := AutoscalerRecoveryInDeployment(experimentsDetails, clients, appsUnderTest); err != nil {
-					log.Errorf("the recovery after abortion failed err: %v", err)
-				}
-			case "statefulset", "statefulsets":
-
-				if err := AutoscalerRecoveryInStatefulset(experimentsDetails, clients, appsUnderTest); err != nil {
-					log.Errorf("the recovery after abortion failed err: %v", err)
-				}
-
-			default:
-				log.Errorf("application type '%s' is not supported for the chaos", experimentsDetails.AppKind)
+			if err := AutoscalerRecovery(experimentsDetails, resource, clients, appsUnderTest); err != nil {
+				log.Errorf("the recovery after abortion failed err: %v", err)
 			}
 			log.Info("[Chaos]: Revert Completed")
 
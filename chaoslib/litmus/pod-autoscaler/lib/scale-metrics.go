@@ -0,0 +1,397 @@
+package lib
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/pod-autoscaler/types"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/sirupsen/logrus"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientTypes "k8s.io/apimachinery/pkg/types"
+)
+
+// podTimeline contains the scheduling outcome of a pod created during the chaos
+type podTimeline struct {
+	scheduledAt    time.Time
+	readyAt        time.Time
+	pendingReasons map[string]bool
+}
+
+// appTimeline contains the pods of an application observed during the chaos
+type appTimeline struct {
+	app                experimentTypes.ApplicationUnderTest
+	initialPods        map[clientTypes.UID]bool
+	pods               map[clientTypes.UID]*podTimeline
+	failedCreateReason string
+	scaledDownAt       time.Time
+}
+
+// ScaleRecorder samples the pods of the scaled applications and the nodes of the cluster, till it is stopped
+type ScaleRecorder struct {
+	mu                 sync.Mutex
+	once               sync.Once
+	stop               chan struct{}
+	done               chan struct{}
+	start              time.Time
+	kind               string
+	experimentsDetails *experimentTypes.ExperimentDetails
+	clients            clients.ClientSets
+	apps               []*appTimeline
+	initialNodes       map[string]bool
+	addedNodes         map[string]time.Time
+}
+
+//StartScaleRecorder records the existing pods and nodes and starts sampling them at every delay interval
+//it should be started just before the applications are scaled
+func StartScaleRecorder(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, appsUnderTest []experimentTypes.ApplicationUnderTest, kind string) *ScaleRecorder {
+
+	recorder := &ScaleRecorder{
+		stop:               make(chan struct{}),
+		done:               make(chan struct{}),
+		kind:               kind,
+		experimentsDetails: experimentsDetails,
+		clients:            clients,
+		initialNodes:       map[string]bool{},
+		addedNodes:         map[string]time.Time{},
+	}
+
+	for _, app := range appsUnderTest {
+		timeline := &appTimeline{
+			app:         app,
+			initialPods: map[clientTypes.UID]bool{},
+			pods:        map[clientTypes.UID]*podTimeline{},
+		}
+		podList, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.AppNS).List(metav1.ListOptions{LabelSelector: app.PodSelector})
+		if err != nil {
+			log.Warnf("Unable to list the pods of %v application, err: %v", app.AppName, err)
+		}
+		if podList != nil {
+			for _, pod := range podList.Items {
+				timeline.initialPods[pod.UID] = true
+			}
+		}
+		recorder.apps = append(recorder.apps, timeline)
+	}
+
+	if experimentsDetails.WaitForNodes {
+		nodeList, err := clients.KubeClient.CoreV1().Nodes().List(metav1.ListOptions{})
+		if err != nil {
+			log.Warnf("Unable to list the nodes, err: %v", err)
+		}
+		if nodeList != nil {
+			for _, node := range nodeList.Items {
+				recorder.initialNodes[node.Name] = true
+			}
+		}
+	}
+
+	recorder.start = time.Now()
+	go func() {
+		defer close(recorder.done)
+		ticker := time.NewTicker(time.Duration(experimentsDetails.Delay) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-recorder.stop:
+				recorder.sample()
+				return
+			case <-ticker.C:
+				recorder.sample()
+			}
+		}
+	}()
+
+	return recorder
+}
+
+//Stop takes the last sample and stops the recorder, it can be called more than once
+func (r *ScaleRecorder) Stop() {
+	r.once.Do(func() {
+		close(r.stop)
+		<-r.done
+	})
+}
+
+// sample records the scheduling state of the pods created since the start of the recorder
+func (r *ScaleRecorder) sample() {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, timeline := range r.apps {
+		podList, err := r.clients.KubeClient.CoreV1().Pods(r.experimentsDetails.AppNS).List(metav1.ListOptions{LabelSelector: timeline.app.PodSelector})
+		if err != nil {
+			log.Warnf("Unable to list the pods of %v application, err: %v", timeline.app.AppName, err)
+			continue
+		}
+
+		// the terminating pods are listed till they are removed, so the scale down is completed once the pod count reaches the target
+		if r.experimentsDetails.Replicas < timeline.app.ReplicaCount && timeline.scaledDownAt.IsZero() && len(podList.Items) <= r.experimentsDetails.Replicas {
+			timeline.scaledDownAt = time.Now()
+		}
+
+		for _, pod := range podList.Items {
+			if timeline.initialPods[pod.UID] {
+				continue
+			}
+			pt, ok := timeline.pods[pod.UID]
+			if !ok {
+				pt = &podTimeline{pendingReasons: map[string]bool{}}
+				timeline.pods[pod.UID] = pt
+			}
+			for _, condition := range pod.Status.Conditions {
+				switch {
+				case condition.Type == apiv1.PodScheduled && condition.Status == apiv1.ConditionTrue:
+					pt.scheduledAt = condition.LastTransitionTime.Time
+				case condition.Type == apiv1.PodScheduled && condition.Reason == apiv1.PodReasonUnschedulable:
+					for _, reason := range getUnschedulableReasons(condition.Message) {
+						pt.pendingReasons[reason] = true
+					}
+				case condition.Type == apiv1.PodReady && condition.Status == apiv1.ConditionTrue && pt.readyAt.IsZero():
+					pt.readyAt = condition.LastTransitionTime.Time
+				}
+			}
+		}
+
+		if reason := r.getFailedCreateReason(timeline.app.AppName); reason != "" {
+			timeline.failedCreateReason = reason
+		}
+	}
+
+	if r.experimentsDetails.WaitForNodes {
+		r.sampleNodes()
+	}
+}
+
+// getFailedCreateReason returns the reason due to which the controller failed to create the pods, like the exceeded quota
+func (r *ScaleRecorder) getFailedCreateReason(appName string) string {
+
+	switch r.kind {
+	case "Deployment":
+		deployment, err := r.clients.KubeClient.AppsV1().Deployments(r.experimentsDetails.AppNS).Get(appName, metav1.GetOptions{})
+		if err != nil {
+			return ""
+		}
+		for _, condition := range deployment.Status.Conditions {
+			if condition.Type == "ReplicaFailure" && condition.Status == apiv1.ConditionTrue {
+				return condition.Message
+			}
+		}
+	default:
+		eventList, err := r.clients.KubeClient.CoreV1().Events(r.experimentsDetails.AppNS).List(metav1.ListOptions{
+			FieldSelector: "involvedObject.kind=" + r.kind + ",involvedObject.name=" + appName + ",reason=FailedCreate",
+		})
+		if err != nil {
+			return ""
+		}
+		var reason string
+		var lastSeen time.Time
+		for _, event := range eventList.Items {
+			if event.LastTimestamp.Time.Before(r.start.Truncate(time.Second)) || event.LastTimestamp.Time.Before(lastSeen) {
+				continue
+			}
+			reason, lastSeen = event.Message, event.LastTimestamp.Time
+		}
+		return reason
+	}
+	return ""
+}
+
+// sampleNodes records the nodes added since the start of the recorder, along with the time they became ready
+func (r *ScaleRecorder) sampleNodes() {
+
+	nodeList, err := r.clients.KubeClient.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		log.Warnf("Unable to list the nodes, err: %v", err)
+		return
+	}
+	for _, node := range nodeList.Items {
+		if r.initialNodes[node.Name] {
+			continue
+		}
+		if _, ok := r.addedNodes[node.Name]; !ok {
+			log.Infof("[Info]: %v node is added to the cluster", node.Name)
+		}
+		readyAt := r.addedNodes[node.Name]
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == apiv1.NodeReady && condition.Status == apiv1.ConditionTrue && readyAt.IsZero() {
+				readyAt = condition.LastTransitionTime.Time
+			}
+		}
+		r.addedNodes[node.Name] = readyAt
+	}
+}
+
+//AddedNodes returns the number of nodes added to the cluster since the start of the recorder
+func (r *ScaleRecorder) AddedNodes() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.addedNodes)
+}
+
+//Metrics derive the scheduling outcome of all the applications from the samples
+func (r *ScaleRecorder) Metrics() map[string]experimentTypes.ScaleMetrics {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metrics := map[string]experimentTypes.ScaleMetrics{}
+	for _, timeline := range r.apps {
+		m := experimentTypes.ScaleMetrics{
+			InitialReplicas:    timeline.app.ReplicaCount,
+			TargetReplicas:     r.experimentsDetails.Replicas,
+			CreatedReplicas:    len(timeline.pods),
+			FailedCreateReason: timeline.failedCreateReason,
+		}
+		var totalScheduled, totalReady float64
+		for _, pt := range timeline.pods {
+			if !pt.scheduledAt.IsZero() {
+				m.ScheduledReplicas++
+				elapsed := r.elapsed(pt.scheduledAt)
+				totalScheduled += elapsed
+				m.MaxTimeToScheduled = math.Max(m.MaxTimeToScheduled, elapsed)
+			}
+			if !pt.readyAt.IsZero() {
+				m.ReadyReplicas++
+				elapsed := r.elapsed(pt.readyAt)
+				totalReady += elapsed
+				m.MaxTimeToReady = math.Max(m.MaxTimeToReady, elapsed)
+			}
+			if len(pt.pendingReasons) != 0 {
+				m.PendingReplicas++
+				if m.PendingReasons == nil {
+					m.PendingReasons = map[string]int{}
+				}
+				for reason := range pt.pendingReasons {
+					m.PendingReasons[reason]++
+				}
+			}
+		}
+		if m.ScheduledReplicas != 0 {
+			m.AvgTimeToScheduled = round(totalScheduled / float64(m.ScheduledReplicas))
+		}
+		if m.ReadyReplicas != 0 {
+			m.AvgTimeToReady = round(totalReady / float64(m.ReadyReplicas))
+		}
+		if !timeline.scaledDownAt.IsZero() {
+			m.TimeToScaleDown = r.elapsed(timeline.scaledDownAt)
+		}
+		metrics[timeline.app.AppName] = m
+	}
+	return metrics
+}
+
+//NodeMetrics derive the nodes provisioned during the chaos from the samples
+func (r *ScaleRecorder) NodeMetrics() experimentTypes.NodeMetrics {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	m := experimentTypes.NodeMetrics{
+		InitialNodes: len(r.initialNodes),
+		NodesAdded:   len(r.addedNodes),
+	}
+	for _, readyAt := range r.addedNodes {
+		if readyAt.IsZero() {
+			continue
+		}
+		if elapsed := r.elapsed(readyAt); m.TimeToNodeReady == 0 || elapsed < m.TimeToNodeReady {
+			m.TimeToNodeReady = elapsed
+		}
+	}
+	return m
+}
+
+// elapsed returns the seconds elapsed between the start of the recorder and the given time
+// the condition timestamps have the precision of seconds, so it is bounded to zero
+func (r *ScaleRecorder) elapsed(t time.Time) float64 {
+	return round(math.Max(0, t.Sub(r.start).Seconds()))
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// getUnschedulableReasons extract the reasons from the message of the scheduler
+// eg. `0/3 nodes are available: 1 node(s) had taint {key: value}, that the pod didn't tolerate, 2 Insufficient cpu.`
+func getUnschedulableReasons(message string) []string {
+
+	if index := strings.Index(message, ": "); index != -1 {
+		message = message[index+2:]
+	}
+	// the newer schedulers append the preemption details as a separate sentence
+	if index := strings.Index(message, ". "); index != -1 {
+		message = message[:index]
+	}
+	message = strings.TrimSuffix(strings.TrimSpace(message), ".")
+
+	var reasons []string
+	for _, part := range strings.Split(message, ", ") {
+		fields := strings.SplitN(part, " ", 2)
+		if len(fields) == 2 && len(fields[0]) != 0 && unicode.IsDigit(rune(fields[0][0])) {
+			reasons = append(reasons, fields[1])
+			continue
+		}
+		// the part is a continuation of the previous reason
+		if len(reasons) != 0 {
+			reasons[len(reasons)-1] += ", " + part
+			continue
+		}
+		reasons = append(reasons, part)
+	}
+	return reasons
+}
+
+//RecordScaleMetricsInResult records the scheduling outcome of the applications in the chaosresult
+func RecordScaleMetricsInResult(experimentsDetails *experimentTypes.ExperimentDetails, resultDetails *types.ResultDetails, recorder *ScaleRecorder) {
+
+	if resultDetails.Annotations == nil {
+		resultDetails.Annotations = map[string]string{}
+	}
+
+	metrics := recorder.Metrics()
+	for appName, m := range metrics {
+		log.InfoWithValues("[Info]: The scaling outcome of the "+appName+" application", logrus.Fields{
+			"InitialReplicas":    m.InitialReplicas,
+			"TargetReplicas":     m.TargetReplicas,
+			"ScheduledReplicas":  m.ScheduledReplicas,
+			"ReadyReplicas":      m.ReadyReplicas,
+			"PendingReplicas":    m.PendingReplicas,
+			"MaxTimeToScheduled": m.MaxTimeToScheduled,
+			"MaxTimeToReady":     m.MaxTimeToReady,
+		})
+		for reason, count := range m.PendingReasons {
+			log.Infof("[Info]: %v replicas of %v application went pending due to: %v", count, appName, reason)
+		}
+		if m.FailedCreateReason != "" {
+			log.Infof("[Info]: Replicas of %v application failed to create due to: %v", appName, m.FailedCreateReason)
+		}
+	}
+	if value, err := json.Marshal(metrics); err != nil {
+		log.Warnf("Unable to record the scaling metrics in the chaosresult, err: %v", err)
+	} else {
+		resultDetails.Annotations["litmuschaos.io/pod-autoscaler-scaling"] = string(value)
+	}
+
+	if experimentsDetails.WaitForNodes {
+		nodeMetrics := recorder.NodeMetrics()
+		log.InfoWithValues("[Info]: The nodes provisioned during the chaos", logrus.Fields{
+			"InitialNodes":    nodeMetrics.InitialNodes,
+			"NodesAdded":      nodeMetrics.NodesAdded,
+			"TimeToNodeReady": nodeMetrics.TimeToNodeReady,
+		})
+		if value, err := json.Marshal(nodeMetrics); err != nil {
+			log.Warnf("Unable to record the node metrics in the chaosresult, err: %v", err)
+		} else {
+			resultDetails.Annotations["litmuschaos.io/pod-autoscaler-nodes"] = string(value)
+		}
+	}
+}
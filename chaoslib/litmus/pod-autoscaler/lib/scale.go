@@ -0,0 +1,126 @@
+package lib
+
+import (
+	"strings"
+
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/pod-autoscaler/types"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	retries "k8s.io/client-go/util/retry"
+)
+
+//GetScalableResource derive the resource of the given kind from the discovery api and verify that it exposes the scale subresource
+//the kind can be the kind, the singular, plural or short name of the resource, optionally qualified with the group eg. rollouts.argoproj.io
+func GetScalableResource(kind string, clients clients.ClientSets) (experimentTypes.ScalableResource, error) {
+
+	kind = strings.ToLower(strings.TrimSpace(kind))
+
+	// the discovery returns the partial result, if some of the api groups are unavailable
+	resourceLists, err := clients.KubeClient.Discovery().ServerPreferredResources()
+	if err != nil && len(resourceLists) == 0 {
+		return experimentTypes.ScalableResource{}, errors.Errorf("Unable to discover the api resources, err: %v", err)
+	}
+
+	for _, resourceList := range resourceLists {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			if strings.Contains(resource.Name, "/") || !resource.Namespaced || !matchesKind(kind, resource, gv.Group) {
+				continue
+			}
+			scalable, err := hasScaleSubresource(resourceList.GroupVersion, resource.Name, clients)
+			if err != nil {
+				return experimentTypes.ScalableResource{}, err
+			}
+			if !scalable {
+				return experimentTypes.ScalableResource{}, errors.Errorf("%v resource doesn't expose the scale subresource", resource.Name)
+			}
+			return experimentTypes.ScalableResource{
+				GVR:  gv.WithResource(resource.Name),
+				Kind: resource.Kind,
+			}, nil
+		}
+	}
+	return experimentTypes.ScalableResource{}, errors.Errorf("application type '%s' is not found in the cluster", kind)
+}
+
+// matchesKind checks whether the given kind refers to the resource
+func matchesKind(kind string, resource metav1.APIResource, group string) bool {
+
+	names := append([]string{strings.ToLower(resource.Kind), resource.Name, resource.SingularName}, resource.ShortNames...)
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if kind == name || (group != "" && kind == name+"."+group) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasScaleSubresource checks whether the resource of the given group version exposes the scale subresource
+func hasScaleSubresource(groupVersion, resourceName string, clients clients.ClientSets) (bool, error) {
+
+	resourceList, err := clients.KubeClient.Discovery().ServerResourcesForGroupVersion(groupVersion)
+	if err != nil {
+		return false, errors.Errorf("Unable to discover the resources of %v, err: %v", groupVersion, err)
+	}
+	for _, resource := range resourceList.APIResources {
+		if resource.Name == resourceName+"/scale" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getScale returns the scale subresource of the application
+func getScale(experimentsDetails *experimentTypes.ExperimentDetails, resource experimentTypes.ScalableResource, appName string, clients clients.ClientSets) (*unstructured.Unstructured, error) {
+	return clients.DynamicClient.Resource(resource.GVR).Namespace(experimentsDetails.AppNS).Get(appName, metav1.GetOptions{}, "scale")
+}
+
+// scaleApplication updates the replica count of the application via the scale subresource
+func scaleApplication(experimentsDetails *experimentTypes.ExperimentDetails, resource experimentTypes.ScalableResource, appName string, replicas int, clients clients.ClientSets) error {
+
+	// RetryOnConflict uses exponential backoff to avoid exhausting the apiserver
+	return retries.RetryOnConflict(retries.DefaultRetry, func() error {
+		// Retrieve the latest version of the scale subresource before attempting update
+		scale, err := getScale(experimentsDetails, resource, appName, clients)
+		if err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedField(scale.Object, int64(replicas), "spec", "replicas"); err != nil {
+			return err
+		}
+		_, err = clients.DynamicClient.Resource(resource.GVR).Namespace(experimentsDetails.AppNS).Update(scale, metav1.UpdateOptions{}, "scale")
+		return err
+	})
+}
+
+// getReadyReplicas returns the number of ready replicas of the application
+// the pods selected by the scale subresource are counted, except the terminating ones
+func getReadyReplicas(experimentsDetails *experimentTypes.ExperimentDetails, resource experimentTypes.ScalableResource, app experimentTypes.ApplicationUnderTest, clients clients.ClientSets) (int, error) {
+
+	podList, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.AppNS).List(metav1.ListOptions{LabelSelector: app.PodSelector})
+	if err != nil {
+		return 0, err
+	}
+	ready := 0
+	for _, pod := range podList.Items {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == apiv1.PodReady && condition.Status == apiv1.ConditionTrue {
+				ready++
+			}
+		}
+	}
+	return ready, nil
+}
@@ -1,13 +1,11 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"os/signal"
 	"strconv"
-	"strings"
 	"syscall"
 	"time"
 
@@ -15,11 +13,12 @@ import (
 	"github.com/litmuschaos/litmus-go/pkg/events"
 	experimentEnv "github.com/litmuschaos/litmus-go/pkg/generic/pod-dns-chaos/environment"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/pod-dns-chaos/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/litmuschaos/litmus-go/pkg/result"
 	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
 	"github.com/pkg/errors"
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	clientTypes "k8s.io/apimachinery/pkg/types"
 )
 
@@ -61,6 +60,7 @@ func main() {
 
 	err := PreparePodDNSChaos(&experimentsDetails, client, &eventsDetails, &chaosDetails, &resultDetails)
 	if err != nil {
+		helper.WriteTerminationMessage(err)
 		log.Fatalf("helper pod failed, err: %v", err)
 	}
 
@@ -69,12 +69,12 @@ func main() {
 //PreparePodDNSChaos contains the preparation steps before chaos injection
 func PreparePodDNSChaos(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails, resultDetails *types.ResultDetails) error {
 
-	containerID, err := GetContainerID(experimentsDetails, clients)
+	containerID, err := common.GetContainerID(experimentsDetails.AppNS, experimentsDetails.TargetPods, experimentsDetails.TargetContainer, experimentsDetails.ContainerRuntime, experimentsDetails.SocketPath, clients)
 	if err != nil {
 		return err
 	}
 	// extract out the pid of the target container
-	pid, err := GetPID(experimentsDetails, containerID)
+	pid, err := common.GetPID(experimentsDetails.ContainerRuntime, containerID, experimentsDetails.SocketPath)
 	if err != nil {
 		return err
 	}
@@ -99,8 +99,10 @@ func PreparePodDNSChaos(experimentsDetails *experimentTypes.ExperimentDetails, c
 		case <-injectAbort:
 			log.Info("[Chaos]: Abort received, skipping chaos injection")
 		default:
+			helper.RecordInjected("dns " + experimentsDetails.ChaosType + " chaos for [" + experimentsDetails.TargetHostNames + "] host names in " + strconv.Itoa(pid) + " pid")
 			err = cmd.Run()
 			if err != nil {
+				helper.WriteTerminationMessage(errors.Errorf("dns interceptor failed, err: %v", err))
 				log.Fatalf("dns interceptor failed : %v", err)
 			}
 		}
@@ -133,6 +135,7 @@ func PreparePodDNSChaos(experimentsDetails *experimentTypes.ExperimentDetails, c
 				log.Errorf("unable to kill dns interceptor process cry, err :%v", err)
 			} else {
 				log.Errorf("dns interceptor process stopped")
+				helper.RecordReverted("stopped the dns interceptor")
 				break
 			}
 		}
@@ -143,170 +146,6 @@ func PreparePodDNSChaos(experimentsDetails *experimentTypes.ExperimentDetails, c
 	return nil
 }
 
-//GetContainerID extract out the container id of the target container
-func GetContainerID(experimentDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (string, error) {
-
-	var containerID string
-	switch experimentDetails.ContainerRuntime {
-	case "docker":
-		host := "unix://" + experimentDetails.SocketPath
-		// deriving the container id of the pause container
-		cmd := "sudo docker --host " + host + " ps | grep k8s_POD_" + experimentDetails.TargetPods + "_" + experimentDetails.AppNS + " | awk '{print $1}'"
-		out, err := exec.Command("/bin/sh", "-c", cmd).CombinedOutput()
-		if err != nil {
-			log.Error(fmt.Sprintf("[docker]: Failed to run docker ps command: %s", string(out)))
-			return "", err
-		}
-		containerID = strings.TrimSpace(string(out))
-	case "containerd", "crio":
-		pod, err := clients.KubeClient.CoreV1().Pods(experimentDetails.AppNS).Get(experimentDetails.TargetPods, v1.GetOptions{})
-		if err != nil {
-			return "", err
-		}
-		// filtering out the container id from the details of containers inside containerStatuses of the given pod
-		// container id is present in the form of <runtime>://<container-id>
-		for _, container := range pod.Status.ContainerStatuses {
-			if container.Name == experimentDetails.TargetContainer {
-				containerID = strings.Split(container.ContainerID, "//")[1]
-				break
-			}
-		}
-	default:
-		return "", errors.Errorf("%v container runtime not suported", experimentDetails.ContainerRuntime)
-	}
-	log.Infof("containerid: %v", containerID)
-
-	return containerID, nil
-}
-
-//GetPID extract out the PID of the target container
-func GetPID(experimentDetails *experimentTypes.ExperimentDetails, containerID string) (int, error) {
-	var PID int
-
-	switch experimentDetails.ContainerRuntime {
-	case "docker":
-		host := "unix://" + experimentDetails.SocketPath
-		// deriving pid from the inspect out of target container
-		out, err := exec.Command("sudo", "docker", "--host", host, "inspect", containerID).CombinedOutput()
-		if err != nil {
-			log.Error(fmt.Sprintf("[docker]: Failed to run docker inspect: %s", string(out)))
-			return 0, err
-		}
-		// parsing data from the json output of inspect command
-		PID, err = parsePIDFromJSON(out, experimentDetails.ContainerRuntime)
-		if err != nil {
-			log.Error(fmt.Sprintf("[docker]: Failed to parse json from docker inspect output: %s", string(out)))
-			return 0, err
-		}
-	case "containerd", "crio":
-		// deriving pid from the inspect out of target container
-		endpoint := "unix://" + experimentDetails.SocketPath
-		out, err := exec.Command("sudo", "crictl", "-i", endpoint, "-r", endpoint, "inspect", containerID).CombinedOutput()
-		if err != nil {
-			log.Error(fmt.Sprintf("[cri]: Failed to run crictl: %s", string(out)))
-			return 0, err
-		}
-		// parsing data from the json output of inspect command
-		PID, err = parsePIDFromJSON(out, experimentDetails.ContainerRuntime)
-		if err != nil {
-			log.Errorf(fmt.Sprintf("[cri]: Failed to parse json from crictl output: %s", string(out)))
-			return 0, err
-		}
-	default:
-		return 0, errors.Errorf("%v container runtime not suported", experimentDetails.ContainerRuntime)
-	}
-
-	log.Info(fmt.Sprintf("[cri]: Container ID=%s has process PID=%d", containerID, PID))
-
-	return PID, nil
-}
-
-// CrictlInspectResponse JSON representation of crictl inspect command output
-// in crio, pid is present inside pid attribute of inspect output
-// in containerd, pid is present inside `info.pid` of inspect output
-type CrictlInspectResponse struct {
-	Info InfoDetails `json:"info"`
-}
-
-// InfoDetails JSON representation of crictl inspect command output
-type InfoDetails struct {
-	RuntimeSpec RuntimeDetails `json:"runtimeSpec"`
-	PID         int            `json:"pid"`
-}
-
-// RuntimeDetails contains runtime details
-type RuntimeDetails struct {
-	Linux LinuxAttributes `json:"linux"`
-}
-
-// LinuxAttributes contains all the linux attributes
-type LinuxAttributes struct {
-	Namespaces []Namespace `json:"namespaces"`
-}
-
-// Namespace contains linux namespace details
-type Namespace struct {
-	Type string `json:"type"`
-	Path string `json:"path"`
-}
-
-// DockerInspectResponse JSON representation of docker inspect command output
-type DockerInspectResponse struct {
-	State StateDetails `json:"state"`
-}
-
-// StateDetails JSON representation of docker inspect command output
-type StateDetails struct {
-	PID int `json:"pid"`
-}
-
-//parsePIDFromJSON extract the pid from the json output
-func parsePIDFromJSON(j []byte, runtime string) (int, error) {
-	var pid int
-	// namespaces are present inside `info.runtimeSpec.linux.namespaces` of inspect output
-	// linux namespace of type network contains pid, in the form of `/proc/<pid>/ns/net`
-	switch runtime {
-	case "docker":
-		// in docker, pid is present inside state.pid attribute of inspect output
-		var resp []DockerInspectResponse
-		if err := json.Unmarshal(j, &resp); err != nil {
-			return 0, err
-		}
-		pid = resp[0].State.PID
-	case "containerd":
-		var resp CrictlInspectResponse
-		if err := json.Unmarshal(j, &resp); err != nil {
-			return 0, err
-		}
-		for _, namespace := range resp.Info.RuntimeSpec.Linux.Namespaces {
-			if namespace.Type == "network" {
-				value := strings.Split(namespace.Path, "/")[2]
-				pid, _ = strconv.Atoi(value)
-			}
-		}
-	case "crio":
-		var info InfoDetails
-		if err := json.Unmarshal(j, &info); err != nil {
-			return 0, err
-		}
-		pid = info.PID
-		if pid == 0 {
-			var resp CrictlInspectResponse
-			if err := json.Unmarshal(j, &resp); err != nil {
-				return 0, err
-			}
-			pid = resp.Info.PID
-		}
-	default:
-		return 0, errors.Errorf("[cri]: No supported container runtime, runtime: %v", runtime)
-	}
-	if pid == 0 {
-		return 0, errors.Errorf("[cri]: No running target container found, pid: %d", pid)
-	}
-
-	return pid, nil
-}
-
 //GetENV fetches all the env variables from the runner pod
 func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
 	experimentDetails.ExperimentName = Getenv("EXPERIMENT_NAME", "")
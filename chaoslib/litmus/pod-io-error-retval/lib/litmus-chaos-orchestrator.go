@@ -187,7 +187,7 @@ observeLoop:
 			c.abortChaos(pods)
 
 		case <-c.endTime:
-			log.Infof("[Chaos]: Tims is up for experiment",
+			log.Infof("[Chaos]: Time is up for experiment: %v",
 				c.exp.ExperimentDetails.ExperimentName)
 			break observeLoop
 		}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// layout of the page injected inside the target process
+// the deltas and the mask of the skewed clocks are followed by the code of the fake vdso functions
+const (
+	secDeltaOffset  = 0
+	nsecDeltaOffset = 8
+	clockMaskOffset = 16
+	usecDeltaOffset = 24
+	codeOffset      = 32
+)
+
+// assembler is a minimal x86-64 assembler, which resolves the short jumps and the rip relative addressing
+// of the fake vdso functions
+type assembler struct {
+	code   []byte
+	labels map[string]int
+	jumps  map[int]string
+}
+
+// pc returns the offset of the next instruction inside the page
+func (a *assembler) pc() int {
+	return codeOffset + len(a.code)
+}
+
+func (a *assembler) emit(b ...byte) {
+	a.code = append(a.code, b...)
+}
+
+func (a *assembler) label(name string) {
+	a.labels[name] = a.pc()
+}
+
+// jump emits the short jump with the given opcode, the displacement is resolved once all the labels are known
+func (a *assembler) jump(opcode byte, label string) {
+	a.emit(opcode, 0)
+	a.jumps[len(a.code)-1] = label
+}
+
+// ripRelative emits the instruction, whose memory operand is the data at the given offset of the page
+func (a *assembler) ripRelative(offset int, instruction ...byte) {
+	a.emit(instruction...)
+	disp := make([]byte, 4)
+	binary.LittleEndian.PutUint32(disp, uint32(int32(offset-(a.pc()+4))))
+	a.emit(disp...)
+}
+
+// imm32 emits the instruction followed by the 32 bit immediate value
+func (a *assembler) imm32(value uint32, instruction ...byte) {
+	a.emit(instruction...)
+	imm := make([]byte, 4)
+	binary.LittleEndian.PutUint32(imm, value)
+	a.emit(imm...)
+}
+
+// resolve fills the displacements of all the short jumps
+func (a *assembler) resolve() error {
+	for pos, label := range a.jumps {
+		target, ok := a.labels[label]
+		if !ok {
+			return errors.Errorf("undefined label %v", label)
+		}
+		rel := target - (codeOffset + pos + 1)
+		if rel < -128 || rel > 127 {
+			return errors.Errorf("label %v is out of range of the short jump", label)
+		}
+		a.code[pos] = byte(int8(rel))
+	}
+	return nil
+}
+
+// buildPage derive the page injected inside the target process, along with the offsets of the fake vdso functions
+// the fake functions call the actual syscall and add the offset to the result, if the clock is skewed
+func buildPage(offset time.Duration, clockMask uint64) ([]byte, map[string]int, error) {
+
+	a := &assembler{labels: map[string]int{}, jumps: map[int]string{}}
+	entries := map[string]int{}
+
+	// clock_gettime(clockid_t rdi, struct timespec *rsi)
+	entries["__vdso_clock_gettime"] = a.pc()
+	a.imm32(228, 0xb8)                               // mov eax, SYS_clock_gettime
+	a.emit(0x0f, 0x05)                               // syscall
+	a.emit(0x48, 0x85, 0xc0)                         // test rax, rax
+	a.jump(0x75, "cgt_done")                         // jnz cgt_done
+	a.emit(0x83, 0xff, 0x3f)                         // cmp edi, 63
+	a.jump(0x77, "cgt_done")                         // ja cgt_done
+	a.ripRelative(clockMaskOffset, 0x48, 0x8b, 0x05) // mov rax, [mask]
+	a.emit(0x48, 0x0f, 0xa3, 0xf8)                   // bt rax, rdi
+	a.jump(0x73, "cgt_zero")                         // jnc cgt_zero
+	a.emit(0x48, 0x8b, 0x06)                         // mov rax, [rsi]
+	a.ripRelative(secDeltaOffset, 0x48, 0x03, 0x05)  // add rax, [sec]
+	a.emit(0x48, 0x8b, 0x4e, 0x08)                   // mov rcx, [rsi+8]
+	a.ripRelative(nsecDeltaOffset, 0x48, 0x03, 0x0d) // add rcx, [nsec]
+	a.imm32(uint32(time.Second), 0x48, 0x81, 0xf9)   // cmp rcx, 1e9
+	a.jump(0x7c, "cgt_negative")                     // jl cgt_negative
+	a.imm32(uint32(time.Second), 0x48, 0x81, 0xe9)   // sub rcx, 1e9
+	a.emit(0x48, 0xff, 0xc0)                         // inc rax
+	a.jump(0xeb, "cgt_store")                        // jmp cgt_store
+	a.label("cgt_negative")
+	a.emit(0x48, 0x85, 0xc9)                       // test rcx, rcx
+	a.jump(0x79, "cgt_store")                      // jns cgt_store
+	a.imm32(uint32(time.Second), 0x48, 0x81, 0xc1) // add rcx, 1e9
+	a.emit(0x48, 0xff, 0xc8)                       // dec rax
+	a.label("cgt_store")
+	a.emit(0x48, 0x89, 0x06)       // mov [rsi], rax
+	a.emit(0x48, 0x89, 0x4e, 0x08) // mov [rsi+8], rcx
+	a.label("cgt_zero")
+	a.emit(0x31, 0xc0) // xor eax, eax
+	a.label("cgt_done")
+	a.emit(0xc3) // ret
+
+	// gettimeofday(struct timeval *rdi, struct timezone *rsi)
+	entries["__vdso_gettimeofday"] = a.pc()
+	a.imm32(96, 0xb8)                                               // mov eax, SYS_gettimeofday
+	a.emit(0x0f, 0x05)                                              // syscall
+	a.emit(0x48, 0x85, 0xc0)                                        // test rax, rax
+	a.jump(0x75, "gtod_done")                                       // jnz gtod_done
+	a.emit(0x48, 0x85, 0xff)                                        // test rdi, rdi
+	a.jump(0x74, "gtod_zero")                                       // jz gtod_zero
+	a.ripRelative(clockMaskOffset, 0x48, 0x8b, 0x05)                // mov rax, [mask]
+	a.emit(0xa8, 0x01)                                              // test al, 1<<CLOCK_REALTIME
+	a.jump(0x74, "gtod_zero")                                       // jz gtod_zero
+	a.emit(0x48, 0x8b, 0x07)                                        // mov rax, [rdi]
+	a.ripRelative(secDeltaOffset, 0x48, 0x03, 0x05)                 // add rax, [sec]
+	a.emit(0x48, 0x8b, 0x4f, 0x08)                                  // mov rcx, [rdi+8]
+	a.ripRelative(usecDeltaOffset, 0x48, 0x03, 0x0d)                // add rcx, [usec]
+	a.imm32(uint32(time.Second/time.Microsecond), 0x48, 0x81, 0xf9) // cmp rcx, 1e6
+	a.jump(0x7c, "gtod_negative")                                   // jl gtod_negative
+	a.imm32(uint32(time.Second/time.Microsecond), 0x48, 0x81, 0xe9) // sub rcx, 1e6
+	a.emit(0x48, 0xff, 0xc0)                                        // inc rax
+	a.jump(0xeb, "gtod_store")                                      // jmp gtod_store
+	a.label("gtod_negative")
+	a.emit(0x48, 0x85, 0xc9)                                        // test rcx, rcx
+	a.jump(0x79, "gtod_store")                                      // jns gtod_store
+	a.imm32(uint32(time.Second/time.Microsecond), 0x48, 0x81, 0xc1) // add rcx, 1e6
+	a.emit(0x48, 0xff, 0xc8)                                        // dec rax
+	a.label("gtod_store")
+	a.emit(0x48, 0x89, 0x07)       // mov [rdi], rax
+	a.emit(0x48, 0x89, 0x4f, 0x08) // mov [rdi+8], rcx
+	a.label("gtod_zero")
+	a.emit(0x31, 0xc0) // xor eax, eax
+	a.label("gtod_done")
+	a.emit(0xc3) // ret
+
+	// time(time_t *rdi)
+	entries["__vdso_time"] = a.pc()
+	a.emit(0x57)                                     // push rdi
+	a.emit(0x31, 0xff)                               // xor edi, edi
+	a.imm32(201, 0xb8)                               // mov eax, SYS_time
+	a.emit(0x0f, 0x05)                               // syscall
+	a.emit(0x5f)                                     // pop rdi
+	a.ripRelative(clockMaskOffset, 0x48, 0x8b, 0x0d) // mov rcx, [mask]
+	a.emit(0xf6, 0xc1, 0x01)                         // test cl, 1<<CLOCK_REALTIME
+	a.jump(0x74, "time_store")                       // jz time_store
+	a.ripRelative(secDeltaOffset, 0x48, 0x03, 0x05)  // add rax, [sec]
+	a.label("time_store")
+	a.emit(0x48, 0x85, 0xff)  // test rdi, rdi
+	a.jump(0x74, "time_done") // jz time_done
+	a.emit(0x48, 0x89, 0x07)  // mov [rdi], rax
+	a.label("time_done")
+	a.emit(0xc3) // ret
+
+	if err := a.resolve(); err != nil {
+		return nil, nil, err
+	}
+
+	// the nanoseconds delta is kept positive, so that the normalisation needs a single carry
+	sec := int64(offset / time.Second)
+	nsec := int64(offset % time.Second)
+	if nsec < 0 {
+		sec--
+		nsec += int64(time.Second)
+	}
+
+	page := make([]byte, codeOffset, codeOffset+len(a.code))
+	binary.LittleEndian.PutUint64(page[secDeltaOffset:], uint64(sec))
+	binary.LittleEndian.PutUint64(page[nsecDeltaOffset:], uint64(nsec))
+	binary.LittleEndian.PutUint64(page[clockMaskOffset:], clockMask)
+	binary.LittleEndian.PutUint64(page[usecDeltaOffset:], uint64(nsec/int64(time.Microsecond)))
+	page = append(page, a.code...)
+
+	return page, entries, nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+	"time"
+)
+
+// fakePage is the page built for an offset of -90m1.5s, skewing the CLOCK_REALTIME only
+const fakePage = "e6eaffffffffffff0065cd1d00000000010000000000000020a1070000000000" +
+	// __vdso_clock_gettime
+	"b8e40000000f054885c0755483ff3f774f488b05d8ffffff480fa3f87340488b06480305b8ffffff488b4e0848030db5ffffff" +
+	"4881f900ca9a3b7c0c4881e900ca9a3b48ffc0eb0f4885c9790a4881c100ca9a3b48ffc848890648894e0831c0c3" +
+	// __vdso_gettimeofday
+	"b8600000000f054885c075524885ff744b488b0577ffffffa8017440488b0748030559ffffff488b4f0848030d66ffffff" +
+	"4881f940420f007c0c4881e940420f0048ffc0eb0f4885c9790a4881c140420f0048ffc848890748894f0831c0c3" +
+	// __vdso_time
+	"5731ffb8c90000000f055f488b0d1efffffff6c101740748030502ffffff4885ff7403488907c3"
+
+func TestBuildPage(t *testing.T) {
+
+	page, entries, err := buildPage(-90*time.Minute-1500*time.Millisecond, 1)
+	if err != nil {
+		t.Fatalf("unable to build the page, err: %v", err)
+	}
+	if got := hex.EncodeToString(page); got != fakePage {
+		t.Errorf("unexpected page\ngot:  %v\nwant: %v", got, fakePage)
+	}
+
+	wantEntries := map[string]int{
+		"__vdso_clock_gettime": 32,
+		"__vdso_gettimeofday":  129,
+		"__vdso_time":          224,
+	}
+	for name, want := range wantEntries {
+		if entries[name] != want {
+			t.Errorf("%v entry: got %v, want %v", name, entries[name], want)
+		}
+	}
+	if len(entries) != len(wantEntries) {
+		t.Errorf("unexpected entries %v", entries)
+	}
+}
+
+func TestBuildPageDeltas(t *testing.T) {
+
+	tests := []struct {
+		offset   time.Duration
+		sec      int64
+		nsec     int64
+		usec     int64
+		mask     uint64
+		testName string
+	}{
+		{offset: 0, sec: 0, nsec: 0, usec: 0, mask: 1, testName: "zero offset"},
+		{offset: 10 * time.Minute, sec: 600, nsec: 0, usec: 0, mask: 1 << 1, testName: "whole seconds"},
+		{offset: 2*time.Second + 1500*time.Nanosecond, sec: 2, nsec: 1500, usec: 1, mask: 1<<0 | 1<<7, testName: "positive fraction"},
+		{offset: -10 * time.Minute, sec: -600, nsec: 0, usec: 0, mask: 1, testName: "negative whole seconds"},
+		{offset: -1500 * time.Millisecond, sec: -2, nsec: 500000000, usec: 500000, mask: 1 << 11, testName: "negative fraction"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			page, _, err := buildPage(tt.offset, tt.mask)
+			if err != nil {
+				t.Fatalf("unable to build the page, err: %v", err)
+			}
+			if got := int64(binary.LittleEndian.Uint64(page[secDeltaOffset:])); got != tt.sec {
+				t.Errorf("seconds delta: got %v, want %v", got, tt.sec)
+			}
+			if got := int64(binary.LittleEndian.Uint64(page[nsecDeltaOffset:])); got != tt.nsec {
+				t.Errorf("nanoseconds delta: got %v, want %v", got, tt.nsec)
+			}
+			if got := int64(binary.LittleEndian.Uint64(page[usecDeltaOffset:])); got != tt.usec {
+				t.Errorf("microseconds delta: got %v, want %v", got, tt.usec)
+			}
+			if got := binary.LittleEndian.Uint64(page[clockMaskOffset:]); got != tt.mask {
+				t.Errorf("clock mask: got %v, want %v", got, tt.mask)
+			}
+			// the code does not depend on the offset and the mask
+			if got, want := hex.EncodeToString(page[codeOffset:]), fakePage[2*codeOffset:]; got != want {
+				t.Errorf("unexpected code\ngot:  %v\nwant: %v", got, want)
+			}
+		})
+	}
+}
+
+func TestAssemblerRipRelative(t *testing.T) {
+
+	a := &assembler{labels: map[string]int{}, jumps: map[int]string{}}
+	a.emit(0x90)                                     // nop
+	a.ripRelative(clockMaskOffset, 0x48, 0x8b, 0x05) // mov rax, [mask]
+
+	// the displacement is relative to the end of the instruction, which ends at codeOffset+8
+	want := []byte{0x90, 0x48, 0x8b, 0x05, 0xe8, 0xff, 0xff, 0xff}
+	if got := hex.EncodeToString(a.code); got != hex.EncodeToString(want) {
+		t.Errorf("got %v, want %x", got, want)
+	}
+}
+
+func TestAssemblerResolve(t *testing.T) {
+
+	t.Run("backward and forward jumps", func(t *testing.T) {
+		a := &assembler{labels: map[string]int{}, jumps: map[int]string{}}
+		a.label("start")
+		a.jump(0x75, "end")   // jnz end
+		a.jump(0xeb, "start") // jmp start
+		a.label("end")
+		a.emit(0xc3) // ret
+		if err := a.resolve(); err != nil {
+			t.Fatalf("unable to resolve the jumps, err: %v", err)
+		}
+		want := []byte{0x75, 0x02, 0xeb, 0xfc, 0xc3}
+		if got := hex.EncodeToString(a.code); got != hex.EncodeToString(want) {
+			t.Errorf("got %v, want %x", got, want)
+		}
+	})
+
+	t.Run("undefined label", func(t *testing.T) {
+		a := &assembler{labels: map[string]int{}, jumps: map[int]string{}}
+		a.jump(0xeb, "missing")
+		if err := a.resolve(); err == nil {
+			t.Error("expected an error for the undefined label")
+		}
+	})
+
+	t.Run("out of range", func(t *testing.T) {
+		a := &assembler{labels: map[string]int{}, jumps: map[int]string{}}
+		a.jump(0xeb, "far")
+		a.emit(make([]byte, 128)...)
+		a.label("far")
+		if err := a.resolve(); err == nil {
+			t.Error("expected an error for the label out of range of the short jump")
+		}
+	})
+}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	experimentEnv "github.com/litmuschaos/litmus-go/pkg/generic/pod-time-chaos/environment"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/pod-time-chaos/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/result"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	clientTypes "k8s.io/apimachinery/pkg/types"
+)
+
+// rescanInterval is the interval in seconds, after which the processes spawned inside the target container are skewed
+const rescanInterval = 5
+
+// clockIDs contains the ids of the clocks, which can be skewed
+var clockIDs = map[string]uint{
+	"CLOCK_REALTIME":         0,
+	"CLOCK_MONOTONIC":        1,
+	"CLOCK_MONOTONIC_RAW":    4,
+	"CLOCK_REALTIME_COARSE":  5,
+	"CLOCK_MONOTONIC_COARSE": 6,
+	"CLOCK_BOOTTIME":         7,
+	"CLOCK_TAI":              11,
+}
+
+var abort chan os.Signal
+
+func main() {
+
+	experimentsDetails := experimentTypes.ExperimentDetails{}
+	client := clients.ClientSets{}
+	eventsDetails := types.EventDetails{}
+	chaosDetails := types.ChaosDetails{}
+	resultDetails := types.ResultDetails{}
+
+	// abort channel is used to transmit signal notifications.
+	abort = make(chan os.Signal, 1)
+
+	// Catch and relay certain signal(s) to abort channel.
+	signal.Notify(abort, os.Interrupt, syscall.SIGTERM)
+
+	//Getting kubeConfig and Generate ClientSets
+	if err := client.GenerateClientSetFromKubeConfig(); err != nil {
+		log.Fatalf("Unable to Get the kubeconfig, err: %v", err)
+	}
+
+	//Fetching all the ENV passed for the helper pod
+	log.Info("[PreReq]: Getting the ENV variables")
+	GetENV(&experimentsDetails)
+
+	// Initialise the chaos attributes
+	experimentEnv.InitialiseChaosVariables(&chaosDetails, &experimentsDetails)
+
+	// Initialise Chaos Result Parameters
+	types.SetResultAttributes(&resultDetails, chaosDetails)
+
+	// Set the chaos result uid
+	result.SetResultUID(&resultDetails, client, &chaosDetails)
+
+	if err := PreparePodTimeChaos(&experimentsDetails, client, &eventsDetails, &chaosDetails); err != nil {
+		helper.WriteTerminationMessage(err)
+		log.Fatalf("helper pod failed, err: %v", err)
+	}
+}
+
+//PreparePodTimeChaos contains the preparation steps before chaos injection
+func PreparePodTimeChaos(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	offset, err := time.ParseDuration(experimentsDetails.TimeOffset)
+	if err != nil {
+		return errors.Errorf("unable to parse the time offset, err: %v", err)
+	}
+	clockMask, err := GetClockMask(experimentsDetails.ClockIDs)
+	if err != nil {
+		return err
+	}
+
+	containerID, err := common.GetTargetContainerID(experimentsDetails.AppNS, experimentsDetails.TargetPods, experimentsDetails.TargetContainer, clients)
+	if err != nil {
+		return err
+	}
+	// extract out the pid of the target container
+	pid, err := common.GetContainerPID(experimentsDetails.ContainerRuntime, containerID, experimentsDetails.SocketPath)
+	if err != nil {
+		return err
+	}
+
+	skew, err := newClockSkew(offset, clockMask)
+	if err != nil {
+		return err
+	}
+
+	log.InfoWithValues("[Info]: Details of the clock skew", logrus.Fields{
+		"PodName":       experimentsDetails.TargetPods,
+		"ContainerName": experimentsDetails.TargetContainer,
+		"PID":           pid,
+		"TimeOffset":    offset.String(),
+		"ClockIDs":      experimentsDetails.ClockIDs,
+	})
+
+	// record the event inside chaosengine
+	if experimentsDetails.EngineName != "" {
+		msg := "Injecting " + experimentsDetails.ExperimentName + " chaos on application pod"
+		types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+		events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+	}
+
+	select {
+	case <-abort:
+		// stopping the chaos execution, if abort signal recieved
+		log.Info("[Chaos]: Abort received, skipping chaos injection")
+		helper.ExitAborted()
+	default:
+	}
+
+	// skewing the clock of the main process of the target container
+	if err := skew.inject(pid); err != nil {
+		if revertErr := skew.revert(pid); revertErr != nil {
+			log.Errorf("unable to revert the clock skew, err: %v", revertErr)
+		}
+		return errors.Errorf("unable to skew the clock of the target container, err: %v", err)
+	}
+	helper.RecordInjected("skewed the clock of " + strconv.Itoa(pid) + " pid by " + offset.String())
+	skewed := map[int]bool{pid: true}
+	injectNewProcesses(skew, pid, skewed)
+
+	timeChan := time.Tick(time.Duration(experimentsDetails.ChaosDuration) * time.Second)
+	rescan := time.NewTicker(rescanInterval * time.Second)
+	defer rescan.Stop()
+	log.Infof("[Chaos]: Waiting for %vs", experimentsDetails.ChaosDuration)
+
+	// either wait for abort signal or chaos duration
+	// the processes spawned inside the target container in the meantime are skewed as well
+	func() {
+		for {
+			select {
+			case <-abort:
+				log.Info("[Chaos]: Killing process started because of terminated signal received")
+				return
+			case <-timeChan:
+				log.Info("[Chaos]: Stopping the experiment, chaos duration over")
+				return
+			case <-rescan.C:
+				injectNewProcesses(skew, pid, skewed)
+			}
+		}
+	}()
+
+	log.Info("Chaos Revert Started")
+	var failed []int
+	for process := range skewed {
+		// retry thrice for the chaos revert
+		retry := 3
+		for retry > 0 {
+			if err = skew.revert(process); err == nil {
+				break
+			}
+			log.Errorf("unable to revert the clock skew of %v process, err: %v", process, err)
+			retry--
+			time.Sleep(1 * time.Second)
+		}
+		if retry == 0 {
+			failed = append(failed, process)
+		}
+	}
+	helper.RecordReverted("reverted the clock skew of " + strconv.Itoa(len(skewed)-len(failed)) + " processes")
+	if len(failed) != 0 {
+		return errors.Errorf("unable to revert the clock skew of %v processes", failed)
+	}
+	log.Info("Chaos Revert Completed")
+	return nil
+}
+
+// injectNewProcesses skews the clock of the processes of the target container, which are not skewed yet
+func injectNewProcesses(skew *clockSkew, pid int, skewed map[int]bool) {
+
+	processes, err := GetContainerProcesses(pid)
+	if err != nil {
+		log.Warnf("Unable to list the processes of the target container, err: %v", err)
+		return
+	}
+	for _, process := range processes {
+		if skewed[process] {
+			continue
+		}
+		// the process is recorded before the injection, so that a partial injection is reverted as well
+		skewed[process] = true
+		if err := skew.inject(process); err != nil {
+			log.Warnf("Unable to skew the clock of %v process, err: %v", process, err)
+		}
+	}
+}
+
+//GetContainerProcesses returns all the processes, which belong to the cgroup of the given process
+func GetContainerProcesses(pid int) ([]int, error) {
+
+	cgroup, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := ioutil.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+
+	var processes []int
+	for _, entry := range entries {
+		process, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		processCgroup, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/cgroup", process))
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(processCgroup, cgroup) {
+			processes = append(processes, process)
+		}
+	}
+	return processes, nil
+}
+
+//GetClockMask derive the mask of the skewed clocks from the comma separated clock ids
+func GetClockMask(ids string) (uint64, error) {
+
+	var mask uint64
+	for _, id := range strings.Split(ids, ",") {
+		id = strings.ToUpper(strings.TrimSpace(id))
+		if id == "" {
+			continue
+		}
+		bit, ok := clockIDs[id]
+		if !ok {
+			return 0, errors.Errorf("%v clock is not supported", id)
+		}
+		mask |= 1 << bit
+	}
+	if mask == 0 {
+		return 0, errors.Errorf("no clock provided to skew")
+	}
+	return mask, nil
+}
+
+//GetENV fetches all the env variables from the runner pod
+func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
+	experimentDetails.ExperimentName = Getenv("EXPERIMENT_NAME", "")
+	experimentDetails.AppNS = Getenv("APP_NS", "")
+	experimentDetails.TargetContainer = Getenv("APP_CONTAINER", "")
+	experimentDetails.TargetPods = Getenv("APP_POD", "")
+	experimentDetails.ChaosDuration, _ = strconv.Atoi(Getenv("CHAOS_DURATION", "30"))
+	experimentDetails.ChaosNamespace = Getenv("CHAOS_NAMESPACE", "litmus")
+	experimentDetails.EngineName = Getenv("CHAOS_ENGINE", "")
+	experimentDetails.ChaosUID = clientTypes.UID(Getenv("CHAOS_UID", ""))
+	experimentDetails.ChaosPodName = Getenv("POD_NAME", "")
+	experimentDetails.ContainerRuntime = Getenv("CONTAINER_RUNTIME", "")
+	experimentDetails.SocketPath = Getenv("SOCKET_PATH", "")
+	experimentDetails.TimeOffset = Getenv("TIME_OFFSET", "-10m")
+	experimentDetails.ClockIDs = Getenv("CLOCK_IDS", "CLOCK_REALTIME")
+}
+
+// Getenv fetch the env and set the default value, if any
+func Getenv(key string, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		value = defaultValue
+	}
+	return value
+}
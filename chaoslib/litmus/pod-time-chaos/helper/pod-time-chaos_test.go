@@ -0,0 +1,38 @@
+package main
+
+import "testing"
+
+func TestGetClockMask(t *testing.T) {
+
+	tests := []struct {
+		ids      string
+		want     uint64
+		wantErr  bool
+		testName string
+	}{
+		{ids: "CLOCK_REALTIME", want: 0x1, testName: "realtime"},
+		{ids: "CLOCK_REALTIME,CLOCK_MONOTONIC", want: 0x3, testName: "realtime and monotonic"},
+		{ids: " clock_boottime , CLOCK_TAI,", want: 0x880, testName: "lower case with spaces"},
+		{ids: "CLOCK_MONOTONIC_RAW,CLOCK_REALTIME_COARSE,CLOCK_MONOTONIC_COARSE", want: 0x70, testName: "raw and coarse clocks"},
+		{ids: "CLOCK_REALTIME,CLOCK_REALTIME", want: 0x1, testName: "duplicate clocks"},
+		{ids: "CLOCK_PROCESS_CPUTIME_ID", wantErr: true, testName: "unsupported clock"},
+		{ids: " , ", wantErr: true, testName: "no clock"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			got, err := GetClockMask(tt.ids)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got the mask %#x", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unable to get the clock mask, err: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %#x, want %#x", got, tt.want)
+			}
+		})
+	}
+}
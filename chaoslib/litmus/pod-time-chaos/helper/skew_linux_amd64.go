@@ -0,0 +1,450 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// jumpSize is the size of the `movabs rax, imm64; jmp rax` sequence, which redirects the vdso function to the fake one
+const jumpSize = 12
+
+// maxSteps is the maximum number of single steps, to move a thread out of the patched instructions
+const maxSteps = 64
+
+// clockSkew skews the clocks of the target processes by redirecting their vdso time functions
+// to the fake functions injected inside a new page of the process
+type clockSkew struct {
+	page    []byte
+	entries map[string]int
+	// vdso is the pristine vdso image of the helper, the kernel maps the same image inside all the processes
+	vdso    []byte
+	symbols map[string]uint64
+	// injected contains the start address of the vdso of all the skewed processes
+	injected map[int]uint64
+}
+
+// newClockSkew derive the fake vdso functions for the given offset and the skewed clocks
+func newClockSkew(offset time.Duration, clockMask uint64) (*clockSkew, error) {
+
+	page, entries, err := buildPage(offset, clockMask)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := getVDSORange(os.Getpid())
+	if err != nil {
+		return nil, err
+	}
+	vdso, err := readMemory(os.Getpid(), start, end)
+	if err != nil {
+		return nil, err
+	}
+	symbols, err := getVDSOSymbols(vdso)
+	if err != nil {
+		return nil, err
+	}
+	return &clockSkew{
+		page:     page,
+		entries:  entries,
+		vdso:     vdso,
+		symbols:  symbols,
+		injected: map[int]uint64{},
+	}, nil
+}
+
+// inject stops all the threads of the process, maps the fake functions inside it and patches its vdso
+func (s *clockSkew) inject(pid int) error {
+
+	// all the ptrace requests should come from the thread which attached the tracee
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	start, end, err := getVDSORange(pid)
+	if err != nil {
+		return err
+	}
+	if end-start != uint64(len(s.vdso)) {
+		return errors.Errorf("vdso of %v process is different from the vdso of the helper", pid)
+	}
+
+	threads, err := attachThreads(pid)
+	defer detachThreads(threads)
+	if err != nil {
+		return err
+	}
+
+	address, err := allocatePage(pid, len(s.page))
+	if err != nil {
+		return err
+	}
+	if _, err := syscall.PtracePokeData(pid, uintptr(address), s.page); err != nil {
+		return errors.Errorf("unable to write the fake functions inside %v process, err: %v", pid, err)
+	}
+
+	if err := stepOutOfPatches(threads, s.patchRanges(start)); err != nil {
+		return err
+	}
+	// recorded before the patches, so that a partially patched vdso is also reverted
+	s.injected[pid] = start
+	for name, offset := range s.symbols {
+		jump := make([]byte, jumpSize)
+		jump[0], jump[1] = 0x48, 0xb8
+		binary.LittleEndian.PutUint64(jump[2:], address+uint64(s.entries[name]))
+		jump[10], jump[11] = 0xff, 0xe0
+		if _, err := syscall.PtracePokeData(pid, uintptr(start+offset), jump); err != nil {
+			return errors.Errorf("unable to patch %v of %v process, err: %v", name, pid, err)
+		}
+	}
+
+	log.Infof("[Chaos]: Clock of %v process is skewed", pid)
+	return nil
+}
+
+// revert restores the pristine vdso functions of the process
+// the injected page is left mapped, as a thread may still be returning from the fake functions
+func (s *clockSkew) revert(pid int) error {
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	start, ok := s.injected[pid]
+	if !ok {
+		return nil
+	}
+
+	threads, err := attachThreads(pid)
+	defer detachThreads(threads)
+	if err != nil {
+		if isProcessGone(pid) {
+			delete(s.injected, pid)
+			log.Infof("[Chaos]: %v process is not running anymore, skipping the revert", pid)
+			return nil
+		}
+		return err
+	}
+
+	if err := stepOutOfPatches(threads, s.patchRanges(start)); err != nil {
+		return err
+	}
+	for name, offset := range s.symbols {
+		if _, err := syscall.PtracePokeData(pid, uintptr(start+offset), s.vdso[offset:offset+jumpSize]); err != nil {
+			return errors.Errorf("unable to restore %v of %v process, err: %v", name, pid, err)
+		}
+	}
+	delete(s.injected, pid)
+
+	log.Infof("[Chaos]: Clock of %v process is restored", pid)
+	return nil
+}
+
+// patchRanges returns the address ranges of the patched instructions for the given vdso start address
+func (s *clockSkew) patchRanges(start uint64) [][2]uint64 {
+	var ranges [][2]uint64
+	for _, offset := range s.symbols {
+		ranges = append(ranges, [2]uint64{start + offset, start + offset + jumpSize})
+	}
+	return ranges
+}
+
+// getVDSORange returns the address range of the vdso mapping of the process
+func getVDSORange(pid int) (uint64, uint64, error) {
+
+	file, err := os.Open(fmt.Sprintf("/proc/%d/maps", pid))
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 || fields[5] != "[vdso]" {
+			continue
+		}
+		bounds := strings.Split(fields[0], "-")
+		start, err := strconv.ParseUint(bounds[0], 16, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		end, err := strconv.ParseUint(bounds[1], 16, 64)
+		if err != nil {
+			return 0, 0, err
+		}
+		return start, end, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, 0, err
+	}
+	return 0, 0, errors.Errorf("vdso is not mapped inside %v process", pid)
+}
+
+// readMemory reads the memory of the process in the given address range
+func readMemory(pid int, start, end uint64) ([]byte, error) {
+
+	file, err := os.Open(fmt.Sprintf("/proc/%d/mem", pid))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	buf := make([]byte, end-start)
+	if _, err := file.ReadAt(buf, int64(start)); err != nil {
+		return nil, errors.Errorf("unable to read the memory of %v process, err: %v", pid, err)
+	}
+	return buf, nil
+}
+
+// getVDSOSymbols returns the offsets of the time functions inside the vdso image
+func getVDSOSymbols(vdso []byte) (map[string]uint64, error) {
+
+	file, err := elf.NewFile(bytes.NewReader(vdso))
+	if err != nil {
+		return nil, errors.Errorf("unable to parse the vdso, err: %v", err)
+	}
+	if file.Class != elf.ELFCLASS64 || file.Machine != elf.EM_X86_64 {
+		return nil, errors.Errorf("vdso of %v %v is not supported", file.Class, file.Machine)
+	}
+
+	// symbol values are relative to the load address of the vdso
+	var base uint64
+	for _, prog := range file.Progs {
+		if prog.Type == elf.PT_LOAD {
+			base = prog.Vaddr - prog.Off
+			break
+		}
+	}
+
+	dynamicSymbols, err := file.DynamicSymbols()
+	if err != nil {
+		return nil, errors.Errorf("unable to read the vdso symbols, err: %v", err)
+	}
+
+	// newer kernels define the time functions as a tail jump, padded till the next function
+	// so the space available for the patch is derived from the start of the next function
+	var starts []uint64
+	for _, symbol := range dynamicSymbols {
+		if elf.ST_TYPE(symbol.Info) == elf.STT_FUNC {
+			starts = append(starts, symbol.Value)
+		}
+	}
+	if text := file.Section(".text"); text != nil {
+		starts = append(starts, text.Addr+text.Size)
+	}
+
+	symbols := map[string]uint64{}
+	for _, symbol := range dynamicSymbols {
+		if _, ok := symbols[symbol.Name]; ok || elf.ST_TYPE(symbol.Info) != elf.STT_FUNC {
+			continue
+		}
+		switch symbol.Name {
+		case "__vdso_clock_gettime", "__vdso_gettimeofday", "__vdso_time":
+			if getSlotSize(symbol, starts) < jumpSize {
+				log.Warnf("%v is too small to be patched, skipping it", symbol.Name)
+				continue
+			}
+			symbols[symbol.Name] = symbol.Value - base
+		}
+	}
+	if _, ok := symbols["__vdso_clock_gettime"]; !ok {
+		return nil, errors.Errorf("__vdso_clock_gettime is not present inside the vdso")
+	}
+	return symbols, nil
+}
+
+// getSlotSize returns the space available for the patch, till the start of the next function
+func getSlotSize(symbol elf.Symbol, starts []uint64) uint64 {
+	var next uint64
+	for _, start := range starts {
+		if start > symbol.Value && (next == 0 || start < next) {
+			next = start
+		}
+	}
+	if next == 0 {
+		return symbol.Size
+	}
+	return next - symbol.Value
+}
+
+// attachThreads attaches to all the threads of the process and waits till they are stopped
+// the threads are listed again, till there is no new thread spawned during the attach
+func attachThreads(pid int) ([]int, error) {
+
+	var threads []int
+	attached := map[int]bool{}
+	for {
+		tasks, err := ioutil.ReadDir(fmt.Sprintf("/proc/%d/task", pid))
+		if err != nil {
+			return threads, err
+		}
+		found := false
+		for _, task := range tasks {
+			tid, err := strconv.Atoi(task.Name())
+			if err != nil || attached[tid] {
+				continue
+			}
+			if err := syscall.PtraceAttach(tid); err != nil {
+				if err == syscall.ESRCH {
+					continue
+				}
+				return threads, errors.Errorf("unable to attach to %v thread, err: %v", tid, err)
+			}
+			attached[tid] = true
+			if err := waitForStop(tid, syscall.SIGSTOP); err != nil {
+				if err == syscall.ESRCH {
+					continue
+				}
+				threads = append(threads, tid)
+				return threads, err
+			}
+			threads = append(threads, tid)
+			found = true
+		}
+		if !found {
+			return threads, nil
+		}
+	}
+}
+
+// detachThreads resumes all the stopped threads
+func detachThreads(threads []int) {
+	for _, tid := range threads {
+		if err := syscall.PtraceDetach(tid); err != nil && err != syscall.ESRCH {
+			log.Warnf("unable to detach from %v thread, err: %v", tid, err)
+		}
+	}
+}
+
+// waitForStop waits till the thread is stopped by the given signal
+// any other signal received in between is delivered to the thread
+func waitForStop(tid int, signal syscall.Signal) error {
+	for {
+		var status syscall.WaitStatus
+		if _, err := syscall.Wait4(tid, &status, syscall.WALL, nil); err != nil {
+			return err
+		}
+		switch {
+		case status.Exited(), status.Signaled():
+			return syscall.ESRCH
+		case status.Stopped() && status.StopSignal() == signal:
+			return nil
+		case status.Stopped():
+			if err := syscall.PtraceCont(tid, int(status.StopSignal())); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// singleStep executes the next instruction of the stopped thread
+func singleStep(tid int) error {
+	if err := syscall.PtraceSingleStep(tid); err != nil {
+		return err
+	}
+	var status syscall.WaitStatus
+	if _, err := syscall.Wait4(tid, &status, syscall.WALL, nil); err != nil {
+		return err
+	}
+	if !status.Stopped() || status.StopSignal() != syscall.SIGTRAP {
+		return errors.Errorf("%v thread is not stopped after the single step, status: %v", tid, status)
+	}
+	return nil
+}
+
+// stepOutOfPatches single steps the threads, which are executing inside the patched instructions
+func stepOutOfPatches(threads []int, ranges [][2]uint64) error {
+
+	for _, tid := range threads {
+		for step := 0; ; step++ {
+			var regs syscall.PtraceRegs
+			if err := syscall.PtraceGetRegs(tid, &regs); err != nil {
+				return err
+			}
+			if !inRanges(regs.Rip, ranges) {
+				break
+			}
+			if step == maxSteps {
+				return errors.Errorf("unable to move %v thread out of the vdso functions", tid)
+			}
+			if err := singleStep(tid); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func inRanges(address uint64, ranges [][2]uint64) bool {
+	for _, r := range ranges {
+		if address >= r[0] && address < r[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// allocatePage maps a new anonymous page inside the stopped process, by executing the mmap syscall from its main thread
+// the registers and the instruction overwritten by the syscall are restored afterwards
+func allocatePage(pid, size int) (uint64, error) {
+
+	var saved syscall.PtraceRegs
+	if err := syscall.PtraceGetRegs(pid, &saved); err != nil {
+		return 0, err
+	}
+	instruction := make([]byte, 2)
+	if _, err := syscall.PtracePeekData(pid, uintptr(saved.Rip), instruction); err != nil {
+		return 0, err
+	}
+	if _, err := syscall.PtracePokeData(pid, uintptr(saved.Rip), []byte{0x0f, 0x05}); err != nil {
+		return 0, err
+	}
+
+	regs := saved
+	regs.Rax = syscall.SYS_MMAP
+	regs.Rdi = 0
+	regs.Rsi = uint64((size + os.Getpagesize() - 1) / os.Getpagesize() * os.Getpagesize())
+	regs.Rdx = syscall.PROT_READ | syscall.PROT_EXEC
+	regs.R10 = syscall.MAP_PRIVATE | syscall.MAP_ANONYMOUS
+	regs.R8 = ^uint64(0)
+	regs.R9 = 0
+	// an interrupted syscall of the thread should not be restarted in place of the mmap
+	regs.Orig_rax = ^uint64(0)
+
+	err := syscall.PtraceSetRegs(pid, &regs)
+	if err == nil {
+		if err = singleStep(pid); err == nil {
+			err = syscall.PtraceGetRegs(pid, &regs)
+		}
+	}
+
+	if _, restoreErr := syscall.PtracePokeData(pid, uintptr(saved.Rip), instruction); restoreErr != nil {
+		return 0, errors.Errorf("unable to restore the instruction of %v process, err: %v", pid, restoreErr)
+	}
+	if restoreErr := syscall.PtraceSetRegs(pid, &saved); restoreErr != nil {
+		return 0, errors.Errorf("unable to restore the registers of %v process, err: %v", pid, restoreErr)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if int64(regs.Rax) < 0 && int64(regs.Rax) > -4096 {
+		return 0, errors.Errorf("unable to map the page inside %v process, err: %v", pid, syscall.Errno(-int64(regs.Rax)))
+	}
+	return regs.Rax, nil
+}
+
+// isProcessGone checks whether the process is exited
+func isProcessGone(pid int) bool {
+	_, err := os.Stat(fmt.Sprintf("/proc/%d", pid))
+	return os.IsNotExist(err)
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"debug/elf"
+	"encoding/binary"
+	"os"
+	"testing"
+)
+
+// vdsoSymbol is a function symbol of the fake vdso, its value is relative to the start of the text
+type vdsoSymbol struct {
+	name  string
+	value uint64
+	size  uint64
+}
+
+// buildVDSO builds a minimal 64 bit elf image with a text section and the dynamic symbols of the given functions
+// the image is loaded at the given base address, like the vdso mapped by the kernel
+func buildVDSO(machine elf.Machine, base uint64, textSize uint64, symbols []vdsoSymbol) []byte {
+
+	const (
+		ehdrSize   = 64
+		phdrSize   = 56
+		shdrSize   = 64
+		symSize    = 24
+		textOffset = 0x100
+	)
+
+	dynstr := []byte{0}
+	dynsym := make([]elf.Sym64, 1, len(symbols)+1)
+	for _, symbol := range symbols {
+		dynsym = append(dynsym, elf.Sym64{
+			Name:  uint32(len(dynstr)),
+			Info:  elf.ST_INFO(elf.STB_GLOBAL, elf.STT_FUNC),
+			Shndx: 1,
+			Value: base + textOffset + symbol.value,
+			Size:  symbol.size,
+		})
+		dynstr = append(append(dynstr, symbol.name...), 0)
+	}
+	shstrtab := []byte("\x00.text\x00.dynsym\x00.dynstr\x00.shstrtab\x00")
+
+	dynsymOffset := textOffset + textSize
+	dynstrOffset := dynsymOffset + uint64(len(dynsym)*symSize)
+	shstrtabOffset := dynstrOffset + uint64(len(dynstr))
+	shdrOffset := shstrtabOffset + uint64(len(shstrtab))
+	fileSize := shdrOffset + 5*shdrSize
+
+	buf := &bytes.Buffer{}
+	header := elf.Header64{
+		Type:      uint16(elf.ET_DYN),
+		Machine:   uint16(machine),
+		Version:   uint32(elf.EV_CURRENT),
+		Phoff:     ehdrSize,
+		Shoff:     shdrOffset,
+		Ehsize:    ehdrSize,
+		Phentsize: phdrSize,
+		Phnum:     1,
+		Shentsize: shdrSize,
+		Shnum:     5,
+		Shstrndx:  4,
+	}
+	copy(header.Ident[:], elf.ELFMAG)
+	header.Ident[elf.EI_CLASS] = byte(elf.ELFCLASS64)
+	header.Ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	header.Ident[elf.EI_VERSION] = byte(elf.EV_CURRENT)
+	binary.Write(buf, binary.LittleEndian, header)
+	binary.Write(buf, binary.LittleEndian, elf.Prog64{
+		Type:   uint32(elf.PT_LOAD),
+		Flags:  uint32(elf.PF_R | elf.PF_X),
+		Vaddr:  base,
+		Paddr:  base,
+		Filesz: fileSize,
+		Memsz:  fileSize,
+		Align:  0x1000,
+	})
+
+	// the text is filled with int3, the code itself is not parsed
+	buf.Write(bytes.Repeat([]byte{0}, textOffset-buf.Len()))
+	buf.Write(bytes.Repeat([]byte{0xcc}, int(textSize)))
+	binary.Write(buf, binary.LittleEndian, dynsym)
+	buf.Write(dynstr)
+	buf.Write(shstrtab)
+
+	sections := []elf.Section64{
+		{},
+		{Name: 1, Type: uint32(elf.SHT_PROGBITS), Flags: uint64(elf.SHF_ALLOC | elf.SHF_EXECINSTR), Addr: base + textOffset, Off: textOffset, Size: textSize, Addralign: 16},
+		{Name: 7, Type: uint32(elf.SHT_DYNSYM), Flags: uint64(elf.SHF_ALLOC), Addr: base + dynsymOffset, Off: dynsymOffset, Size: uint64(len(dynsym) * symSize), Link: 3, Info: 1, Addralign: 8, Entsize: symSize},
+		{Name: 15, Type: uint32(elf.SHT_STRTAB), Flags: uint64(elf.SHF_ALLOC), Addr: base + dynstrOffset, Off: dynstrOffset, Size: uint64(len(dynstr)), Addralign: 1},
+		{Name: 23, Type: uint32(elf.SHT_STRTAB), Off: shstrtabOffset, Size: uint64(len(shstrtab)), Addralign: 1},
+	}
+	binary.Write(buf, binary.LittleEndian, sections)
+	return buf.Bytes()
+}
+
+func TestGetVDSOSymbols(t *testing.T) {
+
+	tests := []struct {
+		machine  elf.Machine
+		base     uint64
+		textSize uint64
+		symbols  []vdsoSymbol
+		want     map[string]uint64
+		wantErr  bool
+		testName string
+	}{
+		{
+			machine:  elf.EM_X86_64,
+			base:     0xffffffffff700000,
+			textSize: 0x100,
+			symbols: []vdsoSymbol{
+				{name: "__vdso_clock_gettime", value: 0x00, size: 0x40},
+				{name: "clock_gettime", value: 0x00, size: 0x40},
+				{name: "__vdso_gettimeofday", value: 0x40, size: 0x30},
+				{name: "__vdso_time", value: 0x70, size: 0x20},
+			},
+			want: map[string]uint64{
+				"__vdso_clock_gettime": 0x100,
+				"__vdso_gettimeofday":  0x140,
+				"__vdso_time":          0x170,
+			},
+			testName: "functions with the code inside the symbols",
+		},
+		{
+			machine:  elf.EM_X86_64,
+			base:     0,
+			textSize: 0x40,
+			symbols: []vdsoSymbol{
+				{name: "__vdso_clock_gettime", value: 0x00, size: 0x05},
+				{name: "__vdso_gettimeofday", value: 0x10, size: 0x05},
+				{name: "__vdso_time", value: 0x36, size: 0x05},
+			},
+			// the tail jumps are padded till the next function, the last one is followed by the end of the text
+			want: map[string]uint64{
+				"__vdso_clock_gettime": 0x100,
+				"__vdso_gettimeofday":  0x110,
+			},
+			testName: "tail jumps padded till the next function",
+		},
+		{
+			machine:  elf.EM_X86_64,
+			base:     0,
+			textSize: 0x40,
+			symbols: []vdsoSymbol{
+				{name: "__vdso_gettimeofday", value: 0x00, size: 0x20},
+				{name: "__vdso_time", value: 0x20, size: 0x20},
+			},
+			wantErr:  true,
+			testName: "clock_gettime is not present",
+		},
+		{
+			machine:  elf.EM_AARCH64,
+			base:     0,
+			textSize: 0x40,
+			symbols: []vdsoSymbol{
+				{name: "__kernel_clock_gettime", value: 0x00, size: 0x40},
+			},
+			wantErr:  true,
+			testName: "unsupported machine",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			symbols, err := getVDSOSymbols(buildVDSO(tt.machine, tt.base, tt.textSize, tt.symbols))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got the symbols %v", symbols)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unable to get the vdso symbols, err: %v", err)
+			}
+			if len(symbols) != len(tt.want) {
+				t.Errorf("got %v, want %v", symbols, tt.want)
+			}
+			for name, want := range tt.want {
+				if got, ok := symbols[name]; !ok || got != want {
+					t.Errorf("%v: got %#x, want %#x", name, got, want)
+				}
+			}
+		})
+	}
+
+	t.Run("not an elf image", func(t *testing.T) {
+		if _, err := getVDSOSymbols([]byte("not a vdso")); err == nil {
+			t.Error("expected an error for the invalid image")
+		}
+	})
+}
+
+func TestGetVDSOSymbolsOfProcess(t *testing.T) {
+
+	start, end, err := getVDSORange(os.Getpid())
+	if err != nil {
+		t.Skipf("vdso is not available, err: %v", err)
+	}
+	vdso, err := readMemory(os.Getpid(), start, end)
+	if err != nil {
+		t.Skipf("unable to read the vdso, err: %v", err)
+	}
+	symbols, err := getVDSOSymbols(vdso)
+	if err != nil {
+		t.Fatalf("unable to get the vdso symbols, err: %v", err)
+	}
+	for name, offset := range symbols {
+		if offset+jumpSize > uint64(len(vdso)) {
+			t.Errorf("%v offset %#x is outside the %#x bytes of the vdso", name, offset, len(vdso))
+		}
+	}
+}
@@ -0,0 +1,25 @@
+//go:build !linux || !amd64
+// +build !linux !amd64
+
+package main
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// clockSkew is only supported on the linux/amd64 nodes
+type clockSkew struct{}
+
+func newClockSkew(offset time.Duration, clockMask uint64) (*clockSkew, error) {
+	return nil, errors.Errorf("pod-time-chaos is only supported on linux/amd64 nodes")
+}
+
+func (s *clockSkew) inject(pid int) error {
+	return errors.Errorf("pod-time-chaos is only supported on linux/amd64 nodes")
+}
+
+func (s *clockSkew) revert(pid int) error {
+	return nil
+}
@@ -0,0 +1,258 @@
+package lib
+
+import (
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/pod-time-chaos/types"
+	"github.com/litmuschaos/litmus-go/pkg/helper"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/probe"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"strconv"
+)
+
+var err error
+
+//PrepareAndInjectChaos contains the preparation & injection steps
+func PrepareAndInjectChaos(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	// Get the target pod details for the chaos execution
+	// if the target pod is not defined it will derive the random target pod list using pod affected percentage
+	if experimentsDetails.TargetPods == "" && chaosDetails.AppDetail.Label == "" {
+		return errors.Errorf("Please provide one of the appLabel or TARGET_PODS")
+	}
+	targetPodList, err := common.GetPodList(experimentsDetails.TargetPods, experimentsDetails.PodsAffectedPerc, clients, chaosDetails)
+	if err != nil {
+		return err
+	}
+
+	podNames := []string{}
+	for _, pod := range targetPodList.Items {
+		podNames = append(podNames, pod.Name)
+	}
+	log.Infof("Target pods list for chaos, %v", podNames)
+
+	//Waiting for the ramp time before chaos injection
+	if experimentsDetails.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time before injecting chaos", experimentsDetails.RampTime)
+		common.WaitForDuration(experimentsDetails.RampTime)
+	}
+
+	// Getting the serviceAccountName, need permission inside helper pod to create the events
+	if experimentsDetails.ChaosServiceAccount == "" {
+		err = GetServiceAccount(experimentsDetails, clients)
+		if err != nil {
+			return errors.Errorf("Unable to get the serviceAccountName, err: %v", err)
+		}
+	}
+
+	//Get the target container name of the application pod
+	if experimentsDetails.TargetContainer == "" {
+		experimentsDetails.TargetContainer, err = GetTargetContainer(experimentsDetails, targetPodList.Items[0].Name, clients)
+		if err != nil {
+			return errors.Errorf("Unable to get the target container name, err: %v", err)
+		}
+	}
+
+	if experimentsDetails.EngineName != "" {
+		// Get Chaos Pod Annotation
+		experimentsDetails.Annotations, err = common.GetChaosPodAnnotation(experimentsDetails.ChaosPodName, experimentsDetails.ChaosNamespace, clients)
+		if err != nil {
+			return errors.Errorf("unable to get annotations, err: %v", err)
+		}
+		// Get Resource Requirements
+		experimentsDetails.Resources, err = common.GetChaosPodResourceRequirements(experimentsDetails.ChaosPodName, experimentsDetails.ExperimentName, experimentsDetails.ChaosNamespace, clients)
+		if err != nil {
+			return errors.Errorf("Unable to get resource requirements, err: %v", err)
+		}
+		// Get ImagePullSecrets
+		experimentsDetails.ImagePullSecrets, err = common.GetImagePullSecrets(experimentsDetails.ChaosPodName, experimentsDetails.ChaosNamespace, clients)
+		if err != nil {
+			return errors.Errorf("Unable to get imagePullSecrets, err: %v", err)
+		}
+	}
+
+	if experimentsDetails.Sequence == "serial" {
+		if err = InjectChaosInSerialMode(experimentsDetails, targetPodList, clients, chaosDetails, resultDetails, eventsDetails); err != nil {
+			return err
+		}
+	} else {
+		if err = InjectChaosInParallelMode(experimentsDetails, targetPodList, clients, chaosDetails, resultDetails, eventsDetails); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InjectChaosInSerialMode inject the time chaos in all target application serially (one by one)
+func InjectChaosInSerialMode(experimentsDetails *experimentTypes.ExperimentDetails, targetPodList apiv1.PodList, clients clients.ClientSets, chaosDetails *types.ChaosDetails, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails) error {
+
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
+
+	// run the probes during chaos
+	if len(resultDetails.ProbeDetails) != 0 {
+		if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+			return err
+		}
+	}
+
+	// creating the helper pod to perform time chaos
+	for _, pod := range targetPodList.Items {
+
+		log.InfoWithValues("[Info]: Details of application under chaos injection", logrus.Fields{
+			"PodName":       pod.Name,
+			"NodeName":      pod.Spec.NodeName,
+			"ContainerName": experimentsDetails.TargetContainer,
+		})
+		if err = CreateHelperPod(experimentsDetails, manager, pod.Name, pod.Spec.NodeName); err != nil {
+			return err
+		}
+
+		//checking the status of the helper pods, wait till the pod comes to running state else fail the experiment
+		if err := manager.WaitForRunning(); err != nil {
+			manager.Cleanup()
+			return err
+		}
+		manager.StreamLogs()
+
+		// Wait till the completion of the helper pod
+		// set an upper limit for the waiting time
+		log.Info("[Wait]: waiting till the completion of the helper pod")
+		if err := manager.WaitForCompletion(experimentsDetails.ChaosDuration + 60); err != nil {
+			manager.Cleanup()
+			return err
+		}
+
+		//Deleting the helper pod
+		if err := manager.Delete(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InjectChaosInParallelMode inject the time chaos in all target application in parallel mode (all at once)
+func InjectChaosInParallelMode(experimentsDetails *experimentTypes.ExperimentDetails, targetPodList apiv1.PodList, clients clients.ClientSets, chaosDetails *types.ChaosDetails, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails) error {
+
+	manager := GetHelperManager(experimentsDetails, clients, chaosDetails)
+
+	// run the probes during chaos
+	if len(resultDetails.ProbeDetails) != 0 {
+		if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+			return err
+		}
+	}
+
+	// creating the helper pod to perform time chaos
+	for _, pod := range targetPodList.Items {
+
+		log.InfoWithValues("[Info]: Details of application under chaos injection", logrus.Fields{
+			"PodName":       pod.Name,
+			"NodeName":      pod.Spec.NodeName,
+			"ContainerName": experimentsDetails.TargetContainer,
+		})
+		if err = CreateHelperPod(experimentsDetails, manager, pod.Name, pod.Spec.NodeName); err != nil {
+			return err
+		}
+	}
+
+	//checking the status of the helper pods, wait till the pod comes to running state else fail the experiment
+	if err := manager.WaitForRunning(); err != nil {
+		manager.Cleanup()
+		return err
+	}
+	manager.StreamLogs()
+
+	// Wait till the completion of the helper pod
+	// set an upper limit for the waiting time
+	log.Info("[Wait]: waiting till the completion of the helper pod")
+	if err := manager.WaitForCompletion(experimentsDetails.ChaosDuration + 60); err != nil {
+		manager.Cleanup()
+		return err
+	}
+
+	//Deleting the helper pod
+	return manager.Delete()
+}
+
+// GetServiceAccount find the serviceAccountName for the helper pod
+func GetServiceAccount(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) error {
+	pod, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaosNamespace).Get(experimentsDetails.ChaosPodName, v1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	experimentsDetails.ChaosServiceAccount = pod.Spec.ServiceAccountName
+	return nil
+}
+
+//GetTargetContainer will fetch the container name from application pod
+//This container will be used as target container
+func GetTargetContainer(experimentsDetails *experimentTypes.ExperimentDetails, appName string, clients clients.ClientSets) (string, error) {
+	pod, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.AppNS).Get(appName, v1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	return pod.Spec.Containers[0].Name, nil
+}
+
+// GetHelperManager returns the manager of the helper pods
+func GetHelperManager(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, chaosDetails *types.ChaosDetails) *helper.Manager {
+	return helper.NewManager(helper.Meta{
+		ExperimentName:  experimentsDetails.ExperimentName,
+		Namespace:       experimentsDetails.ChaosNamespace,
+		ChaosUID:        experimentsDetails.ChaosUID,
+		Image:           experimentsDetails.LIBImage,
+		ImagePullPolicy: experimentsDetails.LIBImagePullPolicy,
+	}, clients, chaosDetails)
+}
+
+// CreateHelperPod derive the attributes for helper pod and create the helper pod
+func CreateHelperPod(experimentsDetails *experimentTypes.ExperimentDetails, manager *helper.Manager, podName, nodeName string) error {
+
+	opts := []helper.Option{
+		helper.WithNodeName(nodeName),
+		helper.WithHostPID(),
+		helper.WithTerminationGracePeriod(experimentsDetails.TerminationGracePeriodSeconds),
+		helper.WithServiceAccount(experimentsDetails.ChaosServiceAccount),
+		helper.WithAnnotations(experimentsDetails.Annotations),
+		helper.WithImagePullSecrets(experimentsDetails.ImagePullSecrets),
+		helper.WithResources(experimentsDetails.Resources),
+		helper.WithCommand([]string{"/bin/bash"}, "-c", "./helper/pod-time-chaos"),
+		helper.WithEnv(GetPodEnv(experimentsDetails, podName)),
+		helper.WithPodNameEnv(),
+		helper.WithSocketMount(experimentsDetails.SocketPath),
+		// the helper runs as root with SYS_PTRACE, as the clock of the target processes is skewed via ptrace
+		helper.WithRunAsUser(0),
+		helper.WithCapabilities("SYS_PTRACE"),
+	}
+	// the crio socket is labeled by selinux, it is accessible only from the privileged containers
+	if experimentsDetails.ContainerRuntime == "crio" {
+		opts = append(opts, helper.WithPrivileged())
+	}
+	return manager.Create(manager.NewPod(opts...))
+}
+
+// GetPodEnv derive all the env required for the helper pod
+func GetPodEnv(experimentsDetails *experimentTypes.ExperimentDetails, podName string) map[string]string {
+
+	return map[string]string{
+		"APP_NS":            experimentsDetails.AppNS,
+		"APP_POD":           podName,
+		"APP_CONTAINER":     experimentsDetails.TargetContainer,
+		"CHAOS_DURATION":    strconv.Itoa(experimentsDetails.ChaosDuration),
+		"CHAOS_NAMESPACE":   experimentsDetails.ChaosNamespace,
+		"CHAOS_ENGINE":      experimentsDetails.EngineName,
+		"CHAOS_UID":         string(experimentsDetails.ChaosUID),
+		"CONTAINER_RUNTIME": experimentsDetails.ContainerRuntime,
+		"EXPERIMENT_NAME":   experimentsDetails.ExperimentName,
+		"SOCKET_PATH":       experimentsDetails.SocketPath,
+		"TIME_OFFSET":       experimentsDetails.TimeOffset,
+		"CLOCK_IDS":         experimentsDetails.ClockIDs,
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"go/format"
 	"io/ioutil"
 	"os"
 	"path"
@@ -22,6 +23,10 @@ func GenerateExperiment(attributeFile *string, generationType string) error {
 	if err := GetConfig(&experimentDetails, *attributeFile); err != nil {
 		return err
 	}
+	// derive the chaoslib type & the permissions of the experiment
+	if err := SetDefaults(&experimentDetails); err != nil {
+		return err
+	}
 
 	// getting the current directory name
 	currDir, err := os.Getwd()
@@ -110,12 +115,32 @@ func GenerateExperiment(attributeFile *string, generationType string) error {
 			return err
 		}
 
-		// generating the chaoslib file
+		// generating the chaoslib file of the chaoslib type
+		v := variants[experimentDetails.Type]
 		chaoslibFilePath := chaoslibDIR + "/" + experimentDetails.Name + ".go"
-		if err = GenerateFile(experimentDetails, chaoslibFilePath, "./templates/chaoslib.tmpl"); err != nil {
+		if err = GenerateFile(experimentDetails, chaoslibFilePath, v.chaoslib); err != nil {
+			return err
+		}
+
+		// generating the unit test file of the chaoslib
+		chaoslibTestFilePath := chaoslibDIR + "/" + experimentDetails.Name + "_test.go"
+		if err = GenerateFile(experimentDetails, chaoslibTestFilePath, v.test); err != nil {
 			return err
 		}
 
+		// generating the helper binary, if the chaoslib runs its own helper
+		if v.helper != "" {
+			helperDIR := chaoslibRootDIR + "/helper"
+			CreateDirectoryIfNotPresent(helperDIR)
+			helperFilePath := helperDIR + "/" + experimentDetails.Name + ".go"
+			if err = GenerateFile(experimentDetails, helperFilePath, v.helper); err != nil {
+				return err
+			}
+			if err = RegisterHelper(experimentDetails, litmusRootDir+"/build/generate_go_binary"); err != nil {
+				return err
+			}
+		}
+
 		// generating the environment var file
 		environmentFilePath := environmentDIR + "/" + "environment.go"
 		if err = GenerateFile(experimentDetails, environmentFilePath, "./templates/environment.tmpl"); err != nil {
@@ -128,6 +153,10 @@ func GenerateExperiment(attributeFile *string, generationType string) error {
 			return err
 		}
 
+		// registering the experiment in the go-runner
+		if err = RegisterExperiment(experimentDetails, litmusRootDir+"/bin/go-runner.go"); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -152,7 +181,10 @@ func GetConfig(experimentDetails *types.Experiment, attributeFile string) error
 func GenerateFile(experimentDetails types.Experiment, fileName string, templatePath string) error {
 
 	// parse the experiment template
-	tpl, err := template.ParseFiles(templatePath)
+	tpl, err := template.New(filepath.Base(templatePath)).Funcs(template.FuncMap{
+		"camel":      CamelCase,
+		"lowerCamel": LowerCamelCase,
+	}).ParseFiles(templatePath)
 	if err != nil {
 		return err
 	}
@@ -163,8 +195,16 @@ func GenerateFile(experimentDetails types.Experiment, fileName string, templateP
 		return err
 	}
 
+	// format the go files, as the conditional blocks of the templates leave the blank lines & imports unaligned
+	data := out.Bytes()
+	if filepath.Ext(fileName) == ".go" {
+		if data, err = format.Source(data); err != nil {
+			return errors.Errorf("Unable to format the %v file, err: %v", fileName, err)
+		}
+	}
+
 	// write the date into the destination file
-	err = ioutil.WriteFile(fileName, out.Bytes(), 0644)
+	err = ioutil.WriteFile(fileName, data, 0644)
 	return err
 }
 
@@ -41,6 +41,12 @@ type Experiment struct {
 	MinKubernetesVersion string `json:"minkubernetesversion"`
 	// reference contains the all the references like docs, youtube video link, etc
 	References []ReferencesDetails `json:"references"`
+	// Type define the type of the chaoslib to be generated
+	// it can be exec, helper, node, cloud or k8s, it defaults to exec
+	Type string `json:"type,omitempty"`
+	// CloudProvider define the cloud provider of the cloud type experiment
+	// it can be aws, gcp or azure, it defaults to aws
+	CloudProvider string `json:"cloudprovider,omitempty"`
 }
 
 // Permission contains the list of all permission needed inside cluster-role to execute the experiment
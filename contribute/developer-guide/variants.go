@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"go/format"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/litmuschaos/litmus-go/contribute/developer-guide/types"
+	"github.com/pkg/errors"
+)
+
+// variant contains the templates and the permissions of a chaoslib type
+type variant struct {
+	// chaoslib is the template of the chaoslib
+	chaoslib string
+	// test is the template of the unit test of the chaoslib
+	test string
+	// helper is the template of the helper binary, if the chaoslib runs its own helper
+	helper string
+	// permissions are the permissions needed by the chaoslib, on top of the declared ones
+	permissions []types.Permission
+}
+
+// basePermissions are the permissions needed by all the experiments to read the targets, update the results and create the events
+var basePermissions = []types.Permission{
+	{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"get", "list"}},
+	{APIGroups: []string{""}, Resources: []string{"events"}, Verbs: []string{"create", "get", "list", "patch", "update"}},
+	{APIGroups: []string{"litmuschaos.io"}, Resources: []string{"chaosengines", "chaosexperiments", "chaosresults"}, Verbs: []string{"create", "get", "list", "patch", "update", "delete"}},
+}
+
+// helperPermissions are the permissions needed to run the helper pods and to store their diagnostics
+// the namespaces are read to verify the helper pods against the Pod Security level of the chaos namespace
+var helperPermissions = []types.Permission{
+	{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"create", "delete", "deletecollection"}},
+	{APIGroups: []string{""}, Resources: []string{"namespaces"}, Verbs: []string{"get"}},
+	{APIGroups: []string{""}, Resources: []string{"pods/log"}, Verbs: []string{"get", "list", "watch"}},
+	{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"create", "get", "update"}},
+}
+
+// variants contains the supported chaoslib types, keyed by the type
+var variants = map[string]variant{
+	"exec": {
+		chaoslib: "./templates/chaoslib.tmpl",
+		test:     "./templates/chaoslib_exec_test.tmpl",
+		permissions: []types.Permission{
+			{APIGroups: []string{""}, Resources: []string{"pods/exec"}, Verbs: []string{"create", "get"}},
+		},
+	},
+	"helper": {
+		chaoslib:    "./templates/chaoslib_helper.tmpl",
+		test:        "./templates/chaoslib_helper_test.tmpl",
+		helper:      "./templates/helper.tmpl",
+		permissions: helperPermissions,
+	},
+	"node": {
+		chaoslib: "./templates/chaoslib_node.tmpl",
+		test:     "./templates/chaoslib_node_test.tmpl",
+		permissions: append([]types.Permission{
+			{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get", "list"}},
+		}, helperPermissions...),
+	},
+	"cloud": {
+		chaoslib: "./templates/chaoslib_cloud.tmpl",
+		test:     "./templates/chaoslib_cloud_test.tmpl",
+	},
+	"k8s": {
+		chaoslib: "./templates/chaoslib_k8s.tmpl",
+		test:     "./templates/chaoslib_k8s_test.tmpl",
+		permissions: []types.Permission{
+			{APIGroups: []string{""}, Resources: []string{"pods"}, Verbs: []string{"patch", "update"}},
+		},
+	},
+}
+
+// clusterResources are the cluster scoped resources, which can't be granted by a namespaced role
+var clusterResources = map[string]bool{"nodes": true, "nodes/proxy": true, "namespaces": true, "persistentvolumes": true, "storageclasses": true}
+
+// nameRegex matches the valid experiment names, which are used as the package paths & the go-runner names
+var nameRegex = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+// SetDefaults validates the attributes of the experiment and derives the attributes of its chaoslib type
+// the permissions of the chaoslib type are merged with the declared permissions
+// and the scope is set to cluster, if any of the permissions is cluster scoped
+func SetDefaults(experimentDetails *types.Experiment) error {
+
+	if !nameRegex.MatchString(experimentDetails.Name) {
+		return errors.Errorf("%v is not a valid experiment name, it should contain lowercase alphanumerics separated by '-'", experimentDetails.Name)
+	}
+	if experimentDetails.Type == "" {
+		experimentDetails.Type = "exec"
+	}
+	v, ok := variants[experimentDetails.Type]
+	if !ok {
+		return errors.Errorf("%v type not supported, supported types are exec, helper, node, cloud and k8s", experimentDetails.Type)
+	}
+	if experimentDetails.Type == "cloud" {
+		switch experimentDetails.CloudProvider {
+		case "":
+			experimentDetails.CloudProvider = "aws"
+		case "aws", "gcp", "azure":
+		default:
+			return errors.Errorf("%v cloud provider not supported, supported providers are aws, gcp and azure", experimentDetails.CloudProvider)
+		}
+	}
+
+	permissions := append(append([]types.Permission{}, basePermissions...), v.permissions...)
+	experimentDetails.Permissions = MergePermissions(append(permissions, experimentDetails.Permissions...))
+	for _, permission := range experimentDetails.Permissions {
+		for _, resource := range permission.Resources {
+			if clusterResources[resource] {
+				experimentDetails.Scope = "Cluster"
+			}
+		}
+	}
+	if experimentDetails.Scope == "" {
+		experimentDetails.Scope = "Namespaced"
+	}
+	return nil
+}
+
+// MergePermissions merges the permissions, so that every resource is listed once with the union of its verbs
+// the resources with the same api group & verbs are grouped in a single permission
+func MergePermissions(permissions []types.Permission) []types.Permission {
+
+	verbs := map[string]map[string]map[string]bool{}
+	for _, permission := range permissions {
+		groups := permission.APIGroups
+		if len(groups) == 0 {
+			groups = []string{""}
+		}
+		for _, group := range groups {
+			if verbs[group] == nil {
+				verbs[group] = map[string]map[string]bool{}
+			}
+			for _, resource := range permission.Resources {
+				if verbs[group][resource] == nil {
+					verbs[group][resource] = map[string]bool{}
+				}
+				for _, verb := range permission.Verbs {
+					verbs[group][resource][verb] = true
+				}
+			}
+		}
+	}
+
+	var result []types.Permission
+	index := map[string]int{}
+	for _, group := range sortedKeys(verbs) {
+		for _, resource := range sortedKeys(verbs[group]) {
+			resourceVerbs := sortedKeys(verbs[group][resource])
+			key := group + "/" + strings.Join(resourceVerbs, ",")
+			if i, ok := index[key]; ok {
+				result[i].Resources = append(result[i].Resources, resource)
+				continue
+			}
+			index[key] = len(result)
+			result = append(result, types.Permission{APIGroups: []string{group}, Resources: []string{resource}, Verbs: resourceVerbs})
+		}
+	}
+	return result
+}
+
+// sortedKeys returns the sorted keys of the map
+func sortedKeys(m interface{}) []string {
+
+	var keys []string
+	switch m := m.(type) {
+	case map[string]map[string]map[string]bool:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	case map[string]map[string]bool:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	case map[string]bool:
+		for key := range m {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// CamelCase converts the experiment name to the exported go identifier, ex: pod-delete to PodDelete
+func CamelCase(name string) string {
+
+	var result string
+	for _, part := range strings.Split(name, "-") {
+		if part != "" {
+			result += strings.ToUpper(part[:1]) + part[1:]
+		}
+	}
+	return result
+}
+
+// LowerCamelCase converts the experiment name to the unexported go identifier, ex: pod-delete to podDelete
+func LowerCamelCase(name string) string {
+
+	result := CamelCase(name)
+	if result == "" {
+		return result
+	}
+	return strings.ToLower(result[:1]) + result[1:]
+}
+
+// RegisterExperiment registers the experiment in the go-runner, so that it can be invoked with its name
+// the registration is skipped, if the experiment is already registered from the same category
+func RegisterExperiment(experimentDetails types.Experiment, goRunnerPath string) error {
+
+	content, err := ioutil.ReadFile(goRunnerPath)
+	if err != nil {
+		return errors.Errorf("Unable to read the go-runner, err: %v", err)
+	}
+	source := string(content)
+	importPath := "\"github.com/litmuschaos/litmus-go/experiments/" + experimentDetails.Category + "/" + experimentDetails.Name + "/experiment\""
+	if strings.Contains(source, "case \""+experimentDetails.Name+"\":") {
+		if strings.Contains(source, importPath) {
+			return nil
+		}
+		return errors.Errorf("%v experiment is already registered in the go-runner by another category", experimentDetails.Name)
+	}
+
+	importLine := "\t" + LowerCamelCase(experimentDetails.Name) + " " + importPath + "\n"
+	caseLines := "\tcase \"" + experimentDetails.Name + "\":\n\t\t" + LowerCamelCase(experimentDetails.Name) + "." + CamelCase(experimentDetails.Name) + "(clients)\n"
+
+	// the import is added after the last experiment import and the case before the default case
+	importIndex := strings.LastIndex(source, "\"github.com/litmuschaos/litmus-go/experiments/")
+	defaultIndex := strings.LastIndex(source, "\tdefault:\n")
+	if importIndex == -1 || defaultIndex == -1 {
+		return errors.Errorf("Unable to find the experiment imports & the default case in the go-runner")
+	}
+	importIndex += strings.Index(source[importIndex:], "\n") + 1
+	source = source[:importIndex] + importLine + source[importIndex:defaultIndex] + caseLines + source[defaultIndex:]
+
+	formatted, err := format.Source([]byte(source))
+	if err != nil {
+		return errors.Errorf("Unable to format the go-runner, err: %v", err)
+	}
+	return ioutil.WriteFile(goRunnerPath, formatted, 0644)
+}
+
+// RegisterHelper adds the build of the helper binary to the build script
+// the registration is skipped, if the helper is already registered
+func RegisterHelper(experimentDetails types.Experiment, buildScriptPath string) error {
+
+	content, err := ioutil.ReadFile(buildScriptPath)
+	if err != nil {
+		return errors.Errorf("Unable to read the build script, err: %v", err)
+	}
+	source := string(content)
+	buildLine := "go build -o build/_output/${GOARCH}/helper/" + experimentDetails.Name + " ./chaoslib/litmus/" + experimentDetails.Name + "/helper\n"
+	if strings.Contains(source, buildLine) {
+		return nil
+	}
+
+	// the helper is built before the experiments
+	experimentsIndex := strings.Index(source, "# Building go binaries for all experiments")
+	if experimentsIndex == -1 {
+		experimentsIndex = len(source)
+	}
+	helperLines := "# Building go binaries for " + strings.Replace(experimentDetails.Name, "-", "_", -1) + " helper\n" + buildLine
+	source = source[:experimentsIndex] + helperLines + source[experimentsIndex:]
+	return ioutil.WriteFile(buildScriptPath, []byte(source), 0644)
+}
@@ -0,0 +1,251 @@
+package experiment
+
+import (
+	litmusLIB "github.com/litmuschaos/litmus-go/chaoslib/litmus/cassandra-node-decommission/lib"
+	"github.com/litmuschaos/litmus-go/pkg/cassandra"
+	experimentEnv "github.com/litmuschaos/litmus-go/pkg/cassandra/pod-delete/environment"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/cassandra/pod-delete/types"
+	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/probe"
+	"github.com/litmuschaos/litmus-go/pkg/result"
+	"github.com/litmuschaos/litmus-go/pkg/status"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/sirupsen/logrus"
+)
+
+// CassandraNodeDecommission inject the cassandra-node-decommission chaos
+func CassandraNodeDecommission(clients clients.ClientSets) {
+
+	var err error
+	var liveness *cassandra.Liveness
+	experimentsDetails := experimentTypes.ExperimentDetails{}
+	resultDetails := types.ResultDetails{}
+	eventsDetails := types.EventDetails{}
+	chaosDetails := types.ChaosDetails{}
+
+	//Fetching all the ENV passed from the runner pod
+	log.Info("[PreReq]: Getting the ENV for the cassandra-node-decommission experiment")
+	experimentEnv.GetENV(&experimentsDetails, "cassandra-node-decommission")
+
+	// Intialise the chaos attributes
+	experimentEnv.InitialiseChaosVariables(&chaosDetails, &experimentsDetails)
+
+	// Intialise Chaos Result Parameters
+	types.SetResultAttributes(&resultDetails, chaosDetails)
+
+	if experimentsDetails.ChaoslibDetail.EngineName != "" {
+		// Intialise the probe details. Bail out upon error, as we haven't entered exp business logic yet
+		if err = probe.InitializeProbesInChaosResultDetails(&chaosDetails, clients, &resultDetails); err != nil {
+			log.Errorf("Unable to initialize the probes, err: %v", err)
+			return
+		}
+	}
+
+	//Updating the chaos result in the beginning of experiment
+	log.Infof("[PreReq]: Updating the chaos result of %v experiment (SOT)", experimentsDetails.ChaoslibDetail.ExperimentName)
+	err = result.ChaosResult(&chaosDetails, clients, &resultDetails, "SOT")
+	if err != nil {
+		log.Errorf("Unable to Create the Chaos Result, err: %v", err)
+		failStep := "Updating the chaos result of cassandra-node-decommission experiment (SOT)"
+		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+		return
+	}
+
+	// Set the chaos result uid
+	result.SetResultUID(&resultDetails, clients, &chaosDetails)
+
+	// generating the event in chaosresult to marked the verdict as awaited
+	msg := "experiment: " + experimentsDetails.ChaoslibDetail.ExperimentName + ", Result: Awaited"
+	types.SetResultEventAttributes(&eventsDetails, types.AwaitedVerdict, msg, "Normal", &resultDetails)
+	events.GenerateEvents(&eventsDetails, clients, &chaosDetails, "ChaosResult")
+
+	//DISPLAY THE APP INFORMATION
+	log.InfoWithValues("The application informations are as follows", logrus.Fields{
+		"Namespace":              experimentsDetails.ChaoslibDetail.AppNS,
+		"Label":                  experimentsDetails.ChaoslibDetail.AppLabel,
+		"CassandraLivenessCheck": experimentsDetails.CassandraLivenessCheck,
+		"CassandraPort":          experimentsDetails.CassandraPort,
+		"CassandraTargetToken":   experimentsDetails.CassandraTargetToken,
+		"DecommissionRecovery":   experimentsDetails.DecommissionRecovery,
+		"Ramp Time":              experimentsDetails.ChaoslibDetail.RampTime,
+	})
+
+	//PRE-CHAOS APPLICATION STATUS CHECK
+	log.Info("[Status]: Verify that the AUT (Application Under Test) is running (pre-chaos)")
+	if err = status.AUTStatusCheck(experimentsDetails.ChaoslibDetail.AppNS, experimentsDetails.ChaoslibDetail.AppLabel, experimentsDetails.ChaoslibDetail.TargetContainer, experimentsDetails.ChaoslibDetail.Timeout, experimentsDetails.ChaoslibDetail.Delay, clients, &chaosDetails); err != nil {
+		log.Errorf("Application status check failed, err: %v", err)
+		failStep := "Verify that the AUT (Application Under Test) is running (pre-chaos)"
+		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+		return
+	}
+
+	if experimentsDetails.ChaoslibDetail.EngineName != "" {
+		// marking AUT as running, as we already checked the status of application under test
+		msg := "AUT: Running"
+
+		// run the probes in the pre-chaos check
+		if len(resultDetails.ProbeDetails) != 0 {
+
+			err = probe.RunProbes(&chaosDetails, clients, &resultDetails, "PreChaos", &eventsDetails)
+			if err != nil {
+				log.Errorf("Probes Failed, err: %v", err)
+				failStep := "Failed while running probes"
+				msg := "AUT: Running, Probes: Unsuccessful"
+				types.SetEngineEventAttributes(&eventsDetails, types.PreChaosCheck, msg, "Warning", &chaosDetails)
+				events.GenerateEvents(&eventsDetails, clients, &chaosDetails, "ChaosEngine")
+				result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+				return
+			}
+			msg = "AUT: Running, Probes: Successful"
+		}
+		// generating the events for the pre-chaos check
+		types.SetEngineEventAttributes(&eventsDetails, types.PreChaosCheck, msg, "Normal", &chaosDetails)
+		events.GenerateEvents(&eventsDetails, clients, &chaosDetails, "ChaosEngine")
+	}
+
+	// Checking the status of the ring (pre-chaos)
+	log.Info("[Status]: Checking the status of the ring (pre-chaos)")
+	err = cassandra.RingStatusCheck(&experimentsDetails, clients)
+	if err != nil {
+		log.Errorf("[Status]: Cassandra ring status check failed, err: %v", err)
+		failStep := "Checking the status of the ring (pre-chaos)"
+		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+		return
+	}
+
+	// Deriving the owner of the target token, if the target pods are not provided
+	if experimentsDetails.CassandraTargetToken != "" && experimentsDetails.ChaoslibDetail.TargetPods == "" {
+		experimentsDetails.ChaoslibDetail.TargetPods, err = cassandra.GetTokenOwner(&experimentsDetails, clients)
+		if err != nil {
+			log.Errorf("Unable to derive the owner of the target token, err: %v", err)
+			failStep := "Deriving the owner of the target token"
+			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+			return
+		}
+	}
+
+	// Cassandra liveness check
+	if experimentsDetails.CassandraLivenessCheck == "enabled" {
+		liveness, err = cassandra.LivenessCheck(&experimentsDetails, clients)
+		if err != nil {
+			log.Errorf("[Liveness]: Cassandra liveness check failed, err: %v", err)
+			failStep := "failed while starting the liveness loop"
+			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+			return
+		}
+		log.Info("[Confirmation]: The cassandra liveness loop started successfully")
+	} else {
+		log.Warn("[Liveness]: Cassandra Liveness check skipped as it was not enabled")
+	}
+
+	// Including the litmus lib for cassandra-node-decommission
+	if experimentsDetails.ChaoslibDetail.ChaosLib == "litmus" {
+		err = litmusLIB.PrepareNodeDecommission(&experimentsDetails, clients, &resultDetails, &eventsDetails, &chaosDetails)
+		if err != nil {
+			log.Errorf("Chaos injection failed, err: %v", err)
+			failStep := "failed in chaos injection phase"
+			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+			return
+		}
+		log.Info("[Confirmation]: The cassandra node has been decommissioned and recovered successfully")
+		resultDetails.Verdict = "Pass"
+	} else {
+		log.Error("[Invalid]: Please Provide the correct LIB")
+		failStep := "no match found for specified lib"
+		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+		return
+	}
+
+	//POST-CHAOS APPLICATION STATUS CHECK
+	log.Info("[Status]: Verify that the AUT (Application Under Test) is running (post-chaos)")
+	if err = status.AUTStatusCheck(experimentsDetails.ChaoslibDetail.AppNS, experimentsDetails.ChaoslibDetail.AppLabel, experimentsDetails.ChaoslibDetail.TargetContainer, experimentsDetails.ChaoslibDetail.Timeout, experimentsDetails.ChaoslibDetail.Delay, clients, &chaosDetails); err != nil {
+		log.Errorf("Application status check failed, err: %v", err)
+		failStep := "Verify that the AUT (Application Under Test) is running (post-chaos)"
+		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+		return
+	}
+	if experimentsDetails.ChaoslibDetail.EngineName != "" {
+		// marking AUT as running, as we already checked the status of application under test
+		msg := "AUT: Running"
+
+		// run the probes in the post-chaos check
+		if len(resultDetails.ProbeDetails) != 0 {
+			err = probe.RunProbes(&chaosDetails, clients, &resultDetails, "PostChaos", &eventsDetails)
+			if err != nil {
+				log.Errorf("Probes Failed, err: %v", err)
+				failStep := "Failed while running probes"
+				msg := "AUT: Running, Probes: Unsuccessful"
+				types.SetEngineEventAttributes(&eventsDetails, types.PostChaosCheck, msg, "Warning", &chaosDetails)
+				events.GenerateEvents(&eventsDetails, clients, &chaosDetails, "ChaosEngine")
+				result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+				return
+			}
+			msg = "AUT: Running, Probes: Successful"
+		}
+
+		// generating post chaos event
+		types.SetEngineEventAttributes(&eventsDetails, types.PostChaosCheck, msg, "Normal", &chaosDetails)
+		events.GenerateEvents(&eventsDetails, clients, &chaosDetails, "ChaosEngine")
+	}
+
+	// Checking the status of the ring (post-chaos), the decommissioned node is not part of the ring without the recovery
+	if experimentsDetails.DecommissionRecovery != "none" {
+		log.Info("[Status]: Checking the status of the ring (post-chaos)")
+		err = cassandra.RingStatusCheck(&experimentsDetails, clients)
+		if err != nil {
+			log.Errorf("[Status]: Cassandra ring status check failed, err: %v", err)
+			failStep := "Checking the status of the ring (post-chaos)"
+			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+			return
+		}
+	} else {
+		log.Warn("[Status]: Cassandra ring status check skipped as the decommission recovery is disabled")
+	}
+
+	// Cassandra liveness check (post-chaos)
+	if experimentsDetails.CassandraLivenessCheck == "enabled" {
+		log.Info("[Status]: Confirm that the cassandra liveness loop is recovered (post-chaos)")
+		if err = cassandra.LivenessStatusCheck(&experimentsDetails, liveness, &resultDetails); err != nil {
+			log.Errorf("Liveness status check failed, err: %v", err)
+			cassandra.LivenessCleanup(&experimentsDetails, liveness)
+			failStep := "failed while checking the status of the liveness loop"
+			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+			return
+		}
+		err = cassandra.LivenessCleanup(&experimentsDetails, liveness)
+		if err != nil {
+			log.Errorf("Liveness cleanup failed, err: %v", err)
+			failStep := "failed while dropping the liveness keyspace"
+			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+			return
+		}
+	}
+	//Updating the chaosResult in the end of experiment
+	log.Info("[The End]: Updating the chaos result of cassandra node decommission experiment (EOT)")
+	err = result.ChaosResult(&chaosDetails, clients, &resultDetails, "EOT")
+	if err != nil {
+		log.Errorf("Unable to Update the Chaos Result, err: %v", err)
+		return
+	}
+
+	// generating the event in chaosresult to marked the verdict as pass/fail
+	msg = "experiment: " + experimentsDetails.ChaoslibDetail.ExperimentName + ", Result: " + resultDetails.Verdict
+	reason := types.PassVerdict
+	eventType := "Normal"
+	if resultDetails.Verdict != "Pass" {
+		reason = types.FailVerdict
+		eventType = "Warning"
+	}
+
+	types.SetResultEventAttributes(&eventsDetails, reason, msg, eventType, &resultDetails)
+	events.GenerateEvents(&eventsDetails, clients, &chaosDetails, "ChaosResult")
+
+	if experimentsDetails.ChaoslibDetail.EngineName != "" {
+		msg := experimentsDetails.ChaoslibDetail.ExperimentName + " experiment has been " + resultDetails.Verdict + "ed"
+		types.SetEngineEventAttributes(&eventsDetails, types.Summary, msg, "Normal", &chaosDetails)
+		events.GenerateEvents(&eventsDetails, clients, &chaosDetails, "ChaosEngine")
+	}
+
+}
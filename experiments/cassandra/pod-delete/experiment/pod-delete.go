@@ -19,7 +19,7 @@ import (
 func CasssandraPodDelete(clients clients.ClientSets) {
 
 	var err error
-	var ResourceVersionBefore string
+	var liveness *cassandra.Liveness
 	experimentsDetails := experimentTypes.ExperimentDetails{}
 	resultDetails := types.ResultDetails{}
 	eventsDetails := types.EventDetails{}
@@ -27,7 +27,7 @@ func CasssandraPodDelete(clients clients.ClientSets) {
 
 	//Fetching all the ENV passed from the runner pod
 	log.Info("[PreReq]: Getting the ENV for the cassandra-pod-delete experiment")
-	experimentEnv.GetENV(&experimentsDetails)
+	experimentEnv.GetENV(&experimentsDetails, "cassandra-pod-delete")
 
 	// Intialise the chaos attributes
 	experimentEnv.InitialiseChaosVariables(&chaosDetails, &experimentsDetails)
@@ -65,9 +65,9 @@ func CasssandraPodDelete(clients clients.ClientSets) {
 	log.InfoWithValues("The application informations are as follows", logrus.Fields{
 		"Namespace":              experimentsDetails.ChaoslibDetail.AppNS,
 		"Label":                  experimentsDetails.ChaoslibDetail.AppLabel,
-		"CassandraLivenessImage": experimentsDetails.CassandraLivenessImage,
 		"CassandraLivenessCheck": experimentsDetails.CassandraLivenessCheck,
 		"CassandraPort":          experimentsDetails.CassandraPort,
+		"CassandraTargetToken":   experimentsDetails.CassandraTargetToken,
 		"Ramp Time":              experimentsDetails.ChaoslibDetail.RampTime,
 	})
 
@@ -104,26 +104,37 @@ func CasssandraPodDelete(clients clients.ClientSets) {
 		events.GenerateEvents(&eventsDetails, clients, &chaosDetails, "ChaosEngine")
 	}
 
-	// Checking the load distribution on the ring (pre-chaos)
-	log.Info("[Status]: Checking the load distribution on the ring (pre-chaos)")
-	err = cassandra.NodeToolStatusCheck(&experimentsDetails, clients)
+	// Checking the status of the ring (pre-chaos)
+	log.Info("[Status]: Checking the status of the ring (pre-chaos)")
+	err = cassandra.RingStatusCheck(&experimentsDetails, clients)
 	if err != nil {
-		log.Errorf("[Status]: Chaos node tool status check failed, err: %v", err)
-		failStep := "Checking for load distribution on the ring(pre-chaos)"
+		log.Errorf("[Status]: Cassandra ring status check failed, err: %v", err)
+		failStep := "Checking the status of the ring (pre-chaos)"
 		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
 		return
 	}
 
+	// Deriving the owner of the target token, if the target pods are not provided
+	if experimentsDetails.CassandraTargetToken != "" && experimentsDetails.ChaoslibDetail.TargetPods == "" {
+		experimentsDetails.ChaoslibDetail.TargetPods, err = cassandra.GetTokenOwner(&experimentsDetails, clients)
+		if err != nil {
+			log.Errorf("Unable to derive the owner of the target token, err: %v", err)
+			failStep := "Deriving the owner of the target token"
+			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+			return
+		}
+	}
+
 	// Cassandra liveness check
 	if experimentsDetails.CassandraLivenessCheck == "enabled" {
-		ResourceVersionBefore, err = cassandra.LivenessCheck(&experimentsDetails, clients)
+		liveness, err = cassandra.LivenessCheck(&experimentsDetails, clients)
 		if err != nil {
 			log.Errorf("[Liveness]: Cassandra liveness check failed, err: %v", err)
-			failStep := "failed while creating liveness pod"
+			failStep := "failed while starting the liveness loop"
 			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
 			return
 		}
-		log.Info("[Confirmation]: The cassandra application liveness pod created successfully")
+		log.Info("[Confirmation]: The cassandra liveness loop started successfully")
 	} else {
 		log.Warn("[Liveness]: Cassandra Liveness check skipped as it was not enabled")
 	}
@@ -178,31 +189,30 @@ func CasssandraPodDelete(clients clients.ClientSets) {
 		events.GenerateEvents(&eventsDetails, clients, &chaosDetails, "ChaosEngine")
 	}
 
-	// Checking the load distribution on the ring (post-chaos)
-	log.Info("[Status]: Checking the load distribution on the ring (post-chaos)")
-	err = cassandra.NodeToolStatusCheck(&experimentsDetails, clients)
+	// Checking the status of the ring (post-chaos)
+	log.Info("[Status]: Checking the status of the ring (post-chaos)")
+	err = cassandra.RingStatusCheck(&experimentsDetails, clients)
 	if err != nil {
-		log.Errorf("[Status]: Chaos node tool status check is failed, err: %v", err)
-		failStep := "Checking for load distribution on the ring(post-chaos)"
+		log.Errorf("[Status]: Cassandra ring status check failed, err: %v", err)
+		failStep := "Checking the status of the ring (post-chaos)"
 		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
 		return
 	}
 
-	// Cassandra statefulset liveness check (post-chaos)
-	log.Info("[Status]: Confirm that the cassandra liveness pod is running(post-chaos)")
-	// Checking the running status of cassandra liveness
+	// Cassandra liveness check (post-chaos)
 	if experimentsDetails.CassandraLivenessCheck == "enabled" {
-		err = status.CheckApplicationStatus(experimentsDetails.ChaoslibDetail.AppNS, "name=cassandra-liveness-deploy-"+experimentsDetails.RunID, experimentsDetails.ChaoslibDetail.Timeout, experimentsDetails.ChaoslibDetail.Delay, clients)
-		if err != nil {
+		log.Info("[Status]: Confirm that the cassandra liveness loop is recovered (post-chaos)")
+		if err = cassandra.LivenessStatusCheck(&experimentsDetails, liveness, &resultDetails); err != nil {
 			log.Errorf("Liveness status check failed, err: %v", err)
-			failStep := "failed while checking the status of liveness pod"
+			cassandra.LivenessCleanup(&experimentsDetails, liveness)
+			failStep := "failed while checking the status of the liveness loop"
 			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
 			return
 		}
-		err = cassandra.LivenessCleanup(&experimentsDetails, clients, ResourceVersionBefore)
+		err = cassandra.LivenessCleanup(&experimentsDetails, liveness)
 		if err != nil {
 			log.Errorf("Liveness cleanup failed, err: %v", err)
-			failStep := "failed while deleting liveness pod"
+			failStep := "failed while dropping the liveness keyspace"
 			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
 			return
 		}
@@ -62,11 +62,12 @@ func NodeCPUHog(clients clients.ClientSets) {
 
 	//DISPLAY THE APP INFORMATION
 	log.InfoWithValues("The application information is as follows", logrus.Fields{
-		"Namespace":      experimentsDetails.AppNS,
-		"Label":          experimentsDetails.AppLabel,
-		"Chaos Duration": experimentsDetails.ChaosDuration,
-		"Target Nodes":   experimentsDetails.TargetNodes,
-		"Ramp Time":      experimentsDetails.RampTime,
+		"Namespace":              experimentsDetails.AppNS,
+		"Label":                  experimentsDetails.AppLabel,
+		"Chaos Duration":         experimentsDetails.ChaosDuration,
+		"Target Nodes":           experimentsDetails.TargetNodes,
+		"Ramp Time":              experimentsDetails.RampTime,
+		"Target CPU Utilisation": experimentsDetails.TargetCPUUtilisation,
 	})
 
 	// Calling AbortWatcher go routine, it will continuously watch for the abort signal and generate the required events and result
@@ -69,6 +69,7 @@ func NodeMemoryHog(clients clients.ClientSets) {
 		"Ramp Time":                     experimentsDetails.RampTime,
 		"Memory Consumption Percentage": experimentsDetails.MemoryConsumptionPercentage,
 		"Memory Consumption Mebibytes":  experimentsDetails.MemoryConsumptionMebibytes,
+		"Target Memory Utilisation":     experimentsDetails.TargetMemoryUtilisation,
 	})
 
 	// Calling AbortWatcher go routine, it will continuously watch for the abort signal and generate the required events and result
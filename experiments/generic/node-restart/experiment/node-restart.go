@@ -60,6 +60,7 @@ func NodeRestart(clients clients.ClientSets) {
 		"Namespace":      experimentsDetails.AppNS,
 		"Label":          experimentsDetails.AppLabel,
 		"Target Node":    experimentsDetails.TargetNode,
+		"Reboot Method":  experimentsDetails.RebootMethod,
 		"Chaos Duration": experimentsDetails.ChaosDuration,
 		"Ramp Time":      experimentsDetails.RampTime,
 	})
@@ -61,10 +61,12 @@ func NodeTaint(clients clients.ClientSets) {
 
 	//DISPLAY THE APP INFORMATION
 	log.InfoWithValues("The application information is as follows", logrus.Fields{
-		"Namespace":   experimentsDetails.AppNS,
-		"Label":       experimentsDetails.AppLabel,
-		"Target Node": experimentsDetails.TargetNode,
-		"Ramp Time":   experimentsDetails.RampTime,
+		"Namespace":    experimentsDetails.AppNS,
+		"Label":        experimentsDetails.AppLabel,
+		"Target Nodes": experimentsDetails.TargetNodes,
+		"Node Label":   experimentsDetails.NodeLabel,
+		"Taints":       experimentsDetails.Taints,
+		"Ramp Time":    experimentsDetails.RampTime,
 	})
 
 	//PRE-CHAOS APPLICATION STATUS CHECK
@@ -90,7 +92,7 @@ func NodeTaint(clients clients.ClientSets) {
 
 	// Checking the status of target nodes
 	log.Info("[Status]: Getting the status of target nodes")
-	err = status.CheckNodeStatus(experimentsDetails.TargetNode, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
+	err = status.CheckNodeStatus(experimentsDetails.TargetNodes, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
 	if err != nil {
 		log.Errorf("Target nodes are not in the ready state, err: %v", err)
 		failStep := "Checking the status of nodes"
@@ -62,10 +62,12 @@ func PodAutoscaler(clients clients.ClientSets) {
 
 	//DISPLAY THE APP INFORMATION
 	log.InfoWithValues("The application informations are as follows", logrus.Fields{
-		"Namespace": experimentsDetails.AppNS,
-		"AppKind":   experimentsDetails.AppKind,
-		"Label":     experimentsDetails.AppLabel,
-		"Ramp Time": experimentsDetails.RampTime,
+		"Namespace":    experimentsDetails.AppNS,
+		"AppKind":      experimentsDetails.AppKind,
+		"Label":        experimentsDetails.AppLabel,
+		"Ramp Time":    experimentsDetails.RampTime,
+		"Replicas":     experimentsDetails.Replicas,
+		"WaitForNodes": experimentsDetails.WaitForNodes,
 	})
 
 	// Calling AbortWatcher go routine, it will continuously watch for the abort signal and generate the required events and result
@@ -12,7 +12,6 @@ import (
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/litmuschaos/litmus-go/pkg/probe"
 	"github.com/litmuschaos/litmus-go/pkg/result"
-	"github.com/litmuschaos/litmus-go/pkg/status"
 	"github.com/litmuschaos/litmus-go/pkg/types"
 	"github.com/sirupsen/logrus"
 )
@@ -21,6 +20,7 @@ import (
 func KafkaBrokerPodFailure(clients clients.ClientSets) {
 
 	var err error
+	var liveness *kafka.Liveness
 	experimentsDetails := experimentTypes.ExperimentDetails{}
 	resultDetails := types.ResultDetails{}
 	eventsDetails := types.EventDetails{}
@@ -106,18 +106,35 @@ func KafkaBrokerPodFailure(clients clients.ClientSets) {
 	// PRE-CHAOS KAFKA APPLICATION LIVENESS CHECK
 	switch strings.ToLower(experimentsDetails.KafkaLivenessStream) {
 	case "enabled":
-		livenessTopicLeader, err := kafka.LivenessStream(&experimentsDetails, clients)
+		liveness, err = kafka.LivenessStream(&experimentsDetails, clients)
 		if err != nil {
 			log.Errorf("Liveness check failed, err: %v", err)
 			failStep := "Verify liveness check (pre-chaos)"
 			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
 			return
 		}
-		log.Info("The Liveness pod gets established")
-		log.Infof("[Info]: Kafka partition leader is %v", livenessTopicLeader)
+		log.Info("The Liveness stream gets established")
+		log.Infof("[Info]: Kafka partition leader is %v", liveness.Leader)
+	}
 
-		if experimentsDetails.KafkaBroker == "" {
-			experimentsDetails.KafkaBroker = livenessTopicLeader
+	// deriving the target brokers as per the kafka chaos mode, if not provided
+	if experimentsDetails.KafkaBroker == "" {
+		experimentsDetails.KafkaBroker, err = kafka.GetTargetBrokers(&experimentsDetails, liveness, clients)
+		if err != nil {
+			log.Errorf("Unable to derive the target brokers, err: %v", err)
+			failStep := "Derive the target kafka brokers (pre-chaos)"
+			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+			return
+		}
+	}
+
+	// recording the lag of the consumer group, which should recover after the chaos
+	if experimentsDetails.KafkaConsumerGroup != "" {
+		if err = kafka.RecordConsumerLagBaseline(&experimentsDetails); err != nil {
+			log.Errorf("Consumer group lag check failed, err: %v", err)
+			failStep := "Record the consumer group lag (pre-chaos)"
+			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+			return
 		}
 	}
 
@@ -150,6 +167,26 @@ func KafkaBrokerPodFailure(clients clients.ClientSets) {
 		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
 		return
 	}
+
+	// POST-CHAOS KAFKA PARTITIONS & CONSUMER GROUP CHECK
+	if kafka.IsClusterConfigured(&experimentsDetails) {
+		log.Info("[Status]: Verify that no partitions are offline or under-replicated(post-chaos)")
+		if err = kafka.ReplicationHealthCheck(&experimentsDetails); err != nil {
+			log.Errorf("Replication health check failed, err: %v", err)
+			failStep := "Verify that no partitions are offline or under-replicated(post-chaos)"
+			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+			return
+		}
+	}
+	if experimentsDetails.KafkaConsumerGroup != "" {
+		log.Info("[Status]: Verify that the consumer group lag is recovered(post-chaos)")
+		if err = kafka.ConsumerLagCheck(&experimentsDetails); err != nil {
+			log.Errorf("Consumer group lag check failed, err: %v", err)
+			failStep := "Verify that the consumer group lag is recovered(post-chaos)"
+			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+			return
+		}
+	}
 	if experimentsDetails.ChaoslibDetail.EngineName != "" {
 		// marking AUT as running, as we already checked the status of application under test
 		msg := "AUT: Running"
@@ -177,16 +214,17 @@ func KafkaBrokerPodFailure(clients clients.ClientSets) {
 	// Liveness Status Check (post-chaos) and cleanup
 	switch strings.ToLower(experimentsDetails.KafkaLivenessStream) {
 	case "enabled":
-		log.Info("[Status]: Verify that the Kafka liveness pod is running(post-chaos)")
-		if err = status.CheckApplicationStatus(experimentsDetails.ChaoslibDetail.AppNS, "name=kafka-liveness-"+experimentsDetails.RunID, experimentsDetails.ChaoslibDetail.Timeout, experimentsDetails.ChaoslibDetail.Delay, clients); err != nil {
+		log.Info("[Status]: Verify that the Kafka liveness stream is recovered(post-chaos)")
+		if err = kafka.LivenessCheck(&experimentsDetails, liveness, &resultDetails); err != nil {
 			log.Errorf("Application liveness status check failed, err: %v", err)
-			failStep := "Verify that the liveness pod is running (post-chaos)"
+			kafka.LivenessCleanup(&experimentsDetails, liveness)
+			failStep := "Verify that the liveness stream is recovered (post-chaos)"
 			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
 			return
 		}
 
-		log.Info("[CleanUp]: Deleting the kafka liveness pod(post-chaos)")
-		if err := kafka.LivenessCleanup(&experimentsDetails, clients); err != nil {
+		log.Info("[CleanUp]: Deleting the kafka liveness topic(post-chaos)")
+		if err := kafka.LivenessCleanup(&experimentsDetails, liveness); err != nil {
 			log.Errorf("liveness cleanup failed, err: %v", err)
 			failStep := "Performing liveness pod cleanup (post-chaos)"
 			result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
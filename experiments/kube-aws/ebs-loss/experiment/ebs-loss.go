@@ -26,7 +26,7 @@ func EBSLoss(clients clients.ClientSets) {
 
 	//Fetching all the ENV passed from the runner pod
 	log.Infof("[PreReq]: Getting the ENV for the %v experiment", experimentsDetails.ExperimentName)
-	experimentEnv.GetENV(&experimentsDetails)
+	experimentEnv.GetENV(&experimentsDetails, "ebs-loss")
 
 	// Intialise the chaos attributes
 	experimentEnv.InitialiseChaosVariables(&chaosDetails, &experimentsDetails)
@@ -65,8 +65,24 @@ func EBSLoss(clients clients.ClientSets) {
 		"App Namespace": experimentsDetails.AppNS,
 		"AppLabel":      experimentsDetails.AppLabel,
 		"Ramp Time":     experimentsDetails.RampTime,
+		"Volume ID":     experimentsDetails.EBSVolumeID,
+		"Sequence":      experimentsDetails.Sequence,
 	})
 
+	// Creating the aws provider for the given region and verifying its credentials before any aws call of the experiment
+	provider, err := aws.NewVerifiedProvider(aws.Config{
+		Region:        experimentsDetails.Region,
+		AssumeRoleARN: experimentsDetails.AssumeRoleARN,
+		ExternalID:    experimentsDetails.ExternalID,
+		EndpointURL:   experimentsDetails.EndpointURL,
+	})
+	if err != nil {
+		log.Errorf("failed to create the aws provider, err: %v", err)
+		failStep := "Create the aws provider and verify the aws credentials (pre-chaos)"
+		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+		return
+	}
+
 	//PRE-CHAOS APPLICATION STATUS CHECK
 	log.Info("[Status]: Verify that the AUT (Application Under Test) is running (pre-chaos)")
 	if err = status.AUTStatusCheck(experimentsDetails.AppNS, experimentsDetails.AppLabel, experimentsDetails.TargetContainer, experimentsDetails.Timeout, experimentsDetails.Delay, clients, &chaosDetails); err != nil {
@@ -113,8 +129,8 @@ func EBSLoss(clients clients.ClientSets) {
 	}
 
 	//Verify the aws ec2 instance is attached to ebs volume
-	EBSStatus, err := aws.GetEBSStatus(&experimentsDetails)
-	if err != nil || EBSStatus != "attached" {
+	err = litmusLIB.EBSStateCheck(litmusLIB.GetVolumeListByID(experimentsDetails.EBSVolumeID), provider)
+	if err != nil {
 		log.Errorf("failed to verify the ebs volume is attached to an ec2 instance, err: %v", err)
 		failStep := "Verify the ebs volume is attached to an ec2 instance (pre-chaos)"
 		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
@@ -123,7 +139,7 @@ func EBSLoss(clients clients.ClientSets) {
 
 	// Including the litmus lib for ebs-loss
 	if experimentsDetails.ChaosLib == "litmus" {
-		err = litmusLIB.InjectEBSLoss(&experimentsDetails, clients, &resultDetails, &eventsDetails, &chaosDetails)
+		err = litmusLIB.PrepareEBSLossByID(&experimentsDetails, clients, provider, &resultDetails, &eventsDetails, &chaosDetails)
 		if err != nil {
 			log.Errorf("Chaos injection failed, err: %v", err)
 			failStep := "failed in chaos injection phase"
@@ -161,8 +177,8 @@ func EBSLoss(clients clients.ClientSets) {
 	}
 
 	//Verify the aws ec2 instance is attached to ebs volume
-	EBSStatus, err = aws.GetEBSStatus(&experimentsDetails)
-	if err != nil || EBSStatus != "attached" {
+	err = litmusLIB.EBSStateCheck(litmusLIB.GetVolumeListByID(experimentsDetails.EBSVolumeID), provider)
+	if err != nil {
 		log.Errorf("failed to verify the ebs volume is attached to an ec2 instance, err: %v", err)
 		failStep := "Verify the ebs volume is attached to an ec2 instance (post-chaos)"
 		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
@@ -3,6 +3,7 @@ package experiment
 import (
 	litmusLIB "github.com/litmuschaos/litmus-go/chaoslib/litmus/ec2-terminate-by-id/lib"
 	clients "github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
 	"github.com/litmuschaos/litmus-go/pkg/cloud/aws"
 	"github.com/litmuschaos/litmus-go/pkg/events"
 	experimentEnv "github.com/litmuschaos/litmus-go/pkg/kube-aws/ec2-terminate-by-id/environment"
@@ -65,9 +66,23 @@ func EC2TerminateByID(clients clients.ClientSets) {
 		"Sequence":        experimentsDetails.Sequence,
 	})
 
+	// Creating the aws provider for the given region and verifying its credentials before any aws call of the experiment
+	provider, err := aws.NewVerifiedProvider(aws.Config{
+		Region:        experimentsDetails.Region,
+		AssumeRoleARN: experimentsDetails.AssumeRoleARN,
+		ExternalID:    experimentsDetails.ExternalID,
+		EndpointURL:   experimentsDetails.EndpointURL,
+	})
+	if err != nil {
+		log.Errorf("failed to create the aws provider, err: %v", err)
+		failStep := "Create the aws provider and verify the aws credentials (pre-chaos)"
+		result.RecordAfterFailure(&chaosDetails, &resultDetails, failStep, clients, &eventsDetails)
+		return
+	}
+
 	//PRE-CHAOS NODE STATUS CHECK
 	if experimentsDetails.ManagedNodegroup == "enable" {
-		activeNodeCount, err = aws.PreChaosNodeStatusCheck(experimentsDetails.Timeout, experimentsDetails.Delay, clients)
+		activeNodeCount, err = cloud.PreChaosNodeStatusCheck(experimentsDetails.Timeout, experimentsDetails.Delay, clients)
 		if err != nil {
 			log.Errorf("Pre chaos node status check failed, err: %v", err)
 			failStep := "Verify that the NUT (Node Under Test) is running (pre-chaos)"
@@ -127,7 +142,7 @@ func EC2TerminateByID(clients clients.ClientSets) {
 	}
 
 	//Verify the aws ec2 instance is running (pre chaos)
-	err = litmusLIB.InstanceStatusCheckByID(&experimentsDetails)
+	err = litmusLIB.InstanceStatusCheckByID(&experimentsDetails, provider)
 	if err != nil {
 		log.Errorf("failed to get the ec2 instance status, err: %v", err)
 		failStep := "Verify the AWS ec2 instance status (pre-chaos)"
@@ -138,7 +153,7 @@ func EC2TerminateByID(clients clients.ClientSets) {
 
 	// Including the litmus lib for ec2-terminate
 	if experimentsDetails.ChaosLib == "litmus" {
-		err = litmusLIB.PrepareEC2TerminateByID(&experimentsDetails, clients, &resultDetails, &eventsDetails, &chaosDetails)
+		err = litmusLIB.PrepareEC2TerminateByID(&experimentsDetails, clients, provider, &resultDetails, &eventsDetails, &chaosDetails)
 		if err != nil {
 			log.Errorf("Chaos injection failed, err: %v", err)
 			failStep := "failed in chaos injection phase"
@@ -156,7 +171,7 @@ func EC2TerminateByID(clients clients.ClientSets) {
 
 	// POST-CHAOS ACTIVE NODE COUNT TEST
 	if experimentsDetails.ManagedNodegroup == "enable" {
-		err = aws.PostChaosActiveNodeCountCheck(activeNodeCount, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
+		err = cloud.PostChaosActiveNodeCountCheck(activeNodeCount, experimentsDetails.Timeout, experimentsDetails.Delay, clients)
 		if err != nil {
 			log.Errorf("Post chaos active node count check failed, err: %v", err)
 			failStep := "Verify active number of nodes post chaos"
@@ -167,7 +182,7 @@ func EC2TerminateByID(clients clients.ClientSets) {
 
 	//Verify the aws ec2 instance is running (post chaos)
 	if experimentsDetails.ManagedNodegroup != "enable" {
-		err = litmusLIB.InstanceStatusCheckByID(&experimentsDetails)
+		err = litmusLIB.InstanceStatusCheckByID(&experimentsDetails, provider)
 		if err != nil {
 			log.Errorf("failed to get the ec2 instance status, err: %v", err)
 			failStep := "Verify the AWS ec2 instance status (post-chaos)"
@@ -0,0 +1,105 @@
+// Package testutil contains the fixtures shared by the unit tests of the chaoslibs
+// the chaoslibs are run against the fake api server of pkg/clients/fake
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/clients/fake"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// the attributes of the test experiments, which target the nginx pods of the default namespace
+const (
+	ChaosNamespace = "litmus"
+	AppNamespace   = "default"
+	AppLabel       = "app=nginx"
+	// Timeout & Delay are in the milliseconds, see ChaosDetails
+	Timeout = 10
+	Delay   = 1
+)
+
+// NewTestClientSets starts the fake api server with the given objects, it fails the test if the server can't be started
+// the server should be closed by the test
+func NewTestClientSets(t *testing.T, objects ...runtime.Object) (clients.ClientSets, *fake.Server) {
+
+	clientSets, server, err := fake.NewClientSets(objects...)
+	if err != nil {
+		t.Fatalf("failed to create the fake clients, err: %v", err)
+	}
+	return clientSets, server
+}
+
+// ChaosDetails returns the chaos details of the test experiment targeting the nginx pods, the chaos duration is zero
+// the helper pods are awaited in the milliseconds, the cloud status checks are delayed in milliseconds by the fake provider
+func ChaosDetails(experimentName string) *types.ChaosDetails {
+	return &types.ChaosDetails{
+		ExperimentName: experimentName,
+		ChaosNamespace: ChaosNamespace,
+		Timeout:        Timeout,
+		Delay:          Delay,
+		DelayUnit:      time.Millisecond,
+		AppDetail: types.AppDetails{
+			Namespace: AppNamespace,
+			Label:     AppLabel,
+		},
+	}
+}
+
+// AppPod returns the running nginx pod with the given name, scheduled on the given node
+// it contains the given containers, or a single nginx container if none is given
+func AppPod(name, nodeName string, containers ...string) *apiv1.Pod {
+
+	if len(containers) == 0 {
+		containers = []string{"nginx"}
+	}
+	pod := &apiv1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: AppNamespace, Labels: map[string]string{"app": "nginx"}},
+		Spec:       apiv1.PodSpec{NodeName: nodeName},
+		Status:     apiv1.PodStatus{Phase: apiv1.PodRunning},
+	}
+	for _, container := range containers {
+		pod.Spec.Containers = append(pod.Spec.Containers, apiv1.Container{Name: container, Image: container})
+	}
+	return pod
+}
+
+// RecordPods returns a pod hook, which appends the created pods to the given list before passing them to the hook
+func RecordPods(pods *[]apiv1.Pod, hook func(pod *apiv1.Pod)) func(pod *apiv1.Pod) {
+	return func(pod *apiv1.Pod) {
+		hook(pod)
+		*pods = append(*pods, *pod)
+	}
+}
+
+// AssertHelperPods verifies that a privileged helper pod, running in the host pid namespace, is pinned to each of the nodes
+// and that the helper pods are deleted once the chaos is completed
+func AssertHelperPods(t *testing.T, server *fake.Server, helpers []apiv1.Pod, nodes ...string) {
+
+	if len(helpers) != len(nodes) {
+		t.Fatalf("expected %v helper pods, got %v", len(nodes), len(helpers))
+	}
+	pinned := map[string]bool{}
+	for _, pod := range helpers {
+		pinned[pod.Spec.NodeName] = true
+		if !pod.Spec.HostPID {
+			t.Errorf("expected the %v helper pod to run in the host pid namespace", pod.Name)
+		}
+		if len(pod.Spec.Containers) == 0 || pod.Spec.Containers[0].SecurityContext == nil || pod.Spec.Containers[0].SecurityContext.Privileged == nil || !*pod.Spec.Containers[0].SecurityContext.Privileged {
+			t.Errorf("expected the %v helper pod to be privileged", pod.Name)
+		}
+		if found, _ := server.Get("/api/v1/namespaces/"+ChaosNamespace+"/pods", pod.Name, &apiv1.Pod{}); found {
+			t.Errorf("expected the %v helper pod to be deleted", pod.Name)
+		}
+	}
+	for _, node := range nodes {
+		if !pinned[node] {
+			t.Errorf("expected a helper pod to be pinned to the %v node, got %v", node, pinned)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package environment
+
+import (
+	"os"
+	"strconv"
+
+	clientTypes "k8s.io/apimachinery/pkg/types"
+
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/azure/azure-instance-stop/types"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+)
+
+// GetENV fetches all the env variables from the runner pod
+func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
+	experimentDetails.ExperimentName = Getenv("EXPERIMENT_NAME", "azure-instance-stop")
+	experimentDetails.ChaosNamespace = Getenv("CHAOS_NAMESPACE", "litmus")
+	experimentDetails.EngineName = Getenv("CHAOSENGINE", "")
+	experimentDetails.AppNS = Getenv("APP_NAMESPACE", "")
+	experimentDetails.AppLabel = Getenv("APP_LABEL", "")
+	experimentDetails.AppKind = Getenv("APP_KIND", "")
+	experimentDetails.AuxiliaryAppInfo = Getenv("AUXILIARY_APPINFO", "")
+	experimentDetails.ChaosDuration, _ = strconv.Atoi(Getenv("TOTAL_CHAOS_DURATION", "30"))
+	experimentDetails.ChaosInterval, _ = strconv.Atoi(Getenv("CHAOS_INTERVAL", "30"))
+	experimentDetails.RampTime, _ = strconv.Atoi(Getenv("RAMP_TIME", "0"))
+	experimentDetails.ChaosLib = Getenv("LIB", "litmus")
+	experimentDetails.ChaosUID = clientTypes.UID(Getenv("CHAOS_UID", ""))
+	experimentDetails.InstanceID = Getenv("INSTANCE_ID", "")
+	experimentDetails.ChaosPodName = Getenv("POD_NAME", "")
+	experimentDetails.Delay, _ = strconv.Atoi(Getenv("STATUS_CHECK_DELAY", "2"))
+	experimentDetails.Timeout, _ = strconv.Atoi(Getenv("STATUS_CHECK_TIMEOUT", "180"))
+	experimentDetails.SubscriptionID = Getenv("AZURE_SUBSCRIPTION_ID", "")
+	experimentDetails.TenantID = Getenv("AZURE_TENANT_ID", "")
+	experimentDetails.ClientID = Getenv("AZURE_CLIENT_ID", "")
+	experimentDetails.ClientSecret = Getenv("AZURE_CLIENT_SECRET", "")
+	experimentDetails.FederatedTokenFile = Getenv("AZURE_FEDERATED_TOKEN_FILE", "")
+	experimentDetails.AuthFile = Getenv("AZURE_AUTH_LOCATION", "")
+	experimentDetails.EndpointURL = Getenv("AZURE_ENDPOINT_URL", "")
+	experimentDetails.AzureInstanceNames = Getenv("AZURE_INSTANCE_NAMES", "")
+	experimentDetails.ResourceGroup = Getenv("RESOURCE_GROUP", "")
+	experimentDetails.ScaleSet = Getenv("SCALE_SET", "disable")
+	experimentDetails.InstanceTag = Getenv("INSTANCE_TAG", "")
+	experimentDetails.InstanceAffectedPerc, _ = strconv.Atoi(Getenv("INSTANCE_AFFECTED_PERC", "0"))
+	experimentDetails.Sequence = Getenv("SEQUENCE", "parallel")
+	experimentDetails.TargetContainer = Getenv("TARGET_CONTAINER", "")
+}
+
+// Getenv fetch the env and set the default value, if any
+func Getenv(key string, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		value = defaultValue
+	}
+	return value
+}
+
+// InitialiseChaosVariables initialise all the global variables
+func InitialiseChaosVariables(chaosDetails *types.ChaosDetails, experimentDetails *experimentTypes.ExperimentDetails) {
+
+	chaosDetails.ChaosNamespace = experimentDetails.ChaosNamespace
+	chaosDetails.ChaosPodName = experimentDetails.ChaosPodName
+	chaosDetails.ChaosUID = experimentDetails.ChaosUID
+	chaosDetails.EngineName = experimentDetails.EngineName
+	chaosDetails.ExperimentName = experimentDetails.ExperimentName
+	chaosDetails.InstanceID = experimentDetails.InstanceID
+	chaosDetails.Timeout = experimentDetails.Timeout
+	chaosDetails.Delay = experimentDetails.Delay
+	chaosDetails.ProbeImagePullPolicy = experimentDetails.LIBImagePullPolicy
+}
@@ -0,0 +1,41 @@
+package types
+
+import (
+	clientTypes "k8s.io/apimachinery/pkg/types"
+)
+
+// ExperimentDetails is for collecting all the experiment-related details
+type ExperimentDetails struct {
+	ExperimentName       string
+	EngineName           string
+	RampTime             int
+	AppNS                string
+	AppLabel             string
+	AppKind              string
+	AuxiliaryAppInfo     string
+	ChaosLib             string
+	ChaosDuration        int
+	ChaosInterval        int
+	ChaosUID             clientTypes.UID
+	InstanceID           string
+	ChaosNamespace       string
+	ChaosPodName         string
+	Timeout              int
+	Delay                int
+	SubscriptionID       string
+	TenantID             string
+	ClientID             string
+	ClientSecret         string
+	FederatedTokenFile   string
+	AuthFile             string
+	EndpointURL          string
+	AzureInstanceNames   string
+	ResourceGroup        string
+	ScaleSet             string
+	InstanceTag          string
+	InstanceAffectedPerc int
+	Sequence             string
+	TargetInstanceIDList []string
+	LIBImagePullPolicy   string
+	TargetContainer      string
+}
@@ -0,0 +1,65 @@
+package cassandra
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"strconv"
+
+	cassandraclient "github.com/litmuschaos/litmus-go/pkg/cassandra/client"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/cassandra/pod-delete/types"
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newClientConfig returns the connection details of the cassandra cluster for the given contact points
+func newClientConfig(experimentsDetails *experimentTypes.ExperimentDetails, hosts []string) (cassandraclient.Config, error) {
+
+	config := cassandraclient.Config{
+		Hosts:    hosts,
+		Username: experimentsDetails.CassandraUsername,
+		Password: experimentsDetails.CassandraPassword,
+	}
+
+	if experimentsDetails.CassandraTLSEnabled {
+		config.TLS = &tls.Config{InsecureSkipVerify: experimentsDetails.CassandraTLSSkipVerify}
+		if experimentsDetails.CassandraTLSCACert != "" {
+			ca, err := ioutil.ReadFile(experimentsDetails.CassandraTLSCACert)
+			if err != nil {
+				return cassandraclient.Config{}, errors.Errorf("Unable to read the cassandra ca certificate, err: %v", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(ca) {
+				return cassandraclient.Config{}, errors.Errorf("no certificates found in %v", experimentsDetails.CassandraTLSCACert)
+			}
+			config.TLS.RootCAs = pool
+		}
+	}
+	return config, nil
+}
+
+// getContactPoints returns the cassandra service followed by the cassandra pods, as the contact points of the cluster
+func getContactPoints(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) ([]string, error) {
+
+	port := strconv.Itoa(experimentsDetails.CassandraPort)
+	hosts := []string{}
+	if experimentsDetails.CassandraServiceName != "" {
+		hosts = append(hosts, net.JoinHostPort(experimentsDetails.CassandraServiceName+"."+experimentsDetails.ChaoslibDetail.AppNS, port))
+	}
+
+	podList, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaoslibDetail.AppNS).List(metav1.ListOptions{LabelSelector: experimentsDetails.ChaoslibDetail.AppLabel})
+	if err != nil {
+		return nil, errors.Errorf("Failed to get the application pods in %v namespace, err: %v", experimentsDetails.ChaoslibDetail.AppNS, err)
+	}
+	for _, pod := range podList.Items {
+		if pod.Status.PodIP != "" {
+			hosts = append(hosts, net.JoinHostPort(pod.Status.PodIP, port))
+		}
+	}
+	if len(hosts) == 0 {
+		return nil, errors.Errorf("neither CASSANDRA_SVC_NAME is provided nor the cassandra pods are found in %v namespace", experimentsDetails.ChaoslibDetail.AppNS)
+	}
+	return hosts, nil
+}
@@ -0,0 +1,418 @@
+package client
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Config contains the connection details of the cassandra cluster
+type Config struct {
+	// Hosts contains the host:port of the contact points, they are tried in order upon the connection failures
+	Hosts []string
+	// Username & Password are used by the password authenticator, if the authentication is enabled
+	Username string
+	Password string
+	// TLS enables the tls connections to the nodes, if not nil
+	TLS *tls.Config
+	// Timeout is the dial and request timeout
+	Timeout time.Duration
+}
+
+// Consistency is the consistency level of the queries
+type Consistency uint16
+
+// consistency levels of the native protocol
+const (
+	Any         Consistency = 0x0000
+	One         Consistency = 0x0001
+	Two         Consistency = 0x0002
+	Three       Consistency = 0x0003
+	Quorum      Consistency = 0x0004
+	All         Consistency = 0x0005
+	LocalQuorum Consistency = 0x0006
+	EachQuorum  Consistency = 0x0007
+	Serial      Consistency = 0x0008
+	LocalSerial Consistency = 0x0009
+	LocalOne    Consistency = 0x000A
+)
+
+var consistencyNames = map[Consistency]string{
+	Any:         "ANY",
+	One:         "ONE",
+	Two:         "TWO",
+	Three:       "THREE",
+	Quorum:      "QUORUM",
+	All:         "ALL",
+	LocalQuorum: "LOCAL_QUORUM",
+	EachQuorum:  "EACH_QUORUM",
+	Serial:      "SERIAL",
+	LocalSerial: "LOCAL_SERIAL",
+	LocalOne:    "LOCAL_ONE",
+}
+
+func (c Consistency) String() string {
+	if name, ok := consistencyNames[c]; ok {
+		return name
+	}
+	return "UNKNOWN"
+}
+
+// ParseConsistency returns the consistency level of the given name, the name is case insensitive
+func ParseConsistency(name string) (Consistency, error) {
+	for c, n := range consistencyNames {
+		if strings.EqualFold(n, strings.TrimSpace(name)) {
+			return c, nil
+		}
+	}
+	return 0, errors.Errorf("%v consistency level is not supported", name)
+}
+
+// Row maps the names of the columns to their values
+type Row map[string]interface{}
+
+// String returns the value of the column as string, null is returned as the empty string
+func (r Row) String(column string) string {
+	switch v := r[column].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Strings returns the elements of the collection column as strings
+func (r Row) Strings(column string) []string {
+	elems, _ := r[column].([]interface{})
+	values := make([]string, 0, len(elems))
+	for _, elem := range elems {
+		values = append(values, fmt.Sprint(elem))
+	}
+	return values
+}
+
+// Session talks the native protocol to the cassandra cluster over a single connection
+// it is safe for the concurrent use, the queries are serialised
+// the connection is dropped upon the network errors and dialled again on the next query
+type Session struct {
+	config Config
+
+	mu   sync.Mutex
+	conn *conn
+	next int
+}
+
+// conn is the connection to the single cassandra node
+type conn struct {
+	conn    net.Conn
+	addr    string
+	timeout time.Duration
+}
+
+// NewSession connects to the first reachable contact point
+func NewSession(config Config) (*Session, error) {
+
+	if len(config.Hosts) == 0 {
+		return nil, errors.New("no cassandra hosts are provided")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+
+	s := &Session{config: config}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.connect(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Host returns the address of the connected node, it is empty if not connected
+func (s *Session) Host() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn == nil {
+		return ""
+	}
+	return s.conn.addr
+}
+
+// Close closes the connection
+func (s *Session) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conn != nil {
+		s.conn.close()
+		s.conn = nil
+	}
+}
+
+// Exec runs the statement at the given consistency level and discards its rows
+func (s *Session) Exec(stmt string, consistency Consistency, values ...interface{}) error {
+	_, err := s.Query(stmt, consistency, values...)
+	return err
+}
+
+// Query runs the statement at the given consistency level and returns its rows
+// the values are bound to the positional markers of the statement
+func (s *Session) Query(stmt string, consistency Consistency, values ...interface{}) ([]Row, error) {
+
+	e := &encoder{}
+	e.longString(stmt)
+	e.short(uint16(consistency))
+	if len(values) == 0 {
+		e.byte(0)
+	} else {
+		// the values flag
+		e.byte(0x01)
+		e.short(uint16(len(values)))
+		for _, v := range values {
+			b, err := marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			e.bytes(b)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, err := s.connect()
+	if err != nil {
+		return nil, err
+	}
+	opcode, d, err := c.roundTrip(opQuery, e.buf)
+	if err != nil {
+		// the cassandra errors are returned over the healthy connection
+		if _, ok := err.(*Error); !ok {
+			c.close()
+			s.conn = nil
+		}
+		return nil, err
+	}
+	if opcode != opResult {
+		return nil, errors.Errorf("unexpected 0x%02X opcode in response to the query", opcode)
+	}
+	return decodeResult(d)
+}
+
+// connect returns the connection, the contact points are dialled in the round robin order if absent
+func (s *Session) connect() (*conn, error) {
+
+	if s.conn != nil {
+		return s.conn, nil
+	}
+	var lastErr error
+	for i := 0; i < len(s.config.Hosts); i++ {
+		addr := s.config.Hosts[s.next%len(s.config.Hosts)]
+		s.next++
+		c, err := s.dial(addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		s.conn = c
+		return c, nil
+	}
+	return nil, errors.Errorf("no cassandra host is reachable, err: %v", lastErr)
+}
+
+// dial connects to the node, starts the protocol and authenticates if requested
+func (s *Session) dial(addr string) (*conn, error) {
+
+	dialer := &net.Dialer{Timeout: s.config.Timeout}
+	var nc net.Conn
+	var err error
+	if s.config.TLS != nil {
+		nc, err = tls.DialWithDialer(dialer, "tcp", addr, s.config.TLS)
+	} else {
+		nc, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, errors.Errorf("Unable to connect to the %v cassandra node, err: %v", addr, err)
+	}
+
+	c := &conn{conn: nc, addr: addr, timeout: s.config.Timeout}
+	if err := c.startup(s.config); err != nil {
+		c.close()
+		return nil, errors.Errorf("Unable to start the session with the %v cassandra node, err: %v", addr, err)
+	}
+	return c, nil
+}
+
+// startup sends the startup frame, the node either is ready or asks for the authentication
+func (c *conn) startup(config Config) error {
+
+	e := &encoder{}
+	e.stringMap(map[string]string{"CQL_VERSION": "3.0.0"})
+	opcode, d, err := c.roundTrip(opStartup, e.buf)
+	if err != nil {
+		return err
+	}
+
+	switch opcode {
+	case opReady:
+		return nil
+	case opAuthenticate:
+		authenticator := d.string()
+		if config.Username == "" {
+			return errors.Errorf("%v authenticator requires the credentials", authenticator)
+		}
+		// the sasl plain token, understood by the password authenticator
+		e := &encoder{}
+		e.bytes([]byte("\x00" + config.Username + "\x00" + config.Password))
+		opcode, _, err := c.roundTrip(opAuthResponse, e.buf)
+		if err != nil {
+			return err
+		}
+		switch opcode {
+		case opAuthSuccess:
+			return nil
+		case opAuthChallenge:
+			return errors.Errorf("%v authenticator is not supported", authenticator)
+		}
+	}
+	return errors.Errorf("unexpected 0x%02X opcode in response to the startup", opcode)
+}
+
+// roundTrip writes the request frame and reads its response, the error frames are returned as *Error
+func (c *conn) roundTrip(opcode byte, body []byte) (byte, *decoder, error) {
+
+	if c.conn == nil {
+		return 0, nil, errors.New("connection is closed")
+	}
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return 0, nil, err
+	}
+
+	frame := make([]byte, headerSize, headerSize+len(body))
+	frame[0] = protocolVersion
+	// flags & stream id are zero, as the requests are serialised
+	frame[4] = opcode
+	binary.BigEndian.PutUint32(frame[5:], uint32(len(body)))
+	if _, err := c.conn.Write(append(frame, body...)); err != nil {
+		return 0, nil, err
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(c.conn, header); err != nil {
+		return 0, nil, err
+	}
+	if header[0] != responseVersion {
+		return 0, nil, errors.Errorf("unsupported 0x%02X version of the response, the node should support the native protocol v4", header[0])
+	}
+	size := binary.BigEndian.Uint32(header[5:])
+	if size > maxFrameSize {
+		return 0, nil, errors.Errorf("response of %v bytes exceeds the maximum frame size", size)
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(c.conn, buf); err != nil {
+		return 0, nil, err
+	}
+
+	d := &decoder{buf: buf}
+	flags := header[1]
+	// tracing id
+	if flags&0x02 != 0 {
+		d.next(16)
+	}
+	// warnings
+	if flags&0x08 != 0 {
+		for i, n := 0, d.count(int(d.short())); i < n; i++ {
+			d.string()
+		}
+	}
+	// custom payload
+	if flags&0x04 != 0 {
+		for i, n := 0, d.count(int(d.short())); i < n; i++ {
+			d.string()
+			d.bytes()
+		}
+	}
+	if d.err != nil {
+		return 0, nil, d.err
+	}
+
+	if header[4] == opError {
+		code, message := d.int(), d.string()
+		if d.err != nil {
+			return 0, nil, d.err
+		}
+		return opError, nil, &Error{Code: ErrorCode(code), Message: message}
+	}
+	return header[4], d, nil
+}
+
+func (c *conn) close() {
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+}
+
+// decodeResult decodes the result frame, only the rows results have the rows
+func decodeResult(d *decoder) ([]Row, error) {
+
+	switch kind := d.int(); kind {
+	case resultVoid, resultSetKeyspace, resultSchemaChange:
+		return nil, d.err
+	case resultRows:
+	default:
+		return nil, errors.Errorf("unexpected %v kind of the result", kind)
+	}
+
+	flags := d.int()
+	columnCount := d.count(int(d.int()))
+	if flags&flagHasMorePages != 0 {
+		// paging state, the queries are not paged
+		d.bytes()
+	}
+	if flags&flagNoMetadata != 0 {
+		return nil, errors.New("rows result has no metadata")
+	}
+	if flags&flagGlobalTablesSpec != 0 {
+		// keyspace and table of the columns
+		d.string()
+		d.string()
+	}
+	names := make([]string, 0, columnCount)
+	types := make([]TypeInfo, 0, columnCount)
+	for i := 0; i < columnCount; i++ {
+		if flags&flagGlobalTablesSpec == 0 {
+			d.string()
+			d.string()
+		}
+		names = append(names, d.string())
+		types = append(types, d.typeInfo())
+	}
+
+	rows := []Row{}
+	for i, n := 0, d.count(int(d.int())); i < n; i++ {
+		row := Row{}
+		for j := 0; j < columnCount; j++ {
+			value, err := unmarshal(types[j], d.bytes())
+			if err != nil {
+				return nil, errors.Errorf("Unable to decode the %v column, err: %v", names[j], err)
+			}
+			row[names[j]] = value
+		}
+		rows = append(rows, row)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+	return rows, nil
+}
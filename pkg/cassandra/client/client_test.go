@@ -0,0 +1,287 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// exchange is the request frame expected by the fake node and the response frame sent back
+// the frames are hand encoded as per the native protocol v4 spec, the fields are separated by the spaces
+// the length of the body is omitted from the header, it is inserted by the fake node
+type exchange struct {
+	request  string
+	response string
+}
+
+// text returns the hex encoding of the string, to spell the strings inside the frames
+func text(s string) string {
+	return hex.EncodeToString([]byte(s))
+}
+
+// decodeHex decodes the hex encoded bytes, ignoring the spaces
+func decodeHex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		t.Fatalf("invalid hex %v, err: %v", s, err)
+	}
+	return b
+}
+
+// frame decodes the hex encoded frame and inserts the length of its body after the header
+func frame(t *testing.T, s string) []byte {
+	b := decodeHex(t, s)
+	if len(b) < headerSize-4 {
+		t.Fatalf("frame %v is shorter than the header", s)
+	}
+	length := make([]byte, 4)
+	binary.BigEndian.PutUint32(length, uint32(len(b)-(headerSize-4)))
+	return append(append(append([]byte{}, b[:headerSize-4]...), length...), b[headerSize-4:]...)
+}
+
+// fakeNode serves the exchanges in order over the server side of the connection
+// every request is compared byte for byte with the expected one, before sending the response
+func fakeNode(t *testing.T, conn net.Conn, exchanges []exchange) <-chan struct{} {
+
+	var requests, responses [][]byte
+	for _, ex := range exchanges {
+		requests = append(requests, frame(t, ex.request))
+		responses = append(responses, frame(t, ex.response))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer conn.Close()
+		for i := range requests {
+			header := make([]byte, headerSize)
+			if _, err := io.ReadFull(conn, header); err != nil {
+				t.Errorf("request %v is not received, err: %v", i+1, err)
+				return
+			}
+			request := append(header, make([]byte, binary.BigEndian.Uint32(header[5:]))...)
+			if _, err := io.ReadFull(conn, request[headerSize:]); err != nil {
+				t.Errorf("request %v is truncated, err: %v", i+1, err)
+				return
+			}
+			if !bytes.Equal(request, requests[i]) {
+				t.Errorf("unexpected request %v\ngot:  %x\nwant: %x", i+1, request, requests[i])
+			}
+			if _, err := conn.Write(responses[i]); err != nil {
+				t.Errorf("unable to send the response %v, err: %v", i+1, err)
+				return
+			}
+		}
+	}()
+	return done
+}
+
+// newTestSession returns the session connected to the fake node serving the exchanges
+// the returned func closes the session and waits till all the exchanges are served
+func newTestSession(t *testing.T, exchanges ...exchange) (*Session, func()) {
+
+	client, server := net.Pipe()
+	done := fakeNode(t, server, exchanges)
+	s := &Session{
+		config: Config{Hosts: []string{"cassandra-0:9042"}, Timeout: 5 * time.Second},
+		conn:   &conn{conn: client, addr: "cassandra-0:9042", timeout: 5 * time.Second},
+	}
+	return s, func() {
+		s.Close()
+		client.Close()
+		<-done
+	}
+}
+
+// startupRequest is the startup frame with the CQL_VERSION option
+const startupRequest = "04 00 0000 01" + "0001 000b 43514c5f56455253494f4e 0005 332e302e30"
+
+// the password authenticator requested by the node
+const passwordAuthenticator = "org.apache.cassandra.auth.PasswordAuthenticator"
+
+func TestStartup(t *testing.T) {
+
+	tests := []struct {
+		config    Config
+		exchanges []exchange
+		wantErr   string
+		testName  string
+	}{
+		{
+			exchanges: []exchange{{request: startupRequest, response: "84 00 0000 02"}},
+			testName:  "ready",
+		},
+		{
+			config: Config{Username: "user", Password: "pass"},
+			exchanges: []exchange{
+				{request: startupRequest, response: "84 00 0000 03 002f" + text(passwordAuthenticator)},
+				// the sasl plain token is sent as the bytes, the auth success carries the null token
+				{request: "04 00 0000 0f 0000000a" + text("\x00user\x00pass"), response: "84 00 0000 10 ffffffff"},
+			},
+			testName: "password authentication",
+		},
+		{
+			exchanges: []exchange{{request: startupRequest, response: "84 00 0000 03 002f" + text(passwordAuthenticator)}},
+			wantErr:   "requires the credentials",
+			testName:  "credentials are not provided",
+		},
+		{
+			config: Config{Username: "user", Password: "wrong"},
+			exchanges: []exchange{
+				{request: startupRequest, response: "84 00 0000 03 002f" + text(passwordAuthenticator)},
+				{request: "04 00 0000 0f 0000000b" + text("\x00user\x00wrong"), response: "84 00 0000 00 00000100 0013" + text("Provided user wrong")},
+			},
+			wantErr:  "BAD_CREDENTIALS: Provided user wrong",
+			testName: "bad credentials",
+		},
+		{
+			config: Config{Username: "user", Password: "pass"},
+			exchanges: []exchange{
+				{request: startupRequest, response: "84 00 0000 03 0004" + text("kerb")},
+				{request: "04 00 0000 0f 0000000a" + text("\x00user\x00pass"), response: "84 00 0000 0e 00000002 0102"},
+			},
+			wantErr:  "kerb authenticator is not supported",
+			testName: "auth challenge",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			s, closeSession := newTestSession(t, tt.exchanges...)
+			defer closeSession()
+
+			err := s.conn.startup(tt.config)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unable to start the session, err: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected the %v error, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestQueryRows(t *testing.T) {
+
+	stmt := "SELECT key, value, tags FROM litmus.liveness WHERE key = ?"
+	s, closeSession := newTestSession(t, exchange{
+		// query: the statement, QUORUM consistency, the values flag and the bound values [k1]
+		request: "04 00 0000 07" +
+			"0000003a" + text(stmt) + "0004 01 0001 00000002" + text("k1"),
+		// rows result with the global table spec litmus.liveness, columns [key varchar, value int, tags set<varchar>]
+		// rows [[k1, 42, {a, b}], [k2, null, null]]
+		response: "84 00 0000 08" +
+			"00000002 00000001 00000003 0006" + text("litmus") + "0008" + text("liveness") +
+			"0003" + text("key") + "000d" +
+			"0005" + text("value") + "0009" +
+			"0004" + text("tags") + "0022 000d" +
+			"00000002" +
+			"00000002" + text("k1") + "00000004 0000002a 0000000e 00000002 00000001 61 00000001 62" +
+			"00000002" + text("k2") + "ffffffff ffffffff",
+	})
+	defer closeSession()
+
+	rows, err := s.Query(stmt, Quorum, "k1")
+	if err != nil {
+		t.Fatalf("unable to run the query, err: %v", err)
+	}
+	want := []Row{
+		{"key": "k1", "value": int64(42), "tags": []interface{}{"a", "b"}},
+		{"key": "k2", "value": nil, "tags": nil},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got %v, want %v", rows, want)
+	}
+	if got := rows[0].Strings("tags"); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("tags: got %v, want [a b]", got)
+	}
+	if got := rows[1].String("value"); got != "" {
+		t.Errorf("null value: got %q, want empty", got)
+	}
+}
+
+func TestQueryFlags(t *testing.T) {
+
+	s, closeSession := newTestSession(t,
+		exchange{
+			// no values, the consistency is followed by the empty flags
+			request: "04 00 0000 07 0000001c" + text("INSERT INTO t (k) VALUES (1)") + "0001 00",
+			// the warnings precede the void result
+			response: "84 08 0000 08 0001 0008" + text("batching") + "00000001",
+		},
+		exchange{
+			request: "04 00 0000 07 0000000f" + text("SELECT k FROM t") + "000a 00",
+			// the tracing id precedes the rows result without the global table spec
+			response: "84 02 0000 08 00112233445566778899aabbccddeeff" +
+				"00000002 00000000 00000001 0002" + text("ks") + "0001" + text("t") + "0001" + text("k") + "0009" +
+				"00000001 00000004 00000001",
+		},
+	)
+	defer closeSession()
+
+	if err := s.Exec("INSERT INTO t (k) VALUES (1)", One); err != nil {
+		t.Errorf("unable to run the query with the warnings, err: %v", err)
+	}
+	rows, err := s.Query("SELECT k FROM t", LocalOne)
+	if err != nil {
+		t.Fatalf("unable to run the traced query, err: %v", err)
+	}
+	if want := []Row{{"k": int64(1)}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("got %v, want %v", rows, want)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+
+	s, closeSession := newTestSession(t,
+		exchange{
+			request: "04 00 0000 07 0000000f" + text("SELECT k FROM t") + "0004 00",
+			// unavailable: message, consistency, required and alive replicas
+			response: "84 00 0000 00 00001000 000f" + text("Cannot achieve!") + "0004 00000002 00000001",
+		},
+		exchange{
+			request: "04 00 0000 07 0000000f" + text("SELECT k FROM t") + "0004 00",
+			// the node answers with the native protocol v3
+			response: "83 00 0000 08",
+		},
+	)
+	defer closeSession()
+
+	_, err := s.Query("SELECT k FROM t", Quorum)
+	cassandraErr, ok := err.(*Error)
+	if !ok || cassandraErr.Code != ErrUnavailable || ErrorName(err) != "UNAVAILABLE" {
+		t.Fatalf("expected the unavailable error, got %v", err)
+	}
+	// the cassandra errors are returned over the healthy connection
+	if s.Host() != "cassandra-0:9042" {
+		t.Error("connection is dropped upon the cassandra error")
+	}
+
+	_, err = s.Query("SELECT k FROM t", Quorum)
+	if err == nil || !strings.Contains(err.Error(), "0x83 version") {
+		t.Errorf("expected the unsupported version error, got %v", err)
+	}
+	if ErrorName(err) != "NETWORK_ERROR" || s.Host() != "" {
+		t.Errorf("connection is not dropped upon the protocol error, err: %v", err)
+	}
+}
+
+func TestParseConsistency(t *testing.T) {
+
+	for name, want := range map[string]Consistency{"quorum": Quorum, " LOCAL_QUORUM ": LocalQuorum, "local_one": LocalOne} {
+		if got, err := ParseConsistency(name); err != nil || got != want {
+			t.Errorf("%q: got %v, %v, want %v", name, got, err, want)
+		}
+	}
+	if _, err := ParseConsistency("most"); err == nil {
+		t.Error("expected an error for the unknown consistency level")
+	}
+}
@@ -0,0 +1,74 @@
+package client
+
+import "fmt"
+
+// ErrorCode is the code of the error returned by the cassandra nodes
+type ErrorCode int32
+
+// error codes of the native protocol
+const (
+	ErrServer          ErrorCode = 0x0000
+	ErrProtocol        ErrorCode = 0x000A
+	ErrBadCredentials  ErrorCode = 0x0100
+	ErrUnavailable     ErrorCode = 0x1000
+	ErrOverloaded      ErrorCode = 0x1001
+	ErrIsBootstrapping ErrorCode = 0x1002
+	ErrTruncate        ErrorCode = 0x1003
+	ErrWriteTimeout    ErrorCode = 0x1100
+	ErrReadTimeout     ErrorCode = 0x1200
+	ErrReadFailure     ErrorCode = 0x1300
+	ErrFunctionFailure ErrorCode = 0x1400
+	ErrWriteFailure    ErrorCode = 0x1500
+	ErrSyntax          ErrorCode = 0x2000
+	ErrUnauthorized    ErrorCode = 0x2100
+	ErrInvalid         ErrorCode = 0x2200
+	ErrConfig          ErrorCode = 0x2300
+	ErrAlreadyExists   ErrorCode = 0x2400
+	ErrUnprepared      ErrorCode = 0x2500
+)
+
+var errorNames = map[ErrorCode]string{
+	ErrServer:          "SERVER_ERROR",
+	ErrProtocol:        "PROTOCOL_ERROR",
+	ErrBadCredentials:  "BAD_CREDENTIALS",
+	ErrUnavailable:     "UNAVAILABLE",
+	ErrOverloaded:      "OVERLOADED",
+	ErrIsBootstrapping: "IS_BOOTSTRAPPING",
+	ErrTruncate:        "TRUNCATE_ERROR",
+	ErrWriteTimeout:    "WRITE_TIMEOUT",
+	ErrReadTimeout:     "READ_TIMEOUT",
+	ErrReadFailure:     "READ_FAILURE",
+	ErrFunctionFailure: "FUNCTION_FAILURE",
+	ErrWriteFailure:    "WRITE_FAILURE",
+	ErrSyntax:          "SYNTAX_ERROR",
+	ErrUnauthorized:    "UNAUTHORIZED",
+	ErrInvalid:         "INVALID",
+	ErrConfig:          "CONFIG_ERROR",
+	ErrAlreadyExists:   "ALREADY_EXISTS",
+	ErrUnprepared:      "UNPREPARED",
+}
+
+func (c ErrorCode) String() string {
+	if name, ok := errorNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("ERROR_0x%04X", int32(c))
+}
+
+// Error is the error returned by the cassandra node in the error frame
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Code.String() + ": " + e.Message
+}
+
+// ErrorName returns the name of the error code for the cassandra errors, the connection errors are named NETWORK_ERROR
+func ErrorName(err error) string {
+	if e, ok := err.(*Error); ok {
+		return e.Code.String()
+	}
+	return "NETWORK_ERROR"
+}
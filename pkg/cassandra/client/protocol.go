@@ -0,0 +1,350 @@
+package client
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// version of the native protocol used by the client, supported by cassandra 2.2 and later
+const (
+	protocolVersion byte = 0x04
+	responseVersion byte = 0x84
+	headerSize           = 9
+	maxFrameSize         = 256 * 1024 * 1024
+)
+
+// opcodes of the native protocol frames used by the client
+const (
+	opError         byte = 0x00
+	opStartup       byte = 0x01
+	opReady         byte = 0x02
+	opAuthenticate  byte = 0x03
+	opQuery         byte = 0x07
+	opResult        byte = 0x08
+	opAuthChallenge byte = 0x0E
+	opAuthResponse  byte = 0x0F
+	opAuthSuccess   byte = 0x10
+)
+
+// kinds of the result frames
+const (
+	resultVoid         int32 = 0x0001
+	resultRows         int32 = 0x0002
+	resultSetKeyspace  int32 = 0x0003
+	resultPrepared     int32 = 0x0004
+	resultSchemaChange int32 = 0x0005
+)
+
+// flags of the rows metadata
+const (
+	flagGlobalTablesSpec int32 = 0x0001
+	flagHasMorePages     int32 = 0x0002
+	flagNoMetadata       int32 = 0x0004
+)
+
+// ids of the column types
+const (
+	typeCustom    uint16 = 0x0000
+	typeASCII     uint16 = 0x0001
+	typeBigInt    uint16 = 0x0002
+	typeBlob      uint16 = 0x0003
+	typeBoolean   uint16 = 0x0004
+	typeCounter   uint16 = 0x0005
+	typeDecimal   uint16 = 0x0006
+	typeDouble    uint16 = 0x0007
+	typeFloat     uint16 = 0x0008
+	typeInt       uint16 = 0x0009
+	typeTimestamp uint16 = 0x000B
+	typeUUID      uint16 = 0x000C
+	typeVarchar   uint16 = 0x000D
+	typeVarint    uint16 = 0x000E
+	typeTimeUUID  uint16 = 0x000F
+	typeInet      uint16 = 0x0010
+	typeDate      uint16 = 0x0011
+	typeTime      uint16 = 0x0012
+	typeSmallInt  uint16 = 0x0013
+	typeTinyInt   uint16 = 0x0014
+	typeList      uint16 = 0x0020
+	typeMap       uint16 = 0x0021
+	typeSet       uint16 = 0x0022
+	typeUDT       uint16 = 0x0030
+	typeTuple     uint16 = 0x0031
+)
+
+// errShortBuffer is returned when the frame is truncated
+var errShortBuffer = errors.New("cassandra frame is truncated")
+
+// encoder builds the body of the native protocol frames
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) byte(v byte) {
+	e.buf = append(e.buf, v)
+}
+
+func (e *encoder) short(v uint16) {
+	e.buf = append(e.buf, byte(v>>8), byte(v))
+}
+
+func (e *encoder) int(v int32) {
+	e.buf = append(e.buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func (e *encoder) string(v string) {
+	e.short(uint16(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *encoder) longString(v string) {
+	e.int(int32(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// bytes encodes the nil slice as null
+func (e *encoder) bytes(v []byte) {
+	if v == nil {
+		e.int(-1)
+		return
+	}
+	e.int(int32(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *encoder) stringMap(m map[string]string) {
+	e.short(uint16(len(m)))
+	for k, v := range m {
+		e.string(k)
+		e.string(v)
+	}
+}
+
+// decoder parses the body of the native protocol frames
+// the first error is retained and the subsequent reads return zero values
+type decoder struct {
+	buf []byte
+	off int
+	err error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || d.off+n > len(d.buf) {
+		d.err = errShortBuffer
+		return nil
+	}
+	b := d.buf[d.off : d.off+n]
+	d.off += n
+	return b
+}
+
+func (d *decoder) byte() byte {
+	b := d.next(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (d *decoder) short() uint16 {
+	b := d.next(2)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint16(b)
+}
+
+func (d *decoder) int() int32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(b))
+}
+
+func (d *decoder) string() string {
+	return string(d.next(int(d.short())))
+}
+
+// bytes decodes the bytes, null is decoded as nil
+func (d *decoder) bytes() []byte {
+	n := d.int()
+	if n < 0 {
+		return nil
+	}
+	return d.next(int(n))
+}
+
+// count decodes the number of the elements, every element takes at least one byte
+// which guards against the corrupted counts
+func (d *decoder) count(n int) int {
+	if n < 0 || n > len(d.buf)-d.off {
+		if d.err == nil {
+			d.err = errShortBuffer
+		}
+		return 0
+	}
+	return n
+}
+
+// TypeInfo describes the type of the column
+type TypeInfo struct {
+	ID uint16
+	// Elems contains the element types of the collections & tuples, the key & value types of the maps
+	Elems []TypeInfo
+	// Custom is the class of the custom types
+	Custom string
+}
+
+// typeInfo decodes the [option] of the column type
+func (d *decoder) typeInfo() TypeInfo {
+	t := TypeInfo{ID: d.short()}
+	switch t.ID {
+	case typeCustom:
+		t.Custom = d.string()
+	case typeList, typeSet:
+		t.Elems = []TypeInfo{d.typeInfo()}
+	case typeMap:
+		t.Elems = []TypeInfo{d.typeInfo(), d.typeInfo()}
+	case typeUDT:
+		// keyspace and name of the user defined type
+		d.string()
+		d.string()
+		for i, n := 0, d.count(int(d.short())); i < n; i++ {
+			// name of the field
+			d.string()
+			t.Elems = append(t.Elems, d.typeInfo())
+		}
+	case typeTuple:
+		for i, n := 0, d.count(int(d.short())); i < n; i++ {
+			t.Elems = append(t.Elems, d.typeInfo())
+		}
+	}
+	return t
+}
+
+// unmarshal decodes the value of the column as per its type, null is decoded as nil
+// the text types are decoded as string, the integers as int64, the uuids & inets as their string form
+// the collections as the slices (the maps as the slices of the key value pairs), the others are retained as bytes
+func unmarshal(t TypeInfo, b []byte) (interface{}, error) {
+
+	if b == nil {
+		return nil, nil
+	}
+	switch t.ID {
+	case typeASCII, typeVarchar:
+		return string(b), nil
+	case typeBigInt, typeCounter, typeInt, typeSmallInt, typeTinyInt, typeTime:
+		var v int64
+		switch len(b) {
+		case 1:
+			v = int64(int8(b[0]))
+		case 2:
+			v = int64(int16(binary.BigEndian.Uint16(b)))
+		case 4:
+			v = int64(int32(binary.BigEndian.Uint32(b)))
+		case 8:
+			v = int64(binary.BigEndian.Uint64(b))
+		default:
+			return nil, errors.Errorf("invalid length %v of the integer", len(b))
+		}
+		return v, nil
+	case typeVarint:
+		return decodeVarint(b).String(), nil
+	case typeBoolean:
+		return len(b) == 1 && b[0] != 0, nil
+	case typeDouble:
+		if len(b) != 8 {
+			return nil, errors.Errorf("invalid length %v of the double", len(b))
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case typeFloat:
+		if len(b) != 4 {
+			return nil, errors.Errorf("invalid length %v of the float", len(b))
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(b))), nil
+	case typeTimestamp:
+		if len(b) != 8 {
+			return nil, errors.Errorf("invalid length %v of the timestamp", len(b))
+		}
+		ms := int64(binary.BigEndian.Uint64(b))
+		return time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond)).UTC(), nil
+	case typeUUID, typeTimeUUID:
+		if len(b) != 16 {
+			return nil, errors.Errorf("invalid length %v of the uuid", len(b))
+		}
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+	case typeInet:
+		if len(b) != 4 && len(b) != 16 {
+			return nil, errors.Errorf("invalid length %v of the inet", len(b))
+		}
+		return net.IP(b).String(), nil
+	case typeList, typeSet, typeMap:
+		d := &decoder{buf: b}
+		values := []interface{}{}
+		for i, n := 0, d.count(int(d.int())); i < n; i++ {
+			for _, elem := range t.Elems {
+				v, err := unmarshal(elem, d.bytes())
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, v)
+			}
+		}
+		return values, d.err
+	}
+	return b, nil
+}
+
+// decodeVarint decodes the two's complement big endian integer
+func decodeVarint(b []byte) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return v
+}
+
+// marshal encodes the bound value of the query
+// the int64 is encoded as bigint, the int32 & int as int, the time as timestamp
+func marshal(v interface{}) ([]byte, error) {
+
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	case bool:
+		if v {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	case int:
+		return marshal(int32(v))
+	case int32:
+		b := make([]byte, 4)
+		binary.BigEndian.PutUint32(b, uint32(v))
+		return b, nil
+	case int64:
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, uint64(v))
+		return b, nil
+	case float64:
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, math.Float64bits(v))
+		return b, nil
+	case time.Time:
+		return marshal(v.UnixNano() / int64(time.Millisecond))
+	}
+	return nil, errors.Errorf("unsupported type %T of the bound value", v)
+}
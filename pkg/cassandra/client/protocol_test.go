@@ -0,0 +1,147 @@
+package client
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTypeInfo(t *testing.T) {
+
+	tests := []struct {
+		option   string
+		want     TypeInfo
+		testName string
+	}{
+		{option: "000d", want: TypeInfo{ID: typeVarchar}, testName: "varchar"},
+		{option: "0000 0016" + text("o.a.c.d.m.DurationType"), want: TypeInfo{ID: typeCustom, Custom: "o.a.c.d.m.DurationType"}, testName: "custom"},
+		{option: "0021 000d 0020 0009", want: TypeInfo{ID: typeMap, Elems: []TypeInfo{{ID: typeVarchar}, {ID: typeList, Elems: []TypeInfo{{ID: typeInt}}}}}, testName: "map of lists"},
+		{option: "0031 0002 000c 0010", want: TypeInfo{ID: typeTuple, Elems: []TypeInfo{{ID: typeUUID}, {ID: typeInet}}}, testName: "tuple"},
+		{
+			// keyspace, name and the fields [street varchar, zip int]
+			option:   "0030 0006" + text("litmus") + "0007" + text("address") + "0002 0006" + text("street") + "000d 0003" + text("zip") + "0009",
+			want:     TypeInfo{ID: typeUDT, Elems: []TypeInfo{{ID: typeVarchar}, {ID: typeInt}}},
+			testName: "user defined type",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			d := &decoder{buf: decodeHex(t, tt.option)}
+			got := d.typeInfo()
+			if d.err != nil {
+				t.Fatalf("unable to decode the type, err: %v", d.err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if d.off != len(d.buf) {
+				t.Errorf("%v bytes are left", len(d.buf)-d.off)
+			}
+		})
+	}
+
+	t.Run("corrupted count", func(t *testing.T) {
+		d := &decoder{buf: []byte{0x00, 0x31, 0xff, 0xff}}
+		d.typeInfo()
+		if d.err != errShortBuffer {
+			t.Errorf("expected the short buffer error, got %v", d.err)
+		}
+	})
+}
+
+func TestUnmarshal(t *testing.T) {
+
+	tests := []struct {
+		typ      TypeInfo
+		value    string
+		want     interface{}
+		wantErr  bool
+		testName string
+	}{
+		{typ: TypeInfo{ID: typeASCII}, value: text("ok"), want: "ok", testName: "ascii"},
+		{typ: TypeInfo{ID: typeVarchar}, value: "", want: "", testName: "empty varchar"},
+		{typ: TypeInfo{ID: typeBigInt}, value: "fffffffffffffffe", want: int64(-2), testName: "bigint"},
+		{typ: TypeInfo{ID: typeInt}, value: "0000002a", want: int64(42), testName: "int"},
+		{typ: TypeInfo{ID: typeSmallInt}, value: "ff9c", want: int64(-100), testName: "smallint"},
+		{typ: TypeInfo{ID: typeTinyInt}, value: "7f", want: int64(127), testName: "tinyint"},
+		{typ: TypeInfo{ID: typeInt}, value: "000000", wantErr: true, testName: "int of invalid length"},
+		{typ: TypeInfo{ID: typeVarint}, value: "ff7f", want: "-129", testName: "negative varint"},
+		{typ: TypeInfo{ID: typeVarint}, value: "00ff", want: "255", testName: "positive varint"},
+		{typ: TypeInfo{ID: typeBoolean}, value: "01", want: true, testName: "boolean"},
+		{typ: TypeInfo{ID: typeDouble}, value: "3ff8000000000000", want: 1.5, testName: "double"},
+		{typ: TypeInfo{ID: typeFloat}, value: "3fc00000", want: 1.5, testName: "float"},
+		{typ: TypeInfo{ID: typeTimestamp}, value: "0000018bcfe56805", want: time.Unix(1700000000, 5*int64(time.Millisecond)).UTC(), testName: "timestamp"},
+		{typ: TypeInfo{ID: typeUUID}, value: "00112233445566778899aabbccddeeff", want: "00112233-4455-6677-8899-aabbccddeeff", testName: "uuid"},
+		{typ: TypeInfo{ID: typeTimeUUID}, value: "0011", wantErr: true, testName: "timeuuid of invalid length"},
+		{typ: TypeInfo{ID: typeInet}, value: "0a000001", want: "10.0.0.1", testName: "ipv4"},
+		{typ: TypeInfo{ID: typeInet}, value: "fd000000000000000000000000000001", want: "fd00::1", testName: "ipv6"},
+		{typ: TypeInfo{ID: typeBlob}, value: "cafe", want: []byte{0xca, 0xfe}, testName: "blob"},
+		{
+			typ:      TypeInfo{ID: typeMap, Elems: []TypeInfo{{ID: typeVarchar}, {ID: typeInt}}},
+			value:    "00000002 00000001" + text("a") + "00000004 00000001 00000001" + text("b") + "ffffffff",
+			want:     []interface{}{"a", int64(1), "b", nil},
+			testName: "map as the key value pairs",
+		},
+		{typ: TypeInfo{ID: typeList, Elems: []TypeInfo{{ID: typeInt}}}, value: "7fffffff", wantErr: true, testName: "list of corrupted count"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			value := decodeHex(t, tt.value)
+			got, err := unmarshal(tt.typ, value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unable to unmarshal, err: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("null", func(t *testing.T) {
+		if got, err := unmarshal(TypeInfo{ID: typeInt}, nil); got != nil || err != nil {
+			t.Errorf("got %v, %v, want nil", got, err)
+		}
+	})
+}
+
+func TestMarshal(t *testing.T) {
+
+	tests := []struct {
+		value    interface{}
+		want     []byte
+		testName string
+	}{
+		{value: nil, want: nil, testName: "null"},
+		{value: "k1", want: []byte("k1"), testName: "string"},
+		{value: []byte{0xca, 0xfe}, want: []byte{0xca, 0xfe}, testName: "bytes"},
+		{value: true, want: []byte{1}, testName: "bool"},
+		{value: 42, want: []byte{0, 0, 0, 42}, testName: "int as int"},
+		{value: int32(-1), want: []byte{0xff, 0xff, 0xff, 0xff}, testName: "int32"},
+		{value: int64(1), want: []byte{0, 0, 0, 0, 0, 0, 0, 1}, testName: "int64 as bigint"},
+		{value: 1.5, want: []byte{0x3f, 0xf8, 0, 0, 0, 0, 0, 0}, testName: "double"},
+		{value: time.Unix(1700000000, 5*int64(time.Millisecond)), want: []byte{0, 0, 0x01, 0x8b, 0xcf, 0xe5, 0x68, 0x05}, testName: "time as timestamp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			got, err := marshal(tt.value)
+			if err != nil {
+				t.Fatalf("unable to marshal, err: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %x, want %x", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unsupported type", func(t *testing.T) {
+		if _, err := marshal(struct{}{}); err == nil {
+			t.Error("expected an error for the unsupported type")
+		}
+	})
+}
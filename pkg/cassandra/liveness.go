@@ -1,373 +1,322 @@
 package cassandra
 
 import (
-	"io/ioutil"
-	"net/http"
+	"encoding/json"
 	"strconv"
-	"strings"
+	"sync"
 	"time"
 
-	"github.com/pkg/errors"
-
+	cassandraclient "github.com/litmuschaos/litmus-go/pkg/cassandra/client"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/cassandra/pod-delete/types"
 	"github.com/litmuschaos/litmus-go/pkg/clients"
 	"github.com/litmuschaos/litmus-go/pkg/log"
-	"github.com/litmuschaos/litmus-go/pkg/status"
+	"github.com/litmuschaos/litmus-go/pkg/types"
 	"github.com/litmuschaos/litmus-go/pkg/utils/common"
-	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
-	appsv1 "k8s.io/api/apps/v1"
-	apiv1 "k8s.io/api/core/v1"
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
-// LivenessCheck will create an external liveness pod which will continuously check for the liveness of cassandra statefulset
-func LivenessCheck(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (string, error) {
-	var err error
+// livenessInterval is the interval between the writes of the liveness loop
+const livenessInterval = time.Second
+
+// Liveness is the in-process cql client, which continuously writes and reads back the rows
+// at the configured consistency levels, to validate the liveness of the cassandra cluster
+type Liveness struct {
+	// Keyspace is the keyspace of the liveness table
+	Keyspace string
+
+	experimentsDetails *experimentTypes.ExperimentDetails
+	session            *cassandraclient.Session
+	writeConsistency   cassandraclient.Consistency
+	readConsistency    cassandraclient.Consistency
+	replicationFactor  int
+
+	stopCh   chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+
+	mu             sync.Mutex
+	writes         int
+	writeErrors    int
+	reads          int
+	readErrors     int
+	staleReads     int
+	errs           map[string]int
+	succeeded      int
+	lastSuccess    time.Time
+	maxUnavailable time.Duration
+	totalLatency   time.Duration
+	maxLatency     time.Duration
+}
 
-	// Generate the run_id for the liveness pod
-	experimentsDetails.RunID = common.GetRunID()
+// LivenessCheck creates the liveness keyspace & table and starts the liveness loop over them
+func LivenessCheck(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (*Liveness, error) {
 
-	// Creating liveness deployment
-	err = CreateLivenessPod(experimentsDetails, clients)
-	if err != nil {
-		return "", err
+	// Generate the run_id for the liveness keyspace
+	experimentsDetails.RunID = common.GetRunID()
+	liveness := &Liveness{
+		Keyspace:           "litmus_liveness_" + experimentsDetails.RunID,
+		experimentsDetails: experimentsDetails,
+		errs:               map[string]int{},
 	}
 
-	// Creating liveness service
-	err = CreateLivenessService(experimentsDetails, clients)
-	if err != nil {
-		return "", err
+	var err error
+	if liveness.writeConsistency, err = cassandraclient.ParseConsistency(experimentsDetails.WriteConsistency); err != nil {
+		return nil, err
 	}
-
-	// Checking the status of liveness deployment pod
-	log.Info("[Status]: Checking the status of the cassandra liveness pod")
-	// Record cassandra liveness pod resource version
-	if err = status.CheckApplicationStatus(experimentsDetails.ChaoslibDetail.AppNS, "name=cassandra-liveness-deploy-"+experimentsDetails.RunID, experimentsDetails.ChaoslibDetail.Timeout, experimentsDetails.ChaoslibDetail.Delay, clients); err != nil {
-		return "", errors.Errorf("Liveness pod is not in running state, err: %v", err)
+	if liveness.readConsistency, err = cassandraclient.ParseConsistency(experimentsDetails.ReadConsistency); err != nil {
+		return nil, err
 	}
 
-	// Record cassandra liveness pod resource version
-	ResourceVersionBefore, err := GetLivenessPodResourceVersion(experimentsDetails, clients)
+	hosts, err := getContactPoints(experimentsDetails, clients)
 	if err != nil {
-		return ResourceVersionBefore, errors.Errorf("Failed to get the pod resource version, err: %v", err)
+		return nil, err
 	}
-
-	return ResourceVersionBefore, nil
-}
-
-// LivenessCleanup will check the status of liveness pod cycle and wait till the cycle comes to the complete state
-// At last it removes/cleanup the liveness deploy and svc
-func LivenessCleanup(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets, ResourceVersionBefore string) error {
-
-	// Getting ClusterIP
-	log.Info("[CleanUP]: Getting ClusterIP of liveness service")
-	ClusterIP, err := GetServiceClusterIP(experimentsDetails, clients)
+	config, err := newClientConfig(experimentsDetails, hosts)
 	if err != nil {
-		return errors.Errorf("Failed to get the ClusterIP of liveness service, err: %v", err)
+		return nil, err
 	}
-
-	// Record cassandra liveness pod resource version after chaos
-	ResourceVersionAfter, err := GetLivenessPodResourceVersion(experimentsDetails, clients)
-	if err != nil {
-		return errors.Errorf("Failed to get the pod resource version, err: %v", err)
+	if liveness.session, err = cassandraclient.NewSession(config); err != nil {
+		return nil, err
 	}
+	liveness.replicationFactor = getReplicationFactor(experimentsDetails, len(hosts))
 
-	if err = ResourceVersionCheck(ResourceVersionBefore, ResourceVersionAfter); err != nil {
-		return err
+	log.Infof("[Liveness]: Creating the %v cassandra liveness keyspace", liveness.Keyspace)
+	stmt := "CREATE KEYSPACE IF NOT EXISTS " + liveness.Keyspace + " WITH replication = {'class': 'SimpleStrategy', 'replication_factor': " + strconv.Itoa(liveness.replicationFactor) + "}"
+	if err := liveness.session.Exec(stmt, cassandraclient.Quorum); err != nil {
+		liveness.session.Close()
+		return nil, errors.Errorf("Unable to create the liveness keyspace, err: %v", err)
 	}
-
-	if err = WaitTillCycleComplete(experimentsDetails, ClusterIP); err != nil {
-		return errors.Errorf("cycle complete test failed, err: %v", err)
+	if err := liveness.session.Exec("CREATE TABLE IF NOT EXISTS "+liveness.Keyspace+".liveness (seq bigint PRIMARY KEY, value text)", cassandraclient.Quorum); err != nil {
+		LivenessCleanup(experimentsDetails, liveness)
+		return nil, errors.Errorf("Unable to create the liveness table, err: %v", err)
 	}
 
-	if err = DeleteLivenessDeployment(experimentsDetails, clients); err != nil {
-		return errors.Errorf("Liveness deployment deletion failed, err: %v", err)
-	}
-
-	log.Info("[Cleanup]: Cassandra liveness deployment has been deleted successfully")
-
-	if err = DeleteLivenessService(experimentsDetails, clients); err != nil {
-		return errors.Errorf("Liveness service deletion failed, err: %v", err)
+	log.InfoWithValues("[Liveness]: Starting the cassandra liveness loop", logrus.Fields{
+		"Keyspace":          liveness.Keyspace,
+		"ReplicationFactor": liveness.replicationFactor,
+		"WriteConsistency":  liveness.writeConsistency.String(),
+		"ReadConsistency":   liveness.readConsistency.String(),
+	})
+	liveness.stopCh, liveness.done = make(chan struct{}), make(chan struct{})
+	go liveness.run()
+
+	// the schema changes may take a while to reach all the nodes
+	log.Info("[Liveness]: Confirm that the cassandra liveness loop is established")
+	if err := liveness.waitForSuccess(time.Now()); err != nil {
+		LivenessCleanup(experimentsDetails, liveness)
+		return nil, err
 	}
-
-	log.Info("[Cleanup]: Cassandra liveness service has been deleted successfully")
-
-	return nil
+	return liveness, nil
 }
 
-// GetLivenessPodResourceVersion will return the resource version of the liveness pod
-func GetLivenessPodResourceVersion(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (string, error) {
-
-	livenessPods, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaoslibDetail.AppNS).List(metav1.ListOptions{LabelSelector: "name=cassandra-liveness-deploy-" + experimentsDetails.RunID})
-	if err != nil || len(livenessPods.Items) == 0 {
-		return "", errors.Errorf("Unable to get the liveness pod, err: %v", err)
+// getReplicationFactor returns the replication factor of the liveness keyspace
+// it defaults to three, bounded by the number of the contact points
+func getReplicationFactor(experimentsDetails *experimentTypes.ExperimentDetails, nodes int) int {
+	if replicationFactor, err := strconv.Atoi(experimentsDetails.KeySpaceReplicaFactor); err == nil && replicationFactor > 0 {
+		return replicationFactor
 	}
-	ResourceVersion := livenessPods.Items[0].ResourceVersion
-
-	return ResourceVersion, nil
-}
-
-// GetServiceClusterIP will return the cluster IP of the liveness service
-func GetServiceClusterIP(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (string, error) {
-
-	service, err := clients.KubeClient.CoreV1().Services(experimentsDetails.ChaoslibDetail.AppNS).Get("cassandra-liveness-service-"+experimentsDetails.RunID, metav1.GetOptions{})
-	if err != nil {
-		return "", err
+	if experimentsDetails.CassandraServiceName != "" {
+		nodes--
 	}
-
-	return service.Spec.ClusterIP, nil
+	if nodes < 3 && nodes > 0 {
+		return nodes
+	}
+	return 3
 }
 
-// WaitTillCycleComplete will check the status of liveness pod cycle
-// Wait till the cycle come to the complete state
-func WaitTillCycleComplete(experimentsDetails *experimentTypes.ExperimentDetails, ClusterIP string) error {
-
-	port := strconv.Itoa(experimentsDetails.LivenessServicePort)
-	URL := "http://" + ClusterIP + ":" + port
-	log.Infof("The URL to check the status of liveness pod cycle, url: %v", URL)
-
-	return retry.
-		Times(uint(experimentsDetails.ChaoslibDetail.Timeout / experimentsDetails.ChaoslibDetail.Delay)).
-		Wait(time.Duration(experimentsDetails.ChaoslibDetail.Delay) * time.Second).
-		Try(func(attempt uint) error {
-			response, err := http.Get(URL)
-			if err != nil {
-				return errors.Errorf("The HTTP request failed with error %s", err)
+// run writes a new row and reads it back in every interval, until stopped
+func (l *Liveness) run() {
+
+	defer close(l.done)
+	ticker := time.NewTicker(livenessInterval)
+	defer ticker.Stop()
+
+	insert := "INSERT INTO " + l.Keyspace + ".liveness (seq, value) VALUES (?, ?)"
+	query := "SELECT value FROM " + l.Keyspace + ".liveness WHERE seq = ?"
+	for seq := int64(0); ; seq++ {
+		select {
+		case <-l.stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		start := time.Now()
+		value := l.experimentsDetails.RunID + "-" + strconv.FormatInt(start.UnixNano(), 10)
+		err := l.session.Exec(insert, l.writeConsistency, seq, value)
+
+		l.mu.Lock()
+		l.writes++
+		if err != nil {
+			l.writeErrors++
+			l.errs[cassandraclient.ErrorName(err)]++
+			l.mu.Unlock()
+			log.Warnf("[Liveness]: Unable to write the %v row, err: %v", seq, err)
+			continue
+		}
+		l.mu.Unlock()
+
+		rows, err := l.session.Query(query, l.readConsistency, seq)
+
+		l.mu.Lock()
+		l.reads++
+		switch {
+		case err != nil:
+			l.readErrors++
+			l.errs[cassandraclient.ErrorName(err)]++
+			log.Warnf("[Liveness]: Unable to read the %v row, err: %v", seq, err)
+		case len(rows) == 0 || rows[0].String("value") != value:
+			// the acknowledged write is not visible to the read
+			l.staleReads++
+			log.Warnf("[Liveness]: The %v row read at %v consistency is stale", seq, l.readConsistency)
+		default:
+			now := time.Now()
+			latency := now.Sub(start)
+			l.succeeded++
+			l.totalLatency += latency
+			if latency > l.maxLatency {
+				l.maxLatency = latency
 			}
-			data, _ := ioutil.ReadAll(response.Body)
-			if !strings.Contains(string(data), "CycleComplete") {
-				log.Info("[Verification]: Wait for liveness pod to come in CycleComplete state")
-				return errors.Errorf("Livenss pod is not in completed state")
+			if !l.lastSuccess.IsZero() && now.Sub(l.lastSuccess) > l.maxUnavailable {
+				l.maxUnavailable = now.Sub(l.lastSuccess)
 			}
-			log.Info("Liveness pod comes to CycleComplete state")
-			return nil
-		})
+			l.lastSuccess = now
+		}
+		l.mu.Unlock()
+	}
 }
 
-// ResourceVersionCheck compare the resource version for target pods before and after chaos
-func ResourceVersionCheck(ResourceVersionBefore, ResourceVersionAfter string) error {
+// waitForSuccess waits for the row to be written and read back after the given time, within the status check timeout
+func (l *Liveness) waitForSuccess(since time.Time) error {
 
-	if ResourceVersionBefore != ResourceVersionAfter {
-		return errors.Errorf("Resource Version Check failed, Resource version remains same")
+	timeout := time.Duration(l.experimentsDetails.ChaoslibDetail.Timeout) * time.Second
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(livenessInterval) {
+		l.mu.Lock()
+		lastSuccess := l.lastSuccess
+		l.mu.Unlock()
+		if lastSuccess.After(since) {
+			return nil
+		}
 	}
-	log.Info("The cassandra cluster is active")
-
-	return nil
+	return errors.Errorf("no row is written and read back from the %v liveness keyspace within %v", l.Keyspace, timeout)
 }
 
-// DeleteLivenessDeployment deletes the livenes deployments and wait for its termination
-func DeleteLivenessDeployment(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) error {
+// stop stops the liveness loop
+func (l *Liveness) stop() {
+	l.stopOnce.Do(func() {
+		if l.stopCh == nil {
+			return
+		}
+		close(l.stopCh)
+		<-l.done
+	})
+}
 
-	deletePolicy := metav1.DeletePropagationForeground
-	if err := clients.KubeClient.AppsV1().Deployments(experimentsDetails.ChaoslibDetail.AppNS).Delete("cassandra-liveness-deploy-"+experimentsDetails.RunID, &metav1.DeleteOptions{
-		PropagationPolicy: &deletePolicy,
-	}); err != nil {
-		return err
+// Metrics returns the outcome of the liveness loop so far
+func (l *Liveness) Metrics() experimentTypes.LivenessMetrics {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	metrics := experimentTypes.LivenessMetrics{
+		Keyspace:         l.Keyspace,
+		WriteConsistency: l.writeConsistency.String(),
+		ReadConsistency:  l.readConsistency.String(),
+		Writes:           l.writes,
+		WriteErrors:      l.writeErrors,
+		Reads:            l.reads,
+		ReadErrors:       l.readErrors,
+		StaleReads:       l.staleReads,
+		Errors:           map[string]int{},
+		MaxLatency:       float64(l.maxLatency) / float64(time.Millisecond),
+		MaxUnavailable:   l.maxUnavailable.Seconds(),
 	}
-	return retry.
-		Times(uint(experimentsDetails.ChaoslibDetail.Timeout / experimentsDetails.ChaoslibDetail.Delay)).
-		Wait(time.Duration(experimentsDetails.ChaoslibDetail.Delay) * time.Second).
-		Try(func(attempt uint) error {
-			podSpec, err := clients.KubeClient.AppsV1().Deployments(experimentsDetails.ChaoslibDetail.AppNS).List(metav1.ListOptions{LabelSelector: "name=cassandra-liveness-deploy-" + experimentsDetails.RunID})
-			if err != nil || len(podSpec.Items) != 0 {
-				return errors.Errorf("Liveness deployment is not deleted yet, err: %v", err)
-			}
-			return nil
-		})
+	for name, count := range l.errs {
+		metrics.Errors[name] = count
+	}
+	if l.writes != 0 {
+		metrics.WriteSuccessPerc = float64(l.writes-l.writeErrors) * 100 / float64(l.writes)
+	}
+	if l.reads != 0 {
+		metrics.ReadSuccessPerc = float64(l.succeeded) * 100 / float64(l.reads)
+	}
+	if l.succeeded != 0 {
+		metrics.AvgLatency = float64(l.totalLatency) / float64(l.succeeded) / float64(time.Millisecond)
+	}
+	return metrics
 }
 
-// DeleteLivenessService deletes the liveness service and wait for its termination
-func DeleteLivenessService(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) error {
+// isStronglyConsistent returns true if the read & write replicas overlap, so that the reads must see the acknowledged writes
+func (l *Liveness) isStronglyConsistent() bool {
+	return requiredReplicas(l.writeConsistency, l.replicationFactor)+requiredReplicas(l.readConsistency, l.replicationFactor) > l.replicationFactor
+}
 
-	deletePolicy := metav1.DeletePropagationForeground
-	if err := clients.KubeClient.CoreV1().Services(experimentsDetails.ChaoslibDetail.AppNS).Delete("cassandra-liveness-service-"+experimentsDetails.RunID, &metav1.DeleteOptions{
-		PropagationPolicy: &deletePolicy,
-	}); err != nil {
-		return errors.Errorf("Fail to delete liveness service, err: %v", err)
+// requiredReplicas returns the number of the replicas acknowledging the request at the consistency level
+// the local & each quorum are considered as the quorum, as the liveness keyspace is not replicated across the datacenters
+func requiredReplicas(consistency cassandraclient.Consistency, replicationFactor int) int {
+	switch consistency {
+	case cassandraclient.One, cassandraclient.LocalOne:
+		return 1
+	case cassandraclient.Two:
+		return 2
+	case cassandraclient.Three:
+		return 3
+	case cassandraclient.Quorum, cassandraclient.LocalQuorum, cassandraclient.EachQuorum, cassandraclient.Serial, cassandraclient.LocalSerial:
+		return replicationFactor/2 + 1
+	case cassandraclient.All:
+		return replicationFactor
 	}
-	return retry.
-		Times(uint(experimentsDetails.ChaoslibDetail.Timeout / experimentsDetails.ChaoslibDetail.Delay)).
-		Wait(time.Duration(experimentsDetails.ChaoslibDetail.Delay) * time.Second).
-		Try(func(attempt uint) error {
-			svc, err := clients.KubeClient.CoreV1().Services(experimentsDetails.ChaoslibDetail.AppNS).List(metav1.ListOptions{LabelSelector: "name=cassandra-liveness-service-" + experimentsDetails.RunID})
-			if err != nil || len(svc.Items) != 0 {
-				return errors.Errorf("Liveness service is not deleted yet, err: %v", err)
-			}
-			return nil
-		})
+	return 0
 }
 
-// CreateLivenessPod will create a cassandra liveness deployment
-func CreateLivenessPod(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) error {
-
-	// Create liveness deploy
-	liveness := &appsv1.Deployment{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "Deployment",
-			APIVersion: "apps/v1",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "cassandra-liveness-deploy-" + experimentsDetails.RunID,
-			Labels: map[string]string{
-				"name": "cassandra-liveness-deploy-" + experimentsDetails.RunID,
-			},
-		},
-		Spec: appsv1.DeploymentSpec{
-			Replicas: func(i int32) *int32 { return &i }(1),
-			Selector: &metav1.LabelSelector{
-				MatchLabels: map[string]string{
-					"name": "cassandra-liveness-deploy-" + experimentsDetails.RunID,
-				},
-			},
-			Template: apiv1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: map[string]string{
-						"name": "cassandra-liveness-deploy-" + experimentsDetails.RunID,
-					},
-				},
-				Spec: apiv1.PodSpec{
-					Volumes: []apiv1.Volume{
-						{
-							Name: "status-volume",
-							VolumeSource: apiv1.VolumeSource{
-								EmptyDir: &apiv1.EmptyDirVolumeSource{},
-							},
-						},
-					},
-					Containers: []apiv1.Container{
-						{
-							Name:  "liveness-business-logic",
-							Image: experimentsDetails.CassandraLivenessImage,
-							Command: []string{
-								"/bin/bash",
-							},
-							Args: []string{
-								"-c",
-								"bash cassandra-liveness-check.sh",
-							},
-							Env: []apiv1.EnvVar{
-								{
-									Name:  "LIVENESS_PERIOD_SECONDS",
-									Value: "10",
-								},
-								{
-									Name:  "LIVENESS_TIMEOUT_SECONDS",
-									Value: "10",
-								},
-								{
-									Name:  "LIVENESS_RETRY_COUNT",
-									Value: "10",
-								},
-								{
-									Name:  "CASSANDRA_SVC_NAME",
-									Value: experimentsDetails.CassandraServiceName,
-								},
-								{
-									Name:  "REPLICATION_FACTOR",
-									Value: experimentsDetails.KeySpaceReplicaFactor,
-								},
-								{
-									Name:  "CASSANDRA_PORT",
-									Value: strconv.Itoa(experimentsDetails.CassandraPort),
-								},
-							},
-							Resources: apiv1.ResourceRequirements{},
-							VolumeMounts: []apiv1.VolumeMount{
-								{
-									Name:      "status-volume",
-									MountPath: "/var/tmp",
-								},
-							},
-							ImagePullPolicy: apiv1.PullPolicy("Always"),
-						},
-						{
-							Name:  "webserver",
-							Image: experimentsDetails.CassandraLivenessImage,
-							Command: []string{
-								"/bin/bash",
-							},
-							Args: []string{
-								"-c",
-								"bash webserver.sh",
-							},
-							Ports: []apiv1.ContainerPort{
-								{
-									HostPort:      0,
-									ContainerPort: int32(experimentsDetails.LivenessServicePort),
-								},
-							},
-							Env: []apiv1.EnvVar{
-								{
-									Name:  "INIT_WAIT_SECONDS",
-									Value: "10",
-								},
-								{
-									Name:  "LIVENESS_SVC_PORT",
-									Value: strconv.Itoa(experimentsDetails.LivenessServicePort),
-								},
-							},
-							Resources: apiv1.ResourceRequirements{},
-							VolumeMounts: []apiv1.VolumeMount{
-								{
-									Name:      "status-volume",
-									MountPath: "/var/tmp",
-								},
-							},
-							ImagePullPolicy: apiv1.PullPolicy("Always"),
-						},
-					},
-				},
-			},
-			Strategy:        appsv1.DeploymentStrategy{},
-			MinReadySeconds: 0,
-		},
+// LivenessStatusCheck verifies that the liveness loop recovered from the chaos
+// it stops the loop and records the success percentages & the latency in the chaosresult
+// the stale reads fail the check, if the consistency levels of the reads & writes overlap
+func LivenessStatusCheck(experimentsDetails *experimentTypes.ExperimentDetails, liveness *Liveness, resultDetails *types.ResultDetails) error {
+
+	loopErr := liveness.waitForSuccess(time.Now())
+	liveness.stop()
+
+	metrics := liveness.Metrics()
+	log.InfoWithValues("[Info]: The outcome of the cassandra liveness loop", logrus.Fields{
+		"Writes":               metrics.Writes,
+		"WriteErrors":          metrics.WriteErrors,
+		"Reads":                metrics.Reads,
+		"ReadErrors":           metrics.ReadErrors,
+		"StaleReads":           metrics.StaleReads,
+		"WriteSuccess(%)":      metrics.WriteSuccessPerc,
+		"ReadSuccess(%)":       metrics.ReadSuccessPerc,
+		"AvgLatency(ms)":       metrics.AvgLatency,
+		"MaxLatency(ms)":       metrics.MaxLatency,
+		"MaxUnavailability(s)": metrics.MaxUnavailable,
+	})
+	if resultDetails.Annotations == nil {
+		resultDetails.Annotations = map[string]string{}
+	}
+	if value, err := json.Marshal(metrics); err != nil {
+		log.Warnf("Unable to record the liveness metrics in the chaosresult, err: %v", err)
+	} else {
+		resultDetails.Annotations["litmuschaos.io/cassandra-liveness"] = string(value)
 	}
 
-	// Creating liveness deployment
-	_, err := clients.KubeClient.AppsV1().Deployments(experimentsDetails.ChaoslibDetail.AppNS).Create(liveness)
-	if err != nil {
-		return err
+	if loopErr != nil {
+		return errors.Errorf("Liveness loop is not recovered, err: %v", loopErr)
+	}
+	if metrics.StaleReads != 0 && liveness.isStronglyConsistent() {
+		return errors.Errorf("%v reads at %v consistency missed the writes acknowledged at %v consistency", metrics.StaleReads, metrics.ReadConsistency, metrics.WriteConsistency)
 	}
-	log.Info("Liveness Deployment Created successfully!")
 	return nil
 }
 
-// CreateLivenessService will create Cassandra liveness service
-func CreateLivenessService(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) error {
-	// Create resource object
-	livenessSvc := &corev1.Service{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "Service",
-			APIVersion: "v1",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "cassandra-liveness-service-" + experimentsDetails.RunID,
-			Labels: map[string]string{
-				"name": "cassandra-liveness-service-" + experimentsDetails.RunID,
-			},
-		},
-		Spec: corev1.ServiceSpec{
-			Ports: []corev1.ServicePort{
-				{
-					Name:     "liveness",
-					Protocol: corev1.Protocol("TCP"),
-					Port:     int32(experimentsDetails.LivenessServicePort),
-				},
-			},
-			Selector: map[string]string{
-				"name": "cassandra-liveness-deploy-" + experimentsDetails.RunID,
-			},
-			HealthCheckNodePort: 0,
-		},
-	}
+// LivenessCleanup stops the liveness loop and drops the liveness keyspace
+func LivenessCleanup(experimentsDetails *experimentTypes.ExperimentDetails, liveness *Liveness) error {
 
-	// Creating liveness service
-	_, err := clients.KubeClient.CoreV1().Services(experimentsDetails.ChaoslibDetail.AppNS).Create(livenessSvc)
-	if err != nil {
-		return err
-	}
-	log.Info("Liveness service created successfully!")
+	liveness.stop()
+	defer liveness.session.Close()
 
+	if err := liveness.session.Exec("DROP KEYSPACE IF EXISTS "+liveness.Keyspace, cassandraclient.Quorum); err != nil {
+		return errors.Errorf("Fail to drop the liveness keyspace, err: %v", err)
+	}
+	log.Info("[Cleanup]: Cassandra liveness keyspace has been dropped successfully")
 	return nil
 }
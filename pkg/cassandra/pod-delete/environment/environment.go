@@ -11,11 +11,11 @@ import (
 )
 
 //GetENV fetches all the env variables from the runner pod
-func GetENV(cassandraDetails *cassandraTypes.ExperimentDetails) {
+func GetENV(cassandraDetails *cassandraTypes.ExperimentDetails, expName string) {
 
 	var ChaoslibDetail exp.ExperimentDetails
 
-	ChaoslibDetail.ExperimentName = Getenv("EXPERIMENT_NAME", "cassandra-pod-delete")
+	ChaoslibDetail.ExperimentName = Getenv("EXPERIMENT_NAME", expName)
 	ChaoslibDetail.ChaosNamespace = Getenv("CHAOS_NAMESPACE", "litmus")
 	ChaoslibDetail.EngineName = Getenv("CHAOSENGINE", "")
 	ChaoslibDetail.ChaosDuration, _ = strconv.Atoi(Getenv("TOTAL_CHAOS_DURATION", "30"))
@@ -34,13 +34,23 @@ func GetENV(cassandraDetails *cassandraTypes.ExperimentDetails) {
 	ChaoslibDetail.Delay, _ = strconv.Atoi(Getenv("STATUS_CHECK_DELAY", "2"))
 	ChaoslibDetail.Timeout, _ = strconv.Atoi(Getenv("STATUS_CHECK_TIMEOUT", "180"))
 	ChaoslibDetail.PodsAffectedPerc, _ = strconv.Atoi(Getenv("PODS_AFFECTED_PERC", "0"))
+	ChaoslibDetail.TargetPods = Getenv("TARGET_PODS", "")
+	ChaoslibDetail.Sequence = Getenv("SEQUENCE", "parallel")
 	cassandraDetails.ChaoslibDetail = &ChaoslibDetail
 	cassandraDetails.CassandraServiceName = Getenv("CASSANDRA_SVC_NAME", "")
 	cassandraDetails.KeySpaceReplicaFactor = Getenv("KEYSPACE_REPLICATION_FACTOR", "")
 	cassandraDetails.CassandraPort, _ = strconv.Atoi(Getenv("CASSANDRA_PORT", "9042"))
-	cassandraDetails.LivenessServicePort, _ = strconv.Atoi(Getenv("LIVENESS_SVC_PORT", "8088"))
-	cassandraDetails.CassandraLivenessImage = Getenv("CASSANDRA_LIVENESS_IMAGE", "litmuschaos/cassandra-client:latest")
 	cassandraDetails.CassandraLivenessCheck = Getenv("CASSANDRA_LIVENESS_CHECK", "")
+	cassandraDetails.CassandraUsername = Getenv("CASSANDRA_USERNAME", "")
+	cassandraDetails.CassandraPassword = Getenv("CASSANDRA_PASSWORD", "")
+	cassandraDetails.CassandraTLSEnabled, _ = strconv.ParseBool(Getenv("CASSANDRA_TLS_ENABLED", "false"))
+	cassandraDetails.CassandraTLSCACert = Getenv("CASSANDRA_TLS_CA_CERT", "")
+	cassandraDetails.CassandraTLSSkipVerify, _ = strconv.ParseBool(Getenv("CASSANDRA_TLS_SKIP_VERIFY", "false"))
+	cassandraDetails.WriteConsistency = Getenv("CASSANDRA_WRITE_CONSISTENCY", "QUORUM")
+	cassandraDetails.ReadConsistency = Getenv("CASSANDRA_READ_CONSISTENCY", "QUORUM")
+	cassandraDetails.CassandraTargetToken = Getenv("CASSANDRA_TARGET_TOKEN", "")
+	cassandraDetails.ManagementAPIPort, _ = strconv.Atoi(Getenv("CASSANDRA_MANAGEMENT_API_PORT", "0"))
+	cassandraDetails.DecommissionRecovery = Getenv("CASSANDRA_DECOMMISSION_RECOVERY", "rebootstrap")
 	cassandraDetails.RunID = Getenv("RunID", "")
 }
 
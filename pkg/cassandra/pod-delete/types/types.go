@@ -10,8 +10,34 @@ type ExperimentDetails struct {
 	CassandraServiceName   string
 	KeySpaceReplicaFactor  string
 	CassandraPort          int
-	LivenessServicePort    int
-	CassandraLivenessImage string
 	CassandraLivenessCheck string
+	CassandraUsername      string
+	CassandraPassword      string
+	CassandraTLSEnabled    bool
+	CassandraTLSCACert     string
+	CassandraTLSSkipVerify bool
+	WriteConsistency       string
+	ReadConsistency        string
+	CassandraTargetToken   string
+	ManagementAPIPort      int
+	DecommissionRecovery   string
 	RunID                  string
 }
+
+// LivenessMetrics contains the outcome of the cassandra liveness loop
+type LivenessMetrics struct {
+	Keyspace         string         `json:"keyspace"`
+	WriteConsistency string         `json:"writeConsistency"`
+	ReadConsistency  string         `json:"readConsistency"`
+	Writes           int            `json:"writes"`
+	WriteErrors      int            `json:"writeErrors"`
+	Reads            int            `json:"reads"`
+	ReadErrors       int            `json:"readErrors"`
+	StaleReads       int            `json:"staleReads"`
+	Errors           map[string]int `json:"errors,omitempty"`
+	WriteSuccessPerc float64        `json:"writeSuccessPerc"`
+	ReadSuccessPerc  float64        `json:"readSuccessPerc"`
+	AvgLatency       float64        `json:"avgLatencyMillis"`
+	MaxLatency       float64        `json:"maxLatencyMillis"`
+	MaxUnavailable   float64        `json:"maxUnavailableSeconds"`
+}
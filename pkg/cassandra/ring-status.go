@@ -0,0 +1,252 @@
+package cassandra
+
+import (
+	"math/big"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	cassandraclient "github.com/litmuschaos/litmus-go/pkg/cassandra/client"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/cassandra/pod-delete/types"
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RingNode contains the details of the cassandra node, as per the system tables of the node
+type RingNode struct {
+	Pod          string
+	Address      string
+	HostID       string
+	DataCenter   string
+	Rack         string
+	Bootstrapped string
+	Partitioner  string
+	Tokens       []string
+	// Peers contains the host ids of the peers known to the node
+	Peers []string
+	// Ownership is the percentage of the token ring owned by the node
+	Ownership float64
+}
+
+// RingStatusCheck checks that every cassandra pod is a bootstrapped member of the ring
+// all the members should agree on the ring membership and own some part of the token ring
+func RingStatusCheck(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) error {
+
+	log.Info("[Check]: Checking the status of the cassandra ring")
+	return retry.
+		Times(uint(experimentsDetails.ChaoslibDetail.Timeout / experimentsDetails.ChaoslibDetail.Delay)).
+		Wait(time.Duration(experimentsDetails.ChaoslibDetail.Delay) * time.Second).
+		Try(func(attempt uint) error {
+			ring, err := GetRing(experimentsDetails, clients)
+			if err != nil {
+				return err
+			}
+			return checkRing(ring)
+		})
+}
+
+// checkRing validates the ring membership and the token ownership of the nodes
+func checkRing(ring []RingNode) error {
+
+	hostIDs := map[string]bool{}
+	for _, node := range ring {
+		hostIDs[node.HostID] = true
+	}
+	for _, node := range ring {
+		if node.Bootstrapped != "COMPLETED" {
+			return errors.Errorf("%v cassandra node is not bootstrapped, state: %v", node.Pod, node.Bootstrapped)
+		}
+		if len(node.Tokens) == 0 {
+			return errors.Errorf("%v cassandra node owns no tokens", node.Pod)
+		}
+		if len(node.Peers) != len(ring)-1 {
+			return errors.Errorf("%v cassandra node knows %v peers, expected %v", node.Pod, len(node.Peers), len(ring)-1)
+		}
+		for _, peer := range node.Peers {
+			if !hostIDs[peer] {
+				return errors.Errorf("%v cassandra node knows the %v peer, which is not part of the ring", node.Pod, peer)
+			}
+		}
+	}
+
+	for _, node := range ring {
+		log.InfoWithValues("[Info]: The details of the cassandra node", logrus.Fields{
+			"Pod":        node.Pod,
+			"HostID":     node.HostID,
+			"DataCenter": node.DataCenter,
+			"Rack":       node.Rack,
+			"Tokens":     len(node.Tokens),
+			"Ownership":  strconv.FormatFloat(node.Ownership, 'f', 2, 64) + "%",
+		})
+		// the ownership is not derived for the order preserving partitioners
+		if ringSize(node.Partitioner) != nil && node.Ownership <= 0 {
+			return errors.Errorf("%v cassandra node owns no part of the token ring", node.Pod)
+		}
+	}
+	log.Info("[Check]: All the cassandra nodes are part of the ring and own the tokens")
+	return nil
+}
+
+// GetRing reads the details of every cassandra pod from the system tables of its node
+func GetRing(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) ([]RingNode, error) {
+
+	podList, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaoslibDetail.AppNS).List(metav1.ListOptions{LabelSelector: experimentsDetails.ChaoslibDetail.AppLabel})
+	if err != nil || len(podList.Items) == 0 {
+		return nil, errors.Errorf("Failed to get the application pod in %v namespace, err: %v", experimentsDetails.ChaoslibDetail.AppNS, err)
+	}
+
+	ring := []RingNode{}
+	for _, pod := range podList.Items {
+		node, err := GetRingNode(experimentsDetails, pod)
+		if err != nil {
+			return nil, err
+		}
+		ring = append(ring, node)
+	}
+	setOwnership(ring)
+	return ring, nil
+}
+
+// GetRingNode connects to the node of the cassandra pod and reads its details from the system.local & system.peers tables
+func GetRingNode(experimentsDetails *experimentTypes.ExperimentDetails, pod apiv1.Pod) (RingNode, error) {
+
+	if pod.Status.PodIP == "" {
+		return RingNode{}, errors.Errorf("%v cassandra pod has no ip", pod.Name)
+	}
+	config, err := newClientConfig(experimentsDetails, []string{net.JoinHostPort(pod.Status.PodIP, strconv.Itoa(experimentsDetails.CassandraPort))})
+	if err != nil {
+		return RingNode{}, err
+	}
+	session, err := cassandraclient.NewSession(config)
+	if err != nil {
+		return RingNode{}, errors.Errorf("%v cassandra node is not reachable, err: %v", pod.Name, err)
+	}
+	defer session.Close()
+
+	rows, err := session.Query("SELECT host_id, data_center, rack, bootstrapped, partitioner, tokens FROM system.local", cassandraclient.One)
+	if err != nil || len(rows) == 0 {
+		return RingNode{}, errors.Errorf("Unable to read the system.local table of the %v cassandra node, err: %v", pod.Name, err)
+	}
+	node := RingNode{
+		Pod:          pod.Name,
+		Address:      pod.Status.PodIP,
+		HostID:       rows[0].String("host_id"),
+		DataCenter:   rows[0].String("data_center"),
+		Rack:         rows[0].String("rack"),
+		Bootstrapped: rows[0].String("bootstrapped"),
+		Partitioner:  rows[0].String("partitioner"),
+		Tokens:       rows[0].Strings("tokens"),
+	}
+
+	peers, err := session.Query("SELECT peer, host_id FROM system.peers", cassandraclient.One)
+	if err != nil {
+		return RingNode{}, errors.Errorf("Unable to read the system.peers table of the %v cassandra node, err: %v", pod.Name, err)
+	}
+	for _, peer := range peers {
+		node.Peers = append(node.Peers, peer.String("host_id"))
+	}
+	return node, nil
+}
+
+// ringToken is the token of the ring, along with the index of its owner
+type ringToken struct {
+	token *big.Int
+	owner int
+}
+
+// getSortedTokens returns the tokens of all the nodes in the ring order
+func getSortedTokens(ring []RingNode) []ringToken {
+
+	tokens := []ringToken{}
+	for i, node := range ring {
+		for _, t := range node.Tokens {
+			if token, ok := new(big.Int).SetString(t, 10); ok {
+				tokens = append(tokens, ringToken{token: token, owner: i})
+			}
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].token.Cmp(tokens[j].token) < 0 })
+	return tokens
+}
+
+// ringSize returns the size of the token space of the partitioner, nil for the order preserving partitioners
+func ringSize(partitioner string) *big.Int {
+	switch {
+	case strings.HasSuffix(partitioner, "Murmur3Partitioner"):
+		return new(big.Int).Lsh(big.NewInt(1), 64)
+	case strings.HasSuffix(partitioner, "RandomPartitioner"):
+		return new(big.Int).Lsh(big.NewInt(1), 127)
+	}
+	return nil
+}
+
+// setOwnership derive the percentage of the token ring owned by every node
+// every token owns the range from the previous token (exclusive) up to itself (inclusive)
+func setOwnership(ring []RingNode) {
+
+	if len(ring) == 0 {
+		return
+	}
+	size := ringSize(ring[0].Partitioner)
+	tokens := getSortedTokens(ring)
+	if size == nil || len(tokens) == 0 {
+		return
+	}
+
+	owned := make([]*big.Int, len(ring))
+	for i := range owned {
+		owned[i] = new(big.Int)
+	}
+	for i, t := range tokens {
+		previous := tokens[len(tokens)-1].token
+		if i > 0 {
+			previous = tokens[i-1].token
+		}
+		rangeSize := new(big.Int).Sub(t.token, previous)
+		if rangeSize.Sign() <= 0 {
+			rangeSize.Add(rangeSize, size)
+		}
+		owned[t.owner].Add(owned[t.owner], rangeSize)
+	}
+	for i := range ring {
+		ring[i].Ownership, _ = new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Mul(owned[i], big.NewInt(100))), new(big.Float).SetInt(size)).Float64()
+	}
+}
+
+// GetTokenOwner returns the pod of the cassandra node, which owns the token range containing the CASSANDRA_TARGET_TOKEN
+// the owner is the node with the first token greater than or equal to the target token, wrapping around the ring
+func GetTokenOwner(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (string, error) {
+
+	target, ok := new(big.Int).SetString(strings.TrimSpace(experimentsDetails.CassandraTargetToken), 10)
+	if !ok {
+		return "", errors.Errorf("%v is not a valid cassandra token", experimentsDetails.CassandraTargetToken)
+	}
+
+	ring, err := GetRing(experimentsDetails, clients)
+	if err != nil {
+		return "", err
+	}
+	tokens := getSortedTokens(ring)
+	if len(tokens) == 0 {
+		return "", errors.New("no tokens found in the cassandra ring")
+	}
+
+	index := sort.Search(len(tokens), func(i int) bool { return tokens[i].token.Cmp(target) >= 0 })
+	if index == len(tokens) {
+		index = 0
+	}
+	previous := tokens[len(tokens)-1].token
+	if index > 0 {
+		previous = tokens[index-1].token
+	}
+	owner := ring[tokens[index].owner]
+	log.Infof("[Info]: The %v token belongs to the (%v, %v] token range, owned by the %v cassandra node", target, previous, tokens[index].token, owner.Pod)
+	return owner.Pod, nil
+}
@@ -0,0 +1,609 @@
+package fake
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// Server is an in-memory kubernetes api server, serving the clientsets of the experiments
+// the objects are stored as json, keyed by their collection path, so that any core or custom resource can be served
+// watches are not supported, as the experiments poll the resources
+type Server struct {
+	mu      sync.Mutex
+	server  *httptest.Server
+	objects map[string]map[string]map[string]interface{}
+	// kinds contains the kind of the objects, keyed by their collection path
+	kinds map[string]string
+	seq   int
+
+	// PodHook is called with every created pod, before it is stored
+	// it can be used to move the helper pods through their lifecycle, see Completed
+	PodHook func(pod *apiv1.Pod)
+	// Logs is the log of every pod, served by the log subresource
+	Logs string
+	// Errors contains the errors to be returned by the requests, keyed by the verb and the resource, ex: create pods
+	Errors map[string]error
+	// Actions records the mutating requests served by the server in order, ex: create pods litmus/helper
+	Actions []string
+}
+
+// NewClientSets starts the fake api server with the given objects and returns the clientsets connected to it
+// the server should be closed once the clientsets are no longer used
+func NewClientSets(objects ...runtime.Object) (clients.ClientSets, *Server, error) {
+
+	s := &Server{
+		objects: map[string]map[string]map[string]interface{}{},
+		kinds:   map[string]string{},
+		Logs:    "fake logs",
+		Errors:  map[string]error{},
+	}
+	for _, object := range objects {
+		if err := s.Add(object); err != nil {
+			return clients.ClientSets{}, nil, err
+		}
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serve))
+
+	// the requests are not throttled, as the retries of the tests are in the milliseconds
+	config := &rest.Config{Host: s.server.URL, RateLimiter: flowcontrol.NewFakeAlwaysRateLimiter()}
+	clientSets := clients.ClientSets{KubeConfig: config}
+	var err error
+	if clientSets.KubeClient, err = clients.GenerateK8sClientSet(config); err != nil {
+		s.Close()
+		return clients.ClientSets{}, nil, err
+	}
+	if clientSets.LitmusClient, err = clients.GenerateLitmusClientSet(config); err != nil {
+		s.Close()
+		return clients.ClientSets{}, nil, err
+	}
+	if clientSets.DynamicClient, err = dynamic.NewForConfig(config); err != nil {
+		s.Close()
+		return clients.ClientSets{}, nil, err
+	}
+	return clientSets, s, nil
+}
+
+// Close shuts down the fake api server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Completed returns a pod hook, which completes all the containers of the pod with the given exit code
+func Completed(exitCode int32) func(pod *apiv1.Pod) {
+	return func(pod *apiv1.Pod) {
+		pod.Status.Phase = apiv1.PodSucceeded
+		if exitCode != 0 {
+			pod.Status.Phase = apiv1.PodFailed
+		}
+		pod.Status.ContainerStatuses = nil
+		for _, container := range pod.Spec.Containers {
+			pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, apiv1.ContainerStatus{
+				Name: container.Name,
+				State: apiv1.ContainerState{
+					Terminated: &apiv1.ContainerStateTerminated{ExitCode: exitCode, Reason: "Completed"},
+				},
+			})
+		}
+	}
+}
+
+// Add stores the object in the server, the object should contain its kind & apiVersion
+// the core objects are defaulted, if they are not set
+func (s *Server) Add(object runtime.Object) error {
+
+	data, err := toMap(object)
+	if err != nil {
+		return err
+	}
+	if data["apiVersion"] == nil || data["kind"] == nil {
+		if err := defaultCoreKind(object, data); err != nil {
+			return err
+		}
+	}
+	path, err := collectionPath(data)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.store(path, data)
+	return nil
+}
+
+// Get returns the stored object in the given collection path, ex: /api/v1/namespaces/litmus/pods
+func (s *Server) Get(path, name string, into runtime.Object) (bool, error) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	object, ok := s.objects[path][name]
+	if !ok {
+		return false, nil
+	}
+	return true, fromMap(object, into)
+}
+
+// serve serves the api requests
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	req, ok := parsePath(r.URL.Path)
+	if !ok {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, "the server could not find the requested resource")
+		return
+	}
+	// the http methods are mapped to the api verbs, as used by the rbac rules
+	verb := strings.ToLower(r.Method)
+	switch {
+	case verb == "get" && req.name == "":
+		verb = "list"
+		if r.URL.Query().Get("watch") == "true" {
+			verb = "watch"
+		}
+	case verb == "post":
+		verb = "create"
+	case verb == "put":
+		verb = "update"
+	case verb == "delete" && req.name == "":
+		verb = "deletecollection"
+	}
+	if err := s.Errors[verb+" "+req.resource]; err != nil {
+		writeStatus(w, http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+		return
+	}
+
+	switch verb {
+	case "list":
+		s.list(w, r, req)
+	case "get":
+		s.get(w, req)
+	case "create":
+		s.create(w, r, req)
+	case "update", "patch":
+		s.update(w, r, req, verb)
+	case "delete":
+		s.delete(w, req)
+	case "deletecollection":
+		s.deleteCollection(w, r, req)
+	default:
+		writeStatus(w, http.StatusMethodNotAllowed, metav1.StatusReasonMethodNotAllowed, verb+" is not supported by the fake server")
+	}
+}
+
+// list serves the objects of the collection, filtered by the label & field selectors
+func (s *Server) list(w http.ResponseWriter, r *http.Request, req request) {
+
+	items, err := s.selectObjects(req.path, r.URL.Query().Get("labelSelector"), r.URL.Query().Get("fieldSelector"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+		return
+	}
+	if items == nil {
+		items = []interface{}{}
+	}
+	list := map[string]interface{}{
+		"metadata": map[string]interface{}{"resourceVersion": strconv.Itoa(s.seq)},
+		"items":    items,
+	}
+	// the kind of the list is required by the dynamic client, it is known once an object is stored in the collection
+	if kind := s.kinds[req.path]; kind != "" {
+		list["apiVersion"], list["kind"] = req.groupVersion, kind+"List"
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+// get serves the object or its log subresource
+func (s *Server) get(w http.ResponseWriter, req request) {
+
+	object, ok := s.objects[req.path][req.name]
+	if !ok {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, req.resource+" \""+req.name+"\" not found")
+		return
+	}
+	if req.subresource == "log" {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(s.Logs))
+		return
+	}
+	writeJSON(w, http.StatusOK, object)
+}
+
+// create stores the object of the request, the pods are passed through the pod hook
+func (s *Server) create(w http.ResponseWriter, r *http.Request, req request) {
+
+	object, err := readObject(r)
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+		return
+	}
+	metadata := metadataOf(object)
+	name, _ := metadata["name"].(string)
+	if name == "" {
+		generateName, _ := metadata["generateName"].(string)
+		name = generateName + strconv.Itoa(s.seq+1)
+		metadata["name"] = name
+	}
+	if _, ok := s.objects[req.path][name]; ok {
+		writeStatus(w, http.StatusConflict, metav1.StatusReasonAlreadyExists, req.resource+" \""+name+"\" already exists")
+		return
+	}
+	if req.namespace != "" {
+		metadata["namespace"] = req.namespace
+	}
+	metadata["uid"] = "uid-" + strconv.Itoa(s.seq+1)
+	metadata["creationTimestamp"] = time.Now().UTC().Format(time.RFC3339)
+
+	if req.resource == "pods" && s.PodHook != nil {
+		var pod apiv1.Pod
+		if err := fromMap(object, &pod); err != nil {
+			writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+			return
+		}
+		s.PodHook(&pod)
+		if object, err = toMap(&pod); err != nil {
+			writeStatus(w, http.StatusInternalServerError, metav1.StatusReasonInternalError, err.Error())
+			return
+		}
+	}
+	s.store(req.path, object)
+	s.record("create", req, name)
+	writeJSON(w, http.StatusCreated, object)
+}
+
+// update replaces or merge patches the object of the request
+// the strategic merge patches are applied as json merge patches, which is sufficient for the maps & scalars
+func (s *Server) update(w http.ResponseWriter, r *http.Request, req request, verb string) {
+
+	current, ok := s.objects[req.path][req.name]
+	if !ok {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, req.resource+" \""+req.name+"\" not found")
+		return
+	}
+	if verb == "patch" && strings.Contains(r.Header.Get("Content-Type"), "json-patch") {
+		writeStatus(w, http.StatusUnsupportedMediaType, metav1.StatusReasonUnsupportedMediaType, "json patch is not supported by the fake server")
+		return
+	}
+	object, err := readObject(r)
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+		return
+	}
+	if verb == "patch" {
+		object = mergePatch(current, object).(map[string]interface{})
+	}
+	metadata := metadataOf(object)
+	metadata["name"] = req.name
+	if req.namespace != "" {
+		metadata["namespace"] = req.namespace
+	}
+	metadata["uid"] = metadataOf(current)["uid"]
+	s.store(req.path, object)
+	s.record(verb, req, req.name)
+	writeJSON(w, http.StatusOK, object)
+}
+
+// delete removes the object of the request
+func (s *Server) delete(w http.ResponseWriter, req request) {
+
+	if _, ok := s.objects[req.path][req.name]; !ok {
+		writeStatus(w, http.StatusNotFound, metav1.StatusReasonNotFound, req.resource+" \""+req.name+"\" not found")
+		return
+	}
+	delete(s.objects[req.path], req.name)
+	s.record("delete", req, req.name)
+	writeStatus(w, http.StatusOK, "", "")
+}
+
+// deleteCollection removes the objects of the collection, matching the label & field selectors
+func (s *Server) deleteCollection(w http.ResponseWriter, r *http.Request, req request) {
+
+	items, err := s.selectObjects(req.path, r.URL.Query().Get("labelSelector"), r.URL.Query().Get("fieldSelector"))
+	if err != nil {
+		writeStatus(w, http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+		return
+	}
+	for _, item := range items {
+		name, _ := metadataOf(item.(map[string]interface{}))["name"].(string)
+		delete(s.objects[req.path], name)
+		s.record("delete", req, name)
+	}
+	writeStatus(w, http.StatusOK, "", "")
+}
+
+// selectObjects returns the objects of the collection sorted by name, which match the label & field selectors
+func (s *Server) selectObjects(path, labelSelector, fieldSelector string) ([]interface{}, error) {
+
+	labelSel, err := labels.Parse(labelSelector)
+	if err != nil {
+		return nil, errors.Errorf("invalid label selector, err: %v", err)
+	}
+	fieldSel, err := fields.ParseSelector(fieldSelector)
+	if err != nil {
+		return nil, errors.Errorf("invalid field selector, err: %v", err)
+	}
+
+	var names []string
+	for name := range s.objects[path] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var items []interface{}
+	for _, name := range names {
+		object := s.objects[path][name]
+		objectLabels := labels.Set{}
+		if l, ok := metadataOf(object)["labels"].(map[string]interface{}); ok {
+			for key, value := range l {
+				objectLabels[key], _ = value.(string)
+			}
+		}
+		if labelSel.Matches(objectLabels) && fieldSel.Matches(objectFields(object)) {
+			items = append(items, object)
+		}
+	}
+	return items, nil
+}
+
+// store stores the object in the collection, with a new resource version
+func (s *Server) store(path string, object map[string]interface{}) {
+
+	s.seq++
+	metadata := metadataOf(object)
+	metadata["resourceVersion"] = strconv.Itoa(s.seq)
+	if s.objects[path] == nil {
+		s.objects[path] = map[string]map[string]interface{}{}
+	}
+	name, _ := metadata["name"].(string)
+	s.objects[path][name] = object
+	if kind, _ := object["kind"].(string); kind != "" {
+		s.kinds[path] = kind
+	}
+}
+
+// record records the mutating action
+func (s *Server) record(verb string, req request, name string) {
+	action := verb + " " + req.resource + " "
+	if req.namespace != "" {
+		action += req.namespace + "/"
+	}
+	s.Actions = append(s.Actions, action+name)
+}
+
+// request contains the attributes of an api request
+type request struct {
+	// path is the collection path of the resource
+	path string
+	// groupVersion is the api version of the resource, ex: v1 or litmuschaos.io/v1alpha1
+	groupVersion string
+	namespace    string
+	resource     string
+	name         string
+	subresource  string
+}
+
+// parsePath parses the api path in the /api/v1/... or /apis/group/version/... format
+func parsePath(path string) (request, bool) {
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	var prefix []string
+	switch {
+	case len(segments) >= 3 && segments[0] == "api":
+		prefix, segments = segments[:2], segments[2:]
+	case len(segments) >= 4 && segments[0] == "apis":
+		prefix, segments = segments[:3], segments[3:]
+	default:
+		return request{}, false
+	}
+
+	req := request{groupVersion: strings.Join(prefix[1:], "/")}
+	if len(segments) >= 3 && segments[0] == "namespaces" {
+		req.namespace, segments = segments[1], segments[2:]
+	}
+	req.resource = segments[0]
+	if len(segments) > 1 {
+		req.name = segments[1]
+	}
+	if len(segments) > 2 {
+		req.subresource = segments[2]
+	}
+	// the status subresource is served by the object itself
+	if req.subresource == "status" {
+		req.subresource = ""
+	}
+
+	req.path = "/" + strings.Join(prefix, "/")
+	if req.namespace != "" {
+		req.path += "/namespaces/" + req.namespace
+	}
+	req.path += "/" + req.resource
+	return req, true
+}
+
+// collectionPath returns the collection path of the object, derived from its kind & apiVersion
+func collectionPath(object map[string]interface{}) (string, error) {
+
+	apiVersion, _ := object["apiVersion"].(string)
+	kind, _ := object["kind"].(string)
+	if apiVersion == "" || kind == "" {
+		return "", errors.Errorf("kind & apiVersion of the object are not set")
+	}
+	path := "/apis/" + apiVersion
+	if apiVersion == "v1" {
+		path = "/api/v1"
+	}
+	if namespace, _ := metadataOf(object)["namespace"].(string); namespace != "" {
+		path += "/namespaces/" + namespace
+	}
+	return path + "/" + resourceName(kind), nil
+}
+
+// resourceName returns the plural resource name of the kind
+func resourceName(kind string) string {
+
+	resource := strings.ToLower(kind)
+	switch {
+	case strings.HasSuffix(resource, "s"):
+		return resource + "es"
+	case strings.HasSuffix(resource, "y"):
+		return strings.TrimSuffix(resource, "y") + "ies"
+	}
+	return resource + "s"
+}
+
+// defaultCoreKind sets the kind & apiVersion of the core objects
+func defaultCoreKind(object runtime.Object, data map[string]interface{}) error {
+
+	var kind string
+	switch object.(type) {
+	case *apiv1.Pod:
+		kind = "Pod"
+	case *apiv1.Node:
+		kind = "Node"
+	case *apiv1.Namespace:
+		kind = "Namespace"
+	case *apiv1.ConfigMap:
+		kind = "ConfigMap"
+	case *apiv1.Secret:
+		kind = "Secret"
+	case *apiv1.Service:
+		kind = "Service"
+	case *apiv1.Event:
+		kind = "Event"
+	default:
+		return errors.Errorf("kind & apiVersion of the %T object are not set", object)
+	}
+	data["apiVersion"], data["kind"] = "v1", kind
+	return nil
+}
+
+// objectFields returns the fields of the object, supported by the field selectors
+func objectFields(object map[string]interface{}) fields.Set {
+
+	set := fields.Set{}
+	metadata := metadataOf(object)
+	set["metadata.name"], _ = metadata["name"].(string)
+	set["metadata.namespace"], _ = metadata["namespace"].(string)
+	if spec, ok := object["spec"].(map[string]interface{}); ok {
+		set["spec.nodeName"], _ = spec["nodeName"].(string)
+	}
+	if status, ok := object["status"].(map[string]interface{}); ok {
+		set["status.phase"], _ = status["phase"].(string)
+	}
+	if involved, ok := object["involvedObject"].(map[string]interface{}); ok {
+		set["involvedObject.kind"], _ = involved["kind"].(string)
+		set["involvedObject.name"], _ = involved["name"].(string)
+	}
+	return set
+}
+
+// mergePatch applies the json merge patch on the object
+func mergePatch(object, patch interface{}) interface{} {
+
+	patchMap, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	objectMap, ok := object.(map[string]interface{})
+	if !ok {
+		objectMap = map[string]interface{}{}
+	}
+	for key, value := range patchMap {
+		if value == nil {
+			delete(objectMap, key)
+			continue
+		}
+		objectMap[key] = mergePatch(objectMap[key], value)
+	}
+	return objectMap
+}
+
+// metadataOf returns the metadata of the object, creating it if absent
+func metadataOf(object map[string]interface{}) map[string]interface{} {
+
+	metadata, ok := object["metadata"].(map[string]interface{})
+	if !ok {
+		metadata = map[string]interface{}{}
+		object["metadata"] = metadata
+	}
+	return metadata
+}
+
+// readObject decodes the json body of the request
+func readObject(r *http.Request) (map[string]interface{}, error) {
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	object := map[string]interface{}{}
+	if err := json.Unmarshal(body, &object); err != nil {
+		return nil, errors.Errorf("unable to decode the request body, err: %v", err)
+	}
+	return object, nil
+}
+
+// toMap converts the object to its json map
+func toMap(object interface{}) (map[string]interface{}, error) {
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{}
+	return result, json.Unmarshal(data, &result)
+}
+
+// fromMap converts the json map to the object
+func fromMap(object map[string]interface{}, into interface{}) error {
+
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, into)
+}
+
+// writeJSON writes the json response
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeStatus writes the status response, the status is failure if the reason is set
+func writeStatus(w http.ResponseWriter, code int, reason metav1.StatusReason, message string) {
+
+	status := metav1.Status{
+		TypeMeta: metav1.TypeMeta{Kind: "Status", APIVersion: "v1"},
+		Status:   metav1.StatusSuccess,
+		Code:     int32(code),
+	}
+	if reason != "" {
+		status.Status = metav1.StatusFailure
+		status.Reason = reason
+		status.Message = message
+	}
+	writeJSON(w, code, status)
+}
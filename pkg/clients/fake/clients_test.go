@@ -0,0 +1,481 @@
+package fake
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/litmuschaos/chaos-operator/pkg/apis/litmuschaos/v1alpha1"
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	apiv1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+// the namespaces & the label of the test objects
+const (
+	chaosNamespace = "litmus"
+	appNamespace   = "default"
+	appLabel       = "app=nginx"
+)
+
+// newTestClientSets starts the fake api server with the given objects, it fails the test if the server can't be started
+func newTestClientSets(t *testing.T, objects ...runtime.Object) (clients.ClientSets, *Server) {
+
+	clientSets, server, err := NewClientSets(objects...)
+	if err != nil {
+		t.Fatalf("unable to create the fake clients, err: %v", err)
+	}
+	return clientSets, server
+}
+
+// appPod returns the running nginx pod with the given name, scheduled on the given node
+func appPod(name, nodeName string) *apiv1.Pod {
+	return &apiv1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: name, Namespace: appNamespace, Labels: map[string]string{"app": "nginx"}},
+		Spec:       apiv1.PodSpec{NodeName: nodeName, Containers: []apiv1.Container{{Name: "nginx", Image: "nginx"}}},
+		Status:     apiv1.PodStatus{Phase: apiv1.PodRunning},
+	}
+}
+
+// podNames returns the names of the pods
+func podNames(pods []apiv1.Pod) []string {
+	names := []string{}
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	return names
+}
+
+func TestNewClientSets(t *testing.T) {
+
+	tests := []struct {
+		objects  []runtime.Object
+		wantErr  string
+		testName string
+	}{
+		{
+			objects:  []runtime.Object{appPod("nginx-1", "node-1"), &apiv1.Node{ObjectMeta: v1.ObjectMeta{Name: "node-1"}}},
+			testName: "core objects",
+		},
+		{
+			objects: []runtime.Object{&v1alpha1.ChaosEngine{
+				TypeMeta:   v1.TypeMeta{Kind: "ChaosEngine", APIVersion: "litmuschaos.io/v1alpha1"},
+				ObjectMeta: v1.ObjectMeta{Name: "engine", Namespace: chaosNamespace},
+			}},
+			testName: "custom resource",
+		},
+		{
+			objects:  []runtime.Object{&appsv1.Deployment{ObjectMeta: v1.ObjectMeta{Name: "nginx", Namespace: appNamespace}}},
+			wantErr:  "kind & apiVersion of the *v1.Deployment object are not set",
+			testName: "kind is not set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			_, server, err := NewClientSets(tt.objects...)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("expected the %v error, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unable to create the fake clients, err: %v", err)
+			}
+			server.Close()
+		})
+	}
+}
+
+func TestPodLifecycle(t *testing.T) {
+
+	clients, server := newTestClientSets(t, appPod("nginx-1", "node-1"))
+	defer server.Close()
+	pods := clients.KubeClient.CoreV1().Pods(appNamespace)
+
+	pod, err := pods.Get("nginx-1", v1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get the pod, err: %v", err)
+	}
+	if pod.Spec.NodeName != "node-1" || pod.Status.Phase != apiv1.PodRunning || pod.ResourceVersion == "" {
+		t.Errorf("unexpected pod %v", pod)
+	}
+
+	// the name is generated from the generateName, if not provided
+	created, err := pods.Create(&apiv1.Pod{ObjectMeta: v1.ObjectMeta{GenerateName: "helper-", Labels: map[string]string{"app": "helper"}}})
+	if err != nil {
+		t.Fatalf("unable to create the pod, err: %v", err)
+	}
+	if !strings.HasPrefix(created.Name, "helper-") || created.Namespace != appNamespace || created.UID == "" {
+		t.Errorf("unexpected metadata of the created pod %v", created.ObjectMeta)
+	}
+	if _, err := pods.Create(appPod("nginx-1", "")); !k8serrors.IsAlreadyExists(err) {
+		t.Errorf("expected the already exists error, got %v", err)
+	}
+
+	created.Spec.NodeName = "node-2"
+	updated, err := pods.Update(created)
+	if err != nil {
+		t.Fatalf("unable to update the pod, err: %v", err)
+	}
+	if updated.Spec.NodeName != "node-2" || updated.UID != created.UID || updated.ResourceVersion == created.ResourceVersion {
+		t.Errorf("unexpected updated pod, got %v, want node-2 with %v uid", updated, created.UID)
+	}
+
+	// the null values of the merge patch remove the fields
+	patched, err := pods.Patch("nginx-1", k8stypes.MergePatchType, []byte(`{"metadata":{"labels":{"app":null,"chaos":"true"}}}`))
+	if err != nil {
+		t.Fatalf("unable to patch the pod, err: %v", err)
+	}
+	if want := map[string]string{"chaos": "true"}; !reflect.DeepEqual(patched.Labels, want) {
+		t.Errorf("got %v, want %v", patched.Labels, want)
+	}
+	if _, err := pods.Patch("nginx-1", k8stypes.JSONPatchType, []byte(`[]`)); !k8serrors.IsUnsupportedMediaType(err) {
+		t.Errorf("expected the unsupported media type error for the json patch, got %v", err)
+	}
+
+	if err := pods.Delete("nginx-1", &v1.DeleteOptions{}); err != nil {
+		t.Fatalf("unable to delete the pod, err: %v", err)
+	}
+	if _, err := pods.Get("nginx-1", v1.GetOptions{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected the not found error, got %v", err)
+	}
+	if err := pods.Delete("nginx-1", &v1.DeleteOptions{}); !k8serrors.IsNotFound(err) {
+		t.Errorf("expected the not found error, got %v", err)
+	}
+
+	want := []string{
+		"create pods default/" + created.Name,
+		"update pods default/" + created.Name,
+		"patch pods default/nginx-1",
+		"delete pods default/nginx-1",
+	}
+	if !reflect.DeepEqual(server.Actions, want) {
+		t.Errorf("got %v, want %v", server.Actions, want)
+	}
+}
+
+func TestListPods(t *testing.T) {
+
+	helper := &apiv1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "helper-1", Namespace: appNamespace, Labels: map[string]string{"app": "helper"}},
+		Spec:       apiv1.PodSpec{NodeName: "node-1"},
+		Status:     apiv1.PodStatus{Phase: apiv1.PodPending},
+	}
+	clients, server := newTestClientSets(t, appPod("nginx-2", "node-2"), appPod("nginx-1", "node-1"), helper)
+	defer server.Close()
+
+	tests := []struct {
+		options  v1.ListOptions
+		want     []string
+		wantErr  bool
+		testName string
+	}{
+		{
+			want:     []string{"helper-1", "nginx-1", "nginx-2"},
+			testName: "all the pods sorted by name",
+		},
+		{
+			options:  v1.ListOptions{LabelSelector: appLabel},
+			want:     []string{"nginx-1", "nginx-2"},
+			testName: "label selector",
+		},
+		{
+			options:  v1.ListOptions{FieldSelector: "spec.nodeName=node-1"},
+			want:     []string{"helper-1", "nginx-1"},
+			testName: "node field selector",
+		},
+		{
+			options:  v1.ListOptions{LabelSelector: "app in (nginx,helper)", FieldSelector: "spec.nodeName=node-1,status.phase=Running"},
+			want:     []string{"nginx-1"},
+			testName: "label & field selectors",
+		},
+		{
+			options:  v1.ListOptions{LabelSelector: "app=redis"},
+			want:     []string{},
+			testName: "no matching pod",
+		},
+		{
+			options:  v1.ListOptions{LabelSelector: "app in (nginx"},
+			wantErr:  true,
+			testName: "invalid label selector",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			podList, err := clients.KubeClient.CoreV1().Pods(appNamespace).List(tt.options)
+			if tt.wantErr {
+				if !k8serrors.IsBadRequest(err) {
+					t.Errorf("expected the bad request error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unable to list the pods, err: %v", err)
+			}
+			if got := podNames(podList.Items); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteCollection(t *testing.T) {
+
+	clients, server := newTestClientSets(t, appPod("nginx-1", ""), appPod("nginx-2", ""), &apiv1.Pod{ObjectMeta: v1.ObjectMeta{Name: "redis", Namespace: appNamespace}})
+	defer server.Close()
+	pods := clients.KubeClient.CoreV1().Pods(appNamespace)
+
+	if err := pods.DeleteCollection(&v1.DeleteOptions{}, v1.ListOptions{LabelSelector: appLabel}); err != nil {
+		t.Fatalf("unable to delete the pods, err: %v", err)
+	}
+	podList, err := pods.List(v1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unable to list the pods, err: %v", err)
+	}
+	if got := podNames(podList.Items); !reflect.DeepEqual(got, []string{"redis"}) {
+		t.Errorf("got %v, want [redis]", got)
+	}
+	if want := []string{"delete pods default/nginx-1", "delete pods default/nginx-2"}; !reflect.DeepEqual(server.Actions, want) {
+		t.Errorf("got %v, want %v", server.Actions, want)
+	}
+}
+
+func TestPodHook(t *testing.T) {
+
+	clients, server := newTestClientSets(t)
+	defer server.Close()
+	var created []apiv1.Pod
+	server.PodHook = func(pod *apiv1.Pod) {
+		Completed(1)(pod)
+		created = append(created, *pod)
+	}
+
+	pod := &apiv1.Pod{
+		ObjectMeta: v1.ObjectMeta{Name: "helper"},
+		Spec: apiv1.PodSpec{
+			Containers: []apiv1.Container{{Name: "inject"}, {Name: "sidecar"}},
+		},
+	}
+	result, err := clients.KubeClient.CoreV1().Pods(chaosNamespace).Create(pod)
+	if err != nil {
+		t.Fatalf("unable to create the pod, err: %v", err)
+	}
+	if result.Status.Phase != apiv1.PodFailed || len(result.Status.ContainerStatuses) != 2 {
+		t.Fatalf("expected the failed pod with 2 container statuses, got %v", result.Status)
+	}
+	for _, status := range result.Status.ContainerStatuses {
+		if status.State.Terminated == nil || status.State.Terminated.ExitCode != 1 {
+			t.Errorf("expected the %v container to be terminated with 1 exit code, got %v", status.Name, status.State)
+		}
+	}
+
+	stored := &apiv1.Pod{}
+	if found, err := server.Get("/api/v1/namespaces/litmus/pods", "helper", stored); err != nil || !found {
+		t.Fatalf("unable to get the stored pod, err: %v", err)
+	}
+	if stored.Status.Phase != apiv1.PodFailed {
+		t.Errorf("expected the hook to be applied on the stored pod, got %v phase", stored.Status.Phase)
+	}
+	if len(created) != 1 || created[0].Namespace != chaosNamespace || created[0].Status.Phase != apiv1.PodFailed {
+		t.Errorf("expected the created pod to be recorded after the hook, got %v", created)
+	}
+}
+
+func TestPodLogs(t *testing.T) {
+
+	clients, server := newTestClientSets(t, appPod("nginx-1", ""))
+	defer server.Close()
+	server.Logs = "chaos injected"
+
+	logs, err := clients.KubeClient.CoreV1().Pods(appNamespace).GetLogs("nginx-1", &apiv1.PodLogOptions{}).DoRaw()
+	if err != nil {
+		t.Fatalf("unable to get the logs, err: %v", err)
+	}
+	if string(logs) != "chaos injected" {
+		t.Errorf("got %q, want %q", logs, "chaos injected")
+	}
+	if _, err := clients.KubeClient.CoreV1().Pods(appNamespace).GetLogs("nginx-2", &apiv1.PodLogOptions{}).DoRaw(); err == nil {
+		t.Error("expected an error for the logs of the missing pod")
+	}
+}
+
+func TestErrors(t *testing.T) {
+
+	clients, server := newTestClientSets(t, appPod("nginx-1", ""))
+	defer server.Close()
+	server.Errors["create pods"] = errors.New("quota exceeded")
+	server.Errors["list nodes"] = errors.New("forbidden")
+	pods := clients.KubeClient.CoreV1().Pods(appNamespace)
+
+	if _, err := pods.Create(appPod("nginx-2", "")); !k8serrors.IsInternalError(err) || !strings.Contains(err.Error(), "quota exceeded") {
+		t.Errorf("expected the injected create error, got %v", err)
+	}
+	if _, err := clients.KubeClient.CoreV1().Nodes().List(v1.ListOptions{}); err == nil || !strings.Contains(err.Error(), "forbidden") {
+		t.Errorf("expected the injected list error, got %v", err)
+	}
+	// the errors are injected for the given verb only
+	if _, err := pods.Get("nginx-1", v1.GetOptions{}); err != nil {
+		t.Errorf("unable to get the pod, err: %v", err)
+	}
+	if len(server.Actions) != 0 {
+		t.Errorf("expected no action for the failed requests, got %v", server.Actions)
+	}
+}
+
+func TestCustomResources(t *testing.T) {
+
+	engine := &v1alpha1.ChaosEngine{
+		TypeMeta:   v1.TypeMeta{Kind: "ChaosEngine", APIVersion: "litmuschaos.io/v1alpha1"},
+		ObjectMeta: v1.ObjectMeta{Name: "nginx-chaos", Namespace: chaosNamespace},
+		Spec:       v1alpha1.ChaosEngineSpec{ChaosServiceAccount: "litmus-admin"},
+	}
+	clients, server := newTestClientSets(t, engine)
+	defer server.Close()
+
+	got, err := clients.LitmusClient.ChaosEngines(chaosNamespace).Get("nginx-chaos", v1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get the chaosengine, err: %v", err)
+	}
+	if got.Spec.ChaosServiceAccount != "litmus-admin" {
+		t.Errorf("got %v, want litmus-admin service account", got.Spec.ChaosServiceAccount)
+	}
+
+	// the custom resources are served to the dynamic client from the same collection
+	gvr := schema.GroupVersionResource{Group: "litmuschaos.io", Version: "v1alpha1", Resource: "chaosengines"}
+	list, err := clients.DynamicClient.Resource(gvr).Namespace(chaosNamespace).List(v1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unable to list the chaosengines, err: %v", err)
+	}
+	if len(list.Items) != 1 || list.Items[0].GetName() != "nginx-chaos" {
+		t.Errorf("expected the nginx-chaos chaosengine, got %v", list.Items)
+	}
+
+	result := &v1alpha1.ChaosResult{ObjectMeta: v1.ObjectMeta{Name: "nginx-chaos-pod-delete"}}
+	if _, err := clients.LitmusClient.ChaosResults(chaosNamespace).Create(result); err != nil {
+		t.Fatalf("unable to create the chaosresult, err: %v", err)
+	}
+	if found, err := server.Get("/apis/litmuschaos.io/v1alpha1/namespaces/litmus/chaosresults", "nginx-chaos-pod-delete", &v1alpha1.ChaosResult{}); err != nil || !found {
+		t.Errorf("expected the chaosresult to be stored, err: %v", err)
+	}
+}
+
+func TestParsePath(t *testing.T) {
+
+	tests := []struct {
+		path     string
+		want     request
+		wantOK   bool
+		testName string
+	}{
+		{
+			path:     "/api/v1/nodes",
+			want:     request{groupVersion: "v1", path: "/api/v1/nodes", resource: "nodes"},
+			wantOK:   true,
+			testName: "cluster scoped collection",
+		},
+		{
+			path:     "/api/v1/namespaces/litmus/pods/helper/log",
+			want:     request{groupVersion: "v1", path: "/api/v1/namespaces/litmus/pods", namespace: "litmus", resource: "pods", name: "helper", subresource: "log"},
+			wantOK:   true,
+			testName: "log subresource",
+		},
+		{
+			path:     "/api/v1/namespaces/litmus/pods/helper/status",
+			want:     request{groupVersion: "v1", path: "/api/v1/namespaces/litmus/pods", namespace: "litmus", resource: "pods", name: "helper"},
+			wantOK:   true,
+			testName: "status subresource",
+		},
+		{
+			path:     "/apis/litmuschaos.io/v1alpha1/namespaces/litmus/chaosengines/engine",
+			want:     request{groupVersion: "litmuschaos.io/v1alpha1", path: "/apis/litmuschaos.io/v1alpha1/namespaces/litmus/chaosengines", namespace: "litmus", resource: "chaosengines", name: "engine"},
+			wantOK:   true,
+			testName: "custom resource",
+		},
+		{
+			path:     "/api/v1/namespaces/litmus",
+			want:     request{groupVersion: "v1", path: "/api/v1/namespaces", resource: "namespaces", name: "litmus"},
+			wantOK:   true,
+			testName: "namespace",
+		},
+		{
+			path:     "/version",
+			testName: "non resource path",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			got, ok := parsePath(tt.path)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("got %+v, %v, want %+v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestResourceName(t *testing.T) {
+
+	for kind, want := range map[string]string{"Pod": "pods", "ChaosEngine": "chaosengines", "Ingress": "ingresses", "NetworkPolicy": "networkpolicies"} {
+		if got := resourceName(kind); got != want {
+			t.Errorf("%v: got %v, want %v", kind, got, want)
+		}
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+
+	tests := []struct {
+		object   string
+		patch    string
+		want     string
+		testName string
+	}{
+		{
+			object:   `{"a":{"b":1,"c":2}}`,
+			patch:    `{"a":{"b":3}}`,
+			want:     `{"a":{"b":3,"c":2}}`,
+			testName: "nested field",
+		},
+		{
+			object:   `{"a":{"b":1,"c":2}}`,
+			patch:    `{"a":{"b":null}}`,
+			want:     `{"a":{"c":2}}`,
+			testName: "null removes the field",
+		},
+		{
+			object:   `{"a":[1,2]}`,
+			patch:    `{"a":[3]}`,
+			want:     `{"a":[3]}`,
+			testName: "lists are replaced",
+		},
+		{
+			object:   `{"a":1}`,
+			patch:    `{"b":{"c":1}}`,
+			want:     `{"a":1,"b":{"c":1}}`,
+			testName: "missing object",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			var object, patch, want interface{}
+			for _, v := range []struct {
+				data string
+				into *interface{}
+			}{{tt.object, &object}, {tt.patch, &patch}, {tt.want, &want}} {
+				if err := json.Unmarshal([]byte(v.data), v.into); err != nil {
+					t.Fatalf("invalid json %v, err: %v", v.data, err)
+				}
+			}
+			if got := mergePatch(object, patch); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
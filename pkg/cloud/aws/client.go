@@ -0,0 +1,137 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// roleSessionName is the session name of the assumed role, it shows up in the cloudtrail events
+const roleSessionName = "litmus-chaos"
+
+// EC2API is the subset of the ec2iface.EC2API used by the provider
+// *ec2.EC2 satisfies it, and it can be replaced with a stub client in tests
+type EC2API interface {
+	StopInstances(*ec2.StopInstancesInput) (*ec2.StopInstancesOutput, error)
+	StartInstances(*ec2.StartInstancesInput) (*ec2.StartInstancesOutput, error)
+	RebootInstances(*ec2.RebootInstancesInput) (*ec2.RebootInstancesOutput, error)
+	DescribeInstances(*ec2.DescribeInstancesInput) (*ec2.DescribeInstancesOutput, error)
+	DescribeVolumes(*ec2.DescribeVolumesInput) (*ec2.DescribeVolumesOutput, error)
+	DetachVolume(*ec2.DetachVolumeInput) (*ec2.VolumeAttachment, error)
+	AttachVolume(*ec2.AttachVolumeInput) (*ec2.VolumeAttachment, error)
+	ModifyNetworkInterfaceAttribute(*ec2.ModifyNetworkInterfaceAttributeInput) (*ec2.ModifyNetworkInterfaceAttributeOutput, error)
+	DescribeNetworkAcls(*ec2.DescribeNetworkAclsInput) (*ec2.DescribeNetworkAclsOutput, error)
+	ReplaceNetworkAclAssociation(*ec2.ReplaceNetworkAclAssociationInput) (*ec2.ReplaceNetworkAclAssociationOutput, error)
+}
+
+// STSAPI is the subset of the stsiface.STSAPI used by the provider to verify the credentials
+type STSAPI interface {
+	GetCallerIdentity(*sts.GetCallerIdentityInput) (*sts.GetCallerIdentityOutput, error)
+}
+
+// Config contains the session configuration of the aws provider
+type Config struct {
+	// Region is the aws region of the target resources
+	Region string
+	// AssumeRoleARN is the role assumed on top of the base credentials, if provided
+	AssumeRoleARN string
+	// ExternalID is passed while assuming the role, if the trust policy of the role requires it
+	ExternalID string
+	// EndpointURL overrides the aws endpoint, ex: a local ec2 stand-in like localstack or moto
+	EndpointURL string
+}
+
+// Provider implements the cloud.NetworkProvider for aws ec2
+type Provider struct {
+	client    EC2API
+	stsClient STSAPI
+}
+
+var _ cloud.NetworkProvider = &Provider{}
+var _ cloud.Rebooter = &Provider{}
+
+// NewProvider creates the aws provider for the given config
+// the base credentials are resolved by the sdk credential chain in the order of the env variables,
+// the web identity token of the IAM Roles for Service Accounts (IRSA) and the shared credentials file.
+// the session is created only once and reused for all the calls
+func NewProvider(config Config) (*Provider, error) {
+
+	awsConfig := aws.Config{Region: aws.String(config.Region)}
+	if config.EndpointURL != "" {
+		awsConfig.Endpoint = aws.String(config.EndpointURL)
+	}
+
+	// Load session from shared config
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+		Config:            awsConfig,
+	})
+	if err != nil {
+		return nil, errors.Errorf("fail to create the aws session, err: %v", err)
+	}
+
+	if config.AssumeRoleARN != "" {
+		log.Infof("[Info]: Assuming the %v role", config.AssumeRoleARN)
+		sess = sess.Copy(&aws.Config{
+			Credentials: stscreds.NewCredentials(sess, config.AssumeRoleARN, func(p *stscreds.AssumeRoleProvider) {
+				p.RoleSessionName = roleSessionName
+				if config.ExternalID != "" {
+					p.ExternalID = aws.String(config.ExternalID)
+				}
+			}),
+		})
+	}
+
+	// Create new EC2 client
+	return NewProviderWithClient(ec2.New(sess), sts.New(sess)), nil
+}
+
+// NewVerifiedProvider creates the aws provider for the given config and verifies its credentials
+// it is used by the experiments to surface the session and credential errors before any aws call
+func NewVerifiedProvider(config Config) (*Provider, error) {
+
+	provider, err := NewProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := provider.CheckCredentials(); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// NewProviderWithClient creates the aws provider with the injected ec2 and sts clients
+func NewProviderWithClient(client EC2API, stsClient STSAPI) *Provider {
+	return &Provider{client: client, stsClient: stsClient}
+}
+
+// CheckCredentials verifies that the credentials of the provider are valid, it resolves the caller identity
+// so that the credential errors are surfaced before the chaos injection
+func (p *Provider) CheckCredentials() error {
+
+	result, err := p.stsClient.GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	if err != nil {
+		return errors.Errorf("unable to verify the aws credentials, provide the credentials file, IRSA or assume role configuration, err: %v", awsError(err))
+	}
+
+	log.InfoWithValues("[Info]: The aws caller identity is:", logrus.Fields{
+		"Account": aws.StringValue(result.Account),
+		"Arn":     aws.StringValue(result.Arn),
+	})
+	return nil
+}
+
+// awsError converts the aws error into the error returned by the provider
+func awsError(err error) error {
+	if aerr, ok := err.(awserr.Error); ok {
+		return errors.Errorf(aerr.Error())
+	}
+	return errors.Errorf(err.Error())
+}
@@ -0,0 +1,126 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// DescribeVolumes will give the details of the given ebs volumes
+func (p *Provider) DescribeVolumes(volumeIDs ...string) ([]cloud.Volume, error) {
+
+	input := &ec2.DescribeVolumesInput{
+		VolumeIds: aws.StringSlice(volumeIDs),
+	}
+	return p.describeVolumes(input)
+}
+
+// ListVolumesByTag will filter out the target volumes using the tag filter, the tag is in key:value format
+func (p *Provider) ListVolumesByTag(tag string) ([]string, error) {
+
+	filter, err := tagFilter(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &ec2.DescribeVolumesInput{
+		Filters: []*ec2.Filter{filter},
+	}
+	volumes, err := p.describeVolumes(input)
+	if err != nil {
+		return nil, errors.Errorf("fail to list the volumes, err: %v", err)
+	}
+
+	var volumeList []string
+	for _, volume := range volumes {
+		volumeList = append(volumeList, volume.ID)
+	}
+	return volumeList, nil
+}
+
+// describeVolumes lists all the pages of the volumes matching the input
+func (p *Provider) describeVolumes(input *ec2.DescribeVolumesInput) ([]cloud.Volume, error) {
+
+	var volumes []cloud.Volume
+	for {
+		result, err := p.client.DescribeVolumes(input)
+		if err != nil {
+			return nil, awsError(err)
+		}
+		for _, volumeDetails := range result.Volumes {
+			volumes = append(volumes, toVolume(volumeDetails))
+		}
+		if aws.StringValue(result.NextToken) == "" {
+			break
+		}
+		input.NextToken = result.NextToken
+	}
+	return volumes, nil
+}
+
+// DetachVolume will detach the ebs volume from the ec2 instance
+func (p *Provider) DetachVolume(volumeID string) error {
+
+	input := &ec2.DetachVolumeInput{
+		VolumeId: aws.String(volumeID),
+	}
+
+	result, err := p.client.DetachVolume(input)
+	if err != nil {
+		return awsError(err)
+	}
+
+	log.InfoWithValues("Detaching ebs having:", logrus.Fields{
+		"VolumeId":   aws.StringValue(result.VolumeId),
+		"State":      aws.StringValue(result.State),
+		"Device":     aws.StringValue(result.Device),
+		"InstanceId": aws.StringValue(result.InstanceId),
+	})
+
+	return nil
+}
+
+// AttachVolume will attach the ebs volume to the ec2 instance
+func (p *Provider) AttachVolume(volumeID, instanceID, device string) error {
+
+	input := &ec2.AttachVolumeInput{
+		Device:     aws.String(device),
+		InstanceId: aws.String(instanceID),
+		VolumeId:   aws.String(volumeID),
+	}
+
+	result, err := p.client.AttachVolume(input)
+	if err != nil {
+		return awsError(err)
+	}
+
+	log.InfoWithValues("Attaching ebs having:", logrus.Fields{
+		"VolumeId":   aws.StringValue(result.VolumeId),
+		"State":      aws.StringValue(result.State),
+		"Device":     aws.StringValue(result.Device),
+		"InstanceId": aws.StringValue(result.InstanceId),
+	})
+	return nil
+}
+
+// toVolume converts the ebs volume into the provider volume
+func toVolume(volumeDetails *ec2.Volume) cloud.Volume {
+
+	volume := cloud.Volume{
+		ID:    aws.StringValue(volumeDetails.VolumeId),
+		Type:  aws.StringValue(volumeDetails.VolumeType),
+		State: aws.StringValue(volumeDetails.State),
+		Tags:  toTags(volumeDetails.Tags),
+	}
+	for _, attachment := range volumeDetails.Attachments {
+		volume.Attachments = append(volume.Attachments, cloud.VolumeAttachment{
+			InstanceID: aws.StringValue(attachment.InstanceId),
+			Device:     aws.StringValue(attachment.Device),
+			State:      aws.StringValue(attachment.State),
+		})
+	}
+	return volume
+}
@@ -2,45 +2,26 @@ package aws
 
 import (
 	"strings"
-	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
 	"github.com/litmuschaos/litmus-go/pkg/log"
-	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
-// EC2Stop will stop an aws ec2 instance
-func EC2Stop(instanceID, region string) error {
-
-	// Load session from shared config
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-		Config:            aws.Config{Region: aws.String(region)},
-	}))
-
-	// Create new EC2 client
-	ec2Svc := ec2.New(sess)
+// StopInstance will stop an aws ec2 instance
+func (p *Provider) StopInstance(instanceID string) error {
 
 	input := &ec2.StopInstancesInput{
 		InstanceIds: []*string{
 			aws.String(instanceID),
 		},
 	}
-	result, err := ec2Svc.StopInstances(input)
+	result, err := p.client.StopInstances(input)
 	if err != nil {
-		if aerr, ok := err.(awserr.Error); ok {
-			switch aerr.Code() {
-			default:
-				return errors.Errorf(aerr.Error())
-			}
-		} else {
-			return errors.Errorf(err.Error())
-		}
+		return awsError(err)
 	}
 
 	log.InfoWithValues("Stopping an ec2 instance:", logrus.Fields{
@@ -52,17 +33,8 @@ func EC2Stop(instanceID, region string) error {
 	return nil
 }
 
-// EC2Start will stop an aws ec2 instance
-func EC2Start(instanceID, region string) error {
-
-	// Load session from shared config
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-		Config:            aws.Config{Region: aws.String(region)},
-	}))
-
-	// Create new EC2 client
-	ec2Svc := ec2.New(sess)
+// StartInstance will start an aws ec2 instance
+func (p *Provider) StartInstance(instanceID string) error {
 
 	input := &ec2.StartInstancesInput{
 		InstanceIds: []*string{
@@ -70,16 +42,9 @@ func EC2Start(instanceID, region string) error {
 		},
 	}
 
-	result, err := ec2Svc.StartInstances(input)
+	result, err := p.client.StartInstances(input)
 	if err != nil {
-		if aerr, ok := err.(awserr.Error); ok {
-			switch aerr.Code() {
-			default:
-				return errors.Errorf(aerr.Error())
-			}
-		} else {
-			return errors.Errorf(err.Error())
-		}
+		return awsError(err)
 	}
 
 	log.InfoWithValues("Starting ec2 instance:", logrus.Fields{
@@ -91,99 +56,162 @@ func EC2Start(instanceID, region string) error {
 	return nil
 }
 
-//WaitForEC2Down will wait for the ec2 instance to get in stopped state
-func WaitForEC2Down(timeout, delay int, managedNodegroup, region, instanceID string) error {
+// RebootInstance will reboot an aws ec2 instance, the reboot request is queued by ec2
+func (p *Provider) RebootInstance(instanceID string) error {
 
-	log.Info("[Status]: Checking EC2 instance status")
-	err := retry.
-		Times(uint(timeout / delay)).
-		Wait(time.Duration(delay) * time.Second).
-		Try(func(attempt uint) error {
-
-			instanceState, err := GetEC2InstanceStatus(instanceID, region)
-			if err != nil {
-				return errors.Errorf("failed to get the instance status")
-			}
-			if (managedNodegroup != "enable" && instanceState != "stopped") || (managedNodegroup == "enable" && instanceState != "terminated") {
-				log.Infof("The instance state is %v", instanceState)
-				return errors.Errorf("instance is not yet in stopped state")
-			}
-			log.Infof("The instance state is %v", instanceState)
-			return nil
-		})
-	if err != nil {
-		return err
+	input := &ec2.RebootInstancesInput{
+		InstanceIds: []*string{
+			aws.String(instanceID),
+		},
+	}
+	if _, err := p.client.RebootInstances(input); err != nil {
+		return awsError(err)
 	}
+
+	log.InfoWithValues("Rebooting ec2 instance:", logrus.Fields{
+		"InstanceId": instanceID,
+	})
+
 	return nil
 }
 
-//WaitForEC2Up will wait for the ec2 instance to get in running state
-func WaitForEC2Up(timeout, delay int, managedNodegroup, region, instanceID string) error {
+// DescribeInstances will give the details of the given ec2 instances
+func (p *Provider) DescribeInstances(instanceIDs ...string) ([]cloud.Instance, error) {
 
-	log.Info("[Status]: Checking EC2 instance status")
-	err := retry.
-		Times(uint(timeout / delay)).
-		Wait(time.Duration(delay) * time.Second).
-		Try(func(attempt uint) error {
+	input := &ec2.DescribeInstancesInput{
+		InstanceIds: aws.StringSlice(instanceIDs),
+	}
+	return p.describeInstances(input)
+}
 
-			instanceState, err := GetEC2InstanceStatus(instanceID, region)
-			if err != nil {
-				return errors.Errorf("failed to get the instance status")
-			}
-			if instanceState != "running" {
-				log.Infof("The instance state is %v", instanceState)
-				return errors.Errorf("instance is not yet in running state")
-			}
-			log.Infof("The instance state is %v", instanceState)
-			return nil
-		})
+// ListByTag will filter out the target instances using the tag filter, the tag is in key:value format
+func (p *Provider) ListByTag(tag string) ([]string, error) {
+
+	filter, err := tagFilter(tag)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
-}
 
-//GetInstanceList will filter out the target instance under chaos using tag filters or the instance list provided.
-func GetInstanceList(instanceTag, region string) ([]string, error) {
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{filter},
+	}
+	instances, err := p.describeInstances(input)
+	if err != nil {
+		return nil, errors.Errorf("fail to list the instances, err: %v", err)
+	}
 
 	var instanceList []string
-	switch instanceTag {
-	case "":
-		return nil, errors.Errorf("fail to get the instance tag please provide a valid instance tag")
-
-	default:
-		instanceTag := strings.Split(instanceTag, ":")
-		sess := session.Must(session.NewSessionWithOptions(session.Options{
-			SharedConfigState: session.SharedConfigEnable,
-			Config:            aws.Config{Region: aws.String(region)},
-		}))
-
-		params := &ec2.DescribeInstancesInput{
-			Filters: []*ec2.Filter{
-				&ec2.Filter{
-					Name: aws.String("tag:" + instanceTag[0]),
-					Values: []*string{
-						aws.String(instanceTag[1]),
-					},
-				},
+	for _, instance := range instances {
+		instanceList = append(instanceList, instance.ID)
+	}
+	return instanceList, nil
+}
+
+// ListByZone will filter out the running instances of the given availability zone
+func (p *Provider) ListByZone(zone string) ([]string, error) {
+
+	if zone == "" {
+		return nil, errors.Errorf("fail to get the zone please provide a valid availability zone")
+	}
+
+	input := &ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("availability-zone"),
+				Values: []*string{aws.String(zone)},
 			},
-		}
-		ec2Svc := ec2.New(sess)
-		res, err := ec2Svc.DescribeInstances(params)
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: []*string{aws.String(cloud.InstanceRunning)},
+			},
+		},
+	}
+	instances, err := p.describeInstances(input)
+	if err != nil {
+		return nil, errors.Errorf("fail to list the instances, err: %v", err)
+	}
+
+	var instanceList []string
+	for _, instance := range instances {
+		instanceList = append(instanceList, instance.ID)
+	}
+	return instanceList, nil
+}
+
+// describeInstances lists all the pages of the instances matching the input
+func (p *Provider) describeInstances(input *ec2.DescribeInstancesInput) ([]cloud.Instance, error) {
+
+	var instances []cloud.Instance
+	for {
+		result, err := p.client.DescribeInstances(input)
 		if err != nil {
-			return nil, errors.Errorf("fail to list the insances, err: %v", err.Error())
+			return nil, awsError(err)
 		}
-
-		for _, reservationDetails := range res.Reservations {
-			for _, i := range reservationDetails.Instances {
-				for _, t := range i.Tags {
-					if *t.Key == instanceTag[0] {
-						instanceList = append(instanceList, *i.InstanceId)
-						break
-					}
-				}
+		for _, reservationDetails := range result.Reservations {
+			for _, instanceDetails := range reservationDetails.Instances {
+				instances = append(instances, toInstance(instanceDetails))
 			}
 		}
+		if aws.StringValue(result.NextToken) == "" {
+			break
+		}
+		input.NextToken = result.NextToken
 	}
-	return instanceList, nil
+	return instances, nil
+}
+
+// toInstance converts the ec2 instance into the provider instance
+func toInstance(instanceDetails *ec2.Instance) cloud.Instance {
+
+	instance := cloud.Instance{
+		ID:       aws.StringValue(instanceDetails.InstanceId),
+		SubnetID: aws.StringValue(instanceDetails.SubnetId),
+		Tags:     toTags(instanceDetails.Tags),
+	}
+	if instanceDetails.State != nil {
+		instance.State = aws.StringValue(instanceDetails.State.Name)
+	}
+	if instanceDetails.Placement != nil {
+		instance.AvailabilityZone = aws.StringValue(instanceDetails.Placement.AvailabilityZone)
+	}
+	for _, networkInterface := range instanceDetails.NetworkInterfaces {
+		var securityGroups []string
+		for _, group := range networkInterface.Groups {
+			securityGroups = append(securityGroups, aws.StringValue(group.GroupId))
+		}
+		instance.NetworkInterfaces = append(instance.NetworkInterfaces, cloud.NetworkInterface{
+			ID:             aws.StringValue(networkInterface.NetworkInterfaceId),
+			SubnetID:       aws.StringValue(networkInterface.SubnetId),
+			SecurityGroups: securityGroups,
+		})
+	}
+	return instance
+}
+
+// toTags converts the ec2 tags into a map
+func toTags(ec2Tags []*ec2.Tag) map[string]string {
+
+	tags := map[string]string{}
+	for _, t := range ec2Tags {
+		tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+	}
+	return tags
+}
+
+// tagFilter builds the ec2 filter for the tag provided in key:value format
+func tagFilter(tag string) (*ec2.Filter, error) {
+
+	if tag == "" {
+		return nil, errors.Errorf("fail to get the tag please provide a valid tag")
+	}
+	keyValue := strings.Split(tag, ":")
+	if len(keyValue) != 2 {
+		return nil, errors.Errorf("fail to parse the tag %v, provide it in key:value format", tag)
+	}
+	return &ec2.Filter{
+		Name: aws.String("tag:" + keyValue[0]),
+		Values: []*string{
+			aws.String(keyValue[1]),
+		},
+	}, nil
 }
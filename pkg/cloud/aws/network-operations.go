@@ -0,0 +1,82 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// SetSecurityGroups will replace the security groups of the network interface
+func (p *Provider) SetSecurityGroups(networkInterfaceID string, securityGroupIDs []string) error {
+
+	if len(securityGroupIDs) == 0 {
+		return errors.Errorf("fail to set the security groups of %v network interface, no security group provided", networkInterfaceID)
+	}
+
+	input := &ec2.ModifyNetworkInterfaceAttributeInput{
+		NetworkInterfaceId: aws.String(networkInterfaceID),
+		Groups:             aws.StringSlice(securityGroupIDs),
+	}
+	if _, err := p.client.ModifyNetworkInterfaceAttribute(input); err != nil {
+		return awsError(err)
+	}
+
+	log.InfoWithValues("Setting the security groups of network interface:", logrus.Fields{
+		"NetworkInterfaceId": networkInterfaceID,
+		"SecurityGroups":     securityGroupIDs,
+	})
+	return nil
+}
+
+// GetNetworkACLAssociation will give the network acl association of the subnet
+func (p *Provider) GetNetworkACLAssociation(subnetID string) (cloud.NetworkACLAssociation, error) {
+
+	input := &ec2.DescribeNetworkAclsInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("association.subnet-id"),
+				Values: []*string{aws.String(subnetID)},
+			},
+		},
+	}
+	result, err := p.client.DescribeNetworkAcls(input)
+	if err != nil {
+		return cloud.NetworkACLAssociation{}, awsError(err)
+	}
+
+	for _, networkACL := range result.NetworkAcls {
+		for _, association := range networkACL.Associations {
+			if aws.StringValue(association.SubnetId) == subnetID {
+				return cloud.NetworkACLAssociation{
+					ID:           aws.StringValue(association.NetworkAclAssociationId),
+					NetworkACLID: aws.StringValue(association.NetworkAclId),
+					SubnetID:     subnetID,
+				}, nil
+			}
+		}
+	}
+	return cloud.NetworkACLAssociation{}, errors.Errorf("unable to find the network acl association of %v subnet", subnetID)
+}
+
+// ReplaceNetworkACLAssociation will associate the network acl with the subnet of the given association
+func (p *Provider) ReplaceNetworkACLAssociation(associationID, networkACLID string) (string, error) {
+
+	input := &ec2.ReplaceNetworkAclAssociationInput{
+		AssociationId: aws.String(associationID),
+		NetworkAclId:  aws.String(networkACLID),
+	}
+	result, err := p.client.ReplaceNetworkAclAssociation(input)
+	if err != nil {
+		return "", awsError(err)
+	}
+
+	log.InfoWithValues("Replacing the network acl association:", logrus.Fields{
+		"AssociationId":    associationID,
+		"NetworkAclId":     networkACLID,
+		"NewAssociationId": aws.StringValue(result.NewAssociationId),
+	})
+	return aws.StringValue(result.NewAssociationId), nil
+}
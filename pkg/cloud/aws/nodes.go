@@ -0,0 +1,125 @@
+package aws
+
+import (
+	"strings"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodegroupLabel is the label set by eks on the nodes of a managed nodegroup
+const NodegroupLabel = "eks.amazonaws.com/nodegroup"
+
+// TargetNode contains the target node along with its ec2 instance id
+type TargetNode struct {
+	NodeName   string
+	InstanceID string
+}
+
+// GetTargetNodes selects the target nodes using the node selector
+// and maps them to their ec2 instance ids using the provider id of the node
+func GetTargetNodes(selector common.NodeSelector, clients clients.ClientSets) ([]TargetNode, error) {
+
+	nodeNames, err := common.GetTargetNodes(selector, clients)
+	if err != nil {
+		return nil, err
+	}
+
+	var targetNodes []TargetNode
+	for _, nodeName := range nodeNames {
+		node, err := clients.KubeClient.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Errorf("fail to get the %v node, err: %v", nodeName, err)
+		}
+		instanceID, err := GetInstanceIDFromProviderID(node.Spec.ProviderID)
+		if err != nil {
+			return nil, errors.Errorf("fail to get the instance id of %v node, err: %v", nodeName, err)
+		}
+		log.InfoWithValues("[Info]: The target node is:", logrus.Fields{
+			"NodeName":   nodeName,
+			"InstanceId": instanceID,
+		})
+		targetNodes = append(targetNodes, TargetNode{NodeName: nodeName, InstanceID: instanceID})
+	}
+	return targetNodes, nil
+}
+
+// GetInstanceIDFromProviderID extracts the ec2 instance id from the provider id of the node
+// the provider id is in the aws:///<zone>/<instance-id> format
+func GetInstanceIDFromProviderID(providerID string) (string, error) {
+
+	if !strings.HasPrefix(providerID, "aws://") {
+		return "", errors.Errorf("the provider id %v is not an aws provider id", providerID)
+	}
+	parts := strings.Split(providerID, "/")
+	instanceID := parts[len(parts)-1]
+	if !strings.HasPrefix(instanceID, "i-") {
+		return "", errors.Errorf("unable to parse the instance id from the provider id %v", providerID)
+	}
+	return instanceID, nil
+}
+
+// PreChaosTargetNodeStatusCheck verifies that the target nodes are ready and returns the count of ready nodes matching the selector
+func PreChaosTargetNodeStatusCheck(targetNodes []TargetNode, selector common.NodeSelector, clients clients.ClientSets) (int, error) {
+
+	for _, target := range targetNodes {
+		node, err := clients.KubeClient.CoreV1().Nodes().Get(target.NodeName, metav1.GetOptions{})
+		if err != nil {
+			return 0, errors.Errorf("fail to get the %v node, err: %v", target.NodeName, err)
+		}
+		if !common.IsNodeReady(*node) {
+			return 0, errors.Errorf("the target node %v is not in ready state", target.NodeName)
+		}
+	}
+	return getReadyNodeCount(selector, nil, clients)
+}
+
+// WaitForReplacementNodes waits for the replacement nodes of the terminated target nodes to join the cluster
+// it expects the ready nodes matching the selector, excluding the target nodes, to reach the pre chaos count
+func WaitForReplacementNodes(targetNodes []TargetNode, selector common.NodeSelector, readyNodeCount, timeout, delay int, clients clients.ClientSets) error {
+
+	excludedNodes := map[string]bool{}
+	for _, target := range targetNodes {
+		excludedNodes[target.NodeName] = true
+	}
+
+	log.Info("[Status]: Checking for the replacement nodes")
+	return retry.
+		Times(uint(timeout / delay)).
+		Wait(time.Duration(delay) * time.Second).
+		Try(func(attempt uint) error {
+
+			count, err := getReadyNodeCount(selector, excludedNodes, clients)
+			if err != nil {
+				return err
+			}
+			log.Infof("[Info]: Ready replacement nodes: %v, expected: %v", count, readyNodeCount)
+			if count < readyNodeCount {
+				return errors.Errorf("replacement nodes are not yet ready")
+			}
+			return nil
+		})
+}
+
+// getReadyNodeCount counts the ready nodes matching the selector, except the excluded nodes
+func getReadyNodeCount(selector common.NodeSelector, excludedNodes map[string]bool, clients clients.ClientSets) (int, error) {
+
+	// the replacement nodes do not host the application pods yet, so only the node attributes are matched
+	nodes, err := common.GetNodesBySelector(selector, clients)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, node := range nodes {
+		if !excludedNodes[node.Name] && common.IsNodeReady(node) {
+			count++
+		}
+	}
+	return count, nil
+}
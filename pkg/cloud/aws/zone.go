@@ -0,0 +1,125 @@
+package aws
+
+import (
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetZoneTargetsByNodes selects the instances of all the nodes in the zone using the node topology labels
+// the node label can be used to restrict the selection to the worker nodes
+func GetZoneTargetsByNodes(zone, nodeLabel string, clients clients.ClientSets) ([]TargetNode, error) {
+
+	if zone == "" {
+		return nil, errors.Errorf("fail to get the zone please provide a valid availability zone")
+	}
+
+	nodes, err := common.GetNodesBySelector(common.NodeSelector{NodeLabel: nodeLabel, Zone: zone}, clients)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) == 0 {
+		return nil, errors.Errorf("no node found in the %v zone", zone)
+	}
+
+	var targets []TargetNode
+	for _, node := range nodes {
+		instanceID, err := GetInstanceIDFromProviderID(node.Spec.ProviderID)
+		if err != nil {
+			return nil, errors.Errorf("fail to get the instance id of %v node, err: %v", node.Name, err)
+		}
+		targets = append(targets, TargetNode{NodeName: node.Name, InstanceID: instanceID})
+	}
+	logZoneTargets(zone, targets)
+	return targets, nil
+}
+
+// GetZoneTargetsByFilter selects the running instances of the zone using the ec2 availability-zone filter
+// the instance tag is mandatory, it restricts the selection to the instances having the tag
+// the instances are mapped to the cluster nodes using the provider id and the selection fails
+// if any of the tagged instances is not a cluster node, to avoid stopping or isolating the non-node instances
+func GetZoneTargetsByFilter(zone, instanceTag string, provider cloud.Provider, clients clients.ClientSets) ([]TargetNode, error) {
+
+	if instanceTag == "" {
+		return nil, errors.Errorf("please provide the INSTANCE_TAG to select the instances of the %v zone", zone)
+	}
+
+	instanceIDs, err := provider.ListByZone(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	taggedIDs, err := provider.ListByTag(instanceTag)
+	if err != nil {
+		return nil, err
+	}
+	tagged := map[string]bool{}
+	for _, id := range taggedIDs {
+		tagged[id] = true
+	}
+	var filteredIDs []string
+	for _, id := range instanceIDs {
+		if tagged[id] {
+			filteredIDs = append(filteredIDs, id)
+		}
+	}
+	if len(filteredIDs) == 0 {
+		return nil, errors.Errorf("no running instance found in the %v zone with the %v tag", zone, instanceTag)
+	}
+
+	nodeList, err := clients.KubeClient.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Errorf("fail to get the nodes, err: %v", err)
+	}
+	nodeNames := map[string]string{}
+	for _, node := range nodeList.Items {
+		if instanceID, err := GetInstanceIDFromProviderID(node.Spec.ProviderID); err == nil {
+			nodeNames[instanceID] = node.Name
+		}
+	}
+
+	var targets []TargetNode
+	var nonNodeIDs []string
+	for _, id := range filteredIDs {
+		if nodeNames[id] == "" {
+			nonNodeIDs = append(nonNodeIDs, id)
+			continue
+		}
+		targets = append(targets, TargetNode{NodeName: nodeNames[id], InstanceID: id})
+	}
+	if len(nonNodeIDs) != 0 {
+		return nil, errors.Errorf("the instances %v of the %v zone with the %v tag are not cluster nodes, please provide a tag matching only the cluster nodes", nonNodeIDs, zone, instanceTag)
+	}
+	logZoneTargets(zone, targets)
+	return targets, nil
+}
+
+// GetPodZone returns the zone of the node hosting the given pod
+func GetPodZone(podName, namespace string, clients clients.ClientSets) (string, error) {
+
+	pod, err := clients.KubeClient.CoreV1().Pods(namespace).Get(podName, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Errorf("fail to get the %v pod, err: %v", podName, err)
+	}
+	node, err := clients.KubeClient.CoreV1().Nodes().Get(pod.Spec.NodeName, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Errorf("fail to get the %v node, err: %v", pod.Spec.NodeName, err)
+	}
+	return common.GetNodeZone(*node), nil
+}
+
+// logZoneTargets logs the target instances of the zone
+func logZoneTargets(zone string, targets []TargetNode) {
+	for _, target := range targets {
+		log.InfoWithValues("[Info]: The target instance is:", logrus.Fields{
+			"Zone":       zone,
+			"NodeName":   target.NodeName,
+			"InstanceId": target.InstanceID,
+		})
+	}
+	log.Infof("[Chaos]:Number of instances targeted in %v zone: %v", zone, len(targets))
+}
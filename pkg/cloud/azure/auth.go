@@ -0,0 +1,107 @@
+package azure
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// defaultAuthorityURL is the azure active directory endpoint
+	defaultAuthorityURL = "https://login.microsoftonline.com/"
+	// defaultResourceManagerURL is the azure resource manager endpoint
+	defaultResourceManagerURL = "https://management.azure.com/"
+)
+
+// authFile contains the fields of the sdk auth file, created by az ad sp create-for-rbac --sdk-auth
+type authFile struct {
+	ClientID                   string `json:"clientId"`
+	ClientSecret               string `json:"clientSecret"`
+	SubscriptionID             string `json:"subscriptionId"`
+	TenantID                   string `json:"tenantId"`
+	ActiveDirectoryEndpointURL string `json:"activeDirectoryEndpointUrl"`
+	ResourceManagerEndpointURL string `json:"resourceManagerEndpointUrl"`
+}
+
+// tokenResponse is the response of the token endpoint
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// clientCredentialsTokenSource fetches the access token of the service principal using the client credentials grant
+// the client is authenticated with the client secret, or the federated token of the workload identity
+type clientCredentialsTokenSource struct {
+	tokenURL           string
+	clientID           string
+	clientSecret       string
+	federatedTokenFile string
+	scope              string
+	client             *http.Client
+}
+
+// readAuthFile reads the sdk auth file
+func readAuthFile(path string) (authFile, error) {
+
+	var auth authFile
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return auth, errors.Errorf("fail to read the azure auth file %v, err: %v", path, err)
+	}
+	if err := json.Unmarshal(data, &auth); err != nil {
+		return auth, errors.Errorf("fail to parse the azure auth file %v, err: %v", path, err)
+	}
+	return auth, nil
+}
+
+// Token fetches the access token from the azure active directory
+func (c *clientCredentialsTokenSource) Token() (*oauth2.Token, error) {
+
+	form := url.Values{
+		"grant_type": {"client_credentials"},
+		"client_id":  {c.clientID},
+		"scope":      {c.scope},
+	}
+	if c.clientSecret != "" {
+		form.Set("client_secret", c.clientSecret)
+	} else {
+		// the federated token is rotated by the kubelet, so it is read on every token request
+		assertion, err := ioutil.ReadFile(c.federatedTokenFile)
+		if err != nil {
+			return nil, errors.Errorf("fail to read the federated token file %v, err: %v", c.federatedTokenFile, err)
+		}
+		form.Set("client_assertion_type", "urn:ietf:params:oauth:client-assertion-type:jwt-bearer")
+		form.Set("client_assertion", strings.TrimSpace(string(assertion)))
+	}
+
+	resp, err := c.client.PostForm(c.tokenURL, form)
+	if err != nil {
+		return nil, errors.Errorf("fail to fetch the access token, err: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Errorf("fail to read the token response, err: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("fail to fetch the access token, status: %v, response: %v", resp.Status, string(body))
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, errors.Errorf("fail to parse the token response, err: %v", err)
+	}
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}, nil
+}
@@ -0,0 +1,182 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// computeAPIVersion is the api version of the compute resource provider
+	computeAPIVersion = "2021-03-01"
+	// subscriptionAPIVersion is the api version of the subscription resource
+	subscriptionAPIVersion = "2020-01-01"
+)
+
+// Config contains the configuration of the azure provider
+type Config struct {
+	// SubscriptionID is the subscription of the target instances
+	SubscriptionID string
+	// TenantID, ClientID and ClientSecret are the service principal credentials
+	TenantID     string
+	ClientID     string
+	ClientSecret string
+	// FederatedTokenFile is the service account token of the workload identity, used if the client secret is empty
+	FederatedTokenFile string
+	// AuthFile is the path of the sdk auth file, it fills the credentials which are not provided
+	AuthFile string
+	// EndpointURL overrides the resource manager endpoint, ex: a fake resource manager in tests
+	EndpointURL string
+}
+
+// Provider implements the cloud.InstanceProvider for azure virtual machines
+// the instance id is in the resourceGroup/vmName format for the virtual machines
+// and in the resourceGroup/scaleSetName/instanceID format for the scale set instances
+type Provider struct {
+	client       *http.Client
+	endpoint     string
+	subscription string
+}
+
+var _ cloud.InstanceProvider = &Provider{}
+var _ cloud.Rebooter = &Provider{}
+
+// apiError is the error response of the resource manager
+type apiError struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewProvider creates the azure provider for the given config
+func NewProvider(config Config) (*Provider, error) {
+
+	authorityURL := defaultAuthorityURL
+	endpoint := defaultResourceManagerURL
+	if config.AuthFile != "" {
+		auth, err := readAuthFile(config.AuthFile)
+		if err != nil {
+			return nil, err
+		}
+		config.SubscriptionID = firstNonEmpty(config.SubscriptionID, auth.SubscriptionID)
+		config.TenantID = firstNonEmpty(config.TenantID, auth.TenantID)
+		config.ClientID = firstNonEmpty(config.ClientID, auth.ClientID)
+		config.ClientSecret = firstNonEmpty(config.ClientSecret, auth.ClientSecret)
+		authorityURL = firstNonEmpty(auth.ActiveDirectoryEndpointURL, authorityURL)
+		endpoint = firstNonEmpty(auth.ResourceManagerEndpointURL, endpoint)
+	}
+	switch {
+	case config.SubscriptionID == "":
+		return nil, errors.Errorf("fail to get the azure subscription please provide a valid subscription id")
+	case config.TenantID == "" || config.ClientID == "":
+		return nil, errors.Errorf("fail to get the azure service principal please provide the tenant id and client id")
+	case config.ClientSecret == "" && config.FederatedTokenFile == "":
+		return nil, errors.Errorf("fail to get the azure credentials please provide the client secret or the federated token file")
+	}
+
+	// the token scope is derived from the real resource manager endpoint, even if the endpoint is overridden
+	scope := strings.TrimSuffix(endpoint, "/") + "/.default"
+	if config.EndpointURL != "" {
+		endpoint = config.EndpointURL
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	tokenSource := &clientCredentialsTokenSource{
+		tokenURL:           strings.TrimSuffix(authorityURL, "/") + "/" + config.TenantID + "/oauth2/v2.0/token",
+		clientID:           config.ClientID,
+		clientSecret:       config.ClientSecret,
+		federatedTokenFile: config.FederatedTokenFile,
+		scope:              scope,
+		client:             httpClient,
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+	return NewProviderWithClient(oauth2.NewClient(ctx, oauth2.ReuseTokenSource(nil, tokenSource)), endpoint, config.SubscriptionID), nil
+}
+
+// NewVerifiedProvider creates the azure provider for the given config and verifies its credentials
+// it is used by the experiments to surface the credential errors before any azure call
+func NewVerifiedProvider(config Config) (*Provider, error) {
+
+	provider, err := NewProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := provider.CheckCredentials(); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// NewProviderWithClient creates the azure provider with the injected http client and endpoint
+func NewProviderWithClient(client *http.Client, endpoint, subscription string) *Provider {
+	if !strings.HasSuffix(endpoint, "/") {
+		endpoint += "/"
+	}
+	return &Provider{client: client, endpoint: endpoint, subscription: subscription}
+}
+
+// CheckCredentials verifies that the service principal can access the subscription
+func (p *Provider) CheckCredentials() error {
+
+	query := url.Values{"api-version": {subscriptionAPIVersion}}
+	if err := p.do(http.MethodGet, p.endpoint+"subscriptions/"+p.subscription+"?"+query.Encode(), nil); err != nil {
+		return errors.Errorf("unable to verify the azure credentials for %v subscription, provide the auth file, service principal or workload identity, err: %v", p.subscription, err)
+	}
+	log.Infof("[Info]: The azure credentials have access to %v subscription", p.subscription)
+	return nil
+}
+
+// do sends the request to the resource manager and decodes the response into out, if provided
+func (p *Provider) do(method, target string, out interface{}) error {
+
+	req, err := http.NewRequest(method, target, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Errorf("fail to read the response, err: %v", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr apiError
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			return errors.Errorf("%v: %v", apiErr.Error.Code, apiErr.Error.Message)
+		}
+		return errors.Errorf("%v %v", resp.Status, string(data))
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return errors.Errorf("fail to parse the response, err: %v", err)
+	}
+	return nil
+}
+
+// firstNonEmpty returns the first non empty value
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,319 @@
+package azure
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// virtualMachine contains the fields of the virtual machine and scale set instance used by the provider
+type virtualMachine struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	InstanceID string            `json:"instanceId"`
+	Zones      []string          `json:"zones"`
+	Tags       map[string]string `json:"tags"`
+	Properties struct {
+		InstanceView *struct {
+			Statuses []struct {
+				Code string `json:"code"`
+			} `json:"statuses"`
+		} `json:"instanceView"`
+	} `json:"properties"`
+}
+
+// virtualMachineList is the response of the virtual machine and scale set instance list api
+type virtualMachineList struct {
+	Value    []virtualMachine `json:"value"`
+	NextLink string           `json:"nextLink"`
+}
+
+// virtualMachineScaleSet contains the fields of the scale set used by the provider
+type virtualMachineScaleSet struct {
+	ID   string            `json:"id"`
+	Tags map[string]string `json:"tags"`
+}
+
+// virtualMachineScaleSetList is the response of the scale set list api
+type virtualMachineScaleSetList struct {
+	Value    []virtualMachineScaleSet `json:"value"`
+	NextLink string                   `json:"nextLink"`
+}
+
+// StopInstance will power off the azure instance
+func (p *Provider) StopInstance(instanceID string) error {
+	return p.instanceAction(instanceID, "powerOff")
+}
+
+// StartInstance will start the azure instance
+func (p *Provider) StartInstance(instanceID string) error {
+	return p.instanceAction(instanceID, "start")
+}
+
+// RebootInstance will restart the azure instance
+func (p *Provider) RebootInstance(instanceID string) error {
+	return p.instanceAction(instanceID, "restart")
+}
+
+// instanceAction invokes the power off, start or restart action on the instance, the action completes asynchronously
+func (p *Provider) instanceAction(instanceID, action string) error {
+
+	instancePath, err := p.instancePath(instanceID)
+	if err != nil {
+		return err
+	}
+	query := url.Values{"api-version": {computeAPIVersion}}
+	if err := p.do(http.MethodPost, instancePath+"/"+action+"?"+query.Encode(), nil); err != nil {
+		return err
+	}
+
+	log.InfoWithValues("Invoking the "+action+" action on azure instance:", logrus.Fields{
+		"InstanceId": instanceID,
+	})
+	return nil
+}
+
+// DescribeInstances will give the details of the given azure instances
+func (p *Provider) DescribeInstances(instanceIDs ...string) ([]cloud.Instance, error) {
+
+	query := url.Values{"api-version": {computeAPIVersion}, "$expand": {"instanceView"}}
+	var instances []cloud.Instance
+	for _, id := range instanceIDs {
+		instancePath, err := p.instancePath(id)
+		if err != nil {
+			return nil, err
+		}
+		var vm virtualMachine
+		if err := p.do(http.MethodGet, instancePath+"?"+query.Encode(), &vm); err != nil {
+			return nil, err
+		}
+		instance := toInstance(vm)
+		instance.ID = id
+		instances = append(instances, instance)
+	}
+	return instances, nil
+}
+
+// ListByTag will filter out the virtual machines and scale set instances of the subscription using the tag, the tag is in key:value format
+// the scale set instances inherit the tags of their scale set, like the aks node pool instances
+func (p *Provider) ListByTag(tag string) ([]string, error) {
+
+	keyValue := strings.Split(tag, ":")
+	if len(keyValue) != 2 {
+		return nil, errors.Errorf("fail to parse the tag %v, provide it in key:value format", tag)
+	}
+
+	vms, err := p.listVirtualMachines(false)
+	if err != nil {
+		return nil, err
+	}
+	var instanceList []string
+	for _, vm := range vms {
+		if value, ok := vm.Tags[keyValue[0]]; ok && value == keyValue[1] {
+			instanceList = append(instanceList, toInstance(vm).ID)
+		}
+	}
+	return instanceList, nil
+}
+
+// ListByZone will filter out the running virtual machines and scale set instances of the given availability zone
+func (p *Provider) ListByZone(zone string) ([]string, error) {
+
+	if zone == "" {
+		return nil, errors.Errorf("fail to get the zone please provide a valid availability zone")
+	}
+
+	vms, err := p.listVirtualMachines(true)
+	if err != nil {
+		return nil, err
+	}
+	var instanceList []string
+	for _, vm := range vms {
+		instance := toInstance(vm)
+		if instance.AvailabilityZone == zone && instance.State == cloud.InstanceRunning {
+			instanceList = append(instanceList, instance.ID)
+		}
+	}
+	return instanceList, nil
+}
+
+// listVirtualMachines lists all the pages of the virtual machines and the scale set instances of the subscription
+// the instance view is included in the response if withStatus is enabled
+func (p *Provider) listVirtualMachines(withStatus bool) ([]virtualMachine, error) {
+
+	query := url.Values{"api-version": {computeAPIVersion}}
+	if withStatus {
+		query.Set("statusOnly", "true")
+	}
+	vms, err := p.listVirtualMachinePages(p.endpoint + "subscriptions/" + p.subscription + "/providers/Microsoft.Compute/virtualMachines?" + query.Encode())
+	if err != nil {
+		return nil, errors.Errorf("fail to list the virtual machines, err: %v", err)
+	}
+
+	scaleSets, err := p.listScaleSets()
+	if err != nil {
+		return nil, err
+	}
+	// the scale set instance list api doesn't support the statusOnly filter, the instance view is expanded instead
+	query = url.Values{"api-version": {computeAPIVersion}}
+	if withStatus {
+		query.Set("$expand", "instanceView")
+	}
+	for _, scaleSet := range scaleSets {
+		instances, err := p.listVirtualMachinePages(p.endpoint + strings.TrimPrefix(scaleSet.ID, "/") + "/virtualMachines?" + query.Encode())
+		if err != nil {
+			return nil, errors.Errorf("fail to list the instances of %v scale set, err: %v", scaleSet.ID, err)
+		}
+		for _, instance := range instances {
+			instance.Tags = inheritTags(instance.Tags, scaleSet.Tags)
+			vms = append(vms, instance)
+		}
+	}
+	return vms, nil
+}
+
+// listVirtualMachinePages lists all the pages of the virtual machine list api starting from the given url
+func (p *Provider) listVirtualMachinePages(target string) ([]virtualMachine, error) {
+
+	var vms []virtualMachine
+	for target != "" {
+		var result virtualMachineList
+		if err := p.do(http.MethodGet, target, &result); err != nil {
+			return nil, err
+		}
+		vms = append(vms, result.Value...)
+		target = result.NextLink
+	}
+	return vms, nil
+}
+
+// listScaleSets lists all the pages of the virtual machine scale sets of the subscription
+func (p *Provider) listScaleSets() ([]virtualMachineScaleSet, error) {
+
+	query := url.Values{"api-version": {computeAPIVersion}}
+	target := p.endpoint + "subscriptions/" + p.subscription + "/providers/Microsoft.Compute/virtualMachineScaleSets?" + query.Encode()
+
+	var scaleSets []virtualMachineScaleSet
+	for target != "" {
+		var result virtualMachineScaleSetList
+		if err := p.do(http.MethodGet, target, &result); err != nil {
+			return nil, errors.Errorf("fail to list the virtual machine scale sets, err: %v", err)
+		}
+		scaleSets = append(scaleSets, result.Value...)
+		target = result.NextLink
+	}
+	return scaleSets, nil
+}
+
+// inheritTags returns the tags of the scale set instance along with the tags of its scale set
+// the tags of the instance take precedence over the tags of the scale set
+func inheritTags(instanceTags, scaleSetTags map[string]string) map[string]string {
+
+	tags := map[string]string{}
+	for key, value := range scaleSetTags {
+		tags[key] = value
+	}
+	for key, value := range instanceTags {
+		tags[key] = value
+	}
+	return tags
+}
+
+// instancePath returns the resource url of the virtual machine or the scale set instance
+func (p *Provider) instancePath(instanceID string) (string, error) {
+
+	parts := strings.Split(instanceID, "/")
+	for _, part := range parts {
+		if part == "" {
+			return "", errors.Errorf("fail to parse the instance id %v", instanceID)
+		}
+	}
+	base := p.endpoint + "subscriptions/" + p.subscription + "/resourceGroups/" + parts[0] + "/providers/Microsoft.Compute/"
+	switch len(parts) {
+	case 2:
+		return base + "virtualMachines/" + parts[1], nil
+	case 3:
+		return base + "virtualMachineScaleSets/" + parts[1] + "/virtualMachines/" + parts[2], nil
+	default:
+		return "", errors.Errorf("fail to parse the instance id %v, provide it in resourceGroup/vmName or resourceGroup/scaleSetName/instanceID format", instanceID)
+	}
+}
+
+// toInstance converts the azure virtual machine into the provider instance
+// the stopped and deallocated power states are mapped to the stopped state
+func toInstance(vm virtualMachine) cloud.Instance {
+
+	instance := cloud.Instance{
+		ID:   resourceGroupOf(vm.ID) + "/" + vm.Name,
+		Tags: vm.Tags,
+	}
+	// the scale set instances are identified by the scale set name and their instance id
+	if instanceID, err := instanceIDOf(vm.ID); err == nil {
+		instance.ID = instanceID
+	}
+	if len(vm.Zones) != 0 {
+		instance.AvailabilityZone = vm.Zones[0]
+	}
+	if vm.Properties.InstanceView == nil {
+		return instance
+	}
+	for _, status := range vm.Properties.InstanceView.Statuses {
+		if !strings.HasPrefix(status.Code, "PowerState/") {
+			continue
+		}
+		switch state := strings.TrimPrefix(status.Code, "PowerState/"); state {
+		case "stopped", "deallocated":
+			instance.State = cloud.InstanceStopped
+		default:
+			instance.State = state
+		}
+	}
+	return instance
+}
+
+// resourceGroupOf extracts the resource group from the resource id
+func resourceGroupOf(resourceID string) string {
+	parts := strings.Split(resourceID, "/")
+	for i := 0; i < len(parts)-1; i++ {
+		if strings.EqualFold(parts[i], "resourceGroups") {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
+
+// GetInstanceIDFromProviderID extracts the instance id from the provider id of the node
+// the provider id is the resource id of the virtual machine or the scale set instance, prefixed with azure://
+func GetInstanceIDFromProviderID(providerID string) (string, error) {
+
+	if !strings.HasPrefix(providerID, "azure://") {
+		return "", errors.Errorf("the provider id %v is not an azure provider id", providerID)
+	}
+	instanceID, err := instanceIDOf(providerID)
+	if err != nil {
+		return "", errors.Errorf("unable to parse the instance id from the provider id %v", providerID)
+	}
+	return instanceID, nil
+}
+
+// instanceIDOf converts the resource id of the virtual machine or the scale set instance into the instance id
+// the instance id is in resourceGroup/vmName or resourceGroup/scaleSetName/instanceID format
+func instanceIDOf(resourceID string) (string, error) {
+
+	resourceGroup := resourceGroupOf(resourceID)
+	parts := strings.Split(resourceID, "/")
+	for i := 0; i < len(parts)-1 && resourceGroup != ""; i++ {
+		switch {
+		case strings.EqualFold(parts[i], "virtualMachineScaleSets") && i+3 < len(parts):
+			return resourceGroup + "/" + parts[i+1] + "/" + parts[i+3], nil
+		case strings.EqualFold(parts[i], "virtualMachines"):
+			return resourceGroup + "/" + parts[i+1], nil
+		}
+	}
+	return "", errors.Errorf("unable to parse the instance id from the resource id %v", resourceID)
+}
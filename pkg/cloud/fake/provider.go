@@ -0,0 +1,370 @@
+package fake
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	"github.com/pkg/errors"
+)
+
+// Provider is an in-memory cloud.NetworkProvider with scripted state transitions
+// every operation queues the transitions of the resource, and each describe call
+// moves the resource to the next queued state, mimicking the eventual consistency of the cloud api
+type Provider struct {
+	mu        sync.Mutex
+	instances map[string]*cloud.Instance
+	volumes   map[string]*cloud.Volume
+	pending   map[string][]string
+	// associations are the network acl associations, keyed by the subnet id
+	associations   map[string]*cloud.NetworkACLAssociation
+	associationSeq int
+
+	// StopTransitions are the states an instance passes through after it is stopped
+	StopTransitions []string
+	// StartTransitions are the states an instance passes through after it is started
+	StartTransitions []string
+	// DetachTransitions are the states a volume attachment passes through after it is detached
+	DetachTransitions []string
+	// AttachTransitions are the states a volume attachment passes through after it is attached
+	AttachTransitions []string
+	// Errors contains the errors to be returned by the operations, keyed by the operation name
+	Errors map[string]error
+	// Calls records the operations invoked on the provider in order
+	Calls []string
+}
+
+var _ cloud.NetworkProvider = &Provider{}
+var _ cloud.Rebooter = &Provider{}
+var _ cloud.Poller = &Provider{}
+
+// NewProvider creates an empty fake provider with the default transitions
+func NewProvider() *Provider {
+	return &Provider{
+		instances:         map[string]*cloud.Instance{},
+		volumes:           map[string]*cloud.Volume{},
+		pending:           map[string][]string{},
+		associations:      map[string]*cloud.NetworkACLAssociation{},
+		StopTransitions:   []string{"stopping", cloud.InstanceStopped},
+		StartTransitions:  []string{"pending", cloud.InstanceRunning},
+		DetachTransitions: []string{"detaching", cloud.VolumeDetached},
+		AttachTransitions: []string{"attaching", cloud.VolumeAttached},
+		Errors:            map[string]error{},
+	}
+}
+
+// DelayUnit returns a millisecond, so that the status checks against the fake provider are delayed in milliseconds
+func (f *Provider) DelayUnit() time.Duration {
+	return time.Millisecond
+}
+
+// AddInstance adds the instance to the fake provider
+func (f *Provider) AddInstance(instance cloud.Instance) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.instances[instance.ID] = &instance
+}
+
+// AddVolume adds the volume to the fake provider
+func (f *Provider) AddVolume(volume cloud.Volume) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.volumes[volume.ID] = &volume
+}
+
+// AddNetworkACLAssociation adds the network acl association of the subnet to the fake provider
+func (f *Provider) AddNetworkACLAssociation(association cloud.NetworkACLAssociation) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.associations[association.SubnetID] = &association
+}
+
+// CheckCredentials returns the scripted error of the credential check, if any
+func (f *Provider) CheckCredentials() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.record("CheckCredentials")
+}
+
+// StopInstance queues the stop transitions of the instance
+func (f *Provider) StopInstance(instanceID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("StopInstance", instanceID); err != nil {
+		return err
+	}
+	if _, ok := f.instances[instanceID]; !ok {
+		return errors.Errorf("instance %v not found", instanceID)
+	}
+	f.pending[instanceID] = append([]string{}, f.StopTransitions...)
+	return nil
+}
+
+// StartInstance queues the start transitions of the instance
+func (f *Provider) StartInstance(instanceID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("StartInstance", instanceID); err != nil {
+		return err
+	}
+	instance, ok := f.instances[instanceID]
+	if !ok {
+		return errors.Errorf("instance %v not found", instanceID)
+	}
+	if instance.State == cloud.InstanceTerminated {
+		return errors.Errorf("instance %v is terminated", instanceID)
+	}
+	f.pending[instanceID] = append([]string{}, f.StartTransitions...)
+	return nil
+}
+
+// RebootInstance records the reboot of the instance, the instance stays in its current state
+func (f *Provider) RebootInstance(instanceID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("RebootInstance", instanceID); err != nil {
+		return err
+	}
+	instance, ok := f.instances[instanceID]
+	if !ok {
+		return errors.Errorf("instance %v not found", instanceID)
+	}
+	if instance.State != cloud.InstanceRunning {
+		return errors.Errorf("instance %v is not running", instanceID)
+	}
+	return nil
+}
+
+// DescribeInstances returns the given instances after moving them to their next state
+func (f *Provider) DescribeInstances(instanceIDs ...string) ([]cloud.Instance, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("DescribeInstances", instanceIDs...); err != nil {
+		return nil, err
+	}
+	var instances []cloud.Instance
+	for _, id := range instanceIDs {
+		instance, ok := f.instances[id]
+		if !ok {
+			return nil, errors.Errorf("instance %v not found", id)
+		}
+		if state, ok := f.next(id); ok {
+			instance.State = state
+		}
+		instances = append(instances, *instance)
+	}
+	return instances, nil
+}
+
+// ListByTag returns the ids of the instances having the given tag
+func (f *Provider) ListByTag(tag string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("ListByTag", tag); err != nil {
+		return nil, err
+	}
+	key, value, err := parseTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	var instanceList []string
+	for id, instance := range f.instances {
+		if v, ok := instance.Tags[key]; ok && v == value {
+			instanceList = append(instanceList, id)
+		}
+	}
+	return instanceList, nil
+}
+
+// ListByZone returns the ids of the running instances in the given availability zone
+func (f *Provider) ListByZone(zone string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("ListByZone", zone); err != nil {
+		return nil, err
+	}
+	var instanceList []string
+	for id, instance := range f.instances {
+		if instance.AvailabilityZone == zone && instance.State == cloud.InstanceRunning {
+			instanceList = append(instanceList, id)
+		}
+	}
+	return instanceList, nil
+}
+
+// ListVolumesByTag returns the ids of the volumes having the given tag
+func (f *Provider) ListVolumesByTag(tag string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("ListVolumesByTag", tag); err != nil {
+		return nil, err
+	}
+	key, value, err := parseTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	var volumeList []string
+	for id, volume := range f.volumes {
+		if v, ok := volume.Tags[key]; ok && v == value {
+			volumeList = append(volumeList, id)
+		}
+	}
+	return volumeList, nil
+}
+
+// DescribeVolumes returns the given volumes after moving their attachments to the next state
+func (f *Provider) DescribeVolumes(volumeIDs ...string) ([]cloud.Volume, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("DescribeVolumes", volumeIDs...); err != nil {
+		return nil, err
+	}
+	var volumes []cloud.Volume
+	for _, id := range volumeIDs {
+		volume, ok := f.volumes[id]
+		if !ok {
+			return nil, errors.Errorf("volume %v not found", id)
+		}
+		if state, ok := f.next(id); ok && len(volume.Attachments) > 0 {
+			if state == cloud.VolumeDetached {
+				volume.Attachments = nil
+				volume.State = "available"
+			} else {
+				volume.Attachments[0].State = state
+				volume.State = "in-use"
+			}
+		}
+		volumes = append(volumes, *volume)
+	}
+	return volumes, nil
+}
+
+// DetachVolume queues the detach transitions of the volume
+func (f *Provider) DetachVolume(volumeID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("DetachVolume", volumeID); err != nil {
+		return err
+	}
+	volume, ok := f.volumes[volumeID]
+	if !ok {
+		return errors.Errorf("volume %v not found", volumeID)
+	}
+	if len(volume.Attachments) == 0 {
+		return errors.Errorf("volume %v is not attached", volumeID)
+	}
+	f.pending[volumeID] = append([]string{}, f.DetachTransitions...)
+	return nil
+}
+
+// AttachVolume attaches the volume and queues the attach transitions
+func (f *Provider) AttachVolume(volumeID, instanceID, device string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("AttachVolume", volumeID, instanceID, device); err != nil {
+		return err
+	}
+	volume, ok := f.volumes[volumeID]
+	if !ok {
+		return errors.Errorf("volume %v not found", volumeID)
+	}
+	if len(volume.Attachments) != 0 {
+		return errors.Errorf("volume %v is already attached", volumeID)
+	}
+	if _, ok := f.instances[instanceID]; !ok {
+		return errors.Errorf("instance %v not found", instanceID)
+	}
+	volume.Attachments = []cloud.VolumeAttachment{{InstanceID: instanceID, Device: device, State: "attaching"}}
+	f.pending[volumeID] = append([]string{}, f.AttachTransitions...)
+	return nil
+}
+
+// SetSecurityGroups replaces the security groups of the network interface
+func (f *Provider) SetSecurityGroups(networkInterfaceID string, securityGroupIDs []string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("SetSecurityGroups", append([]string{networkInterfaceID}, securityGroupIDs...)...); err != nil {
+		return err
+	}
+	for _, instance := range f.instances {
+		for i := range instance.NetworkInterfaces {
+			if instance.NetworkInterfaces[i].ID == networkInterfaceID {
+				instance.NetworkInterfaces[i].SecurityGroups = append([]string{}, securityGroupIDs...)
+				return nil
+			}
+		}
+	}
+	return errors.Errorf("network interface %v not found", networkInterfaceID)
+}
+
+// GetNetworkACLAssociation returns the network acl association of the subnet
+func (f *Provider) GetNetworkACLAssociation(subnetID string) (cloud.NetworkACLAssociation, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("GetNetworkACLAssociation", subnetID); err != nil {
+		return cloud.NetworkACLAssociation{}, err
+	}
+	association, ok := f.associations[subnetID]
+	if !ok {
+		return cloud.NetworkACLAssociation{}, errors.Errorf("network acl association of %v subnet not found", subnetID)
+	}
+	return *association, nil
+}
+
+// ReplaceNetworkACLAssociation replaces the network acl of the association and returns the new association id
+func (f *Provider) ReplaceNetworkACLAssociation(associationID, networkACLID string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.record("ReplaceNetworkACLAssociation", associationID, networkACLID); err != nil {
+		return "", err
+	}
+	for _, association := range f.associations {
+		if association.ID == associationID {
+			f.associationSeq++
+			association.ID = "aclassoc-" + strconv.Itoa(f.associationSeq)
+			association.NetworkACLID = networkACLID
+			return association.ID, nil
+		}
+	}
+	return "", errors.Errorf("network acl association %v not found", associationID)
+}
+
+// record appends the call and returns the scripted error of the operation, if any
+func (f *Provider) record(operation string, args ...string) error {
+	f.Calls = append(f.Calls, operation+"("+strings.Join(args, ",")+")")
+	return f.Errors[operation]
+}
+
+// next pops the next queued state of the resource
+func (f *Provider) next(id string) (string, bool) {
+	states := f.pending[id]
+	if len(states) == 0 {
+		return "", false
+	}
+	f.pending[id] = states[1:]
+	return states[0], true
+}
+
+// parseTag splits the tag provided in key:value format
+func parseTag(tag string) (string, string, error) {
+	keyValue := strings.Split(tag, ":")
+	if len(keyValue) != 2 {
+		return "", "", errors.Errorf("fail to parse the tag %v, provide it in key:value format", tag)
+	}
+	return keyValue[0], keyValue[1], nil
+}
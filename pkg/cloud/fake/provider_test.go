@@ -0,0 +1,276 @@
+package fake
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	"github.com/pkg/errors"
+)
+
+// newTestProvider returns the provider with a running instance & a volume attached to it
+func newTestProvider() *Provider {
+
+	provider := NewProvider()
+	provider.AddInstance(cloud.Instance{ID: "i-1", State: cloud.InstanceRunning, AvailabilityZone: "us-east-1a", Tags: map[string]string{"chaos": "true"}})
+	provider.AddVolume(cloud.Volume{
+		ID:          "vol-1",
+		State:       "in-use",
+		Attachments: []cloud.VolumeAttachment{{InstanceID: "i-1", Device: "/dev/sdb", State: cloud.VolumeAttached}},
+		Tags:        map[string]string{"chaos": "true"},
+	})
+	return provider
+}
+
+// instanceStates returns the states of the instance, as observed by the given number of describe calls
+func instanceStates(t *testing.T, provider *Provider, id string, describes int) []string {
+
+	var states []string
+	for i := 0; i < describes; i++ {
+		instances, err := provider.DescribeInstances(id)
+		if err != nil {
+			t.Fatalf("unable to describe the %v instance, err: %v", id, err)
+		}
+		states = append(states, instances[0].State)
+	}
+	return states
+}
+
+// volumeStates returns the attachment states of the volume, as observed by the given number of describe calls
+func volumeStates(t *testing.T, provider *Provider, id string, describes int) []string {
+
+	var states []string
+	for i := 0; i < describes; i++ {
+		volumes, err := provider.DescribeVolumes(id)
+		if err != nil {
+			t.Fatalf("unable to describe the %v volume, err: %v", id, err)
+		}
+		state := cloud.VolumeDetached
+		if len(volumes[0].Attachments) != 0 {
+			state = volumes[0].Attachments[0].State
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+func TestInstanceTransitions(t *testing.T) {
+
+	tests := []struct {
+		transitions []string
+		want        []string
+		testName    string
+	}{
+		{
+			want:     []string{"stopping", cloud.InstanceStopped, cloud.InstanceStopped},
+			testName: "default transitions",
+		},
+		{
+			transitions: []string{"stopping", "stopping", cloud.InstanceTerminated},
+			want:        []string{"stopping", "stopping", cloud.InstanceTerminated, cloud.InstanceTerminated},
+			testName:    "scripted transitions",
+		},
+		{
+			transitions: []string{},
+			want:        []string{cloud.InstanceRunning},
+			testName:    "stuck instance",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			provider := newTestProvider()
+			if tt.transitions != nil {
+				provider.StopTransitions = tt.transitions
+			}
+			if err := provider.StopInstance("i-1"); err != nil {
+				t.Fatalf("unable to stop the instance, err: %v", err)
+			}
+			if got := instanceStates(t, provider, "i-1", len(tt.want)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v states, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartInstance(t *testing.T) {
+
+	provider := newTestProvider()
+	provider.StopTransitions = []string{cloud.InstanceStopped}
+	if err := provider.StopInstance("i-1"); err != nil {
+		t.Fatalf("unable to stop the instance, err: %v", err)
+	}
+	if err := provider.StartInstance("i-1"); err != nil {
+		t.Fatalf("unable to start the instance, err: %v", err)
+	}
+	// the start transitions replace the pending stop transitions
+	if got, want := instanceStates(t, provider, "i-1", 3), []string{"pending", cloud.InstanceRunning, cloud.InstanceRunning}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v states, want %v", got, want)
+	}
+
+	provider.AddInstance(cloud.Instance{ID: "i-2", State: cloud.InstanceTerminated})
+	if err := provider.StartInstance("i-2"); err == nil {
+		t.Error("expected the terminated instance not to start")
+	}
+	if err := provider.StartInstance("i-3"); err == nil {
+		t.Error("expected the unknown instance not to start")
+	}
+	if _, err := provider.DescribeInstances("i-3"); err == nil {
+		t.Error("expected the describe of the unknown instance to fail")
+	}
+}
+
+func TestRebootInstance(t *testing.T) {
+
+	provider := newTestProvider()
+	if err := provider.RebootInstance("i-1"); err != nil {
+		t.Fatalf("unable to reboot the instance, err: %v", err)
+	}
+	if got := instanceStates(t, provider, "i-1", 1); got[0] != cloud.InstanceRunning {
+		t.Errorf("got %v state, want the rebooted instance to stay running", got[0])
+	}
+
+	provider.AddInstance(cloud.Instance{ID: "i-2", State: cloud.InstanceStopped})
+	if err := provider.RebootInstance("i-2"); err == nil {
+		t.Error("expected the stopped instance not to reboot")
+	}
+}
+
+func TestVolumeTransitions(t *testing.T) {
+
+	provider := newTestProvider()
+	if err := provider.DetachVolume("vol-1"); err != nil {
+		t.Fatalf("unable to detach the volume, err: %v", err)
+	}
+	if got, want := volumeStates(t, provider, "vol-1", 3), []string{"detaching", cloud.VolumeDetached, cloud.VolumeDetached}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v detach states, want %v", got, want)
+	}
+	if status, err := cloud.GetVolumeStatus(provider, "vol-1", "i-1"); err != nil || status != cloud.VolumeDetached {
+		t.Errorf("got %v status, err: %v, want %v", status, err, cloud.VolumeDetached)
+	}
+	if err := provider.DetachVolume("vol-1"); err == nil {
+		t.Error("expected the detached volume not to detach again")
+	}
+
+	if err := provider.AttachVolume("vol-1", "i-1", "/dev/sdb"); err != nil {
+		t.Fatalf("unable to attach the volume, err: %v", err)
+	}
+	if got, want := volumeStates(t, provider, "vol-1", 3), []string{"attaching", cloud.VolumeAttached, cloud.VolumeAttached}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v attach states, want %v", got, want)
+	}
+	if err := provider.AttachVolume("vol-1", "i-1", "/dev/sdb"); err == nil {
+		t.Error("expected the attached volume not to attach again")
+	}
+
+	provider.AddVolume(cloud.Volume{ID: "vol-2", State: "available"})
+	if err := provider.AttachVolume("vol-2", "i-2", "/dev/sdc"); err == nil {
+		t.Error("expected the volume not to attach to an unknown instance")
+	}
+}
+
+func TestList(t *testing.T) {
+
+	provider := newTestProvider()
+	provider.AddInstance(cloud.Instance{ID: "i-2", State: cloud.InstanceRunning, AvailabilityZone: "us-east-1a", Tags: map[string]string{"chaos": "false"}})
+	provider.AddInstance(cloud.Instance{ID: "i-3", State: cloud.InstanceStopped, AvailabilityZone: "us-east-1a", Tags: map[string]string{"chaos": "true"}})
+
+	tests := []struct {
+		list     func() ([]string, error)
+		want     []string
+		wantErr  bool
+		testName string
+	}{
+		{
+			list:     func() ([]string, error) { return provider.ListByTag("chaos:true") },
+			want:     []string{"i-1", "i-3"},
+			testName: "instances by tag",
+		},
+		{
+			list:     func() ([]string, error) { return provider.ListByTag("chaos") },
+			wantErr:  true,
+			testName: "invalid tag",
+		},
+		{
+			list:     func() ([]string, error) { return provider.ListByZone("us-east-1a") },
+			want:     []string{"i-1", "i-2"},
+			testName: "running instances by zone",
+		},
+		{
+			list:     func() ([]string, error) { return provider.ListVolumesByTag("chaos:true") },
+			want:     []string{"vol-1"},
+			testName: "volumes by tag",
+		},
+		{
+			list:     func() ([]string, error) { return provider.ListVolumesByTag("chaos:false") },
+			testName: "no volume with the tag",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			got, err := tt.list()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got %v error, want error: %v", err, tt.wantErr)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+
+	provider := newTestProvider()
+	provider.Errors["StopInstance"] = errors.Errorf("UnauthorizedOperation")
+	if err := provider.StopInstance("i-1"); err == nil || err.Error() != "UnauthorizedOperation" {
+		t.Errorf("got %v error, want the scripted error", err)
+	}
+	if got := instanceStates(t, provider, "i-1", 1); got[0] != cloud.InstanceRunning {
+		t.Errorf("got %v state, want the instance to stay running after the failed stop", got[0])
+	}
+	if err := provider.CheckCredentials(); err != nil {
+		t.Errorf("unable to check the credentials, err: %v", err)
+	}
+
+	want := []string{"StopInstance(i-1)", "DescribeInstances(i-1)", "CheckCredentials()"}
+	if !reflect.DeepEqual(provider.Calls, want) {
+		t.Errorf("got %v calls, want %v", provider.Calls, want)
+	}
+}
+
+func TestNetwork(t *testing.T) {
+
+	provider := newTestProvider()
+	provider.AddInstance(cloud.Instance{ID: "i-2", State: cloud.InstanceRunning, NetworkInterfaces: []cloud.NetworkInterface{{ID: "eni-1", SubnetID: "subnet-1", SecurityGroups: []string{"sg-1"}}}})
+	provider.AddNetworkACLAssociation(cloud.NetworkACLAssociation{ID: "aclassoc-0", NetworkACLID: "acl-1", SubnetID: "subnet-1"})
+
+	if err := provider.SetSecurityGroups("eni-1", []string{"sg-2", "sg-3"}); err != nil {
+		t.Fatalf("unable to set the security groups, err: %v", err)
+	}
+	instances, err := provider.DescribeInstances("i-2")
+	if err != nil {
+		t.Fatalf("unable to describe the instance, err: %v", err)
+	}
+	if got, want := instances[0].NetworkInterfaces[0].SecurityGroups, []string{"sg-2", "sg-3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v security groups, want %v", got, want)
+	}
+	if err := provider.SetSecurityGroups("eni-2", nil); err == nil {
+		t.Error("expected the security groups of an unknown network interface not to be set")
+	}
+
+	associationID, err := provider.ReplaceNetworkACLAssociation("aclassoc-0", "acl-2")
+	if err != nil {
+		t.Fatalf("unable to replace the network acl, err: %v", err)
+	}
+	association, err := provider.GetNetworkACLAssociation("subnet-1")
+	if err != nil {
+		t.Fatalf("unable to get the network acl association, err: %v", err)
+	}
+	if association.ID != associationID || association.ID == "aclassoc-0" || association.NetworkACLID != "acl-2" {
+		t.Errorf("got %v association, want a new association of acl-2 with %v id", association, associationID)
+	}
+	if _, err := provider.ReplaceNetworkACLAssociation("aclassoc-0", "acl-1"); err == nil {
+		t.Error("expected the replaced association not to be found")
+	}
+}
@@ -0,0 +1,182 @@
+package gcp
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// computeScope is the oauth scope of the compute engine api
+	computeScope = "https://www.googleapis.com/auth/compute"
+	// defaultTokenURL is the token endpoint used if the service account key doesn't contain one
+	defaultTokenURL = "https://oauth2.googleapis.com/token"
+	// metadataTokenURL is the token endpoint of the metadata server, used with the workload identity
+	metadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+)
+
+// serviceAccountKey contains the fields of the service account json key used for the authentication
+type serviceAccountKey struct {
+	Type         string `json:"type"`
+	ProjectID    string `json:"project_id"`
+	PrivateKeyID string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// tokenResponse is the response of the token endpoints
+type tokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// serviceAccountTokenSource fetches the access token by exchanging a jwt signed with the service account key
+type serviceAccountTokenSource struct {
+	key        serviceAccountKey
+	privateKey *rsa.PrivateKey
+	client     *http.Client
+}
+
+// metadataTokenSource fetches the access token of the attached service account from the metadata server
+type metadataTokenSource struct {
+	client *http.Client
+}
+
+// readServiceAccountKey reads and validates the service account json key
+func readServiceAccountKey(path string) (serviceAccountKey, *rsa.PrivateKey, error) {
+
+	var key serviceAccountKey
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return key, nil, errors.Errorf("fail to read the service account key %v, err: %v", path, err)
+	}
+	if err := json.Unmarshal(data, &key); err != nil {
+		return key, nil, errors.Errorf("fail to parse the service account key %v, err: %v", path, err)
+	}
+	if key.Type != "service_account" || key.ClientEmail == "" || key.PrivateKey == "" {
+		return key, nil, errors.Errorf("%v is not a valid service account key", path)
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = defaultTokenURL
+	}
+
+	block, _ := pem.Decode([]byte(key.PrivateKey))
+	if block == nil {
+		return key, nil, errors.Errorf("fail to decode the private key of the service account")
+	}
+	parsedKey, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if parsedKey, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return key, nil, errors.Errorf("fail to parse the private key of the service account, err: %v", err)
+		}
+	}
+	privateKey, ok := parsedKey.(*rsa.PrivateKey)
+	if !ok {
+		return key, nil, errors.Errorf("the private key of the service account is not an rsa key")
+	}
+	return key, privateKey, nil
+}
+
+// Token exchanges the signed jwt for an access token
+func (s *serviceAccountTokenSource) Token() (*oauth2.Token, error) {
+
+	assertion, err := s.signedJWT(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequest(http.MethodPost, s.key.TokenURI, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return fetchToken(s.client, req)
+}
+
+// signedJWT builds the jwt assertion of the service account, signed with RS256
+func (s *serviceAccountTokenSource) signedJWT(now time.Time) (string, error) {
+
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+		"kid": s.key.PrivateKeyID,
+	})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   s.key.ClientEmail,
+		"scope": computeScope,
+		"aud":   s.key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", errors.Errorf("fail to sign the jwt, err: %v", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Token fetches the access token from the metadata server
+func (m *metadataTokenSource) Token() (*oauth2.Token, error) {
+
+	req, err := http.NewRequest(http.MethodGet, metadataTokenURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	return fetchToken(m.client, req)
+}
+
+// fetchToken sends the token request and parses the token response
+func fetchToken(client *http.Client, req *http.Request) (*oauth2.Token, error) {
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Errorf("fail to fetch the access token, err: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Errorf("fail to read the token response, err: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("fail to fetch the access token, status: %v, response: %v", resp.Status, string(body))
+	}
+
+	var token tokenResponse
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, errors.Errorf("fail to parse the token response, err: %v", err)
+	}
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}, nil
+}
@@ -0,0 +1,159 @@
+package gcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+// defaultEndpoint is the endpoint of the compute engine v1 api
+const defaultEndpoint = "https://compute.googleapis.com/compute/v1/"
+
+// Config contains the configuration of the gcp provider
+type Config struct {
+	// ProjectID is the gcp project of the target instances, it defaults to the project of the service account key
+	ProjectID string
+	// CredentialsFile is the path of the service account json key,
+	// the token of the attached service account is fetched from the metadata server (workload identity) if it is empty
+	CredentialsFile string
+	// EndpointURL overrides the compute engine endpoint, ex: a fake compute server in tests
+	EndpointURL string
+}
+
+// Provider implements the cloud.InstanceProvider for gcp compute engine
+// the instance id is in the zone/name format, as the instance names are unique only within a zone
+type Provider struct {
+	client   *http.Client
+	endpoint string
+	project  string
+}
+
+var _ cloud.InstanceProvider = &Provider{}
+var _ cloud.Rebooter = &Provider{}
+
+// apiError is the error response of the compute engine api
+type apiError struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// NewProvider creates the gcp provider for the given config
+func NewProvider(config Config) (*Provider, error) {
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+
+	var tokenSource oauth2.TokenSource
+	if config.CredentialsFile != "" {
+		key, privateKey, err := readServiceAccountKey(config.CredentialsFile)
+		if err != nil {
+			return nil, err
+		}
+		if config.ProjectID == "" {
+			config.ProjectID = key.ProjectID
+		}
+		tokenSource = &serviceAccountTokenSource{key: key, privateKey: privateKey, client: httpClient}
+	} else {
+		log.Info("[Info]: No service account key provided, using the metadata server credentials")
+		tokenSource = &metadataTokenSource{client: httpClient}
+	}
+	if config.ProjectID == "" {
+		return nil, errors.Errorf("fail to get the gcp project please provide a valid project id")
+	}
+
+	endpoint := defaultEndpoint
+	if config.EndpointURL != "" {
+		endpoint = config.EndpointURL
+	}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+	return NewProviderWithClient(oauth2.NewClient(ctx, oauth2.ReuseTokenSource(nil, tokenSource)), endpoint, config.ProjectID), nil
+}
+
+// NewVerifiedProvider creates the gcp provider for the given config and verifies its credentials
+// it is used by the experiments to surface the credential errors before any gcp call
+func NewVerifiedProvider(config Config) (*Provider, error) {
+
+	provider, err := NewProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	if err := provider.CheckCredentials(); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// NewProviderWithClient creates the gcp provider with the injected http client and endpoint
+func NewProviderWithClient(client *http.Client, endpoint, project string) *Provider {
+	if !strings.HasSuffix(endpoint, "/") {
+		endpoint += "/"
+	}
+	return &Provider{client: client, endpoint: endpoint, project: project}
+}
+
+// CheckCredentials verifies that the credentials can access the compute engine api of the project
+func (p *Provider) CheckCredentials() error {
+
+	if err := p.do(http.MethodGet, "projects/"+p.project, nil, nil); err != nil {
+		return errors.Errorf("unable to verify the gcp credentials for %v project, provide the service account key or workload identity, err: %v", p.project, err)
+	}
+	log.Infof("[Info]: The gcp credentials have access to %v project", p.project)
+	return nil
+}
+
+// do sends the request to the compute engine api and decodes the response into out, if provided
+func (p *Provider) do(method, path string, query url.Values, out interface{}) error {
+
+	target := p.endpoint + path
+	if len(query) != 0 {
+		target += "?" + query.Encode()
+	}
+	var body *bytes.Reader
+	if method == http.MethodPost {
+		body = bytes.NewReader([]byte("{}"))
+	} else {
+		body = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Errorf("fail to read the response, err: %v", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var apiErr apiError
+		if json.Unmarshal(data, &apiErr) == nil && apiErr.Error.Message != "" {
+			return errors.Errorf("%v %v", apiErr.Error.Code, apiErr.Error.Message)
+		}
+		return errors.Errorf("%v %v", resp.Status, string(data))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return errors.Errorf("fail to parse the response, err: %v", err)
+	}
+	return nil
+}
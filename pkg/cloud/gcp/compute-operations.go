@@ -0,0 +1,221 @@
+package gcp
+
+import (
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/litmuschaos/litmus-go/pkg/cloud"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// instanceResource contains the fields of the compute engine instance used by the provider
+type instanceResource struct {
+	Name              string            `json:"name"`
+	Status            string            `json:"status"`
+	Zone              string            `json:"zone"`
+	Labels            map[string]string `json:"labels"`
+	NetworkInterfaces []struct {
+		Name       string `json:"name"`
+		Subnetwork string `json:"subnetwork"`
+	} `json:"networkInterfaces"`
+}
+
+// instanceListResponse is the response of the instance list api
+type instanceListResponse struct {
+	Items         []instanceResource `json:"items"`
+	NextPageToken string             `json:"nextPageToken"`
+}
+
+// aggregatedInstanceList is the response of the aggregated instance list api, the items are keyed by the zone
+type aggregatedInstanceList struct {
+	Items map[string]struct {
+		Instances []instanceResource `json:"instances"`
+	} `json:"items"`
+	NextPageToken string `json:"nextPageToken"`
+}
+
+// operation is the response of the instance stop and start api
+type operation struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  *struct {
+		Errors []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	} `json:"error"`
+}
+
+// StopInstance will stop the gcp vm instance
+func (p *Provider) StopInstance(instanceID string) error {
+	return p.instanceAction(instanceID, "stop")
+}
+
+// StartInstance will start the gcp vm instance
+func (p *Provider) StartInstance(instanceID string) error {
+	return p.instanceAction(instanceID, "start")
+}
+
+// RebootInstance will reset the gcp vm instance
+func (p *Provider) RebootInstance(instanceID string) error {
+	return p.instanceAction(instanceID, "reset")
+}
+
+// instanceAction invokes the stop, start or reset action on the instance
+func (p *Provider) instanceAction(instanceID, action string) error {
+
+	zone, name, err := SplitInstanceID(instanceID)
+	if err != nil {
+		return err
+	}
+
+	var op operation
+	if err := p.do(http.MethodPost, p.instancePath(zone, name)+"/"+action, nil, &op); err != nil {
+		return err
+	}
+	if op.Error != nil && len(op.Error.Errors) != 0 {
+		return errors.Errorf("%v operation of %v instance failed, err: %v", action, instanceID, op.Error.Errors[0].Message)
+	}
+
+	log.InfoWithValues("Invoking the "+action+" operation on vm instance:", logrus.Fields{
+		"InstanceName": name,
+		"Zone":         zone,
+		"Operation":    op.Name,
+		"Status":       op.Status,
+	})
+	return nil
+}
+
+// DescribeInstances will give the details of the given vm instances
+func (p *Provider) DescribeInstances(instanceIDs ...string) ([]cloud.Instance, error) {
+
+	var instances []cloud.Instance
+	for _, id := range instanceIDs {
+		zone, name, err := SplitInstanceID(id)
+		if err != nil {
+			return nil, err
+		}
+		var resource instanceResource
+		if err := p.do(http.MethodGet, p.instancePath(zone, name), nil, &resource); err != nil {
+			return nil, err
+		}
+		instances = append(instances, toInstance(resource))
+	}
+	return instances, nil
+}
+
+// ListByTag will filter out the target instances using the label filter, the label is in key:value format
+func (p *Provider) ListByTag(label string) ([]string, error) {
+
+	keyValue := strings.Split(label, ":")
+	if len(keyValue) != 2 {
+		return nil, errors.Errorf("fail to parse the label %v, provide it in key:value format", label)
+	}
+
+	query := url.Values{"filter": {"labels." + keyValue[0] + "=" + keyValue[1]}}
+	var instanceList []string
+	for {
+		var result aggregatedInstanceList
+		if err := p.do(http.MethodGet, "projects/"+p.project+"/aggregated/instances", query, &result); err != nil {
+			return nil, errors.Errorf("fail to list the instances, err: %v", err)
+		}
+		for _, scoped := range result.Items {
+			for _, resource := range scoped.Instances {
+				instanceList = append(instanceList, toInstance(resource).ID)
+			}
+		}
+		if result.NextPageToken == "" {
+			break
+		}
+		query.Set("pageToken", result.NextPageToken)
+	}
+	return instanceList, nil
+}
+
+// ListByZone will filter out the running instances of the given zone
+func (p *Provider) ListByZone(zone string) ([]string, error) {
+
+	if zone == "" {
+		return nil, errors.Errorf("fail to get the zone please provide a valid zone")
+	}
+
+	query := url.Values{"filter": {"status=RUNNING"}}
+	var instanceList []string
+	for {
+		var result instanceListResponse
+		if err := p.do(http.MethodGet, "projects/"+p.project+"/zones/"+zone+"/instances", query, &result); err != nil {
+			return nil, errors.Errorf("fail to list the instances, err: %v", err)
+		}
+		for _, resource := range result.Items {
+			instanceList = append(instanceList, toInstance(resource).ID)
+		}
+		if result.NextPageToken == "" {
+			break
+		}
+		query.Set("pageToken", result.NextPageToken)
+	}
+	return instanceList, nil
+}
+
+// instancePath returns the api path of the instance
+func (p *Provider) instancePath(zone, name string) string {
+	return "projects/" + p.project + "/zones/" + zone + "/instances/" + name
+}
+
+// SplitInstanceID splits the instance id provided in zone/name format
+func SplitInstanceID(instanceID string) (string, string, error) {
+	parts := strings.Split(instanceID, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("fail to parse the instance id %v, provide it in zone/name format", instanceID)
+	}
+	return parts[0], parts[1], nil
+}
+
+// GetInstanceIDFromProviderID extracts the instance id in zone/name format from the provider id of the node
+// the provider id is in the gce://<project>/<zone>/<name> format
+func GetInstanceIDFromProviderID(providerID string) (string, error) {
+
+	if !strings.HasPrefix(providerID, "gce://") {
+		return "", errors.Errorf("the provider id %v is not a gce provider id", providerID)
+	}
+	parts := strings.Split(strings.TrimPrefix(providerID, "gce://"), "/")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return "", errors.Errorf("unable to parse the instance id from the provider id %v", providerID)
+	}
+	return parts[1] + "/" + parts[2], nil
+}
+
+// toInstance converts the compute engine instance into the provider instance
+// the compute engine reports the stopped instances as TERMINATED, they are mapped to the stopped state
+func toInstance(resource instanceResource) cloud.Instance {
+
+	zone := path.Base(resource.Zone)
+	instance := cloud.Instance{
+		ID:               zone + "/" + resource.Name,
+		AvailabilityZone: zone,
+		Tags:             resource.Labels,
+	}
+	switch resource.Status {
+	case "RUNNING":
+		instance.State = cloud.InstanceRunning
+	case "TERMINATED", "STOPPED":
+		instance.State = cloud.InstanceStopped
+	default:
+		instance.State = strings.ToLower(resource.Status)
+	}
+	for _, networkInterface := range resource.NetworkInterfaces {
+		subnet := path.Base(networkInterface.Subnetwork)
+		if instance.SubnetID == "" {
+			instance.SubnetID = subnet
+		}
+		instance.NetworkInterfaces = append(instance.NetworkInterfaces, cloud.NetworkInterface{
+			ID:       networkInterface.Name,
+			SubnetID: subnet,
+		})
+	}
+	return instance
+}
@@ -0,0 +1,249 @@
+package cloud
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/events"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/math"
+	"github.com/litmuschaos/litmus-go/pkg/probe"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/pkg/errors"
+)
+
+// InstanceStopDetails contains the attributes of the instance stop chaos shared by the cloud vm experiments
+type InstanceStopDetails struct {
+	ExperimentName       string
+	EngineName           string
+	RampTime             int
+	ChaosDuration        int
+	ChaosInterval        int
+	Timeout              int
+	Delay                int
+	Sequence             string
+	InstanceAffectedPerc int
+	// InstanceKind is the kind of the instance used in the logs and events ex: vm, azure
+	InstanceKind string
+	// SkipStart skips the start of the stopped instances, if they are started back by their managed group
+	SkipStart bool
+}
+
+// PrepareInstanceStop contains the prepration and injection steps of the instance stop chaos
+// it stops and starts back the instances selected by the instance affected percentage
+func PrepareInstanceStop(details InstanceStopDetails, targetInstanceIDList []string, clients clients.ClientSets, provider InstanceProvider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	var err error
+	//Waiting for the ramp time before chaos injection
+	if details.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time before injecting chaos", details.RampTime)
+		common.WaitForDuration(details.RampTime)
+	}
+
+	if len(targetInstanceIDList) == 0 {
+		return errors.Errorf("no target instance found")
+	}
+	instanceIDList := CalculateInstanceAffPerc(details.InstanceAffectedPerc, targetInstanceIDList)
+	log.Infof("[Chaos]:Number of Instance targeted: %v", len(instanceIDList))
+
+	if strings.ToLower(details.Sequence) == "serial" {
+		if err = InjectInstanceStopInSerialMode(details, instanceIDList, clients, provider, resultDetails, eventsDetails, chaosDetails); err != nil {
+			return err
+		}
+	} else {
+		if err = InjectInstanceStopInParallelMode(details, instanceIDList, clients, provider, resultDetails, eventsDetails, chaosDetails); err != nil {
+			return err
+		}
+	}
+	//Waiting for the ramp time after chaos injection
+	if details.RampTime != 0 {
+		log.Infof("[Ramp]: Waiting for the %vs ramp time after injecting chaos", details.RampTime)
+		common.WaitForDuration(details.RampTime)
+	}
+	return nil
+}
+
+// InjectInstanceStopInSerialMode will inject the instance stop in serial mode that is one after other
+func InjectInstanceStopInSerialMode(details InstanceStopDetails, instanceIDList []string, clients clients.ClientSets, provider InstanceProvider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	//ChaosStartTimeStamp contains the start timestamp, when the chaos injection begin
+	ChaosStartTimeStamp := time.Now().Unix()
+
+loop:
+	for {
+
+		log.Infof("Target instanceID list, %v", instanceIDList)
+
+		if details.EngineName != "" {
+			msg := "Injecting " + details.ExperimentName + " chaos on " + details.InstanceKind + " instance"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		//PowerOff the instance
+		for _, id := range instanceIDList {
+
+			if err := stopInstances(details, []string{id}, provider); err != nil {
+				return err
+			}
+
+			// run the probes during chaos
+			if len(resultDetails.ProbeDetails) != 0 {
+				if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+					return err
+				}
+			}
+
+			//Wait for chaos interval
+			log.Infof("[Wait]: Waiting for chaos interval of %vs before starting the instance", details.ChaosInterval)
+			time.Sleep(time.Duration(details.ChaosInterval) * time.Second)
+
+			if err := startInstances(details, []string{id}, provider); err != nil {
+				return err
+			}
+
+			//ChaosCurrentTimeStamp contains the current timestamp
+			ChaosCurrentTimeStamp := time.Now().Unix()
+
+			//ChaosDiffTimeStamp contains the difference of current timestamp and start timestamp
+			//It will helpful to track the total chaos duration
+			chaosDiffTimeStamp := ChaosCurrentTimeStamp - ChaosStartTimeStamp
+
+			if int(chaosDiffTimeStamp) >= details.ChaosDuration {
+				log.Infof("[Chaos]: Time is up for experiment: %v", details.ExperimentName)
+				break loop
+			}
+		}
+	}
+
+	return nil
+}
+
+// InjectInstanceStopInParallelMode will inject the instance stop in parallel mode that is all at once
+func InjectInstanceStopInParallelMode(details InstanceStopDetails, instanceIDList []string, clients clients.ClientSets, provider InstanceProvider, resultDetails *types.ResultDetails, eventsDetails *types.EventDetails, chaosDetails *types.ChaosDetails) error {
+
+	//ChaosStartTimeStamp contains the start timestamp, when the chaos injection begin
+	ChaosStartTimeStamp := time.Now().Unix()
+
+loop:
+	for {
+
+		log.Infof("Target instanceID list, %v", instanceIDList)
+
+		if details.EngineName != "" {
+			msg := "Injecting " + details.ExperimentName + " chaos on " + details.InstanceKind + " instance"
+			types.SetEngineEventAttributes(eventsDetails, types.ChaosInject, msg, "Normal", chaosDetails)
+			events.GenerateEvents(eventsDetails, clients, chaosDetails, "ChaosEngine")
+		}
+
+		if err := stopInstances(details, instanceIDList, provider); err != nil {
+			return err
+		}
+
+		// run the probes during chaos
+		if len(resultDetails.ProbeDetails) != 0 {
+			if err := probe.RunProbes(chaosDetails, clients, resultDetails, "DuringChaos", eventsDetails); err != nil {
+				return err
+			}
+		}
+
+		//Wait for chaos interval
+		log.Infof("[Wait]: Waiting for chaos interval of %vs before starting the instance", details.ChaosInterval)
+		time.Sleep(time.Duration(details.ChaosInterval) * time.Second)
+
+		if err := startInstances(details, instanceIDList, provider); err != nil {
+			return err
+		}
+
+		//ChaosCurrentTimeStamp contains the current timestamp
+		ChaosCurrentTimeStamp := time.Now().Unix()
+
+		//ChaosDiffTimeStamp contains the difference of current timestamp and start timestamp
+		//It will helpful to track the total chaos duration
+		chaosDiffTimeStamp := ChaosCurrentTimeStamp - ChaosStartTimeStamp
+
+		if int(chaosDiffTimeStamp) >= details.ChaosDuration {
+			log.Infof("[Chaos]: Time is up for experiment: %v", details.ExperimentName)
+			break loop
+		}
+	}
+
+	return nil
+}
+
+// stopInstances stops all the given instances and waits for them to come in stopped state
+func stopInstances(details InstanceStopDetails, instanceIDList []string, provider InstanceProvider) error {
+
+	for _, id := range instanceIDList {
+		log.Infof("[Chaos]: Stoping the %v instance '%v'", details.InstanceKind, id)
+		if err := provider.StopInstance(id); err != nil {
+			return errors.Errorf("%v instance failed to stop, err: %v", details.InstanceKind, err)
+		}
+	}
+
+	for _, id := range instanceIDList {
+		log.Infof("[Wait]: Wait for %v instance '%v' to come in stopped state", details.InstanceKind, id)
+		if err := WaitForInstanceState(provider, details.Timeout, details.Delay, id, InstanceStopped); err != nil {
+			return errors.Errorf("unable to stop the %v instance, err: %v", details.InstanceKind, err)
+		}
+	}
+	return nil
+}
+
+// startInstances starts back all the given instances and waits for them to come in running state
+// the instances of a managed group are started back by the group itself, so only their state is awaited
+func startInstances(details InstanceStopDetails, instanceIDList []string, provider InstanceProvider) error {
+
+	if !details.SkipStart {
+		for _, id := range instanceIDList {
+			log.Infof("[Chaos]: Starting back the %v instance '%v'", details.InstanceKind, id)
+			if err := provider.StartInstance(id); err != nil {
+				return errors.Errorf("%v instance failed to start, err: %v", details.InstanceKind, err)
+			}
+		}
+	}
+
+	for _, id := range instanceIDList {
+		log.Infof("[Wait]: Wait for %v instance '%v' to get in running state", details.InstanceKind, id)
+		if err := WaitForInstanceUp(provider, details.Timeout, details.Delay, id); err != nil {
+			return errors.Errorf("unable to start the %v instance, err: %v", details.InstanceKind, err)
+		}
+	}
+	return nil
+}
+
+// CalculateInstanceAffPerc will calculate the target instance ids according to the instance affected percentage provided.
+func CalculateInstanceAffPerc(InstanceAffPerc int, instanceList []string) []string {
+
+	var newIDList []string
+	newInstanceListLength := math.Maximum(1, math.Adjustment(InstanceAffPerc, len(instanceList)))
+	rand.Seed(time.Now().UnixNano())
+
+	// it will generate the random instanceList
+	// it starts from the random index and choose requirement no of instanceID next to that index in a circular way.
+	index := rand.Intn(len(instanceList))
+	for i := 0; i < newInstanceListLength; i++ {
+		newIDList = append(newIDList, instanceList[index])
+		index = (index + 1) % len(instanceList)
+	}
+	return newIDList
+}
+
+// InstanceStatusCheck verifies that all the given instances are in running state
+func InstanceStatusCheck(instanceKind string, instanceIDList []string, provider InstanceProvider) error {
+
+	log.Infof("[Info]: The instances under chaos(IUC) are: %v", instanceIDList)
+	for _, id := range instanceIDList {
+		instanceState, err := GetInstanceStatus(provider, id)
+		if err != nil {
+			return err
+		}
+		if instanceState != InstanceRunning {
+			return errors.Errorf("failed to get the %v instance '%v' status as running", instanceKind, id)
+		}
+	}
+	return nil
+}
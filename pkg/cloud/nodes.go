@@ -1,11 +1,8 @@
-package aws
+package cloud
 
 import (
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/litmuschaos/litmus-go/pkg/clients"
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/litmuschaos/litmus-go/pkg/status"
@@ -15,39 +12,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-//GetEC2InstanceStatus will verify and give the ec2 instance details along with ebs volume idetails.
-func GetEC2InstanceStatus(instanceID, region string) (string, error) {
-
-	var err error
-	// Load session from shared config
-	sess := session.Must(session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-		Config:            aws.Config{Region: aws.String(region)},
-	}))
-
-	// Create new EC2 client
-	ec2Svc := ec2.New(sess)
-
-	// Call to get detailed information on each instance
-	result, err := ec2Svc.DescribeInstances(nil)
-	if err != nil {
-		return "", err
-	}
-
-	for _, reservationDetails := range result.Reservations {
-
-		for _, instanceDetails := range reservationDetails.Instances {
-
-			if *instanceDetails.InstanceId == instanceID {
-				return *instanceDetails.State.Name, nil
-			}
-		}
-	}
-	return "", errors.Errorf("failed to get the status of ec2 instance with instanceID %v", region)
-
-}
-
-// PreChaosNodeStatusCheck fetch the target node name from instance id and checks its status also fetch the total active nodes in the cluster
+// PreChaosNodeStatusCheck checks the status of all the nodes and fetch the total active nodes in the cluster
 func PreChaosNodeStatusCheck(timeout, delay int, clients clients.ClientSets) (int, error) {
 
 	nodeList, err := clients.KubeClient.CoreV1().Nodes().List(metav1.ListOptions{})
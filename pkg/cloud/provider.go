@@ -0,0 +1,99 @@
+package cloud
+
+import "time"
+
+// Instance contains the details of a cloud instance
+type Instance struct {
+	ID               string
+	State            string
+	AvailabilityZone string
+	SubnetID         string
+	Tags             map[string]string
+	// NetworkInterfaces are the network interfaces attached to the instance
+	NetworkInterfaces []NetworkInterface
+}
+
+// NetworkInterface contains the details of a network interface attached to an instance
+type NetworkInterface struct {
+	ID             string
+	SubnetID       string
+	SecurityGroups []string
+}
+
+// NetworkACLAssociation contains the association of a network acl with a subnet
+type NetworkACLAssociation struct {
+	ID           string
+	NetworkACLID string
+	SubnetID     string
+}
+
+// VolumeAttachment contains the attachment details of a cloud volume
+type VolumeAttachment struct {
+	InstanceID string
+	Device     string
+	State      string
+}
+
+// Volume contains the details of a cloud volume
+type Volume struct {
+	ID          string
+	Type        string
+	State       string
+	Attachments []VolumeAttachment
+	Tags        map[string]string
+}
+
+// InstanceProvider abstracts the instance operations of a cloud provider
+// so that the chaoslib can be run against the real cloud api or the in-memory fake
+type InstanceProvider interface {
+	// CheckCredentials verifies that the credentials of the provider are valid
+	CheckCredentials() error
+	// StopInstance stops the given instance
+	StopInstance(instanceID string) error
+	// StartInstance starts the given instance
+	StartInstance(instanceID string) error
+	// DescribeInstances returns the details of the given instances
+	DescribeInstances(instanceIDs ...string) ([]Instance, error)
+	// ListByTag returns the ids of the instances having the given tag, the tag is in key:value format
+	ListByTag(tag string) ([]string, error)
+	// ListByZone returns the ids of the running instances in the given availability zone
+	ListByZone(zone string) ([]string, error)
+}
+
+// Poller is implemented by the providers which are polled in a custom unit of the delay, ex: the in-memory fake
+// the delays between the status checks of the other providers are in seconds
+type Poller interface {
+	// DelayUnit returns the unit of the delay between the status checks
+	DelayUnit() time.Duration
+}
+
+// Rebooter is implemented by the providers which can reboot an instance in place
+type Rebooter interface {
+	// RebootInstance reboots the given instance without changing its power state
+	RebootInstance(instanceID string) error
+}
+
+// Provider extends the InstanceProvider with the volume operations of a cloud provider
+type Provider interface {
+	InstanceProvider
+	// ListVolumesByTag returns the ids of the volumes having the given tag, the tag is in key:value format
+	ListVolumesByTag(tag string) ([]string, error)
+	// DescribeVolumes returns the details of the given volumes
+	DescribeVolumes(volumeIDs ...string) ([]Volume, error)
+	// DetachVolume detaches the given volume from its instance
+	DetachVolume(volumeID string) error
+	// AttachVolume attaches the given volume to the instance at the given device
+	AttachVolume(volumeID, instanceID, device string) error
+}
+
+// NetworkProvider extends the Provider with the network operations used to isolate the instances
+type NetworkProvider interface {
+	Provider
+	// SetSecurityGroups replaces the security groups of the given network interface
+	SetSecurityGroups(networkInterfaceID string, securityGroupIDs []string) error
+	// GetNetworkACLAssociation returns the network acl association of the given subnet
+	GetNetworkACLAssociation(subnetID string) (NetworkACLAssociation, error)
+	// ReplaceNetworkACLAssociation associates the network acl with the subnet of the given association
+	// and returns the id of the new association
+	ReplaceNetworkACLAssociation(associationID, networkACLID string) (string, error)
+}
@@ -0,0 +1,139 @@
+package cloud
+
+import (
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// InstanceRunning is the state of a running instance
+	InstanceRunning = "running"
+	// InstanceStopped is the state of a stopped instance
+	InstanceStopped = "stopped"
+	// InstanceTerminated is the state of a terminated instance
+	InstanceTerminated = "terminated"
+	// VolumeAttached is the state of an attached volume
+	VolumeAttached = "attached"
+	// VolumeDetached is the state of a detached volume
+	VolumeDetached = "detached"
+	// VolumeDetaching is the state of a volume, which is not yet detached
+	VolumeDetaching = "detaching"
+)
+
+// GetInstanceStatus returns the current state of the given instance
+func GetInstanceStatus(provider InstanceProvider, instanceID string) (string, error) {
+
+	instances, err := provider.DescribeInstances(instanceID)
+	if err != nil {
+		return "", err
+	}
+	for _, instance := range instances {
+		if instance.ID == instanceID {
+			return instance.State, nil
+		}
+	}
+	return "", errors.Errorf("failed to get the status of instance with instanceID %v", instanceID)
+}
+
+// GetVolumeStatus returns the attachment state of the given volume w.r.t the given instance
+// it returns detached if the volume is not attached to any instance
+func GetVolumeStatus(provider Provider, volumeID, instanceID string) (string, error) {
+
+	volumes, err := provider.DescribeVolumes(volumeID)
+	if err != nil {
+		return "", err
+	}
+	for _, volume := range volumes {
+		if volume.ID != volumeID {
+			continue
+		}
+		if len(volume.Attachments) > 0 {
+			if volume.Attachments[0].InstanceID == instanceID {
+
+				// DISPLAY THE VOLUME INFORMATION
+				log.InfoWithValues("The selected volume is:", logrus.Fields{
+					"VolumeId":   volume.ID,
+					"VolumeType": volume.Type,
+					"Status":     volume.State,
+					"InstanceId": volume.Attachments[0].InstanceID,
+					"Device":     volume.Attachments[0].Device,
+					"State":      volume.Attachments[0].State,
+				})
+				return volume.Attachments[0].State, nil
+			}
+		}
+		return VolumeDetached, nil
+	}
+	return "", errors.Errorf("unable to find the volume with volumeId %v", volumeID)
+}
+
+// delayUnit returns the unit of the delay between the status checks of the provider
+func delayUnit(provider InstanceProvider) time.Duration {
+
+	if poller, ok := provider.(Poller); ok {
+		return poller.DelayUnit()
+	}
+	return time.Second
+}
+
+// WaitForInstanceDown will wait for the instance to get in stopped state
+// the instance is expected to get terminated if it is part of a managed nodegroup
+func WaitForInstanceDown(provider InstanceProvider, timeout, delay int, managedNodegroup, instanceID string) error {
+
+	expectedState := InstanceStopped
+	if managedNodegroup == "enable" {
+		expectedState = InstanceTerminated
+	}
+	return WaitForInstanceState(provider, timeout, delay, instanceID, expectedState)
+}
+
+// WaitForInstanceUp will wait for the instance to get in running state
+func WaitForInstanceUp(provider InstanceProvider, timeout, delay int, instanceID string) error {
+	return WaitForInstanceState(provider, timeout, delay, instanceID, InstanceRunning)
+}
+
+// WaitForInstanceState will wait for the instance to get in the expected state
+func WaitForInstanceState(provider InstanceProvider, timeout, delay int, instanceID, expectedState string) error {
+
+	log.Info("[Status]: Checking instance status")
+	return retry.
+		Times(uint(timeout / delay)).
+		Wait(time.Duration(delay) * delayUnit(provider)).
+		Try(func(attempt uint) error {
+
+			instanceState, err := GetInstanceStatus(provider, instanceID)
+			if err != nil {
+				return errors.Errorf("failed to get the instance status, err: %v", err)
+			}
+			log.Infof("The instance state is %v", instanceState)
+			if instanceState != expectedState {
+				return errors.Errorf("instance is not yet in %v state", expectedState)
+			}
+			return nil
+		})
+}
+
+// WaitForVolumeState will wait for the volume to get in the expected attachment state w.r.t the given instance
+func WaitForVolumeState(provider Provider, timeout, delay int, volumeID, instanceID, expectedState string) error {
+
+	log.Infof("[Status]: Checking volume status for %v state", expectedState)
+	return retry.
+		Times(uint(timeout / delay)).
+		Wait(time.Duration(delay) * delayUnit(provider)).
+		Try(func(attempt uint) error {
+
+			volumeState, err := GetVolumeStatus(provider, volumeID, instanceID)
+			if err != nil {
+				return errors.Errorf("failed to get the volume status, err: %v", err)
+			}
+			log.Infof("The volume state is %v", volumeState)
+			if volumeState != expectedState {
+				return errors.Errorf("volume is not yet in %v state", expectedState)
+			}
+			return nil
+		})
+}
@@ -0,0 +1,37 @@
+package types
+
+import (
+	clientTypes "k8s.io/apimachinery/pkg/types"
+)
+
+// ExperimentDetails is for collecting all the experiment-related details
+type ExperimentDetails struct {
+	ExperimentName       string
+	EngineName           string
+	RampTime             int
+	AppNS                string
+	AppLabel             string
+	AppKind              string
+	AuxiliaryAppInfo     string
+	ChaosLib             string
+	ChaosDuration        int
+	ChaosInterval        int
+	ChaosUID             clientTypes.UID
+	InstanceID           string
+	ChaosNamespace       string
+	ChaosPodName         string
+	Timeout              int
+	Delay                int
+	GCPProjectID         string
+	CredentialsFile      string
+	EndpointURL          string
+	VMInstanceNames      string
+	InstanceZones        string
+	InstanceLabel        string
+	ManagedInstanceGroup string
+	InstanceAffectedPerc int
+	Sequence             string
+	TargetInstanceIDList []string
+	LIBImagePullPolicy   string
+	TargetContainer      string
+}
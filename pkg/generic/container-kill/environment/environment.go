@@ -69,4 +69,9 @@ func InitialiseChaosVariables(chaosDetails *types.ChaosDetails, experimentDetail
 	chaosDetails.AppDetail = appDetails
 	chaosDetails.JobCleanupPolicy = Getenv("JOB_CLEANUP_POLICY", "retain")
 	chaosDetails.ProbeImagePullPolicy = experimentDetails.LIBImagePullPolicy
+	chaosDetails.HelperSeccompProfile = Getenv("HELPER_SECCOMP_PROFILE", "")
+	chaosDetails.HelperAppArmorProfile = Getenv("HELPER_APPARMOR_PROFILE", "")
+	chaosDetails.HelperTolerations = Getenv("HELPER_TOLERATIONS", "")
+	chaosDetails.HelperPriorityClass = Getenv("HELPER_PRIORITY_CLASS", "")
+	chaosDetails.HelperLogTailLines, _ = strconv.Atoi(Getenv("HELPER_LOG_TAIL_LINES", "50"))
 }
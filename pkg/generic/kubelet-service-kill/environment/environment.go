@@ -26,6 +26,12 @@ func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
 	experimentDetails.ChaosPodName = Getenv("POD_NAME", "")
 	experimentDetails.AuxiliaryAppInfo = Getenv("AUXILIARY_APPINFO", "")
 	experimentDetails.TargetNode = Getenv("TARGET_NODE", "")
+	experimentDetails.NodeLabel = Getenv("NODE_LABEL", "")
+	experimentDetails.MaxTargetNodes = 1
+	experimentDetails.TargetAppNodes, _ = strconv.ParseBool(Getenv("TARGET_APP_NODES", "true"))
+	experimentDetails.ExcludeControlPlaneNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_CONTROL_PLANE_NODES", "true"))
+	experimentDetails.ExcludeNotReadyNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_NOT_READY_NODES", "true"))
+	experimentDetails.ExcludeCordonedNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_CORDONED_NODES", "true"))
 	experimentDetails.Delay, _ = strconv.Atoi(Getenv("STATUS_CHECK_DELAY", "2"))
 	experimentDetails.Timeout, _ = strconv.Atoi(Getenv("STATUS_CHECK_TIMEOUT", "180"))
 	experimentDetails.LIBImage = Getenv("LIB_IMAGE", "ubuntu:16.04")
@@ -55,4 +61,9 @@ func InitialiseChaosVariables(chaosDetails *types.ChaosDetails, experimentDetail
 	chaosDetails.Delay = experimentDetails.Delay
 	chaosDetails.JobCleanupPolicy = Getenv("JOB_CLEANUP_POLICY", "retain")
 	chaosDetails.ProbeImagePullPolicy = experimentDetails.LIBImagePullPolicy
+	chaosDetails.HelperSeccompProfile = Getenv("HELPER_SECCOMP_PROFILE", "")
+	chaosDetails.HelperAppArmorProfile = Getenv("HELPER_APPARMOR_PROFILE", "")
+	chaosDetails.HelperTolerations = Getenv("HELPER_TOLERATIONS", "")
+	chaosDetails.HelperPriorityClass = Getenv("HELPER_PRIORITY_CLASS", "")
+	chaosDetails.HelperLogTailLines, _ = strconv.Atoi(Getenv("HELPER_LOG_TAIL_LINES", "50"))
 }
@@ -34,6 +34,8 @@ type ExperimentDetails struct {
 	TargetPods                         string
 	PodsAffectedPerc                   int
 	DestinationIPs                     string
+	ExcludedIPs                        string
+	NetworkNamespace                   string
 	Annotations                        map[string]string
 	DestinationHosts                   string
 	ContainerRuntime                   string
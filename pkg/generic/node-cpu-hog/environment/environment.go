@@ -6,6 +6,7 @@ import (
 
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/node-cpu-hog/types"
 	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
 	clientTypes "k8s.io/apimachinery/pkg/types"
 )
 
@@ -31,8 +32,17 @@ func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
 	experimentDetails.Timeout, _ = strconv.Atoi(Getenv("STATUS_CHECK_TIMEOUT", "180"))
 	experimentDetails.TargetNodes = Getenv("TARGET_NODES", "")
 	experimentDetails.NodesAffectedPerc, _ = strconv.Atoi(Getenv("NODES_AFFECTED_PERC", "0"))
+	experimentDetails.NodeLabel = Getenv("NODE_LABEL", "")
+	experimentDetails.MaxTargetNodes, _ = strconv.Atoi(Getenv("MAX_TARGET_NODES", "0"))
+	experimentDetails.TargetAppNodes, _ = strconv.ParseBool(Getenv("TARGET_APP_NODES", "false"))
+	experimentDetails.ExcludeControlPlaneNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_CONTROL_PLANE_NODES", "true"))
+	experimentDetails.ExcludeNotReadyNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_NOT_READY_NODES", "true"))
+	experimentDetails.ExcludeCordonedNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_CORDONED_NODES", "true"))
 	experimentDetails.Sequence = Getenv("SEQUENCE", "parallel")
 	experimentDetails.TargetContainer = Getenv("TARGET_CONTAINER", "")
+	experimentDetails.TargetCPUUtilisation, _ = strconv.Atoi(Getenv("TARGET_CPU_UTILISATION", "0"))
+	experimentDetails.MetricsSource = Getenv("METRICS_SOURCE", "kubelet")
+	experimentDetails.ControlInterval = common.ParseControlInterval(os.Getenv("CONTROL_INTERVAL"))
 }
 
 // Getenv fetch the env and set the default value, if any
@@ -57,4 +67,9 @@ func InitialiseChaosVariables(chaosDetails *types.ChaosDetails, experimentDetail
 	chaosDetails.Delay = experimentDetails.Delay
 	chaosDetails.JobCleanupPolicy = Getenv("JOB_CLEANUP_POLICY", "retain")
 	chaosDetails.ProbeImagePullPolicy = experimentDetails.LIBImagePullPolicy
+	chaosDetails.HelperSeccompProfile = Getenv("HELPER_SECCOMP_PROFILE", "")
+	chaosDetails.HelperAppArmorProfile = Getenv("HELPER_APPARMOR_PROFILE", "")
+	chaosDetails.HelperTolerations = Getenv("HELPER_TOLERATIONS", "")
+	chaosDetails.HelperPriorityClass = Getenv("HELPER_PRIORITY_CLASS", "")
+	chaosDetails.HelperLogTailLines, _ = strconv.Atoi(Getenv("HELPER_LOG_TAIL_LINES", "50"))
 }
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
 	corev1 "k8s.io/api/core/v1"
 	clientTypes "k8s.io/apimachinery/pkg/types"
 )
@@ -20,17 +21,19 @@ type ExperimentDetails struct {
 	ChaosNamespace     string
 	ChaosPodName       string
 	NodeCPUcores       int
-	RunID              string
 	LIBImage           string
 	LIBImagePullPolicy string
 	AuxiliaryAppInfo   string
 	Timeout            int
 	Delay              int
 	Annotations        map[string]string
-	TargetNodes        string
-	NodesAffectedPerc  int
-	Sequence           string
-	Resources          corev1.ResourceRequirements
-	ImagePullSecrets   []corev1.LocalObjectReference
-	TargetContainer    string
+	common.NodeTargetDetails
+	Sequence             string
+	Resources            corev1.ResourceRequirements
+	ImagePullSecrets     []corev1.LocalObjectReference
+	TargetContainer      string
+	TargetCPUUtilisation int
+	MetricsSource        string
+	ControlInterval      int
+	ChaosServiceAccount  string
 }
@@ -0,0 +1,78 @@
+package environment
+
+import (
+	"os"
+	"strconv"
+
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/generic/node-disk-fill/types"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	clientTypes "k8s.io/apimachinery/pkg/types"
+)
+
+//GetENV fetches all the env variables from the runner pod
+func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
+	experimentDetails.ExperimentName = Getenv("EXPERIMENT_NAME", "node-disk-fill")
+	experimentDetails.ChaosNamespace = Getenv("CHAOS_NAMESPACE", "litmus")
+	experimentDetails.EngineName = Getenv("CHAOSENGINE", "")
+	experimentDetails.ChaosDuration, _ = strconv.Atoi(Getenv("TOTAL_CHAOS_DURATION", "120"))
+	experimentDetails.RampTime, _ = strconv.Atoi(Getenv("RAMP_TIME", "0"))
+	experimentDetails.ChaosLib = Getenv("LIB", "litmus")
+	experimentDetails.AppNS = Getenv("APP_NAMESPACE", "")
+	experimentDetails.AppLabel = Getenv("APP_LABEL", "")
+	experimentDetails.AppKind = Getenv("APP_KIND", "")
+	experimentDetails.ChaosUID = clientTypes.UID(Getenv("CHAOS_UID", ""))
+	experimentDetails.InstanceID = Getenv("INSTANCE_ID", "")
+	experimentDetails.ChaosPodName = Getenv("POD_NAME", "")
+	experimentDetails.AuxiliaryAppInfo = Getenv("AUXILIARY_APPINFO", "")
+	experimentDetails.TargetNodes = Getenv("TARGET_NODES", "")
+	experimentDetails.NodeLabel = Getenv("NODE_LABEL", "")
+	experimentDetails.NodesAffectedPerc, _ = strconv.Atoi(Getenv("NODES_AFFECTED_PERC", "0"))
+	experimentDetails.MaxTargetNodes, _ = strconv.Atoi(Getenv("MAX_TARGET_NODES", "0"))
+	experimentDetails.TargetAppNodes, _ = strconv.ParseBool(Getenv("TARGET_APP_NODES", "false"))
+	experimentDetails.ExcludeControlPlaneNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_CONTROL_PLANE_NODES", "true"))
+	experimentDetails.ExcludeNotReadyNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_NOT_READY_NODES", "true"))
+	experimentDetails.ExcludeCordonedNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_CORDONED_NODES", "true"))
+	experimentDetails.FillPath = Getenv("FILL_PATH", "/var/lib/kubelet")
+	experimentDetails.FillMode = Getenv("FILL_MODE", "space")
+	experimentDetails.FillPercentage, _ = strconv.Atoi(Getenv("FILL_PERCENTAGE", "95"))
+	experimentDetails.FillMebibytes, _ = strconv.Atoi(Getenv("FILL_MEBIBYTES", "0"))
+	experimentDetails.VerifyDiskPressure, _ = strconv.ParseBool(Getenv("VERIFY_DISK_PRESSURE", "true"))
+	experimentDetails.VerifyEviction, _ = strconv.ParseBool(Getenv("VERIFY_EVICTION", "false"))
+	experimentDetails.Sequence = Getenv("SEQUENCE", "parallel")
+	experimentDetails.Delay, _ = strconv.Atoi(Getenv("STATUS_CHECK_DELAY", "2"))
+	experimentDetails.Timeout, _ = strconv.Atoi(Getenv("STATUS_CHECK_TIMEOUT", "180"))
+	experimentDetails.LIBImage = Getenv("LIB_IMAGE", "litmuschaos/go-runner:latest")
+	experimentDetails.LIBImagePullPolicy = Getenv("LIB_IMAGE_PULL_POLICY", "Always")
+	experimentDetails.ChaosServiceAccount = Getenv("CHAOS_SERVICE_ACCOUNT", "")
+	experimentDetails.TerminationGracePeriodSeconds, _ = strconv.Atoi(Getenv("TERMINATION_GRACE_PERIOD_SECONDS", "60"))
+	experimentDetails.TargetContainer = Getenv("TARGET_CONTAINER", "")
+}
+
+// Getenv fetch the env and set the default value, if any
+func Getenv(key string, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		value = defaultValue
+	}
+	return value
+}
+
+//InitialiseChaosVariables initialise all the global variables
+func InitialiseChaosVariables(chaosDetails *types.ChaosDetails, experimentDetails *experimentTypes.ExperimentDetails) {
+
+	chaosDetails.ChaosNamespace = experimentDetails.ChaosNamespace
+	chaosDetails.ChaosPodName = experimentDetails.ChaosPodName
+	chaosDetails.ChaosUID = experimentDetails.ChaosUID
+	chaosDetails.EngineName = experimentDetails.EngineName
+	chaosDetails.ExperimentName = experimentDetails.ExperimentName
+	chaosDetails.InstanceID = experimentDetails.InstanceID
+	chaosDetails.Timeout = experimentDetails.Timeout
+	chaosDetails.Delay = experimentDetails.Delay
+	chaosDetails.JobCleanupPolicy = Getenv("JOB_CLEANUP_POLICY", "retain")
+	chaosDetails.ProbeImagePullPolicy = experimentDetails.LIBImagePullPolicy
+	chaosDetails.HelperSeccompProfile = Getenv("HELPER_SECCOMP_PROFILE", "")
+	chaosDetails.HelperAppArmorProfile = Getenv("HELPER_APPARMOR_PROFILE", "")
+	chaosDetails.HelperTolerations = Getenv("HELPER_TOLERATIONS", "")
+	chaosDetails.HelperPriorityClass = Getenv("HELPER_PRIORITY_CLASS", "")
+	chaosDetails.HelperLogTailLines, _ = strconv.Atoi(Getenv("HELPER_LOG_TAIL_LINES", "50"))
+}
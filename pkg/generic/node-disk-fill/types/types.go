@@ -0,0 +1,43 @@
+package types
+
+import (
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	corev1 "k8s.io/api/core/v1"
+	clientTypes "k8s.io/apimachinery/pkg/types"
+)
+
+// ExperimentDetails is for collecting all the experiment-related details
+type ExperimentDetails struct {
+	ExperimentName   string
+	EngineName       string
+	ChaosDuration    int
+	RampTime         int
+	ChaosLib         string
+	AppNS            string
+	AppLabel         string
+	AppKind          string
+	ChaosUID         clientTypes.UID
+	InstanceID       string
+	ChaosNamespace   string
+	ChaosPodName     string
+	AuxiliaryAppInfo string
+	common.NodeTargetDetails
+	FillPath                      string
+	FillMode                      string
+	FillPercentage                int
+	FillMebibytes                 int
+	FillDirectory                 string
+	VerifyDiskPressure            bool
+	VerifyEviction                bool
+	Sequence                      string
+	Timeout                       int
+	Delay                         int
+	Annotations                   map[string]string
+	LIBImage                      string
+	LIBImagePullPolicy            string
+	ChaosServiceAccount           string
+	TerminationGracePeriodSeconds int
+	Resources                     corev1.ResourceRequirements
+	ImagePullSecrets              []corev1.LocalObjectReference
+	TargetContainer               string
+}
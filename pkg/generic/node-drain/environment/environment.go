@@ -25,7 +25,14 @@ func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
 	experimentDetails.InstanceID = Getenv("INSTANCE_ID", "")
 	experimentDetails.ChaosPodName = Getenv("POD_NAME", "")
 	experimentDetails.AuxiliaryAppInfo = Getenv("AUXILIARY_APPINFO", "")
-	experimentDetails.TargetNode = Getenv("TARGET_NODE", "")
+	experimentDetails.TargetNodes = Getenv("TARGET_NODES", Getenv("TARGET_NODE", ""))
+	experimentDetails.NodeLabel = Getenv("NODE_LABEL", "")
+	experimentDetails.NodesAffectedPerc, _ = strconv.Atoi(Getenv("NODES_AFFECTED_PERC", "0"))
+	experimentDetails.MaxTargetNodes, _ = strconv.Atoi(Getenv("MAX_TARGET_NODES", "0"))
+	experimentDetails.TargetAppNodes, _ = strconv.ParseBool(Getenv("TARGET_APP_NODES", "true"))
+	experimentDetails.ExcludeControlPlaneNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_CONTROL_PLANE_NODES", "true"))
+	experimentDetails.ExcludeNotReadyNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_NOT_READY_NODES", "true"))
+	experimentDetails.ExcludeCordonedNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_CORDONED_NODES", "true"))
 	experimentDetails.Delay, _ = strconv.Atoi(Getenv("STATUS_CHECK_DELAY", "2"))
 	experimentDetails.Timeout, _ = strconv.Atoi(Getenv("STATUS_CHECK_TIMEOUT", "180"))
 	experimentDetails.TargetContainer = Getenv("TARGET_CONTAINER", "")
@@ -1,24 +1,26 @@
 package types
 
 import (
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
 	clientTypes "k8s.io/apimachinery/pkg/types"
 )
 
 // ExperimentDetails is for collecting all the experiment-related details
 type ExperimentDetails struct {
-	ExperimentName     string
-	EngineName         string
-	ChaosDuration      int
-	RampTime           int
-	ChaosLib           string
-	AppNS              string
-	AppLabel           string
-	AppKind            string
-	ChaosUID           clientTypes.UID
-	InstanceID         string
-	ChaosNamespace     string
-	ChaosPodName       string
-	TargetNode         string
+	ExperimentName string
+	EngineName     string
+	ChaosDuration  int
+	RampTime       int
+	ChaosLib       string
+	AppNS          string
+	AppLabel       string
+	AppKind        string
+	ChaosUID       clientTypes.UID
+	InstanceID     string
+	ChaosNamespace string
+	ChaosPodName   string
+	common.NodeTargetDetails
+	TargetNodeList     []string
 	AuxiliaryAppInfo   string
 	Timeout            int
 	Delay              int
@@ -35,6 +35,12 @@ func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
 	experimentDetails.NumberOfWorkers, _ = strconv.Atoi(Getenv("NUMBER_OF_WORKERS", "4"))
 	experimentDetails.VMWorkers, _ = strconv.Atoi(Getenv("VM_WORKERS", "1"))
 	experimentDetails.NodesAffectedPerc, _ = strconv.Atoi(Getenv("NODES_AFFECTED_PERC", "0"))
+	experimentDetails.NodeLabel = Getenv("NODE_LABEL", "")
+	experimentDetails.MaxTargetNodes, _ = strconv.Atoi(Getenv("MAX_TARGET_NODES", "0"))
+	experimentDetails.TargetAppNodes, _ = strconv.ParseBool(Getenv("TARGET_APP_NODES", "false"))
+	experimentDetails.ExcludeControlPlaneNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_CONTROL_PLANE_NODES", "true"))
+	experimentDetails.ExcludeNotReadyNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_NOT_READY_NODES", "true"))
+	experimentDetails.ExcludeCordonedNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_CORDONED_NODES", "true"))
 	experimentDetails.Sequence = Getenv("SEQUENCE", "parallel")
 	experimentDetails.TargetContainer = Getenv("TARGET_CONTAINER", "")
 }
@@ -61,4 +67,9 @@ func InitialiseChaosVariables(chaosDetails *types.ChaosDetails, experimentDetail
 	chaosDetails.Delay = experimentDetails.Delay
 	chaosDetails.JobCleanupPolicy = Getenv("JOB_CLEANUP_POLICY", "retain")
 	chaosDetails.ProbeImagePullPolicy = experimentDetails.LIBImagePullPolicy
+	chaosDetails.HelperSeccompProfile = Getenv("HELPER_SECCOMP_PROFILE", "")
+	chaosDetails.HelperAppArmorProfile = Getenv("HELPER_APPARMOR_PROFILE", "")
+	chaosDetails.HelperTolerations = Getenv("HELPER_TOLERATIONS", "")
+	chaosDetails.HelperPriorityClass = Getenv("HELPER_PRIORITY_CLASS", "")
+	chaosDetails.HelperLogTailLines, _ = strconv.Atoi(Getenv("HELPER_LOG_TAIL_LINES", "50"))
 }
@@ -0,0 +1,44 @@
+package types
+
+import (
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	corev1 "k8s.io/api/core/v1"
+	clientTypes "k8s.io/apimachinery/pkg/types"
+)
+
+// ExperimentDetails is for collecting all the experiment-related details
+type ExperimentDetails struct {
+	ExperimentName              string
+	EngineName                  string
+	ChaosDuration               int
+	LIBImage                    string
+	LIBImagePullPolicy          string
+	RampTime                    int
+	ChaosLib                    string
+	AppNS                       string
+	AppLabel                    string
+	AppKind                     string
+	ChaosUID                    clientTypes.UID
+	InstanceID                  string
+	ChaosNamespace              string
+	ChaosPodName                string
+	RunID                       string
+	NetworkInterface            string
+	NetworkLatency              int
+	NetworkPacketLossPercentage int
+	Timeout                     int
+	Delay                       int
+	common.NodeTargetDetails
+	DestinationIPs                string
+	DestinationHosts              string
+	ExcludedIPs                   string
+	ProtectAPIServer              bool
+	Annotations                   map[string]string
+	AuxiliaryAppInfo              string
+	TargetContainer               string
+	ChaosServiceAccount           string
+	Sequence                      string
+	Resources                     corev1.ResourceRequirements
+	ImagePullSecrets              []corev1.LocalObjectReference
+	TerminationGracePeriodSeconds int
+}
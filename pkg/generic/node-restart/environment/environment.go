@@ -30,8 +30,31 @@ func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
 	experimentDetails.Timeout, _ = strconv.Atoi(Getenv("STATUS_CHECK_TIMEOUT", "180"))
 	experimentDetails.SSHUser = Getenv("SSH_USER", "root")
 	experimentDetails.RebootCommand = Getenv("REBOOT_COMMAND", "sudo systemctl reboot")
+	experimentDetails.RebootMethod = Getenv("REBOOT_METHOD", "ssh")
+	experimentDetails.VerifyNodeNotReady, _ = strconv.ParseBool(Getenv("VERIFY_NODE_NOT_READY", "true"))
+	experimentDetails.CloudProvider = Getenv("CLOUD_PROVIDER", "")
+	experimentDetails.Region = Getenv("REGION", "")
+	experimentDetails.AssumeRoleARN = Getenv("ASSUME_ROLE_ARN", "")
+	experimentDetails.ExternalID = Getenv("EXTERNAL_ID", "")
+	experimentDetails.AWSEndpointURL = Getenv("AWS_ENDPOINT_URL", "")
+	experimentDetails.GCPProjectID = Getenv("GCP_PROJECT_ID", "")
+	experimentDetails.GCPCredentialsFile = Getenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	experimentDetails.GCPEndpointURL = Getenv("GCP_ENDPOINT_URL", "")
+	experimentDetails.AzureSubscriptionID = Getenv("AZURE_SUBSCRIPTION_ID", "")
+	experimentDetails.AzureTenantID = Getenv("AZURE_TENANT_ID", "")
+	experimentDetails.AzureClientID = Getenv("AZURE_CLIENT_ID", "")
+	experimentDetails.AzureClientSecret = Getenv("AZURE_CLIENT_SECRET", "")
+	experimentDetails.AzureFederatedTokenFile = Getenv("AZURE_FEDERATED_TOKEN_FILE", "")
+	experimentDetails.AzureAuthFile = Getenv("AZURE_AUTH_LOCATION", "")
+	experimentDetails.AzureEndpointURL = Getenv("AZURE_ENDPOINT_URL", "")
 	experimentDetails.TargetNode = Getenv("TARGET_NODE", "")
 	experimentDetails.TargetNodeIP = Getenv("TARGET_NODE_IP", "")
+	experimentDetails.NodeLabel = Getenv("NODE_LABEL", "")
+	experimentDetails.MaxTargetNodes = 1
+	experimentDetails.TargetAppNodes, _ = strconv.ParseBool(Getenv("TARGET_APP_NODES", "true"))
+	experimentDetails.ExcludeControlPlaneNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_CONTROL_PLANE_NODES", "true"))
+	experimentDetails.ExcludeNotReadyNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_NOT_READY_NODES", "true"))
+	experimentDetails.ExcludeCordonedNodes, _ = strconv.ParseBool(Getenv("EXCLUDE_CORDONED_NODES", "true"))
 	experimentDetails.TargetContainer = Getenv("TARGET_CONTAINER", "")
 }
 
@@ -57,4 +80,9 @@ func InitialiseChaosVariables(chaosDetails *types.ChaosDetails, experimentDetail
 	chaosDetails.Delay = experimentDetails.Delay
 	chaosDetails.JobCleanupPolicy = Getenv("JOB_CLEANUP_POLICY", "retain")
 	chaosDetails.ProbeImagePullPolicy = experimentDetails.LIBImagePullPolicy
+	chaosDetails.HelperSeccompProfile = Getenv("HELPER_SECCOMP_PROFILE", "")
+	chaosDetails.HelperAppArmorProfile = Getenv("HELPER_APPARMOR_PROFILE", "")
+	chaosDetails.HelperTolerations = Getenv("HELPER_TOLERATIONS", "")
+	chaosDetails.HelperPriorityClass = Getenv("HELPER_PRIORITY_CLASS", "")
+	chaosDetails.HelperLogTailLines, _ = strconv.Atoi(Getenv("HELPER_LOG_TAIL_LINES", "50"))
 }
@@ -1,36 +1,54 @@
 package types
 
 import (
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
 	corev1 "k8s.io/api/core/v1"
 	clientTypes "k8s.io/apimachinery/pkg/types"
 )
 
 // ExperimentDetails is for collecting all the experiment-related details
 type ExperimentDetails struct {
-	ExperimentName     string
-	EngineName         string
-	ChaosDuration      int
-	Annotations        map[string]string
-	RampTime           int
-	ChaosLib           string
-	AppNS              string
-	AppLabel           string
-	AppKind            string
-	ChaosUID           clientTypes.UID
-	InstanceID         string
-	ChaosNamespace     string
-	ChaosPodName       string
-	RunID              string
-	LIBImage           string
-	LIBImagePullPolicy string
-	AuxiliaryAppInfo   string
-	Timeout            int
-	Delay              int
-	SSHUser            string
-	RebootCommand      string
-	TargetNode         string
-	TargetNodeIP       string
-	Resources          corev1.ResourceRequirements
-	ImagePullSecrets   []corev1.LocalObjectReference
-	TargetContainer    string
+	ExperimentName          string
+	EngineName              string
+	ChaosDuration           int
+	Annotations             map[string]string
+	RampTime                int
+	ChaosLib                string
+	AppNS                   string
+	AppLabel                string
+	AppKind                 string
+	ChaosUID                clientTypes.UID
+	InstanceID              string
+	ChaosNamespace          string
+	ChaosPodName            string
+	LIBImage                string
+	LIBImagePullPolicy      string
+	AuxiliaryAppInfo        string
+	Timeout                 int
+	Delay                   int
+	SSHUser                 string
+	RebootCommand           string
+	RebootMethod            string
+	VerifyNodeNotReady      bool
+	CloudProvider           string
+	Region                  string
+	AssumeRoleARN           string
+	ExternalID              string
+	AWSEndpointURL          string
+	GCPProjectID            string
+	GCPCredentialsFile      string
+	GCPEndpointURL          string
+	AzureSubscriptionID     string
+	AzureTenantID           string
+	AzureClientID           string
+	AzureClientSecret       string
+	AzureFederatedTokenFile string
+	AzureAuthFile           string
+	AzureEndpointURL        string
+	TargetNode              string
+	TargetNodeIP            string
+	common.NodeTargetDetails
+	Resources        corev1.ResourceRequirements
+	ImagePullSecrets []corev1.LocalObjectReference
+	TargetContainer  string
 }
@@ -29,6 +29,8 @@ func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
 	experimentDetails.Delay, _ = strconv.Atoi(Getenv("STATUS_CHECK_DELAY", "2"))
 	experimentDetails.Timeout, _ = strconv.Atoi(Getenv("STATUS_CHECK_TIMEOUT", "180"))
 	experimentDetails.TargetContainer = Getenv("TARGET_CONTAINER", "")
+	experimentDetails.WaitForNodes, _ = strconv.ParseBool(Getenv("WAIT_FOR_NODES", "false"))
+	experimentDetails.NodeProvisionTimeout, _ = strconv.Atoi(Getenv("NODE_PROVISION_TIMEOUT", "600"))
 }
 
 // Getenv fetch the env and set the default value, if any
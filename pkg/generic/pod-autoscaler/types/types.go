@@ -1,35 +1,79 @@
 package types
 
 import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	clientTypes "k8s.io/apimachinery/pkg/types"
 )
 
 // ExperimentDetails is for collecting all the experiment-related details
 type ExperimentDetails struct {
-	ExperimentName      string
-	EngineName          string
-	ChaosDuration       int
-	RampTime            int
-	Replicas            int
-	ChaosLib            string
-	AppNS               string
-	AppLabel            string
-	AppKind             string
-	AppAffectPercentage int
-	ChaosUID            clientTypes.UID
-	InstanceID          string
-	ChaosNamespace      string
-	ChaosPodName        string
-	RunID               string
-	AuxiliaryAppInfo    string
-	Timeout             int
-	Delay               int
-	LIBImagePullPolicy  string
-	TargetContainer     string
+	ExperimentName       string
+	EngineName           string
+	ChaosDuration        int
+	RampTime             int
+	Replicas             int
+	ChaosLib             string
+	AppNS                string
+	AppLabel             string
+	AppKind              string
+	AppAffectPercentage  int
+	ChaosUID             clientTypes.UID
+	InstanceID           string
+	ChaosNamespace       string
+	ChaosPodName         string
+	RunID                string
+	AuxiliaryAppInfo     string
+	Timeout              int
+	Delay                int
+	LIBImagePullPolicy   string
+	TargetContainer      string
+	WaitForNodes         bool
+	NodeProvisionTimeout int
 }
 
-// ApplicationUnderTest contains the name of the deployment object and the current replica count
+// ApplicationUnderTest contains the name of the application and its replica count before the chaos
 type ApplicationUnderTest struct {
 	AppName      string
 	ReplicaCount int
+	// PodSelector is the label selector of the pods of the application
+	PodSelector string
+	// HPA contains the details of the horizontal pod autoscaler of the application, if any
+	HPA *HPADetails
+}
+
+// ScalableResource contains the resource and the kind of the applications, which expose the scale subresource
+type ScalableResource struct {
+	GVR  schema.GroupVersionResource
+	Kind string
+}
+
+// HPADetails contains the name and the initial replica bounds of the horizontal pod autoscaler
+type HPADetails struct {
+	Name        string
+	MinReplicas *int32
+	MaxReplicas int32
+}
+
+// ScaleMetrics contains the scheduling outcome of the replicas of the application during the chaos
+type ScaleMetrics struct {
+	InitialReplicas    int            `json:"initialReplicas"`
+	TargetReplicas     int            `json:"targetReplicas"`
+	CreatedReplicas    int            `json:"createdReplicas"`
+	ScheduledReplicas  int            `json:"scheduledReplicas"`
+	ReadyReplicas      int            `json:"readyReplicas"`
+	PendingReplicas    int            `json:"pendingReplicas"`
+	PendingReasons     map[string]int `json:"pendingReasons,omitempty"`
+	FailedCreateReason string         `json:"failedCreateReason,omitempty"`
+	AvgTimeToScheduled float64        `json:"avgTimeToScheduledSeconds,omitempty"`
+	MaxTimeToScheduled float64        `json:"maxTimeToScheduledSeconds,omitempty"`
+	AvgTimeToReady     float64        `json:"avgTimeToReadySeconds,omitempty"`
+	MaxTimeToReady     float64        `json:"maxTimeToReadySeconds,omitempty"`
+	TimeToScaleDown    float64        `json:"timeToScaleDownSeconds,omitempty"`
+}
+
+// NodeMetrics contains the nodes provisioned during the chaos, by the cluster autoscaler
+type NodeMetrics struct {
+	InitialNodes    int     `json:"initialNodes"`
+	NodesAdded      int     `json:"nodesAdded"`
+	TimeToNodeReady float64 `json:"timeToNodeReadySeconds,omitempty"`
 }
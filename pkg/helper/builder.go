@@ -0,0 +1,307 @@
+package helper
+
+import (
+	"sort"
+
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientTypes "k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// seccompPodAnnotation is the annotation, containing the seccomp profile of all the containers of the pod
+	seccompPodAnnotation = "seccomp.security.alpha.kubernetes.io/pod"
+	// appArmorAnnotationPrefix is the prefix of the annotation, containing the apparmor profile of a container
+	appArmorAnnotationPrefix = "container.apparmor.security.beta.kubernetes.io/"
+)
+
+// Meta contains the attributes shared by all the helper pods of an experiment
+type Meta struct {
+	ExperimentName  string
+	Namespace       string
+	ChaosUID        clientTypes.UID
+	LabelSuffix     string
+	Image           string
+	ImagePullPolicy string
+	// ContainerName is the name of the helper container, defaults to the experiment name
+	ContainerName string
+}
+
+// Option customise the helper pod
+type Option func(*apiv1.Pod)
+
+// NewPod returns the helper pod with the given runID, carrying the labels shared by all the helpers
+// the options are applied in order on top of the defaults
+func NewPod(meta Meta, runID string, opts ...Option) *apiv1.Pod {
+
+	containerName := meta.ContainerName
+	if containerName == "" {
+		containerName = meta.ExperimentName
+	}
+
+	pod := &apiv1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      meta.ExperimentName + "-" + runID,
+			Namespace: meta.Namespace,
+			Labels: map[string]string{
+				"app":                       meta.ExperimentName + "-helper-" + meta.LabelSuffix,
+				"name":                      meta.ExperimentName + "-" + runID,
+				"chaosUID":                  string(meta.ChaosUID),
+				"app.kubernetes.io/part-of": "litmus",
+			},
+		},
+		Spec: apiv1.PodSpec{
+			RestartPolicy: apiv1.RestartPolicyNever,
+			Containers: []apiv1.Container{
+				{
+					Name:            containerName,
+					Image:           meta.Image,
+					ImagePullPolicy: apiv1.PullPolicy(meta.ImagePullPolicy),
+					// the tail of the logs is used as termination message, if the helper fails without writing one
+					TerminationMessagePolicy: apiv1.TerminationMessageFallbackToLogsOnError,
+				},
+			},
+		},
+	}
+
+	for _, opt := range opts {
+		opt(pod)
+	}
+	return pod
+}
+
+// WithNodeName pins the helper pod to the given node
+func WithNodeName(nodeName string) Option {
+	return func(pod *apiv1.Pod) {
+		pod.Spec.NodeName = nodeName
+	}
+}
+
+// WithHostPID runs the helper pod in the pid namespace of the node
+func WithHostPID() Option {
+	return func(pod *apiv1.Pod) {
+		pod.Spec.HostPID = true
+	}
+}
+
+// WithHostNetwork runs the helper pod in the network namespace of the node
+func WithHostNetwork() Option {
+	return func(pod *apiv1.Pod) {
+		pod.Spec.HostNetwork = true
+	}
+}
+
+// WithPrivileged runs the helper container in the privileged mode
+func WithPrivileged() Option {
+	return func(pod *apiv1.Pod) {
+		privileged := true
+		securityContext(pod).Privileged = &privileged
+	}
+}
+
+// WithCapabilities adds the linux capabilities to the helper container
+func WithCapabilities(capabilities ...apiv1.Capability) Option {
+	return func(pod *apiv1.Pod) {
+		sc := securityContext(pod)
+		if sc.Capabilities == nil {
+			sc.Capabilities = &apiv1.Capabilities{}
+		}
+		sc.Capabilities.Add = append(sc.Capabilities.Add, capabilities...)
+	}
+}
+
+// WithRunAsUser runs the helper container with the given user id
+func WithRunAsUser(uid int64) Option {
+	return func(pod *apiv1.Pod) {
+		securityContext(pod).RunAsUser = &uid
+	}
+}
+
+// WithCommand sets the command and the arguments of the helper container
+func WithCommand(command []string, args ...string) Option {
+	return func(pod *apiv1.Pod) {
+		pod.Spec.Containers[0].Command = command
+		pod.Spec.Containers[0].Args = args
+	}
+}
+
+// WithEnv adds the env to the helper container, in the sorted order of the names
+func WithEnv(env map[string]string) Option {
+	return func(pod *apiv1.Pod) {
+		names := make([]string, 0, len(env))
+		for name := range env {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, apiv1.EnvVar{Name: name, Value: env[name]})
+		}
+	}
+}
+
+// WithFieldRefEnv adds the env, derived from the given field of the helper pod via downward api
+func WithFieldRefEnv(name, fieldPath string) Option {
+	return func(pod *apiv1.Pod) {
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, apiv1.EnvVar{
+			Name: name,
+			ValueFrom: &apiv1.EnvVarSource{
+				FieldRef: &apiv1.ObjectFieldSelector{
+					APIVersion: "v1",
+					FieldPath:  fieldPath,
+				},
+			},
+		})
+	}
+}
+
+// WithPodNameEnv adds the POD_NAME env, containing the name of the helper pod
+func WithPodNameEnv() Option {
+	return WithFieldRefEnv("POD_NAME", "metadata.name")
+}
+
+// WithVolume adds the volume to the helper pod and mounts it inside all its containers
+func WithVolume(volume apiv1.Volume, mountPath string, propagation *apiv1.MountPropagationMode) Option {
+	return func(pod *apiv1.Pod) {
+		pod.Spec.Volumes = append(pod.Spec.Volumes, volume)
+		mount := apiv1.VolumeMount{
+			Name:             volume.Name,
+			MountPath:        mountPath,
+			MountPropagation: propagation,
+		}
+		for i := range pod.Spec.InitContainers {
+			pod.Spec.InitContainers[i].VolumeMounts = append(pod.Spec.InitContainers[i].VolumeMounts, mount)
+		}
+		for i := range pod.Spec.Containers {
+			pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, mount)
+		}
+	}
+}
+
+// WithHostPath mounts the path of the node inside all the containers of the helper pod
+func WithHostPath(name, hostPath, mountPath string, propagation *apiv1.MountPropagationMode) Option {
+	return WithVolume(apiv1.Volume{
+		Name: name,
+		VolumeSource: apiv1.VolumeSource{
+			HostPath: &apiv1.HostPathVolumeSource{
+				Path: hostPath,
+			},
+		},
+	}, mountPath, propagation)
+}
+
+// WithSocketMount mounts the container runtime socket of the node at the same path inside the helper pod
+func WithSocketMount(socketPath string) Option {
+	return WithHostPath("cri-socket", socketPath, socketPath, nil)
+}
+
+// WithInitContainer adds an init container running the given command, with the image & mounts of the helper container
+func WithInitContainer(name string, command ...string) Option {
+	return func(pod *apiv1.Pod) {
+		helper := pod.Spec.Containers[0]
+		pod.Spec.InitContainers = append(pod.Spec.InitContainers, apiv1.Container{
+			Name:            name,
+			Image:           helper.Image,
+			ImagePullPolicy: helper.ImagePullPolicy,
+			Command:         command,
+			VolumeMounts:    append([]apiv1.VolumeMount{}, helper.VolumeMounts...),
+		})
+	}
+}
+
+// WithAffinity sets the affinity of the helper pod, it is used by the helpers which are not pinned to a node
+func WithAffinity(affinity *apiv1.Affinity) Option {
+	return func(pod *apiv1.Pod) {
+		pod.Spec.Affinity = affinity
+	}
+}
+
+// WithResources sets the resource requirements of the helper container
+func WithResources(resources apiv1.ResourceRequirements) Option {
+	return func(pod *apiv1.Pod) {
+		pod.Spec.Containers[0].Resources = resources
+	}
+}
+
+// WithLabels adds the labels to the helper pod
+func WithLabels(labels map[string]string) Option {
+	return func(pod *apiv1.Pod) {
+		for key, value := range labels {
+			pod.Labels[key] = value
+		}
+	}
+}
+
+// WithAnnotations adds the annotations to the helper pod
+func WithAnnotations(annotations map[string]string) Option {
+	return func(pod *apiv1.Pod) {
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		for key, value := range annotations {
+			pod.Annotations[key] = value
+		}
+	}
+}
+
+// WithSeccompProfile runs all the containers of the helper pod with the given seccomp profile
+// the profile is one of runtime/default, unconfined or localhost/<path>
+func WithSeccompProfile(profile string) Option {
+	return WithAnnotations(map[string]string{seccompPodAnnotation: profile})
+}
+
+// WithAppArmorProfile runs all the containers of the helper pod with the given apparmor profile
+// the profile is one of runtime/default, unconfined or localhost/<name>, it should be applied after the init containers are added
+func WithAppArmorProfile(profile string) Option {
+	return func(pod *apiv1.Pod) {
+		annotations := map[string]string{}
+		for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+			annotations[appArmorAnnotationPrefix+container.Name] = profile
+		}
+		WithAnnotations(annotations)(pod)
+	}
+}
+
+// WithImagePullSecrets sets the image pull secrets of the helper pod
+func WithImagePullSecrets(secrets []apiv1.LocalObjectReference) Option {
+	return func(pod *apiv1.Pod) {
+		pod.Spec.ImagePullSecrets = secrets
+	}
+}
+
+// WithServiceAccount runs the helper pod with the given service account
+func WithServiceAccount(serviceAccount string) Option {
+	return func(pod *apiv1.Pod) {
+		pod.Spec.ServiceAccountName = serviceAccount
+	}
+}
+
+// WithTerminationGracePeriod sets the termination grace period of the helper pod
+// the helpers use it to revert the chaos on abort
+func WithTerminationGracePeriod(seconds int) Option {
+	return func(pod *apiv1.Pod) {
+		gracePeriod := int64(seconds)
+		pod.Spec.TerminationGracePeriodSeconds = &gracePeriod
+	}
+}
+
+// WithTolerations adds the tolerations to the helper pod
+func WithTolerations(tolerations []apiv1.Toleration) Option {
+	return func(pod *apiv1.Pod) {
+		pod.Spec.Tolerations = append(pod.Spec.Tolerations, tolerations...)
+	}
+}
+
+// WithPriorityClass runs the helper pod with the given priority class
+func WithPriorityClass(priorityClassName string) Option {
+	return func(pod *apiv1.Pod) {
+		pod.Spec.PriorityClassName = priorityClassName
+	}
+}
+
+// securityContext returns the security context of the helper container, creating it if not present
+func securityContext(pod *apiv1.Pod) *apiv1.SecurityContext {
+	if pod.Spec.Containers[0].SecurityContext == nil {
+		pod.Spec.Containers[0].SecurityContext = &apiv1.SecurityContext{}
+	}
+	return pod.Spec.Containers[0].SecurityContext
+}
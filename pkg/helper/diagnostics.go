@@ -0,0 +1,208 @@
+package helper
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Diagnostics contains the details of a failed helper pod, collected before the helper is cleaned up
+type Diagnostics struct {
+	Pod                string              `json:"pod"`
+	Node               string              `json:"node,omitempty"`
+	Phase              string              `json:"phase,omitempty"`
+	Reason             string              `json:"reason,omitempty"`
+	ExitCode           *int32              `json:"exitCode,omitempty"`
+	TerminationMessage string              `json:"terminationMessage,omitempty"`
+	Termination        *TerminationMessage `json:"termination,omitempty"`
+	Logs               string              `json:"logs,omitempty"`
+	Events             []string            `json:"events,omitempty"`
+}
+
+// summary returns the one line summary of the diagnostics
+func (d Diagnostics) summary() string {
+
+	summary := d.Pod + " helper pod"
+	if d.ExitCode != nil {
+		summary += fmt.Sprintf(" exited with %v code", *d.ExitCode)
+	} else {
+		summary += " is in " + d.Phase + " phase"
+	}
+	switch {
+	case d.Termination != nil && d.Termination.Error != "":
+		summary += ", err: " + d.Termination.Error
+	case d.Reason != "":
+		summary += ", reason: " + d.Reason
+	}
+	return summary
+}
+
+// diagnosticsName returns the name of the configmap containing the diagnostics of the failed helper pods
+func (m *Manager) diagnosticsName() string {
+	return m.Meta.ExperimentName + "-helper-" + m.Meta.LabelSuffix + "-diagnostics"
+}
+
+// reportFailure collects the diagnostics of the failed helper pods in the current batch
+// the diagnostics are printed in the experiment logs, stored in a configmap and summarised in the chaos details for the chaosresult
+func (m *Manager) reportFailure() {
+
+	diagnostics, err := m.collectDiagnostics()
+	if err != nil {
+		log.Warnf("Unable to collect the diagnostics of the helper pods, err: %v", err)
+		return
+	}
+	if len(diagnostics) == 0 {
+		return
+	}
+
+	var summaries []string
+	for _, d := range diagnostics {
+		summaries = append(summaries, d.summary())
+		log.Errorf("[Diagnostics]: %v", d.summary())
+		if d.Termination != nil {
+			log.Errorf("[Diagnostics]: %v helper pod injected: [%v], reverted: [%v], aborted: %v", d.Pod, strings.Join(d.Termination.Injected, ", "), strings.Join(d.Termination.Reverted, ", "), d.Termination.Aborted)
+		}
+		for _, event := range d.Events {
+			log.Errorf("[Diagnostics]: %v helper pod event: %v", d.Pod, event)
+		}
+		for _, line := range strings.Split(strings.TrimSpace(d.Logs), "\n") {
+			if line != "" {
+				log.Errorf("[Diagnostics]: [%v]: %v", d.Pod, line)
+			}
+		}
+	}
+
+	m.chaosDetails.HelperFailure = strings.Join(summaries, "; ")
+	if err := m.storeDiagnostics(diagnostics); err != nil {
+		log.Warnf("Unable to store the diagnostics of the helper pods, err: %v", err)
+		return
+	}
+	m.chaosDetails.HelperFailure += ", diagnostics: " + m.diagnosticsName() + " configmap"
+}
+
+// collectDiagnostics returns the diagnostics of the helper pods in the current batch, which are not succeeded
+func (m *Manager) collectDiagnostics() ([]Diagnostics, error) {
+
+	podList, err := m.clients.KubeClient.CoreV1().Pods(m.Meta.Namespace).List(v1.ListOptions{LabelSelector: m.batchLabel()})
+	if err != nil {
+		return nil, errors.Errorf("Unable to list the helper pods, err: %v", err)
+	}
+
+	var diagnostics []Diagnostics
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == apiv1.PodSucceeded {
+			continue
+		}
+		d := Diagnostics{
+			Pod:    pod.Name,
+			Node:   pod.Spec.NodeName,
+			Phase:  string(pod.Status.Phase),
+			Reason: strings.TrimSpace(pod.Status.Reason + " " + pod.Status.Message),
+			Events: m.podEvents(pod.Name),
+			Logs:   m.podLogs(pod.Name),
+		}
+		for _, container := range pod.Status.ContainerStatuses {
+			if container.Name != m.Meta.ContainerName {
+				continue
+			}
+			state := container.State.Terminated
+			if state == nil {
+				state = container.LastTerminationState.Terminated
+			}
+			if state == nil {
+				if container.State.Waiting != nil {
+					d.Reason = strings.TrimSpace(container.State.Waiting.Reason + " " + container.State.Waiting.Message)
+				}
+				continue
+			}
+			exitCode := state.ExitCode
+			d.ExitCode = &exitCode
+			d.Reason = strings.TrimSpace(state.Reason + " " + d.Reason)
+			d.TerminationMessage = state.Message
+			if termination, ok := ParseTerminationMessage(state.Message); ok {
+				d.Termination = &termination
+			}
+		}
+		if d.ExitCode != nil && *d.ExitCode == 0 {
+			continue
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	return diagnostics, nil
+}
+
+// podLogs returns the tail of the logs of the helper container
+func (m *Manager) podLogs(name string) string {
+
+	tailLines := int64(m.chaosDetails.HelperLogTailLines)
+	if tailLines <= 0 {
+		return ""
+	}
+	logs, err := m.clients.KubeClient.CoreV1().Pods(m.Meta.Namespace).GetLogs(name, &apiv1.PodLogOptions{Container: m.Meta.ContainerName, TailLines: &tailLines}).DoRaw()
+	if err != nil {
+		log.Warnf("Unable to get the logs of %v helper pod, err: %v", name, err)
+		return ""
+	}
+	return string(logs)
+}
+
+// podEvents returns the events of the helper pod, in the reason: message format
+func (m *Manager) podEvents(name string) []string {
+
+	eventList, err := m.clients.KubeClient.CoreV1().Events(m.Meta.Namespace).List(v1.ListOptions{FieldSelector: "involvedObject.kind=Pod,involvedObject.name=" + name})
+	if err != nil {
+		log.Warnf("Unable to get the events of %v helper pod, err: %v", name, err)
+		return nil
+	}
+	var events []string
+	for _, event := range eventList.Items {
+		events = append(events, event.Type+" "+event.Reason+": "+event.Message)
+	}
+	return events
+}
+
+// storeDiagnostics stores the diagnostics in a configmap, keyed by the helper pod name
+// the configmap outlives the helper pods, which may be deleted based on the JobCleanupPolicy
+func (m *Manager) storeDiagnostics(diagnostics []Diagnostics) error {
+
+	data := map[string]string{}
+	for _, d := range diagnostics {
+		value, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return err
+		}
+		data[d.Pod] = string(value)
+	}
+
+	configMaps := m.clients.KubeClient.CoreV1().ConfigMaps(m.Meta.Namespace)
+	configMap, err := configMaps.Get(m.diagnosticsName(), v1.GetOptions{})
+	switch {
+	case k8serrors.IsNotFound(err):
+		_, err = configMaps.Create(&apiv1.ConfigMap{
+			ObjectMeta: v1.ObjectMeta{
+				Name:      m.diagnosticsName(),
+				Namespace: m.Meta.Namespace,
+				Labels: map[string]string{
+					"chaosUID":                  string(m.Meta.ChaosUID),
+					"app.kubernetes.io/part-of": "litmus",
+				},
+			},
+			Data: data,
+		})
+	case err == nil:
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		for key, value := range data {
+			configMap.Data[key] = value
+		}
+		_, err = configMaps.Update(configMap)
+	}
+	return err
+}
@@ -0,0 +1,459 @@
+package helper
+
+import (
+	"bufio"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/status"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/litmuschaos/litmus-go/pkg/utils/common"
+	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// logDrainTimeout is the time to wait for the log streams, once the helpers are completed
+const logDrainTimeout = 10 * time.Second
+
+// Manager creates the helper pods of an experiment and manages their lifecycle
+// the helpers created since the last completion form the current batch, which is awaited & cleaned up together
+type Manager struct {
+	Meta         Meta
+	clients      clients.ClientSets
+	chaosDetails *types.ChaosDetails
+	batch        []string
+	exitCodes    map[string]int32
+	streams      sync.WaitGroup
+	// created contains the helper pods of the current batch as created, to report the lost helpers once they are deleted
+	created map[string]*apiv1.Pod
+	// podSecurity contains the enforced & the warned Pod Security levels of the namespace, once looked up
+	podSecurity []string
+	// scheduling contains the scheduling options of the helpers, once derived
+	scheduling    []Option
+	schedulingErr error
+}
+
+// NewManager returns the manager of the helper pods, all the helpers share the label suffix of the manager
+func NewManager(meta Meta, clients clients.ClientSets, chaosDetails *types.ChaosDetails) *Manager {
+
+	if meta.LabelSuffix == "" {
+		meta.LabelSuffix = common.GetRunID()
+	}
+	if meta.ContainerName == "" {
+		meta.ContainerName = meta.ExperimentName
+	}
+	return &Manager{
+		Meta:         meta,
+		clients:      clients,
+		chaosDetails: chaosDetails,
+		exitCodes:    map[string]int32{},
+		created:      map[string]*apiv1.Pod{},
+	}
+}
+
+// Label returns the label selector of all the helper pods of the manager
+func (m *Manager) Label() string {
+	return "app=" + m.Meta.ExperimentName + "-helper-" + m.Meta.LabelSuffix
+}
+
+// batchLabel returns the label selector of the helper pods in the current batch
+func (m *Manager) batchLabel() string {
+	return "name in (" + strings.Join(m.batch, ",") + ")"
+}
+
+// NewPod returns a helper pod with a new runID, carrying the labels of the manager
+// the scheduling options and the seccomp & apparmor profiles of the experiment are applied after the given options
+func (m *Manager) NewPod(opts ...Option) *apiv1.Pod {
+
+	opts = append(opts, m.schedulingOptions()...)
+	if m.chaosDetails.HelperSeccompProfile != "" {
+		opts = append(opts, WithSeccompProfile(m.chaosDetails.HelperSeccompProfile))
+	}
+	if m.chaosDetails.HelperAppArmorProfile != "" {
+		opts = append(opts, WithAppArmorProfile(m.chaosDetails.HelperAppArmorProfile))
+	}
+	return NewPod(m.Meta, common.GetRunID(), opts...)
+}
+
+// Create creates the helper pod and adds it to the current batch
+// the helper pod is verified against the Pod Security level of the namespace before the creation
+// the rejection by the Pod Security admission is reported with the violations, if the levels couldn't be checked upfront
+func (m *Manager) Create(pod *apiv1.Pod) error {
+
+	if m.schedulingErr != nil {
+		return m.schedulingErr
+	}
+	if err := m.checkPodSecurity(pod); err != nil {
+		return err
+	}
+	if _, err := m.clients.KubeClient.CoreV1().Pods(m.Meta.Namespace).Create(pod); err != nil {
+		if strings.Contains(err.Error(), podSecurityRejection) {
+			return errors.Errorf("%v helper pod is rejected by the Pod Security admission of %v namespace, baseline violations: [%v], err: %v", pod.Name, m.Meta.Namespace, strings.Join(PodSecurityViolations(pod, PodSecurityBaseline), ", "), err)
+		}
+		return errors.Errorf("Unable to create the helper pod, err: %v", err)
+	}
+	m.batch = append(m.batch, pod.Name)
+	m.created[pod.Name] = pod
+	return nil
+}
+
+// schedulingOptions returns the tolerations, node selector & priority class of the helpers
+// the tolerations & node selector of the experiment pod are inherited, so that the helpers can run wherever the experiment runs
+func (m *Manager) schedulingOptions() []Option {
+
+	if m.scheduling != nil {
+		return m.scheduling
+	}
+	m.scheduling = []Option{}
+
+	tolerations, err := ParseTolerations(m.chaosDetails.HelperTolerations)
+	if err != nil {
+		m.schedulingErr = errors.Errorf("Unable to parse the helper tolerations, err: %v", err)
+	}
+	if m.chaosDetails.ChaosPodName != "" {
+		chaosPod, err := m.clients.KubeClient.CoreV1().Pods(m.Meta.Namespace).Get(m.chaosDetails.ChaosPodName, v1.GetOptions{})
+		if err != nil {
+			log.Warnf("Unable to inherit the tolerations & node selector of the experiment pod, err: %v", err)
+		} else {
+			tolerations = append(tolerations, chaosPod.Spec.Tolerations...)
+			m.scheduling = append(m.scheduling, WithNodeSelector(chaosPod.Spec.NodeSelector))
+		}
+	}
+	if len(tolerations) != 0 {
+		m.scheduling = append(m.scheduling, WithTolerations(tolerations))
+	}
+	if m.chaosDetails.HelperPriorityClass != "" {
+		m.scheduling = append(m.scheduling, WithPriorityClass(m.chaosDetails.HelperPriorityClass))
+	}
+	return m.scheduling
+}
+
+// checkPodSecurity verifies the helper pod against the Pod Security levels of the namespace
+// the check is skipped, if the levels can't be derived
+func (m *Manager) checkPodSecurity(pod *apiv1.Pod) error {
+
+	if m.podSecurity == nil {
+		enforce, warn, err := GetPodSecurityLevels(m.Meta.Namespace, m.clients)
+		if err != nil {
+			log.Warnf("Skipping the Pod Security check of the helper pods, the experiment needs the get permission on the namespaces, err: %v", err)
+		}
+		m.podSecurity = []string{enforce, warn}
+	}
+	return CheckPodSecurity(pod, m.podSecurity[0], m.podSecurity[1])
+}
+
+// delayUnit returns the unit of the timeout, the delay & the chaos duration, a second unless set in the chaos details
+func (m *Manager) delayUnit() time.Duration {
+
+	if m.chaosDetails.DelayUnit == 0 {
+		return time.Second
+	}
+	return m.chaosDetails.DelayUnit
+}
+
+// WaitForRunning waits till the helper pods of the current batch comes to running state
+// the helpers which are already completed are accepted, their status is verified on completion
+func (m *Manager) WaitForRunning() error {
+
+	log.Info("[Status]: Checking the status of the helper pods")
+	err := retry.
+		Times(uint(m.chaosDetails.Timeout / m.chaosDetails.Delay)).
+		Wait(time.Duration(m.chaosDetails.Delay) * m.delayUnit()).
+		Try(func(attempt uint) error {
+			podList, err := m.clients.KubeClient.CoreV1().Pods(m.Meta.Namespace).List(v1.ListOptions{LabelSelector: m.batchLabel()})
+			if err != nil || len(podList.Items) != len(m.batch) {
+				return errors.Errorf("Unable to find the helper pods, err: %v", err)
+			}
+			for _, pod := range podList.Items {
+				// the node of the unpinned helpers is known once they are scheduled
+				if created, ok := m.created[pod.Name]; ok && pod.Spec.NodeName != "" {
+					created.Spec.NodeName = pod.Spec.NodeName
+				}
+				if !isStarted(pod, m.Meta.ContainerName) {
+					return errors.Errorf("%v helper pod is not yet in running state, phase: %v", pod.Name, pod.Status.Phase)
+				}
+				log.InfoWithValues("[Status]: The status of the helper pod is as follows", logrus.Fields{
+					"Pod": pod.Name, "Status": pod.Status.Phase})
+			}
+			return nil
+		})
+	if err != nil {
+		m.reportFailure()
+		return errors.Errorf("helper pods are not in running state, err: %v", err)
+	}
+	return nil
+}
+
+// isStarted checks whether the helper container of the pod is either running & ready or already terminated
+func isStarted(pod apiv1.Pod, containerName string) bool {
+
+	switch pod.Status.Phase {
+	case apiv1.PodSucceeded, apiv1.PodFailed:
+		return true
+	case apiv1.PodRunning:
+		for _, container := range pod.Status.ContainerStatuses {
+			if container.Name == containerName {
+				return container.Ready || container.State.Terminated != nil
+			}
+		}
+	}
+	return false
+}
+
+// StreamLogs follows the logs of the helper pods of the current batch and prints them in the experiment logs
+func (m *Manager) StreamLogs() {
+
+	for _, name := range m.batch {
+		stream, err := m.clients.KubeClient.CoreV1().Pods(m.Meta.Namespace).GetLogs(name, &apiv1.PodLogOptions{Container: m.Meta.ContainerName, Follow: true}).Stream()
+		if err != nil {
+			log.Warnf("Unable to stream the logs of %v helper pod, err: %v", name, err)
+			continue
+		}
+		m.streams.Add(1)
+		go func(name string) {
+			defer m.streams.Done()
+			defer stream.Close()
+			scanner := bufio.NewScanner(stream)
+			for scanner.Scan() {
+				log.Infof("[%v]: %v", name, scanner.Text())
+			}
+		}(name)
+	}
+}
+
+// WaitForCompletion waits till the completion of the helper pods of the current batch, for an upper limit of the given duration
+// it collects the exit codes of the helpers and returns an error if any of the helpers failed
+// the diagnostics of the failed helpers are reported before returning the error
+func (m *Manager) WaitForCompletion(duration int) error {
+
+	podStatus, err := status.WaitForCompletion(m.Meta.Namespace, m.batchLabel(), m.clients, duration, m.delayUnit(), m.Meta.ContainerName)
+	m.drainLogs()
+	if lostErr := m.checkLostHelpers(); lostErr != nil {
+		err = lostErr
+	}
+	if exitErr := m.collectExitCodes(); err == nil {
+		err = exitErr
+	}
+	if err != nil || podStatus == "Failed" {
+		m.reportFailure()
+		return errors.Errorf("helper pod failed, err: %v", err)
+	}
+	return nil
+}
+
+// drainLogs waits for the log streams to end, for an upper limit of logDrainTimeout
+func (m *Manager) drainLogs() {
+
+	done := make(chan struct{})
+	go func() {
+		m.streams.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(logDrainTimeout):
+		log.Warn("[Wait]: Timed out while reading the logs of the helper pods")
+	}
+}
+
+// checkLostHelpers verifies that none of the helper pods in the current batch is deleted, evicted or failed before the completion
+// the chaos which may be left unreverted by the lost helpers is recorded in the chaos details, to be reported in the chaosresult
+func (m *Manager) checkLostHelpers() error {
+
+	podList, err := m.clients.KubeClient.CoreV1().Pods(m.Meta.Namespace).List(v1.ListOptions{LabelSelector: m.batchLabel()})
+	if err != nil {
+		return errors.Errorf("Unable to list the helper pods, err: %v", err)
+	}
+	found := map[string]bool{}
+	var lost, pendingReverts []string
+	for _, pod := range podList.Items {
+		found[pod.Name] = true
+		if reason := lostReason(pod, m.Meta.ContainerName); reason != "" {
+			lost = append(lost, pod.Name+" is "+reason)
+			if revert := m.pendingRevert(pod.Name, &pod); revert != "" {
+				pendingReverts = append(pendingReverts, revert)
+			}
+		}
+	}
+	for _, name := range m.batch {
+		if !found[name] {
+			lost = append(lost, name+" is deleted")
+			pendingReverts = append(pendingReverts, m.pendingRevert(name, nil))
+		}
+	}
+	if len(lost) == 0 {
+		return nil
+	}
+	for _, reason := range lost {
+		log.Errorf("[Status]: The helper pod %v", reason)
+	}
+	for _, revert := range pendingReverts {
+		log.Errorf("[Status]: Revert the chaos manually, %v", revert)
+	}
+	m.chaosDetails.PendingReverts = append(m.chaosDetails.PendingReverts, pendingReverts...)
+	return errors.Errorf("helper pods are lost before the completion, [%v]", strings.Join(lost, ", "))
+}
+
+// pendingRevert describes the chaos which may be left unreverted by the lost helper pod, the pod is nil if it is deleted
+// the chaos injected & reverted by the helper is known from its termination message, it is unknown if the helper is deleted or killed
+// it returns empty, if the helper injected no chaos or reverted it upon the abort
+func (m *Manager) pendingRevert(name string, pod *apiv1.Pod) string {
+
+	created, ok := m.created[name]
+	if !ok {
+		created = pod
+	}
+	node := ""
+	if pod != nil {
+		node = pod.Spec.NodeName
+	}
+	if node == "" && created != nil {
+		node = created.Spec.NodeName
+	}
+	if node == "" {
+		node = "unknown"
+	}
+
+	revert := m.Meta.ExperimentName + " chaos of " + name + " helper pod on " + node + " node"
+	if target := helperTarget(created, m.Meta.ContainerName); target != "" {
+		revert += ", target: " + target
+	}
+	termination, ok := TerminationMessage{}, false
+	if pod != nil {
+		termination, ok = terminationOf(*pod, m.Meta.ContainerName)
+	}
+	switch {
+	case !ok:
+		return revert + ", the injected chaos is unknown"
+	case len(termination.Injected) == 0 || (termination.Aborted && termination.Error == ""):
+		return ""
+	case len(termination.Reverted) == 0:
+		return revert + ", not reverted: [" + strings.Join(termination.Injected, ", ") + "]"
+	default:
+		return revert + ", injected: [" + strings.Join(termination.Injected, ", ") + "], reverted only: [" + strings.Join(termination.Reverted, ", ") + "]"
+	}
+}
+
+// helperTarget returns the target pod of the helper, from the APP_NS & APP_POD env of its container
+// it is empty for the helpers which target the node itself
+func helperTarget(pod *apiv1.Pod, containerName string) string {
+
+	if pod == nil {
+		return ""
+	}
+	env := map[string]string{}
+	for _, container := range pod.Spec.Containers {
+		if container.Name != containerName {
+			continue
+		}
+		for _, e := range container.Env {
+			env[e.Name] = e.Value
+		}
+	}
+	if env["APP_POD"] == "" {
+		return ""
+	}
+	return env["APP_NS"] + "/" + env["APP_POD"] + " pod"
+}
+
+// terminationOf returns the structured termination message of the helper container, if it is terminated
+func terminationOf(pod apiv1.Pod, containerName string) (TerminationMessage, bool) {
+
+	for _, container := range pod.Status.ContainerStatuses {
+		if container.Name == containerName && container.State.Terminated != nil {
+			return ParseTerminationMessage(container.State.Terminated.Message)
+		}
+	}
+	return TerminationMessage{}, false
+}
+
+// collectExitCodes records the exit code of every terminated helper container in the current batch
+func (m *Manager) collectExitCodes() error {
+
+	podList, err := m.clients.KubeClient.CoreV1().Pods(m.Meta.Namespace).List(v1.ListOptions{LabelSelector: m.batchLabel()})
+	if err != nil {
+		return errors.Errorf("Unable to list the helper pods, err: %v", err)
+	}
+	var exitErr error
+	for _, pod := range podList.Items {
+		for _, container := range pod.Status.ContainerStatuses {
+			if container.Name != m.Meta.ContainerName || container.State.Terminated == nil {
+				continue
+			}
+			m.exitCodes[pod.Name] = container.State.Terminated.ExitCode
+			if container.State.Terminated.ExitCode != 0 && exitErr == nil {
+				exitErr = errors.Errorf("%v helper pod exited with %v code, reason: %v", pod.Name, container.State.Terminated.ExitCode, container.State.Terminated.Reason)
+			}
+		}
+	}
+	return exitErr
+}
+
+// ExitCodes returns the exit codes of the completed helper pods, keyed by the pod name
+func (m *Manager) ExitCodes() map[string]int32 {
+
+	exitCodes := map[string]int32{}
+	for name, code := range m.exitCodes {
+		exitCodes[name] = code
+	}
+	return exitCodes
+}
+
+// Delete deletes the helper pods of the current batch, waits till they are terminated and starts a new batch
+func (m *Manager) Delete() error {
+
+	log.Info("[Cleanup]: Deleting the helper pods")
+	pods := m.clients.KubeClient.CoreV1().Pods(m.Meta.Namespace)
+	if err := pods.DeleteCollection(&v1.DeleteOptions{}, v1.ListOptions{LabelSelector: m.batchLabel()}); err != nil {
+		return errors.Errorf("Unable to delete the helper pods, err: %v", err)
+	}
+	err := retry.
+		Times(uint(m.chaosDetails.Timeout / m.chaosDetails.Delay)).
+		Wait(time.Duration(m.chaosDetails.Delay) * m.delayUnit()).
+		Try(func(attempt uint) error {
+			podList, err := pods.List(v1.ListOptions{LabelSelector: m.batchLabel()})
+			if err != nil || len(podList.Items) != 0 {
+				return errors.Errorf("helper pods are not yet terminated, err: %v", err)
+			}
+			return nil
+		})
+	if err != nil {
+		return errors.Errorf("Unable to delete the helper pods, err: %v", err)
+	}
+	m.batch = nil
+	m.created = map[string]*apiv1.Pod{}
+	return nil
+}
+
+// Cleanup deletes all the helper pods of the manager based on the JobCleanupPolicy
+// it is used to cleanup the helpers after a failure, the helpers are retained for the debugging otherwise
+func (m *Manager) Cleanup() {
+	common.DeleteAllHelperPodBasedOnJobCleanupPolicy(m.Label(), m.chaosDetails, m.clients)
+	m.batch = nil
+	m.created = map[string]*apiv1.Pod{}
+}
+
+// Run waits for the helper pods of the current batch to run & complete within the given duration and deletes them afterwards
+// the helpers are cleaned up based on the JobCleanupPolicy, if any of them failed
+func (m *Manager) Run(duration int) error {
+
+	if len(m.batch) == 0 {
+		return errors.New("no helper pods are created")
+	}
+	if err := m.WaitForRunning(); err != nil {
+		m.Cleanup()
+		return err
+	}
+	m.StreamLogs()
+	if err := m.WaitForCompletion(duration); err != nil {
+		m.Cleanup()
+		return err
+	}
+	return m.Delete()
+}
@@ -0,0 +1,70 @@
+package helper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/litmuschaos/litmus-go/pkg/clients/fake"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCreatePodSecurity(t *testing.T) {
+
+	tests := []struct {
+		labels map[string]string
+		errors map[string]error
+		// wantErr is contained in the error of the creation, the helper pod is created if it is empty
+		wantErr  string
+		testName string
+	}{
+		{
+			labels:   map[string]string{podSecurityEnforceLabel: PodSecurityPrivileged},
+			testName: "privileged namespace",
+		},
+		{
+			labels:   map[string]string{podSecurityWarnLabel: PodSecurityRestricted},
+			testName: "warned namespace",
+		},
+		{
+			labels:   map[string]string{podSecurityEnforceLabel: PodSecurityBaseline},
+			wantErr:  "would be rejected by the baseline Pod Security level of litmus namespace, violations: [hostPID, privileged pod-dns-chaos container]",
+			testName: "baseline namespace",
+		},
+		{
+			errors: map[string]error{
+				"get namespaces": errors.Errorf(`namespaces "litmus" is forbidden`),
+				"create pods":    errors.Errorf(`pods "helper" is forbidden: violates PodSecurity "baseline:latest": host namespaces, privileged`),
+			},
+			wantErr:  "is rejected by the Pod Security admission of litmus namespace, baseline violations: [hostPID, privileged pod-dns-chaos container]",
+			testName: "namespace can't be read",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			namespace := &apiv1.Namespace{ObjectMeta: v1.ObjectMeta{Name: "litmus", Labels: tt.labels}}
+			clients, server, err := fake.NewClientSets(namespace)
+			if err != nil {
+				t.Fatalf("unable to create the fake clients, err: %v", err)
+			}
+			defer server.Close()
+			server.Errors = tt.errors
+
+			m := NewManager(Meta{ExperimentName: "pod-dns-chaos", Namespace: "litmus"}, clients, &types.ChaosDetails{})
+			err = m.Create(m.NewPod(WithHostPID(), WithPrivileged()))
+			created := len(server.Actions) == 1 && strings.HasPrefix(server.Actions[0], "create pods")
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unable to create the helper pod, err: %v", err)
+			case tt.wantErr == "" && !created:
+				t.Errorf("got %v actions, want the creation of the helper pod", server.Actions)
+			case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+				t.Errorf("got %v error, want %v", err, tt.wantErr)
+			case tt.wantErr != "" && created:
+				t.Errorf("got %v actions, want no helper pod", server.Actions)
+			}
+		})
+	}
+}
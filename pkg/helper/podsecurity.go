@@ -0,0 +1,158 @@
+package helper
+
+import (
+	"strings"
+
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// podSecurityEnforceLabel is the namespace label, containing the Pod Security level enforced by the admission
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+	// podSecurityWarnLabel is the namespace label, containing the Pod Security level which only warns on violation
+	podSecurityWarnLabel = "pod-security.kubernetes.io/warn"
+
+	// PodSecurityPrivileged is the unrestricted Pod Security level
+	PodSecurityPrivileged = "privileged"
+	// PodSecurityBaseline is the Pod Security level, which prevents the known privilege escalations
+	PodSecurityBaseline = "baseline"
+	// PodSecurityRestricted is the Pod Security level, which enforces the pod hardening best practices
+	PodSecurityRestricted = "restricted"
+
+	// podSecurityRejection is contained in the error returned by the api server, once the Pod Security admission rejects a pod
+	podSecurityRejection = "violates PodSecurity"
+)
+
+// baselineCapabilities are the capabilities allowed by the baseline level, on top of the runtime defaults
+var baselineCapabilities = map[apiv1.Capability]bool{
+	"AUDIT_WRITE": true, "CHOWN": true, "DAC_OVERRIDE": true, "FOWNER": true, "FSETID": true, "KILL": true, "MKNOD": true,
+	"NET_BIND_SERVICE": true, "SETFCAP": true, "SETGID": true, "SETPCAP": true, "SETUID": true, "SYS_CHROOT": true,
+}
+
+// GetPodSecurityLevels returns the enforced and the warned Pod Security levels of the namespace
+// the levels are empty, if the namespace is not labeled
+func GetPodSecurityLevels(namespace string, clients clients.ClientSets) (string, string, error) {
+
+	ns, err := clients.KubeClient.CoreV1().Namespaces().Get(namespace, v1.GetOptions{})
+	if err != nil {
+		return "", "", errors.Errorf("Unable to get the %v namespace, err: %v", namespace, err)
+	}
+	return ns.Labels[podSecurityEnforceLabel], ns.Labels[podSecurityWarnLabel], nil
+}
+
+// CheckPodSecurity verifies the helper pod against the enforced Pod Security level of its namespace
+// it returns an error, listing the violations, if the admission would reject the helper pod
+// the violations of the warned level are logged only
+func CheckPodSecurity(pod *apiv1.Pod, enforce, warn string) error {
+
+	if violations := PodSecurityViolations(pod, enforce); len(violations) != 0 {
+		return errors.Errorf("%v helper pod would be rejected by the %v Pod Security level of %v namespace, violations: [%v]", pod.Name, enforce, pod.Namespace, strings.Join(violations, ", "))
+	}
+	if violations := PodSecurityViolations(pod, warn); len(violations) != 0 {
+		log.Warnf("%v helper pod violates the %v Pod Security level of %v namespace, violations: [%v]", pod.Name, warn, pod.Namespace, strings.Join(violations, ", "))
+	}
+	return nil
+}
+
+// PodSecurityViolations returns the fields of the pod, which are not allowed by the given Pod Security level
+func PodSecurityViolations(pod *apiv1.Pod, level string) []string {
+
+	switch level {
+	case PodSecurityBaseline:
+		return baselineViolations(pod)
+	case PodSecurityRestricted:
+		return append(baselineViolations(pod), restrictedViolations(pod)...)
+	default:
+		return nil
+	}
+}
+
+// baselineViolations returns the violations of the baseline level
+func baselineViolations(pod *apiv1.Pod) []string {
+
+	var violations []string
+	if pod.Spec.HostPID {
+		violations = append(violations, "hostPID")
+	}
+	if pod.Spec.HostNetwork {
+		violations = append(violations, "hostNetwork")
+	}
+	if pod.Spec.HostIPC {
+		violations = append(violations, "hostIPC")
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.HostPath != nil {
+			violations = append(violations, "hostPath volume "+volume.Name)
+		}
+	}
+	if pod.Annotations[seccompPodAnnotation] == "unconfined" {
+		violations = append(violations, "unconfined seccomp profile")
+	}
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		if pod.Annotations[appArmorAnnotationPrefix+container.Name] == "unconfined" {
+			violations = append(violations, "unconfined apparmor profile of "+container.Name+" container")
+		}
+		for _, port := range container.Ports {
+			if port.HostPort != 0 {
+				violations = append(violations, "hostPort of "+container.Name+" container")
+			}
+		}
+		sc := container.SecurityContext
+		if sc == nil {
+			continue
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			violations = append(violations, "privileged "+container.Name+" container")
+		}
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				if !baselineCapabilities[capability] {
+					violations = append(violations, string(capability)+" capability of "+container.Name+" container")
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// restrictedViolations returns the violations of the restricted level, which are not covered by the baseline level
+func restrictedViolations(pod *apiv1.Pod) []string {
+
+	var violations []string
+	if profile := pod.Annotations[seccompPodAnnotation]; profile != "runtime/default" && !strings.HasPrefix(profile, "localhost/") {
+		violations = append(violations, "seccomp profile is not runtime/default or localhost")
+	}
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		sc := container.SecurityContext
+		if sc == nil {
+			sc = &apiv1.SecurityContext{}
+		}
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			violations = append(violations, "privilege escalation of "+container.Name+" container")
+		}
+		if (sc.RunAsNonRoot == nil || !*sc.RunAsNonRoot) && (pod.Spec.SecurityContext == nil || pod.Spec.SecurityContext.RunAsNonRoot == nil || !*pod.Spec.SecurityContext.RunAsNonRoot) {
+			violations = append(violations, "runAsNonRoot of "+container.Name+" container")
+		}
+		if sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			violations = append(violations, "root user of "+container.Name+" container")
+		}
+		if sc.Capabilities == nil || !dropsAll(sc.Capabilities.Drop) {
+			violations = append(violations, "capabilities of "+container.Name+" container are not dropped")
+		}
+	}
+	return violations
+}
+
+// dropsAll checks whether all the capabilities are dropped
+func dropsAll(capabilities []apiv1.Capability) bool {
+	for _, capability := range capabilities {
+		if capability == "ALL" {
+			return true
+		}
+	}
+	return false
+}
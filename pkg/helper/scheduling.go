@@ -0,0 +1,90 @@
+package helper
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// tolerateAll is the value of the helper tolerations, which tolerates all the taints
+const tolerateAll = "all"
+
+// ParseTolerations parse the tolerations of the helper pods
+// it is either all, which tolerates all the taints or comma separated tolerations in the key[=value][:effect] format
+func ParseTolerations(tolerations string) ([]apiv1.Toleration, error) {
+
+	tolerations = strings.TrimSpace(tolerations)
+	switch tolerations {
+	case "":
+		return nil, nil
+	case tolerateAll:
+		return []apiv1.Toleration{{Operator: apiv1.TolerationOpExists}}, nil
+	}
+
+	var result []apiv1.Toleration
+	for _, toleration := range strings.Split(tolerations, ",") {
+		toleration = strings.TrimSpace(toleration)
+		if toleration == "" {
+			continue
+		}
+		var effect string
+		if i := strings.LastIndex(toleration, ":"); i != -1 {
+			toleration, effect = toleration[:i], toleration[i+1:]
+		}
+		switch apiv1.TaintEffect(effect) {
+		case "", apiv1.TaintEffectNoSchedule, apiv1.TaintEffectPreferNoSchedule, apiv1.TaintEffectNoExecute:
+		default:
+			return nil, errors.Errorf("invalid effect of %v toleration, effect: %v", toleration, effect)
+		}
+		key, value := toleration, ""
+		operator := apiv1.TolerationOpExists
+		if i := strings.Index(toleration, "="); i != -1 {
+			key, value, operator = toleration[:i], toleration[i+1:], apiv1.TolerationOpEqual
+		}
+		if key == "" {
+			return nil, errors.Errorf("invalid toleration, key is not provided in %v", toleration)
+		}
+		result = append(result, apiv1.Toleration{
+			Key:      key,
+			Operator: operator,
+			Value:    value,
+			Effect:   apiv1.TaintEffect(effect),
+		})
+	}
+	return result, nil
+}
+
+// WithNodeSelector sets the node selector of the helper pod
+// it is skipped for the helpers pinned to a node, as the kubelet rejects the pinned pods with a mismatching node selector
+func WithNodeSelector(nodeSelector map[string]string) Option {
+	return func(pod *apiv1.Pod) {
+		if pod.Spec.NodeName != "" || len(nodeSelector) == 0 {
+			return
+		}
+		if pod.Spec.NodeSelector == nil {
+			pod.Spec.NodeSelector = map[string]string{}
+		}
+		for key, value := range nodeSelector {
+			pod.Spec.NodeSelector[key] = value
+		}
+	}
+}
+
+// lostReason returns the reason, if the helper pod is evicted or failed before its helper container completed
+// the chaos injected by such helpers may not be reverted
+func lostReason(pod apiv1.Pod, containerName string) string {
+
+	if pod.Status.Phase != apiv1.PodFailed {
+		return ""
+	}
+	if pod.Status.Reason == "Evicted" {
+		return "evicted from " + pod.Spec.NodeName + " node, " + pod.Status.Message
+	}
+	for _, container := range pod.Status.ContainerStatuses {
+		if container.Name == containerName && container.State.Terminated != nil {
+			return ""
+		}
+	}
+	return "failed on " + pod.Spec.NodeName + " node, reason: " + pod.Status.Reason + " " + pod.Status.Message
+}
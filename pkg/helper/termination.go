@@ -0,0 +1,88 @@
+package helper
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	apiv1 "k8s.io/api/core/v1"
+)
+
+// maxTerminationMessageLength is the upper limit of the termination message accepted by the kubelet
+const maxTerminationMessageLength = 4096
+
+// TerminationMessage is the structured termination message of the helper container
+// it describes the chaos injected & reverted by the helper, so that it can be reported by the experiment after the failure
+type TerminationMessage struct {
+	Injected []string `json:"injected,omitempty"`
+	Reverted []string `json:"reverted,omitempty"`
+	Aborted  bool     `json:"aborted,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+var (
+	termination     TerminationMessage
+	terminationLock sync.Mutex
+)
+
+// RecordInjected records the chaos injected by the helper
+func RecordInjected(chaos string) {
+	terminationLock.Lock()
+	defer terminationLock.Unlock()
+	termination.Injected = append(termination.Injected, chaos)
+}
+
+// RecordReverted records the chaos reverted by the helper
+func RecordReverted(chaos string) {
+	terminationLock.Lock()
+	defer terminationLock.Unlock()
+	termination.Reverted = append(termination.Reverted, chaos)
+}
+
+// RecordAbort records that the helper is aborted
+func RecordAbort() {
+	terminationLock.Lock()
+	defer terminationLock.Unlock()
+	termination.Aborted = true
+}
+
+// WriteTerminationMessage writes the recorded chaos and the given error as the termination message of the helper container
+// it is best effort, the failures are only logged as the helper is about to exit
+func WriteTerminationMessage(err error) {
+
+	terminationLock.Lock()
+	defer terminationLock.Unlock()
+
+	if err != nil {
+		termination.Error = err.Error()
+	}
+	message, _ := json.Marshal(termination)
+	if len(message) > maxTerminationMessageLength {
+		// the error is the most relevant part of the message, which is retained
+		message, _ = json.Marshal(TerminationMessage{Aborted: termination.Aborted, Error: termination.Error})
+	}
+	if err := ioutil.WriteFile(apiv1.TerminationMessagePathDefault, message, 0644); err != nil {
+		log.Warnf("Unable to write the termination message, err: %v", err)
+	}
+}
+
+// ParseTerminationMessage parses the structured termination message of the helper container
+// it returns false, if the message is not written by WriteTerminationMessage
+func ParseTerminationMessage(message string) (TerminationMessage, bool) {
+
+	var result TerminationMessage
+	if err := json.Unmarshal([]byte(message), &result); err != nil {
+		return TerminationMessage{}, false
+	}
+	return result, true
+}
+
+// ExitAborted records the abort, writes the termination message and exits the helper
+// it is used by the helpers once the chaos is reverted or skipped on the abort signal
+func ExitAborted() {
+	RecordAbort()
+	WriteTerminationMessage(nil)
+	os.Exit(1)
+}
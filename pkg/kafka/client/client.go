@@ -0,0 +1,566 @@
+package client
+
+import (
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Config contains the connection details of the kafka cluster
+type Config struct {
+	// BootstrapServers contains the host:port of the brokers used to discover the cluster
+	BootstrapServers []string
+	// ClientID is sent along with every request
+	ClientID string
+	// TLS enables the tls connections to the brokers, if not nil
+	TLS *tls.Config
+	// SASL enables the sasl authentication, if the mechanism is set
+	SASL SASLConfig
+	// Timeout is the dial and request timeout
+	Timeout time.Duration
+}
+
+// Broker contains the details of the kafka broker
+type Broker struct {
+	ID   int32
+	Host string
+	Port int32
+	Rack string
+}
+
+// Addr returns the host:port of the broker
+func (b Broker) Addr() string {
+	return net.JoinHostPort(b.Host, strconv.Itoa(int(b.Port)))
+}
+
+// PartitionMetadata contains the leader and the replicas of the partition
+type PartitionMetadata struct {
+	Partition int32
+	Leader    int32
+	Replicas  []int32
+	ISR       []int32
+	Err       error
+}
+
+// TopicMetadata contains the partitions of the topic
+type TopicMetadata struct {
+	Name       string
+	Partitions []PartitionMetadata
+	Err        error
+}
+
+// Partition returns the metadata of the given partition of the topic
+func (t TopicMetadata) Partition(partition int32) (PartitionMetadata, error) {
+	for _, p := range t.Partitions {
+		if p.Partition == partition {
+			return p, nil
+		}
+	}
+	return PartitionMetadata{}, errors.Errorf("partition %v of %v topic is not found", partition, t.Name)
+}
+
+// Metadata contains the brokers, the controller and the topics of the cluster
+type Metadata struct {
+	Brokers      map[int32]Broker
+	ControllerID int32
+	Topics       map[string]TopicMetadata
+}
+
+// Client talks the kafka protocol to the brokers of the cluster
+// it is safe for the concurrent use, the requests to the same broker are serialised
+type Client struct {
+	config Config
+
+	mu       sync.Mutex
+	brokers  map[int32]Broker
+	conns    map[int32]*brokerConn
+	leaders  map[string]map[int32]int32
+	control  int32
+	metadata *brokerConn
+}
+
+// brokerConn is the connection to the single broker
+type brokerConn struct {
+	mu            sync.Mutex
+	conn          net.Conn
+	addr          string
+	clientID      string
+	timeout       time.Duration
+	correlationID int32
+}
+
+// NewClient connects to the bootstrap servers and derive the brokers of the cluster
+func NewClient(config Config) (*Client, error) {
+
+	if len(config.BootstrapServers) == 0 {
+		return nil, errors.New("no kafka bootstrap servers are provided")
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.ClientID == "" {
+		config.ClientID = "litmus"
+	}
+
+	c := &Client{
+		config:  config,
+		brokers: map[int32]Broker{},
+		conns:   map[int32]*brokerConn{},
+		leaders: map[string]map[int32]int32{},
+		control: -1,
+	}
+	if _, err := c.Metadata(); err != nil {
+		c.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close closes the connections to all the brokers
+func (c *Client) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, conn := range c.conns {
+		conn.close()
+		delete(c.conns, id)
+	}
+	if c.metadata != nil {
+		c.metadata.close()
+		c.metadata = nil
+	}
+}
+
+// Brokers returns the brokers of the cluster, as per the latest metadata
+func (c *Client) Brokers() map[int32]Broker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	brokers := map[int32]Broker{}
+	for id, broker := range c.brokers {
+		brokers[id] = broker
+	}
+	return brokers
+}
+
+// Metadata fetches the metadata of the given topics, no topics are fetched if none is given
+// the leaders of the partitions are cached, to route the produce & fetch requests
+func (c *Client) Metadata(topics ...string) (*Metadata, error) {
+	return c.metadataOf(topics, false)
+}
+
+// ClusterMetadata fetches the metadata of all the topics of the cluster
+func (c *Client) ClusterMetadata() (*Metadata, error) {
+	return c.metadataOf(nil, true)
+}
+
+func (c *Client) metadataOf(topics []string, all bool) (*Metadata, error) {
+
+	e := &encoder{}
+	if all {
+		// the null array fetches all the topics
+		e.int32(-1)
+	} else {
+		e.arrayLen(len(topics))
+	}
+	for _, topic := range topics {
+		e.string(topic)
+	}
+	// allow_auto_topic_creation
+	e.bool(false)
+
+	d, err := c.roundTripAny(apiMetadata, 4, e.buf)
+	if err != nil {
+		return nil, errors.Errorf("Unable to fetch the kafka metadata, err: %v", err)
+	}
+
+	metadata := &Metadata{Brokers: map[int32]Broker{}, Topics: map[string]TopicMetadata{}}
+	// throttle_time_ms
+	d.int32()
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		broker := Broker{ID: d.int32(), Host: d.string(), Port: d.int32(), Rack: d.string()}
+		metadata.Brokers[broker.ID] = broker
+	}
+	// cluster_id
+	d.string()
+	metadata.ControllerID = d.int32()
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		code := d.int16()
+		topic := TopicMetadata{Name: d.string(), Err: asError(code)}
+		// is_internal
+		d.bool()
+		for j, m := 0, d.arrayLen(); j < m; j++ {
+			code := d.int16()
+			partition := PartitionMetadata{Err: asError(code)}
+			partition.Partition = d.int32()
+			partition.Leader = d.int32()
+			partition.Replicas = d.int32Array()
+			partition.ISR = d.int32Array()
+			topic.Partitions = append(topic.Partitions, partition)
+		}
+		metadata.Topics[topic.Name] = topic
+	}
+	if d.err != nil {
+		return nil, errors.Errorf("Unable to decode the kafka metadata, err: %v", d.err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.brokers = metadata.Brokers
+	c.control = metadata.ControllerID
+	for name, topic := range metadata.Topics {
+		if topic.Err != nil {
+			delete(c.leaders, name)
+			continue
+		}
+		leaders := map[int32]int32{}
+		for _, partition := range topic.Partitions {
+			leaders[partition.Partition] = partition.Leader
+		}
+		c.leaders[name] = leaders
+	}
+	// dropping the connections to the brokers, which are no longer part of the cluster
+	for id, conn := range c.conns {
+		if broker, ok := c.brokers[id]; !ok || broker.Addr() != conn.addr {
+			conn.close()
+			delete(c.conns, id)
+		}
+	}
+	return metadata, nil
+}
+
+// DescribeTopic returns the leaders and the in-sync replicas of the partitions of the topic
+func (c *Client) DescribeTopic(topic string) (TopicMetadata, error) {
+
+	metadata, err := c.Metadata(topic)
+	if err != nil {
+		return TopicMetadata{}, err
+	}
+	topicMetadata, ok := metadata.Topics[topic]
+	if !ok {
+		return TopicMetadata{}, errors.Errorf("%v topic is not found", topic)
+	}
+	if topicMetadata.Err != nil {
+		return TopicMetadata{}, errors.Errorf("Unable to describe the %v topic, err: %v", topic, topicMetadata.Err)
+	}
+	return topicMetadata, nil
+}
+
+// CreateTopic creates the topic, the replicas are placed by the controller if no assignment is given
+// otherwise the assignment maps the partitions to their replicas, the first one is the preferred leader
+func (c *Client) CreateTopic(topic string, partitions int32, replicationFactor int16, assignment map[int32][]int32, timeout time.Duration) error {
+
+	e := &encoder{}
+	e.arrayLen(1)
+	e.string(topic)
+	if len(assignment) != 0 {
+		partitions, replicationFactor = -1, -1
+	}
+	e.int32(partitions)
+	e.int16(replicationFactor)
+	e.arrayLen(len(assignment))
+	for partition, replicas := range assignment {
+		e.int32(partition)
+		e.arrayLen(len(replicas))
+		for _, replica := range replicas {
+			e.int32(replica)
+		}
+	}
+	// configs
+	e.arrayLen(0)
+	e.int32(int32(timeout / time.Millisecond))
+	// validate_only
+	e.bool(false)
+
+	d, err := c.roundTripController(apiCreateTopics, 2, e.buf, timeout)
+	if err != nil {
+		return errors.Errorf("Unable to create the %v topic, err: %v", topic, err)
+	}
+	// throttle_time_ms
+	d.int32()
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		name, code, message := d.string(), d.int16(), d.string()
+		if name == topic && code != 0 {
+			return errors.Errorf("Unable to create the %v topic, err: %v %v", topic, Error(code), message)
+		}
+	}
+	return d.err
+}
+
+// DeleteTopic deletes the topic
+func (c *Client) DeleteTopic(topic string, timeout time.Duration) error {
+
+	e := &encoder{}
+	e.arrayLen(1)
+	e.string(topic)
+	e.int32(int32(timeout / time.Millisecond))
+
+	d, err := c.roundTripController(apiDeleteTopics, 1, e.buf, timeout)
+	if err != nil {
+		return errors.Errorf("Unable to delete the %v topic, err: %v", topic, err)
+	}
+	// throttle_time_ms
+	d.int32()
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		name, code := d.string(), d.int16()
+		if name == topic && code != 0 {
+			return errors.Errorf("Unable to delete the %v topic, err: %v", topic, Error(code))
+		}
+	}
+	return d.err
+}
+
+// LeaderOf returns the leader of the partition as per the cached metadata, the metadata is refreshed if absent
+func (c *Client) LeaderOf(topic string, partition int32) (Broker, error) {
+
+	c.mu.Lock()
+	leader, ok := c.leaders[topic][partition]
+	c.mu.Unlock()
+	if !ok || leader < 0 {
+		metadata, err := c.Metadata(topic)
+		if err != nil {
+			return Broker{}, err
+		}
+		if err := metadata.Topics[topic].Err; err != nil {
+			return Broker{}, err
+		}
+		c.mu.Lock()
+		leader, ok = c.leaders[topic][partition]
+		c.mu.Unlock()
+		if !ok || leader < 0 {
+			return Broker{}, ErrLeaderNotAvailable
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	broker, ok := c.brokers[leader]
+	if !ok {
+		return Broker{}, ErrBrokerNotAvailable
+	}
+	return broker, nil
+}
+
+// invalidate drops the cached leaders of the topic, so that they are fetched again
+func (c *Client) invalidate(topic string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.leaders, topic)
+}
+
+// roundTripLeader sends the request to the leader of the partition
+// the cached leaders are dropped upon the connection errors & the retriable error codes
+func (c *Client) roundTripLeader(topic string, partition int32, apiKey, apiVersion int16, body []byte, extra time.Duration) (*decoder, error) {
+
+	leader, err := c.LeaderOf(topic, partition)
+	if err != nil {
+		c.invalidate(topic)
+		return nil, err
+	}
+	d, err := c.roundTripBroker(leader.ID, apiKey, apiVersion, body, extra)
+	if err != nil {
+		c.invalidate(topic)
+	}
+	return d, err
+}
+
+// roundTripController sends the request to the controller, the brokers forward it to the active controller in kraft mode
+func (c *Client) roundTripController(apiKey, apiVersion int16, body []byte, extra time.Duration) (*decoder, error) {
+
+	c.mu.Lock()
+	controller := c.control
+	c.mu.Unlock()
+	if controller < 0 {
+		if _, err := c.Metadata(); err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		controller = c.control
+		c.mu.Unlock()
+	}
+	return c.roundTripBroker(controller, apiKey, apiVersion, body, extra)
+}
+
+// roundTripBroker sends the request to the given broker, the connection is dropped upon failure
+func (c *Client) roundTripBroker(id int32, apiKey, apiVersion int16, body []byte, extra time.Duration) (*decoder, error) {
+
+	conn, err := c.connect(id)
+	if err != nil {
+		return nil, err
+	}
+	d, err := conn.roundTrip(apiKey, apiVersion, body, extra)
+	if err != nil {
+		c.mu.Lock()
+		if c.conns[id] == conn {
+			delete(c.conns, id)
+		}
+		c.mu.Unlock()
+		conn.close()
+	}
+	return d, err
+}
+
+// roundTripAny sends the request to any reachable broker, the bootstrap servers are tried at last
+func (c *Client) roundTripAny(apiKey, apiVersion int16, body []byte) (*decoder, error) {
+
+	c.mu.Lock()
+	conn := c.metadata
+	addrs := []string{}
+	for _, broker := range c.brokers {
+		addrs = append(addrs, broker.Addr())
+	}
+	addrs = append(addrs, c.config.BootstrapServers...)
+	c.mu.Unlock()
+
+	var lastErr error
+	if conn != nil {
+		d, err := conn.roundTrip(apiKey, apiVersion, body, 0)
+		if err == nil {
+			return d, nil
+		}
+		lastErr = err
+		conn.close()
+		c.mu.Lock()
+		if c.metadata == conn {
+			c.metadata = nil
+		}
+		c.mu.Unlock()
+	}
+	for _, addr := range addrs {
+		conn, err := c.dial(addr)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		d, err := conn.roundTrip(apiKey, apiVersion, body, 0)
+		if err != nil {
+			lastErr = err
+			conn.close()
+			continue
+		}
+		c.mu.Lock()
+		c.metadata = conn
+		c.mu.Unlock()
+		return d, nil
+	}
+	return nil, errors.Errorf("no kafka broker is reachable, err: %v", lastErr)
+}
+
+// connect returns the connection to the given broker, it is dialled if absent
+func (c *Client) connect(id int32) (*brokerConn, error) {
+
+	c.mu.Lock()
+	conn, ok := c.conns[id]
+	broker, known := c.brokers[id]
+	c.mu.Unlock()
+	if ok {
+		return conn, nil
+	}
+	if !known {
+		return nil, ErrBrokerNotAvailable
+	}
+
+	conn, err := c.dial(broker.Addr())
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.conns[id]; ok {
+		conn.close()
+		return existing, nil
+	}
+	c.conns[id] = conn
+	return conn, nil
+}
+
+// dial connects to the broker and authenticates, if sasl is enabled
+func (c *Client) dial(addr string) (*brokerConn, error) {
+
+	dialer := &net.Dialer{Timeout: c.config.Timeout}
+	var conn net.Conn
+	var err error
+	if c.config.TLS != nil {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, c.config.TLS)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, errors.Errorf("Unable to connect to the %v kafka broker, err: %v", addr, err)
+	}
+
+	bc := &brokerConn{conn: conn, addr: addr, clientID: c.config.ClientID, timeout: c.config.Timeout}
+	if c.config.SASL.Mechanism != "" {
+		if err := authenticate(bc, c.config.SASL); err != nil {
+			bc.close()
+			return nil, errors.Errorf("Unable to authenticate with the %v kafka broker, err: %v", addr, err)
+		}
+	}
+	return bc, nil
+}
+
+// roundTrip writes the request and reads its response, the extra duration is added to the deadline
+func (b *brokerConn) roundTrip(apiKey, apiVersion int16, body []byte, extra time.Duration) (*decoder, error) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.conn == nil {
+		return nil, errors.New("connection is closed")
+	}
+	b.correlationID++
+
+	// request header v1 followed by the body, prefixed by the size
+	// the flexible requests use the header v2, which adds the tagged fields
+	flexible := isFlexible(apiKey)
+	e := &encoder{buf: make([]byte, 4, 4+15+len(b.clientID)+len(body))}
+	e.int16(apiKey)
+	e.int16(apiVersion)
+	e.int32(b.correlationID)
+	e.nullableString(b.clientID)
+	if flexible {
+		e.uvarint(0)
+	}
+	e.buf = append(e.buf, body...)
+	binary.BigEndian.PutUint32(e.buf, uint32(len(e.buf)-4))
+
+	if err := b.conn.SetDeadline(time.Now().Add(b.timeout + extra)); err != nil {
+		return nil, err
+	}
+	if _, err := b.conn.Write(e.buf); err != nil {
+		return nil, err
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(b.conn, size[:]); err != nil {
+		return nil, err
+	}
+	response := make([]byte, binary.BigEndian.Uint32(size[:]))
+	if _, err := io.ReadFull(b.conn, response); err != nil {
+		return nil, err
+	}
+
+	d := &decoder{buf: response}
+	if correlationID := d.int32(); correlationID != b.correlationID {
+		return nil, errors.Errorf("unexpected correlation id %v, expected %v", correlationID, b.correlationID)
+	}
+	if flexible {
+		d.skipTags()
+	}
+	return d, d.err
+}
+
+func (b *brokerConn) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conn != nil {
+		b.conn.Close()
+		b.conn = nil
+	}
+}
@@ -0,0 +1,346 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// the frames of the tests are hand encoded as per the kafka protocol guide, the fields are separated by the spaces
+// every request starts with the api key, the api version, the correlation id and the client id of the request header
+const clientID = "0006 6c69746d7573"
+
+// exchange is the request expected by the fake broker and the response sent back, both without the size prefix
+type exchange struct {
+	request  string
+	response string
+}
+
+// text returns the hex encoding of the string, to spell the strings inside the frames
+func text(s string) string {
+	return hex.EncodeToString([]byte(s))
+}
+
+// frame decodes the hex encoded frame, ignoring the spaces
+func frame(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(strings.Join(strings.Fields(s), ""))
+	if err != nil {
+		t.Fatalf("invalid frame %v, err: %v", s, err)
+	}
+	return b
+}
+
+// fakeBroker serves the exchanges in order over the server side of the connection
+// every request is compared byte for byte with the expected one, before sending the response
+func fakeBroker(t *testing.T, conn net.Conn, exchanges []exchange) <-chan struct{} {
+
+	var requests, responses [][]byte
+	for _, ex := range exchanges {
+		requests = append(requests, frame(t, ex.request))
+		responses = append(responses, frame(t, ex.response))
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer conn.Close()
+		for i := range requests {
+			var size [4]byte
+			if _, err := io.ReadFull(conn, size[:]); err != nil {
+				t.Errorf("request %v is not received, err: %v", i+1, err)
+				return
+			}
+			request := make([]byte, binary.BigEndian.Uint32(size[:]))
+			if _, err := io.ReadFull(conn, request); err != nil {
+				t.Errorf("request %v is truncated, err: %v", i+1, err)
+				return
+			}
+			if !bytes.Equal(request, requests[i]) {
+				t.Errorf("unexpected request %v\ngot:  %x\nwant: %x", i+1, request, requests[i])
+			}
+			response := make([]byte, 4, 4+len(responses[i]))
+			binary.BigEndian.PutUint32(response, uint32(len(responses[i])))
+			if _, err := conn.Write(append(response, responses[i]...)); err != nil {
+				t.Errorf("unable to send the response %v, err: %v", i+1, err)
+				return
+			}
+		}
+	}()
+	return done
+}
+
+// newTestConn returns the connection to the fake broker serving the exchanges
+// the returned func closes the connection and waits till all the exchanges are served
+func newTestConn(t *testing.T, exchanges ...exchange) (*brokerConn, func()) {
+
+	client, server := net.Pipe()
+	done := fakeBroker(t, server, exchanges)
+	conn := &brokerConn{conn: client, addr: "kafka-0:9092", clientID: "litmus", timeout: 5 * time.Second}
+	return conn, func() {
+		conn.close()
+		<-done
+	}
+}
+
+// newTestClient returns the client connected to the fake broker serving the exchanges
+// the fake broker is the broker 1, which is the controller and the leader of the partition 0 of the orders topic
+// the returned func closes the client and waits till all the exchanges are served
+func newTestClient(t *testing.T, exchanges ...exchange) (*Client, func()) {
+
+	conn, closeConn := newTestConn(t, exchanges...)
+	c := &Client{
+		config:   Config{ClientID: "litmus", Timeout: 10 * time.Second},
+		brokers:  map[int32]Broker{1: {ID: 1, Host: "kafka-0", Port: 9092}},
+		conns:    map[int32]*brokerConn{1: conn},
+		leaders:  map[string]map[int32]int32{"orders": {0: 1}},
+		control:  1,
+		metadata: conn,
+	}
+	return c, func() {
+		c.Close()
+		closeConn()
+	}
+}
+
+func TestMetadata(t *testing.T) {
+
+	c, closeClient := newTestClient(t, exchange{
+		// metadata v4: topics [orders], allow_auto_topic_creation false
+		request: "0003 0004 00000001" + clientID +
+			"00000001 0006" + text("orders") +
+			"00",
+		// throttle_time_ms, brokers [1 kafka-0:9092 null rack, 2 kafka-1:9092 rack-b], cluster_id, controller_id 2
+		// topics [orders: partition 0 led by 1 with replicas & isr [1 2], partition 1 without leader with replicas [2 1] & isr [2]]
+		response: "00000001" +
+			"00000000" +
+			"00000002" +
+			"00000001 0007" + text("kafka-0") + "00002384 ffff" +
+			"00000002 0007" + text("kafka-1") + "00002384 0006" + text("rack-b") +
+			"0002" + text("c1") +
+			"00000002" +
+			"00000001" +
+			"0000 0006" + text("orders") + "00" +
+			"00000002" +
+			"0000 00000000 00000001 00000002 00000001 00000002 00000002 00000001 00000002" +
+			"0005 00000001 ffffffff 00000002 00000002 00000001 00000001 00000002",
+	})
+	defer closeClient()
+
+	metadata, err := c.Metadata("orders")
+	if err != nil {
+		t.Fatalf("unable to fetch the metadata, err: %v", err)
+	}
+
+	wantBrokers := map[int32]Broker{
+		1: {ID: 1, Host: "kafka-0", Port: 9092},
+		2: {ID: 2, Host: "kafka-1", Port: 9092, Rack: "rack-b"},
+	}
+	if !reflect.DeepEqual(metadata.Brokers, wantBrokers) {
+		t.Errorf("brokers: got %v, want %v", metadata.Brokers, wantBrokers)
+	}
+	if metadata.ControllerID != 2 {
+		t.Errorf("controller: got %v, want 2", metadata.ControllerID)
+	}
+	wantTopic := TopicMetadata{
+		Name: "orders",
+		Partitions: []PartitionMetadata{
+			{Partition: 0, Leader: 1, Replicas: []int32{1, 2}, ISR: []int32{1, 2}},
+			{Partition: 1, Leader: -1, Replicas: []int32{2, 1}, ISR: []int32{2}, Err: ErrLeaderNotAvailable},
+		},
+	}
+	if !reflect.DeepEqual(metadata.Topics["orders"], wantTopic) {
+		t.Errorf("topic: got %+v, want %+v", metadata.Topics["orders"], wantTopic)
+	}
+
+	// the leaders are cached, the partition without the leader is not routable
+	if broker, err := c.LeaderOf("orders", 0); err != nil || broker.ID != 1 {
+		t.Errorf("leader of partition 0: got %v, %v, want broker 1", broker, err)
+	}
+	if c.control != 2 {
+		t.Errorf("cached controller: got %v, want 2", c.control)
+	}
+	// the connection to the broker 1 is retained, as its address is not changed
+	if _, ok := c.conns[1]; !ok {
+		t.Error("connection to the broker 1 is dropped")
+	}
+}
+
+func TestClusterMetadata(t *testing.T) {
+
+	c, closeClient := newTestClient(t, exchange{
+		// metadata v4: null topics fetches all the topics
+		request: "0003 0004 00000001" + clientID +
+			"ffffffff" +
+			"00",
+		// throttle_time_ms, brokers [1 kafka-0:9092], null cluster_id, controller_id 1, topics [__consumer_offsets without partitions]
+		response: "00000001" +
+			"00000000" +
+			"00000001" +
+			"00000001 0007" + text("kafka-0") + "00002384 ffff" +
+			"ffff" +
+			"00000001" +
+			"00000001" +
+			"0000 0012" + text("__consumer_offsets") + "01" +
+			"00000000",
+	})
+	defer closeClient()
+
+	metadata, err := c.ClusterMetadata()
+	if err != nil {
+		t.Fatalf("unable to fetch the metadata, err: %v", err)
+	}
+	if _, ok := metadata.Topics["__consumer_offsets"]; !ok || len(metadata.Topics) != 1 {
+		t.Errorf("topics: got %v, want __consumer_offsets", metadata.Topics)
+	}
+}
+
+func TestMetadataTruncated(t *testing.T) {
+
+	c, closeClient := newTestClient(t, exchange{
+		request: "0003 0004 00000001" + clientID +
+			"00000001 0006" + text("orders") +
+			"00",
+		// the response ends within the first broker
+		response: "00000001" +
+			"00000000" +
+			"00000002" +
+			"00000001 0007" + text("kafka-0"),
+	})
+	defer closeClient()
+
+	if _, err := c.Metadata("orders"); err == nil {
+		t.Error("expected an error for the truncated response")
+	}
+}
+
+func TestCorrelationIDMismatch(t *testing.T) {
+
+	conn, closeConn := newTestConn(t, exchange{
+		request:  "0003 0004 00000001" + clientID + "00000000 00",
+		response: "00000007",
+	})
+	defer closeConn()
+
+	body := frame(t, "00000000 00")
+	if _, err := conn.roundTrip(apiMetadata, 4, body, 0); err == nil || !strings.Contains(err.Error(), "correlation id") {
+		t.Errorf("expected the correlation id mismatch, got %v", err)
+	}
+}
+
+func TestCreateTopic(t *testing.T) {
+
+	c, closeClient := newTestClient(t,
+		exchange{
+			// create topics v2: [orders 3 partitions, replication factor 2, no assignment, no configs], timeout_ms 30000, validate_only false
+			request: "0013 0002 00000001" + clientID +
+				"00000001 0006" + text("orders") + "00000003 0002 00000000 00000000" +
+				"00007530" +
+				"00",
+			// throttle_time_ms, topics [orders no error, null message]
+			response: "00000001" +
+				"00000000" +
+				"00000001 0006" + text("orders") + "0000 ffff",
+		},
+		exchange{
+			// the assignment replaces the partition count and the replication factor with -1
+			request: "0013 0002 00000002" + clientID +
+				"00000001 0006" + text("orders") + "ffffffff ffff 00000001 00000000 00000002 00000001 00000002 00000000" +
+				"00007530" +
+				"00",
+			response: "00000002" +
+				"00000000" +
+				"00000001 0006" + text("orders") + "0024 0006" + text("exists"),
+		},
+	)
+	defer closeClient()
+
+	if err := c.CreateTopic("orders", 3, 2, nil, 30*time.Second); err != nil {
+		t.Errorf("unable to create the topic, err: %v", err)
+	}
+	err := c.CreateTopic("orders", 3, 2, map[int32][]int32{0: {1, 2}}, 30*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "TOPIC_ALREADY_EXISTS") {
+		t.Errorf("expected the topic already exists error, got %v", err)
+	}
+}
+
+func TestDeleteTopic(t *testing.T) {
+
+	c, closeClient := newTestClient(t, exchange{
+		// delete topics v1: [orders], timeout_ms 30000
+		request: "0014 0001 00000001" + clientID +
+			"00000001 0006" + text("orders") +
+			"00007530",
+		// throttle_time_ms, topics [orders unknown topic]
+		response: "00000001" +
+			"00000000" +
+			"00000001 0006" + text("orders") + "0003",
+	})
+	defer closeClient()
+
+	err := c.DeleteTopic("orders", 30*time.Second)
+	if err == nil || !strings.Contains(err.Error(), "UNKNOWN_TOPIC_OR_PARTITION") {
+		t.Errorf("expected the unknown topic error, got %v", err)
+	}
+}
+
+func TestActiveController(t *testing.T) {
+
+	c, closeClient := newTestClient(t, exchange{
+		// describe quorum v0 is flexible, the request header v2 ends with the empty tagged fields
+		// topics [__cluster_metadata partitions [0]], with the empty tagged fields of the partition, the topic and the request
+		request: "0037 0000 00000001" + clientID + "00" +
+			"02 13" + text("__cluster_metadata") + "02 00000000 00" + "00" +
+			"00",
+		// the response header v1 ends with the empty tagged fields
+		// error_code, topics [__cluster_metadata partitions [0 no error, leader 3, epoch 5, high watermark 200, no voters, no observers]]
+		response: "00000001 00" +
+			"0000" +
+			"02 13" + text("__cluster_metadata") +
+			"02 00000000 0000 00000003 00000005 00000000000000c8 01 01 00" + "00" +
+			"00",
+	})
+	defer closeClient()
+
+	controller, err := c.ActiveController()
+	if err != nil {
+		t.Fatalf("unable to get the active controller, err: %v", err)
+	}
+	if controller != 3 {
+		t.Errorf("got %v, want 3", controller)
+	}
+}
+
+func TestDecodeQuorumLeader(t *testing.T) {
+
+	tests := []struct {
+		response string
+		want     int32
+		wantErr  bool
+		testName string
+	}{
+		{response: "0000 02 13" + text("__cluster_metadata") + "02 00000000 0000 00000002 00000001 0000000000000000 01 01 00 00 00", want: 2, testName: "leader"},
+		{response: "0000 02 13" + text("__cluster_metadata") + "02 00000000 0000 ffffffff 00000001 0000000000000000 01 01 00 00 00", wantErr: true, testName: "no leader"},
+		{response: "0023", wantErr: true, testName: "unsupported version"},
+		{response: "0000 02 13" + text("__cluster"), wantErr: true, testName: "truncated"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			leader, err := decodeQuorumLeader(&decoder{buf: frame(t, tt.response)})
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got the leader %v", leader)
+				}
+				return
+			}
+			if err != nil || leader != tt.want {
+				t.Errorf("got %v, %v, want %v", leader, err, tt.want)
+			}
+		})
+	}
+}
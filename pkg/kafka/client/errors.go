@@ -0,0 +1,85 @@
+package client
+
+import "fmt"
+
+// Error is the error code returned by the kafka brokers
+type Error int16
+
+// error codes of the kafka protocol, which are handled by the client
+const (
+	ErrOffsetOutOfRange          Error = 1
+	ErrUnknownTopicOrPartition   Error = 3
+	ErrLeaderNotAvailable        Error = 5
+	ErrNotLeaderOrFollower       Error = 6
+	ErrRequestTimedOut           Error = 7
+	ErrBrokerNotAvailable        Error = 8
+	ErrReplicaNotAvailable       Error = 9
+	ErrNetworkException          Error = 13
+	ErrNotEnoughReplicas         Error = 19
+	ErrNotEnoughReplicasAppend   Error = 20
+	ErrTopicAuthorizationFailed  Error = 29
+	ErrClusterAuthorization      Error = 31
+	ErrUnsupportedSaslMechanism  Error = 33
+	ErrIllegalSaslState          Error = 34
+	ErrUnsupportedVersion        Error = 35
+	ErrTopicAlreadyExists        Error = 36
+	ErrNotController             Error = 41
+	ErrSaslAuthenticationFailed  Error = 58
+	ErrFencedLeaderEpoch         Error = 74
+	ErrUnknownLeaderEpoch        Error = 75
+	ErrOffsetNotAvailable        Error = 78
+	ErrPreferredLeaderNotPresent Error = 80
+)
+
+var errorNames = map[Error]string{
+	ErrOffsetOutOfRange:          "OFFSET_OUT_OF_RANGE",
+	ErrUnknownTopicOrPartition:   "UNKNOWN_TOPIC_OR_PARTITION",
+	ErrLeaderNotAvailable:        "LEADER_NOT_AVAILABLE",
+	ErrNotLeaderOrFollower:       "NOT_LEADER_OR_FOLLOWER",
+	ErrRequestTimedOut:           "REQUEST_TIMED_OUT",
+	ErrBrokerNotAvailable:        "BROKER_NOT_AVAILABLE",
+	ErrReplicaNotAvailable:       "REPLICA_NOT_AVAILABLE",
+	ErrNetworkException:          "NETWORK_EXCEPTION",
+	ErrNotEnoughReplicas:         "NOT_ENOUGH_REPLICAS",
+	ErrNotEnoughReplicasAppend:   "NOT_ENOUGH_REPLICAS_AFTER_APPEND",
+	ErrTopicAuthorizationFailed:  "TOPIC_AUTHORIZATION_FAILED",
+	ErrClusterAuthorization:      "CLUSTER_AUTHORIZATION_FAILED",
+	ErrUnsupportedSaslMechanism:  "UNSUPPORTED_SASL_MECHANISM",
+	ErrIllegalSaslState:          "ILLEGAL_SASL_STATE",
+	ErrUnsupportedVersion:        "UNSUPPORTED_VERSION",
+	ErrTopicAlreadyExists:        "TOPIC_ALREADY_EXISTS",
+	ErrNotController:             "NOT_CONTROLLER",
+	ErrSaslAuthenticationFailed:  "SASL_AUTHENTICATION_FAILED",
+	ErrFencedLeaderEpoch:         "FENCED_LEADER_EPOCH",
+	ErrUnknownLeaderEpoch:        "UNKNOWN_LEADER_EPOCH",
+	ErrOffsetNotAvailable:        "OFFSET_NOT_AVAILABLE",
+	ErrPreferredLeaderNotPresent: "PREFERRED_LEADER_NOT_AVAILABLE",
+}
+
+func (e Error) Error() string {
+	if name, ok := errorNames[e]; ok {
+		return fmt.Sprintf("kafka error %v (%v)", int16(e), name)
+	}
+	return fmt.Sprintf("kafka error %v", int16(e))
+}
+
+// Retriable returns true, if the request can succeed once the metadata is refreshed
+// these are expected while the partition leadership moves to the other brokers
+func (e Error) Retriable() bool {
+	switch e {
+	case ErrUnknownTopicOrPartition, ErrLeaderNotAvailable, ErrNotLeaderOrFollower, ErrRequestTimedOut,
+		ErrBrokerNotAvailable, ErrReplicaNotAvailable, ErrNetworkException, ErrNotEnoughReplicas,
+		ErrNotEnoughReplicasAppend, ErrNotController, ErrFencedLeaderEpoch, ErrUnknownLeaderEpoch,
+		ErrOffsetNotAvailable, ErrPreferredLeaderNotPresent:
+		return true
+	}
+	return false
+}
+
+// asError converts the error code of the response into the error
+func asError(code int16) error {
+	if code == 0 {
+		return nil
+	}
+	return Error(code)
+}
@@ -0,0 +1,159 @@
+package client
+
+import (
+	"github.com/pkg/errors"
+)
+
+// the topic of the kraft metadata log, whose leader is the active controller
+const clusterMetadataTopic = "__cluster_metadata"
+
+// ActiveController returns the node id of the active controller
+// the leader of the metadata quorum is the active controller of the kraft clusters, while the
+// controller reported by the metadata is the active controller of the zookeeper clusters
+func (c *Client) ActiveController() (int32, error) {
+
+	e := &encoder{}
+	e.compactArrayLen(1)
+	e.compactString(clusterMetadataTopic)
+	e.compactArrayLen(1)
+	e.int32(0)
+	e.uvarint(0)
+	e.uvarint(0)
+	e.uvarint(0)
+
+	// the zookeeper clusters don't support the describe quorum request, and may close the connection
+	if d, err := c.roundTripAny(apiDescribeQuorum, 0, e.buf); err == nil {
+		if leader, err := decodeQuorumLeader(d); err == nil {
+			return leader, nil
+		}
+	}
+
+	metadata, err := c.Metadata()
+	if err != nil {
+		return -1, err
+	}
+	if metadata.ControllerID < 0 {
+		return -1, errors.New("no active controller found in the cluster")
+	}
+	return metadata.ControllerID, nil
+}
+
+// decodeQuorumLeader returns the leader of the metadata quorum from the describe quorum response
+func decodeQuorumLeader(d *decoder) (int32, error) {
+
+	if code := d.int16(); code != 0 {
+		return -1, Error(code)
+	}
+	for i, n := 0, d.compactArrayLen(); i < n; i++ {
+		d.compactString()
+		for j, m := 0, d.compactArrayLen(); j < m; j++ {
+			// partition_index
+			d.int32()
+			code, leader := d.int16(), d.int32()
+			if d.err != nil {
+				return -1, d.err
+			}
+			if code != 0 {
+				return -1, Error(code)
+			}
+			if leader >= 0 {
+				return leader, nil
+			}
+		}
+	}
+	if d.err != nil {
+		return -1, d.err
+	}
+	return -1, errors.New("metadata quorum has no leader")
+}
+
+// ConsumerGroupLag returns the lag of the consumer group for each of its partitions
+// the lag is the difference of the latest offset and the committed offset of the group
+func (c *Client) ConsumerGroupLag(group string) (map[string]map[int32]int64, error) {
+
+	coordinator, err := c.findCoordinator(group)
+	if err != nil {
+		return nil, err
+	}
+
+	e := &encoder{}
+	e.string(group)
+	// the null array fetches the offsets of all the topics
+	e.int32(-1)
+	d, err := c.roundTripBroker(coordinator, apiOffsetFetch, 2, e.buf, 0)
+	if err != nil {
+		return nil, errors.Errorf("Unable to fetch the offsets of the %v consumer group, err: %v", group, err)
+	}
+
+	committed := map[string]map[int32]int64{}
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		topic := d.string()
+		for j, m := 0, d.arrayLen(); j < m; j++ {
+			partition, offset := d.int32(), d.int64()
+			// metadata
+			d.string()
+			code := d.int16()
+			// the partitions without the committed offset are skipped
+			if code != 0 || offset < 0 {
+				continue
+			}
+			if committed[topic] == nil {
+				committed[topic] = map[int32]int64{}
+			}
+			committed[topic][partition] = offset
+		}
+	}
+	if code := d.int16(); code != 0 {
+		return nil, errors.Errorf("Unable to fetch the offsets of the %v consumer group, err: %v", group, Error(code))
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	lags := map[string]map[int32]int64{}
+	for topic, partitions := range committed {
+		lags[topic] = map[int32]int64{}
+		for partition, offset := range partitions {
+			latest, err := c.ListOffset(topic, partition, OffsetLatest)
+			if err != nil {
+				return nil, errors.Errorf("Unable to get the latest offset of the partition %v of %v topic, err: %v", partition, topic, err)
+			}
+			if lag := latest - offset; lag > 0 {
+				lags[topic][partition] = lag
+			} else {
+				lags[topic][partition] = 0
+			}
+		}
+	}
+	return lags, nil
+}
+
+// findCoordinator returns the node id of the coordinator of the consumer group
+func (c *Client) findCoordinator(group string) (int32, error) {
+
+	e := &encoder{}
+	e.string(group)
+	// key_type, the consumer group
+	e.int8(0)
+	d, err := c.roundTripAny(apiFindCoordinator, 1, e.buf)
+	if err != nil {
+		return -1, errors.Errorf("Unable to find the coordinator of the %v consumer group, err: %v", group, err)
+	}
+	// throttle_time_ms
+	d.int32()
+	code, message := d.int16(), d.string()
+	broker := Broker{ID: d.int32(), Host: d.string(), Port: d.int32()}
+	if d.err != nil {
+		return -1, d.err
+	}
+	if code != 0 {
+		return -1, errors.Errorf("Unable to find the coordinator of the %v consumer group, err: %v %v", group, Error(code), message)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.brokers[broker.ID]; !ok {
+		c.brokers[broker.ID] = broker
+	}
+	return broker.ID, nil
+}
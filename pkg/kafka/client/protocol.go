@@ -0,0 +1,291 @@
+package client
+
+import (
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// api keys of the kafka protocol requests used by the client
+const (
+	apiProduce         int16 = 0
+	apiFetch           int16 = 1
+	apiListOffsets     int16 = 2
+	apiMetadata        int16 = 3
+	apiOffsetFetch     int16 = 9
+	apiFindCoordinator int16 = 10
+	apiSaslHandshake   int16 = 17
+	apiCreateTopics    int16 = 19
+	apiDeleteTopics    int16 = 20
+	apiSaslAuth        int16 = 36
+	apiDescribeQuorum  int16 = 55
+)
+
+// isFlexible returns true for the requests, which are sent in the flexible versions with the tagged fields
+// all the other requests are sent in the versions prior to the flexible ones
+func isFlexible(apiKey int16) bool {
+	return apiKey == apiDescribeQuorum
+}
+
+// errShortBuffer is returned when the response is truncated
+var errShortBuffer = errors.New("kafka response is truncated")
+
+// encoder builds the body of the kafka protocol requests
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) int8(v int8) {
+	e.buf = append(e.buf, byte(v))
+}
+
+func (e *encoder) int16(v int16) {
+	e.buf = append(e.buf, byte(v>>8), byte(v))
+}
+
+func (e *encoder) int32(v int32) {
+	e.buf = append(e.buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func (e *encoder) int64(v int64) {
+	e.int32(int32(v >> 32))
+	e.int32(int32(v))
+}
+
+func (e *encoder) bool(v bool) {
+	if v {
+		e.int8(1)
+		return
+	}
+	e.int8(0)
+}
+
+func (e *encoder) string(v string) {
+	e.int16(int16(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// nullableString encodes the empty string as null
+func (e *encoder) nullableString(v string) {
+	if v == "" {
+		e.int16(-1)
+		return
+	}
+	e.string(v)
+}
+
+// bytes encodes the nil slice as null
+func (e *encoder) bytes(v []byte) {
+	if v == nil {
+		e.int32(-1)
+		return
+	}
+	e.int32(int32(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+func (e *encoder) arrayLen(n int) {
+	e.int32(int32(n))
+}
+
+// varint encodes the zigzag varint, used inside the record batches
+func (e *encoder) varint(v int64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(tmp[:], v)
+	e.buf = append(e.buf, tmp[:n]...)
+}
+
+// uvarint encodes the unsigned varint, used by the flexible versions
+func (e *encoder) uvarint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	e.buf = append(e.buf, tmp[:n]...)
+}
+
+// compactString encodes the string prefixed by its length plus one, used by the flexible versions
+func (e *encoder) compactString(v string) {
+	e.uvarint(uint64(len(v)) + 1)
+	e.buf = append(e.buf, v...)
+}
+
+// compactArrayLen encodes the length plus one of the array, used by the flexible versions
+func (e *encoder) compactArrayLen(n int) {
+	e.uvarint(uint64(n) + 1)
+}
+
+// varbytes encodes the varint length prefixed bytes, used inside the record batches
+func (e *encoder) varbytes(v []byte) {
+	if v == nil {
+		e.varint(-1)
+		return
+	}
+	e.varint(int64(len(v)))
+	e.buf = append(e.buf, v...)
+}
+
+// decoder parses the body of the kafka protocol responses
+// the first error is retained and the subsequent reads return zero values
+type decoder struct {
+	buf []byte
+	off int
+	err error
+}
+
+func (d *decoder) next(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if n < 0 || d.off+n > len(d.buf) {
+		d.err = errShortBuffer
+		return nil
+	}
+	b := d.buf[d.off : d.off+n]
+	d.off += n
+	return b
+}
+
+func (d *decoder) remaining() int {
+	return len(d.buf) - d.off
+}
+
+func (d *decoder) int8() int8 {
+	b := d.next(1)
+	if b == nil {
+		return 0
+	}
+	return int8(b[0])
+}
+
+func (d *decoder) int16() int16 {
+	b := d.next(2)
+	if b == nil {
+		return 0
+	}
+	return int16(binary.BigEndian.Uint16(b))
+}
+
+func (d *decoder) int32() int32 {
+	b := d.next(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(b))
+}
+
+func (d *decoder) int64() int64 {
+	b := d.next(8)
+	if b == nil {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(b))
+}
+
+func (d *decoder) bool() bool {
+	return d.int8() != 0
+}
+
+// string decodes the string, null is decoded as the empty string
+func (d *decoder) string() string {
+	n := d.int16()
+	if n < 0 {
+		return ""
+	}
+	return string(d.next(int(n)))
+}
+
+// bytes decodes the bytes, null is decoded as nil
+func (d *decoder) bytes() []byte {
+	n := d.int32()
+	if n < 0 {
+		return nil
+	}
+	return d.next(int(n))
+}
+
+// arrayLen decodes the length of the array, null is decoded as the empty array
+func (d *decoder) arrayLen() int {
+	n := d.int32()
+	if n < 0 {
+		return 0
+	}
+	// every element takes at least one byte, which guards against the corrupted lengths
+	if int(n) > d.remaining() {
+		d.err = errShortBuffer
+		return 0
+	}
+	return int(n)
+}
+
+func (d *decoder) int32Array() []int32 {
+	n := d.arrayLen()
+	values := make([]int32, 0, n)
+	for i := 0; i < n; i++ {
+		values = append(values, d.int32())
+	}
+	return values
+}
+
+func (d *decoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.buf[d.off:])
+	if n <= 0 {
+		d.err = errShortBuffer
+		return 0
+	}
+	d.off += n
+	return v
+}
+
+func (d *decoder) varbytes() []byte {
+	n := d.varint()
+	if n < 0 {
+		return nil
+	}
+	return d.next(int(n))
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf[d.off:])
+	if n <= 0 {
+		d.err = errShortBuffer
+		return 0
+	}
+	d.off += n
+	return v
+}
+
+// compactString decodes the string of the flexible versions, null is decoded as the empty string
+func (d *decoder) compactString() string {
+	n := d.uvarint()
+	if n == 0 {
+		return ""
+	}
+	return string(d.next(int(n - 1)))
+}
+
+// compactArrayLen decodes the length of the array of the flexible versions, null is decoded as the empty array
+func (d *decoder) compactArrayLen() int {
+	n := d.uvarint()
+	if n == 0 {
+		return 0
+	}
+	if n-1 > uint64(d.remaining()) {
+		d.err = errShortBuffer
+		return 0
+	}
+	return int(n - 1)
+}
+
+// skipTags skips the tagged fields of the flexible versions
+func (d *decoder) skipTags() {
+	for i, n := uint64(0), d.uvarint(); i < n && d.err == nil; i++ {
+		// tag
+		d.uvarint()
+		d.next(int(d.uvarint()))
+	}
+}
@@ -0,0 +1,339 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"hash/crc32"
+	"io/ioutil"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// special timestamps of the list offsets request
+const (
+	OffsetLatest   int64 = -1
+	OffsetEarliest int64 = -2
+)
+
+// the acks of the produce request, which waits for all the in-sync replicas
+const acksAll int16 = -1
+
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// compressionCodecs contains the names of the compression codecs of the record batches
+var compressionCodecs = map[int16]string{
+	1: "gzip",
+	2: "snappy",
+	3: "lz4",
+	4: "zstd",
+}
+
+// Message is the record produced to or consumed from the partition
+type Message struct {
+	Key       []byte
+	Value     []byte
+	Timestamp time.Time
+	Offset    int64
+}
+
+// Produce appends the messages to the partition and waits for the acknowledgement of all the in-sync replicas
+// it returns the offset of the first message
+func (c *Client) Produce(topic string, partition int32, messages []Message) (int64, error) {
+
+	e := &encoder{}
+	// transactional_id
+	e.nullableString("")
+	e.int16(acksAll)
+	e.int32(int32(c.config.Timeout / time.Millisecond))
+	e.arrayLen(1)
+	e.string(topic)
+	e.arrayLen(1)
+	e.int32(partition)
+	e.bytes(encodeRecordBatch(messages))
+
+	d, err := c.roundTripLeader(topic, partition, apiProduce, 3, e.buf, 0)
+	if err != nil {
+		return 0, err
+	}
+	offset := int64(-1)
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		name := d.string()
+		for j, m := 0, d.arrayLen(); j < m; j++ {
+			index, code, baseOffset := d.int32(), d.int16(), d.int64()
+			// log_append_time_ms
+			d.int64()
+			if name != topic || index != partition {
+				continue
+			}
+			if code != 0 {
+				return 0, c.partitionError(topic, Error(code))
+			}
+			offset = baseOffset
+		}
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	if offset < 0 {
+		return 0, errors.Errorf("no response for the partition %v of %v topic", partition, topic)
+	}
+	return offset, nil
+}
+
+// Fetch reads the messages of the partition from the given offset, it waits up to maxWait for the new messages
+// it returns the messages along with the offset to be fetched next
+func (c *Client) Fetch(topic string, partition int32, offset int64, maxWait time.Duration) ([]Message, int64, error) {
+
+	e := &encoder{}
+	// replica_id
+	e.int32(-1)
+	e.int32(int32(maxWait / time.Millisecond))
+	// min_bytes
+	e.int32(1)
+	// max_bytes
+	e.int32(4 << 20)
+	// isolation_level, read_uncommitted
+	e.int8(0)
+	e.arrayLen(1)
+	e.string(topic)
+	e.arrayLen(1)
+	e.int32(partition)
+	e.int64(offset)
+	// partition_max_bytes
+	e.int32(1 << 20)
+
+	d, err := c.roundTripLeader(topic, partition, apiFetch, 4, e.buf, maxWait)
+	if err != nil {
+		return nil, offset, err
+	}
+	// throttle_time_ms
+	d.int32()
+	var records []byte
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		name := d.string()
+		for j, m := 0, d.arrayLen(); j < m; j++ {
+			index, code := d.int32(), d.int16()
+			// high_watermark, last_stable_offset
+			d.int64()
+			d.int64()
+			// aborted_transactions
+			for k, l := 0, d.arrayLen(); k < l; k++ {
+				d.int64()
+				d.int64()
+			}
+			data := d.bytes()
+			if name != topic || index != partition {
+				continue
+			}
+			if code != 0 {
+				return nil, offset, c.partitionError(topic, Error(code))
+			}
+			records = data
+		}
+	}
+	if d.err != nil {
+		return nil, offset, d.err
+	}
+	return decodeRecordBatches(records, offset)
+}
+
+// ListOffset returns the offset of the partition for the given timestamp, OffsetLatest or OffsetEarliest
+func (c *Client) ListOffset(topic string, partition int32, timestamp int64) (int64, error) {
+
+	e := &encoder{}
+	// replica_id
+	e.int32(-1)
+	e.arrayLen(1)
+	e.string(topic)
+	e.arrayLen(1)
+	e.int32(partition)
+	e.int64(timestamp)
+
+	d, err := c.roundTripLeader(topic, partition, apiListOffsets, 1, e.buf, 0)
+	if err != nil {
+		return 0, err
+	}
+	offset := int64(-1)
+	for i, n := 0, d.arrayLen(); i < n; i++ {
+		name := d.string()
+		for j, m := 0, d.arrayLen(); j < m; j++ {
+			index, code := d.int32(), d.int16()
+			// timestamp
+			d.int64()
+			value := d.int64()
+			if name != topic || index != partition {
+				continue
+			}
+			if code != 0 {
+				return 0, c.partitionError(topic, Error(code))
+			}
+			offset = value
+		}
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	if offset < 0 {
+		return 0, errors.Errorf("no offset for the partition %v of %v topic", partition, topic)
+	}
+	return offset, nil
+}
+
+// partitionError drops the cached leaders of the topic, if the error is due to the leadership change
+func (c *Client) partitionError(topic string, err Error) error {
+	if err.Retriable() {
+		c.invalidate(topic)
+	}
+	return err
+}
+
+// encodeRecordBatch encodes the messages into the uncompressed record batch of magic v2
+func encodeRecordBatch(messages []Message) []byte {
+
+	now := time.Now()
+	firstTimestamp := now.UnixNano() / int64(time.Millisecond)
+	if len(messages) != 0 && !messages[0].Timestamp.IsZero() {
+		firstTimestamp = messages[0].Timestamp.UnixNano() / int64(time.Millisecond)
+	}
+
+	records := &encoder{}
+	maxTimestamp := firstTimestamp
+	for i, message := range messages {
+		timestamp := firstTimestamp
+		if !message.Timestamp.IsZero() {
+			timestamp = message.Timestamp.UnixNano() / int64(time.Millisecond)
+		}
+		if timestamp > maxTimestamp {
+			maxTimestamp = timestamp
+		}
+		record := &encoder{}
+		// attributes
+		record.int8(0)
+		record.varint(timestamp - firstTimestamp)
+		record.varint(int64(i))
+		record.varbytes(message.Key)
+		record.varbytes(message.Value)
+		// headers
+		record.varint(0)
+
+		records.varint(int64(len(record.buf)))
+		records.buf = append(records.buf, record.buf...)
+	}
+
+	// the fields covered by the crc, starting from the attributes
+	body := &encoder{}
+	// attributes, no compression
+	body.int16(0)
+	// last_offset_delta
+	body.int32(int32(len(messages) - 1))
+	body.int64(firstTimestamp)
+	body.int64(maxTimestamp)
+	// producer_id, producer_epoch, base_sequence
+	body.int64(-1)
+	body.int16(-1)
+	body.int32(-1)
+	body.arrayLen(len(messages))
+	body.buf = append(body.buf, records.buf...)
+
+	batch := &encoder{}
+	// base_offset
+	batch.int64(0)
+	// batch_length, it covers the fields after itself
+	batch.int32(int32(4 + 1 + 4 + len(body.buf)))
+	// partition_leader_epoch
+	batch.int32(-1)
+	// magic
+	batch.int8(2)
+	batch.int32(int32(crc32.Checksum(body.buf, crc32c)))
+	batch.buf = append(batch.buf, body.buf...)
+	return batch.buf
+}
+
+// decodeRecordBatches decodes the record batches of magic v2, the trailing partial batch is ignored
+// the messages before the given offset are skipped, as the broker returns the whole batch
+func decodeRecordBatches(data []byte, offset int64) ([]Message, int64, error) {
+
+	messages := []Message{}
+	next := offset
+	d := &decoder{buf: data}
+	for d.remaining() >= 12 {
+		baseOffset := d.int64()
+		length := int(d.int32())
+		if length > d.remaining() {
+			break
+		}
+		batch := &decoder{buf: d.next(length)}
+		// partition_leader_epoch
+		batch.int32()
+		if magic := batch.int8(); magic != 2 {
+			return nil, offset, errors.Errorf("unsupported record batch magic %v", magic)
+		}
+		crc := uint32(batch.int32())
+		if batch.err == nil && crc32.Checksum(batch.buf[batch.off:], crc32c) != crc {
+			return nil, offset, errors.Errorf("corrupted record batch at %v offset", baseOffset)
+		}
+		attributes := batch.int16()
+		lastOffsetDelta := batch.int32()
+		firstTimestamp := batch.int64()
+		// max_timestamp, producer_id, producer_epoch, base_sequence
+		batch.int64()
+		batch.int64()
+		batch.int16()
+		batch.int32()
+		count := batch.int32()
+		if batch.err != nil {
+			return nil, offset, batch.err
+		}
+		if end := baseOffset + int64(lastOffsetDelta) + 1; end > next {
+			next = end
+		}
+		// the control batches mark the transaction boundaries and contain no messages
+		if attributes&0x20 != 0 {
+			continue
+		}
+		// the records follow the header of the batch, they are compressed as a whole
+		records := batch
+		switch codec := attributes & 0x07; codec {
+		case 0:
+		case 1:
+			data, err := gunzip(batch.buf[batch.off:])
+			if err != nil {
+				return nil, offset, errors.Errorf("Unable to decompress the record batch at %v offset, err: %v", baseOffset, err)
+			}
+			records = &decoder{buf: data}
+		default:
+			return nil, offset, errors.Errorf("%v compression of the record batches is not supported, supported compressions are none and gzip", compressionCodecs[codec])
+		}
+		for i := int32(0); i < count; i++ {
+			record := &decoder{buf: records.varbytes()}
+			// attributes
+			record.int8()
+			timestampDelta := record.varint()
+			offsetDelta := record.varint()
+			message := Message{
+				Key:       record.varbytes(),
+				Value:     record.varbytes(),
+				Offset:    baseOffset + offsetDelta,
+				Timestamp: time.Unix(0, (firstTimestamp+timestampDelta)*int64(time.Millisecond)),
+			}
+			if records.err != nil || record.err != nil {
+				return nil, offset, errShortBuffer
+			}
+			if message.Offset >= offset {
+				messages = append(messages, message)
+			}
+		}
+	}
+	return messages, next, nil
+}
+
+// gunzip decompresses the gzip compressed records
+func gunzip(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
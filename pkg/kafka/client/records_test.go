@@ -0,0 +1,200 @@
+package client
+
+import (
+	"encoding/hex"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// the record batches of magic v2 holding the messages k1:v1 and null:v2, produced 5ms apart at 1700000000000ms
+// they are encoded independently of the client, along with their crc32c
+const (
+	// the records of the batches, which are gzip compressed in the compressed batch
+	batchRecords = "14000000046b310476310010000a020104763200"
+	// the batch header from the partition_leader_epoch till the records count, without the base offset
+	plainBatch = "00000045 ffffffff 02 bdf15f45 0000 00000001 0000018bcfe56800 0000018bcfe56805 ffffffffffffffff ffff ffffffff 00000002" + batchRecords
+	gzipBatch  = "00000059 ffffffff 02 ce6d1bfc 0001 00000001 0000018bcfe56800 0000018bcfe56805 ffffffffffffffff ffff ffffffff 00000002" +
+		"1f8b08000000000002031361606060c936642933641060e06262642933620000b749807714000000"
+	lz4Batch = "00000045 ffffffff 02 e133c999 0003 00000001 0000018bcfe56800 0000018bcfe56805 ffffffffffffffff ffff ffffffff 00000002" + batchRecords
+)
+
+var batchMessages = []Message{
+	{Key: []byte("k1"), Value: []byte("v1"), Timestamp: time.Unix(1700000000, 0)},
+	{Value: []byte("v2"), Timestamp: time.Unix(1700000000, 5*int64(time.Millisecond))},
+}
+
+// fetchExchange returns the fetch v4 exchange of the partition 0 of the orders topic at the given offset
+// the response carries the given record set, whose size is prefixed
+func fetchExchange(correlationID, offset, recordSet, recordSetSize string) exchange {
+	return exchange{
+		// replica_id -1, max_wait_ms 500, min_bytes 1, max_bytes 4MiB, read_uncommitted, topics [orders partitions [0 at the offset, partition_max_bytes 1MiB]]
+		request: "0001 0004" + correlationID + clientID +
+			"ffffffff 000001f4 00000001 00400000 00" +
+			"00000001 0006" + text("orders") + "00000001 00000000" + offset + "00100000",
+		// throttle_time_ms, topics [orders partitions [0 no error, high_watermark 44, last_stable_offset 44, no aborted transactions, records]]
+		response: correlationID +
+			"00000000" +
+			"00000001 0006" + text("orders") + "00000001 00000000 0000 000000000000002c 000000000000002c 00000000" +
+			recordSetSize + recordSet,
+	}
+}
+
+// wantMessages returns the messages of the batch based at the offset 42
+func wantMessages() []Message {
+	messages := []Message{}
+	for i, message := range batchMessages {
+		message.Offset = 42 + int64(i)
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+func TestEncodeRecordBatch(t *testing.T) {
+	want := strings.Join(strings.Fields("0000000000000000"+plainBatch), "")
+	if got := hex.EncodeToString(encodeRecordBatch(batchMessages)); got != want {
+		t.Errorf("unexpected record batch\ngot:  %v\nwant: %v", got, want)
+	}
+}
+
+func TestProduce(t *testing.T) {
+
+	c, closeClient := newTestClient(t,
+		exchange{
+			// null transactional_id, acks -1, timeout_ms 10000, topics [orders partitions [0 record batch]]
+			request: "0000 0003 00000001" + clientID +
+				"ffff ffff 00002710" +
+				"00000001 0006" + text("orders") + "00000001 00000000 00000051 0000000000000000" + plainBatch,
+			// topics [orders partitions [0 no error, base_offset 42, log_append_time_ms -1]], throttle_time_ms
+			response: "00000001" +
+				"00000001 0006" + text("orders") + "00000001 00000000 0000 000000000000002a ffffffffffffffff" +
+				"00000000",
+		},
+		exchange{
+			request: "0000 0003 00000002" + clientID +
+				"ffff ffff 00002710" +
+				"00000001 0006" + text("orders") + "00000001 00000000 00000051 0000000000000000" + plainBatch,
+			// the broker is no longer the leader
+			response: "00000002" +
+				"00000001 0006" + text("orders") + "00000001 00000000 0006 ffffffffffffffff ffffffffffffffff" +
+				"00000000",
+		},
+	)
+	defer closeClient()
+
+	offset, err := c.Produce("orders", 0, batchMessages)
+	if err != nil {
+		t.Fatalf("unable to produce the messages, err: %v", err)
+	}
+	if offset != 42 {
+		t.Errorf("base offset: got %v, want 42", offset)
+	}
+
+	if _, err := c.Produce("orders", 0, batchMessages); err != ErrNotLeaderOrFollower {
+		t.Errorf("expected the not leader error, got %v", err)
+	}
+	// the leaders of the topic are dropped, to be fetched again
+	if _, ok := c.leaders["orders"]; ok {
+		t.Error("leaders of the orders topic are not invalidated")
+	}
+}
+
+func TestFetch(t *testing.T) {
+
+	tests := []struct {
+		offset   string
+		batch    string
+		size     string
+		from     int64
+		want     []Message
+		testName string
+	}{
+		{offset: "000000000000002a", batch: "000000000000002a" + plainBatch, size: "00000051", from: 42, want: wantMessages(), testName: "uncompressed batch"},
+		{offset: "000000000000002b", batch: "000000000000002a" + plainBatch, size: "00000051", from: 43, want: wantMessages()[1:], testName: "messages before the offset are skipped"},
+		{offset: "000000000000002a", batch: "000000000000002a" + gzipBatch, size: "00000065", from: 42, want: wantMessages(), testName: "gzip compressed batch"},
+		// the partial batch at the end of the response is ignored, it is fetched again from the next offset
+		{offset: "000000000000002a", batch: "000000000000002a" + plainBatch + "000000000000002c 00000045 ffffffff", size: "00000061", from: 42, want: wantMessages(), testName: "trailing partial batch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			c, closeClient := newTestClient(t, fetchExchange("00000001", tt.offset, tt.batch, tt.size))
+			defer closeClient()
+
+			messages, next, err := c.Fetch("orders", 0, tt.from, 500*time.Millisecond)
+			if err != nil {
+				t.Fatalf("unable to fetch the messages, err: %v", err)
+			}
+			if !reflect.DeepEqual(messages, tt.want) {
+				t.Errorf("messages: got %+v, want %+v", messages, tt.want)
+			}
+			if next != 44 {
+				t.Errorf("next offset: got %v, want 44", next)
+			}
+		})
+	}
+}
+
+func TestFetchCompressionNotSupported(t *testing.T) {
+
+	c, closeClient := newTestClient(t, fetchExchange("00000001", "000000000000002a", "000000000000002a"+lz4Batch, "00000051"))
+	defer closeClient()
+
+	_, next, err := c.Fetch("orders", 0, 42, 500*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "lz4 compression") {
+		t.Errorf("expected the lz4 compression to be rejected, got %v", err)
+	}
+	if next != 42 {
+		t.Errorf("next offset: got %v, want 42", next)
+	}
+}
+
+func TestDecodeRecordBatches(t *testing.T) {
+
+	tests := []struct {
+		batch    string
+		wantErr  string
+		testName string
+	}{
+		// the crc of the batch is flipped
+		{batch: "000000000000002a" + strings.Replace(plainBatch, "bdf15f45", "bdf15f44", 1), wantErr: "corrupted", testName: "corrupted batch"},
+		{batch: "000000000000002a" + strings.Replace(plainBatch, "ffffffff 02", "ffffffff 01", 1), wantErr: "magic", testName: "unsupported magic"},
+		{batch: "000000000000002a" + strings.Replace(lz4Batch, "e133c999 0003", "d572442d 0002", 1), wantErr: "compression", testName: "snappy compression"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			if _, _, err := decodeRecordBatches(frame(t, tt.batch), 42); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected the %v error, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("empty record set", func(t *testing.T) {
+		messages, next, err := decodeRecordBatches(nil, 42)
+		if err != nil || len(messages) != 0 || next != 42 {
+			t.Errorf("got %v, %v, %v, want no messages at 42", messages, next, err)
+		}
+	})
+}
+
+func TestListOffset(t *testing.T) {
+
+	c, closeClient := newTestClient(t, exchange{
+		// replica_id -1, topics [orders partitions [0 latest]]
+		request: "0002 0001 00000001" + clientID +
+			"ffffffff" +
+			"00000001 0006" + text("orders") + "00000001 00000000 ffffffffffffffff",
+		// topics [orders partitions [0 no error, timestamp -1, offset 44]]
+		response: "00000001" +
+			"00000001 0006" + text("orders") + "00000001 00000000 0000 ffffffffffffffff 000000000000002c",
+	})
+	defer closeClient()
+
+	offset, err := c.ListOffset("orders", 0, OffsetLatest)
+	if err != nil {
+		t.Fatalf("unable to list the offset, err: %v", err)
+	}
+	if offset != 44 {
+		t.Errorf("got %v, want 44", offset)
+	}
+}
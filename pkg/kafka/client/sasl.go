@@ -0,0 +1,172 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"hash"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// supported sasl mechanisms
+const (
+	SASLPlain       = "PLAIN"
+	SASLScramSHA256 = "SCRAM-SHA-256"
+	SASLScramSHA512 = "SCRAM-SHA-512"
+)
+
+// SASLConfig contains the sasl credentials
+type SASLConfig struct {
+	Mechanism string
+	Username  string
+	Password  string
+}
+
+// authenticate performs the sasl handshake and the authentication over the kafka protocol
+func authenticate(conn *brokerConn, config SASLConfig) error {
+
+	mechanism := strings.ToUpper(config.Mechanism)
+
+	e := &encoder{}
+	e.string(mechanism)
+	d, err := conn.roundTrip(apiSaslHandshake, 1, e.buf, 0)
+	if err != nil {
+		return err
+	}
+	if code := d.int16(); code != 0 {
+		enabled := []string{}
+		for i, n := 0, d.arrayLen(); i < n; i++ {
+			enabled = append(enabled, d.string())
+		}
+		return errors.Errorf("%v mechanism is not enabled, enabled mechanisms: %v, err: %v", mechanism, enabled, Error(code))
+	}
+
+	switch mechanism {
+	case SASLPlain:
+		_, err := saslAuthenticate(conn, []byte("\x00"+config.Username+"\x00"+config.Password))
+		return err
+	case SASLScramSHA256:
+		return scramAuthenticate(conn, sha256.New, config)
+	case SASLScramSHA512:
+		return scramAuthenticate(conn, sha512.New, config)
+	default:
+		return errors.Errorf("%v sasl mechanism is not supported", config.Mechanism)
+	}
+}
+
+// saslAuthenticate sends the sasl message and returns the response of the broker
+func saslAuthenticate(conn *brokerConn, message []byte) ([]byte, error) {
+
+	e := &encoder{}
+	e.bytes(message)
+	d, err := conn.roundTrip(apiSaslAuth, 0, e.buf, 0)
+	if err != nil {
+		return nil, err
+	}
+	code, reason, response := d.int16(), d.string(), d.bytes()
+	if d.err != nil {
+		return nil, d.err
+	}
+	if code != 0 {
+		return nil, errors.Errorf("%v %v", Error(code), reason)
+	}
+	return response, nil
+}
+
+// scramAuthenticate performs the scram exchange of rfc 5802 with a random client nonce
+func scramAuthenticate(conn *brokerConn, h func() hash.Hash, config SASLConfig) error {
+
+	nonce := make([]byte, 24)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	return scramExchange(conn, h, config, base64.RawStdEncoding.EncodeToString(nonce))
+}
+
+// scramExchange sends the client first and the client final messages and verifies the server signature
+func scramExchange(conn *brokerConn, h func() hash.Hash, config SASLConfig, clientNonce string) error {
+
+	username := strings.NewReplacer("=", "=3D", ",", "=2C").Replace(config.Username)
+	clientFirstBare := "n=" + username + ",r=" + clientNonce
+
+	serverFirst, err := saslAuthenticate(conn, []byte("n,,"+clientFirstBare))
+	if err != nil {
+		return err
+	}
+	attributes := parseScramAttributes(string(serverFirst))
+	serverNonce, salt64, iterations := attributes["r"], attributes["s"], attributes["i"]
+	if !strings.HasPrefix(serverNonce, clientNonce) {
+		return errors.New("scram server nonce doesn't match the client nonce")
+	}
+	salt, err := base64.StdEncoding.DecodeString(salt64)
+	if err != nil {
+		return errors.Errorf("invalid scram salt, err: %v", err)
+	}
+	iter, err := strconv.Atoi(iterations)
+	if err != nil || iter <= 0 {
+		return errors.Errorf("invalid scram iteration count %v", iterations)
+	}
+
+	saltedPassword := pbkdf2(h, []byte(config.Password), salt, iter)
+	clientKey := hmacSum(h, saltedPassword, []byte("Client Key"))
+	storedKey := h()
+	storedKey.Write(clientKey)
+
+	clientFinalWithoutProof := "c=biws,r=" + serverNonce
+	authMessage := clientFirstBare + "," + string(serverFirst) + "," + clientFinalWithoutProof
+	clientSignature := hmacSum(h, storedKey.Sum(nil), []byte(authMessage))
+	proof := make([]byte, len(clientKey))
+	for i := range clientKey {
+		proof[i] = clientKey[i] ^ clientSignature[i]
+	}
+
+	serverFinal, err := saslAuthenticate(conn, []byte(clientFinalWithoutProof+",p="+base64.StdEncoding.EncodeToString(proof)))
+	if err != nil {
+		return err
+	}
+	attributes = parseScramAttributes(string(serverFinal))
+	if reason, ok := attributes["e"]; ok {
+		return errors.Errorf("scram authentication failed, err: %v", reason)
+	}
+	serverKey := hmacSum(h, saltedPassword, []byte("Server Key"))
+	serverSignature := base64.StdEncoding.EncodeToString(hmacSum(h, serverKey, []byte(authMessage)))
+	if !hmac.Equal([]byte(attributes["v"]), []byte(serverSignature)) {
+		return errors.New("scram server signature doesn't match")
+	}
+	return nil
+}
+
+// parseScramAttributes parses the comma separated key=value attributes of the scram messages
+func parseScramAttributes(message string) map[string]string {
+	attributes := map[string]string{}
+	for _, field := range strings.Split(message, ",") {
+		if len(field) > 2 && field[1] == '=' {
+			attributes[field[:1]] = field[2:]
+		}
+	}
+	return attributes
+}
+
+func hmacSum(h func() hash.Hash, key, message []byte) []byte {
+	mac := hmac.New(h, key)
+	mac.Write(message)
+	return mac.Sum(nil)
+}
+
+// pbkdf2 derives the salted password, the output length is the size of the hash as required by scram
+func pbkdf2(h func() hash.Hash, password, salt []byte, iterations int) []byte {
+	u := hmacSum(h, password, append(append([]byte{}, salt...), 0, 0, 0, 1))
+	result := append([]byte{}, u...)
+	for i := 1; i < iterations; i++ {
+		u = hmacSum(h, password, u)
+		for j := range result {
+			result[j] ^= u[j]
+		}
+	}
+	return result
+}
@@ -0,0 +1,121 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// saslAuthExchange returns the sasl authenticate v0 exchange carrying the given sasl messages
+func saslAuthExchange(correlationID, request, response string) exchange {
+	return exchange{
+		request:  "0024 0000" + correlationID + clientID + size(request) + text(request),
+		response: correlationID + "0000 ffff" + size(response) + text(response),
+	}
+}
+
+// size returns the int32 size prefix of the bytes
+func size(s string) string {
+	return hex.EncodeToString([]byte{byte(len(s) >> 24), byte(len(s) >> 16), byte(len(s) >> 8), byte(len(s))})
+}
+
+func TestAuthenticatePlain(t *testing.T) {
+
+	conn, closeConn := newTestConn(t,
+		exchange{
+			// sasl handshake v1: mechanism PLAIN
+			request:  "0011 0001 00000001" + clientID + "0005" + text("PLAIN"),
+			response: "00000001 0000 00000001 0005" + text("PLAIN"),
+		},
+		// the authzid is empty, the username and the password are separated by the nul bytes
+		saslAuthExchange("00000002", "\x00user\x00pass", ""),
+	)
+	defer closeConn()
+
+	if err := authenticate(conn, SASLConfig{Mechanism: "plain", Username: "user", Password: "pass"}); err != nil {
+		t.Errorf("unable to authenticate, err: %v", err)
+	}
+}
+
+func TestAuthenticateFailed(t *testing.T) {
+
+	conn, closeConn := newTestConn(t,
+		exchange{
+			request:  "0011 0001 00000001" + clientID + "0005" + text("PLAIN"),
+			response: "00000001 0000 00000001 0005" + text("PLAIN"),
+		},
+		exchange{
+			request: "0024 0000 00000002" + clientID + "0000000a" + text("\x00user\x00pass"),
+			// SASL_AUTHENTICATION_FAILED with the error message and the empty auth bytes
+			response: "00000002 003a 0015" + text("Invalid username/pass") + "00000000",
+		},
+	)
+	defer closeConn()
+
+	err := authenticate(conn, SASLConfig{Mechanism: "PLAIN", Username: "user", Password: "pass"})
+	if err == nil || !strings.Contains(err.Error(), "SASL_AUTHENTICATION_FAILED") {
+		t.Errorf("expected the authentication failure, got %v", err)
+	}
+}
+
+func TestAuthenticateMechanismNotEnabled(t *testing.T) {
+
+	conn, closeConn := newTestConn(t, exchange{
+		request: "0011 0001 00000001" + clientID + "000d" + text("SCRAM-SHA-512"),
+		// UNSUPPORTED_SASL_MECHANISM along with the enabled mechanisms
+		response: "00000001 0021 00000001 0005" + text("PLAIN"),
+	})
+	defer closeConn()
+
+	err := authenticate(conn, SASLConfig{Mechanism: "scram-sha-512", Username: "user", Password: "pencil"})
+	if err == nil || !strings.Contains(err.Error(), "UNSUPPORTED_SASL_MECHANISM") || !strings.Contains(err.Error(), "[PLAIN]") {
+		t.Errorf("expected the mechanism to be rejected, got %v", err)
+	}
+}
+
+// the scram exchange is the test vector of rfc 7677
+const (
+	scramNonce       = "rOprNGfwEbeRWgbNEkqO"
+	scramServerFirst = "r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096"
+	scramClientFinal = "c=biws,r=rOprNGfwEbeRWgbNEkqO%hvYDpWUa2RaTCAfuxFIlj)hNlF$k0,p=dHzbZapWIk4jUhN+Ute9ytag9zjfMHgsqmmiz7AndVQ="
+	scramServerFinal = "v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G4="
+)
+
+func TestScramExchange(t *testing.T) {
+
+	tests := []struct {
+		serverFinal string
+		wantErr     bool
+		testName    string
+	}{
+		{serverFinal: scramServerFinal, testName: "server signature verified"},
+		{serverFinal: "v=6rriTRBi23WpRR/wtup+mMhUZUn/dB5nLTJRsjl95G5=", wantErr: true, testName: "server signature mismatch"},
+		{serverFinal: "e=invalid-proof", wantErr: true, testName: "server error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			conn, closeConn := newTestConn(t,
+				saslAuthExchange("00000001", "n,,n=user,r="+scramNonce, scramServerFirst),
+				saslAuthExchange("00000002", scramClientFinal, tt.serverFinal),
+			)
+			defer closeConn()
+
+			err := scramExchange(conn, sha256.New, SASLConfig{Username: "user", Password: "pencil"}, scramNonce)
+			if tt.wantErr != (err != nil) {
+				t.Errorf("got %v, want the error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScramExchangeNonceMismatch(t *testing.T) {
+
+	// the server nonce doesn't extend the client nonce, the client final message is not sent
+	conn, closeConn := newTestConn(t, saslAuthExchange("00000001", "n,,n=user,r="+scramNonce, "r=fyko+d2lbbFgONRv9qkxdawL,s=W22ZaJ0SNY7soEsUEjb6gQ==,i=4096"))
+	defer closeConn()
+
+	if err := scramExchange(conn, sha256.New, SASLConfig{Username: "user", Password: "pencil"}, scramNonce); err == nil {
+		t.Error("expected the nonce mismatch")
+	}
+}
@@ -37,19 +37,29 @@ func GetENV(kafkaDetails *kafkaTypes.ExperimentDetails) {
 	kafkaDetails.ChaoslibDetail = &ChaoslibDetail
 	kafkaDetails.KafkaKind = Getenv("KAFKA_KIND", "statefulset")
 	kafkaDetails.KafkaLivenessStream = Getenv("KAFKA_LIVENESS_STREAM", "enabled")
-	kafkaDetails.KafkaLivenessImage = Getenv("KAFKA_LIVENESS_IMAGE", "litmuschaos/kafka-client:ci")
 	kafkaDetails.KafkaConsumerTimeout, _ = strconv.Atoi(Getenv("KAFKA_CONSUMER_TIMEOUT", "60000"))
-	kafkaDetails.KafkaInstanceName = Getenv("KAFKA_INSTANCE_NAME", "kafka")
 	kafkaDetails.KafkaNamespace = Getenv("KAFKA_NAMESPACE", "default")
 	kafkaDetails.KafkaLabel = Getenv("KAFKA_LABEL", "")
 	kafkaDetails.KafkaBroker = Getenv("KAFKA_BROKER", "")
 	kafkaDetails.KafkaRepliationFactor = Getenv("KAFKA_REPLICATION_FACTOR", "")
 	kafkaDetails.KafkaService = Getenv("KAFKA_SERVICE", "")
 	kafkaDetails.KafkaPort = Getenv("KAFKA_PORT", "9092")
+	kafkaDetails.KafkaBootstrapServers = Getenv("KAFKA_BOOTSTRAP_SERVERS", "")
+	kafkaDetails.KafkaTopic = Getenv("KAFKA_TOPIC", "")
+	kafkaDetails.KafkaPartition, _ = strconv.Atoi(Getenv("KAFKA_PARTITION", "0"))
+	kafkaDetails.KafkaSASLMechanism = Getenv("KAFKA_SASL_MECHANISM", "")
+	kafkaDetails.KafkaSASLUsername = Getenv("KAFKA_SASL_USERNAME", "")
+	kafkaDetails.KafkaSASLPassword = Getenv("KAFKA_SASL_PASSWORD", "")
+	kafkaDetails.KafkaTLSEnabled, _ = strconv.ParseBool(Getenv("KAFKA_TLS_ENABLED", "false"))
+	kafkaDetails.KafkaTLSCACert = Getenv("KAFKA_TLS_CA_CERT", "")
+	kafkaDetails.KafkaTLSSkipVerify, _ = strconv.ParseBool(Getenv("KAFKA_TLS_SKIP_VERIFY", "false"))
+	kafkaDetails.KafkaChaosMode = Getenv("KAFKA_CHAOS_MODE", "leader")
+	kafkaDetails.KafkaISRAffectedPerc, _ = strconv.Atoi(Getenv("KAFKA_ISR_AFFECTED_PERC", "50"))
+	kafkaDetails.KafkaRecoveryTimeout, _ = strconv.Atoi(Getenv("KAFKA_RECOVERY_TIMEOUT", "180"))
+	kafkaDetails.KafkaConsumerGroup = Getenv("KAFKA_CONSUMER_GROUP", "")
+	kafkaDetails.KafkaConsumerLagLimit, _ = strconv.ParseInt(Getenv("KAFKA_CONSUMER_LAG_LIMIT", "100"), 10, 64)
 	kafkaDetails.ZookeeperNamespace = Getenv("ZOOKEEPER_NAMESPACE", "")
 	kafkaDetails.ZookeeperLabel = Getenv("ZOOKEEPER_LABEL", "")
-	kafkaDetails.ZookeeperService = Getenv("ZOOKEEPER_SERVICE", "")
-	kafkaDetails.ZookeeperPort = Getenv("ZOOKEEPER_PORT", "")
 	kafkaDetails.Lib = Getenv("LIB", "litmus")
 	kafkaDetails.RunID = Getenv("RunID", "")
 
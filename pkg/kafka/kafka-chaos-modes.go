@@ -0,0 +1,150 @@
+package kafka
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	kafkaclient "github.com/litmuschaos/litmus-go/pkg/kafka/client"
+	experimentTypes "github.com/litmuschaos/litmus-go/pkg/kafka/types"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// GetTargetBrokers derive the kafka broker pods for the chaos, as per the KAFKA_CHAOS_MODE
+// leader: the leader of the chosen partition, controller: the active controller,
+// topic-brokers: the brokers hosting the partitions of the chosen topic, isr: the percentage of the in-sync replicas of the chosen partition
+// it returns the comma separated pod names, an empty value selects the random brokers by the label
+func GetTargetBrokers(experimentsDetails *experimentTypes.ExperimentDetails, liveness *Liveness, clients clients.ClientSets) (string, error) {
+
+	mode := strings.ToLower(experimentsDetails.KafkaChaosMode)
+	if mode == "leader" && liveness != nil {
+		return liveness.Leader, nil
+	}
+	if mode == "leader" && experimentsDetails.KafkaTopic == "" {
+		return "", nil
+	}
+
+	admin, err := newAdminClient(experimentsDetails)
+	if err != nil {
+		return "", err
+	}
+	defer admin.Close()
+
+	var brokerIDs []int32
+	switch mode {
+	case "leader":
+		partition, err := describePartition(admin, experimentsDetails.KafkaTopic, int32(experimentsDetails.KafkaPartition))
+		if err != nil {
+			return "", err
+		}
+		brokerIDs = []int32{partition.Leader}
+	case "controller":
+		controller, err := admin.ActiveController()
+		if err != nil {
+			return "", errors.Errorf("Unable to find the active controller, err: %v", err)
+		}
+		log.Infof("[Info]: Active controller of the kafka cluster is %v", controller)
+		brokerIDs = []int32{controller}
+	case "topic-brokers":
+		topic, err := getTargetTopic(experimentsDetails, liveness)
+		if err != nil {
+			return "", err
+		}
+		topicMetadata, err := admin.DescribeTopic(topic)
+		if err != nil {
+			return "", err
+		}
+		brokerIDs = getTopicBrokers(topicMetadata)
+		log.Infof("[Info]: Partitions of %v topic are hosted by %v brokers", topic, brokerIDs)
+	case "isr":
+		topic, err := getTargetTopic(experimentsDetails, liveness)
+		if err != nil {
+			return "", err
+		}
+		partition, err := describePartition(admin, topic, int32(experimentsDetails.KafkaPartition))
+		if err != nil {
+			return "", err
+		}
+		if brokerIDs, err = getRandomISRMembers(partition.ISR, experimentsDetails.KafkaISRAffectedPerc); err != nil {
+			return "", errors.Errorf("Unable to select the in-sync replicas of partition %v of %v topic, err: %v", experimentsDetails.KafkaPartition, topic, err)
+		}
+	default:
+		return "", errors.Errorf("%v kafka chaos mode is not supported, supported modes: leader, controller, topic-brokers, isr", experimentsDetails.KafkaChaosMode)
+	}
+
+	brokers := admin.Brokers()
+	podNames := []string{}
+	for _, id := range brokerIDs {
+		broker, ok := brokers[id]
+		if !ok {
+			// the dedicated kraft controllers are not part of the brokers, their pods follow the ordinality
+			broker = kafkaclient.Broker{ID: id}
+		}
+		podName, err := getBrokerPod(experimentsDetails, broker, clients)
+		if err != nil {
+			return "", err
+		}
+		podNames = append(podNames, podName)
+	}
+	return strings.Join(podNames, ","), nil
+}
+
+// getTargetTopic returns the chosen topic, it defaults to the liveness topic
+func getTargetTopic(experimentsDetails *experimentTypes.ExperimentDetails, liveness *Liveness) (string, error) {
+	if experimentsDetails.KafkaTopic != "" {
+		return experimentsDetails.KafkaTopic, nil
+	}
+	if liveness != nil {
+		return liveness.Topic, nil
+	}
+	return "", errors.Errorf("KAFKA_TOPIC is required for the %v kafka chaos mode, when the liveness stream is disabled", experimentsDetails.KafkaChaosMode)
+}
+
+// getTopicBrokers returns the brokers hosting the replicas of the partitions of the topic
+func getTopicBrokers(topic kafkaclient.TopicMetadata) []int32 {
+	brokerIDs := []int32{}
+	found := map[int32]bool{}
+	for _, partition := range topic.Partitions {
+		for _, replica := range partition.Replicas {
+			if !found[replica] {
+				found[replica] = true
+				brokerIDs = append(brokerIDs, replica)
+			}
+		}
+	}
+	return brokerIDs
+}
+
+// getRandomISRMembers selects the random in-sync replicas as per the affected percentage, at least one replica is selected
+// it returns an error for an empty isr (an offline partition), rather than falling back to the random brokers
+func getRandomISRMembers(isr []int32, affectedPerc int) ([]int32, error) {
+
+	if len(isr) == 0 {
+		return nil, errors.Errorf("the partition has no in-sync replica, it may be offline")
+	}
+	count := int(math.Round(float64(len(isr)*affectedPerc) / float64(100)))
+	if count == 0 {
+		count = 1
+	}
+	if count > len(isr) {
+		count = len(isr)
+	}
+
+	members := append([]int32{}, isr...)
+	rand.Seed(time.Now().UnixNano())
+	rand.Shuffle(len(members), func(i, j int) { members[i], members[j] = members[j], members[i] })
+	log.Infof("[Info]: Selected %v of %v in-sync replicas", members[:count], isr)
+	return members[:count], nil
+}
+
+// newAdminClient connects to the kafka cluster
+func newAdminClient(experimentsDetails *experimentTypes.ExperimentDetails) (*kafkaclient.Client, error) {
+	config, err := newClientConfig(experimentsDetails)
+	if err != nil {
+		return nil, err
+	}
+	return kafkaclient.NewClient(config)
+}
@@ -1,10 +1,17 @@
 package kafka
 
 import (
+	"strconv"
+	"time"
+
 	"github.com/litmuschaos/litmus-go/pkg/clients"
+	kafkaclient "github.com/litmuschaos/litmus-go/pkg/kafka/client"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/kafka/types"
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/litmuschaos/litmus-go/pkg/status"
+	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
 )
 
 // ClusterHealthCheck checks health of the kafka cluster
@@ -16,6 +23,12 @@ func ClusterHealthCheck(experimentsDetails *experimentTypes.ExperimentDetails, c
 		return err
 	}
 
+	// the kraft clusters have no zookeeper
+	if experimentsDetails.ZookeeperLabel == "" {
+		log.Info("[Info]: Zookeeper label is not provided, skipping the zookeeper status check")
+		return nil
+	}
+
 	// Checking zookeeper pods status
 	log.Info("[Status]: Verify that all the zookeeper pods are running")
 	if err := status.CheckApplicationStatus(experimentsDetails.ZookeeperNamespace, experimentsDetails.ZookeeperLabel, experimentsDetails.ChaoslibDetail.Timeout, experimentsDetails.ChaoslibDetail.Delay, clients); err != nil {
@@ -29,8 +42,121 @@ func ClusterHealthCheck(experimentsDetails *experimentTypes.ExperimentDetails, c
 func DisplayKafkaBroker(experimentsDetails *experimentTypes.ExperimentDetails) {
 
 	if experimentsDetails.KafkaBroker != "" {
-		log.Infof("[Info]: Kafka broker pod for deletion is %v", experimentsDetails.KafkaBroker)
+		log.Infof("[Info]: Kafka broker pods for deletion are %v, chaos mode: %v", experimentsDetails.KafkaBroker, experimentsDetails.KafkaChaosMode)
 	} else {
 		log.Info("[Info]: kafka broker will be selected randomly across the cluster")
 	}
 }
+
+// IsClusterConfigured returns true, if the bootstrap servers or the kafka service are provided to connect to the cluster
+func IsClusterConfigured(experimentsDetails *experimentTypes.ExperimentDetails) bool {
+	return experimentsDetails.KafkaBootstrapServers != "" || experimentsDetails.KafkaService != ""
+}
+
+// ReplicationHealthCheck verifies that no partitions stay offline or under-replicated, within the recovery timeout
+func ReplicationHealthCheck(experimentsDetails *experimentTypes.ExperimentDetails) error {
+
+	admin, err := newAdminClient(experimentsDetails)
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+
+	var offline, underReplicated []string
+	err = retry.
+		Times(uint(experimentsDetails.KafkaRecoveryTimeout / experimentsDetails.ChaoslibDetail.Delay)).
+		Wait(time.Duration(experimentsDetails.ChaoslibDetail.Delay) * time.Second).
+		Try(func(attempt uint) error {
+			metadata, err := admin.ClusterMetadata()
+			if err != nil {
+				return err
+			}
+			offline, underReplicated = []string{}, []string{}
+			for _, topic := range metadata.Topics {
+				for _, partition := range topic.Partitions {
+					name := topic.Name + "-" + strconv.Itoa(int(partition.Partition))
+					switch {
+					case partition.Leader < 0:
+						offline = append(offline, name)
+					case len(partition.ISR) < len(partition.Replicas):
+						underReplicated = append(underReplicated, name)
+					}
+				}
+			}
+			if len(offline) != 0 || len(underReplicated) != 0 {
+				return errors.Errorf("%v partitions are offline and %v partitions are under-replicated", len(offline), len(underReplicated))
+			}
+			return nil
+		})
+	if err != nil {
+		log.InfoWithValues("[Info]: The partitions not recovered from the chaos", logrus.Fields{
+			"Offline":         offline,
+			"UnderReplicated": underReplicated,
+		})
+		return errors.Errorf("Partitions are not recovered within %vs, err: %v", experimentsDetails.KafkaRecoveryTimeout, err)
+	}
+	log.Info("[Info]: All the partitions have the leader and the in-sync replicas")
+	return nil
+}
+
+// RecordConsumerLagBaseline records the lag of the consumer group before the chaos
+func RecordConsumerLagBaseline(experimentsDetails *experimentTypes.ExperimentDetails) error {
+
+	admin, err := newAdminClient(experimentsDetails)
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+
+	if experimentsDetails.ConsumerLagBaseline, err = getConsumerGroupLag(admin, experimentsDetails.KafkaConsumerGroup); err != nil {
+		return err
+	}
+	log.Infof("[Info]: Lag of the %v consumer group is %v (pre-chaos)", experimentsDetails.KafkaConsumerGroup, experimentsDetails.ConsumerLagBaseline)
+	return nil
+}
+
+// ConsumerLagCheck verifies that the lag of the consumer group recovers to the pre-chaos lag, or within the lag limit
+func ConsumerLagCheck(experimentsDetails *experimentTypes.ExperimentDetails) error {
+
+	admin, err := newAdminClient(experimentsDetails)
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+
+	limit := experimentsDetails.ConsumerLagBaseline
+	if experimentsDetails.KafkaConsumerLagLimit > limit {
+		limit = experimentsDetails.KafkaConsumerLagLimit
+	}
+
+	return retry.
+		Times(uint(experimentsDetails.KafkaRecoveryTimeout / experimentsDetails.ChaoslibDetail.Delay)).
+		Wait(time.Duration(experimentsDetails.ChaoslibDetail.Delay) * time.Second).
+		Try(func(attempt uint) error {
+			lag, err := getConsumerGroupLag(admin, experimentsDetails.KafkaConsumerGroup)
+			if err != nil {
+				return err
+			}
+			log.Infof("[Info]: Lag of the %v consumer group is %v, limit: %v", experimentsDetails.KafkaConsumerGroup, lag, limit)
+			if lag > limit {
+				return errors.Errorf("lag of the %v consumer group is not recovered yet", experimentsDetails.KafkaConsumerGroup)
+			}
+			return nil
+		})
+}
+
+// getConsumerGroupLag returns the total lag of the consumer group across its partitions
+func getConsumerGroupLag(admin *kafkaclient.Client, group string) (int64, error) {
+
+	lags, err := admin.ConsumerGroupLag(group)
+	if err != nil {
+		return 0, err
+	}
+	total := int64(0)
+	for _, partitions := range lags {
+		for _, lag := range partitions {
+			total += lag
+		}
+	}
+	return total, nil
+}
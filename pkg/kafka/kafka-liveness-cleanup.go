@@ -1,30 +1,63 @@
 package kafka
 
 import (
+	"encoding/json"
 	"time"
 
-	"github.com/litmuschaos/litmus-go/pkg/clients"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/kafka/types"
-	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/types"
 	"github.com/pkg/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"github.com/sirupsen/logrus"
 )
 
-// LivenessCleanup deletes the kafka liveness pod
-func LivenessCleanup(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) error {
+// LivenessCheck verifies that the liveness stream recovered from the broker failure
+// it stops the stream and records the lost & duplicated messages and the latency in the chaosresult
+func LivenessCheck(experimentsDetails *experimentTypes.ExperimentDetails, liveness *Liveness, resultDetails *types.ResultDetails) error {
 
-	if err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaoslibDetail.AppNS).Delete("kafka-liveness-"+experimentsDetails.RunID, &metav1.DeleteOptions{}); err != nil {
-		return errors.Errorf("Fail to delete liveness deployment, err: %v", err)
+	streamErr := liveness.waitForConsumption(time.Now())
+	liveness.stop()
+
+	metrics := liveness.Metrics()
+	log.InfoWithValues("[Info]: The outcome of the kafka liveness stream", logrus.Fields{
+		"Produced":        metrics.Produced,
+		"Acknowledged":    metrics.Acknowledged,
+		"ProduceErrors":   metrics.ProduceErrors,
+		"Consumed":        metrics.Consumed,
+		"Lost":            metrics.Lost,
+		"Duplicated":      metrics.Duplicated,
+		"AvgLatency(ms)":  metrics.AvgLatency,
+		"MaxLatency(ms)":  metrics.MaxLatency,
+		"MaxProduceStall": metrics.MaxProduceStall,
+	})
+	if resultDetails.Annotations == nil {
+		resultDetails.Annotations = map[string]string{}
+	}
+	if value, err := json.Marshal(metrics); err != nil {
+		log.Warnf("Unable to record the liveness metrics in the chaosresult, err: %v", err)
+	} else {
+		resultDetails.Annotations["litmuschaos.io/kafka-liveness"] = string(value)
 	}
 
-	return retry.
-		Times(uint(experimentsDetails.ChaoslibDetail.Timeout / experimentsDetails.ChaoslibDetail.Delay)).
-		Wait(time.Duration(experimentsDetails.ChaoslibDetail.Delay) * time.Second).
-		Try(func(attempt uint) error {
-			podSpec, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.ChaoslibDetail.AppNS).List(metav1.ListOptions{LabelSelector: "name=kafka-liveness-" + experimentsDetails.RunID})
-			if err != nil || len(podSpec.Items) != 0 {
-				return errors.Errorf("Liveness pod is not deleted yet, err: %v", err)
-			}
-			return nil
-		})
+	if streamErr != nil {
+		return errors.Errorf("Liveness stream is not recovered, err: %v", streamErr)
+	}
+	if metrics.Lost != 0 {
+		return errors.Errorf("%v acknowledged messages are lost from the %v liveness topic", metrics.Lost, metrics.Topic)
+	}
+	return nil
+}
+
+// LivenessCleanup stops the liveness stream and deletes the kafka liveness topic
+func LivenessCleanup(experimentsDetails *experimentTypes.ExperimentDetails, liveness *Liveness) error {
+
+	liveness.stop()
+	defer liveness.close()
+
+	if liveness.createdTopic {
+		if err := liveness.admin.DeleteTopic(liveness.Topic, time.Duration(experimentsDetails.ChaoslibDetail.Timeout)*time.Second); err != nil {
+			return errors.Errorf("Fail to delete liveness topic, err: %v", err)
+		}
+	}
+	return nil
 }
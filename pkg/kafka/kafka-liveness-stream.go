@@ -1,190 +1,447 @@
 package kafka
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/litmuschaos/litmus-go/pkg/clients"
+	kafkaclient "github.com/litmuschaos/litmus-go/pkg/kafka/client"
 	experimentTypes "github.com/litmuschaos/litmus-go/pkg/kafka/types"
 	"github.com/litmuschaos/litmus-go/pkg/log"
-	"github.com/litmuschaos/litmus-go/pkg/status"
 	"github.com/litmuschaos/litmus-go/pkg/utils/common"
-	litmusexec "github.com/litmuschaos/litmus-go/pkg/utils/exec"
+	"github.com/litmuschaos/litmus-go/pkg/utils/retry"
 	"github.com/pkg/errors"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-// LivenessStream generates kafka liveness pod, which continuously validate the liveness of kafka brokers
+// livenessProduceInterval is the interval between the messages produced by the liveness stream
+const livenessProduceInterval = 200 * time.Millisecond
+
+// Liveness is the in-process producer & consumer stream, which continuously validate the liveness of kafka brokers
+type Liveness struct {
+	// Topic is the topic of the liveness stream
+	Topic string
+	// Leader is the pod of the partition leader broker (candidate for the deletion)
+	Leader string
+
+	experimentsDetails *experimentTypes.ExperimentDetails
+	admin              *kafkaclient.Client
+	producer           *kafkaclient.Client
+	consumer           *kafkaclient.Client
+	createdTopic       bool
+
+	stopProducer chan struct{}
+	stopConsumer chan struct{}
+	producerDone chan struct{}
+	consumerDone chan struct{}
+	stopOnce     sync.Once
+
+	mu            sync.Mutex
+	produced      int
+	produceErrors int
+	acked         map[int64]bool
+	lastAcked     time.Time
+	maxStall      time.Duration
+	seen          map[int64]bool
+	consumed      int
+	duplicated    int
+	lastConsumed  time.Time
+	totalLatency  time.Duration
+	maxLatency    time.Duration
+}
+
+// LivenessStream creates the kafka liveness topic and starts the producer & consumer stream over it
 // and derive the kafka topic leader(candidate for the deletion)
-func LivenessStream(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (string, error) {
-	var ordinality string
+func LivenessStream(experimentsDetails *experimentTypes.ExperimentDetails, clients clients.ClientSets) (*Liveness, error) {
 
 	// Generate a random string as suffix to topic name
 	log.Info("[Liveness]: Set the kafka topic name")
 	experimentsDetails.RunID = common.GetRunID()
-	KafkaTopicName := "topic-" + experimentsDetails.RunID
+	liveness := &Liveness{
+		Topic:              "topic-" + experimentsDetails.RunID,
+		experimentsDetails: experimentsDetails,
+		acked:              map[int64]bool{},
+		seen:               map[int64]bool{},
+	}
 
-	log.Info("[Liveness]: Creating the kafka liveness pod")
-	err := CreateLivenessPod(experimentsDetails, KafkaTopicName, clients)
+	config, err := newClientConfig(experimentsDetails)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-
-	log.Info("[Liveness]: Confirm that the kafka liveness pod is running")
-	if err := status.CheckApplicationStatus(experimentsDetails.KafkaNamespace, "name=kafka-liveness-"+experimentsDetails.RunID, experimentsDetails.ChaoslibDetail.Timeout, experimentsDetails.ChaoslibDetail.Delay, clients); err != nil {
-		return "", errors.Errorf("Liveness pod status check failed, err: %v", err)
+	if liveness.admin, err = kafkaclient.NewClient(config); err != nil {
+		return nil, err
 	}
 
-	log.Info("[Liveness]: Obtain the leader broker ordinality for the topic (partition) created by kafka-liveness")
-	if experimentsDetails.KafkaInstanceName == "" {
-
-		execCommandDetails := litmusexec.PodDetails{}
-		command := append([]string{"/bin/sh", "-c"}, "kafka-topics --topic topic-"+experimentsDetails.RunID+" --describe --zookeeper "+experimentsDetails.ZookeeperService+":"+experimentsDetails.ZookeeperPort+" | grep -o 'Leader: [^[:space:]]*' | awk '{print $2}'")
-		litmusexec.SetExecCommandAttributes(&execCommandDetails, "kafka-liveness-"+experimentsDetails.RunID, "kafka-consumer", experimentsDetails.KafkaNamespace)
-		ordinality, err = litmusexec.Exec(&execCommandDetails, clients, command)
+	// the leader of the chosen topic partition is targeted, otherwise the leader of the liveness topic
+	// the replicas of the liveness topic are placed on the replicas of the chosen partition, with the same preferred leader
+	var assignment map[int32][]int32
+	var leaderID int32
+	if experimentsDetails.KafkaTopic != "" {
+		partition, err := describePartition(liveness.admin, experimentsDetails.KafkaTopic, int32(experimentsDetails.KafkaPartition))
 		if err != nil {
-			return "", errors.Errorf("Unable to get ordinality details, err: %v", err)
+			liveness.close()
+			return nil, err
 		}
-	} else {
-		// It will contains all the pod & container details required for exec command
-		execCommandDetails := litmusexec.PodDetails{}
+		leaderID = partition.Leader
+		assignment = map[int32][]int32{0: livenessReplicas(partition)}
+	}
 
-		command := append([]string{"/bin/sh", "-c"}, "kafka-topics --topic topic-"+experimentsDetails.RunID+" --describe --zookeeper "+experimentsDetails.ZookeeperService+":"+experimentsDetails.ZookeeperPort+"/"+experimentsDetails.KafkaInstanceName+" | grep -o 'Leader: [^[:space:]]*' | awk '{print $2}'")
-		litmusexec.SetExecCommandAttributes(&execCommandDetails, "kafka-liveness-"+experimentsDetails.RunID, "kafka-consumer", experimentsDetails.KafkaNamespace)
-		ordinality, err = litmusexec.Exec(&execCommandDetails, clients, command)
-		if err != nil {
-			return "", errors.Errorf("Unable to get ordinality details, err: %v", err)
-		}
+	log.Infof("[Liveness]: Creating the %v kafka liveness topic", liveness.Topic)
+	if err := liveness.admin.CreateTopic(liveness.Topic, 1, getReplicationFactor(experimentsDetails, liveness.admin), assignment, time.Duration(experimentsDetails.ChaoslibDetail.Timeout)*time.Second); err != nil {
+		liveness.close()
+		return nil, err
+	}
+	liveness.createdTopic = true
+
+	log.Info("[Liveness]: Confirm that the kafka liveness topic has the leader")
+	err = retry.
+		Times(uint(experimentsDetails.ChaoslibDetail.Timeout / experimentsDetails.ChaoslibDetail.Delay)).
+		Wait(time.Duration(experimentsDetails.ChaoslibDetail.Delay) * time.Second).
+		Try(func(attempt uint) error {
+			partition, err := describePartition(liveness.admin, liveness.Topic, 0)
+			if err != nil {
+				return err
+			}
+			if experimentsDetails.KafkaTopic == "" {
+				leaderID = partition.Leader
+			}
+			return nil
+		})
+	if err != nil {
+		LivenessCleanup(experimentsDetails, liveness)
+		return nil, errors.Errorf("Liveness topic status check failed, err: %v", err)
 	}
 
 	log.Info("[Liveness]: Determine the leader broker pod name")
+	leader, ok := liveness.admin.Brokers()[leaderID]
+	if !ok {
+		LivenessCleanup(experimentsDetails, liveness)
+		return nil, errors.Errorf("leader broker %v is not found in the cluster", leaderID)
+	}
+	if liveness.Leader, err = getBrokerPod(experimentsDetails, leader, clients); err != nil {
+		LivenessCleanup(experimentsDetails, liveness)
+		return nil, err
+	}
+
+	log.Info("[Liveness]: Starting the kafka liveness producer & consumer")
+	if err := liveness.start(config); err != nil {
+		LivenessCleanup(experimentsDetails, liveness)
+		return nil, err
+	}
+
+	log.Info("[Liveness]: Confirm that the kafka liveness stream is established")
+	if err := liveness.waitForConsumption(time.Now()); err != nil {
+		LivenessCleanup(experimentsDetails, liveness)
+		return nil, err
+	}
+	return liveness, nil
+}
+
+// describePartition returns the metadata of the partition, once it has the leader
+func describePartition(admin *kafkaclient.Client, topic string, partition int32) (kafkaclient.PartitionMetadata, error) {
+
+	topicMetadata, err := admin.DescribeTopic(topic)
+	if err != nil {
+		return kafkaclient.PartitionMetadata{}, err
+	}
+	partitionMetadata, err := topicMetadata.Partition(partition)
+	if err != nil {
+		return kafkaclient.PartitionMetadata{}, err
+	}
+	if partitionMetadata.Err != nil || partitionMetadata.Leader < 0 {
+		return kafkaclient.PartitionMetadata{}, errors.Errorf("partition %v of %v topic has no leader, err: %v", partition, topic, partitionMetadata.Err)
+	}
+	log.Infof("[Info]: Partition %v of %v topic has the leader %v, replicas %v and isr %v", partition, topic, partitionMetadata.Leader, partitionMetadata.Replicas, partitionMetadata.ISR)
+	return partitionMetadata, nil
+}
+
+// livenessReplicas returns the replicas of the partition, starting with its leader
+func livenessReplicas(partition kafkaclient.PartitionMetadata) []int32 {
+	replicas := []int32{partition.Leader}
+	for _, replica := range partition.Replicas {
+		if replica != partition.Leader {
+			replicas = append(replicas, replica)
+		}
+	}
+	return replicas
+}
+
+// getReplicationFactor returns the replication factor of the liveness topic
+// it defaults to three, bounded by the number of brokers
+func getReplicationFactor(experimentsDetails *experimentTypes.ExperimentDetails, admin *kafkaclient.Client) int16 {
+	if replicationFactor, err := strconv.Atoi(experimentsDetails.KafkaRepliationFactor); err == nil && replicationFactor > 0 {
+		return int16(replicationFactor)
+	}
+	if brokers := len(admin.Brokers()); brokers < 3 {
+		return int16(brokers)
+	}
+	return 3
+}
+
+// getBrokerPod derive the pod of the broker from its advertised host, which is either the pod ip or the pod dns name
+// the broker ids of the statefulsets generally match the pod ordinality, which is used as the fallback
+func getBrokerPod(experimentsDetails *experimentTypes.ExperimentDetails, broker kafkaclient.Broker, clients clients.ClientSets) (string, error) {
+
 	podList, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.KafkaNamespace).List(metav1.ListOptions{LabelSelector: experimentsDetails.KafkaLabel})
 	if err != nil {
 		return "", errors.Errorf("unable to find the pods with matching labels, err: %v", err)
 	}
 
+	if broker.Host != "" {
+		hostname := strings.Split(broker.Host, ".")[0]
+		for _, pod := range podList.Items {
+			if pod.Name == hostname || pod.Status.PodIP == broker.Host {
+				return pod.Name, nil
+			}
+		}
+	}
 	for _, pod := range podList.Items {
-		if strings.ContainsAny(pod.Name, ordinality) {
+		if strings.HasSuffix(pod.Name, "-"+strconv.Itoa(int(broker.ID))) {
 			return pod.Name, nil
 		}
 	}
-
-	return "", errors.Errorf("No kafka pod found with %v ordinality", ordinality)
+	return "", errors.Errorf("No kafka pod found for the %v broker with %v host", broker.ID, broker.Host)
 }
 
-// CreateLivenessPod creates the kafka liveness pod
-func CreateLivenessPod(experimentsDetails *experimentTypes.ExperimentDetails, KafkaTopicName string, clients clients.ClientSets) error {
+// newClientConfig returns the connection details of the kafka cluster
+func newClientConfig(experimentsDetails *experimentTypes.ExperimentDetails) (kafkaclient.Config, error) {
 
-	LivenessPod := &corev1.Pod{
-		TypeMeta: metav1.TypeMeta{
-			Kind:       "Pod",
-			APIVersion: "v1",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "kafka-liveness-" + experimentsDetails.RunID,
-			Labels: map[string]string{
-				"app":                       "kafka-liveness",
-				"name":                      "kafka-liveness-" + experimentsDetails.RunID,
-				"app.kubernetes.io/part-of": "litmus",
-			},
-		},
-		Spec: corev1.PodSpec{
-			InitContainers: []corev1.Container{
-				{
-					Name:  "kafka-topic-creator",
-					Image: experimentsDetails.KafkaLivenessImage,
-					Command: []string{
-						"sh",
-						"-c",
-						"./topic.sh",
-					},
-					Env: []corev1.EnvVar{
-						{
-							Name:  "TOPIC_NAME",
-							Value: KafkaTopicName,
-						},
-						{
-							Name:  "KAFKA_INSTANCE_NAME",
-							Value: experimentsDetails.KafkaInstanceName,
-						},
-						{
-							Name:  "ZOOKEEPER_SERVICE",
-							Value: experimentsDetails.ZookeeperService,
-						},
-						{
-							Name:  "ZOOKEEPER_PORT",
-							Value: experimentsDetails.ZookeeperPort,
-						},
-						{
-							Name:  "REPLICATION_FACTOR",
-							Value: experimentsDetails.KafkaRepliationFactor,
-						},
-					},
-					ImagePullPolicy: corev1.PullPolicy("Always"),
-				},
-			},
-			Containers: []corev1.Container{
-				{
-					Name:  "kafka-producer",
-					Image: experimentsDetails.KafkaLivenessImage,
-					Command: []string{
-						"sh",
-						"-c",
-						"stdbuf -oL ./producer.sh",
-					},
-					Env: []corev1.EnvVar{
-						{
-							Name:  "TOPIC_NAME",
-							Value: KafkaTopicName,
-						},
-						{
-							Name:  "KAFKA_SERVICE",
-							Value: experimentsDetails.KafkaService,
-						},
-						{
-							Name:  "KAFKA_PORT",
-							Value: experimentsDetails.KafkaPort,
-						},
-					},
-					ImagePullPolicy: corev1.PullPolicy("Always"),
-				},
-				{
-					Name:  "kafka-consumer",
-					Image: experimentsDetails.KafkaLivenessImage,
-					Command: []string{
-						"sh",
-						"-c",
-						"stdbuf -oL ./consumer.sh",
-					},
-					Env: []corev1.EnvVar{
-						{
-							Name:  "KAFKA_CONSUMER_TIMEOUT",
-							Value: strconv.Itoa(experimentsDetails.KafkaConsumerTimeout),
-						},
-						{
-							Name:  "TOPIC_NAME",
-							Value: KafkaTopicName,
-						},
-						{
-							Name:  "KAFKA_SERVICE",
-							Value: experimentsDetails.KafkaService,
-						},
-						{
-							Name:  "KAFKA_PORT",
-							Value: experimentsDetails.KafkaPort,
-						},
-					},
-					ImagePullPolicy: corev1.PullPolicy("Always"),
-				},
-			},
-			RestartPolicy: corev1.RestartPolicy("Never"),
+	servers := experimentsDetails.KafkaBootstrapServers
+	if servers == "" {
+		if experimentsDetails.KafkaService == "" {
+			return kafkaclient.Config{}, errors.New("neither KAFKA_BOOTSTRAP_SERVERS nor KAFKA_SERVICE is provided")
+		}
+		servers = experimentsDetails.KafkaService + ":" + experimentsDetails.KafkaPort
+	}
+
+	config := kafkaclient.Config{
+		ClientID: "litmus-" + experimentsDetails.ChaoslibDetail.ExperimentName,
+		SASL: kafkaclient.SASLConfig{
+			Mechanism: experimentsDetails.KafkaSASLMechanism,
+			Username:  experimentsDetails.KafkaSASLUsername,
+			Password:  experimentsDetails.KafkaSASLPassword,
 		},
 	}
+	for _, server := range strings.Split(servers, ",") {
+		if server = strings.TrimSpace(server); server != "" {
+			config.BootstrapServers = append(config.BootstrapServers, server)
+		}
+	}
+
+	if experimentsDetails.KafkaTLSEnabled {
+		config.TLS = &tls.Config{InsecureSkipVerify: experimentsDetails.KafkaTLSSkipVerify}
+		if experimentsDetails.KafkaTLSCACert != "" {
+			ca, err := ioutil.ReadFile(experimentsDetails.KafkaTLSCACert)
+			if err != nil {
+				return kafkaclient.Config{}, errors.Errorf("Unable to read the kafka ca certificate, err: %v", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(ca) {
+				return kafkaclient.Config{}, errors.Errorf("no certificates found in %v", experimentsDetails.KafkaTLSCACert)
+			}
+			config.TLS.RootCAs = pool
+		}
+	}
+	return config, nil
+}
 
-	_, err := clients.KubeClient.CoreV1().Pods(experimentsDetails.KafkaNamespace).Create(LivenessPod)
+// start connects the producer & consumer and starts streaming from the end of the liveness topic
+func (l *Liveness) start(config kafkaclient.Config) error {
+
+	var err error
+	if l.producer, err = kafkaclient.NewClient(config); err != nil {
+		return err
+	}
+	if l.consumer, err = kafkaclient.NewClient(config); err != nil {
+		return err
+	}
+	offset, err := l.consumer.ListOffset(l.Topic, 0, kafkaclient.OffsetLatest)
 	if err != nil {
-		return errors.Errorf("Unable to create Liveness pod, err: %v", err)
+		return errors.Errorf("Unable to get the offset of the liveness topic, err: %v", err)
 	}
+
+	l.stopProducer, l.stopConsumer = make(chan struct{}), make(chan struct{})
+	l.producerDone, l.consumerDone = make(chan struct{}), make(chan struct{})
+	l.lastAcked = time.Now()
+	go l.produce()
+	go l.consume(offset)
 	return nil
 }
+
+// produce sends the sequenced messages, the failed message is resent until acknowledged
+// the resent messages may get duplicated, if the broker failed after appending them
+func (l *Liveness) produce() {
+
+	defer close(l.producerDone)
+	ticker := time.NewTicker(livenessProduceInterval)
+	defer ticker.Stop()
+
+	for seq := int64(0); ; {
+		select {
+		case <-l.stopProducer:
+			return
+		case <-ticker.C:
+		}
+
+		message := kafkaclient.Message{
+			Key:   []byte(l.experimentsDetails.RunID + "-" + strconv.FormatInt(seq, 10)),
+			Value: []byte(strconv.FormatInt(time.Now().UnixNano(), 10)),
+		}
+		_, err := l.producer.Produce(l.Topic, 0, []kafkaclient.Message{message})
+
+		l.mu.Lock()
+		l.produced++
+		if err != nil {
+			l.produceErrors++
+			l.mu.Unlock()
+			log.Warnf("[Liveness]: Unable to produce the %v message, err: %v", seq, err)
+			continue
+		}
+		now := time.Now()
+		if stall := now.Sub(l.lastAcked); stall > l.maxStall {
+			l.maxStall = stall
+		}
+		l.lastAcked = now
+		l.acked[seq] = true
+		l.mu.Unlock()
+		seq++
+	}
+}
+
+// consume reads the liveness topic from the given offset and tracks the duplicated messages & the latency
+func (l *Liveness) consume(offset int64) {
+
+	defer close(l.consumerDone)
+	prefix := l.experimentsDetails.RunID + "-"
+
+	for {
+		select {
+		case <-l.stopConsumer:
+			return
+		default:
+		}
+
+		messages, next, err := l.consumer.Fetch(l.Topic, 0, offset, 500*time.Millisecond)
+		if err != nil {
+			// the log is truncated to the new leader, upon the unclean leader election
+			if err == kafkaclient.ErrOffsetOutOfRange {
+				if latest, err := l.consumer.ListOffset(l.Topic, 0, kafkaclient.OffsetLatest); err == nil {
+					log.Warnf("[Liveness]: Liveness topic is truncated, resuming from %v offset", latest)
+					offset = latest
+				}
+			}
+			select {
+			case <-l.stopConsumer:
+				return
+			case <-time.After(500 * time.Millisecond):
+			}
+			continue
+		}
+		offset = next
+
+		now := time.Now()
+		l.mu.Lock()
+		for _, message := range messages {
+			key := string(message.Key)
+			if !strings.HasPrefix(key, prefix) {
+				continue
+			}
+			seq, err := strconv.ParseInt(strings.TrimPrefix(key, prefix), 10, 64)
+			if err != nil {
+				continue
+			}
+			l.consumed++
+			l.lastConsumed = now
+			if l.seen[seq] {
+				l.duplicated++
+				continue
+			}
+			l.seen[seq] = true
+			if producedAt, err := strconv.ParseInt(string(message.Value), 10, 64); err == nil {
+				latency := now.Sub(time.Unix(0, producedAt))
+				l.totalLatency += latency
+				if latency > l.maxLatency {
+					l.maxLatency = latency
+				}
+			}
+		}
+		l.mu.Unlock()
+	}
+}
+
+// waitForConsumption waits for the message to be consumed after the given time, within the consumer timeout
+func (l *Liveness) waitForConsumption(since time.Time) error {
+
+	timeout := time.Duration(l.experimentsDetails.KafkaConsumerTimeout) * time.Millisecond
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(livenessProduceInterval) {
+		l.mu.Lock()
+		lastConsumed := l.lastConsumed
+		l.mu.Unlock()
+		if lastConsumed.After(since) {
+			return nil
+		}
+	}
+	return errors.Errorf("no message is consumed from the %v liveness topic within %v", l.Topic, timeout)
+}
+
+// stop stops the producer, and the consumer once it has consumed all the acknowledged messages or the consumer timeout elapsed
+func (l *Liveness) stop() {
+
+	l.stopOnce.Do(func() {
+		if l.stopProducer == nil {
+			return
+		}
+		close(l.stopProducer)
+		<-l.producerDone
+
+		timeout := time.Duration(l.experimentsDetails.KafkaConsumerTimeout) * time.Millisecond
+		for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(livenessProduceInterval) {
+			if l.Metrics().Lost == 0 {
+				break
+			}
+		}
+		close(l.stopConsumer)
+		<-l.consumerDone
+	})
+}
+
+// Metrics returns the outcome of the liveness stream so far
+func (l *Liveness) Metrics() experimentTypes.LivenessMetrics {
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	metrics := experimentTypes.LivenessMetrics{
+		Topic:           l.Topic,
+		Produced:        l.produced,
+		Acknowledged:    len(l.acked),
+		ProduceErrors:   l.produceErrors,
+		Consumed:        l.consumed,
+		Duplicated:      l.duplicated,
+		MaxLatency:      float64(l.maxLatency) / float64(time.Millisecond),
+		MaxProduceStall: l.maxStall.Seconds(),
+	}
+	for seq := range l.acked {
+		if !l.seen[seq] {
+			metrics.Lost++
+		}
+	}
+	if len(l.seen) != 0 {
+		metrics.AvgLatency = float64(l.totalLatency) / float64(len(l.seen)) / float64(time.Millisecond)
+	}
+	return metrics
+}
+
+// close closes the connections to the kafka brokers
+func (l *Liveness) close() {
+	for _, client := range []*kafkaclient.Client{l.admin, l.producer, l.consumer} {
+		if client != nil {
+			client.Close()
+		}
+	}
+}
@@ -10,19 +10,44 @@ type ExperimentDetails struct {
 	ExperimentName        string
 	KafkaKind             string
 	KafkaLivenessStream   string
-	KafkaLivenessImage    string
 	KafkaConsumerTimeout  int
-	KafkaInstanceName     string
 	KafkaNamespace        string
 	KafkaLabel            string
 	KafkaBroker           string
 	KafkaRepliationFactor string
 	KafkaService          string
 	KafkaPort             string
+	KafkaBootstrapServers string
+	KafkaTopic            string
+	KafkaPartition        int
+	KafkaSASLMechanism    string
+	KafkaSASLUsername     string
+	KafkaSASLPassword     string
+	KafkaTLSEnabled       bool
+	KafkaTLSCACert        string
+	KafkaTLSSkipVerify    bool
+	KafkaChaosMode        string
+	KafkaISRAffectedPerc  int
+	KafkaRecoveryTimeout  int
+	KafkaConsumerGroup    string
+	KafkaConsumerLagLimit int64
+	ConsumerLagBaseline   int64
 	ZookeeperNamespace    string
 	ZookeeperLabel        string
-	ZookeeperService      string
-	ZookeeperPort         string
 	Lib                   string
 	RunID                 string
 }
+
+// LivenessMetrics contains the outcome of the liveness stream across the broker failure
+type LivenessMetrics struct {
+	Topic           string  `json:"topic"`
+	Produced        int     `json:"produced"`
+	Acknowledged    int     `json:"acknowledged"`
+	ProduceErrors   int     `json:"produceErrors"`
+	Consumed        int     `json:"consumed"`
+	Lost            int     `json:"lost"`
+	Duplicated      int     `json:"duplicated"`
+	AvgLatency      float64 `json:"avgLatencyMillis"`
+	MaxLatency      float64 `json:"maxLatencyMillis"`
+	MaxProduceStall float64 `json:"maxProduceStallSeconds"`
+}
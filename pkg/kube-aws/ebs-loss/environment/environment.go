@@ -11,8 +11,8 @@ import (
 )
 
 //GetENV fetches all the env variables from the runner pod
-func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
-	experimentDetails.ExperimentName = Getenv("EXPERIMENT_NAME", "ebs-loss")
+func GetENV(experimentDetails *experimentTypes.ExperimentDetails, expName string) {
+	experimentDetails.ExperimentName = Getenv("EXPERIMENT_NAME", expName)
 	experimentDetails.ChaosNamespace = Getenv("CHAOS_NAMESPACE", "litmus")
 	experimentDetails.EngineName = Getenv("CHAOSENGINE", "")
 	experimentDetails.ChaosDuration, _ = strconv.Atoi(Getenv("TOTAL_CHAOS_DURATION", "60"))
@@ -27,11 +27,16 @@ func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
 	experimentDetails.AuxiliaryAppInfo = Getenv("AUXILIARY_APPINFO", "")
 	experimentDetails.Delay, _ = strconv.Atoi(Getenv("STATUS_CHECK_DELAY", "2"))
 	experimentDetails.Timeout, _ = strconv.Atoi(Getenv("STATUS_CHECK_TIMEOUT", "180"))
-	experimentDetails.Ec2InstanceID = Getenv("EC2_INSTANCE_ID", "")
 	experimentDetails.EBSVolumeID = Getenv("EBS_VOL_ID", "")
-	experimentDetails.DeviceName = Getenv("DEVICE_NAME", "")
 	experimentDetails.Region = Getenv("REGION", "")
+	experimentDetails.AssumeRoleARN = Getenv("ASSUME_ROLE_ARN", "")
+	experimentDetails.ExternalID = Getenv("EXTERNAL_ID", "")
+	experimentDetails.EndpointURL = Getenv("AWS_ENDPOINT_URL", "")
 	experimentDetails.TargetContainer = Getenv("TARGET_CONTAINER", "")
+	experimentDetails.ChaosInterval, _ = strconv.Atoi(Getenv("CHAOS_INTERVAL", "0"))
+	experimentDetails.Sequence = Getenv("SEQUENCE", "parallel")
+	experimentDetails.VolumeTag = Getenv("EBS_VOLUME_TAG", "")
+	experimentDetails.VolumeAffectedPerc, _ = strconv.Atoi(Getenv("VOLUME_AFFECTED_PERC", "0"))
 }
 
 // Getenv fetch the env and set the default value, if any
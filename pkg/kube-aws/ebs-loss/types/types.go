@@ -22,10 +22,16 @@ type ExperimentDetails struct {
 	RunID              string
 	Timeout            int
 	Delay              int
-	Ec2InstanceID      string
 	EBSVolumeID        string
-	DeviceName         string
 	Region             string
+	AssumeRoleARN      string
+	ExternalID         string
+	EndpointURL        string
 	LIBImagePullPolicy string
 	TargetContainer    string
+	ChaosInterval      int
+	Sequence           string
+	VolumeTag          string
+	VolumeAffectedPerc int
+	TargetVolumeIDList []string
 }
@@ -30,6 +30,9 @@ func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
 	experimentDetails.Timeout, _ = strconv.Atoi(Getenv("STATUS_CHECK_TIMEOUT", "180"))
 	experimentDetails.Ec2InstanceID = Getenv("EC2_INSTANCE_ID", "")
 	experimentDetails.Region = Getenv("REGION", "")
+	experimentDetails.AssumeRoleARN = Getenv("ASSUME_ROLE_ARN", "")
+	experimentDetails.ExternalID = Getenv("EXTERNAL_ID", "")
+	experimentDetails.EndpointURL = Getenv("AWS_ENDPOINT_URL", "")
 	experimentDetails.ManagedNodegroup = Getenv("MANAGED_NODEGROUP", "disable")
 	experimentDetails.Sequence = Getenv("SEQUENCE", "parallel")
 	experimentDetails.TargetContainer = Getenv("TARGET_CONTAINER", "")
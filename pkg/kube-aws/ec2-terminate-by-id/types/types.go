@@ -24,6 +24,9 @@ type ExperimentDetails struct {
 	Delay              int
 	Ec2InstanceID      string
 	Region             string
+	AssumeRoleARN      string
+	ExternalID         string
+	EndpointURL        string
 	ManagedNodegroup   string
 	Sequence           string
 	ActiveNodes        int
@@ -29,6 +29,9 @@ func GetENV(experimentDetails *experimentTypes.ExperimentDetails) {
 	experimentDetails.Delay, _ = strconv.Atoi(Getenv("STATUS_CHECK_DELAY", "2"))
 	experimentDetails.Timeout, _ = strconv.Atoi(Getenv("STATUS_CHECK_TIMEOUT", "180"))
 	experimentDetails.Region = Getenv("REGION", "")
+	experimentDetails.AssumeRoleARN = Getenv("ASSUME_ROLE_ARN", "")
+	experimentDetails.ExternalID = Getenv("EXTERNAL_ID", "")
+	experimentDetails.EndpointURL = Getenv("AWS_ENDPOINT_URL", "")
 	experimentDetails.ManagedNodegroup = Getenv("MANAGED_NODEGROUP", "disable")
 	experimentDetails.InstanceTag = Getenv("INSTANCE_TAG", "")
 	experimentDetails.InstanceAffectedPerc, _ = strconv.Atoi(Getenv("INSTANCE_AFFECTED_PERC", "0"))
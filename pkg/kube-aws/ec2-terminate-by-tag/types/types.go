@@ -24,6 +24,9 @@ type ExperimentDetails struct {
 	Delay                int
 	InstanceTag          string
 	Region               string
+	AssumeRoleARN        string
+	ExternalID           string
+	EndpointURL          string
 	InstanceAffectedPerc int
 	ManagedNodegroup     string
 	Sequence             string
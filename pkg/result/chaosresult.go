@@ -1,6 +1,7 @@
 package result
 
 import (
+	"encoding/json"
 	"reflect"
 	"strconv"
 	"strings"
@@ -81,9 +82,10 @@ func InitializeChaosResult(chaosDetails *types.ChaosDetails, clients clients.Cli
 	probeStatus := GetProbeStatus(resultDetails)
 	chaosResult := &v1alpha1.ChaosResult{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      resultDetails.Name,
-			Namespace: chaosDetails.ChaosNamespace,
-			Labels:    chaosResultLabel,
+			Name:        resultDetails.Name,
+			Namespace:   chaosDetails.ChaosNamespace,
+			Labels:      chaosResultLabel,
+			Annotations: resultDetails.Annotations,
 		},
 		Spec: v1alpha1.ChaosResultSpec{
 			EngineName:     chaosDetails.EngineName,
@@ -153,6 +155,12 @@ func PatchChaosResult(result *v1alpha1.ChaosResult, clients clients.ClientSets,
 	result.Status.ExperimentStatus.FailStep = resultDetails.FailStep
 	// for existing chaos result resource it will patch the label
 	result.ObjectMeta.Labels = chaosResultLabel
+	for key, value := range resultDetails.Annotations {
+		if result.ObjectMeta.Annotations == nil {
+			result.ObjectMeta.Annotations = map[string]string{}
+		}
+		result.ObjectMeta.Annotations[key] = value
+	}
 	result.Status.ProbeStatus = GetProbeStatus(resultDetails)
 
 	switch strings.ToLower(resultDetails.Phase) {
@@ -212,6 +220,13 @@ func SetResultUID(resultDetails *types.ResultDetails, clients clients.ClientSets
 //RecordAfterFailure update the chaosresult and create the summary events
 func RecordAfterFailure(chaosDetails *types.ChaosDetails, resultDetails *types.ResultDetails, failStep string, clients clients.ClientSets, eventsDetails *types.EventDetails) {
 
+	// add the diagnostics summary of the failed helper pods, if any
+	if chaosDetails.HelperFailure != "" {
+		failStep = failStep + ", " + chaosDetails.HelperFailure
+	}
+	// add the chaos left unreverted, ex: by the lost helper pods, which needs the manual revert
+	failStep = WithPendingReverts(failStep, chaosDetails.PendingReverts, resultDetails)
+
 	// update the chaos result
 	types.SetResultAfterCompletion(resultDetails, "Fail", "Completed", failStep)
 	ChaosResult(chaosDetails, clients, resultDetails, "EOT")
@@ -229,6 +244,22 @@ func RecordAfterFailure(chaosDetails *types.ChaosDetails, resultDetails *types.R
 
 }
 
+// WithPendingReverts adds the chaos left to be reverted manually to the fail step
+// and to the litmuschaos.io/pending-reverts annotation of the chaosresult
+func WithPendingReverts(failStep string, pendingReverts []string, resultDetails *types.ResultDetails) string {
+
+	if len(pendingReverts) == 0 {
+		return failStep
+	}
+	if value, err := json.Marshal(pendingReverts); err == nil {
+		if resultDetails.Annotations == nil {
+			resultDetails.Annotations = map[string]string{}
+		}
+		resultDetails.Annotations["litmuschaos.io/pending-reverts"] = string(value)
+	}
+	return failStep + ", manual revert pending for: [" + strings.Join(pendingReverts, "; ") + "]"
+}
+
 // updateHistory initialise the history for the older results
 func updateHistory(result *v1alpha1.ChaosResult) {
 	if reflect.DeepEqual(result.Status.History, v1alpha1.HistoryDetails{}) {
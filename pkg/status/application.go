@@ -226,14 +226,15 @@ func validateAllContainerStatus(podName string, ContainerStatuses []v1.Container
 }
 
 // WaitForCompletion wait until the completion of pod
-func WaitForCompletion(appNs, appLabel string, clients clients.ClientSets, duration int, containerName string) (string, error) {
+// the pods are checked at every interval, till the duration number of intervals
+func WaitForCompletion(appNs, appLabel string, clients clients.ClientSets, duration int, interval time.Duration, containerName string) (string, error) {
 	var podStatus string
 	failedPods := 0
 	// It will wait till the completion of target container
 	// it will retries until the target container completed or met the timeout(chaos duration)
 	err := retry.
 		Times(uint(duration)).
-		Wait(1 * time.Second).
+		Wait(interval).
 		Try(func(attempt uint) error {
 			podList, err := clients.KubeClient.CoreV1().Pods(appNs).List(metav1.ListOptions{LabelSelector: appLabel})
 			if err != nil || len(podList.Items) == 0 {
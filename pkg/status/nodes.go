@@ -84,3 +84,32 @@ func CheckNodeNotReadyState(nodeName string, timeout, delay int, clients clients
 			return nil
 		})
 }
+
+// CheckNodeCondition check for the given condition of the node to be in the expected status
+// ex: DiskPressure condition to be True during the disk fill chaos
+func CheckNodeCondition(nodeName string, conditionType apiv1.NodeConditionType, expectedStatus apiv1.ConditionStatus, timeout, delay int, clients clients.ClientSets) error {
+	return retry.
+		Times(uint(timeout / delay)).
+		Wait(time.Duration(delay) * time.Second).
+		Try(func(attempt uint) error {
+			node, err := clients.KubeClient.CoreV1().Nodes().Get(nodeName, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			conditionStatus := apiv1.ConditionUnknown
+			for _, condition := range node.Status.Conditions {
+				if condition.Type == conditionType {
+					conditionStatus = condition.Status
+					break
+				}
+			}
+			// It will retries until the condition is in the expected status
+			if conditionStatus != expectedStatus {
+				return errors.Errorf("%v condition of %v node is %v, expected %v", conditionType, nodeName, conditionStatus, expectedStatus)
+			}
+			log.InfoWithValues("The Node status are as follows", logrus.Fields{
+				"Node": node.Name, string(conditionType): conditionStatus})
+
+			return nil
+		})
+}
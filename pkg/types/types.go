@@ -1,6 +1,8 @@
 package types
 
 import (
+	"time"
+
 	clientTypes "k8s.io/apimachinery/pkg/types"
 )
 
@@ -33,6 +35,8 @@ type ResultDetails struct {
 	ProbeDetails     []ProbeDetails
 	PassedProbeCount int
 	ProbeArtifacts   map[string]ProbeArtifact
+	// Annotations are added in the chaosresult, to record the experiment specific observations
+	Annotations map[string]string
 }
 
 // ProbeArtifact contains the probe artifacts
@@ -79,6 +83,21 @@ type ChaosDetails struct {
 	JobCleanupPolicy     string
 	ProbeImagePullPolicy string
 	Randomness           bool
+	// DelayUnit is the unit of the Timeout, the Delay & the ChaosDuration of the helper pods, it defaults to a second if unset
+	DelayUnit time.Duration
+	// HelperSeccompProfile and HelperAppArmorProfile are the profiles applied on the helper pods, if provided
+	HelperSeccompProfile  string
+	HelperAppArmorProfile string
+	// HelperTolerations contains the taints tolerated by the helper pods, either all or the comma separated key=value:effect tolerations
+	HelperTolerations string
+	// HelperPriorityClass is the priority class of the helper pods, if provided
+	HelperPriorityClass string
+	// HelperLogTailLines is the number of log lines of the failed helper pods, collected in the diagnostics
+	HelperLogTailLines int
+	// HelperFailure contains the summary of the failed helper pods and the configmap containing their diagnostics
+	HelperFailure string
+	// PendingReverts describes the chaos which is left to be reverted manually, ex: by the lost helper pods, along with their targets
+	PendingReverts []string
 }
 
 // AppDetails contains all the application related envs
@@ -0,0 +1,238 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/pkg/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+//GetContainerID extract out the container id of the target container
+// for the docker runtime it returns the pause container of the pod, which holds the network namespace of the pod
+func GetContainerID(appNamespace, targetPod, targetContainer, runtime, socketPath string, clients clients.ClientSets) (string, error) {
+
+	var containerID string
+	switch runtime {
+	case "docker":
+		host := "unix://" + socketPath
+		// deriving the container id of the pause container
+		cmd := "sudo docker --host " + host + " ps | grep k8s_POD_" + targetPod + "_" + appNamespace + " | awk '{print $1}'"
+		out, err := exec.Command("/bin/sh", "-c", cmd).CombinedOutput()
+		if err != nil {
+			log.Error(fmt.Sprintf("[docker]: Failed to run docker ps command: %s", string(out)))
+			return "", err
+		}
+		containerID = strings.TrimSpace(string(out))
+	case "containerd", "crio":
+		var err error
+		containerID, err = GetTargetContainerID(appNamespace, targetPod, targetContainer, clients)
+		if err != nil {
+			return "", err
+		}
+	default:
+		return "", errors.Errorf("%v container runtime not suported", runtime)
+	}
+	log.Infof("containerid: %v", containerID)
+
+	return containerID, nil
+}
+
+//GetTargetContainerID extract out the container id of the target container from the pod status
+// unlike GetContainerID, it returns the target container itself for all the runtimes
+func GetTargetContainerID(appNamespace, targetPod, targetContainer string, clients clients.ClientSets) (string, error) {
+
+	pod, err := clients.KubeClient.CoreV1().Pods(appNamespace).Get(targetPod, v1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	// filtering out the container id from the details of containers inside containerStatuses of the given pod
+	// container id is present in the form of <runtime>://<container-id>
+	for _, container := range pod.Status.ContainerStatuses {
+		if container.Name == targetContainer {
+			if container.ContainerID == "" {
+				return "", errors.Errorf("%v container of %v pod is not started yet", targetContainer, targetPod)
+			}
+			return strings.Split(container.ContainerID, "//")[1], nil
+		}
+	}
+	return "", errors.Errorf("%v container not found in %v pod", targetContainer, targetPod)
+}
+
+//GetPID extract out the PID of the target container
+// for the containerd runtime it is derived from the network namespace of the container
+func GetPID(runtime, containerID, socketPath string) (int, error) {
+
+	out, err := inspectContainer(runtime, containerID, socketPath)
+	if err != nil {
+		return 0, err
+	}
+	// parsing data from the json output of inspect command
+	PID, err := parsePIDFromJSON(out, runtime)
+	if err != nil {
+		log.Errorf(fmt.Sprintf("[cri]: Failed to parse json from inspect output: %s", string(out)))
+		return 0, err
+	}
+
+	log.Info(fmt.Sprintf("[cri]: Container ID=%s has process PID=%d", containerID, PID))
+
+	return PID, nil
+}
+
+//GetContainerPID extract out the PID of the init process of the target container itself
+// it is used to enter the namespaces of the target container, other than its network namespace
+func GetContainerPID(runtime, containerID, socketPath string) (int, error) {
+
+	out, err := inspectContainer(runtime, containerID, socketPath)
+	if err != nil {
+		return 0, err
+	}
+
+	var PID int
+	switch runtime {
+	case "docker":
+		var resp []DockerInspectResponse
+		if err := json.Unmarshal(out, &resp); err != nil || len(resp) == 0 {
+			return 0, errors.Errorf("[docker]: Failed to parse json from docker inspect output: %s", string(out))
+		}
+		PID = resp[0].State.PID
+	default:
+		var resp CrictlInspectResponse
+		if err := json.Unmarshal(out, &resp); err != nil {
+			return 0, errors.Errorf("[cri]: Failed to parse json from crictl output: %s", string(out))
+		}
+		PID = resp.Info.PID
+		if PID == 0 {
+			// older crio versions report the pid at the top level of the inspect output
+			var info InfoDetails
+			if err := json.Unmarshal(out, &info); err == nil {
+				PID = info.PID
+			}
+		}
+	}
+	if PID == 0 {
+		return 0, errors.Errorf("[cri]: No running target container found, pid: %d", PID)
+	}
+
+	log.Info(fmt.Sprintf("[cri]: Container ID=%s has process PID=%d", containerID, PID))
+
+	return PID, nil
+}
+
+// inspectContainer returns the inspect output of the container from the container runtime
+func inspectContainer(runtime, containerID, socketPath string) ([]byte, error) {
+
+	switch runtime {
+	case "docker":
+		host := "unix://" + socketPath
+		// deriving pid from the inspect out of target container
+		out, err := exec.Command("sudo", "docker", "--host", host, "inspect", containerID).CombinedOutput()
+		if err != nil {
+			log.Error(fmt.Sprintf("[docker]: Failed to run docker inspect: %s", string(out)))
+			return nil, err
+		}
+		return out, nil
+	case "containerd", "crio":
+		// deriving pid from the inspect out of target container
+		endpoint := "unix://" + socketPath
+		out, err := exec.Command("sudo", "crictl", "-i", endpoint, "-r", endpoint, "inspect", containerID).CombinedOutput()
+		if err != nil {
+			log.Error(fmt.Sprintf("[cri]: Failed to run crictl: %s", string(out)))
+			return nil, err
+		}
+		return out, nil
+	default:
+		return nil, errors.Errorf("%v container runtime not suported", runtime)
+	}
+}
+
+// CrictlInspectResponse JSON representation of crictl inspect command output
+// in crio, pid is present inside pid attribute of inspect output
+// in containerd, pid is present inside `info.pid` of inspect output
+type CrictlInspectResponse struct {
+	Info InfoDetails `json:"info"`
+}
+
+// InfoDetails JSON representation of crictl inspect command output
+type InfoDetails struct {
+	RuntimeSpec RuntimeDetails `json:"runtimeSpec"`
+	PID         int            `json:"pid"`
+}
+
+// RuntimeDetails contains runtime details
+type RuntimeDetails struct {
+	Linux LinuxAttributes `json:"linux"`
+}
+
+// LinuxAttributes contains all the linux attributes
+type LinuxAttributes struct {
+	Namespaces []Namespace `json:"namespaces"`
+}
+
+// Namespace contains linux namespace details
+type Namespace struct {
+	Type string `json:"type"`
+	Path string `json:"path"`
+}
+
+// DockerInspectResponse JSON representation of docker inspect command output
+type DockerInspectResponse struct {
+	State StateDetails `json:"state"`
+}
+
+// StateDetails JSON representation of docker inspect command output
+type StateDetails struct {
+	PID int `json:"pid"`
+}
+
+//parsePIDFromJSON extract the pid from the json output
+func parsePIDFromJSON(j []byte, runtime string) (int, error) {
+	var pid int
+	// namespaces are present inside `info.runtimeSpec.linux.namespaces` of inspect output
+	// linux namespace of type network contains pid, in the form of `/proc/<pid>/ns/net`
+	switch runtime {
+	case "docker":
+		// in docker, pid is present inside state.pid attribute of inspect output
+		var resp []DockerInspectResponse
+		if err := json.Unmarshal(j, &resp); err != nil {
+			return 0, err
+		}
+		pid = resp[0].State.PID
+	case "containerd":
+		var resp CrictlInspectResponse
+		if err := json.Unmarshal(j, &resp); err != nil {
+			return 0, err
+		}
+		for _, namespace := range resp.Info.RuntimeSpec.Linux.Namespaces {
+			if namespace.Type == "network" {
+				value := strings.Split(namespace.Path, "/")[2]
+				pid, _ = strconv.Atoi(value)
+			}
+		}
+	case "crio":
+		var info InfoDetails
+		if err := json.Unmarshal(j, &info); err != nil {
+			return 0, err
+		}
+		pid = info.PID
+		if pid == 0 {
+			var resp CrictlInspectResponse
+			if err := json.Unmarshal(j, &resp); err != nil {
+				return 0, err
+			}
+			pid = resp.Info.PID
+		}
+	default:
+		return 0, errors.Errorf("[cri]: No supported container runtime, runtime: %v", runtime)
+	}
+	if pid == 0 {
+		return 0, errors.Errorf("[cri]: No running target container found, pid: %d", pid)
+	}
+
+	return pid, nil
+}
@@ -3,39 +3,126 @@ package common
 import (
 	"math/rand"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/litmuschaos/litmus-go/pkg/clients"
 	"github.com/litmuschaos/litmus-go/pkg/log"
 	"github.com/litmuschaos/litmus-go/pkg/math"
 	"github.com/pkg/errors"
+	apiv1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-//GetNodeList check for the availibilty of the application node for the chaos execution
-// if the application node is not defined it will derive the random target node list using node affected percentage
-func GetNodeList(nodeName string, nodeAffPerc int, clients clients.ClientSets) ([]string, error) {
+const (
+	// zoneLabel is the well known topology label of the node zone
+	zoneLabel = "topology.kubernetes.io/zone"
+	// legacyZoneLabel is the deprecated zone label, still set by the older kubelets
+	legacyZoneLabel = "failure-domain.beta.kubernetes.io/zone"
+)
 
-	var nodeList []string
+// controlPlaneLabels are the well known role labels of the control-plane nodes
+var controlPlaneLabels = []string{
+	"node-role.kubernetes.io/master",
+	"node-role.kubernetes.io/control-plane",
+}
 
-	if nodeName != "" {
-		nodeList = append(nodeList, nodeName)
-		return nodeList, nil
+// NodeSelector contains the attributes used to select the target nodes of the node level experiments
+type NodeSelector struct {
+	// TargetNodes is the comma separated list of node names, it takes precedence over all the other attributes
+	TargetNodes       string
+	NodeLabel         string
+	NodesAffectedPerc int
+	// Zone restricts the nodes to the given topology zone, it is ignored if empty
+	Zone string
+	// MaxTargetNodes caps the number of target nodes, it is ignored if zero
+	MaxTargetNodes int
+	// AppNS and AppLabel are used to select the nodes hosting the application pods, if AppNodes is enabled
+	AppNS               string
+	AppLabel            string
+	AppNodes            bool
+	ExcludeControlPlane bool
+	ExcludeNotReady     bool
+	ExcludeCordoned     bool
+}
+
+// NodeTargetDetails contains the node selection attributes of the experiment details of the node level experiments
+type NodeTargetDetails struct {
+	TargetNodes              string
+	NodesAffectedPerc        int
+	NodeLabel                string
+	MaxTargetNodes           int
+	TargetAppNodes           bool
+	ExcludeControlPlaneNodes bool
+	ExcludeNotReadyNodes     bool
+	ExcludeCordonedNodes     bool
+}
+
+//GetNodeSelector builds the node selector from the node selection attributes and the application details of the experiment
+func GetNodeSelector(details NodeTargetDetails, appNS, appLabel string) NodeSelector {
+	return NodeSelector{
+		TargetNodes:         details.TargetNodes,
+		NodeLabel:           details.NodeLabel,
+		NodesAffectedPerc:   details.NodesAffectedPerc,
+		MaxTargetNodes:      details.MaxTargetNodes,
+		AppNS:               appNS,
+		AppLabel:            appLabel,
+		AppNodes:            details.TargetAppNodes,
+		ExcludeControlPlane: details.ExcludeControlPlaneNodes,
+		ExcludeNotReady:     details.ExcludeNotReadyNodes,
+		ExcludeCordoned:     details.ExcludeCordonedNodes,
 	}
-	nodes, err := clients.KubeClient.CoreV1().Nodes().List(v1.ListOptions{})
-	if err != nil || len(nodes.Items) == 0 {
-		return nil, errors.Errorf("Failed to find the nodes, err: %v", err)
+}
+
+//GetTargetNodes derive the target node list for the chaos execution
+// the TARGET_NODES are used as it is, if provided. Otherwise it picks the random target nodes from the nodes matching
+// the node label, hosting the application pods (if enabled) and not excluded, using the node affected percentage
+func GetTargetNodes(selector NodeSelector, clients clients.ClientSets) ([]string, error) {
+
+	if selector.TargetNodes != "" {
+		return getTargetNodesByName(selector.TargetNodes, clients)
 	}
 
-	newNodeListLength := math.Maximum(1, math.Adjustment(nodeAffPerc, len(nodes.Items)))
+	nodes, err := GetNodesBySelector(selector, clients)
+	if err != nil {
+		return nil, err
+	}
+
+	appNodes := map[string]bool{}
+	if selector.AppNodes && selector.AppLabel != "" {
+		podList, err := clients.KubeClient.CoreV1().Pods(selector.AppNS).List(v1.ListOptions{LabelSelector: selector.AppLabel})
+		if err != nil || len(podList.Items) == 0 {
+			return nil, errors.Errorf("Failed to find the application pods with matching labels in %v namespace, err: %v", selector.AppNS, err)
+		}
+		for _, pod := range podList.Items {
+			appNodes[pod.Spec.NodeName] = true
+		}
+	}
+
+	var candidates []string
+	for _, node := range nodes {
+		if len(appNodes) != 0 && !appNodes[node.Name] {
+			continue
+		}
+		candidates = append(candidates, node.Name)
+	}
+	if len(candidates) == 0 {
+		return nil, errors.Errorf("no node found matching the node selection criteria, node label: '%v'", selector.NodeLabel)
+	}
+
+	newNodeListLength := math.Maximum(1, math.Adjustment(selector.NodesAffectedPerc, len(candidates)))
+	if selector.MaxTargetNodes > 0 && newNodeListLength > selector.MaxTargetNodes {
+		newNodeListLength = selector.MaxTargetNodes
+	}
 
 	// it will generate the random nodelist
-	// it starts from the random index and choose requirement no of pods next to that index in a circular way.
+	// it starts from the random index and choose requirement no of nodes next to that index in a circular way.
+	var nodeList []string
 	rand.Seed(time.Now().UnixNano())
-	index := rand.Intn(len(nodes.Items))
+	index := rand.Intn(len(candidates))
 	for i := 0; i < newNodeListLength; i++ {
-		nodeList = append(nodeList, nodes.Items[index].Name)
-		index = (index + 1) % len(nodes.Items)
+		nodeList = append(nodeList, candidates[index])
+		index = (index + 1) % len(candidates)
 	}
 
 	log.Infof("[Chaos]:Number of nodes targeted: %v", strconv.Itoa(newNodeListLength))
@@ -43,6 +130,83 @@ func GetNodeList(nodeName string, nodeAffPerc int, clients clients.ClientSets) (
 	return nodeList, nil
 }
 
+// GetNodesBySelector lists the nodes matching the node label and zone of the selector, except the excluded nodes
+// the nodes hosting the application pods are not filtered, ex: the replacement nodes do not host them yet
+func GetNodesBySelector(selector NodeSelector, clients clients.ClientSets) ([]apiv1.Node, error) {
+
+	nodeList, err := clients.KubeClient.CoreV1().Nodes().List(v1.ListOptions{LabelSelector: selector.NodeLabel})
+	if err != nil {
+		return nil, errors.Errorf("Failed to find the nodes, err: %v", err)
+	}
+
+	var nodes []apiv1.Node
+	for _, node := range nodeList.Items {
+		switch {
+		case selector.Zone != "" && GetNodeZone(node) != selector.Zone:
+			continue
+		case selector.ExcludeControlPlane && isControlPlaneNode(node):
+			log.Infof("[Info]: Skipping the %v control-plane node", node.Name)
+			continue
+		case selector.ExcludeNotReady && !IsNodeReady(node):
+			log.Infof("[Info]: Skipping the %v node, it is not in ready state", node.Name)
+			continue
+		case selector.ExcludeCordoned && node.Spec.Unschedulable:
+			log.Infof("[Info]: Skipping the %v node, it is cordoned", node.Name)
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// getTargetNodesByName returns the comma separated node names, after verifying that all of them exist in the cluster
+func getTargetNodesByName(targetNodes string, clients clients.ClientSets) ([]string, error) {
+
+	var nodeList []string
+	for _, nodeName := range strings.Split(targetNodes, ",") {
+		nodeName = strings.TrimSpace(nodeName)
+		if nodeName == "" {
+			continue
+		}
+		if _, err := clients.KubeClient.CoreV1().Nodes().Get(nodeName, v1.GetOptions{}); err != nil {
+			return nil, errors.Errorf("Unable to find the %v target node, err: %v", nodeName, err)
+		}
+		nodeList = append(nodeList, nodeName)
+	}
+	if len(nodeList) == 0 {
+		return nil, errors.Errorf("no node found in the '%v' target nodes", targetNodes)
+	}
+	return nodeList, nil
+}
+
+// isControlPlaneNode checks whether the node carries any of the control-plane role labels
+func isControlPlaneNode(node apiv1.Node) bool {
+	for _, label := range controlPlaneLabels {
+		if _, ok := node.Labels[label]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNodeReady checks the ready condition of the node
+func IsNodeReady(node apiv1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == apiv1.NodeReady && condition.Status == apiv1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// GetNodeZone returns the zone of the node from the topology labels
+func GetNodeZone(node apiv1.Node) string {
+	if zone, ok := node.Labels[zoneLabel]; ok {
+		return zone
+	}
+	return node.Labels[legacyZoneLabel]
+}
+
 //GetNodeName will select a random replica of application pod and return the node name of that application pod
 func GetNodeName(namespace, labels string, clients clients.ClientSets) (string, error) {
 	podList, err := clients.KubeClient.CoreV1().Pods(namespace).List(v1.ListOptions{LabelSelector: labels})
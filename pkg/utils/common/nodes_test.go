@@ -0,0 +1,124 @@
+package common
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/litmuschaos/litmus-go/pkg/clients/fake"
+	apiv1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// newNode returns the node with the given labels, which is ready unless notReady is set
+func newNode(name string, labels map[string]string, notReady, unschedulable bool) *apiv1.Node {
+
+	status := apiv1.ConditionTrue
+	if notReady {
+		status = apiv1.ConditionFalse
+	}
+	return &apiv1.Node{
+		ObjectMeta: v1.ObjectMeta{Name: name, Labels: labels},
+		Spec:       apiv1.NodeSpec{Unschedulable: unschedulable},
+		Status: apiv1.NodeStatus{
+			Conditions: []apiv1.NodeCondition{{Type: apiv1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func TestGetTargetNodes(t *testing.T) {
+
+	objects := []runtime.Object{
+		newNode("node-1", map[string]string{"role": "worker", zoneLabel: "us-east-1a"}, false, false),
+		newNode("node-2", map[string]string{"role": "worker", legacyZoneLabel: "us-east-1b"}, false, false),
+		newNode("node-3", map[string]string{"role": "worker", zoneLabel: "us-east-1a"}, true, false),
+		newNode("node-4", map[string]string{"role": "worker", zoneLabel: "us-east-1a"}, false, true),
+		newNode("master", map[string]string{"node-role.kubernetes.io/control-plane": ""}, false, false),
+		&apiv1.Pod{
+			ObjectMeta: v1.ObjectMeta{Name: "app", Namespace: "default", Labels: map[string]string{"app": "nginx"}},
+			Spec:       apiv1.PodSpec{NodeName: "node-2"},
+		},
+	}
+
+	tests := []struct {
+		selector NodeSelector
+		want     []string
+		wantErr  bool
+		testName string
+	}{
+		{
+			selector: NodeSelector{TargetNodes: " node-1 , node-2,"},
+			want:     []string{"node-1", "node-2"},
+			testName: "target nodes are trimmed",
+		},
+		{
+			selector: NodeSelector{TargetNodes: "node-1,node-5"},
+			wantErr:  true,
+			testName: "unknown target node",
+		},
+		{
+			selector: NodeSelector{TargetNodes: " , "},
+			wantErr:  true,
+			testName: "empty target nodes",
+		},
+		{
+			selector: NodeSelector{NodeLabel: "role=worker", NodesAffectedPerc: 100, Zone: "us-east-1a"},
+			want:     []string{"node-1", "node-3", "node-4"},
+			testName: "nodes of the zone",
+		},
+		{
+			selector: NodeSelector{NodeLabel: "role=worker", NodesAffectedPerc: 100, Zone: "us-east-1b"},
+			want:     []string{"node-2"},
+			testName: "nodes of the legacy zone label",
+		},
+		{
+			selector: NodeSelector{NodesAffectedPerc: 100, ExcludeControlPlane: true, ExcludeNotReady: true, ExcludeCordoned: true},
+			want:     []string{"node-1", "node-2"},
+			testName: "excluded nodes",
+		},
+		{
+			selector: NodeSelector{NodesAffectedPerc: 100, MaxTargetNodes: 2},
+			want:     nil,
+			testName: "max target nodes",
+		},
+		{
+			selector: NodeSelector{NodesAffectedPerc: 100, AppNS: "default", AppLabel: "app=nginx", AppNodes: true},
+			want:     []string{"node-2"},
+			testName: "nodes hosting the application pods",
+		},
+		{
+			selector: NodeSelector{NodeLabel: "role=worker", Zone: "us-east-1c"},
+			wantErr:  true,
+			testName: "no node matches the selector",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.testName, func(t *testing.T) {
+			clients, server, err := fake.NewClientSets(objects...)
+			if err != nil {
+				t.Fatalf("unable to create the fake clients, err: %v", err)
+			}
+			defer server.Close()
+
+			got, err := GetTargetNodes(tt.selector, clients)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got %v error, want error: %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			// the random selection is verified by its count, if the expected nodes are not given
+			if tt.want == nil {
+				if len(got) != tt.selector.MaxTargetNodes {
+					t.Errorf("got %v nodes, want %v nodes", got, tt.selector.MaxTargetNodes)
+				}
+				return
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v nodes, want %v", got, tt.want)
+			}
+		})
+	}
+}
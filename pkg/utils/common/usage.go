@@ -0,0 +1,272 @@
+package common
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/litmuschaos/litmus-go/pkg/clients"
+	"github.com/litmuschaos/litmus-go/pkg/log"
+	"github.com/litmuschaos/litmus-go/pkg/types"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/resource"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NodeUsage contains the current cpu and memory usage of the node along with its capacity
+type NodeUsage struct {
+	CPUMillicores         int64
+	CPUCapacityMillicores int64
+	MemoryBytes           int64
+	MemoryCapacityBytes   int64
+}
+
+// UtilisationSample is the utilisation percentage of the node at the given time
+type UtilisationSample struct {
+	Time        string  `json:"time"`
+	Utilisation float64 `json:"utilisation"`
+}
+
+// nodeMetrics is the node metrics response of the metrics-server
+type nodeMetrics struct {
+	Usage struct {
+		CPU    string `json:"cpu"`
+		Memory string `json:"memory"`
+	} `json:"usage"`
+}
+
+// summary is the subset of the stats summary response of the kubelet
+type summary struct {
+	Node struct {
+		CPU struct {
+			UsageNanoCores int64 `json:"usageNanoCores"`
+		} `json:"cpu"`
+		Memory struct {
+			WorkingSetBytes int64 `json:"workingSetBytes"`
+		} `json:"memory"`
+	} `json:"node"`
+}
+
+// CPUPercentage returns the cpu utilisation of the node in percentage
+func (u NodeUsage) CPUPercentage() float64 {
+	if u.CPUCapacityMillicores == 0 {
+		return 0
+	}
+	return float64(u.CPUMillicores) * 100 / float64(u.CPUCapacityMillicores)
+}
+
+// MemoryPercentage returns the memory utilisation of the node in percentage
+func (u NodeUsage) MemoryPercentage() float64 {
+	if u.MemoryCapacityBytes == 0 {
+		return 0
+	}
+	return float64(u.MemoryBytes) * 100 / float64(u.MemoryCapacityBytes)
+}
+
+// Percentage returns the utilisation of the given resource (cpu or memory) in percentage
+func (u NodeUsage) Percentage(resourceName string) float64 {
+	if resourceName == "memory" {
+		return u.MemoryPercentage()
+	}
+	return u.CPUPercentage()
+}
+
+//GetNodeUsage fetch the current cpu and memory usage of the node
+// it reads the usage from the metrics-server if the source is metrics-server, otherwise from the stats summary api of the kubelet
+func GetNodeUsage(nodeName, source string, clients clients.ClientSets) (NodeUsage, error) {
+
+	node, err := clients.KubeClient.CoreV1().Nodes().Get(nodeName, v1.GetOptions{})
+	if err != nil {
+		return NodeUsage{}, errors.Errorf("Unable to get the %v node, err: %v", nodeName, err)
+	}
+	usage := NodeUsage{
+		CPUCapacityMillicores: node.Status.Capacity.Cpu().MilliValue(),
+		MemoryCapacityBytes:   node.Status.Capacity.Memory().Value(),
+	}
+
+	switch source {
+	case "metrics-server":
+		raw, err := clients.KubeClient.CoreV1().RESTClient().Get().AbsPath("/apis/metrics.k8s.io/v1beta1/nodes/" + nodeName).DoRaw()
+		if err != nil {
+			return NodeUsage{}, errors.Errorf("Unable to get the metrics of %v node from the metrics-server, err: %v", nodeName, err)
+		}
+		var metrics nodeMetrics
+		if err := json.Unmarshal(raw, &metrics); err != nil {
+			return NodeUsage{}, errors.Errorf("Unable to parse the metrics of %v node, err: %v", nodeName, err)
+		}
+		cpu, err := resource.ParseQuantity(metrics.Usage.CPU)
+		if err != nil {
+			return NodeUsage{}, errors.Errorf("Unable to parse the cpu usage of %v node, err: %v", nodeName, err)
+		}
+		memory, err := resource.ParseQuantity(metrics.Usage.Memory)
+		if err != nil {
+			return NodeUsage{}, errors.Errorf("Unable to parse the memory usage of %v node, err: %v", nodeName, err)
+		}
+		usage.CPUMillicores = cpu.MilliValue()
+		usage.MemoryBytes = memory.Value()
+	default:
+		raw, err := clients.KubeClient.CoreV1().RESTClient().Get().AbsPath("/api/v1/nodes/" + nodeName + "/proxy/stats/summary").DoRaw()
+		if err != nil {
+			return NodeUsage{}, errors.Errorf("Unable to get the stats summary of %v node from the kubelet, err: %v", nodeName, err)
+		}
+		var stats summary
+		if err := json.Unmarshal(raw, &stats); err != nil {
+			return NodeUsage{}, errors.Errorf("Unable to parse the stats summary of %v node, err: %v", nodeName, err)
+		}
+		usage.CPUMillicores = stats.Node.CPU.UsageNanoCores / 1000000
+		usage.MemoryBytes = stats.Node.Memory.WorkingSetBytes
+	}
+	return usage, nil
+}
+
+// UtilisationRecorder records the utilisation of the target nodes at every interval, till it is stopped
+type UtilisationRecorder struct {
+	mu      sync.Mutex
+	samples map[string][]UtilisationSample
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// DefaultControlInterval is the interval in seconds, at which the utilisation of the target nodes is sampled by default
+const DefaultControlInterval = 15
+
+// ParseControlInterval parses the CONTROL_INTERVAL in seconds
+// it falls back to the DefaultControlInterval with a warning, if the value is not a positive integer
+func ParseControlInterval(value string) int {
+
+	if value == "" {
+		return DefaultControlInterval
+	}
+	interval, err := strconv.Atoi(value)
+	if err != nil || interval <= 0 {
+		log.Warnf("CONTROL_INTERVAL %q is not a positive number of seconds, falling back to %vs", value, DefaultControlInterval)
+		return DefaultControlInterval
+	}
+	return interval
+}
+
+//StartUtilisationRecorder starts recording the utilisation of the given resource (cpu or memory) of the target nodes
+// the interval should be parsed by ParseControlInterval, a non positive interval falls back to the DefaultControlInterval
+func StartUtilisationRecorder(nodes []string, resourceName, source string, interval int, clients clients.ClientSets) *UtilisationRecorder {
+
+	if interval <= 0 {
+		interval = DefaultControlInterval
+	}
+
+	recorder := &UtilisationRecorder{
+		samples: map[string][]UtilisationSample{},
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+
+	go func() {
+		defer close(recorder.done)
+		ticker := time.NewTicker(time.Duration(interval) * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-recorder.stop:
+				return
+			case <-ticker.C:
+				for _, node := range nodes {
+					usage, err := GetNodeUsage(node, source, clients)
+					if err != nil {
+						log.Warnf("Unable to record the utilisation of %v node, err: %v", node, err)
+						continue
+					}
+					recorder.mu.Lock()
+					recorder.samples[node] = append(recorder.samples[node], UtilisationSample{
+						Time:        time.Now().UTC().Format(time.RFC3339),
+						Utilisation: float64(int(usage.Percentage(resourceName)*100)) / 100,
+					})
+					recorder.mu.Unlock()
+				}
+			}
+		}
+	}()
+	return recorder
+}
+
+// Stop stops the recording and returns the recorded samples of the target nodes
+func (r *UtilisationRecorder) Stop() map[string][]UtilisationSample {
+	close(r.stop)
+	<-r.done
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.samples
+}
+
+// maxRecordedSamples caps the samples of a target node recorded in the chaosresult annotation
+// the annotations of an object are limited to 256KiB in total, so the long chaos durations are downsampled
+const maxRecordedSamples = 10
+
+// UtilisationSummary contains the recorded utilisation of a target node
+// min, avg and max are derived from all the samples, while the recorded samples are downsampled to maxRecordedSamples
+type UtilisationSummary struct {
+	Resource string              `json:"resource"`
+	Target   int                 `json:"target"`
+	Min      float64             `json:"min"`
+	Avg      float64             `json:"avg"`
+	Max      float64             `json:"max"`
+	Samples  []UtilisationSample `json:"samples"`
+}
+
+//RecordUtilisationInResult summarise the recorded utilisation of the target nodes and add it in the chaosresult annotations
+// the annotation is keyed by the resource name, ex: litmuschaos.io/node-cpu-utilisation
+func RecordUtilisationInResult(resultDetails *types.ResultDetails, samples map[string][]UtilisationSample, resourceName string, target int) {
+
+	summaries := map[string]UtilisationSummary{}
+	for node, nodeSamples := range samples {
+		if len(nodeSamples) == 0 {
+			continue
+		}
+		summary := UtilisationSummary{
+			Resource: resourceName,
+			Target:   target,
+			Min:      nodeSamples[0].Utilisation,
+			Max:      nodeSamples[0].Utilisation,
+			Samples:  downsample(nodeSamples, maxRecordedSamples),
+		}
+		total := 0.0
+		for _, sample := range nodeSamples {
+			total += sample.Utilisation
+			summary.Min = math.Min(summary.Min, sample.Utilisation)
+			summary.Max = math.Max(summary.Max, sample.Utilisation)
+		}
+		summary.Avg = float64(int(total/float64(len(nodeSamples))*100)) / 100
+		summaries[node] = summary
+
+		log.InfoWithValues("[Info]: The "+resourceName+" utilisation of the "+node+" node during the chaos", logrus.Fields{
+			"Target": target,
+			"Min":    summary.Min,
+			"Avg":    summary.Avg,
+			"Max":    summary.Max,
+		})
+	}
+
+	value, err := json.Marshal(summaries)
+	if err != nil {
+		log.Warnf("Unable to record the %v utilisation in the chaosresult, err: %v", resourceName, err)
+		return
+	}
+	if resultDetails.Annotations == nil {
+		resultDetails.Annotations = map[string]string{}
+	}
+	resultDetails.Annotations["litmuschaos.io/node-"+resourceName+"-utilisation"] = string(value)
+}
+
+// downsample picks the evenly spaced samples, including the first and the last one, if there are more than the given count
+func downsample(samples []UtilisationSample, count int) []UtilisationSample {
+	if len(samples) <= count {
+		return samples
+	}
+	picked := make([]UtilisationSample, 0, count)
+	for i := 0; i < count; i++ {
+		picked = append(picked, samples[i*(len(samples)-1)/(count-1)])
+	}
+	return picked
+}